@@ -11,6 +11,38 @@ uniform vec3 cameraPos;
 uniform int isUnderwater;
 uniform float time;
 
+// Fancy water: still and flowing water tops on the reflection plane blend a
+// mirrored render of the world with the scene behind them, by a fresnel term
+// perturbed with scrolling ripple normals.
+uniform int fancyWater;           // 1 when the planar passes are ready this frame
+uniform sampler2D reflectionTex;  // the world mirrored in the water plane
+uniform sampler2D refractionTex;  // the opaque scene behind the water
+uniform sampler2D rippleNormals;  // tiling normal map, tangent xy, up z
+uniform vec2 screenSize;
+uniform float waterPlaneY;
+uniform vec3 eyePos;
+uniform vec2 waterLayers;         // texture layers of still and flowing water
+
+// rippleNormal samples the ripple map twice, scrolling different ways, and
+// returns the world-space surface normal at p.
+vec3 rippleNormal(vec2 p) {
+    vec3 a = texture(rippleNormals, p * 0.125 + vec2(time * 0.021, time * 0.013)).rgb;
+    vec3 b = texture(rippleNormals, p * 0.07 - vec2(time * 0.017, -time * 0.011)).rgb;
+    vec3 n = (a * 2.0 - 1.0) + (b * 2.0 - 1.0);
+    return normalize(vec3(n.x, n.z * 2.0, n.y));
+}
+
+// isPlanarWater reports whether this fragment is a water top on the plane.
+bool isPlanarWater() {
+    if (fancyWater == 0 || isUnderwater != 0 || FlowAngle < -1.5) {
+        return false;
+    }
+    if (abs(TexCoord.z - waterLayers.x) > 0.5 && abs(TexCoord.z - waterLayers.y) > 0.5) {
+        return false;
+    }
+    return abs(FragPos.y - waterPlaneY) < 0.06 && eyePos.y > waterPlaneY;
+}
+
 void main() {
     vec2 animUV = TexCoord.xy;
 
@@ -34,6 +66,21 @@ void main() {
     vec4 texColor = texture(textureArray, vec3(sampUV, TexCoord.z));
     vec4 finalColor = texColor * vec4(TintColor, 1.0);
 
+    if (isPlanarWater()) {
+        vec3 n = rippleNormal(FragPos.xz);
+        vec2 screenUV = gl_FragCoord.xy / screenSize;
+        vec2 offset = n.xz * 0.03;
+        vec3 reflection = texture(reflectionTex, screenUV + offset).rgb;
+        vec3 refraction = texture(refractionTex, screenUV + offset * 0.5).rgb;
+
+        // Schlick's approximation with water's reflectance at normal incidence
+        float cosTheta = max(dot(normalize(eyePos - FragPos), n), 0.0);
+        float fresnel = 0.02 + 0.98 * pow(1.0 - cosTheta, 5.0);
+
+        vec3 body = mix(refraction * mix(vec3(1.0), TintColor, 0.6), finalColor.rgb, 0.2);
+        finalColor = vec4(mix(body, reflection, fresnel), 1.0);
+    }
+
     float dist = length(FragPos - cameraPos);
     float fogFactor = 1.0 - exp(-dist * 0.15);
     fogFactor = clamp(fogFactor, 0.0, 1.0);
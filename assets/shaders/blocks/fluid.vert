@@ -4,18 +4,29 @@ layout(location = 1) in vec2 aUV;
 layout(location = 2) in float aTexID;
 layout(location = 3) in vec3 aTint;
 layout(location = 4) in float aFlowAngle;
+layout(location = 5) in float aLight; // sky<<4 | block
 
 uniform mat4 view;
 uniform mat4 proj;
+uniform float skyDarken; // sky light levels taken away by the time of day
 
 out vec3 FragPos;
 out vec3 TexCoord; // u, v, layer
 out vec3 TintColor;
 out float FlowAngle;
 
+// lightBrightness maps a light level, 0-15, to brightness on 1.8.9's curve.
+// Matches lightBrightness in the block shader.
+float lightBrightness(float level) {
+    float f = 1.0 - level / 15.0;
+    return 0.05 + 0.95 * (1.0 - f) / (f * 3.0 + 1.0);
+}
+
 void main() {
+    int light = int(aLight);
+    float sky = max(float(light >> 4) - skyDarken, 0.0);
     FragPos = aPos;
-    TintColor = aTint;
+    TintColor = aTint * lightBrightness(max(sky, float(light & 15)));
     FlowAngle = aFlowAngle;
     TexCoord = vec3(aUV, aTexID);
     gl_Position = proj * view * vec4(aPos, 1.0);
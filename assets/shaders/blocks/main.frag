@@ -2,20 +2,122 @@
 in vec3 Normal;
 in vec3 FragPos;
 in vec3 TexCoord; // u, v, layer
-in float Brightness;
+in float SkyLevel;
+in float BlockLevel;
+in float FaceShade;
 in vec3 TintColor;
+in float Occlusion;
+flat in int Varied;
 
 uniform vec3 lightDir;
 uniform sampler2DArray textureArray;
 uniform vec3 cameraPos;
 uniform int isUnderwater;
+uniform int textureVariation; // 0 disables per-block variation of natural blocks
+uniform int ambientOcclusion; // 0 ignores the per-vertex occlusion
+uniform vec4 lodTint;         // level of detail debug tint, alpha its strength
+
+// Sun shadows; see shadows.go
+uniform sampler2DArrayShadow shadowMap;
+uniform int shadowCascades;     // 0 when shadows are off
+uniform mat4 shadowMatrices[3];
+uniform float shadowRadii[3];   // blocks from the eye each cascade reaches
+uniform float shadowTexel;      // 1 / shadow map size
+uniform int pcfRadius;          // taps either side of the centre
+uniform float shadowStrength;   // 0 to 1, fading as the light sets
+uniform vec3 shadowLightDir;    // towards the sun, or the moon by night
 out vec4 FragColor;
 
+// Shadows take up to this much of the sky light's brightness
+const float shadowDarkness = 0.4;
+
+// hashBlock mixes a block position into 32 well-spread bits
+uint hashBlock(ivec3 p) {
+	uint h = uint(p.x) * 73856093u ^ uint(p.y) * 19349663u ^ uint(p.z) * 83492791u;
+	h ^= h >> 13;
+	h *= 0x5bd1e995u;
+	h ^= h >> 15;
+	return h;
+}
+
+// lightBrightness maps a light level, 0-15, to brightness on 1.8.9's curve,
+// which falls off slowly near full light and quickly in the dark. Matches
+// lightBrightness in the fluid shader.
+float lightBrightness(float level) {
+	float f = 1.0 - level / 15.0;
+	return 0.05 + 0.95 * (1.0 - f) / (f * 3.0 + 1.0);
+}
+
+// shadowAt returns how much of the sky's direct light is blocked at this
+// fragment, from 0 to 1. Faces turned from the light are in their own
+// shadow; others look up the smallest cascade holding them, filtered over a
+// square of (2 * pcfRadius + 1)^2 texels.
+float shadowAt() {
+	float facing = dot(Normal, shadowLightDir);
+	float self = 1.0 - smoothstep(0.0, 0.1, facing);
+	if (facing <= 0.0) return self;
+
+	float dist = length(FragPos - cameraPos);
+	for (int i = 0; i < shadowCascades; i++) {
+		if (dist > shadowRadii[i]) continue;
+		// Look up a little off the surface, further when the light grazes
+		// it, so faces don't shadow themselves
+		float texelSize = 2.0 * shadowRadii[i] * shadowTexel;
+		vec3 p = FragPos + Normal * texelSize * (1.0 + 2.0 * (1.0 - facing));
+		vec3 uvz = (shadowMatrices[i] * vec4(p, 1.0)).xyz * 0.5 + 0.5;
+		float lit = 0.0;
+		for (int x = -pcfRadius; x <= pcfRadius; x++) {
+			for (int y = -pcfRadius; y <= pcfRadius; y++) {
+				vec2 uv = uvz.xy + vec2(x, y) * shadowTexel;
+				lit += texture(shadowMap, vec4(uv, float(i), uvz.z));
+			}
+		}
+		float taps = float((2 * pcfRadius + 1) * (2 * pcfRadius + 1));
+		float blocked = 1.0 - lit / taps;
+		if (i == shadowCascades - 1) {
+			// Fade out towards the edge of the last cascade
+			blocked *= 1.0 - smoothstep(shadowRadii[i] * 0.8, shadowRadii[i], dist);
+		}
+		return max(blocked, self);
+	}
+	return self;
+}
+
 void main() {
-	vec4 texColor = texture(textureArray, TexCoord);
+	vec3 tc = TexCoord;
+	vec3 jitter = vec3(1.0);
+	if (Varied != 0 && textureVariation != 0) {
+		// The block this fragment belongs to: step back inside along the normal
+		uint h = hashBlock(ivec3(floor(FragPos - Normal * 0.5)));
+		if (Normal.y != 0.0) {
+			// Rotate top and bottom textures by a multiple of 90 degrees
+			vec2 cell = floor(tc.xy);
+			vec2 f = tc.xy - cell;
+			uint rot = h & 3u;
+			if (rot == 1u) f = vec2(1.0 - f.y, f.x);
+			else if (rot == 2u) f = 1.0 - f;
+			else if (rot == 3u) f = vec2(f.y, 1.0 - f.x);
+			tc.xy = cell + f;
+		}
+		// Up to 3% brighter or darker per channel
+		jitter += (vec3(uvec3(h >> 8, h >> 16, h >> 24) & 255u) / 255.0 - 0.5) * 0.06;
+	}
+	// Gradients of the unrotated coordinates avoid mip seams at block edges
+	vec4 texColor = textureGrad(textureArray, tc, dFdx(TexCoord.xy), dFdy(TexCoord.xy));
 	if (texColor.a < 0.1) discard;
-	texColor.rgb *= TintColor;
-	vec3 col = texColor.rgb * Brightness;
+	texColor.rgb *= TintColor * jitter;
+	float brightness = FaceShade * lightBrightness(max(SkyLevel, BlockLevel));
+	if (shadowCascades > 0) {
+		// Shadows only take away the sky's light, not that of torches
+		float skyShare = clamp((SkyLevel - BlockLevel) / 3.0, 0.0, 1.0);
+		brightness *= 1.0 - shadowDarkness * shadowStrength * skyShare * shadowAt();
+	}
+	vec3 col = texColor.rgb * brightness;
+	if (ambientOcclusion != 0) {
+		// Each level takes a fifth of the light, as smooth lighting does
+		col *= 1.0 - 0.2 * Occlusion;
+	}
+	col = mix(col, lodTint.rgb * brightness, lodTint.a);
 
 	if (isUnderwater != 0) {
 		float dist = length(FragPos - cameraPos);
@@ -4,12 +4,21 @@ layout(location = 1) in vec3 aData; // Info(Normal+Bright), TexID, Tint
 
 uniform mat4 view;
 uniform mat4 proj;
+uniform mat3 tintRemap; // colorblind foliage remap; identity when disabled
+uniform float time;       // seconds, drives the wind
+uniform int foliageWaving; // 0 keeps foliage still
+uniform float skyDarken;  // sky light levels taken away by the time of day
+uniform vec4 clipPlane;   // keeps the side with dot(pos, clipPlane) >= 0 when clipping is on
 
 out vec3 Normal;
 out vec3 FragPos;
 out vec3 TexCoord; // u, v, layer
-out float Brightness;
+out float SkyLevel;   // sky light, 0-15, after the time of day
+out float BlockLevel; // block light, 0-15
+out float FaceShade;
 out vec3 TintColor;
+out float Occlusion; // ambient occlusion level, 0 (open) to 3
+flat out int Varied;
 
 // Decode normal from encoded value
 vec3 decodeNormal(int idx) {
@@ -22,6 +31,13 @@ vec3 decodeNormal(int idx) {
 	return vec3(0.0, 0.0, 1.0); // Default
 }
 
+// faceShade darkens faces by direction: tops full, sides 0.8, bottoms 0.5.
+float faceShade(int idx) {
+	if (idx == 4) return 1.0;
+	if (idx == 5) return 0.5;
+	return 0.8;
+}
+
 vec3 unpackRGB565(int val) {
 	// R: 5 bits, G: 6 bits, B: 5 bits
 	float r = float((val >> 11) & 0x1F) / 31.0;
@@ -31,27 +47,34 @@ vec3 unpackRGB565(int val) {
 }
 
 void main() {
-	vec3 pos = vec3(aPos);
-	FragPos = pos;
-	
 	// Decode info
-	// aData.x = Normal (low byte) | Brightness (high byte)
-	// aData.y = TextureID
+	// aData.x = Normal (bits 0-2) | Drop (bits 3-6) | Varied (bit 7) | Light (high byte, sky<<4 | block)
+	// aData.y = TextureID (bits 0-11) | Foliage (bit 12) | Occlusion (bits 13-14)
 	// aData.z = Tint (RGB565)
 	
 	int info = int(aData.x);
-	int normalIdx = info & 255;
-	int brightnessVal = (info >> 8) & 255;
+	int normalIdx = info & 7;
+	int drop = (info >> 3) & 15; // sixteenths of a block, for partial-height blocks
+	int light = (info >> 8) & 255;
+	Varied = (info >> 7) & 1;
+
+	vec3 pos = vec3(aPos);
+	pos.y -= float(drop) / 16.0;
+	FragPos = pos;
 	
-	int texID = int(aData.y);
+	int texID = int(aData.y) & 4095;
+	bool foliage = ((int(aData.y) >> 12) & 1) != 0;
+	Occlusion = float((int(aData.y) >> 13) & 3);
 	// Cast directly to int (handling signed/unsigned issue via bit logic if needed)
 	// But since we use GL_UNSIGNED_SHORT in pointer, and "int" in shader, OpenGL converts float to int.
 	// 65535.0 -> 65535.
 	int tintVal = int(aData.z);
 
 	Normal = decodeNormal(normalIdx);
-	Brightness = float(brightnessVal) / 255.0;
-	TintColor = unpackRGB565(tintVal);
+	SkyLevel = max(float(light >> 4) - skyDarken, 0.0);
+	BlockLevel = float(light & 15);
+	FaceShade = faceShade(normalIdx);
+	TintColor = tintRemap * unpackRGB565(tintVal);
 
 	// Generate UVs based on world position and normal
 	// pos is now in range [0,1] for X/Z and [0,1] for Y (bottom-left origin)
@@ -66,5 +89,15 @@ void main() {
 	
 	TexCoord = vec3(uv, float(texID));
 
+	// Sway foliage in the wind. The phase comes from the vertex position, so
+	// corners shared by neighbouring blocks move together and leave no gaps.
+	if (foliage && foliageWaving != 0) {
+		float phase = dot(pos, vec3(0.37, 0.21, 0.53));
+		pos.x += sin(time * 1.7 + phase) * 0.04;
+		pos.z += cos(time * 1.3 + phase * 1.3) * 0.04;
+		pos.y += sin(time * 2.1 + phase * 0.7) * 0.015;
+	}
+
+	gl_ClipDistance[0] = dot(vec4(pos, 1.0), clipPlane);
 	gl_Position = proj * view * vec4(pos, 1.0);
 }
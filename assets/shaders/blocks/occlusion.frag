@@ -0,0 +1,6 @@
+#version 330 core
+// Only depth testing matters: colour writes are off while boxes are drawn
+out vec4 FragColor;
+void main() {
+	FragColor = vec4(1.0);
+}
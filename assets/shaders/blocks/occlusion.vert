@@ -0,0 +1,11 @@
+#version 330 core
+layout(location = 0) in vec3 aPos; // corner of the unit cube
+
+uniform mat4 view;
+uniform mat4 proj;
+uniform vec3 boxMin;
+uniform vec3 boxMax;
+
+void main() {
+	gl_Position = proj * view * vec4(mix(boxMin, boxMax, aPos), 1.0);
+}
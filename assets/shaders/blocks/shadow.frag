@@ -0,0 +1,9 @@
+#version 330 core
+in vec3 TexCoord;
+
+uniform sampler2DArray textureArray;
+
+void main() {
+	// Light passes through the gaps in leaves and other cutout blocks
+	if (texture(textureArray, TexCoord).a < 0.1) discard;
+}
@@ -0,0 +1,29 @@
+#version 330 core
+layout(location = 0) in vec3 aPos;
+layout(location = 1) in vec3 aData; // as in main.vert
+
+uniform mat4 lightViewProj;
+
+out vec3 TexCoord; // u, v, layer
+
+void main() {
+	int info = int(aData.x);
+	int normalIdx = info & 7;
+	int drop = (info >> 3) & 15;
+
+	vec3 pos = vec3(aPos);
+	pos.y -= float(drop) / 16.0;
+
+	// Texture coordinates as main.vert makes them, for cutout blocks
+	vec2 uv;
+	if (normalIdx == 0 || normalIdx == 1) {
+		uv = vec2(pos.x, -pos.y);
+	} else if (normalIdx == 2 || normalIdx == 3) {
+		uv = vec2(pos.z, -pos.y);
+	} else {
+		uv = pos.xz;
+	}
+	TexCoord = vec3(uv, float(int(aData.y) & 4095));
+
+	gl_Position = lightViewProj * vec4(pos, 1.0);
+}
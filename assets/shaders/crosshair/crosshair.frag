@@ -2,11 +2,13 @@
 in vec2 TexCoord;
 
 uniform sampler2D crosshairTexture;
+uniform vec3 tint;
 
 out vec4 FragColor;
 
 void main() {
     // Sample the texture directly
     // Minecraft uses the full texture color with special blend mode
-    FragColor = texture(crosshairTexture, TexCoord);
-}
\ No newline at end of file
+    vec4 texColor = texture(crosshairTexture, TexCoord);
+    FragColor = vec4(texColor.rgb * tint, texColor.a);
+}
@@ -8,6 +8,9 @@ out vec4 FragColor;
 uniform vec3 lightPos;
 uniform vec3 viewPos;
 uniform sampler2D skinTexture;
+// Colour mixed over the lit result, e.g. a mob's red hurt flash; alpha is
+// the mix, 0 for none
+uniform vec4 overlayColor;
 
 void main() {
     vec4 texColor = texture(skinTexture, TexCoord);
@@ -33,5 +36,6 @@ void main() {
     vec3 specular = specularStrength * spec * vec3(1.0);
 
     vec3 result = ambient + diffuse + specular;
+    result = mix(result, overlayColor.rgb, overlayColor.a);
     FragColor = vec4(result, 1.0);
 }
\ No newline at end of file
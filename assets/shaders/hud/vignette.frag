@@ -0,0 +1,14 @@
+#version 410 core
+in vec2 uv;
+
+uniform vec3 color;
+uniform float alpha; // at the screen edges and beyond
+uniform float inner; // radius where the tint starts; the edges are at 1
+out vec4 FragColor;
+
+void main() {
+	// Radius stretched with the screen, so the tint follows its shape:
+	// 0 at the centre, 1 at the middle of each edge
+	float r = length(uv * 2.0 - 1.0);
+	FragColor = vec4(color, alpha * smoothstep(inner, 1.0, r));
+}
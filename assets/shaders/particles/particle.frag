@@ -0,0 +1,17 @@
+#version 330 core
+out vec4 FragColor;
+
+in vec2 TexCoord;
+in float Layer;
+in vec4 Color;
+
+uniform sampler2DArray textureArray;
+
+void main() {
+    vec4 base = vec4(1.0);
+    if (Layer >= 0.0) {
+        base = texture(textureArray, vec3(TexCoord, Layer));
+        if (base.a < 0.1) discard;
+    }
+    FragColor = base * Color;
+}
@@ -0,0 +1,25 @@
+#version 330 core
+layout (location = 0) in vec2 aCorner;
+layout (location = 1) in vec4 aCentreSize; // xyz centre, w edge length
+layout (location = 2) in vec4 aUVRect;     // u0, v0, du, dv
+layout (location = 3) in float aLayer;     // texture layer, < 0 for flat colour
+layout (location = 4) in vec4 aColor;
+
+uniform mat4 view;
+uniform mat4 proj;
+
+out vec2 TexCoord;
+out float Layer;
+out vec4 Color;
+
+void main() {
+    // Camera right and up vectors are the first two rows of the view rotation
+    vec3 right = vec3(view[0][0], view[1][0], view[2][0]);
+    vec3 up = vec3(view[0][1], view[1][1], view[2][1]);
+    vec3 pos = aCentreSize.xyz + (right * aCorner.x + up * aCorner.y) * aCentreSize.w;
+    gl_Position = proj * view * vec4(pos, 1.0);
+
+    TexCoord = aUVRect.xy + (aCorner * vec2(1.0, -1.0) + 0.5) * aUVRect.zw;
+    Layer = aLayer;
+    Color = aColor;
+}
@@ -0,0 +1,18 @@
+#version 330 core
+in vec2 texCoord;
+in float height;
+out vec4 FragColor;
+
+uniform sampler2D tex;
+uniform bool textured;
+uniform vec4 color;
+
+void main() {
+	vec4 c = color;
+	if (textured) {
+		c *= texture(tex, texCoord);
+	}
+	// Set behind the ground, not seen through the void below the horizon
+	c.a *= smoothstep(-0.08, 0.0, height);
+	FragColor = c;
+}
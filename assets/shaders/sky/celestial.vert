@@ -0,0 +1,16 @@
+#version 330 core
+layout (location = 0) in vec3 aPos;
+layout (location = 1) in vec2 aTexCoord;
+
+uniform mat4 viewProj; // of the view without its translation
+uniform mat4 model;    // turns the sky with the time of day
+
+out vec2 texCoord;
+out float height;
+
+void main() {
+	vec4 pos = model * vec4(aPos, 1.0);
+	texCoord = aTexCoord;
+	height = normalize(pos.xyz).y;
+	gl_Position = viewProj * pos;
+}
@@ -0,0 +1,26 @@
+#version 330 core
+in vec2 ndc;
+out vec4 FragColor;
+
+uniform mat4 invViewProj; // of the view without its translation
+uniform vec3 zenithColor;
+uniform vec3 horizonColor;
+uniform vec4 sunsetColor; // rgb, and a for its strength
+uniform vec3 sunsetDir;   // the horizon under the sun
+
+void main() {
+	vec4 p = invViewProj * vec4(ndc, 1.0, 1.0);
+	vec3 dir = normalize(p.xyz / p.w);
+
+	// Horizon colour low down, deepening to the zenith colour overhead, and
+	// darker again below the horizon
+	vec3 color = mix(horizonColor, zenithColor, smoothstep(0.0, 0.6, dir.y));
+	color = mix(color, horizonColor * 0.6, smoothstep(0.0, -0.3, dir.y));
+
+	// Sunrise and sunset glow along the horizon on the sun's side
+	float toward = max(dot(dir, sunsetDir), 0.0);
+	float band = 1.0 - smoothstep(0.0, 0.45, abs(dir.y - 0.05));
+	color = mix(color, sunsetColor.rgb, sunsetColor.a * toward * toward * band);
+
+	FragColor = vec4(color, 1.0);
+}
@@ -0,0 +1,34 @@
+#version 330 core
+in vec2 uv;
+
+uniform sampler2D depthTex;
+uniform vec2 sunPos;   // the sun on screen, 0-1; may lie off screen
+uniform float aspect;  // screen width over height
+uniform vec3 sunColor; // already scaled by the shafts' strength
+out vec4 FragColor;
+
+const int SAMPLES = 48;
+const float DENSITY = 0.9; // share of the way to the sun that is sampled
+const float DECAY = 0.96;  // weight kept from one sample to the next
+const float GLOW = 30.0;   // falloff of the glow around the sun
+
+// skyGlow is the sun's glow at p, or nothing where terrain hides the sky
+float skyGlow(vec2 p) {
+	if (texture(depthTex, p).r < 1.0) return 0.0;
+	vec2 d = (p - sunPos) * vec2(aspect, 1.0);
+	return exp(-dot(d, d) * GLOW);
+}
+
+void main() {
+	// March towards the sun, gathering the glow of the sky on the way
+	vec2 stepUV = (uv - sunPos) * DENSITY / float(SAMPLES);
+	vec2 p = uv;
+	float weight = 1.0;
+	float light = 0.0;
+	for (int i = 0; i < SAMPLES; i++) {
+		light += skyGlow(p) * weight;
+		weight *= DECAY;
+		p -= stepUV;
+	}
+	FragColor = vec4(sunColor * light / float(SAMPLES), 1.0);
+}
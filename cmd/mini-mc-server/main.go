@@ -0,0 +1,100 @@
+// Command mini-mc-server runs a world for remote players, without a window:
+// the world simulation, the chunk streaming to each player and the server
+// console.
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"mini-mc/internal/command"
+	"mini-mc/internal/logging"
+	mcnet "mini-mc/internal/net"
+	"mini-mc/internal/netsim"
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+)
+
+// logDir holds the server's latest.log, apart from the game's.
+const logDir = "logs/server"
+
+func main() {
+	addr := flag.String("addr", ":25565", "address to listen for players on")
+	dir := flag.String("world", "saves/server", "directory of the world to serve")
+	creative := flag.Bool("creative", false, "players join in creative mode")
+	rconAddr := flag.String("rcon", "", "address for the remote admin interface, e.g. :25575; needs MINI_MC_RCON_PASSWORD")
+	flag.Parse()
+
+	// MINI_MC_LOG_LEVEL is debug, info (default), warn or error
+	level, err := logging.ParseLevel(os.Getenv("MINI_MC_LOG_LEVEL"))
+	if err != nil {
+		panic(err)
+	}
+	logFile, err := logging.Setup(logDir, level)
+	if err != nil {
+		panic(err)
+	}
+	defer logFile.Close()
+
+	// MINI_MC_NETSIM simulates a bad network on player connections, as in
+	// the game
+	if sim, err := netsim.ParseSettings(os.Getenv("MINI_MC_NETSIM")); err != nil {
+		slog.Warn("ignoring MINI_MC_NETSIM", "err", err)
+	} else if sim.Enabled() {
+		netsim.Configure(sim)
+		slog.Info("simulating network conditions", "settings", sim)
+	}
+
+	registry.UseWorldOverrides(*dir)
+	registry.InitRegistry()
+
+	w, err := world.Open(*dir)
+	if err != nil {
+		slog.Error("opening world failed", "dir", *dir, "err", err)
+		os.Exit(1)
+	}
+	srv, err := mcnet.Listen(*addr, w)
+	if err != nil {
+		slog.Error("listening failed", "err", err)
+		w.Close()
+		os.Exit(1)
+	}
+	if *creative {
+		srv.GameMode = mcnet.GameModeCreative
+	}
+
+	d := command.NewDispatcher()
+	command.RegisterAdmin(d, srv)
+	if *rconAddr != "" {
+		admin, err := command.ListenAdmin(*rconAddr, os.Getenv("MINI_MC_RCON_PASSWORD"), d)
+		if err != nil {
+			slog.Error("remote admin disabled", "err", err)
+		} else {
+			defer admin.Close()
+		}
+	}
+	go func() {
+		if err := command.RunConsole(d, os.Stdin, os.Stdout); err != nil {
+			slog.Warn("console closed", "err", err)
+		}
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		srv.Stop()
+	}()
+
+	srv.Run()
+
+	slog.Info("stopping server")
+	srv.Close()
+	if err := w.Save(); err != nil {
+		slog.Error("saving world failed", "err", err)
+	}
+	w.Close()
+}
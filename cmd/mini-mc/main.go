@@ -1,17 +1,68 @@
 package main
 
 import (
+	"flag"
+	"log/slog"
+	"os"
 	"runtime"
 
 	"github.com/go-gl/glfw/v3.3/glfw"
+	"mini-mc/internal/audio"
+	"mini-mc/internal/config"
 	"mini-mc/internal/game"
+	"mini-mc/internal/logging"
+	"mini-mc/internal/netsim"
+	"mini-mc/internal/sound"
 )
 
+// logDir holds latest.log and its rotated predecessors.
+const logDir = "logs"
+
+// soundDir holds the sound effects, laid out like 1.8.9's (see audio.Output).
+const soundDir = "assets/sounds"
+
 func init() {
 	runtime.LockOSThread()
 }
 
 func main() {
+	pregen := flag.Int("pregen", 0, "generate and save all chunks within this many chunks of spawn, then exit")
+	connect := flag.String("connect", "", "join the server at this address, e.g. localhost:25565, instead of opening the main menu")
+	name := flag.String("name", "Player", "player name to join a server with")
+	benchmark := flag.Bool("benchmark", false, "fly a scripted path over a world of a fixed seed, write a performance report and exit")
+	benchmarkSeconds := flag.Int("benchmark-seconds", 60, "how long -benchmark flies for")
+	benchmarkOut := flag.String("benchmark-out", "benchmarks", "directory -benchmark writes its JSON and CSV reports to")
+	flag.Parse()
+
+	// MINI_MC_LOG_LEVEL is debug, info (default), warn or error
+	level, err := logging.ParseLevel(os.Getenv("MINI_MC_LOG_LEVEL"))
+	if err != nil {
+		panic(err)
+	}
+	logFile, err := logging.Setup(logDir, level)
+	if err != nil {
+		panic(err)
+	}
+	defer logFile.Close()
+
+	// MINI_MC_NETSIM simulates a bad network on server connections for
+	// development, e.g. "latency=120ms,jitter=30ms,loss=0.05"
+	sim, err := netsim.ParseSettings(os.Getenv("MINI_MC_NETSIM"))
+	if err != nil {
+		slog.Warn("ignoring MINI_MC_NETSIM", "err", err)
+	} else if sim.Enabled() {
+		netsim.Configure(sim)
+		slog.Info("simulating network conditions", "settings", sim)
+	}
+
+	if *pregen > 0 {
+		if err := game.Pregenerate(*pregen); err != nil {
+			slog.Error("pregeneration failed", "err", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if err := glfw.Init(); err != nil {
 		panic(err)
 	}
@@ -23,12 +74,39 @@ func main() {
 		panic(err)
 	}
 
+	firstRun, err := config.LoadOptions(config.OptionsFile)
+	if err != nil {
+		slog.Warn("reading options; keeping defaults where invalid", "err", err)
+	}
+
+	if *benchmark {
+		if err := game.RunBenchmark(window, *benchmarkSeconds, *benchmarkOut); err != nil {
+			slog.Error("benchmark failed", "err", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Without an audio device the game runs silent
+	if out, err := audio.Open(soundDir); err != nil {
+		slog.Warn("no audio output", "err", err)
+	} else {
+		sound.SetOutput(out)
+		defer out.Close()
+	}
+
 	// Create App (Manages Lifecycle)
-	app := game.NewApp(window)
+	app := game.NewApp(window, firstRun)
 
 	// Setup input handlers (routes low level callbacks to App/Session)
 	game.SetupInputHandlers(app)
 
+	if *connect != "" {
+		if err := app.Connect(*connect, *name); err != nil {
+			slog.Error("connecting to server failed", "addr", *connect, "err", err)
+		}
+	}
+
 	// Run the app loop
 	app.Run()
 
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"log/slog"
 	"runtime"
 	"time"
 
@@ -120,7 +121,7 @@ void main() {
 			now := time.Now()
 			elapsed := now.Sub(last).Seconds()
 			if elapsed > 0 {
-				fmt.Printf("FPS: %d\n", int(float64(frames)/elapsed+0.5))
+				slog.Info("fps", "fps", int(float64(frames)/elapsed+0.5))
 			}
 			frames = 0
 			last = now
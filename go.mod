@@ -1,12 +1,19 @@
 module mini-mc
 
-go 1.24
+go 1.24.0
 
 require (
+	github.com/ebitengine/oto/v3 v3.4.0
 	github.com/go-gl/gl v0.0.0-20231021071112-07e5d0ea2e71
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20250301202403-da16c1255728
 	github.com/go-gl/mathgl v1.2.0
+	github.com/jfreymuth/oggvorbis v1.0.5
 	golang.org/x/image v0.19.0
 )
 
-require golang.org/x/text v0.17.0 // indirect
+require (
+	github.com/ebitengine/purego v0.9.0 // indirect
+	github.com/jfreymuth/vorbis v1.0.2 // indirect
+	golang.org/x/sys v0.36.0 // indirect
+	golang.org/x/text v0.17.0 // indirect
+)
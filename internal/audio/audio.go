@@ -0,0 +1,102 @@
+// Package audio plays sound events through the default audio device. It is
+// the output the sound package sends its events to: short sounds are loaded
+// from OGG files, placed around the listener and mixed with the music
+// streams into one stereo stream for the device.
+package audio
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"os"
+
+	"mini-mc/internal/sound"
+
+	"github.com/ebitengine/oto/v3"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/jfreymuth/oggvorbis"
+)
+
+// Output is the audio device with the sounds it has loaded.
+type Output struct {
+	mixer  *mixer
+	bank   *bank
+	player *oto.Player
+}
+
+// Open starts playback on the default audio device, loading sounds from
+// the files under dir (see bank). There can be only one Output.
+func Open(dir string) (*Output, error) {
+	ctx, ready, err := oto.NewContext(&oto.NewContextOptions{
+		SampleRate:   sampleRate,
+		ChannelCount: 2,
+		Format:       oto.FormatFloat32LE,
+	})
+	if err != nil {
+		return nil, err
+	}
+	<-ready
+
+	if _, err := os.Stat(dir); err != nil {
+		slog.Warn("no sound effects; see the README for where they come from", "dir", dir)
+	}
+	o := &Output{mixer: newMixer(), bank: newBank(dir)}
+	o.player = ctx.NewPlayer(o.mixer)
+	o.player.Play()
+	return o, nil
+}
+
+// Play starts a sound: a random variant of its clip, at its volume and
+// pitch, placed in the world unless it is on a bus outside it. The first
+// time a sound plays it starts once its files are decoded, a moment late.
+func (o *Output) Play(e sound.Event) {
+	s := o.bank.get(e.Name)
+	select {
+	case <-s.ready:
+		o.playClip(s.clips, e)
+	default:
+		go func() {
+			<-s.ready
+			o.playClip(s.clips, e)
+		}()
+	}
+}
+
+func (o *Output) playClip(clips []*clip, e sound.Event) {
+	if len(clips) == 0 {
+		return
+	}
+	o.mixer.play(clips[rand.Intn(len(clips))], e)
+}
+
+// SetListener moves the ears world sounds are placed around.
+func (o *Output) SetListener(pos, front mgl32.Vec3) {
+	o.mixer.setListener(pos, front)
+}
+
+// StreamMusic starts a music track, decoding it from disk as it plays. It
+// starts silent; Music raises its gain.
+func (o *Output) StreamMusic(path string) (sound.MusicStream, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := oggvorbis.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return o.mixer.stream(r, f), nil
+}
+
+// Close stops playback and every stream.
+func (o *Output) Close() error {
+	o.mixer.stopAll()
+	return o.player.Close()
+}
+
+var (
+	_ sound.Output      = (*Output)(nil)
+	_ sound.MusicOutput = (*Output)(nil)
+	_ sound.Listener    = (*Output)(nil)
+)
@@ -0,0 +1,114 @@
+package audio
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/jfreymuth/oggvorbis"
+)
+
+// clip is a decoded sound, mixed down to mono.
+type clip struct {
+	samples []float32
+	rate    int
+}
+
+// bank holds the clips sounds play from. A sound's files are named after
+// it with its dots as directories, like 1.8.9's assets: "dig.stone" is
+// dig/stone1.ogg, dig/stone2.ogg and so on, one of which is picked each
+// time, or dig/stone.ogg when there is only one. Clips are decoded the
+// first time a sound is played, on a goroutine of its own so the game loop
+// never waits on a file, and kept; a sound without files stays silent.
+type bank struct {
+	dir    string
+	mu     sync.Mutex
+	sounds map[string]*bankSound
+}
+
+// bankSound is the clips of one sound, which may still be decoding.
+type bankSound struct {
+	ready chan struct{} // closed once clips is set
+	clips []*clip
+}
+
+func newBank(dir string) *bank {
+	return &bank{dir: dir, sounds: make(map[string]*bankSound)}
+}
+
+// get returns a sound, starting to decode its files the first time.
+func (b *bank) get(name string) *bankSound {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if s, ok := b.sounds[name]; ok {
+		return s
+	}
+	s := &bankSound{ready: make(chan struct{})}
+	b.sounds[name] = s
+	go func() {
+		s.clips = b.load(name)
+		close(s.ready)
+	}()
+	return s
+}
+
+// load decodes the variants of a sound.
+func (b *bank) load(name string) []*clip {
+	var clips []*clip
+	base := filepath.Join(b.dir, filepath.FromSlash(strings.ReplaceAll(name, ".", "/")))
+	paths := []string{base + ".ogg"}
+	for i := 1; ; i++ {
+		path := fmt.Sprintf("%s%d.ogg", base, i)
+		if _, err := os.Stat(path); err != nil {
+			break
+		}
+		paths = append(paths, path)
+	}
+	for _, path := range paths {
+		c, err := loadClip(path)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				slog.Warn("loading sound failed", "path", path, "err", err)
+			}
+			continue
+		}
+		clips = append(clips, c)
+	}
+	if len(clips) == 0 {
+		slog.Debug("no files for sound", "name", name, "dir", b.dir)
+	}
+	return clips
+}
+
+// loadClip decodes an OGG file, averaging its channels.
+func loadClip(path string) (*clip, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, format, err := oggvorbis.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return &clip{samples: downmix(data, format.Channels), rate: format.SampleRate}, nil
+}
+
+// downmix averages interleaved samples of channels channels into one.
+func downmix(data []float32, channels int) []float32 {
+	if channels <= 1 {
+		return data
+	}
+	mono := make([]float32, len(data)/channels)
+	for i := range mono {
+		var sum float32
+		for c := range channels {
+			sum += data[i*channels+c]
+		}
+		mono[i] = sum / float32(channels)
+	}
+	return mono
+}
@@ -0,0 +1,22 @@
+package audio
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBankLoadsSoundsInTheBackground(t *testing.T) {
+	b := newBank(t.TempDir())
+	s := b.get("dig.stone")
+	select {
+	case <-s.ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("sound never finished loading")
+	}
+	if len(s.clips) != 0 {
+		t.Fatalf("%d clips loaded from an empty directory", len(s.clips))
+	}
+	if b.get("dig.stone") != s {
+		t.Fatal("sound loaded twice")
+	}
+}
@@ -0,0 +1,270 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"sync"
+
+	"mini-mc/internal/sound"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// The mixer's output is 44.1 kHz stereo. It plays up to maxVoices sounds at
+// once, dropping new ones past that, as MC runs out of channels. A world
+// sound fades linearly to silence at attenuationDistance blocks, further
+// for volumes over 1, and is panned toward the ear on its side, never
+// quite leaving the other.
+const (
+	sampleRate          = 44100
+	maxVoices           = 32
+	attenuationDistance = 16
+	maxPan              = 0.8
+	streamChunk         = 4096 // frames decoded at a time for music
+)
+
+// pitchRange is the pitch range MC plays sounds in.
+var pitchRange = [2]float32{0.5, 2}
+
+// voice is a clip playing.
+type voice struct {
+	clip   *clip
+	pos    float64 // in clip samples
+	step   float64 // clip samples per output frame
+	world  bool
+	at     mgl32.Vec3
+	volume float32
+	gain   [2]float32 // left, right
+}
+
+// mixer mixes the playing voices and music streams into the stereo stream
+// the device reads. The game loop starts sounds and moves the listener
+// while the device's goroutine reads, so everything is behind mu.
+type mixer struct {
+	mu      sync.Mutex
+	voices  []*voice
+	streams []*musicStream
+	ear     mgl32.Vec3
+	right   mgl32.Vec3
+	buf     []float32
+}
+
+func newMixer() *mixer {
+	return &mixer{right: mgl32.Vec3{1, 0, 0}}
+}
+
+// play starts clip c for event e.
+func (m *mixer) play(c *clip, e sound.Event) {
+	pitch := mgl32.Clamp(e.Pitch, pitchRange[0], pitchRange[1])
+	v := &voice{
+		clip:   c,
+		step:   float64(pitch) * float64(c.rate) / sampleRate,
+		world:  e.Bus.IsWorld(),
+		at:     e.Pos,
+		volume: e.Volume,
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.voices) >= maxVoices {
+		return
+	}
+	v.gain = m.placeGain(v)
+	m.voices = append(m.voices, v)
+}
+
+// setListener moves the ears to pos, facing front, and places the world
+// sounds playing around them again.
+func (m *mixer) setListener(pos, front mgl32.Vec3) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ear = pos
+	// Looking straight up or down keeps the last right
+	if right := front.Cross(mgl32.Vec3{0, 1, 0}); right.Len() > 1e-3 {
+		m.right = right.Normalize()
+	}
+	for _, v := range m.voices {
+		v.gain = m.placeGain(v)
+	}
+}
+
+// placeGain returns a voice's gain in each ear.
+func (m *mixer) placeGain(v *voice) [2]float32 {
+	if !v.world {
+		return [2]float32{v.volume, v.volume}
+	}
+	d := v.at.Sub(m.ear)
+	dist := d.Len()
+	g := min(v.volume, 1) * max(0, 1-dist/(attenuationDistance*max(v.volume, 1)))
+	var pan float32
+	if dist > 1e-3 {
+		pan = d.Dot(m.right) / dist * maxPan
+	}
+	return [2]float32{g * min(1, 1-pan), g * min(1, 1+pan)}
+}
+
+// Read fills p with the next frames of 32-bit float stereo. It never runs
+// out: with nothing playing it is silence.
+func (m *mixer) Read(p []byte) (int, error) {
+	frames := len(p) / 8
+	if cap(m.buf) < frames*2 {
+		m.buf = make([]float32, frames*2)
+	}
+	out := m.buf[:frames*2]
+	clear(out)
+
+	m.mu.Lock()
+	m.voices = mixVoices(out, m.voices)
+	for _, s := range m.streams {
+		s.mix(out)
+	}
+	m.mu.Unlock()
+
+	for i, s := range out {
+		binary.LittleEndian.PutUint32(p[i*4:], math.Float32bits(mgl32.Clamp(s, -1, 1)))
+	}
+	return frames * 8, nil
+}
+
+// mixVoices adds the voices' next frames to out and returns those still
+// playing.
+func mixVoices(out []float32, voices []*voice) []*voice {
+	playing := voices[:0]
+	for _, v := range voices {
+		samples := v.clip.samples
+		done := false
+		for f := 0; f < len(out)/2; f++ {
+			i := int(v.pos)
+			if i+1 >= len(samples) {
+				done = true
+				break
+			}
+			frac := float32(v.pos - float64(i))
+			s := samples[i] + (samples[i+1]-samples[i])*frac
+			out[2*f] += s * v.gain[0]
+			out[2*f+1] += s * v.gain[1]
+			v.pos += v.step
+		}
+		if !done {
+			playing = append(playing, v)
+		}
+	}
+	clear(voices[len(playing):])
+	return playing
+}
+
+// stopAll ends every stream and sound.
+func (m *mixer) stopAll() {
+	m.mu.Lock()
+	streams := m.streams
+	m.streams, m.voices = nil, nil
+	m.mu.Unlock()
+	for _, s := range streams {
+		s.closer.Close()
+	}
+}
+
+// streamReader is a decoder music is read from: interleaved samples.
+type streamReader interface {
+	Read(p []float32) (int, error)
+	SampleRate() int
+	Channels() int
+}
+
+// musicStream is a music track decoding as it plays. Its fields are
+// guarded by the mixer's mu.
+type musicStream struct {
+	m        *mixer
+	r        streamReader
+	closer   io.Closer
+	channels int
+	step     float64   // track frames per output frame
+	buf      []float32 // decoded frames not yet played, interleaved
+	pos      float64   // in frames of buf
+	gain     float32
+	done     bool
+}
+
+// stream starts playing r silently; closer is closed when it stops.
+func (m *mixer) stream(r streamReader, closer io.Closer) *musicStream {
+	s := &musicStream{
+		m:        m,
+		r:        r,
+		closer:   closer,
+		channels: r.Channels(),
+		step:     float64(r.SampleRate()) / sampleRate,
+	}
+	m.mu.Lock()
+	m.streams = append(m.streams, s)
+	m.mu.Unlock()
+	return s
+}
+
+// mix adds the stream's next frames to out, decoding more as needed.
+func (s *musicStream) mix(out []float32) {
+	if s.done {
+		return
+	}
+	for f := 0; f < len(out)/2; f++ {
+		i := int(s.pos)
+		if (i+1)*s.channels >= len(s.buf) {
+			if !s.fill() {
+				s.done = true
+				return
+			}
+			i = int(s.pos)
+		}
+		frac := float32(s.pos - float64(i))
+		for c := range 2 {
+			// Mono tracks play in both ears
+			ch := min(c, s.channels-1)
+			a, b := s.buf[i*s.channels+ch], s.buf[(i+1)*s.channels+ch]
+			out[2*f+c] += (a + (b-a)*frac) * s.gain
+		}
+		s.pos += s.step
+	}
+}
+
+// fill drops the frames played from buf and decodes the next chunk after
+// the rest, reporting false at the end of the track.
+func (s *musicStream) fill() bool {
+	played := int(s.pos)
+	s.buf = s.buf[min(played*s.channels, len(s.buf)):]
+	s.pos -= float64(played)
+
+	chunk := make([]float32, streamChunk*s.channels)
+	n, err := s.r.Read(chunk)
+	s.buf = append(s.buf, chunk[:n]...)
+	if n == 0 && err != nil {
+		return false
+	}
+	return (int(s.pos)+1)*s.channels < len(s.buf)
+}
+
+// SetGain sets the track's volume, 0-1.
+func (s *musicStream) SetGain(gain float32) {
+	s.m.mu.Lock()
+	s.gain = gain
+	s.m.mu.Unlock()
+}
+
+// Done reports whether the track has played to its end.
+func (s *musicStream) Done() bool {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+	return s.done
+}
+
+// Stop ends the track and closes its file.
+func (s *musicStream) Stop() {
+	m := s.m
+	m.mu.Lock()
+	for i, t := range m.streams {
+		if t == s {
+			m.streams = append(m.streams[:i], m.streams[i+1:]...)
+			break
+		}
+	}
+	m.mu.Unlock()
+	s.closer.Close()
+}
@@ -0,0 +1,131 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+
+	"mini-mc/internal/config"
+	"mini-mc/internal/sound"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestWorldSoundsArePlacedAroundTheListener(t *testing.T) {
+	m := newMixer()
+	// Facing +Z, so +X is on the left and -X on the right
+	m.setListener(mgl32.Vec3{0, 64, 0}, mgl32.Vec3{0, 0, 1})
+	c := &clip{samples: make([]float32, 100), rate: sampleRate}
+
+	for _, tc := range []struct {
+		name         string
+		at           mgl32.Vec3
+		bus          config.AudioBus
+		louder, same bool // right ear louder; both ears alike
+		silent       bool
+	}{
+		{name: "right", at: mgl32.Vec3{-4, 64, 0}, bus: config.AudioBusBlocks, louder: true},
+		{name: "ahead", at: mgl32.Vec3{0, 64, 4}, bus: config.AudioBusBlocks, same: true},
+		{name: "too far", at: mgl32.Vec3{0, 64, 17}, bus: config.AudioBusBlocks, silent: true},
+		{name: "ui anywhere", at: mgl32.Vec3{100, 0, 0}, bus: config.AudioBusUI, same: true},
+	} {
+		m.voices = nil
+		m.play(c, sound.Event{Pos: tc.at, Volume: 1, Pitch: 1, Bus: tc.bus})
+		g := m.voices[0].gain
+		switch {
+		case tc.silent && (g[0] != 0 || g[1] != 0):
+			t.Errorf("%s: gain %v, want silence", tc.name, g)
+		case tc.louder && !(g[1] > g[0] && g[0] > 0):
+			t.Errorf("%s: gain %v, want louder on the right", tc.name, g)
+		case tc.same && g[0] != g[1]:
+			t.Errorf("%s: gain %v, want both ears alike", tc.name, g)
+		}
+	}
+
+	// Turning around swaps the ears
+	m.voices = nil
+	m.play(c, sound.Event{Pos: mgl32.Vec3{-4, 64, 0}, Volume: 1, Pitch: 1, Bus: config.AudioBusBlocks})
+	m.setListener(mgl32.Vec3{0, 64, 0}, mgl32.Vec3{0, 0, -1})
+	if g := m.voices[0].gain; g[0] <= g[1] {
+		t.Errorf("after turning: gain %v, want louder on the left", g)
+	}
+}
+
+func TestMixerPlaysVoicesToTheirEnd(t *testing.T) {
+	m := newMixer()
+	c := &clip{samples: []float32{0.5, 0.5, 0.5, 0.5, 0.5}, rate: sampleRate}
+	m.play(c, sound.Event{Volume: 1, Pitch: 1, Bus: config.AudioBusUI})
+	m.play(c, sound.Event{Volume: 1, Pitch: 2, Bus: config.AudioBusUI})
+
+	frames := readFrames(t, m, 8)
+	// Both play for two frames, then the octave-up one has run out
+	for f, want := range []float32{1, 1, 0.5, 0.5, 0} {
+		if got := frames[2*f]; math.Abs(float64(got-want)) > 1e-6 {
+			t.Errorf("frame %d: %v, want %v", f, got, want)
+		}
+	}
+	if len(m.voices) != 0 {
+		t.Errorf("%d voices still playing", len(m.voices))
+	}
+}
+
+// fakeTrack is a mono track of n samples of 0.25.
+type fakeTrack struct{ n int }
+
+func (f *fakeTrack) Read(p []float32) (int, error) {
+	if f.n == 0 {
+		return 0, io.EOF
+	}
+	n := min(len(p), f.n)
+	for i := range n {
+		p[i] = 0.25
+	}
+	f.n -= n
+	return n, nil
+}
+
+func (f *fakeTrack) SampleRate() int { return sampleRate }
+
+func (f *fakeTrack) Channels() int { return 1 }
+
+type nopCloser struct{ closed *bool }
+
+func (c nopCloser) Close() error { *c.closed = true; return nil }
+
+func TestMusicStreamsUntilDone(t *testing.T) {
+	m := newMixer()
+	closed := false
+	s := m.stream(&fakeTrack{n: streamChunk + 10}, nopCloser{&closed})
+	s.SetGain(0.5)
+
+	frames := readFrames(t, m, streamChunk)
+	if frames[0] != 0.125 || frames[1] != 0.125 {
+		t.Fatalf("first frame %v, %v; want 0.125 in both ears", frames[0], frames[1])
+	}
+	if s.Done() {
+		t.Fatal("done before the end")
+	}
+	readFrames(t, m, 64)
+	if !s.Done() {
+		t.Fatal("not done after the end")
+	}
+	s.Stop()
+	if !closed || len(m.streams) != 0 {
+		t.Errorf("stopping left closed=%v, %d streams", closed, len(m.streams))
+	}
+}
+
+// readFrames reads n stereo frames from m.
+func readFrames(t *testing.T, m *mixer, n int) []float32 {
+	t.Helper()
+	p := make([]byte, n*8)
+	if got, err := m.Read(p); got != len(p) || err != nil {
+		t.Fatalf("Read = %d, %v", got, err)
+	}
+	out := make([]float32, n*2)
+	for i := range out {
+		out[i] = math.Float32frombits(binary.LittleEndian.Uint32(p[i*4:]))
+	}
+	return out
+}
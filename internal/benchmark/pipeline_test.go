@@ -121,10 +121,11 @@ func setupBenchWorld(seed int64, radius int) (*world.World, []*world.Chunk) {
 // Bit layout:
 //
 //	V1: X(5) | Y(9) | Z(5) | Normal(3) | Brightness(8)
-//	V2: TextureID(16) | Tint(16)
+//	V2: TextureID(10) | Occlusion(2) | Drop(4) | Tint(16)
 //
 // Output per vertex: [worldX, worldY, worldZ, info, texID, tint] as int16,
-// where info = normal | (brightness << 8).
+// where info = normal | (drop << 3) | (light << 8). The occlusion bits are
+// not unpacked.
 func unpackVertices(cpuVerts []uint32, baseX, baseY, baseZ int) []int16 {
 	count := len(cpuVerts) / 2
 	buf := make([]int16, 0, count*6)
@@ -136,14 +137,15 @@ func unpackVertices(cpuVerts []uint32, baseX, baseY, baseZ int) []int16 {
 		ly := int((v1 >> 5) & 0x1FF)
 		lz := int((v1 >> 14) & 0x1F)
 		norm := int((v1 >> 19) & 0x7)
-		brightness := int((v1 >> 22) & 0xFF)
-		texID := int(v2 & 0xFFFF)
+		light := int((v1 >> 22) & 0xFF)
+		texID := int(v2 & 0x3FF)
+		drop := int((v2 >> 12) & 0xF)
 		tint := int((v2 >> 16) & 0xFFFF)
 
 		wx := int16(baseX + lx)
 		wy := int16(baseY + ly)
 		wz := int16(baseZ + lz)
-		info := int16(norm | (brightness << 8))
+		info := int16(norm | (drop << 3) | (light << 8))
 
 		buf = append(buf, wx, wy, wz, info, int16(texID), int16(tint))
 	}
@@ -0,0 +1,26 @@
+// Package blockentity implements per-position block state (furnaces, item
+// frames, ...) stored in the world through world.BlockEntity.
+package blockentity
+
+import (
+	"mini-mc/internal/item"
+	"mini-mc/internal/world"
+)
+
+// Dropper is implemented by block entities holding items that spill out when
+// their block is broken.
+type Dropper interface {
+	Drops() []item.ItemStack
+}
+
+// New returns a fresh block entity for a block of type t placed with the given
+// metadata, or nil if the block has none.
+func New(t world.BlockType, meta uint8) world.BlockEntity {
+	switch t {
+	case world.BlockTypeFurnace, world.BlockTypeLitFurnace:
+		return NewFurnace(meta)
+	case world.BlockTypeItemFrame:
+		return NewItemFrame(meta)
+	}
+	return nil
+}
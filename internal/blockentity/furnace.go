@@ -0,0 +1,152 @@
+package blockentity
+
+import (
+	"mini-mc/internal/inventory"
+	"mini-mc/internal/item"
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+)
+
+// FurnaceCookTime is how many ticks one item takes to smelt (MC: 200).
+const FurnaceCookTime = 200
+
+// Furnace smelts its input into the output slot while fuel burns. It mirrors
+// MC 1.8's TileEntityFurnace.update.
+type Furnace struct {
+	slots [inventory.FurnaceSlotCount]*item.ItemStack
+
+	BurnTime            int // ticks left on the current fuel item
+	CurrentItemBurnTime int // total ticks of the current fuel item
+	CookTime            int // ticks spent on the current input item
+
+	facing uint8
+}
+
+// NewFurnace creates an empty furnace whose front points to facing.
+func NewFurnace(facing uint8) *Furnace {
+	return &Furnace{facing: facing}
+}
+
+// GetItem returns the stack in a furnace slot (see inventory.Furnace*Slot).
+func (f *Furnace) GetItem(index int) *item.ItemStack {
+	if index < 0 || index >= len(f.slots) {
+		return nil
+	}
+	return f.slots[index]
+}
+
+// SetItem replaces the stack in a furnace slot.
+func (f *Furnace) SetItem(index int, stack *item.ItemStack) {
+	if index < 0 || index >= len(f.slots) {
+		return
+	}
+	if stack != nil && stack.Count <= 0 {
+		stack = nil
+	}
+	f.slots[index] = stack
+}
+
+// IsBurning reports whether fuel is currently burning.
+func (f *Furnace) IsBurning() bool {
+	return f.BurnTime > 0
+}
+
+// CookProgress returns smelting progress of the current item in [0,1].
+func (f *Furnace) CookProgress() float32 {
+	return float32(f.CookTime) / FurnaceCookTime
+}
+
+// BurnProgress returns how much of the current fuel item is left in [0,1].
+func (f *Furnace) BurnProgress() float32 {
+	if f.CurrentItemBurnTime <= 0 {
+		return 0
+	}
+	return float32(f.BurnTime) / float32(f.CurrentItemBurnTime)
+}
+
+// Tick burns fuel and advances smelting by one tick.
+func (f *Furnace) Tick() {
+	if f.BurnTime > 0 {
+		f.BurnTime--
+	}
+
+	canSmelt := f.canSmelt()
+	if f.BurnTime == 0 && canSmelt {
+		if fuel := f.slots[inventory.FurnaceFuelSlot]; fuel != nil {
+			if burn := registry.GetBurnTime(fuel.Type); burn > 0 {
+				f.BurnTime = burn
+				f.CurrentItemBurnTime = burn
+				fuel.Count--
+				if fuel.Count <= 0 {
+					f.slots[inventory.FurnaceFuelSlot] = nil
+				}
+			}
+		}
+	}
+
+	switch {
+	case f.BurnTime > 0 && canSmelt:
+		f.CookTime++
+		if f.CookTime >= FurnaceCookTime {
+			f.CookTime = 0
+			f.smeltItem()
+		}
+	case f.BurnTime > 0:
+		f.CookTime = 0
+	default:
+		// Progress cools down while there is no fuel
+		f.CookTime = max(f.CookTime-2, 0)
+	}
+}
+
+// canSmelt reports whether the input has a recipe whose result fits in the output.
+func (f *Furnace) canSmelt() bool {
+	input := f.slots[inventory.FurnaceInputSlot]
+	if input == nil {
+		return false
+	}
+	recipe, ok := registry.GetSmeltingResult(input.Type)
+	if !ok {
+		return false
+	}
+	out := f.slots[inventory.FurnaceOutputSlot]
+	if out == nil {
+		return true
+	}
+	result := item.NewItemStack(recipe.Output, recipe.Count)
+	return out.CanStackWith(result) && out.Count+recipe.Count <= out.GetMaxStackSize()
+}
+
+func (f *Furnace) smeltItem() {
+	input := f.slots[inventory.FurnaceInputSlot]
+	recipe, _ := registry.GetSmeltingResult(input.Type)
+	if out := f.slots[inventory.FurnaceOutputSlot]; out != nil {
+		out.Count += recipe.Count
+	} else {
+		result := item.NewItemStack(recipe.Output, recipe.Count)
+		f.slots[inventory.FurnaceOutputSlot] = &result
+	}
+	input.Count--
+	if input.Count <= 0 {
+		f.slots[inventory.FurnaceInputSlot] = nil
+	}
+}
+
+// Block implements world.BlockEntity: the furnace is lit while fuel burns.
+func (f *Furnace) Block() (world.BlockType, uint8) {
+	if f.IsBurning() {
+		return world.BlockTypeLitFurnace, f.facing
+	}
+	return world.BlockTypeFurnace, f.facing
+}
+
+// Drops implements Dropper.
+func (f *Furnace) Drops() []item.ItemStack {
+	var out []item.ItemStack
+	for _, s := range f.slots {
+		if s != nil && s.Count > 0 {
+			out = append(out, *s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+package blockentity
+
+import (
+	"testing"
+
+	"mini-mc/internal/inventory"
+	"mini-mc/internal/item"
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+)
+
+func TestFurnaceSmeltsWithFuel(t *testing.T) {
+	registry.RegisterSmelting(world.BlockTypeCobblestone, world.BlockTypeStone, 1)
+	registry.RegisterFuel(world.BlockTypePlanksOak, 300)
+
+	f := NewFurnace(uint8(world.FaceNorth))
+	input := item.NewItemStack(world.BlockTypeCobblestone, 2)
+	fuel := item.NewItemStack(world.BlockTypePlanksOak, 1)
+	f.SetItem(inventory.FurnaceInputSlot, &input)
+	f.SetItem(inventory.FurnaceFuelSlot, &fuel)
+
+	f.Tick()
+	if bt, _ := f.Block(); bt != world.BlockTypeLitFurnace {
+		t.Fatalf("furnace did not light: block = %v", bt)
+	}
+	if f.GetItem(inventory.FurnaceFuelSlot) != nil {
+		t.Fatal("fuel was not consumed")
+	}
+
+	for range FurnaceCookTime {
+		f.Tick()
+	}
+	out := f.GetItem(inventory.FurnaceOutputSlot)
+	if out == nil || out.Type != world.BlockTypeStone || out.Count != 1 {
+		t.Fatalf("output = %+v, want 1 stone", out)
+	}
+	if in := f.GetItem(inventory.FurnaceInputSlot); in == nil || in.Count != 1 {
+		t.Fatalf("input = %+v, want 1 cobblestone left", in)
+	}
+
+	// One plank burns for 300 ticks; the furnace goes out before the second item is done
+	for range 300 {
+		f.Tick()
+	}
+	if f.IsBurning() {
+		t.Fatal("furnace still burning after its fuel ran out")
+	}
+	if out := f.GetItem(inventory.FurnaceOutputSlot); out.Count != 1 {
+		t.Fatalf("output count = %d, want 1", out.Count)
+	}
+}
@@ -0,0 +1,61 @@
+package blockentity
+
+import (
+	"mini-mc/internal/item"
+	"mini-mc/internal/world"
+)
+
+// ItemFrameRotations is how many steps a framed item turns through (45° each).
+const ItemFrameRotations = 8
+
+// ItemFrame shows a single item on the side of a block. Right-clicking puts
+// the held item in; further clicks rotate it.
+type ItemFrame struct {
+	Item     *item.ItemStack
+	Rotation int // 0..ItemFrameRotations-1
+
+	facing uint8
+}
+
+// NewItemFrame creates an empty frame whose front points to facing.
+func NewItemFrame(facing uint8) *ItemFrame {
+	return &ItemFrame{facing: facing}
+}
+
+// Facing returns the face the frame's front points to (see world.FacingOffset).
+func (f *ItemFrame) Facing() uint8 {
+	return f.facing
+}
+
+// Interact handles a right-click with held (may be nil). An empty frame takes
+// one item from held and reports true so the caller consumes it; a filled
+// frame rotates its item instead.
+func (f *ItemFrame) Interact(held *item.ItemStack) (consumed bool) {
+	if f.Item != nil {
+		f.Rotation = (f.Rotation + 1) % ItemFrameRotations
+		return false
+	}
+	if held == nil || held.Count <= 0 || held.Type == world.BlockTypeAir {
+		return false
+	}
+	one := held.WithCount(1)
+	f.Item = &one
+	f.Rotation = 0
+	return true
+}
+
+// Tick implements world.BlockEntity; frames have nothing to update.
+func (f *ItemFrame) Tick() {}
+
+// Block implements world.BlockEntity.
+func (f *ItemFrame) Block() (world.BlockType, uint8) {
+	return world.BlockTypeItemFrame, f.facing
+}
+
+// Drops implements Dropper.
+func (f *ItemFrame) Drops() []item.ItemStack {
+	if f.Item == nil {
+		return nil
+	}
+	return []item.ItemStack{*f.Item}
+}
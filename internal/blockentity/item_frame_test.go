@@ -0,0 +1,35 @@
+package blockentity
+
+import (
+	"testing"
+
+	"mini-mc/internal/item"
+	"mini-mc/internal/world"
+)
+
+func TestItemFrameInsertRotateDrop(t *testing.T) {
+	f := NewItemFrame(uint8(world.FaceEast))
+	held := item.NewItemStack(world.BlockTypeStonePickaxe, 1)
+	held.Damage = 12
+
+	if !f.Interact(&held) {
+		t.Fatal("empty frame did not take the held item")
+	}
+	if f.Item == nil || f.Item.Damage != 12 {
+		t.Fatalf("framed item = %+v, want the pickaxe with its damage", f.Item)
+	}
+
+	other := item.NewItemStack(world.BlockTypeDirt, 5)
+	for range ItemFrameRotations + 1 {
+		if f.Interact(&other) {
+			t.Fatal("filled frame consumed another item")
+		}
+	}
+	if f.Rotation != 1 {
+		t.Fatalf("rotation = %d, want 1 after wrapping around", f.Rotation)
+	}
+
+	if drops := f.Drops(); len(drops) != 1 || drops[0].Type != world.BlockTypeStonePickaxe {
+		t.Fatalf("drops = %+v, want the framed pickaxe", drops)
+	}
+}
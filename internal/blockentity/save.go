@@ -0,0 +1,119 @@
+package blockentity
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"mini-mc/internal/inventory"
+	"mini-mc/internal/item"
+	"mini-mc/internal/world"
+)
+
+// Block entity type IDs, as saved with chunks.
+const (
+	TypeFurnace   world.BlockEntityTypeID = "furnace"
+	TypeItemFrame world.BlockEntityTypeID = "item_frame"
+)
+
+func init() {
+	world.RegisterBlockEntityType(world.BlockEntityType{ID: TypeFurnace, Load: loadFurnace})
+	world.RegisterBlockEntityType(world.BlockEntityType{ID: TypeItemFrame, Load: loadItemFrame})
+}
+
+// Saved block entities are their state structs below in little-endian
+// order. An empty slot is saved as a stack of air.
+
+type stackState struct {
+	Type   world.BlockType
+	Count  int32
+	Damage int32
+}
+
+type furnaceState struct {
+	Slots               [inventory.FurnaceSlotCount]stackState
+	BurnTime            int32
+	CurrentItemBurnTime int32
+	CookTime            int32
+	Facing              uint8
+}
+
+type itemFrameState struct {
+	Item     stackState
+	Rotation uint8
+	Facing   uint8
+}
+
+func saveStack(s *item.ItemStack) stackState {
+	if s == nil {
+		return stackState{}
+	}
+	return stackState{Type: s.Type, Count: int32(s.Count), Damage: int32(s.Damage)}
+}
+
+func loadStack(s stackState) *item.ItemStack {
+	if s.Type == world.BlockTypeAir || s.Count <= 0 {
+		return nil
+	}
+	return &item.ItemStack{Type: s.Type, Count: int(s.Count), Damage: int(s.Damage)}
+}
+
+func (f *Furnace) BlockEntityType() world.BlockEntityTypeID { return TypeFurnace }
+
+// SaveBlockEntity returns the furnace's slots, fuel and smelting progress.
+func (f *Furnace) SaveBlockEntity() []byte {
+	s := furnaceState{
+		BurnTime:            int32(f.BurnTime),
+		CurrentItemBurnTime: int32(f.CurrentItemBurnTime),
+		CookTime:            int32(f.CookTime),
+		Facing:              f.facing,
+	}
+	for i, slot := range f.slots {
+		s.Slots[i] = saveStack(slot)
+	}
+	data, _ := binary.Append(nil, binary.LittleEndian, s)
+	return data
+}
+
+func loadFurnace(data []byte) (world.BlockEntity, error) {
+	var s furnaceState
+	if _, err := binary.Decode(data, binary.LittleEndian, &s); err != nil {
+		return nil, fmt.Errorf("furnace: %w", err)
+	}
+	f := NewFurnace(s.Facing)
+	for i, slot := range s.Slots {
+		f.slots[i] = loadStack(slot)
+	}
+	f.BurnTime = int(s.BurnTime)
+	f.CurrentItemBurnTime = int(s.CurrentItemBurnTime)
+	f.CookTime = int(s.CookTime)
+	return f, nil
+}
+
+func (f *ItemFrame) BlockEntityType() world.BlockEntityTypeID { return TypeItemFrame }
+
+// SaveBlockEntity returns the framed item and how it is turned.
+func (f *ItemFrame) SaveBlockEntity() []byte {
+	data, _ := binary.Append(nil, binary.LittleEndian, itemFrameState{
+		Item:     saveStack(f.Item),
+		Rotation: uint8(f.Rotation),
+		Facing:   f.facing,
+	})
+	return data
+}
+
+func loadItemFrame(data []byte) (world.BlockEntity, error) {
+	var s itemFrameState
+	if _, err := binary.Decode(data, binary.LittleEndian, &s); err != nil {
+		return nil, fmt.Errorf("item frame: %w", err)
+	}
+	f := NewItemFrame(s.Facing)
+	f.Item = loadStack(s.Item)
+	f.Rotation = int(s.Rotation) % ItemFrameRotations
+	return f, nil
+}
+
+// Block entities that persist with their chunk
+var (
+	_ world.SavedBlockEntity = (*Furnace)(nil)
+	_ world.SavedBlockEntity = (*ItemFrame)(nil)
+)
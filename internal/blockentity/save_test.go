@@ -0,0 +1,77 @@
+package blockentity
+
+import (
+	"testing"
+
+	"mini-mc/internal/inventory"
+	"mini-mc/internal/item"
+	"mini-mc/internal/world"
+)
+
+func TestBlockEntitiesSavedWithTheirChunk(t *testing.T) {
+	dir := t.TempDir()
+	w, err := world.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.StreamChunksAroundSync(8, 8, 0)
+
+	furnace := NewFurnace(uint8(world.FaceSouth))
+	input := item.NewItemStack(world.BlockTypeCobblestone, 5)
+	furnace.SetItem(inventory.FurnaceInputSlot, &input)
+	furnace.CookTime = 42
+	w.SetWithMeta(3, 100, 4, world.BlockTypeFurnace, uint8(world.FaceSouth))
+	w.SetBlockEntity(3, 100, 4, furnace)
+
+	frame := NewItemFrame(uint8(world.FaceEast))
+	held := item.NewItemStack(world.BlockTypeStonePickaxe, 1)
+	held.Damage = 7
+	frame.Interact(&held)
+	frame.Interact(nil)
+	w.SetWithMeta(5, 101, 6, world.BlockTypeItemFrame, uint8(world.FaceEast))
+	w.SetBlockEntity(5, 101, 6, frame)
+
+	check := func(when string) {
+		t.Helper()
+		f, ok := w.BlockEntityAt(3, 100, 4).(*Furnace)
+		if !ok {
+			t.Fatalf("%s: no furnace", when)
+		}
+		if in := f.GetItem(inventory.FurnaceInputSlot); in == nil || in.Type != world.BlockTypeCobblestone || in.Count != 5 {
+			t.Fatalf("%s: furnace input = %+v, want 5 cobblestone", when, in)
+		}
+		if f.CookTime != 42 {
+			t.Fatalf("%s: cook time = %d, want 42", when, f.CookTime)
+		}
+		fr, ok := w.BlockEntityAt(5, 101, 6).(*ItemFrame)
+		if !ok {
+			t.Fatalf("%s: no item frame", when)
+		}
+		if fr.Item == nil || fr.Item.Type != world.BlockTypeStonePickaxe || fr.Item.Damage != 7 {
+			t.Fatalf("%s: framed item = %+v, want the damaged pickaxe", when, fr.Item)
+		}
+		if fr.Rotation != 1 || fr.Facing() != uint8(world.FaceEast) {
+			t.Fatalf("%s: frame rotation %d facing %d, want 1 and east", when, fr.Rotation, fr.Facing())
+		}
+	}
+
+	w.EvictFarChunks(10000, 10000, 1)
+	if w.BlockEntityAt(3, 100, 4) != nil {
+		t.Fatal("furnace stayed in the world after its chunk unloaded")
+	}
+	w.StreamChunksAroundSync(8, 8, 0)
+	check("after reload")
+
+	if err := w.Save(); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	w, err = world.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.StreamChunksAroundSync(8, 8, 0)
+	check("after reopening")
+}
@@ -0,0 +1,114 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"mini-mc/internal/world"
+)
+
+// Host is the server the admin commands act on. Its methods are called from
+// console and admin connection goroutines, so they must be safe to call
+// concurrently with the game loop, e.g. by handing the work to the loop and
+// waiting for it.
+type Host interface {
+	Players() []string
+	Kick(player, reason string) error
+	SaveAll() error
+	// Stop saves and shuts the server down; it may return before it has.
+	Stop()
+	GameRule(name string) (string, bool)
+	SetGameRule(name, value string) error
+}
+
+// RegisterAdmin adds the server administration commands: list, kick,
+// save-all, stop and gamerule.
+func RegisterAdmin(d *Dispatcher, h Host) {
+	d.Register(Command{
+		Name: "list",
+		Help: "lists the players online",
+		Run: func(out io.Writer, args []string) error {
+			if len(args) != 0 {
+				return ErrUsage
+			}
+			players := h.Players()
+			fmt.Fprintf(out, "%d players online: %s\n", len(players), strings.Join(players, ", "))
+			return nil
+		},
+	})
+	d.Register(Command{
+		Name:  "kick",
+		Usage: "<player> [reason]",
+		Help:  "disconnects a player",
+		Run: func(out io.Writer, args []string) error {
+			if len(args) == 0 {
+				return ErrUsage
+			}
+			reason := strings.Join(args[1:], " ")
+			if reason == "" {
+				reason = "Kicked by an operator"
+			}
+			if err := h.Kick(args[0], reason); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Kicked %s: %s\n", args[0], reason)
+			return nil
+		},
+	})
+	d.Register(Command{
+		Name: "save-all",
+		Help: "saves the world now",
+		Run: func(out io.Writer, args []string) error {
+			if len(args) != 0 {
+				return ErrUsage
+			}
+			if err := h.SaveAll(); err != nil {
+				return fmt.Errorf("saving failed: %w", err)
+			}
+			fmt.Fprintln(out, "Saved the world")
+			return nil
+		},
+	})
+	d.Register(Command{
+		Name: "stop",
+		Help: "saves the world and stops the server",
+		Run: func(out io.Writer, args []string) error {
+			if len(args) != 0 {
+				return ErrUsage
+			}
+			fmt.Fprintln(out, "Stopping the server")
+			h.Stop()
+			return nil
+		},
+	})
+	d.Register(Command{
+		Name:  "gamerule",
+		Usage: "[rule [value]]",
+		Help:  "lists the game rules, or shows or sets one",
+		Run: func(out io.Writer, args []string) error {
+			switch len(args) {
+			case 0:
+				for _, name := range world.GameRuleNames() {
+					v, _ := h.GameRule(name)
+					fmt.Fprintf(out, "%s = %s\n", name, v)
+				}
+			case 1:
+				v, ok := h.GameRule(args[0])
+				if !ok {
+					return fmt.Errorf("unknown game rule %q", args[0])
+				}
+				fmt.Fprintf(out, "%s = %s\n", args[0], v)
+			case 2:
+				if err := h.SetGameRule(args[0], args[1]); err != nil {
+					return err
+				}
+				v, _ := h.GameRule(args[0])
+				fmt.Fprintf(out, "Game rule %s is now %s\n", args[0], v)
+			default:
+				return ErrUsage
+			}
+			return nil
+		},
+	})
+}
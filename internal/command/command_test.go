@@ -0,0 +1,352 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// fakeHost is a server with a fixed player list and an in-memory level.
+type fakeHost struct {
+	players []string
+	kicked  map[string]string
+	saves   int
+	stopped bool
+	level   world.Level
+}
+
+func newFakeHost(players ...string) *fakeHost {
+	return &fakeHost{players: players, kicked: make(map[string]string)}
+}
+
+func (h *fakeHost) Players() []string { return h.players }
+
+func (h *fakeHost) Kick(player, reason string) error {
+	for _, p := range h.players {
+		if p == player {
+			h.kicked[player] = reason
+			return nil
+		}
+	}
+	return errors.New("no such player")
+}
+
+func (h *fakeHost) SaveAll() error { h.saves++; return nil }
+func (h *fakeHost) Stop()          { h.stopped = true }
+
+func (h *fakeHost) GameRule(name string) (string, bool) { return h.level.GameRule(name) }
+
+func (h *fakeHost) SetGameRule(name, value string) error { return h.level.SetGameRule(name, value) }
+
+func run(t *testing.T, d *Dispatcher, line string) (string, error) {
+	t.Helper()
+	var out strings.Builder
+	err := d.Execute(line, &out)
+	return out.String(), err
+}
+
+func TestAdminCommands(t *testing.T) {
+	h := newFakeHost("alex", "steve")
+	d := NewDispatcher()
+	RegisterAdmin(d, h)
+
+	if out, err := run(t, d, "list"); err != nil || !strings.Contains(out, "2 players online: alex, steve") {
+		t.Errorf("list = %q, %v", out, err)
+	}
+	if _, err := run(t, d, "/kick steve griefing again"); err != nil || h.kicked["steve"] != "griefing again" {
+		t.Errorf("kick: %v, kicked %v", err, h.kicked)
+	}
+	if _, err := run(t, d, "kick herobrine"); err == nil {
+		t.Error("kicking an unknown player succeeded")
+	}
+	if _, err := run(t, d, "kick"); err == nil || !strings.HasPrefix(err.Error(), "usage: kick <player>") {
+		t.Errorf("kick without a player: %v", err)
+	}
+	if _, err := run(t, d, "save-all"); err != nil || h.saves != 1 {
+		t.Errorf("save-all: %v, %d saves", err, h.saves)
+	}
+
+	if _, err := run(t, d, "gamerule keepInventory yes"); err == nil {
+		t.Error("boolean game rule accepted yes")
+	}
+	if _, err := run(t, d, "gamerule keepInventory true"); err != nil || !h.level.GameRuleBool("keepInventory") {
+		t.Errorf("setting keepInventory: %v", err)
+	}
+	if out, err := run(t, d, "gamerule randomTickSpeed"); err != nil || out != "randomTickSpeed = 3\n" {
+		t.Errorf("gamerule randomTickSpeed = %q, %v", out, err)
+	}
+
+	if _, err := run(t, d, "stop"); err != nil || !h.stopped {
+		t.Errorf("stop: %v, stopped %v", err, h.stopped)
+	}
+	if _, err := run(t, d, "explode"); err == nil {
+		t.Error("unknown command ran")
+	}
+}
+
+func TestRunConsole(t *testing.T) {
+	h := newFakeHost()
+	d := NewDispatcher()
+	RegisterAdmin(d, h)
+
+	var out strings.Builder
+	if err := RunConsole(d, strings.NewReader("save-all\n\nnope\nsave-all\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+	if h.saves != 2 || !strings.Contains(out.String(), `unknown command "nope"`) {
+		t.Errorf("%d saves, output %q", h.saves, out.String())
+	}
+}
+
+// fakeGame is a player standing in a world of set blocks, with a stone block
+// and a stick item and room for 64 items.
+type fakeGame struct {
+	pos    mgl32.Vec3
+	given  map[world.BlockType]int
+	time   float64
+	mode   string
+	blocks map[[3]int]world.BlockType
+}
+
+func newFakeGame() *fakeGame {
+	return &fakeGame{
+		pos:    mgl32.Vec3{10.5, 64, -3.5},
+		given:  make(map[world.BlockType]int),
+		mode:   "survival",
+		blocks: make(map[[3]int]world.BlockType),
+	}
+}
+
+func (g *fakeGame) Position() mgl32.Vec3    { return g.pos }
+func (g *fakeGame) Teleport(pos mgl32.Vec3) { g.pos = pos }
+func (g *fakeGame) Time() float64           { return g.time }
+func (g *fakeGame) SetTime(ticks float64)   { g.time = ticks }
+func (g *fakeGame) SetGameMode(mode string) { g.mode = mode }
+func (g *fakeGame) Seed() int64             { return 42 }
+
+func (g *fakeGame) Item(name string) (world.BlockType, bool) {
+	switch name {
+	case "stick":
+		return world.BlockTypeStick, true
+	case "stone":
+		return world.BlockTypeStone, true
+	}
+	return 0, false
+}
+
+func (g *fakeGame) Block(name string) (world.BlockType, bool) {
+	if name == "stone" {
+		return world.BlockTypeStone, true
+	}
+	return 0, false
+}
+
+func (g *fakeGame) Give(item world.BlockType, count int) int {
+	room := 64
+	for _, n := range g.given {
+		room -= n
+	}
+	count = min(count, room)
+	g.given[item] += count
+	return count
+}
+
+// Loaded reports the 64 blocks either side of the origin as loaded.
+func (g *fakeGame) Loaded(x, z int) bool { return max(x, -x, z, -z) < 64 }
+
+func (g *fakeGame) SetBlock(x, y, z int, block world.BlockType) {
+	g.blocks[[3]int{x, y, z}] = block
+}
+
+func TestGameCommands(t *testing.T) {
+	g := newFakeGame()
+	d := NewDispatcher()
+	RegisterGame(d, g)
+
+	if _, err := run(t, d, "/tp 3 70 -8"); err != nil || g.pos != (mgl32.Vec3{3.5, 70, -7.5}) {
+		t.Errorf("tp to whole blocks: %v, at %v", err, g.pos)
+	}
+	if _, err := run(t, d, "/tp ~ ~-2 ~0.25"); err != nil || g.pos != (mgl32.Vec3{3.5, 68, -7.25}) {
+		t.Errorf("relative tp: %v, at %v", err, g.pos)
+	}
+	if _, err := run(t, d, "/tp 1 2 north"); err == nil {
+		t.Error("tp took a word for a coordinate")
+	}
+
+	if _, err := run(t, d, "/give minecraft:stick 40"); err != nil || g.given[world.BlockTypeStick] != 40 {
+		t.Errorf("give stick: %v, given %v", err, g.given)
+	}
+	if out, err := run(t, d, "/give stone 64"); err != nil || out != "Gave 24 stone\n" {
+		t.Errorf("give past a full inventory = %q, %v", out, err)
+	}
+	if _, err := run(t, d, "/give stone"); err == nil {
+		t.Error("give into a full inventory succeeded")
+	}
+	if _, err := run(t, d, "/give diamond"); err == nil {
+		t.Error("gave an unknown item")
+	}
+
+	g.time = 3*world.DayLength + 500
+	if _, err := run(t, d, "/time set midnight"); err != nil || g.time != 3*world.DayLength+18000 {
+		t.Errorf("time set midnight: %v, time %v", err, g.time)
+	}
+	if out, err := run(t, d, "/time add 7000"); err != nil || out != "Set the time to 1000\n" {
+		t.Errorf("time add = %q, %v", out, err)
+	}
+	if _, err := run(t, d, "/time set teatime"); err == nil {
+		t.Error("time set took an unknown time")
+	}
+
+	if _, err := run(t, d, "/gamemode c"); err != nil || g.mode != "creative" {
+		t.Errorf("gamemode c: %v, mode %q", err, g.mode)
+	}
+	if out, err := run(t, d, "/seed"); err != nil || out != "Seed: 42\n" {
+		t.Errorf("seed = %q, %v", out, err)
+	}
+
+	g.pos = mgl32.Vec3{0.5, 64, 0.5}
+	if out, err := run(t, d, "/fill ~ ~ ~ ~2 ~-1 1 stone"); err != nil || out != "Filled 12 blocks\n" {
+		t.Errorf("fill = %q, %v", out, err)
+	}
+	if g.blocks[[3]int{2, 63, 1}] != world.BlockTypeStone || len(g.blocks) != 12 {
+		t.Errorf("filled %d blocks: %v", len(g.blocks), g.blocks)
+	}
+	if _, err := run(t, d, "/fill 0 0 0 100 100 100 stone"); err == nil || len(g.blocks) != 12 {
+		t.Errorf("oversized fill: %v", err)
+	}
+	if _, err := run(t, d, "/fill 60 0 0 70 1 1 stone"); err == nil || len(g.blocks) != 12 {
+		t.Errorf("fill reaching unloaded blocks: %v, %d blocks", err, len(g.blocks))
+	}
+	if _, err := run(t, d, "/fill 0 0 0 1 1 1 stick"); err == nil {
+		t.Error("filled with an item")
+	}
+}
+
+func TestComplete(t *testing.T) {
+	d := NewDispatcher()
+	RegisterGame(d, newFakeGame())
+	if got := strings.Join(d.Complete("g"), " "); got != "gamemode give" {
+		t.Errorf("Complete(g) = %q", got)
+	}
+	if got := d.Complete("x"); len(got) != 0 {
+		t.Errorf("Complete(x) = %v", got)
+	}
+}
+
+// fakeReplays keeps one recording in memory.
+type fakeReplays struct {
+	recording bool
+	saved     int // ticks in the saved recording; 0 for none
+	playing   string
+	camera    bool
+}
+
+func (r *fakeReplays) StartRecording() error {
+	if r.recording {
+		return errors.New("already recording")
+	}
+	r.recording = true
+	return nil
+}
+
+func (r *fakeReplays) StopRecording() (string, int, error) {
+	if !r.recording {
+		return "", 0, errors.New("not recording")
+	}
+	r.recording, r.saved = false, 30
+	return "walk", r.saved, nil
+}
+
+func (r *fakeReplays) Play(name string, camera bool) (int, error) {
+	if r.saved == 0 || name != "walk" && name != "last" {
+		return 0, errors.New("no such recording")
+	}
+	r.playing, r.camera = name, camera
+	return r.saved, nil
+}
+
+func (r *fakeReplays) StopPlayback() bool {
+	playing := r.playing != ""
+	r.playing = ""
+	return playing
+}
+
+func TestReplayCommands(t *testing.T) {
+	r := &fakeReplays{}
+	d := NewDispatcher()
+	RegisterReplay(d, r)
+
+	if _, err := run(t, d, "replay last"); err == nil {
+		t.Error("replayed before anything was recorded")
+	}
+	if _, err := run(t, d, "record start"); err != nil || !r.recording {
+		t.Errorf("record start: %v", err)
+	}
+	if out, err := run(t, d, "record stop"); err != nil || !strings.Contains(out, "walk, 1.5 seconds") {
+		t.Errorf("record stop = %q, %v", out, err)
+	}
+	if _, err := run(t, d, "record"); err == nil || !strings.HasPrefix(err.Error(), "usage") {
+		t.Errorf("record with no arguments: %v", err)
+	}
+
+	if _, err := run(t, d, "replay walk camera"); err != nil || r.playing != "walk" || !r.camera {
+		t.Errorf("replay walk camera: %v, playing %q camera %v", err, r.playing, r.camera)
+	}
+	if _, err := run(t, d, "replay last ghost"); err == nil {
+		t.Error("replay took an unknown mode")
+	}
+	if _, err := run(t, d, "replay stop"); err != nil || r.playing != "" {
+		t.Errorf("replay stop: %v", err)
+	}
+	if _, err := run(t, d, "replay stop"); err == nil {
+		t.Error("stopped a replay when none was playing")
+	}
+}
+
+// fakePregen runs one job at a time, over a square of columns.
+type fakePregen struct {
+	radius int // of the running job; 0 for none
+}
+
+func (p *fakePregen) StartPregen(radius int) (int, error) {
+	if p.radius != 0 {
+		return 0, errors.New("already pregenerating")
+	}
+	p.radius = radius
+	side := 2*radius + 1
+	return side * side, nil
+}
+
+func (p *fakePregen) StopPregen() bool {
+	running := p.radius != 0
+	p.radius = 0
+	return running
+}
+
+func TestPregenCommand(t *testing.T) {
+	p := &fakePregen{}
+	d := NewDispatcher()
+	RegisterPregen(d, p)
+
+	for _, line := range []string{"pregen", "pregen 0", "pregen -3", "pregen far", "pregen 4 8"} {
+		if _, err := run(t, d, line); err == nil {
+			t.Errorf("%q succeeded", line)
+		}
+	}
+	if out, err := run(t, d, "pregen 4"); err != nil || p.radius != 4 || !strings.Contains(out, "81 columns") {
+		t.Errorf("pregen 4 = %q, %v; radius %d", out, err, p.radius)
+	}
+	if _, err := run(t, d, "pregen 2"); err == nil || p.radius != 4 {
+		t.Errorf("second job started: %v, radius %d", err, p.radius)
+	}
+	if _, err := run(t, d, "pregen stop"); err != nil || p.radius != 0 {
+		t.Errorf("pregen stop: %v", err)
+	}
+	if _, err := run(t, d, "pregen stop"); err == nil {
+		t.Error("stopped pregeneration when none was running")
+	}
+}
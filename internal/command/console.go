@@ -0,0 +1,19 @@
+package command
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// RunConsole executes each line read from in, writing output and errors to
+// out, until in ends. It is the server's interactive stdin console.
+func RunConsole(d *Dispatcher, in io.Reader, out io.Writer) error {
+	sc := bufio.NewScanner(in)
+	for sc.Scan() {
+		if err := d.Execute(sc.Text(), out); err != nil {
+			fmt.Fprintln(out, err)
+		}
+	}
+	return sc.Err()
+}
@@ -0,0 +1,127 @@
+// Package command parses and runs console commands. One dispatcher serves
+// every place commands are typed: the server's stdin console, the remote
+// admin interface and the in-game console.
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrUsage is returned by a command's Run when its arguments are wrong; the
+// dispatcher replaces it with the command's usage line.
+var ErrUsage = errors.New("wrong arguments")
+
+// Command is one console command.
+type Command struct {
+	Name  string
+	Usage string // arguments after the name, e.g. "<player> [reason]"
+	Help  string // one line shown by help
+	// Run executes the command with the words after its name, writing any
+	// output to out.
+	Run func(out io.Writer, args []string) error
+}
+
+// Dispatcher looks up and runs commands by name. It is safe to use from
+// several goroutines; commands themselves must be too.
+type Dispatcher struct {
+	mu       sync.RWMutex
+	commands map[string]*Command
+}
+
+// NewDispatcher returns a dispatcher with only the help command.
+func NewDispatcher() *Dispatcher {
+	d := &Dispatcher{commands: make(map[string]*Command)}
+	d.Register(Command{
+		Name:  "help",
+		Usage: "[command]",
+		Help:  "lists commands, or shows how to use one",
+		Run:   d.help,
+	})
+	return d
+}
+
+// Register adds a command, replacing any command with the same name.
+func (d *Dispatcher) Register(c Command) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.commands[c.Name] = &c
+}
+
+// Names returns the names of all commands, sorted.
+func (d *Dispatcher) Names() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	names := make([]string, 0, len(d.commands))
+	for name := range d.commands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Complete returns the names of the commands starting with prefix, sorted.
+func (d *Dispatcher) Complete(prefix string) []string {
+	var names []string
+	for _, name := range d.Names() {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (d *Dispatcher) lookup(name string) *Command {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.commands[name]
+}
+
+// Execute runs a command line. A leading slash is optional and blank lines
+// do nothing.
+func (d *Dispatcher) Execute(line string, out io.Writer) error {
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), "/"))
+	if len(fields) == 0 {
+		return nil
+	}
+	c := d.lookup(fields[0])
+	if c == nil {
+		return fmt.Errorf("unknown command %q, try help", fields[0])
+	}
+	err := c.Run(out, fields[1:])
+	if errors.Is(err, ErrUsage) {
+		return fmt.Errorf("usage: %s", usageLine(c))
+	}
+	return err
+}
+
+func usageLine(c *Command) string {
+	if c.Usage == "" {
+		return c.Name
+	}
+	return c.Name + " " + c.Usage
+}
+
+func (d *Dispatcher) help(out io.Writer, args []string) error {
+	switch len(args) {
+	case 0:
+		for _, name := range d.Names() {
+			c := d.lookup(name)
+			fmt.Fprintf(out, "%s - %s\n", usageLine(c), c.Help)
+		}
+		return nil
+	case 1:
+		c := d.lookup(args[0])
+		if c == nil {
+			return fmt.Errorf("unknown command %q", args[0])
+		}
+		fmt.Fprintf(out, "%s - %s\n", usageLine(c), c.Help)
+		return nil
+	default:
+		return ErrUsage
+	}
+}
@@ -0,0 +1,274 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Game is the world and player the in-game console's commands act on. Its
+// methods are called on the game loop, between frames.
+type Game interface {
+	// Position returns where the player's feet are; coordinates starting
+	// with ~ are relative to it.
+	Position() mgl32.Vec3
+	Teleport(pos mgl32.Vec3)
+	// Item returns the block or item type called name, as registered, and
+	// Block the same for types that can be placed.
+	Item(name string) (world.BlockType, bool)
+	Block(name string) (world.BlockType, bool)
+	// Give puts count of item in the player's inventory and returns how many
+	// fit.
+	Give(item world.BlockType, count int) int
+	Time() float64
+	SetTime(ticks float64)
+	// SetGameMode switches the player to "survival" or "creative".
+	SetGameMode(mode string)
+	Seed() int64
+	// Loaded reports whether the column of blocks at (x, z) is loaded;
+	// fill only changes loaded blocks.
+	Loaded(x, z int) bool
+	SetBlock(x, y, z int, block world.BlockType)
+}
+
+// maxFillBlocks is the most blocks one fill may change, as in MC.
+const maxFillBlocks = 32768
+
+// The times of day time set takes by name, in ticks into the day.
+var namedTimes = map[string]float64{
+	"day":      1000,
+	"noon":     6000,
+	"night":    13000,
+	"midnight": 18000,
+}
+
+// RegisterGame adds the commands of the in-game console: tp, give, time,
+// gamemode, seed and fill.
+func RegisterGame(d *Dispatcher, g Game) {
+	d.Register(Command{
+		Name:  "tp",
+		Usage: "<x> <y> <z>",
+		Help:  "teleports you; ~ is where you are, ~5 five blocks on",
+		Run: func(out io.Writer, args []string) error {
+			if len(args) != 3 {
+				return ErrUsage
+			}
+			pos, err := parseCoords(args, g.Position(), true)
+			if err != nil {
+				return err
+			}
+			g.Teleport(pos)
+			fmt.Fprintf(out, "Teleported to %.1f, %.1f, %.1f\n", pos.X(), pos.Y(), pos.Z())
+			return nil
+		},
+	})
+	d.Register(Command{
+		Name:  "give",
+		Usage: "<item> [count]",
+		Help:  "puts items in your inventory",
+		Run: func(out io.Writer, args []string) error {
+			if len(args) < 1 || len(args) > 2 {
+				return ErrUsage
+			}
+			item, err := lookupItem(g, args[0])
+			if err != nil {
+				return err
+			}
+			count := 1
+			if len(args) == 2 {
+				count, err = strconv.Atoi(args[1])
+				if err != nil || count < 1 || count > 64 {
+					return fmt.Errorf("count %q is not a number from 1 to 64", args[1])
+				}
+			}
+			given := g.Give(item, count)
+			if given == 0 {
+				return fmt.Errorf("no room in your inventory")
+			}
+			fmt.Fprintf(out, "Gave %d %s\n", given, args[0])
+			return nil
+		},
+	})
+	d.Register(Command{
+		Name:  "time",
+		Usage: "set <day|noon|night|midnight|ticks> | add <ticks> | query",
+		Help:  "sets or shows the time of day",
+		Run: func(out io.Writer, args []string) error {
+			if len(args) == 1 && args[0] == "query" {
+				fmt.Fprintf(out, "The time is %d\n", int(math.Mod(g.Time(), world.DayLength)))
+				return nil
+			}
+			if len(args) != 2 {
+				return ErrUsage
+			}
+			ticks, ok := namedTimes[args[1]]
+			if !ok {
+				n, err := strconv.Atoi(args[1])
+				if err != nil || n < 0 {
+					return fmt.Errorf("%q is not a time of day or a number of ticks", args[1])
+				}
+				ticks = float64(n)
+			}
+			switch args[0] {
+			case "set":
+				// The day count carries on; only the time within it changes
+				day := math.Floor(g.Time() / world.DayLength)
+				g.SetTime(day*world.DayLength + math.Mod(ticks, world.DayLength))
+			case "add":
+				g.SetTime(g.Time() + ticks)
+			default:
+				return ErrUsage
+			}
+			fmt.Fprintf(out, "Set the time to %d\n", int(math.Mod(g.Time(), world.DayLength)))
+			return nil
+		},
+	})
+	d.Register(Command{
+		Name:  "gamemode",
+		Usage: "<survival|creative>",
+		Help:  "switches your game mode",
+		Run: func(out io.Writer, args []string) error {
+			if len(args) != 1 {
+				return ErrUsage
+			}
+			mode, ok := parseGameMode(args[0])
+			if !ok {
+				return fmt.Errorf("unknown game mode %q", args[0])
+			}
+			g.SetGameMode(mode)
+			fmt.Fprintf(out, "Set your game mode to %s\n", mode)
+			return nil
+		},
+	})
+	d.Register(Command{
+		Name: "seed",
+		Help: "shows the world's seed",
+		Run: func(out io.Writer, args []string) error {
+			if len(args) != 0 {
+				return ErrUsage
+			}
+			fmt.Fprintf(out, "Seed: %d\n", g.Seed())
+			return nil
+		},
+	})
+	d.Register(Command{
+		Name:  "fill",
+		Usage: "<x1> <y1> <z1> <x2> <y2> <z2> <block>",
+		Help:  "fills the box between two corners with a block",
+		Run: func(out io.Writer, args []string) error {
+			if len(args) != 7 {
+				return ErrUsage
+			}
+			from, err := parseCoords(args[0:3], g.Position(), false)
+			if err != nil {
+				return err
+			}
+			to, err := parseCoords(args[3:6], g.Position(), false)
+			if err != nil {
+				return err
+			}
+			block, ok := g.Block(strings.TrimPrefix(args[6], "minecraft:"))
+			if !ok {
+				return fmt.Errorf("unknown block %q", args[6])
+			}
+			lo, hi := blockOf(from), blockOf(to)
+			for i := range 3 {
+				lo[i], hi[i] = min(lo[i], hi[i]), max(lo[i], hi[i])
+			}
+			lo[1], hi[1] = max(lo[1], 0), min(hi[1], world.ChunkSizeY-1)
+			if lo[1] > hi[1] {
+				return fmt.Errorf("the box is outside the world")
+			}
+			// Each side is checked first so the count cannot overflow
+			count := 1
+			for i := range 3 {
+				count *= min(hi[i]-lo[i]+1, maxFillBlocks+1)
+			}
+			if count > maxFillBlocks {
+				return fmt.Errorf("too many blocks: %d, the most is %d", count, maxFillBlocks)
+			}
+			// All or nothing, as setting blocks in unloaded terrain would
+			// leave holes where it should have been loaded
+			for x := lo[0]; x <= hi[0]; x++ {
+				for z := lo[2]; z <= hi[2]; z++ {
+					if !g.Loaded(x, z) {
+						return fmt.Errorf("the position %d %d %d is not loaded", x, lo[1], z)
+					}
+				}
+			}
+			for x := lo[0]; x <= hi[0]; x++ {
+				for y := lo[1]; y <= hi[1]; y++ {
+					for z := lo[2]; z <= hi[2]; z++ {
+						g.SetBlock(x, y, z, block)
+					}
+				}
+			}
+			fmt.Fprintf(out, "Filled %d blocks\n", count)
+			return nil
+		},
+	})
+}
+
+// parseCoords reads three coordinates, each a number or ~ followed by an
+// optional offset from the same coordinate of origin. With centre, whole
+// numbers across (x and z) mean the middle of that block, as tp takes them.
+func parseCoords(args []string, origin mgl32.Vec3, centre bool) (mgl32.Vec3, error) {
+	var pos mgl32.Vec3
+	for i, arg := range args {
+		rel, ok := strings.CutPrefix(arg, "~")
+		text := arg
+		if ok {
+			text = rel
+			if text == "" {
+				text = "0"
+			}
+		}
+		v, err := strconv.ParseFloat(text, 32)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return pos, fmt.Errorf("%q is not a coordinate", arg)
+		}
+		switch {
+		case ok:
+			v += float64(origin[i])
+		case centre && i != 1 && !strings.Contains(text, "."):
+			v += 0.5
+		}
+		pos[i] = float32(v)
+	}
+	return pos, nil
+}
+
+// blockOf returns the block holding p.
+func blockOf(p mgl32.Vec3) [3]int {
+	return [3]int{
+		int(math.Floor(float64(p.X()))),
+		int(math.Floor(float64(p.Y()))),
+		int(math.Floor(float64(p.Z()))),
+	}
+}
+
+// lookupItem finds the type called name, with or without MC's namespace.
+func lookupItem(g Game, name string) (world.BlockType, error) {
+	if t, ok := g.Item(strings.TrimPrefix(name, "minecraft:")); ok {
+		return t, nil
+	}
+	return 0, fmt.Errorf("unknown block or item %q", name)
+}
+
+// parseGameMode returns the name of the game mode arg means, taking MC's
+// numbers and first letters too.
+func parseGameMode(arg string) (string, bool) {
+	switch arg {
+	case "survival", "s", "0":
+		return "survival", true
+	case "creative", "c", "1":
+		return "creative", true
+	}
+	return "", false
+}
@@ -0,0 +1,48 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Pregen generates and saves the world ahead of play, as the -pregen flag
+// does at startup. Its methods are called on the game loop, between frames.
+type Pregen interface {
+	// StartPregen starts generating and saving the chunks within radius
+	// columns of the player and returns how many columns that is.
+	StartPregen(radius int) (columns int, err error)
+	// StopPregen cancels the running job, reporting whether one was.
+	StopPregen() bool
+}
+
+// RegisterPregen adds the pregen command.
+func RegisterPregen(d *Dispatcher, p Pregen) {
+	d.Register(Command{
+		Name:  "pregen",
+		Usage: "<radius|stop>",
+		Help:  "generates and saves the chunks around you, radius in chunks",
+		Run: func(out io.Writer, args []string) error {
+			if len(args) != 1 {
+				return ErrUsage
+			}
+			if args[0] == "stop" {
+				if !p.StopPregen() {
+					return fmt.Errorf("nothing is being pregenerated")
+				}
+				fmt.Fprintln(out, "Stopped pregenerating")
+				return nil
+			}
+			radius, err := strconv.Atoi(args[0])
+			if err != nil || radius < 1 {
+				return fmt.Errorf("radius %q is not a number of chunks", args[0])
+			}
+			columns, err := p.StartPregen(radius)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Pregenerating %d columns of chunks\n", columns)
+			return nil
+		},
+	})
+}
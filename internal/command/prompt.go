@@ -0,0 +1,149 @@
+package command
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maxPromptLength is the longest line the prompt takes, as MC's chat.
+const maxPromptLength = 100
+
+// maxHistory is how many sent lines the prompt remembers.
+const maxHistory = 100
+
+// Prompt is the line being typed into the in-game console: its text and
+// caret, the lines sent before it to recall, and tab completion of command
+// names. The zero value is an empty prompt with no history.
+type Prompt struct {
+	text  []rune
+	caret int
+
+	history []string
+	recall  int    // the history line shown; len(history) for the line being typed
+	draft   string // the line being typed, put aside while recalling
+
+	completions []string // the names Tab steps through; nil when not completing
+	completion  int
+}
+
+// Open starts a new line holding text, with the caret after it.
+func (p *Prompt) Open(text string) {
+	p.setText(text)
+	p.recall = len(p.history)
+	p.draft = ""
+}
+
+// Text returns the line typed so far.
+func (p *Prompt) Text() string {
+	return string(p.text)
+}
+
+// Caret returns how many characters are before the caret.
+func (p *Prompt) Caret() int {
+	return p.caret
+}
+
+// Insert types r at the caret. Control characters and anything past
+// maxPromptLength are dropped.
+func (p *Prompt) Insert(r rune) {
+	if unicode.IsControl(r) || len(p.text) >= maxPromptLength {
+		return
+	}
+	p.text = append(p.text, 0)
+	copy(p.text[p.caret+1:], p.text[p.caret:])
+	p.text[p.caret] = r
+	p.caret++
+	p.completions = nil
+}
+
+// Backspace deletes the character before the caret.
+func (p *Prompt) Backspace() {
+	if p.caret == 0 {
+		return
+	}
+	p.text = append(p.text[:p.caret-1], p.text[p.caret:]...)
+	p.caret--
+	p.completions = nil
+}
+
+// Delete deletes the character after the caret.
+func (p *Prompt) Delete() {
+	if p.caret == len(p.text) {
+		return
+	}
+	p.text = append(p.text[:p.caret], p.text[p.caret+1:]...)
+	p.completions = nil
+}
+
+// MoveCaret moves the caret delta characters right, or left if negative,
+// stopping at either end of the line.
+func (p *Prompt) MoveCaret(delta int) {
+	p.caret = min(max(p.caret+delta, 0), len(p.text))
+}
+
+// Recall shows the line sent step lines before the one shown, or after it
+// if step is positive. Past the newest is the line being typed.
+func (p *Prompt) Recall(step int) {
+	to := min(max(p.recall+step, 0), len(p.history))
+	if to == p.recall {
+		return
+	}
+	if p.recall == len(p.history) {
+		p.draft = p.Text()
+	}
+	p.recall = to
+	if to == len(p.history) {
+		p.setText(p.draft)
+	} else {
+		p.setText(p.history[to])
+	}
+}
+
+// Complete completes the command name being typed after the slash from the
+// commands of d. A unique match is finished with a space; with several,
+// each call shows the next.
+func (p *Prompt) Complete(d *Dispatcher) {
+	if names := p.completions; names != nil {
+		next := (p.completion + 1) % len(names)
+		p.setText("/" + names[next])
+		p.completions, p.completion = names, next
+		return
+	}
+	name, ok := strings.CutPrefix(p.Text(), "/")
+	if !ok || strings.ContainsRune(name, ' ') {
+		return
+	}
+	matches := d.Complete(name)
+	switch len(matches) {
+	case 0:
+	case 1:
+		p.setText("/" + matches[0] + " ")
+	default:
+		p.setText("/" + matches[0])
+		p.completions, p.completion = matches, 0
+	}
+}
+
+// Submit returns the line typed, without surrounding spaces, remembers it
+// and starts an empty line.
+func (p *Prompt) Submit() string {
+	line := strings.TrimSpace(p.Text())
+	if line != "" && (len(p.history) == 0 || p.history[len(p.history)-1] != line) {
+		p.history = append(p.history, line)
+		if len(p.history) > maxHistory {
+			p.history = p.history[1:]
+		}
+	}
+	p.Open("")
+	return line
+}
+
+// setText replaces the line with text, caret at the end.
+func (p *Prompt) setText(text string) {
+	p.text = []rune(text)
+	if len(p.text) > maxPromptLength {
+		p.text = p.text[:maxPromptLength]
+	}
+	p.caret = len(p.text)
+	p.completions = nil
+}
@@ -0,0 +1,93 @@
+package command
+
+import "testing"
+
+func typeText(p *Prompt, text string) {
+	for _, r := range text {
+		p.Insert(r)
+	}
+}
+
+func TestPromptEditing(t *testing.T) {
+	var p Prompt
+	p.Open("/")
+	typeText(&p, "tp 1 2 3")
+	p.MoveCaret(-2)
+	p.Backspace()
+	p.Insert('9')
+	p.MoveCaret(-100)
+	p.Delete()
+	if p.Text() != "tp 1 9 3" || p.Caret() != 0 {
+		t.Errorf("text %q, caret %d", p.Text(), p.Caret())
+	}
+	p.Insert('\t')
+	if p.Text() != "tp 1 9 3" {
+		t.Errorf("control character typed: %q", p.Text())
+	}
+
+	p.Open("")
+	for range maxPromptLength + 10 {
+		p.Insert('é')
+	}
+	if n := len([]rune(p.Text())); n != maxPromptLength {
+		t.Errorf("%d characters typed, want %d", n, maxPromptLength)
+	}
+}
+
+func TestPromptHistory(t *testing.T) {
+	var p Prompt
+	for _, line := range []string{"hello", "  /seed ", "/seed", ""} {
+		p.Open("")
+		typeText(&p, line)
+		p.Submit()
+	}
+
+	p.Open("")
+	typeText(&p, "draft")
+	p.Recall(-1)
+	if p.Text() != "/seed" {
+		t.Errorf("newest line = %q, want /seed once", p.Text())
+	}
+	p.Recall(-1)
+	p.Recall(-1)
+	if p.Text() != "hello" {
+		t.Errorf("oldest line = %q", p.Text())
+	}
+	p.Recall(5)
+	if p.Text() != "draft" {
+		t.Errorf("back past the newest = %q, want the draft", p.Text())
+	}
+}
+
+func TestPromptComplete(t *testing.T) {
+	d := NewDispatcher()
+	RegisterGame(d, newFakeGame())
+
+	var p Prompt
+	p.Open("/ti")
+	p.Complete(d)
+	if p.Text() != "/time " {
+		t.Errorf("unique completion = %q", p.Text())
+	}
+
+	p.Open("/g")
+	var seen []string
+	for range 3 {
+		p.Complete(d)
+		seen = append(seen, p.Text())
+	}
+	if seen[0] != "/gamemode" || seen[1] != "/give" || seen[2] != "/gamemode" {
+		t.Errorf("completions = %q", seen)
+	}
+	p.Insert(' ')
+	p.Complete(d)
+	if p.Text() != "/gamemode " {
+		t.Errorf("completing arguments changed the line to %q", p.Text())
+	}
+
+	p.Open("hello")
+	p.Complete(d)
+	if p.Text() != "hello" {
+		t.Errorf("completing chat changed it to %q", p.Text())
+	}
+}
@@ -0,0 +1,202 @@
+package command
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+// The remote admin interface speaks the RCON protocol of 1.8.9 servers, so
+// existing RCON clients work with it. Each packet is a little-endian int32
+// length followed by that many bytes: int32 request ID, int32 type, the
+// body and two NUL bytes.
+const (
+	rconResponse = 0
+	rconCommand  = 2
+	rconAuthOK   = 2 // the login reply reuses the command type
+	rconLogin    = 3
+
+	rconMaxRequest  = 1460 // longest packet accepted from a client
+	rconMaxResponse = 4096 // longest body sent in one packet; longer output is split
+	rconLoginWait   = 10 * time.Second
+)
+
+var errRconPacket = errors.New("malformed rcon packet")
+
+// AdminServer is the authenticated remote admin interface. Each connection
+// must log in with the password before its commands are run.
+type AdminServer struct {
+	d        *Dispatcher
+	password []byte
+	ln       net.Listener
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// ListenAdmin starts serving admin connections on addr, e.g. ":25575". An
+// empty password is refused so the interface is never left open.
+func ListenAdmin(addr, password string, d *Dispatcher) (*AdminServer, error) {
+	if password == "" {
+		return nil, errors.New("admin interface needs a password")
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &AdminServer{
+		d:        d,
+		password: []byte(password),
+		ln:       ln,
+		conns:    make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.accept()
+	slog.Info("admin interface listening", "addr", ln.Addr())
+	return s, nil
+}
+
+// Addr returns the address the interface listens on.
+func (s *AdminServer) Addr() net.Addr {
+	return s.ln.Addr()
+}
+
+// Close stops listening, drops every connection and waits for them to end.
+func (s *AdminServer) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for c := range s.conns {
+		c.Close()
+	}
+	s.mu.Unlock()
+	err := s.ln.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *AdminServer) accept() {
+	defer s.wg.Done()
+	for {
+		c, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			c.Close()
+			return
+		}
+		s.conns[c] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go s.serve(c)
+	}
+}
+
+func (s *AdminServer) serve(c net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		c.Close()
+	}()
+	r := bufio.NewReader(c)
+	addr := c.RemoteAddr().String()
+
+	// Log in
+	c.SetReadDeadline(time.Now().Add(rconLoginWait))
+	id, typ, body, err := readRconPacket(r)
+	if err != nil {
+		return
+	}
+	if typ != rconLogin || subtle.ConstantTimeCompare([]byte(body), s.password) != 1 {
+		slog.Warn("admin login failed", "addr", addr)
+		writeRconPacket(c, -1, rconAuthOK, "")
+		return
+	}
+	if err := writeRconPacket(c, id, rconAuthOK, ""); err != nil {
+		return
+	}
+	c.SetReadDeadline(time.Time{})
+	slog.Info("admin logged in", "addr", addr)
+
+	var out bytes.Buffer
+	for {
+		id, typ, body, err := readRconPacket(r)
+		if err != nil {
+			return
+		}
+		out.Reset()
+		if typ != rconCommand {
+			fmt.Fprintf(&out, "unknown request type %d", typ)
+		} else {
+			slog.Info("admin command", "addr", addr, "command", body)
+			if err := s.d.Execute(body, &out); err != nil {
+				fmt.Fprintln(&out, err)
+			}
+		}
+		if err := writeRconResponse(c, id, out.Bytes()); err != nil {
+			return
+		}
+	}
+}
+
+// readRconPacket reads one packet and returns its ID, type and body.
+func readRconPacket(r io.Reader) (id, typ int32, body string, err error) {
+	var size int32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return 0, 0, "", err
+	}
+	if size < 10 || size > rconMaxRequest {
+		return 0, 0, "", errRconPacket
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0, 0, "", err
+	}
+	if buf[size-1] != 0 || buf[size-2] != 0 {
+		return 0, 0, "", errRconPacket
+	}
+	id = int32(binary.LittleEndian.Uint32(buf[0:4]))
+	typ = int32(binary.LittleEndian.Uint32(buf[4:8]))
+	return id, typ, string(buf[8 : size-2]), nil
+}
+
+// writeRconPacket writes one packet.
+func writeRconPacket(w io.Writer, id, typ int32, body string) error {
+	buf := make([]byte, 4, 14+len(body))
+	binary.LittleEndian.PutUint32(buf, uint32(10+len(body)))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(id))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(typ))
+	buf = append(buf, body...)
+	buf = append(buf, 0, 0)
+	_, err := w.Write(buf)
+	return err
+}
+
+// writeRconResponse writes command output, split over as many packets as
+// it needs, each with the request's ID.
+func writeRconResponse(w io.Writer, id int32, out []byte) error {
+	for {
+		n := min(len(out), rconMaxResponse)
+		if err := writeRconPacket(w, id, rconResponse, string(out[:n])); err != nil {
+			return err
+		}
+		out = out[n:]
+		if len(out) == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package command
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+)
+
+func dialAdmin(t *testing.T, s *AdminServer, password string) (net.Conn, *bufio.Reader, bool) {
+	t.Helper()
+	c, err := net.Dial("tcp", s.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := bufio.NewReader(c)
+	if err := writeRconPacket(c, 7, rconLogin, password); err != nil {
+		t.Fatal(err)
+	}
+	id, _, _, err := readRconPacket(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, r, id == 7
+}
+
+func TestAdminServer(t *testing.T) {
+	if _, err := ListenAdmin("127.0.0.1:0", "", NewDispatcher()); err == nil {
+		t.Fatal("admin interface started without a password")
+	}
+
+	h := newFakeHost()
+	d := NewDispatcher()
+	RegisterAdmin(d, h)
+	s, err := ListenAdmin("127.0.0.1:0", "hunter2", d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	c, _, ok := dialAdmin(t, s, "hunter3")
+	c.Close()
+	if ok {
+		t.Fatal("login with the wrong password succeeded")
+	}
+
+	c, r, ok := dialAdmin(t, s, "hunter2")
+	defer c.Close()
+	if !ok {
+		t.Fatal("login with the right password failed")
+	}
+	if err := writeRconPacket(c, 8, rconCommand, "save-all"); err != nil {
+		t.Fatal(err)
+	}
+	id, typ, body, err := readRconPacket(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 8 || typ != rconResponse || !strings.Contains(body, "Saved") || h.saves != 1 {
+		t.Errorf("reply %d/%d %q after %d saves", id, typ, body, h.saves)
+	}
+}
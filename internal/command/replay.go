@@ -0,0 +1,82 @@
+package command
+
+import (
+	"fmt"
+	"io"
+
+	"mini-mc/internal/world"
+)
+
+// Replays records the player's movement and plays recordings back. Its
+// methods are called on the game loop, between frames.
+type Replays interface {
+	StartRecording() error
+	// StopRecording saves the recording and returns the name to play it
+	// back by and how many ticks it holds.
+	StopRecording() (name string, ticks int, err error)
+	// Play plays the recording called name, or the newest when name is
+	// "last": as a ghost walking the path, or with camera, by moving the
+	// player's view along it. It returns how many ticks it lasts.
+	Play(name string, camera bool) (ticks int, err error)
+	// StopPlayback stops the recording playing, reporting whether one was.
+	StopPlayback() bool
+}
+
+// RegisterReplay adds the record and replay commands.
+func RegisterReplay(d *Dispatcher, r Replays) {
+	d.Register(Command{
+		Name:  "record",
+		Usage: "<start|stop>",
+		Help:  "records where you go, to play back with replay",
+		Run: func(out io.Writer, args []string) error {
+			if len(args) != 1 {
+				return ErrUsage
+			}
+			switch args[0] {
+			case "start":
+				if err := r.StartRecording(); err != nil {
+					return err
+				}
+				fmt.Fprintln(out, "Recording")
+			case "stop":
+				name, ticks, err := r.StopRecording()
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "Saved recording %s, %.1f seconds\n", name, ticksToSeconds(ticks))
+			default:
+				return ErrUsage
+			}
+			return nil
+		},
+	})
+	d.Register(Command{
+		Name:  "replay",
+		Usage: "<name|last|stop> [camera]",
+		Help:  "plays a recording back as a ghost, or along your view",
+		Run: func(out io.Writer, args []string) error {
+			if len(args) == 1 && args[0] == "stop" {
+				if !r.StopPlayback() {
+					return fmt.Errorf("nothing is playing")
+				}
+				fmt.Fprintln(out, "Stopped the replay")
+				return nil
+			}
+			camera := len(args) == 2 && args[1] == "camera"
+			if len(args) == 0 || len(args) > 2 || len(args) == 2 && !camera {
+				return ErrUsage
+			}
+			ticks, err := r.Play(args[0], camera)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Replaying %s, %.1f seconds\n", args[0], ticksToSeconds(ticks))
+			return nil
+		},
+	})
+}
+
+// ticksToSeconds returns how long ticks game ticks take.
+func ticksToSeconds(ticks int) float64 {
+	return float64(ticks) / world.TicksPerSecond
+}
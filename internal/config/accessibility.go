@@ -0,0 +1,185 @@
+package config
+
+import "sync"
+
+// HUD text scale bounds
+const (
+	MinHUDTextScale = 0.5
+	MaxHUDTextScale = 2.0
+)
+
+// ColorblindMode selects a palette for colour-coded HUD elements and block tints
+type ColorblindMode int
+
+const (
+	ColorblindOff ColorblindMode = iota
+	ColorblindDeuteranopia
+	ColorblindProtanopia
+	ColorblindTritanopia
+	ColorblindModeCount
+)
+
+// String returns the display name of the mode
+func (m ColorblindMode) String() string {
+	switch m {
+	case ColorblindDeuteranopia:
+		return "Deuteranopia"
+	case ColorblindProtanopia:
+		return "Protanopia"
+	case ColorblindTritanopia:
+		return "Tritanopia"
+	default:
+		return "Off"
+	}
+}
+
+// AccessibilitySettings holds accessibility-related options
+type AccessibilitySettings struct {
+	mu              sync.RWMutex
+	lowHealthEffect bool    // pulsing red border + FOV pulse at low health
+	reducedMotion   bool    // suppress view bobbing and camera/FOV shake
+	highContrast    bool    // high-contrast block highlight and crosshair
+	hudTextScale    float32 // multiplier for HUD text, independent of GUI scale
+	disableFlashing bool    // replace pulsing/flashing effects with steady ones
+
+	colorblindMode    ColorblindMode
+	colorblindFoliage bool // also remap grass/foliage tints in the block shader
+}
+
+var globalAccessibilitySettings = &AccessibilitySettings{
+	lowHealthEffect: true,
+	reducedMotion:   false,
+	highContrast:    false,
+	hudTextScale:    1.0,
+	disableFlashing: false,
+
+	colorblindMode:    ColorblindOff,
+	colorblindFoliage: true,
+}
+
+// GetLowHealthEffect returns whether the low-health heartbeat effect is enabled
+func GetLowHealthEffect() bool {
+	globalAccessibilitySettings.mu.RLock()
+	defer globalAccessibilitySettings.mu.RUnlock()
+	return globalAccessibilitySettings.lowHealthEffect
+}
+
+// SetLowHealthEffect enables or disables the low-health heartbeat effect
+func SetLowHealthEffect(enabled bool) {
+	globalAccessibilitySettings.mu.Lock()
+	defer globalAccessibilitySettings.mu.Unlock()
+	globalAccessibilitySettings.lowHealthEffect = enabled
+}
+
+// GetReducedMotion returns whether reduced motion is enabled
+func GetReducedMotion() bool {
+	globalAccessibilitySettings.mu.RLock()
+	defer globalAccessibilitySettings.mu.RUnlock()
+	return globalAccessibilitySettings.reducedMotion
+}
+
+// SetReducedMotion enables or disables reduced motion
+func SetReducedMotion(enabled bool) {
+	globalAccessibilitySettings.mu.Lock()
+	defer globalAccessibilitySettings.mu.Unlock()
+	globalAccessibilitySettings.reducedMotion = enabled
+}
+
+// IsViewBobbingActive reports whether view bobbing should be applied: it must be
+// enabled and not overridden by reduced motion.
+func IsViewBobbingActive() bool {
+	return GetViewBobbing() && !GetReducedMotion()
+}
+
+// IsFoliageWavingActive reports whether foliage should sway: it must be enabled
+// and not overridden by reduced motion.
+func IsFoliageWavingActive() bool {
+	return GetFoliageWaving() && !GetReducedMotion()
+}
+
+// GetHighContrast returns whether high-contrast highlight and crosshair are enabled
+func GetHighContrast() bool {
+	globalAccessibilitySettings.mu.RLock()
+	defer globalAccessibilitySettings.mu.RUnlock()
+	return globalAccessibilitySettings.highContrast
+}
+
+// SetHighContrast enables or disables high-contrast highlight and crosshair
+func SetHighContrast(enabled bool) {
+	globalAccessibilitySettings.mu.Lock()
+	defer globalAccessibilitySettings.mu.Unlock()
+	globalAccessibilitySettings.highContrast = enabled
+}
+
+// GetHUDTextScale returns the HUD text scale multiplier
+func GetHUDTextScale() float32 {
+	globalAccessibilitySettings.mu.RLock()
+	defer globalAccessibilitySettings.mu.RUnlock()
+	return globalAccessibilitySettings.hudTextScale
+}
+
+// SetHUDTextScale sets the HUD text scale multiplier
+func SetHUDTextScale(scale float32) {
+	globalAccessibilitySettings.mu.Lock()
+	defer globalAccessibilitySettings.mu.Unlock()
+	if scale < MinHUDTextScale {
+		scale = MinHUDTextScale
+	}
+	if scale > MaxHUDTextScale {
+		scale = MaxHUDTextScale
+	}
+	globalAccessibilitySettings.hudTextScale = scale
+}
+
+// GetDisableFlashing returns whether flashing effects are disabled
+func GetDisableFlashing() bool {
+	globalAccessibilitySettings.mu.RLock()
+	defer globalAccessibilitySettings.mu.RUnlock()
+	return globalAccessibilitySettings.disableFlashing
+}
+
+// SetDisableFlashing enables or disables flashing effects
+func SetDisableFlashing(disabled bool) {
+	globalAccessibilitySettings.mu.Lock()
+	defer globalAccessibilitySettings.mu.Unlock()
+	globalAccessibilitySettings.disableFlashing = disabled
+}
+
+// GetColorblindMode returns the active colorblind palette
+func GetColorblindMode() ColorblindMode {
+	globalAccessibilitySettings.mu.RLock()
+	defer globalAccessibilitySettings.mu.RUnlock()
+	return globalAccessibilitySettings.colorblindMode
+}
+
+// SetColorblindMode sets the active colorblind palette
+func SetColorblindMode(mode ColorblindMode) {
+	globalAccessibilitySettings.mu.Lock()
+	defer globalAccessibilitySettings.mu.Unlock()
+	if mode < ColorblindOff || mode >= ColorblindModeCount {
+		mode = ColorblindOff
+	}
+	globalAccessibilitySettings.colorblindMode = mode
+}
+
+// CycleColorblindMode advances to the next colorblind palette, wrapping to Off
+func CycleColorblindMode() ColorblindMode {
+	globalAccessibilitySettings.mu.Lock()
+	defer globalAccessibilitySettings.mu.Unlock()
+	globalAccessibilitySettings.colorblindMode = (globalAccessibilitySettings.colorblindMode + 1) % ColorblindModeCount
+	return globalAccessibilitySettings.colorblindMode
+}
+
+// GetColorblindFoliage returns whether grass/foliage tints follow the colorblind palette
+func GetColorblindFoliage() bool {
+	globalAccessibilitySettings.mu.RLock()
+	defer globalAccessibilitySettings.mu.RUnlock()
+	return globalAccessibilitySettings.colorblindFoliage
+}
+
+// SetColorblindFoliage sets whether grass/foliage tints follow the colorblind palette
+func SetColorblindFoliage(enabled bool) {
+	globalAccessibilitySettings.mu.Lock()
+	defer globalAccessibilitySettings.mu.Unlock()
+	globalAccessibilitySettings.colorblindFoliage = enabled
+}
@@ -0,0 +1,119 @@
+package config
+
+import "sync"
+
+// AudioBus is a group of sounds that share a volume. Every sound also goes
+// through the master bus.
+type AudioBus int
+
+const (
+	AudioBusMaster AudioBus = iota
+	AudioBusMusic
+	AudioBusBlocks
+	AudioBusEntities
+	AudioBusUI
+	AudioBusCount
+)
+
+// String returns the display name of the bus
+func (b AudioBus) String() string {
+	switch b {
+	case AudioBusMusic:
+		return "Music"
+	case AudioBusBlocks:
+		return "Blocks"
+	case AudioBusEntities:
+		return "Entities"
+	case AudioBusUI:
+		return "UI"
+	default:
+		return "Master"
+	}
+}
+
+// optionKey is the key the bus's volume is saved under, after 1.8.9's
+// sound categories
+func (b AudioBus) optionKey() string {
+	switch b {
+	case AudioBusMusic:
+		return "soundCategory_music"
+	case AudioBusBlocks:
+		return "soundCategory_block"
+	case AudioBusEntities:
+		return "soundCategory_neutral"
+	case AudioBusUI:
+		return "soundCategory_ui"
+	default:
+		return "soundCategory_master"
+	}
+}
+
+// IsWorld reports whether the bus carries sounds from the world, which are
+// ducked under menus and muted while paused.
+func (b AudioBus) IsWorld() bool {
+	return b == AudioBusBlocks || b == AudioBusEntities
+}
+
+// AudioSettings holds the mixer's volumes and behaviour
+type AudioSettings struct {
+	mu             sync.RWMutex
+	volumes        [AudioBusCount]float32 // 0-1
+	duckInMenus    bool                   // lower world sounds while a menu or inventory is open
+	muteWhenPaused bool                   // silence world sounds and music while paused
+}
+
+var globalAudioSettings = &AudioSettings{
+	volumes:        [AudioBusCount]float32{1, 1, 1, 1, 1},
+	duckInMenus:    true,
+	muteWhenPaused: false,
+}
+
+// GetBusVolume returns the volume of a bus, 0-1
+func GetBusVolume(b AudioBus) float32 {
+	if b < 0 || b >= AudioBusCount {
+		return 0
+	}
+	globalAudioSettings.mu.RLock()
+	defer globalAudioSettings.mu.RUnlock()
+	return globalAudioSettings.volumes[b]
+}
+
+// SetBusVolume sets the volume of a bus, clamped to 0-1
+func SetBusVolume(b AudioBus, volume float32) {
+	if b < 0 || b >= AudioBusCount {
+		return
+	}
+	globalAudioSettings.mu.Lock()
+	defer globalAudioSettings.mu.Unlock()
+	globalAudioSettings.volumes[b] = min(max(volume, 0), 1)
+}
+
+// GetDuckInMenus returns whether world sounds are lowered while menus are open
+func GetDuckInMenus() bool {
+	globalAudioSettings.mu.RLock()
+	defer globalAudioSettings.mu.RUnlock()
+	return globalAudioSettings.duckInMenus
+}
+
+// SetDuckInMenus sets whether world sounds are lowered while menus are open
+func SetDuckInMenus(enabled bool) {
+	globalAudioSettings.mu.Lock()
+	defer globalAudioSettings.mu.Unlock()
+	globalAudioSettings.duckInMenus = enabled
+}
+
+// GetMuteWhenPaused returns whether world sounds and music are silenced
+// while the game is paused
+func GetMuteWhenPaused() bool {
+	globalAudioSettings.mu.RLock()
+	defer globalAudioSettings.mu.RUnlock()
+	return globalAudioSettings.muteWhenPaused
+}
+
+// SetMuteWhenPaused sets whether world sounds and music are silenced while
+// the game is paused
+func SetMuteWhenPaused(enabled bool) {
+	globalAudioSettings.mu.Lock()
+	defer globalAudioSettings.mu.Unlock()
+	globalAudioSettings.muteWhenPaused = enabled
+}
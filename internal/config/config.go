@@ -2,6 +2,43 @@ package config
 
 import "sync"
 
+// Range of the field of view, in degrees measured vertically
+const (
+	MinFOV     = 30
+	MaxFOV     = 110
+	DefaultFOV = 60
+)
+
+// Ranges of the entity simulation and render distances, in chunks
+const (
+	MinEntitySimulationDistance = 2
+	MaxEntitySimulationDistance = 32
+	MinEntityRenderDistance     = 1
+	MaxEntityRenderDistance     = 32
+)
+
+// ShadowQuality is how the sun's shadows are drawn
+type ShadowQuality int
+
+const (
+	ShadowsOff ShadowQuality = iota
+	ShadowsLow
+	ShadowsHigh
+	ShadowQualityCount
+)
+
+// String returns the display name of the quality
+func (q ShadowQuality) String() string {
+	switch q {
+	case ShadowsLow:
+		return "Low"
+	case ShadowsHigh:
+		return "High"
+	default:
+		return "Off"
+	}
+}
+
 // RenderSettings holds render configuration
 type RenderSettings struct {
 	mu             sync.RWMutex
@@ -9,6 +46,29 @@ type RenderSettings struct {
 	fpsLimit       int  // 0 means uncapped, otherwise target FPS
 	wireframeMode  bool // wireframe rendering mode
 	viewBobbing    bool // view bobbing animation
+	fpsInTitle     bool // append the frame rate to the window title
+	fov            int  // vertical field of view in degrees, before sprinting widens it
+	fullscreen     bool // fill the primary monitor rather than a window
+
+	textureVariation bool // rotate and tint natural block textures per position
+	foliageWaving    bool // sway leaves in the wind
+	ambientOcclusion bool // darken block corners next to other blocks
+	fancyWater       bool // planar reflection and refraction on water
+	sunShafts        bool // light scattering from the sun over the sky
+	shadows          ShadowQuality
+
+	packedColumnCulling bool // experimental flat-array column culling path
+	occlusionCulling    bool // skip columns hidden behind nearer terrain
+	lodDistance         int  // in chunks; columns beyond it use coarser meshes, 0 for off
+	lodDebugTint        bool // tint columns by the level of detail they are drawn at
+
+	entitySimulationDistance int // in chunks; entities beyond it tick less often
+	entityRenderDistance     int // in chunks; entities beyond it are not drawn
+
+	meshMemoryCapMB       int  // cap on block mesh memory, GPU atlas plus CPU copies
+	keepEvictedMeshCopies bool // keep the CPU copy of meshes evicted over the cap
+
+	onRenderDistance func(old, new int) // see OnRenderDistanceChange
 }
 
 var globalRenderSettings = &RenderSettings{
@@ -16,6 +76,21 @@ var globalRenderSettings = &RenderSettings{
 	fpsLimit:       180, // default FPS cap
 	wireframeMode:  false,
 	viewBobbing:    true, // default enabled
+	fov:            DefaultFOV,
+
+	textureVariation: true,
+	foliageWaving:    true,
+	ambientOcclusion: true,
+	sunShafts:        true,
+	shadows:          ShadowsLow,
+	occlusionCulling: true,
+	lodDistance:      16,
+
+	entitySimulationDistance: 8,
+	entityRenderDistance:     4,
+
+	meshMemoryCapMB:       2048,
+	keepEvictedMeshCopies: true,
 }
 
 // GetRenderDistance returns the current render distance in chunks
@@ -25,10 +100,10 @@ func GetRenderDistance() int {
 	return globalRenderSettings.renderDistance
 }
 
-// SetRenderDistance sets the render distance in chunks
+// SetRenderDistance sets the render distance in chunks, telling the hook set
+// with OnRenderDistanceChange when it changes
 func SetRenderDistance(distance int) {
 	globalRenderSettings.mu.Lock()
-	defer globalRenderSettings.mu.Unlock()
 
 	// Clamp to reasonable values
 	if distance < 5 {
@@ -38,7 +113,23 @@ func SetRenderDistance(distance int) {
 		distance = 50
 	}
 
+	old := globalRenderSettings.renderDistance
 	globalRenderSettings.renderDistance = distance
+	hook := globalRenderSettings.onRenderDistance
+	globalRenderSettings.mu.Unlock()
+
+	if hook != nil && distance != old {
+		hook(old, distance)
+	}
+}
+
+// OnRenderDistanceChange sets the function called with the old and new
+// distance whenever SetRenderDistance changes it, replacing any before; nil
+// removes it. It runs on the goroutine that set the distance.
+func OnRenderDistanceChange(fn func(old, new int)) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.onRenderDistance = fn
 }
 
 // GetFPSLimit returns the configured FPS cap (0 means uncapped)
@@ -61,6 +152,34 @@ func SetFPSLimit(limit int) {
 	globalRenderSettings.fpsLimit = limit
 }
 
+// GetFOV returns the vertical field of view in degrees
+func GetFOV() int {
+	globalRenderSettings.mu.RLock()
+	defer globalRenderSettings.mu.RUnlock()
+	return globalRenderSettings.fov
+}
+
+// SetFOV sets the vertical field of view in degrees
+func SetFOV(fov int) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.fov = max(MinFOV, min(fov, MaxFOV))
+}
+
+// GetFullscreen returns whether the game fills the primary monitor
+func GetFullscreen() bool {
+	globalRenderSettings.mu.RLock()
+	defer globalRenderSettings.mu.RUnlock()
+	return globalRenderSettings.fullscreen
+}
+
+// SetFullscreen sets whether the game fills the primary monitor
+func SetFullscreen(enabled bool) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.fullscreen = enabled
+}
+
 // GetChunkLoadRadius returns radius for chunk loading (slightly larger than render distance)
 func GetChunkLoadRadius() int {
 	return GetRenderDistance()
@@ -77,6 +196,87 @@ func GetMaxRenderRadius() int {
 	return rd
 }
 
+// GetEntitySimulationDistance returns the distance in chunks within which
+// entities tick every frame
+func GetEntitySimulationDistance() int {
+	globalRenderSettings.mu.RLock()
+	defer globalRenderSettings.mu.RUnlock()
+	return globalRenderSettings.entitySimulationDistance
+}
+
+// SetEntitySimulationDistance sets the entity simulation distance in chunks
+func SetEntitySimulationDistance(distance int) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.entitySimulationDistance = max(MinEntitySimulationDistance, min(distance, MaxEntitySimulationDistance))
+}
+
+// GetEntityRenderDistance returns the distance in chunks within which
+// entities are drawn
+func GetEntityRenderDistance() int {
+	globalRenderSettings.mu.RLock()
+	defer globalRenderSettings.mu.RUnlock()
+	return globalRenderSettings.entityRenderDistance
+}
+
+// SetEntityRenderDistance sets the entity render distance in chunks
+func SetEntityRenderDistance(distance int) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.entityRenderDistance = max(MinEntityRenderDistance, min(distance, MaxEntityRenderDistance))
+}
+
+// GetLODDistance returns the distance in chunks beyond which block columns
+// are drawn from level-of-detail meshes, or 0 when they are off
+func GetLODDistance() int {
+	globalRenderSettings.mu.RLock()
+	defer globalRenderSettings.mu.RUnlock()
+	return globalRenderSettings.lodDistance
+}
+
+// SetLODDistance sets the LOD distance in chunks; columns are drawn at half
+// detail beyond it and at quarter detail beyond twice it. 0 turns LOD off.
+func SetLODDistance(distance int) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	if distance <= 0 {
+		globalRenderSettings.lodDistance = 0
+		return
+	}
+	globalRenderSettings.lodDistance = max(4, min(distance, 64))
+}
+
+// GetMeshMemoryCapMB returns the cap on block mesh memory in megabytes
+func GetMeshMemoryCapMB() int {
+	globalRenderSettings.mu.RLock()
+	defer globalRenderSettings.mu.RUnlock()
+	return globalRenderSettings.meshMemoryCapMB
+}
+
+// SetMeshMemoryCapMB sets the cap on block mesh memory in megabytes. Past it
+// the meshes farthest from the player are evicted.
+func SetMeshMemoryCapMB(mb int) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.meshMemoryCapMB = max(128, min(mb, 16384))
+}
+
+// GetKeepEvictedMeshCopies returns whether meshes evicted over the memory cap
+// keep their CPU copy
+func GetKeepEvictedMeshCopies() bool {
+	globalRenderSettings.mu.RLock()
+	defer globalRenderSettings.mu.RUnlock()
+	return globalRenderSettings.keepEvictedMeshCopies
+}
+
+// SetKeepEvictedMeshCopies sets whether meshes evicted over the memory cap
+// keep their CPU copy, so they return without remeshing at the cost of memory
+func SetKeepEvictedMeshCopies(keep bool) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.keepEvictedMeshCopies = keep
+}
+
 // GetWireframeMode returns whether wireframe mode is enabled
 func GetWireframeMode() bool {
 	globalRenderSettings.mu.RLock()
@@ -98,6 +298,72 @@ func ToggleWireframeMode() {
 	globalRenderSettings.wireframeMode = !globalRenderSettings.wireframeMode
 }
 
+// GetPackedColumnCulling returns whether the experimental packed column
+// culling path is used instead of the per-chunk culling loop
+func GetPackedColumnCulling() bool {
+	globalRenderSettings.mu.RLock()
+	defer globalRenderSettings.mu.RUnlock()
+	return globalRenderSettings.packedColumnCulling
+}
+
+// SetPackedColumnCulling sets the packed column culling setting
+func SetPackedColumnCulling(enabled bool) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.packedColumnCulling = enabled
+}
+
+// TogglePackedColumnCulling toggles packed column culling
+func TogglePackedColumnCulling() {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.packedColumnCulling = !globalRenderSettings.packedColumnCulling
+}
+
+// GetOcclusionCulling returns whether block columns hidden behind nearer
+// terrain are left out of the frame
+func GetOcclusionCulling() bool {
+	globalRenderSettings.mu.RLock()
+	defer globalRenderSettings.mu.RUnlock()
+	return globalRenderSettings.occlusionCulling
+}
+
+// SetOcclusionCulling sets the occlusion culling setting
+func SetOcclusionCulling(enabled bool) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.occlusionCulling = enabled
+}
+
+// ToggleOcclusionCulling toggles occlusion culling
+func ToggleOcclusionCulling() {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.occlusionCulling = !globalRenderSettings.occlusionCulling
+}
+
+// GetLODDebugTint returns whether block columns are tinted by the level of
+// detail they are drawn at
+func GetLODDebugTint() bool {
+	globalRenderSettings.mu.RLock()
+	defer globalRenderSettings.mu.RUnlock()
+	return globalRenderSettings.lodDebugTint
+}
+
+// SetLODDebugTint sets the LOD debug tint setting
+func SetLODDebugTint(enabled bool) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.lodDebugTint = enabled
+}
+
+// ToggleLODDebugTint toggles the LOD debug tint
+func ToggleLODDebugTint() {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.lodDebugTint = !globalRenderSettings.lodDebugTint
+}
+
 // GetViewBobbing returns whether view bobbing is enabled
 func GetViewBobbing() bool {
 	globalRenderSettings.mu.RLock()
@@ -112,6 +378,119 @@ func SetViewBobbing(enabled bool) {
 	globalRenderSettings.viewBobbing = enabled
 }
 
+// GetFPSInTitle returns whether the window title shows the frame rate
+func GetFPSInTitle() bool {
+	globalRenderSettings.mu.RLock()
+	defer globalRenderSettings.mu.RUnlock()
+	return globalRenderSettings.fpsInTitle
+}
+
+// SetFPSInTitle sets whether the window title shows the frame rate
+func SetFPSInTitle(enabled bool) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.fpsInTitle = enabled
+}
+
+// GetTextureVariation returns whether natural block textures vary per position
+func GetTextureVariation() bool {
+	globalRenderSettings.mu.RLock()
+	defer globalRenderSettings.mu.RUnlock()
+	return globalRenderSettings.textureVariation
+}
+
+// SetTextureVariation sets whether natural block textures vary per position
+func SetTextureVariation(enabled bool) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.textureVariation = enabled
+}
+
+// GetFoliageWaving returns whether foliage sways in the wind
+func GetFoliageWaving() bool {
+	globalRenderSettings.mu.RLock()
+	defer globalRenderSettings.mu.RUnlock()
+	return globalRenderSettings.foliageWaving
+}
+
+// SetFoliageWaving sets whether foliage sways in the wind
+func SetFoliageWaving(enabled bool) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.foliageWaving = enabled
+}
+
+// GetAmbientOcclusion returns whether block corners are darkened by the
+// blocks around them
+func GetAmbientOcclusion() bool {
+	globalRenderSettings.mu.RLock()
+	defer globalRenderSettings.mu.RUnlock()
+	return globalRenderSettings.ambientOcclusion
+}
+
+// SetAmbientOcclusion sets whether block corners are darkened by the blocks
+// around them
+func SetAmbientOcclusion(enabled bool) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.ambientOcclusion = enabled
+}
+
+// GetFancyWater returns whether water is drawn with planar reflection and
+// refraction
+func GetFancyWater() bool {
+	globalRenderSettings.mu.RLock()
+	defer globalRenderSettings.mu.RUnlock()
+	return globalRenderSettings.fancyWater
+}
+
+// SetFancyWater sets whether water is drawn with planar reflection and
+// refraction, which renders the world a second time each frame
+func SetFancyWater(enabled bool) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.fancyWater = enabled
+}
+
+// GetSunShafts returns whether shafts of sunlight are drawn around the sun
+func GetSunShafts() bool {
+	globalRenderSettings.mu.RLock()
+	defer globalRenderSettings.mu.RUnlock()
+	return globalRenderSettings.sunShafts
+}
+
+// SetSunShafts sets whether shafts of sunlight are drawn around the sun
+func SetSunShafts(enabled bool) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.sunShafts = enabled
+}
+
+// GetShadowQuality returns how the sun's shadows are drawn
+func GetShadowQuality() ShadowQuality {
+	globalRenderSettings.mu.RLock()
+	defer globalRenderSettings.mu.RUnlock()
+	return globalRenderSettings.shadows
+}
+
+// SetShadowQuality sets how the sun's shadows are drawn
+func SetShadowQuality(q ShadowQuality) {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	if q < ShadowsOff || q >= ShadowQualityCount {
+		q = ShadowsOff
+	}
+	globalRenderSettings.shadows = q
+}
+
+// CycleShadowQuality advances to the next shadow quality, wrapping to Off
+func CycleShadowQuality() ShadowQuality {
+	globalRenderSettings.mu.Lock()
+	defer globalRenderSettings.mu.Unlock()
+	globalRenderSettings.shadows = (globalRenderSettings.shadows + 1) % ShadowQualityCount
+	return globalRenderSettings.shadows
+}
+
 // ToggleViewBobbing toggles view bobbing
 func ToggleViewBobbing() {
 	globalRenderSettings.mu.Lock()
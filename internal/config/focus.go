@@ -0,0 +1,66 @@
+package config
+
+import "sync"
+
+// FocusSettings holds what the game does while its window is in the
+// background
+type FocusSettings struct {
+	mu               sync.RWMutex
+	pauseOnLostFocus bool // open the pause menu when the window loses focus
+	resumeOnFocus    bool // close a pause menu opened by losing focus once it returns
+	minimizedFPS     int  // frame rate cap while minimized; 0 keeps the normal cap
+}
+
+var globalFocusSettings = &FocusSettings{
+	pauseOnLostFocus: true,
+	resumeOnFocus:    false,
+	minimizedFPS:     10,
+}
+
+// GetPauseOnLostFocus returns whether the game pauses when the window loses
+// focus
+func GetPauseOnLostFocus() bool {
+	globalFocusSettings.mu.RLock()
+	defer globalFocusSettings.mu.RUnlock()
+	return globalFocusSettings.pauseOnLostFocus
+}
+
+// SetPauseOnLostFocus sets whether the game pauses when the window loses
+// focus
+func SetPauseOnLostFocus(enabled bool) {
+	globalFocusSettings.mu.Lock()
+	defer globalFocusSettings.mu.Unlock()
+	globalFocusSettings.pauseOnLostFocus = enabled
+}
+
+// GetResumeOnFocus returns whether a pause caused by losing focus ends when
+// the window gets it back
+func GetResumeOnFocus() bool {
+	globalFocusSettings.mu.RLock()
+	defer globalFocusSettings.mu.RUnlock()
+	return globalFocusSettings.resumeOnFocus
+}
+
+// SetResumeOnFocus sets whether a pause caused by losing focus ends when the
+// window gets it back
+func SetResumeOnFocus(enabled bool) {
+	globalFocusSettings.mu.Lock()
+	defer globalFocusSettings.mu.Unlock()
+	globalFocusSettings.resumeOnFocus = enabled
+}
+
+// GetMinimizedFPS returns the frame rate cap while the window is minimized
+// (0 means the normal cap applies)
+func GetMinimizedFPS() int {
+	globalFocusSettings.mu.RLock()
+	defer globalFocusSettings.mu.RUnlock()
+	return globalFocusSettings.minimizedFPS
+}
+
+// SetMinimizedFPS sets the frame rate cap while the window is minimized; 0
+// keeps the normal cap
+func SetMinimizedFPS(limit int) {
+	globalFocusSettings.mu.Lock()
+	defer globalFocusSettings.mu.Unlock()
+	globalFocusSettings.minimizedFPS = max(0, min(limit, 60))
+}
@@ -0,0 +1,160 @@
+package config
+
+import "sync"
+
+// HUDWidget is one of the small info readouts the HUD can show in a corner
+// of the screen
+type HUDWidget int
+
+const (
+	WidgetCoordinates HUDWidget = iota
+	WidgetFacing
+	WidgetClock
+	WidgetFPS
+	HUDWidgetCount
+)
+
+// String returns the display name of the widget
+func (w HUDWidget) String() string {
+	switch w {
+	case WidgetCoordinates:
+		return "Coordinates"
+	case WidgetFacing:
+		return "Facing"
+	case WidgetClock:
+		return "Clock"
+	default:
+		return "FPS"
+	}
+}
+
+// optionKey is the key whether the widget is shown is saved under; its
+// corner is saved under the key with "Corner" added
+func (w HUDWidget) optionKey() string {
+	switch w {
+	case WidgetCoordinates:
+		return "hudCoordinates"
+	case WidgetFacing:
+		return "hudFacing"
+	case WidgetClock:
+		return "hudClock"
+	default:
+		return "hudFps"
+	}
+}
+
+// HUDCorner is a corner of the screen HUD widgets stack in
+type HUDCorner int
+
+const (
+	CornerTopLeft HUDCorner = iota
+	CornerTopRight
+	CornerBottomLeft
+	CornerBottomRight
+	HUDCornerCount
+)
+
+// String returns the display name of the corner
+func (c HUDCorner) String() string {
+	switch c {
+	case CornerTopRight:
+		return "Top Right"
+	case CornerBottomLeft:
+		return "Bottom Left"
+	case CornerBottomRight:
+		return "Bottom Right"
+	default:
+		return "Top Left"
+	}
+}
+
+// Right reports whether the corner is on the right of the screen
+func (c HUDCorner) Right() bool {
+	return c == CornerTopRight || c == CornerBottomRight
+}
+
+// Bottom reports whether the corner is at the bottom of the screen
+func (c HUDCorner) Bottom() bool {
+	return c == CornerBottomLeft || c == CornerBottomRight
+}
+
+// HUDSettings holds which HUD widgets are shown and where
+type HUDSettings struct {
+	mu      sync.RWMutex
+	shown   [HUDWidgetCount]bool
+	corners [HUDWidgetCount]HUDCorner
+}
+
+var globalHUDSettings = &HUDSettings{
+	shown: [HUDWidgetCount]bool{true, true, true, true},
+}
+
+// GetHUDWidgetShown returns whether a HUD widget is shown
+func GetHUDWidgetShown(w HUDWidget) bool {
+	if w < 0 || w >= HUDWidgetCount {
+		return false
+	}
+	globalHUDSettings.mu.RLock()
+	defer globalHUDSettings.mu.RUnlock()
+	return globalHUDSettings.shown[w]
+}
+
+// SetHUDWidgetShown sets whether a HUD widget is shown
+func SetHUDWidgetShown(w HUDWidget, shown bool) {
+	if w < 0 || w >= HUDWidgetCount {
+		return
+	}
+	globalHUDSettings.mu.Lock()
+	defer globalHUDSettings.mu.Unlock()
+	globalHUDSettings.shown[w] = shown
+}
+
+// GetHUDWidgetCorner returns the corner a HUD widget is shown in
+func GetHUDWidgetCorner(w HUDWidget) HUDCorner {
+	if w < 0 || w >= HUDWidgetCount {
+		return CornerTopLeft
+	}
+	globalHUDSettings.mu.RLock()
+	defer globalHUDSettings.mu.RUnlock()
+	return globalHUDSettings.corners[w]
+}
+
+// SetHUDWidgetCorner sets the corner a HUD widget is shown in
+func SetHUDWidgetCorner(w HUDWidget, c HUDCorner) {
+	if w < 0 || w >= HUDWidgetCount {
+		return
+	}
+	if c < 0 || c >= HUDCornerCount {
+		c = CornerTopLeft
+	}
+	globalHUDSettings.mu.Lock()
+	defer globalHUDSettings.mu.Unlock()
+	globalHUDSettings.corners[w] = c
+}
+
+// CycleHUDWidgetCorner moves a HUD widget to the next corner, clockwise
+func CycleHUDWidgetCorner(w HUDWidget) {
+	clockwise := [HUDCornerCount]HUDCorner{
+		CornerTopLeft:     CornerTopRight,
+		CornerTopRight:    CornerBottomRight,
+		CornerBottomRight: CornerBottomLeft,
+		CornerBottomLeft:  CornerTopLeft,
+	}
+	SetHUDWidgetCorner(w, clockwise[GetHUDWidgetCorner(w)])
+}
+
+// hudWidgetOptions returns the options for whether each HUD widget is shown
+// and its corner.
+func hudWidgetOptions() []option {
+	opts := make([]option, 0, 2*HUDWidgetCount)
+	for w := range HUDWidgetCount {
+		opts = append(opts,
+			boolOption(w.optionKey(),
+				func() bool { return GetHUDWidgetShown(w) },
+				func(shown bool) { SetHUDWidgetShown(w, shown) }),
+			intOption(w.optionKey()+"Corner",
+				func() int { return int(GetHUDWidgetCorner(w)) },
+				func(c int) { SetHUDWidgetCorner(w, HUDCorner(c)) }))
+	}
+	return opts
+}
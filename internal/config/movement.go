@@ -0,0 +1,116 @@
+package config
+
+import "sync"
+
+// MaxJumpAssistTicks is the upper bound for coyote time and jump buffering.
+const MaxJumpAssistTicks = 10
+
+// Range of the mouse sensitivity, in degrees turned per pixel moved
+const (
+	MinMouseSensitivity     = 0.02
+	MaxMouseSensitivity     = 0.4
+	DefaultMouseSensitivity = 0.1
+)
+
+// SprintMode is how the sprint key works
+type SprintMode int
+
+const (
+	SprintHold   SprintMode = iota // sprint while the key is held
+	SprintToggle                   // a press switches sprinting on or off
+)
+
+// String returns the display name of the mode
+func (m SprintMode) String() string {
+	if m == SprintToggle {
+		return "Toggle"
+	}
+	return "Hold"
+}
+
+// MovementSettings holds player movement and control tuning options
+type MovementSettings struct {
+	mu               sync.RWMutex
+	coyoteTicks      int        // ticks after leaving a ledge during which a jump is still allowed
+	jumpBufferTicks  int        // ticks a jump press is remembered before landing
+	sprintMode       SprintMode // hold or toggle the sprint key
+	mouseSensitivity float32    // degrees the camera turns per pixel of mouse movement
+}
+
+var globalMovementSettings = &MovementSettings{
+	coyoteTicks:      2,
+	jumpBufferTicks:  2,
+	sprintMode:       SprintHold,
+	mouseSensitivity: DefaultMouseSensitivity,
+}
+
+func clampJumpAssistTicks(ticks int) int {
+	if ticks < 0 {
+		return 0
+	}
+	if ticks > MaxJumpAssistTicks {
+		return MaxJumpAssistTicks
+	}
+	return ticks
+}
+
+// GetCoyoteTicks returns the coyote time in game ticks (0 disables it)
+func GetCoyoteTicks() int {
+	globalMovementSettings.mu.RLock()
+	defer globalMovementSettings.mu.RUnlock()
+	return globalMovementSettings.coyoteTicks
+}
+
+// SetCoyoteTicks sets the coyote time in game ticks (0 disables it)
+func SetCoyoteTicks(ticks int) {
+	globalMovementSettings.mu.Lock()
+	defer globalMovementSettings.mu.Unlock()
+	globalMovementSettings.coyoteTicks = clampJumpAssistTicks(ticks)
+}
+
+// GetJumpBufferTicks returns the jump buffer window in game ticks (0 disables it)
+func GetJumpBufferTicks() int {
+	globalMovementSettings.mu.RLock()
+	defer globalMovementSettings.mu.RUnlock()
+	return globalMovementSettings.jumpBufferTicks
+}
+
+// SetJumpBufferTicks sets the jump buffer window in game ticks (0 disables it)
+func SetJumpBufferTicks(ticks int) {
+	globalMovementSettings.mu.Lock()
+	defer globalMovementSettings.mu.Unlock()
+	globalMovementSettings.jumpBufferTicks = clampJumpAssistTicks(ticks)
+}
+
+// GetSprintMode returns how the sprint key works
+func GetSprintMode() SprintMode {
+	globalMovementSettings.mu.RLock()
+	defer globalMovementSettings.mu.RUnlock()
+	return globalMovementSettings.sprintMode
+}
+
+// SetSprintMode sets how the sprint key works
+func SetSprintMode(mode SprintMode) {
+	globalMovementSettings.mu.Lock()
+	defer globalMovementSettings.mu.Unlock()
+	if mode != SprintToggle {
+		mode = SprintHold
+	}
+	globalMovementSettings.sprintMode = mode
+}
+
+// GetMouseSensitivity returns the degrees the camera turns per pixel of mouse
+// movement
+func GetMouseSensitivity() float32 {
+	globalMovementSettings.mu.RLock()
+	defer globalMovementSettings.mu.RUnlock()
+	return globalMovementSettings.mouseSensitivity
+}
+
+// SetMouseSensitivity sets the degrees the camera turns per pixel of mouse
+// movement
+func SetMouseSensitivity(sensitivity float32) {
+	globalMovementSettings.mu.Lock()
+	defer globalMovementSettings.mu.Unlock()
+	globalMovementSettings.mouseSensitivity = max(MinMouseSensitivity, min(sensitivity, MaxMouseSensitivity))
+}
@@ -0,0 +1,318 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// OptionsFile is where settings are kept between runs, relative to the
+// working directory like saves/ and logs/.
+const OptionsFile = "options.txt"
+
+// GUI scale bounds; the HUD and container screens are drawn at this many
+// pixels per texture pixel
+const (
+	MinGUIScale = 1
+	MaxGUIScale = 4
+)
+
+// Language is a selectable UI language.
+type Language struct {
+	Code string // e.g. "en_us", as in MC's options.txt
+	Name string
+}
+
+// Languages lists the UI languages the game ships. Only English has
+// strings so far; the choice is saved for when translations are added but
+// changes no text yet.
+var Languages = []Language{
+	{Code: "en_us", Name: "English (US)"},
+}
+
+// KeyboardLayout is the layout printed on the player's keyboard. It names
+// the keys in hints and picks the movement keys by their labels.
+type KeyboardLayout int
+
+const (
+	LayoutQWERTY KeyboardLayout = iota
+	LayoutAZERTY
+	LayoutQWERTZ
+	LayoutDvorak
+	KeyboardLayoutCount
+)
+
+// String returns the display name of the layout
+func (l KeyboardLayout) String() string {
+	switch l {
+	case LayoutAZERTY:
+		return "AZERTY"
+	case LayoutQWERTZ:
+		return "QWERTZ"
+	case LayoutDvorak:
+		return "Dvorak"
+	default:
+		return "QWERTY"
+	}
+}
+
+// MovementKeyLabels returns the labels of the forward, left, back and right
+// keys on this layout.
+func (l KeyboardLayout) MovementKeyLabels() [4]string {
+	switch l {
+	case LayoutAZERTY:
+		return [4]string{"Z", "Q", "S", "D"}
+	case LayoutDvorak:
+		return [4]string{",", "A", "O", "E"}
+	default:
+		return [4]string{"W", "A", "S", "D"}
+	}
+}
+
+// MovementKeys returns the movement key labels as a hint, e.g. "W A S D".
+func (l KeyboardLayout) MovementKeys() string {
+	labels := l.MovementKeyLabels()
+	return strings.Join(labels[:], " ")
+}
+
+// InterfaceSettings holds language, GUI scale and control hints
+type InterfaceSettings struct {
+	mu             sync.RWMutex
+	language       string
+	guiScale       int
+	keyboardLayout KeyboardLayout
+}
+
+var globalInterfaceSettings = &InterfaceSettings{
+	language:       "en_us",
+	guiScale:       2,
+	keyboardLayout: LayoutQWERTY,
+}
+
+// GetLanguage returns the UI language code
+func GetLanguage() string {
+	globalInterfaceSettings.mu.RLock()
+	defer globalInterfaceSettings.mu.RUnlock()
+	return globalInterfaceSettings.language
+}
+
+// SetLanguage sets the UI language; unknown codes are ignored
+func SetLanguage(code string) {
+	for _, l := range Languages {
+		if l.Code == code {
+			globalInterfaceSettings.mu.Lock()
+			globalInterfaceSettings.language = code
+			globalInterfaceSettings.mu.Unlock()
+			return
+		}
+	}
+}
+
+// GetGUIScale returns the GUI scale
+func GetGUIScale() int {
+	globalInterfaceSettings.mu.RLock()
+	defer globalInterfaceSettings.mu.RUnlock()
+	return globalInterfaceSettings.guiScale
+}
+
+// SetGUIScale sets the GUI scale
+func SetGUIScale(scale int) {
+	globalInterfaceSettings.mu.Lock()
+	defer globalInterfaceSettings.mu.Unlock()
+	globalInterfaceSettings.guiScale = max(MinGUIScale, min(scale, MaxGUIScale))
+}
+
+// GetKeyboardLayout returns the keyboard layout used for key hints and
+// movement keys
+func GetKeyboardLayout() KeyboardLayout {
+	globalInterfaceSettings.mu.RLock()
+	defer globalInterfaceSettings.mu.RUnlock()
+	return globalInterfaceSettings.keyboardLayout
+}
+
+// SetKeyboardLayout sets the keyboard layout used for key hints and
+// movement keys
+func SetKeyboardLayout(layout KeyboardLayout) {
+	globalInterfaceSettings.mu.Lock()
+	defer globalInterfaceSettings.mu.Unlock()
+	if layout < 0 || layout >= KeyboardLayoutCount {
+		layout = LayoutQWERTY
+	}
+	globalInterfaceSettings.keyboardLayout = layout
+}
+
+// option is one line of the options file.
+type option struct {
+	key string
+	get func() string
+	set func(string) error
+}
+
+func intOption(key string, get func() int, set func(int)) option {
+	return option{key,
+		func() string { return strconv.Itoa(get()) },
+		func(v string) error {
+			n, err := strconv.Atoi(v)
+			if err == nil {
+				set(n)
+			}
+			return err
+		}}
+}
+
+func boolOption(key string, get func() bool, set func(bool)) option {
+	return option{key,
+		func() string { return strconv.FormatBool(get()) },
+		func(v string) error {
+			b, err := strconv.ParseBool(v)
+			if err == nil {
+				set(b)
+			}
+			return err
+		}}
+}
+
+func float32Option(key string, get func() float32, set func(float32)) option {
+	return option{key,
+		func() string { return strconv.FormatFloat(float64(get()), 'f', 2, 32) },
+		func(v string) error {
+			f, err := strconv.ParseFloat(v, 32)
+			if err == nil {
+				set(float32(f))
+			}
+			return err
+		}}
+}
+
+// busVolumeOptions returns an option for each audio bus's volume.
+func busVolumeOptions() []option {
+	opts := make([]option, 0, AudioBusCount)
+	for b := range AudioBusCount {
+		opts = append(opts, float32Option(b.optionKey(),
+			func() float32 { return GetBusVolume(b) },
+			func(v float32) { SetBusVolume(b, v) }))
+	}
+	return opts
+}
+
+var options = append([]option{
+	{"lang", GetLanguage, func(v string) error { SetLanguage(v); return nil }},
+	intOption("guiScale", GetGUIScale, SetGUIScale),
+	intOption("keyboardLayout", func() int { return int(GetKeyboardLayout()) }, func(n int) { SetKeyboardLayout(KeyboardLayout(n)) }),
+	intOption("renderDistance", GetRenderDistance, SetRenderDistance),
+	intOption("entitySimulationDistance", GetEntitySimulationDistance, SetEntitySimulationDistance),
+	intOption("entityRenderDistance", GetEntityRenderDistance, SetEntityRenderDistance),
+	intOption("maxFps", GetFPSLimit, SetFPSLimit),
+	intOption("fov", GetFOV, SetFOV),
+	boolOption("fullscreen", GetFullscreen, SetFullscreen),
+	float32Option("mouseSensitivity", GetMouseSensitivity, SetMouseSensitivity),
+	boolOption("bobView", GetViewBobbing, SetViewBobbing),
+	boolOption("fpsInTitle", GetFPSInTitle, SetFPSInTitle),
+	boolOption("textureVariation", GetTextureVariation, SetTextureVariation),
+	boolOption("wavingFoliage", GetFoliageWaving, SetFoliageWaving),
+	boolOption("ao", GetAmbientOcclusion, SetAmbientOcclusion),
+	boolOption("fancyWater", GetFancyWater, SetFancyWater),
+	boolOption("sunShafts", GetSunShafts, SetSunShafts),
+	intOption("shadows", func() int { return int(GetShadowQuality()) }, func(n int) { SetShadowQuality(ShadowQuality(n)) }),
+	boolOption("occlusionCulling", GetOcclusionCulling, SetOcclusionCulling),
+	boolOption("packedColumnCulling", GetPackedColumnCulling, SetPackedColumnCulling),
+	intOption("lodDistance", GetLODDistance, SetLODDistance),
+	intOption("meshMemoryCap", GetMeshMemoryCapMB, SetMeshMemoryCapMB),
+	boolOption("keepEvictedMeshCopies", GetKeepEvictedMeshCopies, SetKeepEvictedMeshCopies),
+	float32Option("hudTextScale", GetHUDTextScale, SetHUDTextScale),
+	boolOption("lowHealthEffect", GetLowHealthEffect, SetLowHealthEffect),
+	boolOption("reducedMotion", GetReducedMotion, SetReducedMotion),
+	boolOption("highContrast", GetHighContrast, SetHighContrast),
+	boolOption("disableFlashing", GetDisableFlashing, SetDisableFlashing),
+	intOption("colorblindMode", func() int { return int(GetColorblindMode()) }, func(n int) { SetColorblindMode(ColorblindMode(n)) }),
+	boolOption("colorblindFoliage", GetColorblindFoliage, SetColorblindFoliage),
+	{"dayCycleSpeed",
+		func() string { return strconv.FormatFloat(GetDayCycleSpeed(), 'g', -1, 64) },
+		func(v string) error {
+			f, err := strconv.ParseFloat(v, 64)
+			if err == nil {
+				SetDayCycleSpeed(f)
+			}
+			return err
+		}},
+	boolOption("duckInMenus", GetDuckInMenus, SetDuckInMenus),
+	boolOption("muteWhenPaused", GetMuteWhenPaused, SetMuteWhenPaused),
+	boolOption("pauseOnLostFocus", GetPauseOnLostFocus, SetPauseOnLostFocus),
+	boolOption("resumeOnFocus", GetResumeOnFocus, SetResumeOnFocus),
+	intOption("minimizedFps", GetMinimizedFPS, SetMinimizedFPS),
+	boolOption("toggleSprint", func() bool { return GetSprintMode() == SprintToggle }, func(on bool) {
+		if on {
+			SetSprintMode(SprintToggle)
+		} else {
+			SetSprintMode(SprintHold)
+		}
+	}),
+	intOption("coyoteTicks", GetCoyoteTicks, SetCoyoteTicks),
+	intOption("jumpBufferTicks", GetJumpBufferTicks, SetJumpBufferTicks),
+}, append(busVolumeOptions(), hudWidgetOptions()...)...)
+
+// LoadOptions applies the settings saved in path. firstRun is true when the
+// file does not exist yet, so the caller can offer first-run setup. Unknown
+// keys are skipped and malformed values reported, keeping the defaults.
+func LoadOptions(path string) (firstRun bool, err error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	var errs []error
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		key, value, ok := strings.Cut(strings.TrimSpace(sc.Text()), ":")
+		if !ok {
+			continue
+		}
+		for _, o := range options {
+			if o.key == key {
+				if err := o.set(value); err != nil {
+					errs = append(errs, fmt.Errorf("%s:%d: %s: %w", path, line, key, err))
+				}
+				break
+			}
+		}
+	}
+	return false, errors.Join(append(errs, sc.Err())...)
+}
+
+// optionsDirty is set while settings changed by the player are not saved.
+var optionsDirty atomic.Bool
+
+// MarkOptionsDirty records a settings change for FlushOptions to save. Call
+// it once a change is committed, such as when a slider is let go, rather
+// than on every step of it.
+func MarkOptionsDirty() {
+	optionsDirty.Store(true)
+}
+
+// FlushOptions saves the settings to path if they changed since they were
+// last saved. It is cheap to call every frame.
+func FlushOptions(path string) error {
+	if !optionsDirty.Load() {
+		return nil
+	}
+	return SaveOptions(path)
+}
+
+// SaveOptions writes the current settings to path as key:value lines.
+func SaveOptions(path string) error {
+	optionsDirty.Store(false)
+	var b strings.Builder
+	for _, o := range options {
+		fmt.Fprintf(&b, "%s:%s\n", o.key, o.get())
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOptionsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), OptionsFile)
+	firstRun, err := LoadOptions(path)
+	if err != nil || !firstRun {
+		t.Fatalf("missing file: firstRun %v, err %v; want first run", firstRun, err)
+	}
+
+	prevScale, prevDist, prevColorblind := GetGUIScale(), GetRenderDistance(), GetColorblindMode()
+	prevFOV, prevSens, prevShadows := GetFOV(), GetMouseSensitivity(), GetShadowQuality()
+	prevClock, prevClockCorner := GetHUDWidgetShown(WidgetClock), GetHUDWidgetCorner(WidgetClock)
+	prevLayout := GetKeyboardLayout()
+	defer func() {
+		SetGUIScale(prevScale)
+		SetRenderDistance(prevDist)
+		SetColorblindMode(prevColorblind)
+		SetFOV(prevFOV)
+		SetMouseSensitivity(prevSens)
+		SetShadowQuality(prevShadows)
+		SetHUDWidgetShown(WidgetClock, prevClock)
+		SetHUDWidgetCorner(WidgetClock, prevClockCorner)
+		SetKeyboardLayout(prevLayout)
+	}()
+
+	SetGUIScale(3)
+	SetRenderDistance(12)
+	SetColorblindMode(ColorblindDeuteranopia)
+	SetFOV(90)
+	SetMouseSensitivity(0.25)
+	SetShadowQuality(ShadowsHigh)
+	SetHUDWidgetShown(WidgetClock, false)
+	SetHUDWidgetCorner(WidgetClock, CornerBottomRight)
+	SetKeyboardLayout(LayoutAZERTY)
+	if err := SaveOptions(path); err != nil {
+		t.Fatal(err)
+	}
+	SetGUIScale(1)
+	SetRenderDistance(30)
+	SetColorblindMode(ColorblindOff)
+	SetFOV(DefaultFOV)
+	SetMouseSensitivity(DefaultMouseSensitivity)
+	SetShadowQuality(ShadowsOff)
+	SetHUDWidgetShown(WidgetClock, true)
+	CycleHUDWidgetCorner(WidgetClock)
+	SetKeyboardLayout(LayoutQWERTY)
+
+	if firstRun, err := LoadOptions(path); err != nil || firstRun {
+		t.Fatalf("LoadOptions = %v, %v", firstRun, err)
+	}
+	if GetGUIScale() != 3 || GetRenderDistance() != 12 || GetColorblindMode() != ColorblindDeuteranopia {
+		t.Errorf("loaded guiScale %d, renderDistance %d, colorblind mode %v", GetGUIScale(), GetRenderDistance(), GetColorblindMode())
+	}
+	if GetFOV() != 90 || GetMouseSensitivity() != 0.25 {
+		t.Errorf("loaded fov %d, mouseSensitivity %v; want 90, 0.25", GetFOV(), GetMouseSensitivity())
+	}
+	if GetShadowQuality() != ShadowsHigh {
+		t.Errorf("loaded shadows %v, want High", GetShadowQuality())
+	}
+	if GetHUDWidgetShown(WidgetClock) || GetHUDWidgetCorner(WidgetClock) != CornerBottomRight {
+		t.Errorf("loaded clock widget shown %v in %v; want hidden in the bottom right", GetHUDWidgetShown(WidgetClock), GetHUDWidgetCorner(WidgetClock))
+	}
+
+	if GetKeyboardLayout() != LayoutAZERTY {
+		t.Errorf("loaded keyboard layout %v, want AZERTY", GetKeyboardLayout())
+	}
+
+	// A bad value is reported but does not stop the other options loading
+	if err := os.WriteFile(path, []byte("guiScale:big\nrenderDistance:9\nunknown:1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOptions(path); err == nil {
+		t.Error("malformed guiScale was not reported")
+	}
+	if GetGUIScale() != 3 || GetRenderDistance() != 9 {
+		t.Errorf("after bad file: guiScale %d, renderDistance %d; want 3, 9", GetGUIScale(), GetRenderDistance())
+	}
+}
+
+func TestFlushOptionsOnlyWhenDirty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), OptionsFile)
+	optionsDirty.Store(false)
+	if err := FlushOptions(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("clean options were written: %v", err)
+	}
+
+	MarkOptionsDirty()
+	if err := FlushOptions(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("dirty options were not written: %v", err)
+	}
+	if optionsDirty.Load() {
+		t.Error("options still dirty after flushing")
+	}
+}
@@ -0,0 +1,34 @@
+package config
+
+import "strings"
+
+// RenderPreset is a named render distance offered during first-run setup.
+type RenderPreset struct {
+	Name     string
+	Distance int // chunks
+}
+
+// RenderPresets are ordered from lightest to heaviest.
+var RenderPresets = []RenderPreset{
+	{"Low", 8},
+	{"Medium", 12},
+	{"High", 20},
+	{"Ultra", 32},
+}
+
+// RecommendRenderPreset picks an index into RenderPresets from the GL
+// renderer string and the number of CPUs. Meshing runs on every core but
+// one, and integrated or software GPUs struggle with large atlases.
+func RecommendRenderPreset(glRenderer string, cpus int) int {
+	r := strings.ToLower(glRenderer)
+	switch {
+	case strings.Contains(r, "llvmpipe") || strings.Contains(r, "softpipe") || strings.Contains(r, "swiftshader"):
+		return 0
+	case strings.Contains(r, "intel") || cpus <= 4:
+		return 1
+	case cpus >= 12 && (strings.Contains(r, "nvidia") || strings.Contains(r, "geforce") || strings.Contains(r, "radeon") || strings.Contains(r, "amd")):
+		return 3
+	default:
+		return 2
+	}
+}
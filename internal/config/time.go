@@ -0,0 +1,32 @@
+package config
+
+import "sync"
+
+// MaxDayCycleSpeed is the fastest the day/night cycle can be set to run.
+const MaxDayCycleSpeed = 100
+
+// TimeSettings holds how world time passes
+type TimeSettings struct {
+	mu            sync.RWMutex
+	dayCycleSpeed float64
+}
+
+var globalTimeSettings = &TimeSettings{
+	dayCycleSpeed: 1, // a day takes 20 minutes, as in 1.8.9
+}
+
+// GetDayCycleSpeed returns how many times faster than normal the day/night
+// cycle runs; 0 stops it
+func GetDayCycleSpeed() float64 {
+	globalTimeSettings.mu.RLock()
+	defer globalTimeSettings.mu.RUnlock()
+	return globalTimeSettings.dayCycleSpeed
+}
+
+// SetDayCycleSpeed sets how many times faster than normal the day/night
+// cycle runs, clamped to [0, MaxDayCycleSpeed]
+func SetDayCycleSpeed(speed float64) {
+	globalTimeSettings.mu.Lock()
+	defer globalTimeSettings.mu.Unlock()
+	globalTimeSettings.dayCycleSpeed = min(max(speed, 0), MaxDayCycleSpeed)
+}
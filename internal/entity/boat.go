@@ -0,0 +1,185 @@
+package entity
+
+import (
+	"math"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Boat dimensions and handling. Speeds are in blocks per second.
+const (
+	BoatWidth  = 1.5
+	BoatHeight = 0.6
+
+	boatDraft       = 0.3   // how deep the hull sits in the water
+	boatSeatHeight  = 0.1   // rider's feet above the boat's bottom
+	boatAccel       = 6.0   // forward acceleration on water
+	boatMaxSpeed    = 8.0   // MC boats top out around 8 blocks/s
+	boatTurnSpeed   = 120.0 // degrees per second at full input
+	boatWaterDrag   = 0.95  // per tick
+	boatLandDrag    = 0.5   // per tick; boats barely move on land
+	boatBuoyancy    = 5.0   // how hard the hull is pulled to its waterline
+	boatMaxBobSpeed = 2.0
+	boatGravity     = 18.0
+)
+
+// Boat is a ridable entity that floats on water. The rider steers it with
+// Steer; it has no controls of its own.
+type Boat struct {
+	Pos     mgl32.Vec3 // bottom centre
+	Vel     mgl32.Vec3
+	Yaw     float32 // degrees, same convention as the player's CamYaw
+	World   WorldSource
+	Dead    bool
+	OnWater bool
+
+	rider  Rider
+	motion motion
+
+	forward, turn float32 // rider input in [-1, 1]
+}
+
+// NewBoat creates a boat at pos facing yaw.
+func NewBoat(w WorldSource, pos mgl32.Vec3, yaw float32) *Boat {
+	return &Boat{Pos: pos, Yaw: yaw, World: w}
+}
+
+// Steer sets the rider's input for the next updates: forward accelerates along
+// the boat's heading and turn (positive = right) rotates it.
+func (b *Boat) Steer(forward, turn float32) {
+	b.forward = max(-1, min(1, forward))
+	b.turn = max(-1, min(1, turn))
+}
+
+// Heading returns the unit XZ direction the boat faces.
+func (b *Boat) Heading() mgl32.Vec3 {
+	yaw := float64(mgl32.DegToRad(b.Yaw))
+	return mgl32.Vec3{float32(math.Cos(yaw)), 0, float32(math.Sin(yaw))}
+}
+
+func (b *Boat) Update(dt float64) {
+	b.motion.startTick(b.Transform())
+	if b.Dead {
+		return
+	}
+	if b.rider == nil {
+		b.forward, b.turn = 0, 0
+	}
+	ticks := dt * 20
+
+	// Float: pull the hull towards its waterline, otherwise fall
+	surface, inWater := b.waterSurface()
+	b.OnWater = inWater
+	if inWater {
+		target := surface - boatDraft
+		vy := (target - b.Pos.Y()) * boatBuoyancy
+		b.Vel[1] = max(-boatMaxBobSpeed, min(boatMaxBobSpeed, vy))
+	} else {
+		b.Vel[1] -= boatGravity * float32(dt)
+	}
+
+	// Steering
+	b.Yaw += b.turn * boatTurnSpeed * float32(dt)
+	if b.forward != 0 {
+		accel := b.Heading().Mul(b.forward * boatAccel * float32(dt))
+		b.Vel[0] += accel.X()
+		b.Vel[2] += accel.Z()
+	}
+
+	drag := boatWaterDrag
+	if !inWater && b.onGround() {
+		drag = boatLandDrag
+	}
+	dragFactor := float32(math.Pow(drag, ticks))
+	b.Vel[0] *= dragFactor
+	b.Vel[2] *= dragFactor
+	speed := mgl32.Vec2{b.Vel.X(), b.Vel.Z()}.Len()
+	if speed > boatMaxSpeed {
+		scale := boatMaxSpeed / speed
+		b.Vel[0] *= scale
+		b.Vel[2] *= scale
+	}
+
+	// Move one axis at a time, stopping on solid blocks
+	delta := b.Vel.Mul(float32(dt))
+	if b.collides(b.Pos.X()+delta.X(), b.Pos.Y(), b.Pos.Z()) {
+		b.Vel[0] = 0
+	} else {
+		b.Pos[0] += delta.X()
+	}
+	if b.collides(b.Pos.X(), b.Pos.Y()+delta.Y(), b.Pos.Z()) {
+		b.Vel[1] = 0
+	} else {
+		b.Pos[1] += delta.Y()
+	}
+	if b.collides(b.Pos.X(), b.Pos.Y(), b.Pos.Z()+delta.Z()) {
+		b.Vel[2] = 0
+	} else {
+		b.Pos[2] += delta.Z()
+	}
+
+	if b.rider != nil {
+		b.rider.FollowVehicle(b.SeatPosition())
+	}
+}
+
+// waterSurface returns the Y of the water surface the boat floats on, if the
+// hull is in water.
+func (b *Boat) waterSurface() (float32, bool) {
+	x := int(math.Floor(float64(b.Pos.X())))
+	z := int(math.Floor(float64(b.Pos.Z())))
+	y := int(math.Floor(float64(b.Pos.Y() + boatDraft)))
+	if b.World.Get(x, y, z) != world.BlockTypeWater {
+		y-- // hull just above the surface
+		if b.World.Get(x, y, z) != world.BlockTypeWater {
+			return 0, false
+		}
+	}
+	for b.World.Get(x, y+1, z) == world.BlockTypeWater {
+		y++
+	}
+	return float32(y + 1), true
+}
+
+func (b *Boat) onGround() bool {
+	return b.collides(b.Pos.X(), b.Pos.Y()-0.05, b.Pos.Z())
+}
+
+// collides reports whether the boat's box at (x, y, z) overlaps a solid block.
+func (b *Boat) collides(x, y, z float32) bool {
+	return boxCollides(b.World, x, y, z, BoatWidth, BoatHeight)
+}
+
+// SeatPosition returns where the rider's feet go.
+func (b *Boat) SeatPosition() mgl32.Vec3 {
+	return b.Pos.Add(mgl32.Vec3{0, boatSeatHeight, 0})
+}
+
+func (b *Boat) Rider() Rider { return b.rider }
+
+func (b *Boat) SetRider(r Rider) { b.rider = r }
+
+func (b *Boat) Position() mgl32.Vec3 { return b.Pos }
+
+func (b *Boat) Transform() Transform { return Transform{Pos: b.Pos, Yaw: b.Yaw} }
+
+func (b *Boat) PrevTransform() Transform { return b.motion.prevOr(b.Transform()) }
+
+func (b *Boat) Idle() { b.motion.startTick(b.Transform()) }
+
+func (b *Boat) IsDead() bool { return b.Dead }
+
+func (b *Boat) SetDead() { b.Dead = true }
+
+func (b *Boat) GetBounds() (width, height float32) { return BoatWidth, BoatHeight }
+
+func (b *Boat) AABB() AABB { return boundsAABB(b.Pos, BoatWidth, BoatHeight) }
+
+func (b *Boat) Model() world.BlockType { return world.BlockTypeBoat }
+
+func (b *Boat) Push(dvx, dvz float32) {
+	b.Vel[0] += dvx
+	b.Vel[2] += dvz
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// pond is a flat world of water at y <= 10 over stone at y <= 5.
+type pond struct{}
+
+func (pond) Get(x, y, z int) world.BlockType {
+	switch {
+	case y <= 5:
+		return world.BlockTypeStone
+	case y <= 10:
+		return world.BlockTypeWater
+	}
+	return world.BlockTypeAir
+}
+
+func (p pond) IsAir(x, y, z int) bool { return p.Get(x, y, z) == world.BlockTypeAir }
+
+func (pond) GetMeta(x, y, z int) uint8 { return 0 }
+
+func TestBoatFloatsAndSteers(t *testing.T) {
+	world.BlockSolidTable[world.BlockTypeStone] = true
+
+	b := NewBoat(pond{}, mgl32.Vec3{0.5, 13, 0.5}, 0)
+	for range 100 {
+		b.Update(0.05)
+	}
+	want := float32(11 - boatDraft)
+	if !b.OnWater || b.Pos.Y() < want-0.05 || b.Pos.Y() > want+0.05 {
+		t.Fatalf("boat settled at y=%v (on water %v), want ~%v", b.Pos.Y(), b.OnWater, want)
+	}
+
+	// Without a rider input is ignored
+	b.Steer(1, 0)
+	b.Update(0.05)
+	if b.Vel.X() != 0 {
+		t.Fatalf("riderless boat moved: vel %v", b.Vel)
+	}
+
+	b.SetRider(riderFunc(func(mgl32.Vec3) {}))
+	startX := b.Pos.X()
+	for range 20 {
+		b.Steer(1, 0)
+		b.Update(0.05)
+	}
+	if b.Pos.X() <= startX {
+		t.Fatalf("boat did not move forward: x %v -> %v", startX, b.Pos.X())
+	}
+}
+
+type riderFunc func(mgl32.Vec3)
+
+func (f riderFunc) FollowVehicle(seat mgl32.Vec3) { f(seat) }
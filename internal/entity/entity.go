@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"math"
+
 	"mini-mc/internal/world"
 
 	"github.com/go-gl/mathgl/mgl32"
@@ -10,6 +12,14 @@ import (
 type WorldSource interface {
 	IsAir(x, y, z int) bool
 	Get(x, y, z int) world.BlockType
+	GetMeta(x, y, z int) uint8
+}
+
+// EntitySource is implemented by worlds that can find the entities around
+// a point, such as *world.World; items use it to merge with their
+// neighbours.
+type EntitySource interface {
+	GetEntitiesInAABB(minX, minY, minZ, maxX, maxY, maxZ float32) []world.Ticker
 }
 
 // Entity interface
@@ -19,4 +29,81 @@ type Entity interface {
 	IsDead() bool
 	SetDead()
 	GetBounds() (width, height float32)
+	// AABB returns the box the entity takes up.
+	AABB() AABB
+	// Transform returns where the entity is now, and PrevTransform where it
+	// was before its last update; see RenderTransform.
+	Transform() Transform
+	PrevTransform() Transform
+}
+
+// AABB is an axis-aligned box.
+type AABB struct {
+	Min, Max mgl32.Vec3
+}
+
+// boundsAABB returns the box of an entity of the given size whose bottom
+// centre is at pos.
+func boundsAABB(pos mgl32.Vec3, width, height float32) AABB {
+	r := width / 2
+	return AABB{
+		Min: mgl32.Vec3{pos.X() - r, pos.Y(), pos.Z() - r},
+		Max: mgl32.Vec3{pos.X() + r, pos.Y() + height, pos.Z() + r},
+	}
+}
+
+// Modeled is implemented by entities drawn with a block or item model. It
+// is the renderer's hook: the entity picks the model, the renderer places
+// it at the entity's transform.
+type Modeled interface {
+	Entity
+	Model() world.BlockType
 }
+
+// Rider is whatever sits in a vehicle. The vehicle carries it along after
+// each of its own updates.
+type Rider interface {
+	FollowVehicle(seat mgl32.Vec3)
+}
+
+// Vehicle is a ridable entity that its rider steers.
+type Vehicle interface {
+	Entity
+	// Steer passes the rider's input for the next updates: forward/back and
+	// turn (positive = right), each in [-1, 1].
+	Steer(forward, turn float32)
+	// SeatPosition returns where the rider's feet go.
+	SeatPosition() mgl32.Vec3
+	Rider() Rider
+	SetRider(r Rider)
+}
+
+// boxCollides reports whether a box of the given size standing at (x, y, z),
+// its bottom centre, overlaps a solid block.
+func boxCollides(w WorldSource, x, y, z, width, height float32) bool {
+	r := width / 2
+	minX := int(math.Floor(float64(x - r)))
+	maxX := int(math.Floor(float64(x + r)))
+	minY := int(math.Floor(float64(y)))
+	maxY := int(math.Floor(float64(y + height)))
+	minZ := int(math.Floor(float64(z - r)))
+	maxZ := int(math.Floor(float64(z + r)))
+
+	for bx := minX; bx <= maxX; bx++ {
+		for by := minY; by <= maxY; by++ {
+			for bz := minZ; bz <= maxZ; bz++ {
+				if world.BlockSolidTable[w.Get(bx, by, bz)] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Entities with a box push each other apart
+var (
+	_ world.Body = (*ItemEntity)(nil)
+	_ world.Body = (*Boat)(nil)
+	_ world.Body = (*Minecart)(nil)
+)
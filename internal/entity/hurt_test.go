@@ -0,0 +1,94 @@
+package entity
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestHurtAnimFlashAndDeath(t *testing.T) {
+	const dt = 1.0 / 16 // exact in binary so the timers land on their durations
+	var a HurtAnim
+	a.Hurt()
+	if !a.Flashing() {
+		t.Fatal("not flashing after a hit")
+	}
+	for range 8 {
+		a.TickHurt(dt)
+	}
+	if a.Flashing() {
+		t.Fatal("still flashing after HurtDuration")
+	}
+
+	a.Kill()
+	if a.DeathTilt() != 0 || a.DeathDone() {
+		t.Fatal("death animation should start upright")
+	}
+	var last float32
+	for range 15 {
+		a.TickHurt(dt)
+		if tilt := a.DeathTilt(); tilt < last || tilt > 90 {
+			t.Fatalf("tilt %v at %vs, previous %v", tilt, a.DeathTime, last)
+		} else {
+			last = tilt
+		}
+		if a.DeathDone() {
+			t.Fatalf("death done early at %vs", a.DeathTime)
+		}
+	}
+	if last != 90 || !a.Flashing() {
+		t.Fatalf("dying entity tilt %v, flashing %v; want 90, true", last, a.Flashing())
+	}
+	a.TickHurt(dt)
+	if !a.DeathDone() {
+		t.Fatal("death not done after DeathDuration")
+	}
+}
+
+func TestMobHurtUntilItDies(t *testing.T) {
+	world.BlockSolidTable[world.BlockTypeStone] = true
+	w := world.New()
+	defer w.Close()
+	for x := -8; x <= 8; x++ {
+		for z := -8; z <= 8; z++ {
+			w.Set(x, 63, z, world.BlockTypeStone)
+		}
+	}
+	chicken := NewMob(w, MobChicken, mgl32.Vec3{0.5, 64, 0.5}, 0)
+	attacker := mgl32.Vec3{-1.5, 64, 0.5}
+
+	if !chicken.Damage(1, attacker) {
+		t.Fatal("first hit missed")
+	}
+	if !chicken.Flashing() || chicken.Vel.X() <= 0 || chicken.Vel.Y() <= 0 {
+		t.Fatalf("flashing %v, velocity %v; want flashing and knocked away and up", chicken.Flashing(), chicken.Vel)
+	}
+	if chicken.Damage(1, attacker) {
+		t.Fatal("hit while still flashing")
+	}
+
+	hits := 1
+	for !chicken.Dying() {
+		for range 11 {
+			chicken.Update(0.05)
+		}
+		if !chicken.Damage(1, attacker) {
+			t.Fatalf("hit %d missed after the flash", hits+1)
+		}
+		hits++
+	}
+	if hits != 4 {
+		t.Fatalf("chicken died after %d hits, want 4", hits)
+	}
+	for range 20 {
+		if chicken.IsDead() {
+			t.Fatal("removed before falling over")
+		}
+		chicken.Update(0.05)
+	}
+	if !chicken.IsDead() || chicken.DeathTilt() != 90 {
+		t.Fatalf("dead %v, tilt %v after the death animation", chicken.IsDead(), chicken.DeathTilt())
+	}
+}
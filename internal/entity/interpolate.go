@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// MaxExtrapolation is how many ticks past the last one an entity is carried
+// on along its last tick's movement when the simulation falls behind.
+const MaxExtrapolation = 0.5
+
+// Transform is where an entity is drawn: its position and the way it faces,
+// in degrees of yaw.
+type Transform struct {
+	Pos mgl32.Vec3
+	Yaw float32
+}
+
+// motion remembers an entity's transform from before its last update.
+// Entities call startTick with their transform as each update begins, and
+// when an update passes them by (world.Idler).
+type motion struct {
+	prev    Transform
+	hasPrev bool
+}
+
+func (m *motion) startTick(t Transform) {
+	m.prev, m.hasPrev = t, true
+}
+
+// prevOr returns the remembered transform, or cur before the first update.
+func (m *motion) prevOr(cur Transform) Transform {
+	if !m.hasPrev {
+		return cur
+	}
+	return m.prev
+}
+
+// RenderTransform returns e's transform partialTicks of the way from before
+// its last update to now. Beyond 1 the last movement is extrapolated, for at
+// most MaxExtrapolation ticks.
+func RenderTransform(e Entity, partialTicks float32) Transform {
+	t := min(max(partialTicks, 0), 1+MaxExtrapolation)
+	prev, cur := e.PrevTransform(), e.Transform()
+	return Transform{
+		Pos: prev.Pos.Add(cur.Pos.Sub(prev.Pos).Mul(t)),
+		Yaw: prev.Yaw + wrapDegrees(cur.Yaw-prev.Yaw)*t,
+	}
+}
+
+// wrapDegrees wraps an angle to [-180, 180), so turning takes the short way.
+func wrapDegrees(a float32) float32 {
+	a = float32(math.Mod(float64(a)+180, 360))
+	if a < 0 {
+		a += 360
+	}
+	return a - 180
+}
@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestRenderTransformBlendsTicks(t *testing.T) {
+	world.BlockSolidTable[world.BlockTypeStone] = true
+
+	b := NewBoat(pond{}, mgl32.Vec3{0.5, 10.7, 0.5}, 170)
+	if got := RenderTransform(b, 0.5); got != b.Transform() {
+		t.Fatalf("before any update drawn at %+v, want where it is", got)
+	}
+
+	b.SetRider(riderFunc(func(mgl32.Vec3) {}))
+	b.Steer(1, 1)
+	b.Update(0.05)
+	prev, cur := b.PrevTransform(), b.Transform()
+	if prev.Pos == cur.Pos || prev.Yaw == cur.Yaw {
+		t.Fatalf("update did not move or turn the boat: %+v -> %+v", prev, cur)
+	}
+
+	if got := RenderTransform(b, 0); got != prev {
+		t.Errorf("at partial tick 0 drawn at %+v, want %+v", got, prev)
+	}
+	if got := RenderTransform(b, 1); !got.Pos.ApproxEqual(cur.Pos) || !mgl32.FloatEqual(got.Yaw, cur.Yaw) {
+		t.Errorf("at partial tick 1 drawn at %+v, want %+v", got, cur)
+	}
+	mid := RenderTransform(b, 0.5)
+	if want := prev.Pos.Add(cur.Pos).Mul(0.5); !mid.Pos.ApproxEqual(want) {
+		t.Errorf("halfway drawn at %v, want %v", mid.Pos, want)
+	}
+
+	// A hitch carries it on, but only so far
+	far := RenderTransform(b, 5)
+	want := cur.Pos.Add(cur.Pos.Sub(prev.Pos).Mul(MaxExtrapolation))
+	if !far.Pos.ApproxEqualThreshold(want, 1e-4) {
+		t.Errorf("extrapolated to %v, want capped at %v", far.Pos, want)
+	}
+
+	// Idling draws it standing still
+	b.Idle()
+	if got := RenderTransform(b, 0.3); got != b.Transform() {
+		t.Errorf("idle boat drawn at %+v, want %+v", got, b.Transform())
+	}
+}
+
+func TestRenderTransformTurnsTheShortWay(t *testing.T) {
+	c := NewMinecart(pond{}, mgl32.Vec3{}, 350)
+	c.motion.startTick(c.Transform())
+	c.Yaw = 10
+	if got := RenderTransform(c, 0.5).Yaw; !mgl32.FloatEqual(got, 360) {
+		t.Errorf("turning from 350 to 10 halfway = %v, want 360", got)
+	}
+}
@@ -24,14 +24,16 @@ const (
 	NoDespawnAge        = -6000 // Age that prevents despawn (300-(-6000)=6300 seconds = 105 minutes)
 
 	// Timing
-	StackSearchInterval = 25   // ticks (1.25 seconds)
-	TickDuration        = 0.05 // seconds per tick (1/20)
+	StackSearchInterval = 25    // ticks (1.25 seconds)
+	TickDuration        = 0.05  // seconds per tick (1/20)
+	DespawnAge          = 300.0 // seconds (6000 ticks)
+	OwnerPickupAge      = 290.0 // seconds (5800 ticks) before others' drops can be taken
+
+	// Magnet: pickable items this close to the player drift towards them
+	MagnetRadius       = 1.5  // blocks
+	MagnetAcceleration = 40.0 // blocks/s²
 )
 
-// NearbyItemsFunc is a callback function to get nearby item entities.
-// Takes center position and range, returns slice of interface{} that can be cast to *ItemEntity.
-type NearbyItemsFunc func(centerX, centerY, centerZ, rangeX, rangeY, rangeZ float32) []interface{}
-
 type ItemEntity struct {
 	Stack       item.ItemStack
 	Pos         mgl32.Vec3
@@ -50,20 +52,21 @@ type ItemEntity struct {
 	prevBlockX, prevBlockY, prevBlockZ int
 	noDespawn                          bool // If true, item never despawns
 
-	// GetNearbyItems is a callback function for stacking queries (set by world after creation)
-	GetNearbyItems NearbyItemsFunc
-
 	// Pickup animation (visual only, not physical movement)
 	IsPickingUp     bool
 	PickupProgress  float64 // 0.0 to 1.0
 	PickupStartPos  mgl32.Vec3
 	PickupTargetPos mgl32.Vec3
+
+	motion motion // for drawing between ticks
 }
 
-func NewItemEntity(w WorldSource, pos mgl32.Vec3, stack item.ItemStack) *ItemEntity {
+// NewItemEntity drops stack at pos. rnd supplies the toss and bobbing phase;
+// pass the world's gameplay source so drops replay with the world.
+func NewItemEntity(w WorldSource, rnd *rand.Rand, pos mgl32.Vec3, stack item.ItemStack) *ItemEntity {
 	// Random velocity based on Minecraft logic
-	vx := (rand.Float64() * 0.2) - 0.1
-	vz := (rand.Float64() * 0.2) - 0.1
+	vx := (rnd.Float64() * 0.2) - 0.1
+	vz := (rnd.Float64() * 0.2) - 0.1
 	vy := 0.4
 
 	return &ItemEntity{
@@ -71,8 +74,8 @@ func NewItemEntity(w WorldSource, pos mgl32.Vec3, stack item.ItemStack) *ItemEnt
 		Pos:         pos,
 		Vel:         mgl32.Vec3{float32(vx), float32(vy), float32(vz)},
 		World:       w,
-		HoverStart:  rand.Float64() * math.Pi * 2.0,
-		RotationYaw: rand.Float64() * 360.0,
+		HoverStart:  rnd.Float64() * math.Pi * 2.0,
+		RotationYaw: rnd.Float64() * 360.0,
 		PickupDelay: 0.5, // 0.5 second delay (10 ticks = 0.5 second at 20 ticks/s, Minecraft default)
 		Owner:       "",  // No owner by default
 		// Initialize previous block positions to current
@@ -83,6 +86,7 @@ func NewItemEntity(w WorldSource, pos mgl32.Vec3, stack item.ItemStack) *ItemEnt
 }
 
 func (e *ItemEntity) Update(dt float64) {
+	e.motion.startTick(e.Transform())
 	if e.Dead {
 		return
 	}
@@ -107,7 +111,7 @@ func (e *ItemEntity) Update(dt float64) {
 	// Minecraft: items despawn after 6000 ticks (300 seconds at 20 ticks/s)
 	// Age is in seconds, so 300 seconds = 5 minutes
 	// Unless noDespawn is set
-	if !e.noDespawn && e.Age >= 300.0 {
+	if !e.noDespawn && e.Age >= DespawnAge {
 		e.Dead = true
 		return
 	}
@@ -182,15 +186,17 @@ func (e *ItemEntity) Update(dt float64) {
 
 	// Search for nearby items to merge with (Minecraft 1.8.9 behavior)
 	// Trigger when crossing block boundary OR every 25 ticks
-	if e.GetNearbyItems != nil && (crossedBlockBoundary || e.ticksExisted%StackSearchInterval == 0) {
+	if crossedBlockBoundary || e.ticksExisted%StackSearchInterval == 0 {
 		e.searchForOtherItemsNearby()
 	}
 }
 
 // searchForOtherItemsNearby finds and attempts to combine with nearby items
 // Matching Minecraft 1.8.9 EntityItem.searchForOtherItemsNearby()
+// Worlds that cannot find entities (see EntitySource) have nothing to merge.
 func (e *ItemEntity) searchForOtherItemsNearby() {
-	if e.Dead || e.GetNearbyItems == nil {
+	src, ok := e.World.(EntitySource)
+	if e.Dead || !ok {
 		return
 	}
 
@@ -202,9 +208,9 @@ func (e *ItemEntity) searchForOtherItemsNearby() {
 	rangeY := halfHeight // No Y expansion
 	rangeZ := halfWidth + StackSearchExpandZ
 
-	nearbyItems := e.GetNearbyItems(
-		e.Pos.X(), e.Pos.Y(), e.Pos.Z(),
-		rangeX, rangeY, rangeZ,
+	nearbyItems := src.GetEntitiesInAABB(
+		e.Pos.X()-rangeX, e.Pos.Y()-rangeY, e.Pos.Z()-rangeZ,
+		e.Pos.X()+rangeX, e.Pos.Y()+rangeY, e.Pos.Z()+rangeZ,
 	)
 
 	for _, other := range nearbyItems {
@@ -318,6 +324,55 @@ func (e *ItemEntity) Position() mgl32.Vec3 {
 	return e.Pos
 }
 
+// Transform returns the item's position; its spin is worked out from its age.
+func (e *ItemEntity) Transform() Transform {
+	return Transform{Pos: e.Position()}
+}
+
+func (e *ItemEntity) PrevTransform() Transform {
+	return e.motion.prevOr(e.Transform())
+}
+
+func (e *ItemEntity) Idle() {
+	e.motion.startTick(e.Transform())
+}
+
+// CanBePickedUp reports whether a player may collect this item now: it is
+// alive, not already flying into an inventory, past its pickup delay, not
+// despawning this tick, and, if it has an owner, old enough for anyone.
+func (e *ItemEntity) CanBePickedUp() bool {
+	if e.Dead || e.IsPickingUp || e.Stack.Count <= 0 {
+		return false
+	}
+	if e.PickupDelay == InfinitePickupDelay || e.PickupDelay > 0 {
+		return false
+	}
+	if !e.noDespawn && e.Age >= DespawnAge {
+		return false
+	}
+	// We have no player names, so an owned item is never the player's own
+	return e.Owner == "" || e.Age >= OwnerPickupAge
+}
+
+// AttractTo accelerates the item towards target for dt seconds. It only
+// changes velocity, so the item still collides and keeps its age.
+func (e *ItemEntity) AttractTo(target mgl32.Vec3, dt float64) {
+	dir := target.Sub(e.Pos)
+	if dir.Len() < 1e-3 {
+		return
+	}
+	e.Vel = e.Vel.Add(dir.Normalize().Mul(float32(MagnetAcceleration * dt)))
+}
+
+// Push nudges the item sideways, e.g. out of a pile. Items flying into the
+// player are left alone.
+func (e *ItemEntity) Push(dvx, dvz float32) {
+	if e.IsPickingUp {
+		return
+	}
+	e.Vel = e.Vel.Add(mgl32.Vec3{dvx, 0, dvz})
+}
+
 // StartPickupAnimation starts the visual pickup animation towards target position
 func (e *ItemEntity) StartPickupAnimation(targetPos mgl32.Vec3) {
 	e.IsPickingUp = true
@@ -339,6 +394,12 @@ func (e *ItemEntity) GetBounds() (width, height float32) {
 	return ItemEntityWidth, ItemEntityHeight
 }
 
+// AABB returns the item's box, following it into the player while it is
+// picked up.
+func (e *ItemEntity) AABB() AABB {
+	return boundsAABB(e.Position(), ItemEntityWidth, ItemEntityHeight)
+}
+
 // SetNoDespawn marks this item as never despawning
 func (e *ItemEntity) SetNoDespawn() {
 	e.noDespawn = true
@@ -0,0 +1,336 @@
+package entity
+
+import (
+	"math"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Minecart dimensions and handling. Speeds are in blocks per second.
+const (
+	MinecartWidth  = 0.98
+	MinecartHeight = 0.7
+
+	cartRideHeight  = 1.0 / 16 // wheels sit on the track, 1/16 above the rail block
+	cartSeatHeight  = 0.2
+	cartMaxSpeed    = 8.0   // MC caps carts at 0.4 blocks/tick
+	cartPushAccel   = 4.0   // rider pushing along the track
+	cartSlopeAccel  = 3.125 // MC adds 0.0078125 blocks/tick² on slopes
+	cartRiddenDrag  = 0.997 // per tick
+	cartEmptyDrag   = 0.96  // per tick
+	cartGroundDrag  = 0.5   // per tick, off the rails
+	cartGravity     = 18.0
+	cartSnapSamples = 16 // path samples when landing on a rail
+)
+
+// Minecart is a ridable entity that follows rails. On a rail it moves along
+// the track's path with momentum, speeding up downhill; off the rails it
+// falls and slides to a stop.
+type Minecart struct {
+	Pos    mgl32.Vec3 // bottom centre
+	Vel    mgl32.Vec3 // world velocity while off the rails
+	Yaw    float32    // degrees, same convention as the player's CamYaw
+	World  WorldSource
+	Dead   bool
+	OnRail bool
+
+	rider  Rider
+	motion motion
+
+	// Track state while OnRail: the rail block, the position along its path
+	// (0 at its first exit, 1 at its second) and the speed along it
+	railX, railY, railZ int
+	railT               float32
+	speed               float32
+
+	forward float32 // rider input in [-1, 1]
+}
+
+// NewMinecart creates a minecart at pos facing yaw. It latches onto a rail
+// at pos on its first update.
+func NewMinecart(w WorldSource, pos mgl32.Vec3, yaw float32) *Minecart {
+	return &Minecart{Pos: pos, Yaw: yaw, World: w}
+}
+
+// Steer pushes the cart along its facing; turning follows the track.
+func (c *Minecart) Steer(forward, turn float32) {
+	c.forward = max(-1, min(1, forward))
+}
+
+// Speed returns how fast the cart moves, in blocks per second.
+func (c *Minecart) Speed() float32 {
+	if c.OnRail {
+		return abs32(c.speed)
+	}
+	return c.Vel.Len()
+}
+
+func (c *Minecart) Update(dt float64) {
+	c.motion.startTick(c.Transform())
+	if c.Dead {
+		return
+	}
+	if c.rider == nil {
+		c.forward = 0
+	}
+
+	if !c.OnRail {
+		c.tryLatch()
+	}
+	if !c.OnRail {
+		c.updateOffRail(dt)
+		c.tryLatch()
+	}
+	if c.OnRail {
+		c.updateOnRail(dt)
+	}
+
+	if c.rider != nil {
+		c.rider.FollowVehicle(c.SeatPosition())
+	}
+}
+
+// updateOnRail moves the cart along its rail, crossing into connected rails
+// and flying off the end of the track.
+func (c *Minecart) updateOnRail(dt float64) {
+	shape := c.railShape()
+	tangent := railPathTangent(shape, c.railT)
+
+	if shape.IsAscending() {
+		c.speed -= cartSlopeAccel * float32(dt) // the first exit is the low end
+	}
+	if c.forward != 0 {
+		facing := mgl32.Vec3{float32(math.Cos(float64(mgl32.DegToRad(c.Yaw)))), 0, float32(math.Sin(float64(mgl32.DegToRad(c.Yaw))))}
+		along := float32(1)
+		if tangent.Dot(facing) < 0 {
+			along = -1
+		}
+		c.speed += c.forward * along * cartPushAccel * float32(dt)
+	}
+
+	drag := cartEmptyDrag
+	if c.rider != nil {
+		drag = cartRiddenDrag
+	}
+	c.speed *= float32(math.Pow(drag, dt*20))
+	c.speed = max(-cartMaxSpeed, min(cartMaxSpeed, c.speed))
+
+	c.railT += c.speed * float32(dt) / railPathLength(shape)
+	for c.railT < 0 || c.railT > 1 {
+		if !c.advanceRail() {
+			return
+		}
+	}
+
+	shape = c.railShape()
+	c.Pos = mgl32.Vec3{float32(c.railX), float32(c.railY) + cartRideHeight, float32(c.railZ)}.Add(railPathPoint(shape, c.railT))
+	if dir := railPathTangent(shape, c.railT).Mul(c.speed); dir.X() != 0 || dir.Z() != 0 {
+		c.Yaw = mgl32.RadToDeg(float32(math.Atan2(float64(dir.Z()), float64(dir.X()))))
+	}
+}
+
+// advanceRail carries the part of the move past the end of the current rail
+// onto the rail connected there. With no rail to continue on, the cart leaves
+// the track at its current velocity and false is returned.
+func (c *Minecart) advanceRail() bool {
+	shape := c.railShape()
+	length := railPathLength(shape)
+	a, b := shape.Exits()
+
+	exit, overshoot := a, -c.railT*length
+	if c.railT > 1 {
+		exit, overshoot = b, (c.railT-1)*length
+	}
+
+	// Leaving the raised end of a slope leads one block up; a flat end may
+	// lead onto a slope down
+	dx, dz := world.FacingOffset(uint8(exit))
+	dys := []int{0, -1}
+	if shape.IsAscending() && exit == b {
+		dys = []int{1}
+	}
+	entry := exit.Opposite()
+	for _, dy := range dys {
+		nx, ny, nz := c.railX+dx, c.railY+dy, c.railZ+dz
+		if c.World.Get(nx, ny, nz) != world.BlockTypeRail {
+			continue
+		}
+		next := world.RailShape(c.World.GetMeta(nx, ny, nz))
+		na, nb := next.Exits()
+		if na != entry && nb != entry {
+			continue
+		}
+		speed := abs32(c.speed)
+		c.railX, c.railY, c.railZ = nx, ny, nz
+		if na == entry {
+			c.railT, c.speed = overshoot/railPathLength(next), speed
+		} else {
+			c.railT, c.speed = 1-overshoot/railPathLength(next), -speed
+		}
+		return true
+	}
+
+	// Start just past the edge so the cart does not latch straight back on
+	c.railT = max(0, min(1, c.railT))
+	c.Vel = railPathTangent(shape, c.railT).Mul(c.speed)
+	c.Pos = mgl32.Vec3{float32(c.railX), float32(c.railY) + cartRideHeight, float32(c.railZ)}.
+		Add(railPathPoint(shape, c.railT)).
+		Add(mgl32.Vec3{float32(dx), 0, float32(dz)}.Mul(0.01))
+	c.OnRail = false
+	return false
+}
+
+// updateOffRail applies gravity, ground friction and block collisions.
+func (c *Minecart) updateOffRail(dt float64) {
+	c.Vel[1] -= cartGravity * float32(dt)
+	if boxCollides(c.World, c.Pos.X(), c.Pos.Y()-0.05, c.Pos.Z(), MinecartWidth, MinecartHeight) {
+		drag := float32(math.Pow(cartGroundDrag, dt*20))
+		c.Vel[0] *= drag
+		c.Vel[2] *= drag
+	}
+
+	delta := c.Vel.Mul(float32(dt))
+	for axis := 0; axis < 3; axis++ {
+		next := c.Pos
+		next[axis] += delta[axis]
+		if boxCollides(c.World, next.X(), next.Y(), next.Z(), MinecartWidth, MinecartHeight) {
+			c.Vel[axis] = 0
+		} else {
+			c.Pos = next
+		}
+	}
+}
+
+// tryLatch puts the cart on the rail it is over, at the nearest point of the
+// track, keeping the part of its velocity that runs along it.
+func (c *Minecart) tryLatch() {
+	x := int(math.Floor(float64(c.Pos.X())))
+	y := int(math.Floor(float64(c.Pos.Y() + cartRideHeight)))
+	z := int(math.Floor(float64(c.Pos.Z())))
+	if c.World.Get(x, y, z) != world.BlockTypeRail {
+		return
+	}
+	shape := world.RailShape(c.World.GetMeta(x, y, z))
+
+	local := c.Pos.Sub(mgl32.Vec3{float32(x), float32(y) + cartRideHeight, float32(z)})
+	bestT, bestDist := float32(0), float32(math.Inf(1))
+	for i := 0; i <= cartSnapSamples; i++ {
+		t := float32(i) / cartSnapSamples
+		if d := railPathPoint(shape, t).Sub(local).Len(); d < bestDist {
+			bestT, bestDist = t, d
+		}
+	}
+
+	c.railX, c.railY, c.railZ = x, y, z
+	c.railT = bestT
+	c.speed = c.Vel.Dot(railPathTangent(shape, bestT))
+	c.Vel = mgl32.Vec3{}
+	c.OnRail = true
+}
+
+func (c *Minecart) railShape() world.RailShape {
+	return world.RailShape(c.World.GetMeta(c.railX, c.railY, c.railZ))
+}
+
+// railExitPoint returns where track leaves a block through face f, in
+// block-local coordinates.
+func railExitPoint(f world.BlockFace) mgl32.Vec3 {
+	switch f {
+	case world.FaceNorth:
+		return mgl32.Vec3{0.5, 0, 1}
+	case world.FaceSouth:
+		return mgl32.Vec3{0.5, 0, 0}
+	case world.FaceEast:
+		return mgl32.Vec3{1, 0, 0.5}
+	default:
+		return mgl32.Vec3{0, 0, 0.5}
+	}
+}
+
+// railPathPoint returns the point at t along a rail's path, in block-local
+// coordinates: a straight line (sloped for ascending rails) or a quarter
+// circle around the corner a curve turns.
+func railPathPoint(shape world.RailShape, t float32) mgl32.Vec3 {
+	a, b := shape.Exits()
+	pa, pb := railExitPoint(a), railExitPoint(b)
+	if shape.IsAscending() {
+		pb[1] = 1
+	}
+	if !shape.IsCurve() {
+		return pa.Add(pb.Sub(pa).Mul(t))
+	}
+	corner := pa.Add(pb).Sub(mgl32.Vec3{0.5, 0, 0.5})
+	angle := float64(t) * math.Pi / 2
+	return corner.
+		Add(pa.Sub(corner).Mul(float32(math.Cos(angle)))).
+		Add(pb.Sub(corner).Mul(float32(math.Sin(angle))))
+}
+
+// railPathTangent returns the unit direction of travel at t towards the
+// path's second exit.
+func railPathTangent(shape world.RailShape, t float32) mgl32.Vec3 {
+	const h = 0.01
+	t0, t1 := max(0, t-h), min(1, t+h)
+	d := railPathPoint(shape, t1).Sub(railPathPoint(shape, t0))
+	if d.Len() == 0 {
+		return mgl32.Vec3{}
+	}
+	return d.Normalize()
+}
+
+// railPathLength returns the length of a rail's path in blocks.
+func railPathLength(shape world.RailShape) float32 {
+	switch {
+	case shape.IsCurve():
+		return math.Pi / 4
+	case shape.IsAscending():
+		return math.Sqrt2
+	}
+	return 1
+}
+
+func abs32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// SeatPosition returns where the rider's feet go.
+func (c *Minecart) SeatPosition() mgl32.Vec3 {
+	return c.Pos.Add(mgl32.Vec3{0, cartSeatHeight, 0})
+}
+
+func (c *Minecart) Rider() Rider { return c.rider }
+
+func (c *Minecart) SetRider(r Rider) { c.rider = r }
+
+func (c *Minecart) Position() mgl32.Vec3 { return c.Pos }
+
+func (c *Minecart) Transform() Transform { return Transform{Pos: c.Pos, Yaw: c.Yaw} }
+
+func (c *Minecart) PrevTransform() Transform { return c.motion.prevOr(c.Transform()) }
+
+func (c *Minecart) Idle() { c.motion.startTick(c.Transform()) }
+
+func (c *Minecart) IsDead() bool { return c.Dead }
+
+func (c *Minecart) SetDead() { c.Dead = true }
+
+func (c *Minecart) GetBounds() (width, height float32) { return MinecartWidth, MinecartHeight }
+
+func (c *Minecart) AABB() AABB { return boundsAABB(c.Pos, MinecartWidth, MinecartHeight) }
+
+func (c *Minecart) Model() world.BlockType { return world.BlockTypeMinecart }
+
+// Push nudges the cart; on a rail only the part along the track counts.
+func (c *Minecart) Push(dvx, dvz float32) {
+	if c.OnRail {
+		c.speed += railPathTangent(c.railShape(), c.railT).Dot(mgl32.Vec3{dvx, 0, dvz})
+		return
+	}
+	c.Vel[0] += dvx
+	c.Vel[2] += dvz
+}
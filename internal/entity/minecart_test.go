@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// track is a sparse world of rails over a stone floor at y = 0.
+type track map[[3]int]world.RailShape
+
+func (t track) Get(x, y, z int) world.BlockType {
+	if _, ok := t[[3]int{x, y, z}]; ok {
+		return world.BlockTypeRail
+	}
+	if y <= 0 {
+		return world.BlockTypeStone
+	}
+	return world.BlockTypeAir
+}
+
+func (t track) IsAir(x, y, z int) bool { return t.Get(x, y, z) == world.BlockTypeAir }
+
+func (t track) GetMeta(x, y, z int) uint8 { return uint8(t[[3]int{x, y, z}]) }
+
+func TestMinecartFollowsCurve(t *testing.T) {
+	world.BlockSolidTable[world.BlockTypeStone] = true
+
+	// East along z = 0, then a curve at x = 3 turning north (+Z) up to z = 3
+	rails := track{
+		{0, 1, 0}: world.RailEastWest,
+		{1, 1, 0}: world.RailEastWest,
+		{2, 1, 0}: world.RailEastWest,
+		{3, 1, 0}: world.RailNorthWest,
+		{3, 1, 1}: world.RailNorthSouth,
+		{3, 1, 2}: world.RailNorthSouth,
+		{3, 1, 3}: world.RailNorthSouth,
+	}
+	c := NewMinecart(rails, mgl32.Vec3{0.5, 1, 0.5}, 0)
+	c.Update(0)
+	if !c.OnRail {
+		t.Fatal("cart did not latch onto the rail")
+	}
+	c.speed = 6
+
+	for range 20 {
+		c.Update(0.05)
+		if !c.OnRail {
+			t.Fatalf("cart left the track at %v", c.Pos)
+		}
+	}
+	if c.railX != 3 || c.railZ < 1 {
+		t.Fatalf("cart at rail (%d, %d), want past the curve on x = 3", c.railX, c.railZ)
+	}
+	if x := c.Pos.X(); x < 3.45 || x > 3.55 {
+		t.Fatalf("cart drifted off the track centre: x = %v", x)
+	}
+}
+
+func TestMinecartRollsDownSlope(t *testing.T) {
+	world.BlockSolidTable[world.BlockTypeStone] = true
+
+	rails := track{
+		{0, 1, 0}: world.RailEastWest,
+		{1, 1, 0}: world.RailAscendingEast,
+		{2, 2, 0}: world.RailEastWest,
+	}
+	c := NewMinecart(rails, mgl32.Vec3{1.5, 1.5, 0.5}, 0)
+	for range 40 {
+		c.Update(0.05)
+	}
+	if c.Pos.X() >= 1 || c.Pos.Y() > 1.1 {
+		t.Fatalf("cart did not roll down the slope: %v", c.Pos)
+	}
+}
@@ -0,0 +1,173 @@
+package entity
+
+import (
+	"math"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// MobKind is a kind of passive mob.
+type MobKind uint8
+
+const (
+	MobPig MobKind = iota
+	MobChicken
+)
+
+// mobSpec is what differs between the kinds of mob. Speeds are in blocks
+// per second.
+type mobSpec struct {
+	typeID        world.EntityTypeID
+	width, height float32
+	walkSpeed     float32
+	maxFallSpeed  float32 // chickens flap their wings to fall slowly
+	maxHealth     float32 // hit points, as in MC
+}
+
+var mobSpecs = [...]mobSpec{
+	MobPig:     {typeID: TypePig, width: 0.9, height: 0.9, walkSpeed: 1.2, maxFallSpeed: 60, maxHealth: 10},
+	MobChicken: {typeID: TypeChicken, width: 0.4, height: 0.7, walkSpeed: 1.0, maxFallSpeed: 2, maxHealth: 4},
+}
+
+// Wandering: a mob stands for a while, then walks a random way for a few
+// seconds. It stops and turns around rather than step off a drop of more
+// than mobMaxDrop blocks or into water, and hops up single blocks.
+const (
+	mobGravity   = 18.0
+	mobJumpSpeed = 7.0
+	mobMaxDrop   = 1
+	mobMinIdle   = 2.0 // seconds
+	mobMaxIdle   = 8.0
+	mobMinWalk   = 1.0
+	mobMaxWalk   = 4.0
+	mobDrag      = 0.6 // per tick on the ground, of velocity not from walking
+)
+
+// Mob is a passive animal that wanders about. Hit, it flashes and is
+// knocked back; out of health, it falls over and is removed once it lies
+// on its side.
+type Mob struct {
+	walker
+	HurtAnim
+	Kind   MobKind
+	Yaw    float32 // degrees, same convention as the player's CamYaw
+	World  *world.World
+	Dead   bool
+	Health float32
+
+	walkTime float64 // seconds of walking left; standing when <= 0
+	idleTime float64 // seconds left standing before the next walk
+
+	motion motion
+}
+
+// NewMob creates a mob of the given kind at pos facing yaw. It stands for a
+// moment before wandering off.
+func NewMob(w *world.World, kind MobKind, pos mgl32.Vec3, yaw float32) *Mob {
+	return &Mob{walker: walker{Pos: pos}, Kind: kind, Yaw: yaw, World: w, Health: mobSpecs[kind].maxHealth, idleTime: mobMinIdle}
+}
+
+func (m *Mob) spec() mobSpec { return mobSpecs[m.Kind] }
+
+// Heading returns the unit XZ direction the mob faces.
+func (m *Mob) Heading() mgl32.Vec3 {
+	yaw := float64(mgl32.DegToRad(m.Yaw))
+	return mgl32.Vec3{float32(math.Cos(yaw)), 0, float32(math.Sin(yaw))}
+}
+
+func (m *Mob) Update(dt float64) {
+	m.motion.startTick(m.Transform())
+	if m.Dead {
+		return
+	}
+	m.TickHurt(dt)
+	spec := m.spec()
+	if m.Dying() {
+		m.move(m.World, spec, mgl32.Vec3{}, dt)
+		return
+	}
+	m.wander(dt)
+
+	walk := mgl32.Vec3{}
+	if m.walkTime > 0 {
+		walk = m.Heading().Mul(spec.walkSpeed)
+	}
+	if m.move(m.World, spec, walk, dt) && m.OnGround && m.walkTime > 0 {
+		m.Vel[1] = mobJumpSpeed
+	}
+}
+
+// wander advances the mob's standing and walking, choosing a new way to
+// walk when it is done standing and turning around at edges.
+func (m *Mob) wander(dt float64) {
+	rnd := m.World.Rand()
+	if m.walkTime > 0 {
+		m.walkTime -= dt
+		if m.OnGround && m.edgeAhead() {
+			m.Yaw = float32(math.Mod(float64(m.Yaw)+180+(rnd.Float64()-0.5)*90, 360))
+			m.walkTime = 0
+		}
+		if m.walkTime <= 0 {
+			m.idleTime = mobMinIdle + rnd.Float64()*(mobMaxIdle-mobMinIdle)
+		}
+		return
+	}
+	m.idleTime -= dt
+	if m.idleTime <= 0 {
+		m.Yaw = float32(rnd.Float64() * 360)
+		m.walkTime = mobMinWalk + rnd.Float64()*(mobMaxWalk-mobMinWalk)
+	}
+}
+
+// edgeAhead reports whether the ground just ahead of the mob drops more
+// than mobMaxDrop blocks or is water.
+func (m *Mob) edgeAhead() bool {
+	ahead := m.Pos.Add(m.Heading().Mul(m.spec().width/2 + 0.4))
+	x := int(math.Floor(float64(ahead.X())))
+	y := int(math.Floor(float64(m.Pos.Y())))
+	z := int(math.Floor(float64(ahead.Z())))
+	for dy := 0; dy <= mobMaxDrop+1; dy++ {
+		bt := m.World.Get(x, y-dy, z)
+		if bt == world.BlockTypeWater {
+			return true
+		}
+		if world.BlockSolidTable[bt] {
+			// Solid at feet height is a step up, which the mob hops
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Mob) Position() mgl32.Vec3 { return m.Pos }
+
+func (m *Mob) Transform() Transform { return Transform{Pos: m.Pos, Yaw: m.Yaw} }
+
+func (m *Mob) PrevTransform() Transform { return m.motion.prevOr(m.Transform()) }
+
+func (m *Mob) Idle() { m.motion.startTick(m.Transform()) }
+
+func (m *Mob) IsDead() bool { return m.Dead || m.DeathDone() }
+
+func (m *Mob) Damage(amount float32, from mgl32.Vec3) bool {
+	return m.takeDamage(&m.HurtAnim, &m.Health, amount, from)
+}
+
+func (m *Mob) SetDead() { m.Dead = true }
+
+func (m *Mob) GetBounds() (width, height float32) {
+	spec := m.spec()
+	return spec.width, spec.height
+}
+
+func (m *Mob) AABB() AABB {
+	spec := m.spec()
+	return boundsAABB(m.Pos, spec.width, spec.height)
+}
+
+var (
+	_ world.Body = (*Mob)(nil)
+	_ Damageable = (*Mob)(nil)
+)
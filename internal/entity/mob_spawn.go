@@ -0,0 +1,179 @@
+package entity
+
+import (
+	"math"
+
+	"mini-mc/internal/mathutil"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Passive mobs spawn in small groups on grass a little way from the player,
+// until mobCap of them are around. Like 1.8.9 animals they need light above
+// 8 where they stand, and none live on oceans, deserts or ice plains.
+const (
+	mobSpawnInterval = 100 // ticks between spawn attempts
+	mobSpawnMinDist  = 24  // blocks from the focus
+	mobSpawnMaxDist  = 64
+	mobCap           = 12 // mobs within mobSpawnMaxDist of the focus
+	mobMinLight      = 9
+	mobGroupMin      = 2
+	mobGroupMax      = 4
+	mobGroupSpread   = 2 // blocks either way from the group's centre
+)
+
+// Zombies spawn one at a time in the dark around the target, on a floor
+// within zombieSpawnRise blocks of its height, until zombieCap of them are
+// around. Those left further than zombieDespawnDist away despawn.
+const (
+	zombieSpawnInterval = 40 // ticks between spawn attempts
+	zombieSpawnMinDist  = 24 // blocks from the target
+	zombieSpawnMaxDist  = 48
+	zombieSpawnRise     = 16
+	zombieCap           = 8
+	zombieDespawnDist   = 128
+	hostileMaxLight     = 7
+)
+
+// MobSpawner spawns passive mobs around a focus, usually the player, as the
+// game ticks, and zombies around its Target if it has one. Its zero value
+// is ready to use.
+type MobSpawner struct {
+	Target Target
+	ticks  int
+}
+
+// Tick runs one game tick of spawning around (x, z).
+func (s *MobSpawner) Tick(w *world.World, x, z float32) {
+	s.ticks++
+	if s.Target != nil && s.ticks%zombieSpawnInterval == 0 {
+		s.tickZombies(w)
+	}
+	if s.ticks%mobSpawnInterval != 0 || mobsAround(w, x, z) >= mobCap {
+		return
+	}
+	rnd := w.Rand()
+	angle := rnd.Float64() * 2 * math.Pi
+	dist := mobSpawnMinDist + rnd.Float64()*(mobSpawnMaxDist-mobSpawnMinDist)
+	cx := int(math.Floor(float64(x) + math.Cos(angle)*dist))
+	cz := int(math.Floor(float64(z) + math.Sin(angle)*dist))
+
+	kinds := MobsFor(world.GetBiomeForCoords(float64(cx), float64(cz), w.Seed()))
+	if len(kinds) == 0 {
+		return
+	}
+	kind := kinds[rnd.Intn(len(kinds))]
+	for range mobGroupMin + rnd.Intn(mobGroupMax-mobGroupMin+1) {
+		bx := cx + rnd.Intn(2*mobGroupSpread+1) - mobGroupSpread
+		bz := cz + rnd.Intn(2*mobGroupSpread+1) - mobGroupSpread
+		y, ok := MobSpawnHeight(w, bx, bz)
+		if !ok {
+			continue
+		}
+		pos := mgl32.Vec3{float32(bx) + 0.5, float32(y), float32(bz) + 0.5}
+		w.AddEntity(NewMob(w, kind, pos, rnd.Float32()*360))
+	}
+}
+
+// tickZombies despawns the zombies that strayed too far from the target and
+// tries to spawn another near it.
+func (s *MobSpawner) tickZombies(w *world.World) {
+	pos := s.Target.FeetPosition()
+	n := 0
+	for _, e := range w.GetEntities() {
+		zombie, ok := e.(*Zombie)
+		if !ok || zombie.IsDead() {
+			continue
+		}
+		switch d := horizontalDist(zombie.Pos, pos); {
+		case d > zombieDespawnDist:
+			zombie.SetDead()
+		case d <= zombieSpawnMaxDist:
+			n++
+		}
+	}
+	if n >= zombieCap {
+		return
+	}
+	rnd := w.Rand()
+	angle := rnd.Float64() * 2 * math.Pi
+	dist := zombieSpawnMinDist + rnd.Float64()*(zombieSpawnMaxDist-zombieSpawnMinDist)
+	bx := int(math.Floor(float64(pos.X()) + math.Cos(angle)*dist))
+	bz := int(math.Floor(float64(pos.Z()) + math.Sin(angle)*dist))
+	if y, ok := ZombieSpawnHeight(w, bx, bz, int(math.Floor(float64(pos.Y())))); ok {
+		spawn := mgl32.Vec3{float32(bx) + 0.5, float32(y), float32(bz) + 0.5}
+		w.AddEntity(NewZombie(w, s.Target, spawn, rnd.Float32()*360))
+	}
+}
+
+// mobsAround counts the live mobs within mobSpawnMaxDist of (x, z).
+func mobsAround(w *world.World, x, z float32) int {
+	n := 0
+	for _, e := range w.GetEntitiesInAABB(x-mobSpawnMaxDist, 0, z-mobSpawnMaxDist, x+mobSpawnMaxDist, world.ChunkSizeY, z+mobSpawnMaxDist) {
+		if _, ok := e.(*Mob); ok {
+			n++
+		}
+	}
+	return n
+}
+
+// MobsFor returns the kinds of passive mob that live in biome b.
+func MobsFor(b *world.Biome) []MobKind {
+	switch b {
+	case world.BiomeOcean, world.BiomeDeepOcean, world.BiomeDesert, world.BiomeIcePlains:
+		return nil
+	}
+	return []MobKind{MobPig, MobChicken}
+}
+
+// MobSpawnHeight returns the height a passive mob may spawn at in column
+// (x, z): on top of the column's highest solid block, which must be grass,
+// with dry room above lit at least mobMinLight. ok is false when the column
+// is unloaded or fails any of that.
+func MobSpawnHeight(w *world.World, x, z int) (y int, ok bool) {
+	cx, cz := mathutil.FloorDiv(x, world.ChunkSizeX), mathutil.FloorDiv(z, world.ChunkSizeZ)
+	if w.GetChunk(cx, 0, cz, false) == nil {
+		return 0, false
+	}
+	y = world.ChunkSizeY - 1
+	for y > 0 && !world.BlockSolidTable[w.Get(x, y, z)] {
+		y--
+	}
+	if w.Get(x, y, z) != world.BlockTypeGrass {
+		return 0, false
+	}
+	y++
+	for dy := range 2 {
+		if w.Get(x, y+dy, z) == world.BlockTypeWater {
+			return 0, false
+		}
+	}
+	if max(w.SkyLight(x, y, z), w.BlockLight(x, y, z)) < mobMinLight {
+		return 0, false
+	}
+	return y, true
+}
+
+// ZombieSpawnHeight returns the height a zombie may spawn at in column
+// (x, z): the highest floor within zombieSpawnRise blocks of y that has two
+// blocks of dry room above lit no more than hostileMaxLight, counting sky
+// light as the time of day dims it. ok is false when the column is unloaded
+// or has no such floor.
+func ZombieSpawnHeight(w *world.World, x, z, y int) (int, bool) {
+	cx, cz := mathutil.FloorDiv(x, world.ChunkSizeX), mathutil.FloorDiv(z, world.ChunkSizeZ)
+	if w.GetChunk(cx, 0, cz, false) == nil {
+		return 0, false
+	}
+	dark := w.SkyDarkening()
+	for fy := min(y+zombieSpawnRise, world.ChunkSizeY-2); fy >= max(y-zombieSpawnRise, 1); fy-- {
+		if !world.BlockSolidTable[w.Get(x, fy-1, z)] || !pathOpen(w, x, fy, z) {
+			continue
+		}
+		sky := float32(w.SkyLight(x, fy, z)) - dark
+		if max(sky, float32(w.BlockLight(x, fy, z))) <= hostileMaxLight {
+			return fy, true
+		}
+	}
+	return 0, false
+}
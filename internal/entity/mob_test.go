@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestMobWandersWithoutFallingOff(t *testing.T) {
+	world.BlockSolidTable[world.BlockTypeStone] = true
+	w := world.New()
+	defer w.Close()
+	// A 5x5 platform floating over nothing
+	for x := 2; x <= 6; x++ {
+		for z := 2; z <= 6; z++ {
+			w.Set(x, 63, z, world.BlockTypeStone)
+		}
+	}
+
+	pig := NewMob(w, MobPig, mgl32.Vec3{4.5, 64, 4.5}, 0)
+	walked := false
+	for range 2000 {
+		pig.Update(0.05)
+		if pig.Pos.Y() < 63.9 || pig.Pos.X() < 1.6 || pig.Pos.X() > 7.4 || pig.Pos.Z() < 1.6 || pig.Pos.Z() > 7.4 {
+			t.Fatalf("pig left the platform at %v", pig.Pos)
+		}
+		walked = walked || pig.LimbAmount > 0.5
+	}
+	if !walked {
+		t.Fatal("pig never walked")
+	}
+}
+
+func TestMobSpawnRules(t *testing.T) {
+	world.BlockSolidTable[world.BlockTypeStone] = true
+	world.BlockSolidTable[world.BlockTypeGrass] = true
+	opacity := world.BlockLightOpacity
+	t.Cleanup(func() { world.BlockLightOpacity = opacity })
+	world.BlockLightOpacity[world.BlockTypeStone] = world.MaxLight
+
+	w := world.New()
+	defer w.Close()
+	open := world.NewChunk(0, 0, 0)
+	open.SetBlock(2, 63, 2, world.BlockTypeGrass)
+	open.SetBlock(5, 63, 5, world.BlockTypeStone)
+	w.InstallChunk(open)
+	// Grass under a roof over the whole chunk is too dark
+	roofed := world.NewChunk(2, 0, 0)
+	for x := range world.ChunkSizeX {
+		for z := range world.ChunkSizeZ {
+			roofed.SetBlock(x, 66, z, world.BlockTypeStone)
+		}
+	}
+	roofed.SetBlock(8, 63, 8, world.BlockTypeGrass)
+	w.InstallChunk(roofed)
+
+	if y, ok := MobSpawnHeight(w, 2, 2); !ok || y != 64 {
+		t.Fatalf("open grass: %d, %v; want 64, true", y, ok)
+	}
+	for _, c := range [][2]int{{5, 5}, {2*world.ChunkSizeX + 8, 8}, {5 * world.ChunkSizeX, 0}} {
+		if y, ok := MobSpawnHeight(w, c[0], c[1]); ok {
+			t.Fatalf("mob may spawn at %v, y=%d", c, y)
+		}
+	}
+
+	if len(MobsFor(world.BiomeDesert)) != 0 || len(MobsFor(world.BiomeOcean)) != 0 {
+		t.Fatal("mobs live in deserts or oceans")
+	}
+	if len(MobsFor(world.BiomePlains)) == 0 {
+		t.Fatal("no mobs live on plains")
+	}
+}
@@ -0,0 +1,155 @@
+package entity
+
+import (
+	"container/heap"
+
+	"mini-mc/internal/world"
+)
+
+// Paths are found over the block grid, a node being the block a walker's
+// feet are in. From a node it may walk to any of the four blocks beside it
+// on the same level, step up one block where there is headroom, or drop up
+// to pathMaxDrop blocks. Nodes need two blocks of room, so paths suit
+// walkers up to two blocks tall and a block wide.
+const (
+	pathMaxDrop  = 3
+	pathMaxNodes = 600 // nodes expanded before giving up on the goal
+)
+
+var pathSteps = [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+
+// pathNode is a node queued in an A* search.
+type pathNode struct {
+	pos    world.BlockPos
+	g, f   int // cost from the start, and that plus the estimate to the goal
+	parent *pathNode
+	index  int // in the open heap, -1 once closed
+}
+
+type pathHeap []*pathNode
+
+func (h pathHeap) Len() int { return len(h) }
+
+func (h pathHeap) Less(i, j int) bool { return h[i].f < h[j].f }
+
+func (h pathHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *pathHeap) Push(x interface{}) {
+	n := x.(*pathNode)
+	n.index = len(*h)
+	*h = append(*h, n)
+}
+
+func (h *pathHeap) Pop() interface{} {
+	old := *h
+	n := old[len(old)-1]
+	old[len(old)-1] = nil
+	n.index = -1
+	*h = old[:len(old)-1]
+	return n
+}
+
+// FindPath searches for a walk from the node start to goal with A*. It
+// returns the nodes to pass through after start, ending at goal. When goal
+// can't be reached within pathMaxNodes it returns the way to the node it
+// found nearest the goal, which is empty if that is start itself.
+func FindPath(w WorldSource, start, goal world.BlockPos) []world.BlockPos {
+	nodes := map[world.BlockPos]*pathNode{}
+	open := &pathHeap{}
+	first := &pathNode{pos: start, f: pathEstimate(start, goal)}
+	nodes[start] = first
+	heap.Push(open, first)
+
+	best := first
+	for expanded := 0; open.Len() > 0 && expanded < pathMaxNodes; expanded++ {
+		n := heap.Pop(open).(*pathNode)
+		if n.pos == goal {
+			best = n
+			break
+		}
+		if h := n.f - n.g; h < best.f-best.g {
+			best = n
+		}
+		for _, s := range pathSteps {
+			next, ok := pathStep(w, n.pos, s[0], s[1])
+			if !ok {
+				continue
+			}
+			g := n.g + 1 + absInt(next.Y-n.pos.Y)
+			m, seen := nodes[next]
+			if seen && g >= m.g {
+				continue
+			}
+			if !seen {
+				m = &pathNode{pos: next, index: -1}
+				nodes[next] = m
+			}
+			m.g, m.f, m.parent = g, g+pathEstimate(next, goal), n
+			if m.index >= 0 {
+				heap.Fix(open, m.index)
+			} else {
+				heap.Push(open, m)
+			}
+		}
+	}
+
+	var path []world.BlockPos
+	for n := best; n.parent != nil; n = n.parent {
+		path = append(path, n.pos)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// pathStep returns the node reached walking from p one block along (dx,
+// dz): level, a step up or a drop. ok is false when the way is blocked.
+func pathStep(w WorldSource, p world.BlockPos, dx, dz int) (next world.BlockPos, ok bool) {
+	x, z := p.X+dx, p.Z+dz
+	if !pathOpen(w, x, p.Y, z) {
+		// A step up needs room over the walker's head to jump
+		if pathOpen(w, x, p.Y+1, z) && pathPassable(w.Get(p.X, p.Y+2, p.Z)) {
+			return world.BlockPos{X: x, Y: p.Y + 1, Z: z}, true
+		}
+		return next, false
+	}
+	for y := p.Y; y >= p.Y-pathMaxDrop; y-- {
+		if y < p.Y && !pathOpen(w, x, y, z) {
+			break
+		}
+		if world.BlockSolidTable[w.Get(x, y-1, z)] {
+			return world.BlockPos{X: x, Y: y, Z: z}, true
+		}
+	}
+	return next, false
+}
+
+// pathOpen reports whether a walker's feet fit in block (x, y, z), with its
+// head in the block above.
+func pathOpen(w WorldSource, x, y, z int) bool {
+	return pathPassable(w.Get(x, y, z)) && pathPassable(w.Get(x, y+1, z))
+}
+
+// pathPassable reports whether a walker may be in a block of type bt. It
+// keeps out of water and lava as well as solid blocks.
+func pathPassable(bt world.BlockType) bool {
+	return !world.BlockSolidTable[bt] && bt != world.BlockTypeWater && bt != world.BlockTypeLava
+}
+
+// pathEstimate is the A* heuristic: the Manhattan distance, which never
+// overestimates as each step costs at least one.
+func pathEstimate(a, b world.BlockPos) int {
+	return absInt(a.X-b.X) + absInt(a.Y-b.Y) + absInt(a.Z-b.Z)
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+)
+
+// floorWorld returns a world with a stone floor at y = 63 over x and z
+// from -8 to 8, so feet nodes on it are at y = 64.
+func floorWorld(t *testing.T) *world.World {
+	t.Helper()
+	world.BlockSolidTable[world.BlockTypeStone] = true
+	w := world.New()
+	t.Cleanup(w.Close)
+	for x := -8; x <= 8; x++ {
+		for z := -8; z <= 8; z++ {
+			w.Set(x, 63, z, world.BlockTypeStone)
+		}
+	}
+	return w
+}
+
+func TestFindPathAroundWall(t *testing.T) {
+	w := floorWorld(t)
+	for z := -3; z <= 3; z++ {
+		w.Set(0, 64, z, world.BlockTypeStone)
+		w.Set(0, 65, z, world.BlockTypeStone)
+	}
+
+	start, goal := world.BlockPos{X: -3, Y: 64}, world.BlockPos{X: 3, Y: 64}
+	path := FindPath(w, start, goal)
+	if len(path) == 0 || path[len(path)-1] != goal {
+		t.Fatalf("path %v does not reach %v", path, goal)
+	}
+	prev := start
+	for _, p := range path {
+		if pathEstimate(prev, p) != 1 {
+			t.Fatalf("path jumps from %v to %v", prev, p)
+		}
+		if !pathOpen(w, p.X, p.Y, p.Z) {
+			t.Fatalf("path goes through the wall at %v", p)
+		}
+		prev = p
+	}
+	// Around the end of the wall and back: 6 across, 4+4 along
+	if len(path) != 14 {
+		t.Fatalf("path of %d steps, want 14: %v", len(path), path)
+	}
+}
+
+func TestFindPathClimbsSteps(t *testing.T) {
+	w := floorWorld(t)
+	// A one-block step up onto a ledge, then a wall two blocks higher still
+	for z := -8; z <= 8; z++ {
+		for x := 2; x <= 8; x++ {
+			w.Set(x, 64, z, world.BlockTypeStone)
+		}
+		w.Set(5, 65, z, world.BlockTypeStone)
+		w.Set(5, 66, z, world.BlockTypeStone)
+	}
+
+	path := FindPath(w, world.BlockPos{Y: 64}, world.BlockPos{X: 4, Y: 65})
+	if want := (world.BlockPos{X: 4, Y: 65}); len(path) != 4 || path[len(path)-1] != want {
+		t.Fatalf("path %v, want 4 steps up to %v", path, want)
+	}
+
+	// Beyond the wall it gets as close as it can
+	path = FindPath(w, world.BlockPos{Y: 64}, world.BlockPos{X: 7, Y: 65})
+	if want := (world.BlockPos{X: 4, Y: 65}); len(path) == 0 || path[len(path)-1] != want {
+		t.Fatalf("partial path %v, want to end at %v", path, want)
+	}
+}
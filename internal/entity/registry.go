@@ -0,0 +1,175 @@
+package entity
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"mini-mc/internal/item"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Entity type IDs, as saved with chunks.
+const (
+	TypeItem     world.EntityTypeID = "item"
+	TypeBoat     world.EntityTypeID = "boat"
+	TypeMinecart world.EntityTypeID = "minecart"
+	TypePig      world.EntityTypeID = "pig"
+	TypeChicken  world.EntityTypeID = "chicken"
+)
+
+func init() {
+	// Items need a stack, so they are only dropped, never spawned by type
+	world.RegisterEntityType(world.EntityType{ID: TypeItem, Load: loadItemEntity})
+	world.RegisterEntityType(world.EntityType{
+		ID:   TypeBoat,
+		New:  func(w *world.World, pos mgl32.Vec3) world.Ticker { return NewBoat(w, pos, 0) },
+		Load: loadBoat,
+	})
+	world.RegisterEntityType(world.EntityType{
+		ID:   TypeMinecart,
+		New:  func(w *world.World, pos mgl32.Vec3) world.Ticker { return NewMinecart(w, pos, 0) },
+		Load: loadMinecart,
+	})
+	for i, spec := range mobSpecs {
+		kind := MobKind(i)
+		world.RegisterEntityType(world.EntityType{
+			ID:  spec.typeID,
+			New: func(w *world.World, pos mgl32.Vec3) world.Ticker { return NewMob(w, kind, pos, 0) },
+			Load: func(w *world.World, data []byte) (world.Ticker, error) {
+				return loadMob(w, kind, data)
+			},
+		})
+	}
+}
+
+// Saved entities are their state structs below in little-endian order; an
+// item's owner follows its struct.
+
+type itemState struct {
+	Pos, Vel    mgl32.Vec3
+	Type        world.BlockType
+	NoDespawn   bool
+	Count       int32
+	Damage      int32
+	Age         float64
+	HoverStart  float64
+	RotationYaw float64
+	PickupDelay float64
+}
+
+type bodyState struct {
+	Pos, Vel mgl32.Vec3
+	Yaw      float32
+}
+
+func (e *ItemEntity) EntityType() world.EntityTypeID { return TypeItem }
+
+// SaveEntity returns the item's stack, motion and timers. Its pickup
+// animation is not saved: an item being picked up is next to the player.
+func (e *ItemEntity) SaveEntity() []byte {
+	data, _ := binary.Append(nil, binary.LittleEndian, itemState{
+		Pos:         e.Pos,
+		Vel:         e.Vel,
+		Type:        e.Stack.Type,
+		NoDespawn:   e.noDespawn,
+		Count:       int32(e.Stack.Count),
+		Damage:      int32(e.Stack.Damage),
+		Age:         e.Age,
+		HoverStart:  e.HoverStart,
+		RotationYaw: e.RotationYaw,
+		PickupDelay: e.PickupDelay,
+	})
+	return append(data, e.Owner...)
+}
+
+func loadItemEntity(w *world.World, data []byte) (world.Ticker, error) {
+	var s itemState
+	n, err := binary.Decode(data, binary.LittleEndian, &s)
+	if err != nil {
+		return nil, fmt.Errorf("item: %w", err)
+	}
+	e := &ItemEntity{
+		Stack:       item.ItemStack{Type: s.Type, Count: int(s.Count), Damage: int(s.Damage)},
+		Pos:         s.Pos,
+		Vel:         s.Vel,
+		World:       w,
+		Age:         s.Age,
+		HoverStart:  s.HoverStart,
+		RotationYaw: s.RotationYaw,
+		PickupDelay: s.PickupDelay,
+		Owner:       string(data[n:]),
+		noDespawn:   s.NoDespawn,
+	}
+	e.prevBlockX = int(math.Floor(float64(s.Pos.X())))
+	e.prevBlockY = int(math.Floor(float64(s.Pos.Y())))
+	e.prevBlockZ = int(math.Floor(float64(s.Pos.Z())))
+	return e, nil
+}
+
+func (b *Boat) EntityType() world.EntityTypeID { return TypeBoat }
+
+// SaveEntity returns the boat's position, motion and heading.
+func (b *Boat) SaveEntity() []byte {
+	data, _ := binary.Append(nil, binary.LittleEndian, bodyState{Pos: b.Pos, Vel: b.Vel, Yaw: b.Yaw})
+	return data
+}
+
+func loadBoat(w *world.World, data []byte) (world.Ticker, error) {
+	var s bodyState
+	if _, err := binary.Decode(data, binary.LittleEndian, &s); err != nil {
+		return nil, fmt.Errorf("boat: %w", err)
+	}
+	b := NewBoat(w, s.Pos, s.Yaw)
+	b.Vel = s.Vel
+	return b, nil
+}
+
+func (c *Minecart) EntityType() world.EntityTypeID { return TypeMinecart }
+
+// SaveEntity returns the cart's position, motion and heading. Its place on
+// the track is not saved; it latches onto the rail again on its first
+// update.
+func (c *Minecart) SaveEntity() []byte {
+	data, _ := binary.Append(nil, binary.LittleEndian, bodyState{Pos: c.Pos, Vel: c.Vel, Yaw: c.Yaw})
+	return data
+}
+
+func loadMinecart(w *world.World, data []byte) (world.Ticker, error) {
+	var s bodyState
+	if _, err := binary.Decode(data, binary.LittleEndian, &s); err != nil {
+		return nil, fmt.Errorf("minecart: %w", err)
+	}
+	c := NewMinecart(w, s.Pos, s.Yaw)
+	c.Vel = s.Vel
+	return c, nil
+}
+
+func (m *Mob) EntityType() world.EntityTypeID { return m.spec().typeID }
+
+// SaveEntity returns the mob's position, motion and heading. It forgets
+// where it was wandering to.
+func (m *Mob) SaveEntity() []byte {
+	data, _ := binary.Append(nil, binary.LittleEndian, bodyState{Pos: m.Pos, Vel: m.Vel, Yaw: m.Yaw})
+	return data
+}
+
+func loadMob(w *world.World, kind MobKind, data []byte) (world.Ticker, error) {
+	var s bodyState
+	if _, err := binary.Decode(data, binary.LittleEndian, &s); err != nil {
+		return nil, fmt.Errorf("%s: %w", mobSpecs[kind].typeID, err)
+	}
+	m := NewMob(w, kind, s.Pos, s.Yaw)
+	m.Vel = s.Vel
+	return m, nil
+}
+
+// Entities that persist with their chunk
+var (
+	_ world.SavedEntity = (*ItemEntity)(nil)
+	_ world.SavedEntity = (*Boat)(nil)
+	_ world.SavedEntity = (*Minecart)(nil)
+	_ world.SavedEntity = (*Mob)(nil)
+)
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"math/rand"
+	"testing"
+
+	"mini-mc/internal/item"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestSavedEntitiesLoadBack(t *testing.T) {
+	w := world.New()
+	defer w.Close()
+
+	drop := NewItemEntity(w, rand.New(rand.NewSource(1)), mgl32.Vec3{1.5, 70, -2.5}, item.ItemStack{Type: world.BlockTypeCobblestone, Count: 12, Damage: 3})
+	drop.Age, drop.Owner = 42, "steve"
+	drop.SetNoDespawn()
+	boat := NewBoat(w, mgl32.Vec3{3, 62, 3}, 90)
+	boat.Vel = mgl32.Vec3{1, 0, 2}
+
+	for _, e := range []world.SavedEntity{drop, boat} {
+		typ, ok := world.LookupEntityType(e.EntityType())
+		if !ok {
+			t.Fatalf("type %q not registered", e.EntityType())
+		}
+		loaded, err := typ.Load(w, e.SaveEntity())
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch got := loaded.(type) {
+		case *ItemEntity:
+			if got.Stack != drop.Stack || got.Pos != drop.Pos || got.Vel != drop.Vel || got.Age != 42 || got.Owner != "steve" || !got.noDespawn {
+				t.Fatalf("item loaded as %+v", got)
+			}
+		case *Boat:
+			if got.Pos != boat.Pos || got.Vel != boat.Vel || got.Yaw != 90 {
+				t.Fatalf("boat loaded as %+v", got)
+			}
+		default:
+			t.Fatalf("%q loaded as %T", e.EntityType(), loaded)
+		}
+	}
+
+	cart, err := w.SpawnEntity(TypeMinecart, mgl32.Vec3{0, 64, 0})
+	if _, ok := cart.(*Minecart); !ok || err != nil {
+		t.Fatalf("spawned %T, %v", cart, err)
+	}
+	if _, err := w.SpawnEntity(TypeItem, mgl32.Vec3{}); err == nil {
+		t.Fatal("spawned an item without a stack")
+	}
+}
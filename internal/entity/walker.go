@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"math"
+
+	"mini-mc/internal/physics"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// walker is the body of a mob that walks on the ground: where it is, how it
+// falls and collides, and how its legs swing. Mobs embed it and choose
+// which way to walk.
+type walker struct {
+	Pos      mgl32.Vec3 // bottom centre
+	Vel      mgl32.Vec3
+	OnGround bool
+
+	// LimbSwing grows by four per block walked and sets the phase of the
+	// stride, as for other players; LimbAmount is how far the legs swing, 0
+	// standing to 1 walking.
+	LimbSwing, LimbAmount float32
+}
+
+// move runs dt seconds of falling and walking at walk blocks per second,
+// moving one axis at a time and stopping at whatever is in the way. It
+// reports whether something blocked the walker sideways, for it to hop.
+func (b *walker) move(w *world.World, spec mobSpec, walk mgl32.Vec3, dt float64) (blocked bool) {
+	b.Vel[1] = max(b.Vel[1]-mobGravity*float32(dt), -spec.maxFallSpeed)
+	if b.OnGround {
+		drag := float32(math.Pow(mobDrag, dt*20))
+		b.Vel[0] *= drag
+		b.Vel[2] *= drag
+	}
+	d := b.Vel.Add(walk).Mul(float32(dt))
+
+	collides := func(pos mgl32.Vec3) bool {
+		return physics.Collides(pos, spec.width, spec.height, w)
+	}
+	if next := b.Pos.Add(mgl32.Vec3{d.X(), 0, 0}); !collides(next) {
+		b.Pos = next
+	} else {
+		b.Vel[0], blocked = 0, true
+	}
+	if next := b.Pos.Add(mgl32.Vec3{0, 0, d.Z()}); !collides(next) {
+		b.Pos = next
+	} else {
+		b.Vel[2], blocked = 0, true
+	}
+	b.OnGround = false
+	if next := b.Pos.Add(mgl32.Vec3{0, d.Y(), 0}); !collides(next) {
+		b.Pos = next
+	} else {
+		b.OnGround = d.Y() < 0
+		b.Vel[1] = 0
+	}
+
+	// Legs swing with the distance walked, easing in and out
+	if dt > 0 {
+		moved := float32(math.Hypot(float64(d.X()), float64(d.Z())))
+		b.LimbSwing += moved * 4
+		target := min(moved/(spec.walkSpeed*float32(dt)), 1)
+		b.LimbAmount += (target - b.LimbAmount) * 0.4
+	}
+	return blocked
+}
+
+func (b *walker) Push(dvx, dvz float32) {
+	b.Vel[0] += dvx
+	b.Vel[2] += dvz
+}
@@ -0,0 +1,174 @@
+package entity
+
+import (
+	"math"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Target is what hostile mobs hunt, usually the player.
+type Target interface {
+	FeetPosition() mgl32.Vec3
+	ApplyDamage(amount float32)
+}
+
+var zombieSpec = mobSpec{width: 0.6, height: 1.95, walkSpeed: 2.3, maxFallSpeed: 60, maxHealth: 20}
+
+// A zombie walks the path FindPath gives it to its target, searching again
+// every zombieRepathTime, and hits the target whenever it is within reach
+// and its cooldown is over. It gives up on targets further away than
+// zombieFollowRange and despawns once the day lights where it stands.
+const (
+	zombieFollowRange    = 40
+	zombieRepathTime     = 1.0 // seconds
+	zombieReach          = 1.2 // blocks between centres, across
+	zombieReachHeight    = 1.5 // and up or down
+	zombieDamage         = 3
+	zombieAttackCooldown = 1.0 // seconds
+	zombieWaypointRadius = 0.35
+)
+
+// Zombie is a hostile mob that chases its target over the blocks between
+// them and hits it. It is hurt and dies as a Mob does.
+type Zombie struct {
+	walker
+	HurtAnim
+	Yaw    float32 // degrees, same convention as the player's CamYaw
+	World  *world.World
+	Target Target
+	Dead   bool
+	Health float32
+
+	path           []world.BlockPos // nodes still to walk through
+	repathTime     float64          // seconds until the next search
+	attackCooldown float64          // seconds until it may hit again
+
+	motion motion
+}
+
+// NewZombie creates a zombie at pos facing yaw, hunting target.
+func NewZombie(w *world.World, target Target, pos mgl32.Vec3, yaw float32) *Zombie {
+	return &Zombie{walker: walker{Pos: pos}, Yaw: yaw, World: w, Target: target, Health: zombieSpec.maxHealth}
+}
+
+func (z *Zombie) Update(dt float64) {
+	z.motion.startTick(z.Transform())
+	if z.Dead {
+		return
+	}
+	z.TickHurt(dt)
+	if z.Dying() {
+		z.move(z.World, zombieSpec, mgl32.Vec3{}, dt)
+		return
+	}
+	if z.daylit() {
+		z.Dead = true
+		return
+	}
+	z.attackCooldown -= dt
+
+	walk := mgl32.Vec3{}
+	if aim, ok := z.chase(dt); ok {
+		if d := (mgl32.Vec3{aim.X() - z.Pos.X(), 0, aim.Z() - z.Pos.Z()}); d.Len() > 0.05 {
+			z.Yaw = mgl32.RadToDeg(float32(math.Atan2(float64(d.Z()), float64(d.X()))))
+			walk = d.Normalize().Mul(zombieSpec.walkSpeed)
+		}
+	}
+	if z.move(z.World, zombieSpec, walk, dt) && z.OnGround && walk.Len() > 0 {
+		z.Vel[1] = mobJumpSpeed
+	}
+	z.attack()
+}
+
+// chase returns the point the zombie walks toward: the next node of its
+// path, or the target itself once it is at the end of it. ok is false when
+// there is no target in range to chase.
+func (z *Zombie) chase(dt float64) (aim mgl32.Vec3, ok bool) {
+	if z.Target == nil {
+		return aim, false
+	}
+	target := z.Target.FeetPosition()
+	if horizontalDist(target, z.Pos) > zombieFollowRange {
+		z.path = nil
+		return aim, false
+	}
+
+	z.repathTime -= dt
+	if z.repathTime <= 0 {
+		z.path = FindPath(z.World, blockPosOf(z.Pos), blockPosOf(target))
+		z.repathTime = zombieRepathTime
+	}
+	for len(z.path) > 0 {
+		next := z.path[0]
+		centre := mgl32.Vec3{float32(next.X) + 0.5, float32(next.Y), float32(next.Z) + 0.5}
+		if horizontalDist(centre, z.Pos) > zombieWaypointRadius {
+			return centre, true
+		}
+		z.path = z.path[1:]
+	}
+	return target, horizontalDist(target, z.Pos) < 2
+}
+
+// attack hits the target if it is within reach and the cooldown is over.
+func (z *Zombie) attack() {
+	if z.Target == nil || z.attackCooldown > 0 {
+		return
+	}
+	target := z.Target.FeetPosition()
+	if horizontalDist(target, z.Pos) > zombieReach || abs32(target.Y()-z.Pos.Y()) > zombieReachHeight {
+		return
+	}
+	z.Target.ApplyDamage(zombieDamage)
+	z.attackCooldown = zombieAttackCooldown
+}
+
+// daylit reports whether the daylight reaching the zombie is too bright for
+// a hostile mob, as it is outdoors from dawn on.
+func (z *Zombie) daylit() bool {
+	b := blockPosOf(z.Pos.Add(mgl32.Vec3{0, 1, 0}))
+	return float32(z.World.SkyLight(b.X, b.Y, b.Z))-z.World.SkyDarkening() > hostileMaxLight
+}
+
+// blockPosOf returns the block that p is in.
+func blockPosOf(p mgl32.Vec3) world.BlockPos {
+	return world.BlockPos{
+		X: int(math.Floor(float64(p.X()))),
+		Y: int(math.Floor(float64(p.Y()))),
+		Z: int(math.Floor(float64(p.Z()))),
+	}
+}
+
+func horizontalDist(a, b mgl32.Vec3) float32 {
+	return float32(math.Hypot(float64(a.X()-b.X()), float64(a.Z()-b.Z())))
+}
+
+func (z *Zombie) Position() mgl32.Vec3 { return z.Pos }
+
+func (z *Zombie) Transform() Transform { return Transform{Pos: z.Pos, Yaw: z.Yaw} }
+
+func (z *Zombie) PrevTransform() Transform { return z.motion.prevOr(z.Transform()) }
+
+func (z *Zombie) Idle() { z.motion.startTick(z.Transform()) }
+
+func (z *Zombie) IsDead() bool { return z.Dead || z.DeathDone() }
+
+func (z *Zombie) Damage(amount float32, from mgl32.Vec3) bool {
+	return z.takeDamage(&z.HurtAnim, &z.Health, amount, from)
+}
+
+func (z *Zombie) SetDead() { z.Dead = true }
+
+func (z *Zombie) GetBounds() (width, height float32) {
+	return zombieSpec.width, zombieSpec.height
+}
+
+func (z *Zombie) AABB() AABB {
+	return boundsAABB(z.Pos, zombieSpec.width, zombieSpec.height)
+}
+
+var (
+	_ world.Body = (*Zombie)(nil)
+	_ Damageable = (*Zombie)(nil)
+)
@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type testTarget struct {
+	pos    mgl32.Vec3
+	damage float32
+}
+
+func (t *testTarget) FeetPosition() mgl32.Vec3 { return t.pos }
+
+func (t *testTarget) ApplyDamage(amount float32) { t.damage += amount }
+
+func TestZombieReachesTargetBehindWall(t *testing.T) {
+	w := floorWorld(t)
+	w.SetTime(18000) // midnight
+	for z := -3; z <= 3; z++ {
+		w.Set(0, 64, z, world.BlockTypeStone)
+		w.Set(0, 65, z, world.BlockTypeStone)
+	}
+
+	target := &testTarget{pos: mgl32.Vec3{3.5, 64, 0.5}}
+	zombie := NewZombie(w, target, mgl32.Vec3{-2.5, 64, 0.5}, 0)
+	for range 200 {
+		zombie.Update(0.05)
+	}
+	if zombie.Dead {
+		t.Fatal("zombie despawned at midnight")
+	}
+	// Hits at most once a second once it gets there
+	if target.damage < 2*zombieDamage || target.damage > 10*zombieDamage {
+		t.Fatalf("target took %v damage", target.damage)
+	}
+
+	w.SetTime(6000) // noon
+	zombie.Update(0.05)
+	if !zombie.Dead {
+		t.Fatal("zombie survived the day")
+	}
+}
+
+func TestZombieSpawnsInTheDark(t *testing.T) {
+	w := floorWorld(t)
+	w.SetTime(6000)
+	if y, ok := ZombieSpawnHeight(w, 2, 2, 64); ok {
+		t.Fatalf("zombie may spawn at noon, y=%d", y)
+	}
+	w.SetTime(18000)
+	if y, ok := ZombieSpawnHeight(w, 2, 2, 64); !ok || y != 64 {
+		t.Fatalf("at midnight: %d, %v; want 64, true", y, ok)
+	}
+
+	s := &MobSpawner{Target: &testTarget{pos: mgl32.Vec3{0, 64, 0}}}
+	far := NewZombie(w, s.Target, mgl32.Vec3{zombieDespawnDist + 10, 64, 0}, 0)
+	w.AddEntity(far)
+	for range zombieSpawnInterval {
+		s.Tick(w, 0, 0)
+	}
+	if !far.Dead {
+		t.Fatal("far zombie did not despawn")
+	}
+}
@@ -1,14 +1,17 @@
 package game
 
 import (
-	"log"
+	"log/slog"
+	"mini-mc/internal/config"
 	"mini-mc/internal/graphics/renderables/font"
 	"mini-mc/internal/graphics/renderables/ui"
 	"mini-mc/internal/input"
+	mcnet "mini-mc/internal/net"
 	"mini-mc/internal/player"
 	"mini-mc/internal/profiling"
 	"mini-mc/internal/ui/menu"
 
+	"runtime"
 	"time"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -25,22 +28,36 @@ const (
 type App struct {
 	window       *glfw.Window
 	inputManager *input.InputManager
+	cursor       *cursorController
 
 	state AppState
 
 	// Main Menu components
 	mainMenu     *menu.MainMenu
+	setupMenu    *menu.SetupMenu // first-run setup shown over the main menu; nil once done
 	menuUI       *ui.UI
 	fontRenderer *font.FontRenderer
+	panorama     *menuPanorama // nil if the backdrop failed to initialise
+	worldIconTex uint32        // icon of the listed saved world, 0 if none
 
 	// Game Session
 	session *Session
 
 	fpsLimiter *FPSLimiter
 	lastTime   time.Time
+	title      windowTitle
+	minimized  bool              // the window is iconified; frames are capped low
+	windowed   windowedPlacement // where the window goes back to leaving fullscreen
+
+	// Slow frames since the last warning, reported at most once a second
+	slowFrames        int
+	worstSlowFrame    time.Duration
+	lastSlowFrameWarn time.Time
 }
 
-func NewApp(window *glfw.Window) *App {
+// NewApp creates the app on the main menu. With firstRun set (no options
+// file yet) the setup page is shown first.
+func NewApp(window *glfw.Window, firstRun bool) *App {
 	// Initialize UI for Main Menu
 	newUI := ui.NewUI()
 	if err := newUI.Init(); err != nil {
@@ -58,15 +75,17 @@ func NewApp(window *glfw.Window) *App {
 	}
 	newUI.SetFontRenderer(fr)
 
-	width, height := window.GetSize()
+	width, height := ui.ScreenOf(window).Size()
 	newUI.SetViewport(width, height)
 	fr.SetViewport(float32(width), float32(height))
 
 	im := input.NewInputManager()
+	im.BindMovementKeys(config.GetKeyboardLayout().MovementKeyLabels())
 
-	return &App{
+	app := &App{
 		window:       window,
 		inputManager: im,
+		cursor:       newCursorController(window),
 		state:        StateMainMenu,
 		mainMenu:     menu.NewMainMenu(),
 		menuUI:       newUI,
@@ -74,12 +93,46 @@ func NewApp(window *glfw.Window) *App {
 		fpsLimiter:   NewFPSLimiter(),
 		lastTime:     time.Now(),
 	}
+	if firstRun {
+		renderer := gl.GoStr(gl.GetString(gl.RENDERER))
+		app.setupMenu = menu.NewSetupMenu(config.RecommendRenderPreset(renderer, runtime.NumCPU()))
+	}
+	if config.GetFullscreen() {
+		app.setFullscreen(true)
+	}
+	app.openPanorama()
+	app.loadWorldInfo()
+	return app
+}
+
+// openPanorama starts the main menu backdrop, falling back to the plain
+// background if it cannot be created.
+func (a *App) openPanorama() {
+	p, err := newMenuPanorama(a.window)
+	if err != nil {
+		slog.Warn("menu panorama disabled", "err", err)
+		p = nil
+	}
+	a.panorama = p
+	a.mainMenu.SetBackdrop(p != nil)
+}
+
+// closePanorama releases the backdrop so a session can take over the mesh system.
+func (a *App) closePanorama() {
+	if a.panorama != nil {
+		a.panorama.Close()
+		a.panorama = nil
+	}
+	a.mainMenu.SetBackdrop(false)
 }
 
 func (a *App) Run() {
 	for !a.window.ShouldClose() {
 		a.tick()
 	}
+	if err := config.SaveOptions(config.OptionsFile); err != nil {
+		slog.Error("saving options failed", "err", err)
+	}
 }
 
 func (a *App) tick() {
@@ -91,10 +144,17 @@ func (a *App) tick() {
 
 	glfw.PollEvents()
 
+	if a.inputManager.JustPressed(input.ActionToggleFullscreen) {
+		fullscreen := !config.GetFullscreen()
+		config.SetFullscreen(fullscreen)
+		config.MarkOptionsDirty()
+		a.setFullscreen(fullscreen)
+	}
+
 	switch a.state {
 	case StateMainMenu:
 		a.updateMainMenu(dt)
-		a.renderMainMenu()
+		a.renderMainMenu(dt)
 	case StatePlaying:
 		if a.session != nil {
 			action := a.session.Update(dt, a.inputManager)
@@ -109,13 +169,16 @@ func (a *App) tick() {
 	}
 
 	a.window.SwapBuffers()
+	a.title.update(a)
 
-	// Check if frame took too long (> 16ms)
-	processingDuration := time.Since(startTick)
-	if processingDuration > 15*time.Millisecond {
-		log.Printf("Slow frame: %v", processingDuration)
+	// Settings committed in a menu this frame
+	if err := config.FlushOptions(config.OptionsFile); err != nil {
+		slog.Error("saving options failed", "err", err)
 	}
 
+	// Check if frame took too long (> 16ms)
+	a.noteFrameTime(time.Since(startTick))
+
 	a.inputManager.PostUpdate() // Clear "JustPressed" flags
 
 	// FPS limit
@@ -123,10 +186,25 @@ func (a *App) tick() {
 	if a.session != nil {
 		paused = a.session.Paused
 	}
-	a.fpsLimiter.Wait(paused || a.state == StateMainMenu)
+	a.fpsLimiter.Wait(paused || a.state == StateMainMenu, a.minimized)
 }
 
 func (a *App) updateMainMenu(dt float64) {
+	if a.panorama != nil {
+		a.panorama.Update(dt)
+	}
+
+	if a.setupMenu != nil {
+		if a.setupMenu.Update(a.window, a.inputManager.JustPressed(input.ActionMouseLeft)) {
+			a.setupMenu = nil
+			a.inputManager.BindMovementKeys(config.GetKeyboardLayout().MovementKeyLabels())
+			if err := config.SaveOptions(config.OptionsFile); err != nil {
+				slog.Error("saving options failed", "err", err)
+			}
+		}
+		return
+	}
+
 	// Handle input for menu
 	action := a.mainMenu.Update(a.window, a.inputManager.JustPressed(input.ActionMouseLeft))
 
@@ -142,35 +220,62 @@ func (a *App) updateMainMenu(dt float64) {
 	}
 }
 
-func (a *App) renderMainMenu() {
-	// Clear screen
-	gl.ClearColor(0, 0, 0, 1)
-	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+func (a *App) renderMainMenu(dt float64) {
+	if a.panorama != nil {
+		a.panorama.Render(dt)
+	} else {
+		// Clear screen
+		gl.ClearColor(0, 0, 0, 1)
+		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	}
 
 	// Use menuUI to render
 	a.menuUI.BeginFrame()
-	a.mainMenu.Render(a.menuUI, a.window)
+	if a.setupMenu != nil {
+		a.setupMenu.Render(a.menuUI, a.window)
+	} else {
+		a.mainMenu.Render(a.menuUI, a.window)
+	}
 	a.menuUI.Flush()
 }
 
 func (a *App) StartSession(mode player.GameMode) {
+	a.closePanorama()
+
 	var err error
-	a.session, err = NewSession(a.window, mode)
+	a.session, err = NewSession(a.window, a.cursor, mode)
 	if err != nil {
 		panic(err)
 	}
 	a.state = StatePlaying
 }
 
+// Connect logs in to the server at addr as name and starts play there.
+func (a *App) Connect(addr, name string) error {
+	client, err := mcnet.Dial(addr, name, config.GetRenderDistance())
+	if err != nil {
+		return err
+	}
+	a.closePanorama()
+	a.session, err = NewRemoteSession(a.window, a.cursor, client, addr)
+	if err != nil {
+		client.Close()
+		a.openPanorama()
+		return err
+	}
+	a.state = StatePlaying
+	return nil
+}
+
 func (a *App) EndSession() {
 	if a.session != nil {
 		a.session.Cleanup()
 		a.session = nil
 	}
 	a.state = StateMainMenu
-
-	// Restore cursor for menu
-	a.window.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+	a.openPanorama()
+	a.loadWorldInfo()
+	a.cursor.Reset()
 }
 
 // RefreshRender handles window resize repaints
@@ -179,7 +284,22 @@ func (a *App) RefreshRender() {
 		a.session.RefreshRender()
 	} else {
 		// Repaint menu
-		a.renderMainMenu()
+		a.renderMainMenu(0.016)
 		a.window.SwapBuffers()
 	}
 }
+
+// noteFrameTime counts frames over the 60 FPS budget and warns about them
+// at most once a second, so a slow stretch does not flood the log.
+func (a *App) noteFrameTime(d time.Duration) {
+	if d > 15*time.Millisecond {
+		a.slowFrames++
+		a.worstSlowFrame = max(a.worstSlowFrame, d)
+	}
+	if a.slowFrames == 0 || time.Since(a.lastSlowFrameWarn) < time.Second {
+		return
+	}
+	slog.Warn("slow frames", "count", a.slowFrames, "worst", a.worstSlowFrame)
+	a.slowFrames, a.worstSlowFrame = 0, 0
+	a.lastSlowFrameWarn = time.Now()
+}
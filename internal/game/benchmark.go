@@ -0,0 +1,159 @@
+package game
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"mini-mc/internal/config"
+	"mini-mc/internal/graphics/renderables/blocks"
+	"mini-mc/internal/input"
+	"mini-mc/internal/player"
+	"mini-mc/internal/profiling"
+	"mini-mc/internal/replay"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// The benchmark flies the camera over a fresh, unsaved world of
+// benchmarkSeed along a scripted path, the same every run, so frame times
+// and streaming latencies of two builds can be compared. The path is played
+// as a recording would be (see replay.go), a frame each game tick.
+const (
+	benchmarkSeed     = 7355608
+	benchmarkSpeed    = 10 // blocks a second, about creative flight
+	benchmarkAltitude = 30 // blocks over spawn
+	benchmarkSweep    = 12 // seconds the view takes to look side to side and back
+)
+
+// RunBenchmark flies the benchmark path for seconds in window, drawing as
+// fast as it can, and writes what it measured to dir as JSON and CSV (see
+// profiling.Benchmark). Timing starts once the terrain at the start of the
+// path is loaded. The options are used as loaded and not saved.
+func RunBenchmark(window *glfw.Window, seconds int, dir string) error {
+	if seconds <= 0 {
+		return fmt.Errorf("benchmark of %d seconds; it needs at least one", seconds)
+	}
+	w := world.NewWithSeed(benchmarkSeed)
+	spawn := w.Spawn()
+	start := mgl32.Vec3{float32(spawn.X) + 0.5, float32(spawn.Y), float32(spawn.Z) + 0.5}
+	s, err := newSession(window, newCursorController(window), player.GameModeCreative, w, "", start, localPlayerName)
+	if err != nil {
+		w.Close()
+		return err
+	}
+	defer s.Cleanup()
+
+	path := benchmarkPath(start, seconds*world.TicksPerSecond)
+	s.playback = replay.NewPlayback(path)
+	s.followPlayback = true
+	s.Teleport(path.Frames[0].Pos)
+
+	b := profiling.Benchmark{
+		Seed:           benchmarkSeed,
+		RenderDistance: config.GetRenderDistance(),
+		Renderer:       gl.GoStr(gl.GetString(gl.RENDERER)),
+		CPUs:           runtime.NumCPU(),
+		GoVersion:      runtime.Version(),
+	}
+	slog.Info("benchmark loading", "seed", benchmarkSeed, "seconds", seconds, "renderDistance", b.RenderDistance)
+
+	im := input.NewInputManager()
+	var started time.Time
+	draws := blocks.TerrainDrawCalls()
+	last := time.Now()
+	for s.playback != nil {
+		if window.ShouldClose() {
+			return fmt.Errorf("window closed before the benchmark finished")
+		}
+		profiling.ResetFrame()
+		now := time.Now()
+		dt := now.Sub(last)
+		last = now
+
+		glfw.PollEvents()
+		s.Update(dt.Seconds(), im)
+		render, _, _ := s.Render(dt.Seconds())
+		window.SwapBuffers()
+		calls := blocks.TerrainDrawCalls() - draws
+		draws += calls
+
+		if s.teleport.holdsPlayer() {
+			// Loading the start is not part of the run
+			b.ChunkLatencies = w.ChunkLatencies().Take(b.ChunkLatencies[:0])
+			b.MeshLatencies = blocks.MeshLatencies().Take(b.MeshLatencies[:0])
+			continue
+		}
+		if started.IsZero() {
+			started = now.Add(-dt)
+			slog.Info("benchmark started")
+		}
+		b.Frames = append(b.Frames, profiling.BenchmarkFrame{
+			At:            now.Sub(started),
+			Frame:         dt,
+			Render:        render,
+			DrawCalls:     calls,
+			PendingMeshes: blocks.PendingMeshJobs(),
+			Pos:           s.Player.Position,
+		})
+		// Taken every frame, as they only keep so many
+		b.ChunkLatencies = w.ChunkLatencies().Take(b.ChunkLatencies)
+		b.MeshLatencies = blocks.MeshLatencies().Take(b.MeshLatencies)
+	}
+
+	name := filepath.Join(dir, time.Now().Format("benchmark_2006-01-02_15.04.05"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := writeReport(name+".json", b.WriteJSON); err != nil {
+		return err
+	}
+	if err := writeReport(name+".csv", b.WriteCSV); err != nil {
+		return err
+	}
+	sum := b.Summary()
+	slog.Info("benchmark finished", "report", name+".json", "frames", sum.Frames,
+		"fps", math.Round(sum.AverageFPS), "p99FrameMs", sum.FrameTime.P99, "meshP95Ms", sum.MeshLatency.P95)
+	return nil
+}
+
+// benchmarkPath returns the scripted flight of ticks game ticks: from
+// benchmarkAltitude over start on along +X, the view sweeping 50 degrees
+// either side and dipping towards the ground and back, so terrain keeps
+// streaming in ahead and every side of what is loaded gets drawn.
+func benchmarkPath(start mgl32.Vec3, ticks int) *replay.Recording {
+	r := &replay.Recording{Seed: benchmarkSeed}
+	y := start.Y() + benchmarkAltitude
+	for t := range ticks + 1 {
+		sec := float64(t) / world.TicksPerSecond
+		phase := 2 * math.Pi * sec / benchmarkSweep
+		r.Add(replay.Frame{
+			Pos:     mgl32.Vec3{start.X() + float32(sec*benchmarkSpeed), y, start.Z()},
+			Yaw:     float32(50 * math.Sin(phase)),
+			Pitch:   float32(-20 - 15*math.Sin(phase*1.5)),
+			Actions: replay.Flying,
+		})
+	}
+	return r
+}
+
+// writeReport creates path and writes a report to it with write.
+func writeReport(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
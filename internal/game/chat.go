@@ -0,0 +1,179 @@
+package game
+
+import (
+	"strings"
+
+	"mini-mc/internal/command"
+	standardInput "mini-mc/internal/input"
+	"mini-mc/internal/item"
+	mcnet "mini-mc/internal/net"
+	"mini-mc/internal/player"
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// The chat prompt opens with T, or with / already typed for a command. While
+// it is open it takes the keyboard: the key callback hands keys and typed
+// characters here instead of to the input manager. A line starting with a
+// slash runs as a command; anything else is said in chat, to the server
+// when playing on one.
+
+// ChatOpen reports whether the chat prompt is taking the keyboard.
+func (s *Session) ChatOpen() bool {
+	return s.chatOpen
+}
+
+// openChat opens the chat prompt holding text, letting go of any keys held
+// for the game.
+func (s *Session) openChat(text string, im *standardInput.InputManager) {
+	im.ReleaseAll()
+	s.chatOpen = true
+	s.prompt.Open(text)
+	s.HUDRenderer.SetChatPrompt(&s.prompt)
+	s.cursor.Push(standardInput.ContextChat)
+}
+
+// closeChat closes the chat prompt, dropping what was typed.
+func (s *Session) closeChat() {
+	if !s.chatOpen {
+		return
+	}
+	s.chatOpen = false
+	s.HUDRenderer.SetChatPrompt(nil)
+	s.cursor.Pop(standardInput.ContextChat)
+}
+
+// HandleChatKey edits the prompt for a key pressed or repeated while it is
+// open.
+func (s *Session) HandleChatKey(key glfw.Key) {
+	p := &s.prompt
+	switch key {
+	case glfw.KeyEscape:
+		s.closeChat()
+	case glfw.KeyEnter, glfw.KeyKPEnter:
+		line := p.Submit()
+		s.closeChat()
+		s.sendChat(line)
+	case glfw.KeyBackspace:
+		p.Backspace()
+	case glfw.KeyDelete:
+		p.Delete()
+	case glfw.KeyLeft:
+		p.MoveCaret(-1)
+	case glfw.KeyRight:
+		p.MoveCaret(1)
+	case glfw.KeyHome:
+		p.MoveCaret(-len(p.Text()))
+	case glfw.KeyEnd:
+		p.MoveCaret(len(p.Text()))
+	case glfw.KeyUp:
+		p.Recall(-1)
+	case glfw.KeyDown:
+		p.Recall(1)
+	case glfw.KeyTab:
+		p.Complete(s.commands)
+	}
+}
+
+// HandleChatChar types a character into the open prompt.
+func (s *Session) HandleChatChar(r rune) {
+	s.prompt.Insert(r)
+}
+
+// sendChat runs line as a command if it starts with a slash, and otherwise
+// says it in chat.
+func (s *Session) sendChat(line string) {
+	if line == "" {
+		return
+	}
+	if strings.HasPrefix(line, "/") {
+		var out strings.Builder
+		err := s.commands.Execute(line, &out)
+		if out.Len() > 0 {
+			s.HUDRenderer.AddChatLine(out.String())
+		}
+		if err != nil {
+			s.HUDRenderer.AddChatError(err.Error())
+		}
+		return
+	}
+	if s.remote != nil {
+		// The server sends it back to everyone, this player included
+		s.remote.client.Send(&mcnet.Chat{Text: line})
+		return
+	}
+	s.HUDRenderer.AddChatLine("<" + localPlayerName + "> " + line)
+}
+
+// consoleGame is the session as the console's commands see it.
+type consoleGame struct {
+	s *Session
+}
+
+func (g consoleGame) Position() mgl32.Vec3 { return g.s.Player.Position }
+
+func (g consoleGame) Teleport(pos mgl32.Vec3) { g.s.Teleport(pos) }
+
+func (g consoleGame) Item(name string) (world.BlockType, bool) {
+	t, ok := registry.BlockNames[name]
+	return t, ok && t != world.BlockTypeAir
+}
+
+func (g consoleGame) Block(name string) (world.BlockType, bool) {
+	t, ok := registry.BlockNames[name]
+	if !ok || registry.BlockDefs[t] == nil || registry.BlockDefs[t].IsItem {
+		return 0, false
+	}
+	return t, true
+}
+
+func (g consoleGame) Give(t world.BlockType, count int) int {
+	stack := item.NewItemStack(t, count)
+	g.s.Player.Inventory.AddItem(&stack)
+	return count - stack.Count
+}
+
+func (g consoleGame) Time() float64 { return g.s.World.Time() }
+
+func (g consoleGame) SetTime(ticks float64) { g.s.World.SetTime(ticks) }
+
+func (g consoleGame) SetGameMode(mode string) {
+	p := g.s.Player
+	p.GameMode = player.GameModeSurvival
+	if mode == player.GameModeCreative.String() {
+		p.GameMode = player.GameModeCreative
+	}
+	if level := g.s.World.Level(); level != nil {
+		level.GameMode = p.GameMode.String()
+	}
+}
+
+func (g consoleGame) Seed() int64 { return g.s.World.Seed() }
+
+func (g consoleGame) Loaded(x, z int) bool {
+	return g.s.World.GetChunkFromBlockCoords(x, 0, z, false) != nil
+}
+
+// SetBlock places block, telling the server when playing on one, which
+// then updates its neighbours. Offline they are updated here, and placed
+// water and lava start flowing.
+func (g consoleGame) SetBlock(x, y, z int, block world.BlockType) {
+	w := g.s.World
+	w.Set(x, y, z, block)
+	if g.s.remote != nil {
+		g.s.remote.client.Send(&mcnet.BlockChange{X: int32(x), Y: int32(y), Z: int32(z), State: w.GetState(x, y, z)})
+		return
+	}
+	w.NotifyNeighbors(x, y, z)
+	switch block {
+	case world.BlockTypeWater:
+		w.ScheduleBlockTick(x, y, z, world.WaterTickRate, 0)
+	case world.BlockTypeLava:
+		w.ScheduleBlockTick(x, y, z, world.LavaTickRate, 0)
+	}
+}
+
+var _ command.Game = consoleGame{}
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"mini-mc/internal/input"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// cursorController is the one place the window's cursor mode is set. The
+// mode follows the input context on top of the stack: gameplay captures the
+// cursor, anything over it frees it. Focus changes re-apply the mode, since
+// the window system may have released the cursor while the window was in
+// the background.
+type cursorController struct {
+	window   *glfw.Window
+	contexts input.ContextStack
+	focused  bool
+	captured bool // the mode last applied
+
+	// The first mouse delta after capture measures how far the cursor moved
+	// while free, not a camera turn; it is swallowed
+	recaptured bool
+}
+
+func newCursorController(window *glfw.Window) *cursorController {
+	return &cursorController{window: window, focused: true}
+}
+
+// Top returns the context receiving input.
+func (c *cursorController) Top() input.Context {
+	return c.contexts.Top()
+}
+
+// Push opens ctx over the current context.
+func (c *cursorController) Push(ctx input.Context) {
+	c.contexts.Push(ctx)
+	c.apply()
+}
+
+// Pop closes ctx and whatever was opened over it.
+func (c *cursorController) Pop(ctx input.Context) {
+	c.contexts.Pop(ctx)
+	c.apply()
+}
+
+// Reset closes every context, back to a free cursor for the menus.
+func (c *cursorController) Reset() {
+	c.contexts.Reset()
+	c.apply()
+}
+
+// FocusChanged records a focus change of the window and sets the mode again.
+func (c *cursorController) FocusChanged(focused bool) {
+	c.focused = focused
+	if focused && c.contexts.Top().CapturesCursor() {
+		c.captured = false // capture again even if it looks held
+	}
+	c.apply()
+}
+
+// Captured reports whether mouse movement turns the camera.
+func (c *cursorController) Captured() bool {
+	return c.captured
+}
+
+// TakeRecapture reports whether the cursor was captured since the last call,
+// meaning the next mouse delta should be swallowed.
+func (c *cursorController) TakeRecapture() bool {
+	r := c.recaptured
+	c.recaptured = false
+	return r
+}
+
+// apply sets the cursor mode the top context wants, if it is not set yet.
+// A freed cursor starts in the middle of the window.
+func (c *cursorController) apply() {
+	want := c.focused && c.contexts.Top().CapturesCursor()
+	if want == c.captured {
+		return
+	}
+	c.captured = want
+	if want {
+		c.window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
+		c.recaptured = true
+		return
+	}
+	c.window.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+	if c.focused {
+		w, h := c.window.GetSize()
+		c.window.SetCursorPos(float64(w)/2, float64(h)/2)
+	}
+}
@@ -0,0 +1,149 @@
+package game
+
+import (
+	"math"
+
+	"mini-mc/internal/config"
+	"mini-mc/internal/graphics/renderables/particles"
+	"mini-mc/internal/item"
+	"mini-mc/internal/player"
+	"mini-mc/internal/registry"
+	"mini-mc/internal/sound"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// connectFeedback plays the sounds and particles for the player's block
+// events, footsteps, landings, splashes and pickups, the click for a
+// refused placement, the sparks for entity hits and the low-health
+// heartbeat.
+// Block feedback comes from the block's registry entry (sound group and
+// texture), so new blocks get it without extra code.
+func connectFeedback(p *player.Player, fx *particles.Particles) {
+	p.OnBlockBreak = func(x, y, z int, block world.BlockType, meta uint8) {
+		fx.SpawnBlockBreak(x, y, z, blockFragment(block))
+		playBlockSound(x, y, z, block)
+	}
+	p.OnBlockPlace = func(x, y, z int, block world.BlockType, meta uint8) {
+		fx.SpawnBlockPlace(x, y, z, blockFragment(block))
+		playBlockSound(x, y, z, block)
+	}
+	p.OnPlaceDenied = func(x, y, z int, reason player.PlaceDenial) {
+		// A soft low click, quieter than the menu buttons
+		sound.Play(sound.Event{
+			Name:   "random.click",
+			Pos:    mgl32.Vec3{float32(x) + 0.5, float32(y) + 0.5, float32(z) + 0.5},
+			Volume: 0.2,
+			Pitch:  0.6,
+			Bus:    config.AudioBusUI,
+		})
+	}
+	p.OnEntityHit = func(pos mgl32.Vec3, crit bool) {
+		fx.SpawnHit(pos, crit) // the hurt sound is the entity's own
+	}
+	p.OnStep = func(pos mgl32.Vec3, block world.BlockType, volume, pitch float32) {
+		group := registry.GetSoundFast(block)
+		sound.Play(sound.Event{
+			Name:   group.StepSound(),
+			Pos:    pos,
+			Volume: volume,
+			Pitch:  group.Pitch() * pitch,
+			Bus:    config.AudioBusBlocks,
+		})
+	}
+	p.OnLand = func(pos mgl32.Vec3, block world.BlockType, fallDistance float32) {
+		fx.SpawnLandingDust(pos, blockFragment(block), fallDistance)
+	}
+	p.OnSprintDust = func(pos, vel mgl32.Vec3, block world.BlockType) {
+		width, _ := p.GetBounds()
+		fx.SpawnRunningDust(pos, vel, blockFragment(block), width)
+	}
+	p.OnSplash = func(pos, vel mgl32.Vec3) {
+		width, _ := p.GetBounds()
+		fx.SpawnSplash(pos, blockFragment(world.BlockTypeWater), width)
+		// 1.8.9's Entity.resetHeight: louder the faster the fall, counting
+		// the vertical motion (in blocks/tick) most
+		m := vel.Mul(1.0 / 20)
+		speed := float32(math.Sqrt(float64(m.X()*m.X()*0.2 + m.Y()*m.Y() + m.Z()*m.Z()*0.2)))
+		rnd := p.World.Rand()
+		sound.Play(sound.Event{
+			Name:   "game.player.swim.splash",
+			Pos:    pos,
+			Volume: min(1, speed*0.2),
+			Pitch:  1 + (rnd.Float32()-rnd.Float32())*0.4,
+			Bus:    config.AudioBusEntities,
+		})
+	}
+	p.OnItemPickup = func(pos mgl32.Vec3) {
+		// 1.8.9's pickup pop, pitched at random around an octave up
+		rnd := p.World.Rand()
+		sound.Play(sound.Event{
+			Name:   "random.pop",
+			Pos:    pos,
+			Volume: 0.2,
+			Pitch:  ((rnd.Float32()-rnd.Float32())*0.7 + 1) * 2,
+			Bus:    config.AudioBusEntities,
+		})
+	}
+	p.OnHeartbeat = func(intensity float32) {
+		// A low bass drum thump, louder the lower the health; 1.8.9 has no
+		// heartbeat of its own
+		sound.Play(sound.Event{
+			Name:   "note.bd",
+			Volume: 0.4 + 0.6*intensity,
+			Pitch:  0.5,
+			Bus:    config.AudioBusUI,
+		})
+	}
+	p.OnToolBreak = func(tool item.ItemStack, pos mgl32.Vec3) {
+		fx.SpawnItemBreak(pos, p.Camera().Front, blockFragment(tool.Type))
+		sound.Play(sound.Event{
+			Name:   "random.break",
+			Pos:    pos,
+			Volume: 0.8,
+			Pitch:  0.8 + p.World.Rand().Float32()*0.4,
+			Bus:    config.AudioBusEntities,
+		})
+	}
+}
+
+// blockFragment returns how a block's fragments look: its side texture and
+// that face's tint.
+func blockFragment(block world.BlockType) particles.BlockFragment {
+	face := int(world.FaceNorth)
+	return particles.BlockFragment{
+		Layer: registry.GetTexLayerFast(block, face),
+		Tint:  unpackTint(registry.GetTintFast(block, face)),
+	}
+}
+
+// unpackTint expands a packed RGB565 tint into a colour.
+func unpackTint(c uint16) mgl32.Vec3 {
+	return mgl32.Vec3{
+		float32(c>>11&0x1F) / 31,
+		float32(c>>5&0x3F) / 63,
+		float32(c&0x1F) / 31,
+	}
+}
+
+// playBlockSound plays the break/place sound of a block's sound group, at
+// the 1.8.9 volume and pitch for both.
+func playBlockSound(x, y, z int, block world.BlockType) {
+	group := registry.GetSoundFast(block)
+	sound.Play(sound.Event{
+		Name:   group.BreakSound(),
+		Pos:    mgl32.Vec3{float32(x) + 0.5, float32(y) + 0.5, float32(z) + 0.5},
+		Volume: 1,
+		Pitch:  group.Pitch() * 0.8,
+		Bus:    config.AudioBusBlocks,
+	})
+}
+
+// musicMood returns the biome the player stands in and whether it is night,
+// which pick the background music.
+func (s *Session) musicMood() sound.MusicMood {
+	pos := s.Player.Position
+	biome := world.GetBiomeForCoords(float64(pos[0]), float64(pos[2]), s.World.Seed())
+	return sound.MusicMood{Biome: biome.Name, Night: s.World.Daylight() < 0.5}
+}
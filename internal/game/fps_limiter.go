@@ -17,11 +17,15 @@ func NewFPSLimiter() *FPSLimiter {
 
 // Wait blocks until the next frame should be rendered based on the FPS limit.
 // Uses a hybrid sleep/spin approach for better precision on high FPS caps.
-func (f *FPSLimiter) Wait(paused bool) {
+// While minimized the lower minimized cap applies, to save power.
+func (f *FPSLimiter) Wait(paused, minimized bool) {
 	effectiveLimit := config.GetFPSLimit()
 	if paused {
 		effectiveLimit = 120
 	}
+	if low := config.GetMinimizedFPS(); minimized && low > 0 {
+		effectiveLimit = low
+	}
 
 	if effectiveLimit <= 0 {
 		f.next = time.Time{}
@@ -1,7 +1,8 @@
 package game
 
 import (
-	"github.com/go-gl/gl/v4.1-core/gl"
+	"mini-mc/internal/graphics/renderables/ui"
+
 	"github.com/go-gl/glfw/v3.3/glfw"
 )
 
@@ -13,9 +14,13 @@ func SetupInputHandlers(app *App) {
 	window.SetCursorPosCallback(func(w *glfw.Window, xpos, ypos float64) {
 		if app.session != nil && !app.session.Paused {
 			s := app.session
-			s.Player.MouseX = xpos
-			s.Player.MouseY = ypos
-			if !s.Player.IsInventoryOpen {
+			// The inventory screens lay out in UI units like the menus
+			mx, my := ui.CursorPos(w)
+			s.Player.MouseX, s.Player.MouseY = float64(mx), float64(my)
+			if app.cursor.Captured() {
+				if app.cursor.TakeRecapture() {
+					s.Player.FirstMouse = true
+				}
 				s.Player.HandleMouseMovement(w, xpos, ypos)
 			}
 		}
@@ -23,10 +28,16 @@ func SetupInputHandlers(app *App) {
 
 	// Mouse button callback
 	window.SetMouseButtonCallback(func(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+		// The chat prompt takes no clicks, but buttons held before it
+		// opened are still let go
+		if app.session != nil && app.session.ChatOpen() && action != glfw.Release {
+			return
+		}
+
 		// Update InputManager state first (globally tracking inputs)
 		im.HandleMouseButtonEvent(button, action)
 
-		if app.session != nil && !app.session.Paused {
+		if app.session != nil && !app.session.Paused && !app.session.ChatOpen() {
 			s := app.session
 			if s.Player.IsInventoryOpen {
 				s.HUDRenderer.HandleInventoryClick(s.Player.MouseX, s.Player.MouseY, button, action)
@@ -37,55 +48,59 @@ func SetupInputHandlers(app *App) {
 	})
 
 	window.SetScrollCallback(func(w *glfw.Window, xoff, yoff float64) {
-		if app.session != nil && !app.session.Paused {
+		if app.session != nil && !app.session.Paused && !app.session.ChatOpen() {
 			s := app.session
-			if !s.Player.IsInventoryOpen {
+			if s.Player.IsInventoryOpen {
+				s.HUDRenderer.HandleInventoryScroll(yoff)
+			} else {
 				s.Player.HandleScroll(yoff)
 			}
 		}
 	})
 
-	// Handle keyboard actions
+	// Handle keyboard actions. The open chat prompt takes key presses and
+	// typed characters; releases still reach the input manager so keys held
+	// before it opened are let go.
 	window.SetKeyCallback(func(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+		if app.session != nil && app.session.ChatOpen() && action != glfw.Release {
+			app.session.HandleChatKey(key)
+			return
+		}
 		im.HandleKeyEvent(key, action)
 	})
-
-	// Framebuffer size callback
-	window.SetFramebufferSizeCallback(func(w *glfw.Window, fbWidth, fbHeight int) {
-		gl.Viewport(0, 0, int32(fbWidth), int32(fbHeight))
-
-		// Update App level viewports (Menu)
-		// UI logic uses Window (Logical) coordinates for layout, so we must pass Window size to SetViewport
-		winW, winH := w.GetSize()
-		app.menuUI.SetViewport(winW, winH)
-		app.fontRenderer.SetViewport(float32(winW), float32(winH))
-
-		if app.session != nil {
-			app.session.Renderer.UpdateViewport(winW, winH)
-			app.session.UIRenderer.SetViewport(winW, winH)
+	window.SetCharCallback(func(w *glfw.Window, char rune) {
+		if app.session != nil && app.session.ChatOpen() {
+			app.session.HandleChatChar(char)
 		}
-		// NOTE: Do not render here. Rely on SetRefreshCallback for smooth resizing on macOS.
 	})
 
-	// Window size callback
+	// Size callbacks. The framebuffer, the window and the content scale
+	// can each change on their own (moving between a HiDPI and a normal
+	// monitor changes the scale only).
+	// NOTE: Do not render here. Rely on SetRefreshCallback for smooth resizing on macOS.
+	window.SetFramebufferSizeCallback(func(w *glfw.Window, fbWidth, fbHeight int) {
+		app.relayout()
+	})
 	window.SetSizeCallback(func(w *glfw.Window, width, height int) {
-		if app.session != nil {
-			app.session.Renderer.UpdateViewport(width, height)
-		}
+		app.relayout()
+	})
+	window.SetContentScaleCallback(func(w *glfw.Window, x, y float32) {
+		app.relayout()
 	})
 
 	// Focus callback
 	window.SetFocusCallback(func(w *glfw.Window, focused bool) {
-		if !focused && app.session != nil && !app.session.Paused {
-			s := app.session
-			if s.Player.IsInventoryOpen {
-				s.Player.SetInventoryOpen(false)
-				s.Player.DropCursorItem()
-			}
-			s.SetPaused(true)
+		app.cursor.FocusChanged(focused)
+		if app.session != nil {
+			app.session.FocusChanged(focused)
 		}
 	})
 
+	// Iconify callback
+	window.SetIconifyCallback(func(w *glfw.Window, iconified bool) {
+		app.minimized = iconified
+	})
+
 	// Refresh callback
 	window.SetRefreshCallback(func(w *glfw.Window) {
 		app.RefreshRender()
@@ -0,0 +1,204 @@
+package game
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+
+	"mini-mc/internal/graphics/renderables/playermodel"
+	mcnet "mini-mc/internal/net"
+	"mini-mc/internal/player"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+const (
+	// maxPacketsPerFrame bounds the packets handled a frame, so a burst of
+	// chunks is spread over a few frames rather than stalling one
+	maxPacketsPerFrame = 64
+
+	// positionInterval is how often the player's position is sent, once a
+	// tick as in 1.8.9
+	positionInterval = 1.0 / world.TicksPerSecond
+
+	// otherPlayerCatchUp is how quickly, per second, other players move
+	// toward where the server last said they are
+	otherPlayerCatchUp = 15.0
+)
+
+// remoteGame is a session's connection to the server it plays on. The
+// server runs the world: the session shows the chunks and block changes it
+// is sent, and sends back where the player is and what they break and
+// place.
+type remoteGame struct {
+	client *mcnet.Client
+	addr   string
+	others map[int32]*otherPlayer
+
+	sinceSent float64 // seconds since the position was last sent
+	lastSent  mcnet.PlayerPosition
+}
+
+// otherPlayer is another player on the server, drawn easing toward the
+// position the server last sent.
+type otherPlayer struct {
+	name   string
+	pose   playermodel.WorldPose
+	target mcnet.MovePlayer
+}
+
+// NewRemoteSession starts play on the server client is logged in to. The
+// world is built from the server's seed but never generated locally: it
+// holds only the chunks the server sends.
+func NewRemoteSession(window *glfw.Window, cursor *cursorController, client *mcnet.Client, addr string) (*Session, error) {
+	gameWorld := world.NewWithSeed(client.Seed)
+	gameWorld.SetTime(client.Time)
+	s, err := newSession(window, cursor, player.GameMode(client.GameMode), gameWorld, "", client.Spawn, client.Name)
+	if err != nil {
+		gameWorld.Close()
+		return nil, err
+	}
+	s.engine.Remote = true
+	s.remote = &remoteGame{client: client, addr: addr, others: make(map[int32]*otherPlayer)}
+	s.HUDRenderer.SetNetStats(client.Stats())
+	s.connectRemote()
+
+	// Wait in the dark for the terrain to arrive
+	s.Teleport(client.Spawn)
+	slog.Info("connected to server", "addr", addr, "name", client.Name)
+	return s, nil
+}
+
+// connectRemote sends the server the blocks the player breaks and places,
+// after the feedback the session already plays for them.
+func (s *Session) connectRemote() {
+	p := s.Player
+	sendBlock := func(x, y, z int) {
+		s.remote.client.Send(&mcnet.BlockChange{X: int32(x), Y: int32(y), Z: int32(z), State: s.World.GetState(x, y, z)})
+	}
+	onBreak, onPlace := p.OnBlockBreak, p.OnBlockPlace
+	p.OnBlockBreak = func(x, y, z int, block world.BlockType, meta uint8) {
+		onBreak(x, y, z, block, meta)
+		sendBlock(x, y, z)
+	}
+	p.OnBlockPlace = func(x, y, z int, block world.BlockType, meta uint8) {
+		onPlace(x, y, z, block, meta)
+		sendBlock(x, y, z)
+	}
+}
+
+// updateRemote handles what the server sent, sends the player's position
+// and moves the other players. It returns an error once the connection has
+// ended.
+func (s *Session) updateRemote(dt float64) error {
+	r := s.remote
+	packets, ok := r.client.Poll(maxPacketsPerFrame)
+	for _, p := range packets {
+		s.handlePacket(p)
+	}
+	if !ok {
+		err := r.client.Err()
+		if err == nil {
+			err = errors.New("connection closed")
+		}
+		return err
+	}
+
+	r.sinceSent += dt
+	if r.sinceSent >= positionInterval {
+		r.sinceSent = 0
+		pos := mcnet.PlayerPosition{
+			Pos:      s.Player.Position,
+			Yaw:      float32(s.Player.CamYaw),
+			Pitch:    float32(s.Player.CamPitch),
+			OnGround: s.Player.OnGround,
+		}
+		if pos != r.lastSent {
+			r.client.Send(&pos)
+			r.lastSent = pos
+		}
+	}
+
+	poses := make([]playermodel.WorldPose, 0, len(r.others))
+	for _, o := range r.others {
+		o.update(dt)
+		poses = append(poses, o.pose)
+	}
+	s.others.Set(poses)
+	return nil
+}
+
+// handlePacket applies one packet from the server.
+func (s *Session) handlePacket(p mcnet.Packet) {
+	r := s.remote
+	switch p := p.(type) {
+	case *mcnet.ChunkData:
+		coord := world.ChunkCoord{X: int(p.X), Z: int(p.Z)}
+		c, err := world.DecodeChunk(coord, p.Data)
+		if err != nil {
+			slog.Warn("bad chunk from server", "chunk", coord, "err", err)
+			return
+		}
+		s.World.InstallChunk(c)
+	case *mcnet.UnloadChunk:
+		s.World.UnloadChunk(int(p.X), int(p.Z))
+	case *mcnet.BlockChange:
+		x, y, z := int(p.X), int(p.Y), int(p.Z)
+		// A change in a chunk not loaded here would create an empty one
+		if s.World.GetChunkFromBlockCoords(x, y, z, false) != nil && s.World.GetState(x, y, z) != p.State {
+			s.World.SetState(x, y, z, p.State)
+		}
+	case *mcnet.SpawnPlayer:
+		if p.PlayerID == r.client.PlayerID {
+			return
+		}
+		target := mcnet.MovePlayer{PlayerID: p.PlayerID, Pos: p.Pos, Yaw: p.Yaw, Pitch: p.Pitch}
+		r.others[p.PlayerID] = &otherPlayer{
+			name:   p.Name,
+			pose:   playermodel.WorldPose{Pos: p.Pos, Yaw: p.Yaw, Pitch: p.Pitch},
+			target: target,
+		}
+	case *mcnet.MovePlayer:
+		if o := r.others[p.PlayerID]; o != nil {
+			o.target = *p
+		}
+	case *mcnet.DestroyPlayer:
+		delete(r.others, p.PlayerID)
+	case *mcnet.Chat:
+		slog.Info(p.Text)
+		s.HUDRenderer.AddChatLine(p.Text)
+	case *mcnet.TimeUpdate:
+		s.World.SetTime(p.Time)
+	case *mcnet.PlayerList:
+		s.Players.Replace(p.Entries)
+	}
+}
+
+// update eases the player toward their target and swings their limbs by
+// how far they moved, as MC's walking animation does.
+func (o *otherPlayer) update(dt float64) {
+	k := float32(min(1, dt*otherPlayerCatchUp))
+	prev := o.pose.Pos
+	o.pose.Pos = prev.Add(o.target.Pos.Sub(prev).Mul(k))
+	o.pose.Yaw += wrapDegrees(o.target.Yaw-o.pose.Yaw) * k
+	o.pose.Pitch += (o.target.Pitch - o.pose.Pitch) * k
+
+	moved := o.pose.Pos.Sub(prev)
+	dist := float32(math.Hypot(float64(moved[0]), float64(moved[2])))
+	o.pose.LimbSwing += dist * 4
+	amount := float32(0)
+	if dt > 0 {
+		amount = min(1, dist/float32(dt)/4.3) // 4.3 blocks a second is walking pace
+	}
+	o.pose.LimbAmount += (amount - o.pose.LimbAmount) * float32(min(1, dt*8))
+}
+
+// wrapDegrees wraps an angle to [-180, 180), so turning takes the short way.
+func wrapDegrees(a float32) float32 {
+	a = float32(math.Mod(float64(a)+180, 360))
+	if a < 0 {
+		a += 360
+	}
+	return a - 180
+}
@@ -0,0 +1,80 @@
+package game
+
+import (
+	"runtime"
+
+	"mini-mc/internal/graphics/renderables/blocks"
+	"mini-mc/internal/graphics/renderables/ui"
+	"mini-mc/internal/graphics/renderer"
+	"mini-mc/internal/player"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+const (
+	// panoramaSyncRadius chunks are generated before the first menu frame;
+	// the rest of panoramaRadius streams in while the menu is shown.
+	panoramaSyncRadius = 3
+	panoramaRadius     = 6
+
+	panoramaHeight   = 24.0  // camera height above the surface at the origin
+	panoramaPitch    = -12.0 // degrees, looking slightly down at the terrain
+	panoramaYawSpeed = 3.0   // degrees per second
+)
+
+// menuPanorama renders a slowly rotating view over a small generated terrain
+// patch behind the main menu. It owns the block mesh system while the menu is
+// shown, so it must be closed before a session starts.
+type menuPanorama struct {
+	world    *world.World
+	camera   *player.Player
+	renderer *renderer.Renderer
+}
+
+func newMenuPanorama(window *glfw.Window) (*menuPanorama, error) {
+	r, err := renderer.NewRenderer(blocks.NewBlocks())
+	if err != nil {
+		return nil, err
+	}
+
+	w := world.New()
+	blocks.InitMeshSystem(runtime.NumCPU() - 1)
+	w.StreamChunksAroundSync(0, 0, panoramaSyncRadius)
+
+	cam := player.New(w, player.GameModeCreative)
+	cam.Position[1] = float32(w.SurfaceHeightAt(0, 0)) + panoramaHeight
+	cam.CamPitch = panoramaPitch
+
+	width, height := ui.ScreenOf(window).Size()
+	r.UpdateViewport(width, height)
+
+	return &menuPanorama{world: w, camera: cam, renderer: r}, nil
+}
+
+// Update turns the camera and keeps terrain streaming and meshing.
+func (m *menuPanorama) Update(dt float64) {
+	m.camera.CamYaw += panoramaYawSpeed * dt
+	if m.camera.CamYaw >= 360 {
+		m.camera.CamYaw -= 360
+	}
+	m.world.StreamChunksAroundAsync(0, 0, panoramaRadius)
+	blocks.ProcessMeshResults()
+}
+
+// Render draws the terrain; the menu is drawn on top afterwards.
+func (m *menuPanorama) Render(dt float64) {
+	m.renderer.Render(m.world, m.camera, dt)
+}
+
+// UpdateViewport forwards window size changes to the panorama camera.
+func (m *menuPanorama) UpdateViewport(width, height int) {
+	m.renderer.UpdateViewport(width, height)
+}
+
+// Close releases the world, mesh system and GL resources.
+func (m *menuPanorama) Close() {
+	m.world.Close()
+	blocks.ShutdownMeshSystem()
+	m.renderer.Dispose()
+}
@@ -0,0 +1,130 @@
+package game
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"mini-mc/internal/graphics/cubemap"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	panoramaShotFace = 1024          // edge of each cube face, in pixels
+	panoramaShotDir  = "screenshots" // where the panoramas are saved
+)
+
+// takePanoramaShot renders the six 90 degree views around the player's eye
+// and saves them, stitched into an equirectangular PNG centred on the way
+// the player faces, from a worker goroutine.
+func (s *Session) takePanoramaShot() {
+	faces, err := s.renderCubeFaces(panoramaShotFace)
+	if err != nil {
+		slog.Error("panorama screenshot failed", "err", err)
+		return
+	}
+	front := s.Player.GetFrontVector()
+	heading := mgl32.Vec3{front.X(), 0, front.Z()}
+	if heading.Len() < 1e-4 {
+		heading = mgl32.Vec3{0, 0, -1} // looking straight up or down
+	}
+	heading = heading.Normalize()
+
+	path := filepath.Join(panoramaShotDir, time.Now().Format("panorama_2006-01-02_15.04.05.png"))
+	go func() {
+		start := time.Now()
+		if err := writePanorama(path, cubemap.Equirect(faces, 4*panoramaShotFace, heading)); err != nil {
+			slog.Error("saving panorama screenshot failed", "err", err)
+			return
+		}
+		slog.Info("saved panorama screenshot", "path", path, "elapsed", time.Since(start).Round(time.Millisecond))
+	}()
+}
+
+// renderCubeFaces renders the world around the player once for each of
+// cubemap.Faces into a size x size offscreen target, and reads the images
+// back, top row first. The window's framebuffer and viewport are restored.
+func (s *Session) renderCubeFaces(size int) ([6]*image.RGBA, error) {
+	var faces [6]*image.RGBA
+
+	var fbo, color, depth uint32
+	gl.GenTextures(1, &color)
+	gl.BindTexture(gl.TEXTURE_2D, color)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, int32(size), int32(size), 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	gl.GenRenderbuffers(1, &depth)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, depth)
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH_COMPONENT24, int32(size), int32(size))
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+	gl.GenFramebuffers(1, &fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, fbo)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, color, 0)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.RENDERBUFFER, depth)
+
+	var viewport [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	defer func() {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+		gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+		gl.DeleteFramebuffers(1, &fbo)
+		gl.DeleteRenderbuffers(1, &depth)
+		gl.DeleteTextures(1, &color)
+	}()
+	if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		return faces, fmt.Errorf("framebuffer incomplete: 0x%x", status)
+	}
+	gl.Viewport(0, 0, int32(size), int32(size))
+
+	// The block outline belongs to the player's view, not the scenery
+	hovered := s.Player.HasHoveredBlock
+	s.Player.HasHoveredBlock = false
+	defer func() { s.Player.HasHoveredBlock = hovered }()
+
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	for i, f := range cubemap.Faces {
+		s.Renderer.RenderView(s.World, s.Player, f.Front, f.Up, 90)
+		img := image.NewRGBA(image.Rect(0, 0, size, size))
+		gl.ReadPixels(0, 0, int32(size), int32(size), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+		flipRows(img)
+		for p := 3; p < len(img.Pix); p += 4 {
+			img.Pix[p] = 255 // blending leaves glass and water partly transparent
+		}
+		faces[i] = img
+	}
+	return faces, nil
+}
+
+// flipRows turns a bottom-up GL read into a top-down image.
+func flipRows(img *image.RGBA) {
+	h := img.Rect.Dy()
+	row := make([]byte, img.Stride)
+	for y := range h / 2 {
+		top := img.Pix[y*img.Stride : (y+1)*img.Stride]
+		bottom := img.Pix[(h-1-y)*img.Stride : (h-y)*img.Stride]
+		copy(row, top)
+		copy(top, bottom)
+		copy(bottom, row)
+	}
+}
+
+// writePanorama saves img as a PNG at path, creating its directory.
+func writePanorama(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = png.Encode(f, img)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
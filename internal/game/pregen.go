@@ -0,0 +1,144 @@
+package game
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"time"
+
+	"mini-mc/internal/command"
+	"mini-mc/internal/config"
+	"mini-mc/internal/world"
+)
+
+// pregenResultShown is how long the HUD keeps a finished job's result up.
+const pregenResultShown = 5 * time.Second
+
+// togglePregen starts pregenerating the world around the player out to twice
+// the render distance, or stops the running job.
+func (s *Session) togglePregen() {
+	if s.stopPregenJob() {
+		return
+	}
+	if _, err := s.startPregen(2 * config.GetRenderDistance()); err != nil {
+		slog.Error("starting pregeneration failed", "err", err)
+	}
+}
+
+// startPregen starts pregenerating the world within radius columns of the
+// player, as the pregen command and the pause menu do, and returns the job.
+func (s *Session) startPregen(radius int) (*world.PregenJob, error) {
+	if s.pregen != nil && s.pregenFinished.IsZero() {
+		return nil, errors.New("already pregenerating")
+	}
+	cx := int(math.Floor(float64(s.Player.Position[0]) / world.ChunkSizeX))
+	cz := int(math.Floor(float64(s.Player.Position[2]) / world.ChunkSizeZ))
+	job, err := s.World.Pregenerate(cx, cz, radius)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("pregenerating world", "centerX", cx, "centerZ", cz, "radius", radius)
+	s.pregen, s.pregenFinished = job, time.Time{}
+	return job, nil
+}
+
+// stopPregenJob cancels the running job without waiting for it, reporting
+// whether one was running.
+func (s *Session) stopPregenJob() bool {
+	if s.pregen == nil || !s.pregenFinished.IsZero() {
+		return false
+	}
+	s.pregen.Cancel()
+	return true
+}
+
+// updatePregen passes job progress to the HUD and pause menu, and drops the
+// job once its result has been shown for a while.
+func (s *Session) updatePregen() {
+	if s.pregen == nil {
+		s.HUDRenderer.SetPregenProgress(nil)
+		s.PauseMenu.SetPregenRunning(false)
+		return
+	}
+	p := s.pregen.Progress()
+	s.HUDRenderer.SetPregenProgress(&p)
+	s.PauseMenu.SetPregenRunning(!p.Finished)
+	switch {
+	case !p.Finished:
+	case s.pregenFinished.IsZero():
+		s.pregenFinished = time.Now()
+		logPregenResult(p)
+	case time.Since(s.pregenFinished) > pregenResultShown:
+		s.pregen = nil
+	}
+}
+
+// stopPregen cancels a running job and waits for it, so the world can be
+// closed.
+func (s *Session) stopPregen() {
+	if s.pregen != nil {
+		s.pregen.Cancel()
+		s.pregen.Wait()
+		s.pregen = nil
+	}
+}
+
+func logPregenResult(p world.PregenProgress) {
+	if p.Err != nil {
+		slog.Error("pregeneration failed", "done", p.Done, "total", p.Total, "err", p.Err)
+		return
+	}
+	slog.Info("pregeneration finished", "done", p.Done, "total", p.Total, "elapsed", p.Elapsed.Round(time.Millisecond))
+}
+
+// Pregenerate generates and saves the game world's chunks within radius
+// columns of the origin without opening a window, logging progress every few
+// seconds. It is used to build a world ahead of play.
+func Pregenerate(radius int) error {
+	w, err := world.Open(worldSaveDir)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	job, err := w.Pregenerate(0, 0, radius)
+	if err != nil {
+		return err
+	}
+	slog.Info("pregenerating world", "radius", radius, "columns", job.Progress().Total)
+
+	done := make(chan world.PregenProgress)
+	go func() { done <- job.Wait() }()
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case p := <-done:
+			if p.Err != nil {
+				return p.Err // logged by the caller
+			}
+			logPregenResult(p)
+			return nil
+		case <-ticker.C:
+			p := job.Progress()
+			slog.Info("pregenerating", "done", p.Done, "total", p.Total,
+				"percent", int(p.Fraction()*100), "eta", p.ETA().Round(time.Second))
+		}
+	}
+}
+
+// consolePregen is the session as the pregen command sees it.
+type consolePregen struct {
+	s *Session
+}
+
+func (p consolePregen) StartPregen(radius int) (int, error) {
+	job, err := p.s.startPregen(radius)
+	if err != nil {
+		return 0, err
+	}
+	return job.Progress().Total, nil
+}
+
+func (p consolePregen) StopPregen() bool { return p.s.stopPregenJob() }
+
+var _ command.Pregen = consolePregen{}
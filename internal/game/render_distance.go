@@ -0,0 +1,63 @@
+package game
+
+import (
+	"log/slog"
+	"time"
+
+	"mini-mc/internal/config"
+	"mini-mc/internal/graphics/renderables/blocks"
+	mcnet "mini-mc/internal/net"
+	"mini-mc/internal/profiling"
+)
+
+// growthCheckInterval is how often the progress of a render distance
+// increase is counted; counting walks every chunk of the new ring.
+const growthCheckInterval = 250 * time.Millisecond
+
+// renderDistanceChanged applies a new render distance at once rather than
+// waiting on the streaming and eviction cadence: the ring of columns it adds
+// is queued nearest first, or the chunks and meshes it drops are freed. On
+// a server, the server is asked to send or unload them instead.
+func (s *Session) renderDistanceChanged(old, new int) {
+	x, y, z := s.Player.Position[0], s.Player.Position[1], s.Player.Position[2]
+	if s.remote != nil {
+		s.remote.client.Send(&mcnet.ViewDistance{Chunks: uint8(new)})
+	}
+	if new > old {
+		if s.remote == nil {
+			s.World.StreamRing(x, z, old, new)
+		}
+		p := s.World.RingProgress(x, z, old, new)
+		s.growth, s.growthChecked = &p, time.Now()
+		s.HUDRenderer.SetRenderDistanceGrowth(s.growth)
+		return
+	}
+
+	s.growth = nil
+	s.HUDRenderer.SetRenderDistanceGrowth(nil)
+	if s.remote != nil {
+		return
+	}
+	defer profiling.Track("world.EvictFarChunks")()
+	evictRadius := config.GetChunkEvictRadius()
+	evicted := s.World.EvictFarChunks(x, z, evictRadius)
+	pruned := blocks.PruneMeshesByWorld(s.World, x, y, z, evictRadius)
+	s.lastEviction = time.Now()
+	slog.Info("render distance lowered", "from", old, "to", new, "evictedChunks", evicted, "prunedMeshes", pruned)
+}
+
+// updateRenderDistanceGrowth recounts how much of a render distance
+// increase has loaded for the debug HUD, and stops once all of it has.
+func (s *Session) updateRenderDistanceGrowth() {
+	if s.growth == nil || time.Since(s.growthChecked) < growthCheckInterval {
+		return
+	}
+	pos := s.Player.Position
+	p := s.World.RingProgress(pos[0], pos[2], s.growth.Inner, s.growth.Outer)
+	s.growth, s.growthChecked = &p, time.Now()
+	if p.Done() {
+		slog.Info("render distance raised", "from", p.Inner, "to", p.Outer, "chunks", p.Total)
+		s.growth = nil
+	}
+	s.HUDRenderer.SetRenderDistanceGrowth(s.growth)
+}
@@ -0,0 +1,221 @@
+package game
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"mini-mc/internal/command"
+	"mini-mc/internal/graphics/renderables/playermodel"
+	"mini-mc/internal/player"
+	"mini-mc/internal/replay"
+)
+
+const (
+	recordingDir = "recordings" // where the record command saves
+	recordingExt = ".mcr"
+)
+
+// The record command captures the player a frame each game tick until it
+// is stopped, and saves the frames under recordingDir named by when the
+// recording started. The replay command plays one back: as a ghost walking
+// the path beside the player, or with the camera option by moving the
+// player along it, their own movement and looking switched off, so the
+// renderer sees the same views each time it is played.
+
+// startRecording starts recording the player from the next tick.
+func (s *Session) startRecording() error {
+	if s.recording != nil {
+		return fmt.Errorf("already recording")
+	}
+	s.recording = &replay.Recording{Seed: s.World.Seed()}
+	s.recordingName = time.Now().Format("2006-01-02_15.04.05")
+	return nil
+}
+
+// stopRecording saves the recording under recordingDir.
+func (s *Session) stopRecording() (string, int, error) {
+	r := s.recording
+	if r == nil {
+		return "", 0, fmt.Errorf("not recording")
+	}
+	s.recording = nil
+	if len(r.Frames) == 0 {
+		return "", 0, fmt.Errorf("nothing was recorded")
+	}
+	if err := r.Save(filepath.Join(recordingDir, s.recordingName+recordingExt)); err != nil {
+		return "", 0, fmt.Errorf("saving the recording failed: %w", err)
+	}
+	return s.recordingName, len(r.Frames) - 1, nil
+}
+
+// playRecording loads the recording called name, or the newest for "last",
+// and starts playing it from its first frame.
+func (s *Session) playRecording(name string, camera bool) (int, error) {
+	if name == "last" {
+		newest, err := newestRecording()
+		if err != nil {
+			return 0, err
+		}
+		name = newest
+	}
+	r, err := replay.Load(filepath.Join(recordingDir, name+recordingExt))
+	if os.IsNotExist(err) {
+		return 0, fmt.Errorf("no recording called %q", name)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(r.Frames) == 0 {
+		return 0, fmt.Errorf("recording %q is empty", name)
+	}
+	if r.Seed != s.World.Seed() {
+		s.HUDRenderer.AddChatLine("This was recorded in another world")
+	}
+
+	s.stopPlayback()
+	s.playback = replay.NewPlayback(r)
+	s.followPlayback = camera
+	if camera {
+		s.Teleport(r.Frames[0].Pos)
+	}
+	return len(r.Frames) - 1, nil
+}
+
+// stopPlayback stops the recording playing, leaving the player where it
+// took them.
+func (s *Session) stopPlayback() bool {
+	if s.playback == nil {
+		return false
+	}
+	s.playback = nil
+	s.followPlayback = false
+	s.ghost.Hide()
+	return true
+}
+
+// newestRecording returns the name of the last recording saved.
+func newestRecording() (string, error) {
+	paths, err := filepath.Glob(filepath.Join(recordingDir, "*"+recordingExt))
+	if err != nil || len(paths) == 0 {
+		return "", fmt.Errorf("nothing has been recorded")
+	}
+	// Named by the time they started, so the newest sorts last
+	newest := filepath.Base(slices.Max(paths))
+	return strings.TrimSuffix(newest, recordingExt), nil
+}
+
+// following reports whether the player is being moved along a recording.
+func (s *Session) following() bool {
+	return s.playback != nil && s.followPlayback
+}
+
+// tickReplay records the tick the player just moved, and moves a playback
+// on a tick, the player with it when following. A playback waits while a
+// teleport holds the player.
+func (s *Session) tickReplay() {
+	p := s.Player
+	if s.recording != nil {
+		s.recording.Add(recordFrame(p))
+	}
+	pb := s.playback
+	if pb == nil || s.teleport.holdsPlayer() {
+		return
+	}
+	if !pb.Tick() {
+		s.stopPlayback()
+		s.HUDRenderer.AddChatLine("Replay finished")
+		return
+	}
+	if s.followPlayback {
+		f := pb.Frame(1)
+		_, step := pb.Walked(1)
+		p.PrevPosition = p.Position
+		p.Position = f.Pos
+		p.Velocity = [3]float32{}
+		p.IsSneaking = f.Actions&replay.Sneaking != 0
+		p.IsSprinting = f.Actions&replay.Sprinting != 0
+		p.IsFlying = f.Actions&replay.Flying != 0
+		p.OnGround = f.Actions&replay.OnGround != 0
+		p.PrevDistanceWalkedModified = p.DistanceWalkedModified
+		p.DistanceWalkedModified += float64(step) * 0.6
+	}
+}
+
+// updateReplay shows the playback for the frame: the ghost where it stands,
+// or the player's view turned the way the recording looked.
+func (s *Session) updateReplay(partialTick float32) {
+	pb := s.playback
+	if pb == nil {
+		return
+	}
+	f := pb.Frame(partialTick)
+	if s.followPlayback {
+		s.Player.CamYaw = float64(f.Yaw)
+		s.Player.CamPitch = float64(f.Pitch)
+		return
+	}
+	distance, step := pb.Walked(partialTick)
+	// As selfPose, in blocks: the stride's phase grows by 4 a block
+	s.ghost.Show(playermodel.WorldPose{
+		Pos:        f.Pos,
+		Yaw:        f.Yaw,
+		Pitch:      f.Pitch,
+		LimbSwing:  distance * 4,
+		LimbAmount: min(step*4, 1),
+	})
+}
+
+// recordFrame returns the player as they are after a tick.
+func recordFrame(p *player.Player) replay.Frame {
+	f := replay.Frame{Pos: p.Position, Yaw: float32(p.CamYaw), Pitch: float32(p.CamPitch)}
+	for _, a := range []struct {
+		on     bool
+		action replay.Action
+	}{
+		{p.IsSneaking, replay.Sneaking},
+		{p.IsSprinting, replay.Sprinting},
+		{p.IsFlying, replay.Flying},
+		{p.OnGround, replay.OnGround},
+		{p.IsBreaking, replay.Breaking},
+		{p.HandSwingProgress > 0, replay.Swinging},
+	} {
+		if a.on {
+			f.Actions |= a.action
+		}
+	}
+	return f
+}
+
+// saveRecording saves a recording still running when the session ends.
+func (s *Session) saveRecording() {
+	if s.recording == nil {
+		return
+	}
+	if name, _, err := s.stopRecording(); err != nil {
+		slog.Error("saving recording failed", "err", err)
+	} else {
+		slog.Info("saved recording", "name", name)
+	}
+}
+
+// consoleReplays is the session as the record and replay commands see it.
+type consoleReplays struct {
+	s *Session
+}
+
+func (r consoleReplays) StartRecording() error { return r.s.startRecording() }
+
+func (r consoleReplays) StopRecording() (string, int, error) { return r.s.stopRecording() }
+
+func (r consoleReplays) Play(name string, camera bool) (int, error) {
+	return r.s.playRecording(name, camera)
+}
+
+func (r consoleReplays) StopPlayback() bool { return r.s.stopPlayback() }
+
+var _ command.Replays = consoleReplays{}
@@ -1,23 +1,35 @@
 package game
 
 import (
+	"log/slog"
 	"runtime"
 	"time"
 
+	"mini-mc/internal/command"
 	"mini-mc/internal/config"
+	"mini-mc/internal/entity"
 	"mini-mc/internal/graphics/renderables/blocks"
 	"mini-mc/internal/graphics/renderables/breaking"
 	"mini-mc/internal/graphics/renderables/crosshair"
 	"mini-mc/internal/graphics/renderables/hand"
 	"mini-mc/internal/graphics/renderables/hud"
 	"mini-mc/internal/graphics/renderables/items"
+	"mini-mc/internal/graphics/renderables/particles"
+	"mini-mc/internal/graphics/renderables/playermodel"
+	"mini-mc/internal/graphics/renderables/sky"
+	"mini-mc/internal/graphics/renderables/sunshafts"
 	"mini-mc/internal/graphics/renderables/ui"
 	"mini-mc/internal/graphics/renderables/wireframe"
 	"mini-mc/internal/graphics/renderer"
 	standardInput "mini-mc/internal/input"
-	"mini-mc/internal/physics"
+	"mini-mc/internal/item"
 	"mini-mc/internal/player"
+	"mini-mc/internal/presence"
 	"mini-mc/internal/profiling"
+	"mini-mc/internal/registry"
+	"mini-mc/internal/replay"
+	"mini-mc/internal/sim"
+	"mini-mc/internal/sound"
 	"mini-mc/internal/ui/menu"
 	"mini-mc/internal/world"
 
@@ -25,6 +37,20 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 )
 
+// worldSaveDir holds the level metadata, icon and edited chunks of the game
+// world.
+const worldSaveDir = "saves/world"
+
+// autosaveInterval is how often edited chunks are handed to the background
+// writer, as in 1.8.9.
+const autosaveInterval = 45 * time.Second
+
+// musicDir holds the background music tracks; see sound.Music.
+const musicDir = "assets/music"
+
+// localPlayerName is the name of the player at this computer.
+const localPlayerName = "Player"
+
 type Session struct {
 	Window      *glfw.Window
 	Renderer    *renderer.Renderer
@@ -32,34 +58,114 @@ type Session struct {
 	HUDRenderer *hud.HUD
 	Player      *player.Player
 	World       *world.World
+	Players     *presence.List // who is connected, shown while Tab is held
 
 	Paused    bool
 	PauseMenu *menu.PauseMenu
+	cursor    *cursorController // shared with the app; the session pushes its contexts
+
+	pausedByFocus bool // the pause menu opened because the window lost focus
+
+	DeathScreen *menu.DeathScreen
+	dead        bool       // the death screen is open
+	spawn       mgl32.Vec3 // where the player respawns
 
 	Frames           int
 	LastFPSCheckTime time.Time
 	lastEviction     time.Time
+	lastAutosave     time.Time
+
+	engine *sim.Engine                 // ticks the world, moves time and entities
+	input  *standardInput.InputManager // of the frame being simulated, for the player's ticks
+
+	playTime float64    // unpaused seconds this session
+	icon     *worldIcon // takes the world's thumbnail a few seconds in
+
+	panoramaShot bool // a 360 screenshot is taken before the next frame
+
+	pregen         *world.PregenJob // nil when no pregeneration is running or shown
+	pregenFinished time.Time        // when pregen finished; zero while it runs
+
+	teleport teleport // a far move waiting on terrain at the destination
+
+	growth        *world.RingProgress // nil unless a render distance increase is streaming in
+	growthChecked time.Time
+
+	music *sound.Music
+
+	commands *command.Dispatcher // what the chat prompt runs
+	prompt   command.Prompt
+	chatOpen bool
 
-	tickAccumulator float64 // seconds accumulated toward the next 20 TPS game tick
+	remote *remoteGame         // the server played on; nil in single player
+	others *playermodel.Others // draws the other players on the server
+
+	recording      *replay.Recording // nil unless recording
+	recordingName  string
+	playback       *replay.Playback // nil unless a recording is playing
+	followPlayback bool             // the playback moves the player, not a ghost
+	ghost          *playermodel.Ghost
+}
+
+func NewSession(window *glfw.Window, cursor *cursorController, mode player.GameMode) (*Session, error) {
+	// Open the saved world; edited chunks load from disk, the rest regenerate
+	gameWorld, err := world.Open(worldSaveDir)
+	if err != nil {
+		return nil, err
+	}
+	gameWorld.Level().GameMode = mode.String()
+
+	// Appear standing at the world's spawn, which is found (or built) the
+	// first time the world is played
+	spawn := gameWorld.Spawn()
+	spawnPos := mgl32.Vec3{float32(spawn.X) + 0.5, float32(spawn.Y), float32(spawn.Z) + 0.5}
+
+	s, err := newSession(window, cursor, mode, gameWorld, worldSaveDir, spawnPos, localPlayerName)
+	if err != nil {
+		gameWorld.Close()
+		return nil, err
+	}
+	return s, nil
 }
 
-func NewSession(window *glfw.Window, mode player.GameMode) (*Session, error) {
+// newSession sets up play in gameWorld, with the player standing at spawn.
+// The world's icon is saved in iconDir, unless it is empty, and the overrides
+// the world carries there are loaded on top of the global assets.
+func newSession(window *glfw.Window, cursor *cursorController, mode player.GameMode, gameWorld *world.World, iconDir string, spawn mgl32.Vec3, name string) (*Session, error) {
+	registry.UseWorldOverrides(iconDir)
+
 	// Initialize renderable features
+	skyRenderer := sky.NewSky()
 	blocksRenderer := blocks.NewBlocks()
 	itemsRenderer := items.NewItems()
+	othersRenderer := playermodel.NewOthers()
+	selfRenderer := playermodel.NewSelf()
+	ghostRenderer := playermodel.NewGhost()
+	mobsRenderer := playermodel.NewMobs()
 	breakingRenderer := breaking.NewBreaking()
 	wireframeRenderer := wireframe.NewWireframe()
+	sunShaftsRenderer := sunshafts.NewSunShafts()
 	crosshairRenderer := crosshair.NewCrosshair()
 	handRenderer := hand.NewHand(itemsRenderer)
+	particlesRenderer := particles.NewParticles()
+	iconCapture := newWorldIcon(iconDir)
 	uiRenderer := ui.NewUI()
 	hudRenderer := hud.NewHUD()
 
 	// Initialize renderer with all features
 	r, err := renderer.NewRenderer(
+		skyRenderer, // first, behind everything
 		blocksRenderer,
 		itemsRenderer,
+		othersRenderer,
+		selfRenderer,
+		ghostRenderer,
+		mobsRenderer,
+		particlesRenderer,
 		breakingRenderer,
 		wireframeRenderer,
+		sunShaftsRenderer, // over the finished world
+		iconCapture,       // after the world, before the hand and HUD
 		crosshairRenderer,
 		handRenderer,
 		uiRenderer,
@@ -71,67 +177,102 @@ func NewSession(window *glfw.Window, mode player.GameMode) (*Session, error) {
 
 	uiRenderer.SetFontRenderer(hudRenderer.FontRenderer())
 
-	// Create world
-	gameWorld := world.New()
-
 	// Initialize (or re-initialize) mesh system
 	blocks.InitMeshSystem(runtime.NumCPU() - 1)
+	gameWorld.OnRelight(blocks.InvalidateChunkLighting)
 
 	// Create player
 	gamePlayer := player.New(gameWorld, mode)
-
-	// Fix spawn position: find ground level at 0,0
-	spawnX, spawnZ := 0, 0
-
-	// Ensure spawn chunks are generated so we can check collisions
-	gameWorld.StreamChunksAroundSync(float32(spawnX), float32(spawnZ), 2)
-
-	// Calculate approximate Y (theoretical max)
-	approxY := gameWorld.SurfaceHeightAt(spawnX, spawnZ)
-
-	// Search for actual ground starting slightly above approximate Y
-	searchStartPos := mgl32.Vec3{float32(spawnX), float32(approxY) + 5, float32(spawnZ)}
-	pWidth, pHeight := gamePlayer.GetBounds()
-	groundY := physics.FindGroundLevel(float32(spawnX), float32(spawnZ), searchStartPos, pWidth, pHeight, gameWorld)
-
-	if groundY > -1000 {
-		// Found valid ground, place player exactly on it
-		gamePlayer.Position[1] = groundY
-	} else {
-		// Fallback to approximate height
-		gamePlayer.Position[1] = float32(approxY) + 2.0
+	if mode == player.GameModeSurvival {
+		// These have no recipe yet, so survival starts with them
+		for _, starter := range []item.ItemStack{
+			item.NewItemStack(world.BlockTypeItemFrame, 4),
+			item.NewItemStack(world.BlockTypeRail, 32),
+			item.NewItemStack(world.BlockTypeMinecart, 1),
+		} {
+			gamePlayer.Inventory.AddItem(&starter)
+		}
 	}
+	gamePlayer.Position = spawn
 
 	// Reset velocity just in case
 	gamePlayer.Velocity = [3]float32{0, 0, 0}
 
-	// Set cursor disabled for game
-	window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
-
-	width, height := window.GetSize()
+	width, height := ui.ScreenOf(window).Size()
 	r.UpdateViewport(width, height)
 
-	// Connect inventory state changes to HUD
+	connectFeedback(gamePlayer, particlesRenderer)
+
+	// Connect inventory state changes to HUD and the cursor, however the
+	// screen was opened
 	gamePlayer.OnInventoryStateChange = func(isOpen bool) {
 		hudRenderer.SetInventoryOpen(isOpen, gamePlayer)
+		if isOpen {
+			cursor.Push(standardInput.ContextInventory)
+		} else {
+			cursor.Pop(standardInput.ContextInventory)
+		}
 	}
 
-	return &Session{
+	players := &presence.List{OnMessage: func(text string) {
+		slog.Info(text)
+	}}
+	if _, err := players.Join(name); err != nil {
+		r.Dispose()
+		return nil, err
+	}
+	players.SetPing(name, 0)
+
+	s := &Session{
 		Window:           window,
 		Renderer:         r,
 		UIRenderer:       uiRenderer,
 		HUDRenderer:      hudRenderer,
 		World:            gameWorld,
 		Player:           gamePlayer,
+		Players:          players,
 		PauseMenu:        menu.NewPauseMenu(),
+		DeathScreen:      menu.NewDeathScreen(),
+		spawn:            spawn,
+		cursor:           cursor,
+		icon:             iconCapture,
+		others:           othersRenderer,
+		ghost:            ghostRenderer,
+		engine:           sim.NewEngine(gameWorld),
+		music:            sound.NewMusic(musicDir, gameWorld.Seed()),
 		LastFPSCheckTime: time.Now(),
-	}, nil
+		lastAutosave:     time.Now(),
+	}
+	s.music.OnTrackStart = hudRenderer.ShowNowPlaying
+	s.commands = command.NewDispatcher()
+	command.RegisterGame(s.commands, consoleGame{s})
+	command.RegisterReplay(s.commands, consoleReplays{s})
+	command.RegisterPregen(s.commands, consolePregen{s})
+	s.engine.OnTick = s.tickPlayer
+	s.engine.Mobs = &entity.MobSpawner{Target: gamePlayer}
+	config.OnRenderDistanceChange(s.renderDistanceChanged)
+	cursor.Reset()
+	cursor.Push(standardInput.ContextGameplay)
+	return s, nil
 }
 
 func (s *Session) Cleanup() {
+	config.OnRenderDistanceChange(nil)
+	if s.remote != nil {
+		s.remote.client.Close()
+	}
+	s.music.Stop()
+	sound.SetPaused(false)
+	sound.SetMenuOpen(false)
+	s.stopPregen()
+	s.saveRecording()
+	if err := s.World.Save(); err != nil {
+		slog.Error("saving world failed", "err", err)
+	}
 	s.World.Close()
 	blocks.ShutdownMeshSystem()
 	s.Renderer.Dispose()
+	registry.UseWorldOverrides("")
 
 	// Explicilty nil out
 	s.World = nil
@@ -153,27 +294,58 @@ func (s *Session) Update(dt float64, im *standardInput.InputManager) menu.Action
 			return menu.ActionQuitToMenu
 		case menu.ActionQuitGame:
 			return menu.ActionQuitGame
+		case menu.ActionTogglePregen:
+			s.togglePregen()
+		}
+	}
+	if s.dead {
+		switch s.DeathScreen.Update(s.Window, im.JustPressed(standardInput.ActionMouseLeft)) {
+		case menu.ActionRespawn:
+			s.respawn()
+		case menu.ActionQuitToMenu:
+			return menu.ActionQuitToMenu
+		}
+	}
+	s.updatePregen()
+	s.updateRenderDistanceGrowth()
+	sound.SetPaused(s.Paused)
+	sound.SetMenuOpen(s.Paused || s.Player.IsInventoryOpen)
+	cam := s.Player.Camera()
+	sound.SetListener(cam.Eye, cam.Front)
+	sound.Update(dt)
+	s.music.Update(dt, s.musicMood())
+	s.updateTeleport(dt)
+	if s.remote != nil {
+		if err := s.updateRemote(dt); err != nil {
+			slog.Error("disconnected from server", "addr", s.remote.addr, "err", err)
+			return menu.ActionQuitToMenu
 		}
 	}
 
 	if !s.Paused {
-		profiling.Track("player.Update")
-		s.Player.Update(dt, im)
-		profiling.Track("world.UpdateEntities")
-		s.World.UpdateEntities(dt)
-
-		// Fixed-rate game ticks at 20 TPS (0.05 s per tick).
-		// Cap to 10 ticks per frame to prevent spiral-of-death on slow frames.
-		s.tickAccumulator += dt
-		ticksThisFrame := 0
-		for s.tickAccumulator >= 0.05 && ticksThisFrame < 10 {
-			s.World.Tick()
-			s.tickAccumulator -= 0.05
-			ticksThisFrame++
+		s.playTime += dt
+		if level := s.World.Level(); level != nil {
+			level.PlayTime += dt
+		}
+		s.icon.Update(s.playTime)
+
+		if !s.teleport.holdsPlayer() && !s.following() {
+			profiling.Track("player.Update")
+			s.Player.Update(dt, im)
 		}
-		// Hard cap: discard excess accumulation rather than spiral.
-		if s.tickAccumulator > 0.5 {
-			s.tickAccumulator = 0.5
+		s.input = im
+		s.engine.DaySpeed = config.GetDayCycleSpeed()
+		s.engine.SimDistance = float32(config.GetEntitySimulationDistance() * world.ChunkSizeX)
+		s.engine.SetFocus(s.Player.Position[0], s.Player.Position[2])
+		s.engine.Update(dt)
+		s.updateReplay(s.engine.PartialTick())
+		s.Player.SetPartialTick(s.engine.PartialTick())
+		s.Renderer.SetEntityPartialTick(s.engine.EntityPartialTick())
+		if s.Player.IsDead() && !s.dead {
+			s.closeChat()
+			s.dead = true
+			s.DeathScreen.Open()
+			s.cursor.Push(standardInput.ContextDead)
 		}
 	}
 
@@ -183,19 +355,42 @@ func (s *Session) Update(dt float64, im *standardInput.InputManager) menu.Action
 	return menu.ActionNone
 }
 
+// respawn brings the dead player back at the spawn point and closes the
+// death screen.
+func (s *Session) respawn() {
+	s.dead = false
+	s.cursor.Pop(standardInput.ContextDead)
+	s.Player.Revive()
+	s.Teleport(s.spawn)
+}
+
+// tickPlayer runs the player's part of a game tick, unless a teleport is
+// holding them until terrain arrives or a replay is moving them, and
+// records or replays the tick.
+func (s *Session) tickPlayer() {
+	if !s.teleport.holdsPlayer() && !s.following() {
+		s.Player.Tick(s.input)
+	}
+	s.tickReplay()
+}
+
 func (s *Session) Render(dt float64) (time.Duration, time.Duration, time.Duration) {
 	renderStart := time.Now()
-	s.Renderer.Render(s.World, s.Player, dt)
-
-	// Render Pause Menu
-	if s.Paused {
-		s.UIRenderer.BeginFrame()
-		s.PauseMenu.Render(s.UIRenderer, s.Window)
-		s.UIRenderer.Flush()
+	if s.panoramaShot {
+		s.panoramaShot = false
+		s.takePanoramaShot()
 	}
+	s.Renderer.Render(s.World, s.Player, dt)
+	s.renderMenus()
 
 	renderDur := time.Since(renderStart)
 	s.HUDRenderer.ProfilingSetRenderDuration(renderDur)
+	s.HUDRenderer.ProfilingSetCulling(blocks.CullingStats())
+	s.HUDRenderer.ProfilingSetOcclusion(blocks.OcclusionStats())
+	s.HUDRenderer.ProfilingSetMeshMemory(blocks.MeshMemoryStats())
+	rendered, distant := items.EntityRenderStats()
+	mobsRendered, mobsDistant := playermodel.MobRenderStats()
+	s.HUDRenderer.ProfilingSetEntities(s.World.EntityUpdateStats(), rendered+mobsRendered, distant+mobsDistant)
 
 	s.Frames++
 	if time.Since(s.LastFPSCheckTime) >= time.Second {
@@ -208,19 +403,47 @@ func (s *Session) Render(dt float64) (time.Duration, time.Duration, time.Duratio
 
 func (s *Session) SetPaused(paused bool) {
 	s.Paused = paused
+	s.pausedByFocus = false
 	if s.Paused {
-		s.Window.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
-		w, h := s.Window.GetSize()
-		s.Window.SetCursorPos(float64(w)/2, float64(h)/2)
+		s.cursor.Push(standardInput.ContextPaused)
 	} else {
-		s.Window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
-		s.Player.FirstMouse = true
+		s.cursor.Pop(standardInput.ContextPaused)
+	}
+}
+
+// FocusChanged applies the focus settings when the window loses or regains
+// focus: losing it closes the inventory and pauses, if enabled, and getting
+// it back ends that pause if resuming on focus is on. The cursor controller
+// sees the change first and restores the cursor mode.
+func (s *Session) FocusChanged(focused bool) {
+	if !focused {
+		if s.Paused || s.dead || !config.GetPauseOnLostFocus() {
+			return
+		}
+		if s.Player.IsInventoryOpen {
+			s.Player.SetInventoryOpen(false)
+			s.Player.DropCursorItem()
+		}
+		s.closeChat()
+		s.SetPaused(true)
+		s.pausedByFocus = true
+		return
+	}
+
+	if s.pausedByFocus && config.GetResumeOnFocus() {
+		s.SetPaused(false)
 	}
 }
 
 func (s *Session) processWorldUpdates() {
-	if !s.Paused {
+	// While a teleport waits on terrain, streaming follows the destination
+	// and the area around the player is kept
+	waiting := s.teleport.holdsPlayer()
+
+	// On a server the chunks come from it, and go when it says
+	if !s.Paused && !waiting && s.remote == nil {
 		func() {
+			s.World.SetStreamView(s.Player.GetFrontVector())
 			s.World.StreamChunksAroundAsync(s.Player.Position[0], s.Player.Position[2], config.GetChunkLoadRadius())
 		}()
 	}
@@ -231,16 +454,28 @@ func (s *Session) processWorldUpdates() {
 	}()
 
 	// Periodic cleanup (every 1 second)
-	if time.Since(s.lastEviction) > time.Second {
+	if time.Since(s.lastEviction) > time.Second && !waiting {
 		func() {
 			defer profiling.Track("world.EvictFarChunks")()
 			// Use EvictRadius (e.g. 2x render distance) to avoid thrashing
 			evictRadius := config.GetChunkEvictRadius()
-			s.World.EvictFarChunks(s.Player.Position[0], s.Player.Position[2], evictRadius)
-			blocks.PruneMeshesByWorld(s.World, s.Player.Position[0], s.Player.Position[2], evictRadius)
+			if s.remote == nil {
+				s.World.EvictFarChunks(s.Player.Position[0], s.Player.Position[2], evictRadius)
+			}
+			blocks.PruneMeshesByWorld(s.World, s.Player.Position[0], s.Player.Position[1], s.Player.Position[2], evictRadius)
 		}()
 		s.lastEviction = time.Now()
 	}
+
+	if time.Since(s.lastAutosave) > autosaveInterval && !s.Paused {
+		func() {
+			defer profiling.Track("world.Autosave")()
+			if err := s.World.Autosave(); err != nil {
+				slog.Error("autosave failed", "err", err)
+			}
+		}()
+		s.lastAutosave = time.Now()
+	}
 }
 
 func (s *Session) handleInputActions(im *standardInput.InputManager) {
@@ -275,39 +510,37 @@ func (s *Session) handleInputActions(im *standardInput.InputManager) {
 	}
 
 	if im.JustPressed(standardInput.ActionDropItem) {
-		if !s.Paused && !p.IsInventoryOpen {
+		if !s.Paused && !s.dead && !p.IsInventoryOpen {
 			dropStack := im.IsActive(standardInput.ActionModControl)
 			p.DropHeldItem(dropStack)
 		}
 	}
 
 	if im.JustPressed(standardInput.ActionInventory) {
-		if !s.Paused {
+		if !s.Paused && !s.dead {
 			newState := !p.IsInventoryOpen
 			p.SetInventoryOpen(newState)
-			if newState {
-				s.Window.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
-				w, h := s.Window.GetSize()
-				s.Window.SetCursorPos(float64(w)/2, float64(h)/2)
-			} else {
+			if !newState {
 				p.DropCursorItem()
-				s.Window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
-				p.FirstMouse = true
 			}
 		}
 	}
 
-	if im.JustPressed(standardInput.ActionPause) {
+	if im.JustPressed(standardInput.ActionPause) && !s.dead {
 		if p.IsInventoryOpen {
 			p.SetInventoryOpen(false)
 			p.DropCursorItem()
-			s.Window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
-			p.FirstMouse = true
 		} else {
 			s.SetPaused(!s.Paused)
 		}
 	}
 
+	if im.IsActive(standardInput.ActionPlayerList) && !s.Paused && !p.IsInventoryOpen {
+		s.HUDRenderer.SetPlayerList(s.Players.Entries())
+	} else {
+		s.HUDRenderer.SetPlayerList(nil)
+	}
+
 	if im.JustPressed(standardInput.ActionToggleWireframe) {
 		config.ToggleWireframeMode()
 	}
@@ -315,11 +548,59 @@ func (s *Session) handleInputActions(im *standardInput.InputManager) {
 	if im.JustPressed(standardInput.ActionToggleProfiling) {
 		s.HUDRenderer.ToggleProfiling()
 	}
+
+	if im.JustPressed(standardInput.ActionToggleColumnCulling) {
+		config.TogglePackedColumnCulling()
+	}
+
+	if im.JustPressed(standardInput.ActionToggleOcclusionCulling) {
+		config.ToggleOcclusionCulling()
+	}
+
+	if im.JustPressed(standardInput.ActionToggleLODDebugTint) {
+		config.ToggleLODDebugTint()
+	}
+
+	if im.JustPressed(standardInput.ActionToggleLogViewer) {
+		s.HUDRenderer.ToggleLogViewer()
+	}
+
+	if im.JustPressed(standardInput.ActionToggleNetGraph) {
+		s.HUDRenderer.ToggleNetGraph()
+	}
+
+	if im.JustPressed(standardInput.ActionToggleDebugScreen) {
+		s.HUDRenderer.ToggleDebugScreen()
+	}
+
+	if im.JustPressed(standardInput.ActionToggleBuilderMode) && !s.Paused && !p.IsInventoryOpen {
+		p.ToggleBuilderMode()
+	}
+
+	if im.JustPressed(standardInput.ActionCycleMirrorAxis) && p.BuilderActive() {
+		p.CycleMirrorAxis()
+	}
+
+	if im.JustPressed(standardInput.ActionPanoramaShot) && !s.Paused {
+		s.panoramaShot = true
+	}
+
+	if im.JustPressed(standardInput.ActionCyclePerspective) && !s.Paused {
+		p.CyclePerspective()
+	}
+
+	if !s.Paused && !s.dead && !p.IsInventoryOpen && !s.chatOpen {
+		if im.JustPressed(standardInput.ActionChat) {
+			s.openChat("", im)
+		} else if im.JustPressed(standardInput.ActionCommand) {
+			s.openChat("/", im)
+		}
+	}
 }
 
 func (s *Session) handleHotbar(slot int) {
 	if s.Player.IsInventoryOpen {
-		s.HUDRenderer.MoveHoveredItemToHotbar(slot)
+		s.HUDRenderer.MoveHoveredItemToHotbar(s.Player, slot)
 	} else {
 		s.Player.HandleNumKey(slot)
 	}
@@ -328,10 +609,21 @@ func (s *Session) handleHotbar(slot int) {
 func (s *Session) RefreshRender() {
 	dt := 0.016
 	s.Renderer.Render(s.World, s.Player, dt)
+	s.renderMenus()
+	s.Window.SwapBuffers()
+}
+
+// renderMenus draws the death screen and the pause menu over the world.
+func (s *Session) renderMenus() {
+	if !s.dead && !s.Paused {
+		return
+	}
+	s.UIRenderer.BeginFrame()
+	if s.dead {
+		s.DeathScreen.Render(s.UIRenderer, s.Window)
+	}
 	if s.Paused {
-		s.UIRenderer.BeginFrame()
 		s.PauseMenu.Render(s.UIRenderer, s.Window)
-		s.UIRenderer.Flush()
 	}
-	s.Window.SwapBuffers()
+	s.UIRenderer.Flush()
 }
@@ -1,6 +1,8 @@
 package game
 
 import (
+	"log/slog"
+
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 )
@@ -11,10 +13,13 @@ func SetupWindow() (*glfw.Window, error) {
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 
-	window, err := glfw.CreateWindow(900, 600, "Minecraft", nil, nil)
+	window, err := glfw.CreateWindow(900, 600, appTitle, nil, nil)
 	if err != nil {
 		return nil, err
 	}
+	if err := setWindowIcon(window); err != nil {
+		slog.Warn("window icon not set", "err", err)
+	}
 	window.MakeContextCurrent()
 
 	// Initialize OpenGL bindings
@@ -0,0 +1,113 @@
+package game
+
+import (
+	"log/slog"
+	"math"
+
+	"mini-mc/internal/graphics/renderables/blocks"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// teleportRadius is how many chunk columns around a teleport destination
+	// are loaded and meshed before the player is moved there
+	teleportRadius = 2
+
+	teleportFade    = 0.25 // seconds to fade out, and again to fade back in
+	teleportTimeout = 10.0 // seconds to wait for terrain before moving anyway
+)
+
+type teleportPhase int
+
+const (
+	teleportIdle teleportPhase = iota
+	teleportFadeOut
+	teleportLoading
+	teleportFadeIn
+)
+
+// teleport is a move of the player to a far destination: the screen fades
+// out, the terrain around the destination is streamed and meshed while the
+// player waits, then the player is moved and the screen fades back in.
+type teleport struct {
+	phase teleportPhase
+	dest  mgl32.Vec3
+	t     float64 // seconds in the current phase
+}
+
+// holdsPlayer reports whether the player is frozen waiting to be moved.
+func (tp *teleport) holdsPlayer() bool {
+	return tp.phase == teleportFadeOut || tp.phase == teleportLoading
+}
+
+// fade returns the opacity of the black overlay.
+func (tp *teleport) fade() float32 {
+	switch tp.phase {
+	case teleportFadeOut:
+		return float32(tp.t / teleportFade)
+	case teleportLoading:
+		return 1
+	case teleportFadeIn:
+		return float32(1 - tp.t/teleportFade)
+	default:
+		return 0
+	}
+}
+
+// Teleport moves the player to dest. If the terrain there is already loaded
+// and meshed the move is immediate; otherwise the screen fades to black until
+// it is, so the player never arrives in empty space.
+func (s *Session) Teleport(dest mgl32.Vec3) {
+	s.teleport = teleport{dest: dest}
+	if s.destinationReady() {
+		s.Player.Teleport(dest)
+		return
+	}
+	s.teleport.phase = teleportFadeOut
+}
+
+// destinationReady reports whether the terrain around the teleport
+// destination is loaded and meshed, queueing meshes for loaded chunks.
+func (s *Session) destinationReady() bool {
+	dest := s.teleport.dest
+	if !s.World.AreaLoaded(dest[0], dest[2], teleportRadius) {
+		return false
+	}
+	cx := int(math.Floor(float64(dest[0]) / world.ChunkSizeX))
+	cz := int(math.Floor(float64(dest[2]) / world.ChunkSizeZ))
+	return blocks.PrepareArea(s.World, cx, cz, teleportRadius)
+}
+
+// updateTeleport advances a teleport in progress and updates the fade.
+func (s *Session) updateTeleport(dt float64) {
+	tp := &s.teleport
+	tp.t += dt
+	switch tp.phase {
+	case teleportFadeOut:
+		if tp.t >= teleportFade {
+			tp.phase, tp.t = teleportLoading, 0
+		}
+	case teleportLoading:
+		// One column more than is meshed, so edge chunks mesh against
+		// their neighbours. On a server they are on their way already.
+		if s.remote == nil {
+			s.World.StreamChunksAroundAsync(tp.dest[0], tp.dest[2], teleportRadius+1)
+		}
+		ready := s.destinationReady()
+		if !ready && tp.t < teleportTimeout {
+			break
+		}
+		if !ready {
+			slog.Warn("teleport destination not ready, moving anyway", "x", tp.dest[0], "y", tp.dest[1], "z", tp.dest[2])
+		}
+		s.Player.Teleport(tp.dest)
+		tp.phase, tp.t = teleportFadeIn, 0
+	case teleportFadeIn:
+		if tp.t >= teleportFade {
+			tp.phase = teleportIdle
+		}
+	}
+	s.HUDRenderer.SetFade(tp.fade(), tp.phase == teleportLoading)
+}
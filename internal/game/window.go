@@ -0,0 +1,147 @@
+package game
+
+import (
+	"embed"
+	"fmt"
+	"image"
+	"image/png"
+	"io/fs"
+	"mini-mc/internal/config"
+	"mini-mc/internal/graphics/renderables/ui"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// appTitle is the window title outside a world; in game the world name is
+// appended.
+const appTitle = "Mini MC"
+
+// The window icon at several sizes; the platform picks the closest one for
+// the title bar, taskbar and task switcher.
+//
+//go:embed icons/*.png
+var iconFiles embed.FS
+
+// loadWindowIcons decodes the embedded icon images.
+func loadWindowIcons() ([]image.Image, error) {
+	names, err := fs.Glob(iconFiles, "icons/*.png")
+	if err != nil {
+		return nil, err
+	}
+	icons := make([]image.Image, 0, len(names))
+	for _, name := range names {
+		f, err := iconFiles.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		img, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		icons = append(icons, img)
+	}
+	return icons, nil
+}
+
+// windowTitle tracks frames so the title can show the frame rate, and
+// remembers the last title set to avoid redundant calls into the window
+// system.
+type windowTitle struct {
+	current    string
+	frames     int
+	fps        int
+	lastSample time.Time
+}
+
+// update counts a frame and sets the title from the app's state. The frame
+// rate is sampled once a second.
+func (t *windowTitle) update(a *App) {
+	t.frames++
+	now := time.Now()
+	if elapsed := now.Sub(t.lastSample); elapsed >= time.Second {
+		t.fps = int(float64(t.frames)/elapsed.Seconds() + 0.5)
+		t.frames = 0
+		t.lastSample = now
+	}
+
+	title := appTitle
+	if a.state == StatePlaying && a.session != nil && a.session.World != nil {
+		if level := a.session.World.Level(); level != nil {
+			title += " - " + level.Name
+		} else if a.session.remote != nil {
+			title += " - " + a.session.remote.addr
+		}
+	}
+	if config.GetFPSInTitle() {
+		title += fmt.Sprintf(" - %d FPS", t.fps)
+	}
+	if title != t.current {
+		a.window.SetTitle(title)
+		t.current = title
+	}
+}
+
+// setWindowIcon installs the embedded icon. Platforms without window icons
+// (macOS, Wayland) ignore it.
+func setWindowIcon(window *glfw.Window) error {
+	icons, err := loadWindowIcons()
+	if err != nil {
+		return err
+	}
+	window.SetIcon(icons)
+	return nil
+}
+
+// windowedPlacement is the position and size of the window before it went
+// fullscreen.
+type windowedPlacement struct {
+	x, y, width, height int
+}
+
+// setFullscreen moves the window onto the primary monitor at its current
+// video mode, or back to where it was before. The size callbacks then update
+// the viewport, projection and UI layout.
+func (a *App) setFullscreen(on bool) {
+	if on == (a.window.GetMonitor() != nil) {
+		return
+	}
+	if !on {
+		p := a.windowed
+		a.window.SetMonitor(nil, p.x, p.y, p.width, p.height, 0)
+		return
+	}
+	monitor := glfw.GetPrimaryMonitor()
+	if monitor == nil {
+		return
+	}
+	mode := monitor.GetVideoMode()
+	a.windowed.x, a.windowed.y = a.window.GetPos()
+	a.windowed.width, a.windowed.height = a.window.GetSize()
+	a.window.SetMonitor(monitor, 0, 0, mode.Width, mode.Height, mode.RefreshRate)
+}
+
+// relayout draws to the whole framebuffer and lays the menus, the HUD and
+// the world's projection out in UI units (see ui.Screen), so they keep their
+// size across HiDPI screens. It runs whenever the window, its framebuffer or
+// the monitor's content scale change.
+func (a *App) relayout() {
+	fbW, fbH := a.window.GetFramebufferSize()
+	if fbW == 0 || fbH == 0 {
+		return // minimized; keep the last layout rather than divide by zero
+	}
+	gl.Viewport(0, 0, int32(fbW), int32(fbH))
+
+	width, height := ui.ScreenOf(a.window).Size()
+	a.menuUI.SetViewport(width, height)
+	a.fontRenderer.SetViewport(float32(width), float32(height))
+	if a.session != nil {
+		a.session.Renderer.UpdateViewport(width, height)
+		a.session.UIRenderer.SetViewport(width, height)
+	}
+	if a.panorama != nil {
+		a.panorama.UpdateViewport(width, height)
+	}
+}
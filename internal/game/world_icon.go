@@ -0,0 +1,148 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"mini-mc/internal/graphics"
+	"mini-mc/internal/graphics/renderer"
+	"mini-mc/internal/ui/menu"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+const (
+	worldIconSize  = 64  // edge of the square thumbnail, in pixels
+	worldIconDelay = 5.0 // seconds of play before the icon is taken
+)
+
+// worldIcon is a renderable placed after the world renderables. Once armed
+// it grabs the next frame, before the hand and HUD are drawn, and saves it
+// as the world's thumbnail, like 1.8.9 does a few seconds into play.
+type worldIcon struct {
+	dir           string // world save directory
+	width, height int
+	armed         bool
+	taken         bool
+}
+
+func newWorldIcon(dir string) *worldIcon {
+	return &worldIcon{dir: dir, width: 900, height: 600}
+}
+
+// Update arms the capture once the player has been in the world for a while.
+// playTime is the session's unpaused play so far.
+func (c *worldIcon) Update(playTime float64) {
+	if !c.taken && c.dir != "" && playTime >= worldIconDelay {
+		c.armed = true
+	}
+}
+
+func (c *worldIcon) Init() error { return nil }
+
+func (c *worldIcon) Render(ctx renderer.RenderContext) {
+	if !c.armed {
+		return
+	}
+	c.armed, c.taken = false, true
+
+	// Centre square of the frame, bottom row first
+	side := min(c.width, c.height)
+	x0, y0 := (c.width-side)/2, (c.height-side)/2
+	frame := make([]byte, side*side*4)
+	gl.ReadPixels(int32(x0), int32(y0), int32(side), int32(side), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(frame))
+
+	path := filepath.Join(c.dir, world.IconFile)
+	go func() {
+		if err := writeWorldIcon(path, frame, side); err != nil {
+			slog.Warn("saving world icon failed", "err", err)
+		}
+	}()
+}
+
+func (c *worldIcon) Dispose() {}
+
+// Stage captures the icon once the world, translucent geometry included, is
+// drawn, before the hand and HUD.
+func (c *worldIcon) Stage() renderer.Stage {
+	return renderer.StageOverlay
+}
+
+func (c *worldIcon) SetViewport(width, height int) {
+	c.width, c.height = width, height
+}
+
+// writeWorldIcon box-filters a side x side bottom-up RGBA frame down to the
+// icon size and writes it as a PNG.
+func writeWorldIcon(path string, frame []byte, side int) error {
+	icon := image.NewRGBA(image.Rect(0, 0, worldIconSize, worldIconSize))
+	for iy := range worldIconSize {
+		sy0, sy1 := iy*side/worldIconSize, max((iy+1)*side/worldIconSize, iy*side/worldIconSize+1)
+		for ix := range worldIconSize {
+			sx0, sx1 := ix*side/worldIconSize, max((ix+1)*side/worldIconSize, ix*side/worldIconSize+1)
+			var sum [3]int
+			for sy := sy0; sy < sy1; sy++ {
+				row := (side - 1 - sy) * side * 4 // flip to top row first
+				for sx := sx0; sx < sx1; sx++ {
+					for c := range 3 {
+						sum[c] += int(frame[row+sx*4+c])
+					}
+				}
+			}
+			n := (sy1 - sy0) * (sx1 - sx0)
+			o := icon.PixOffset(ix, iy)
+			for c := range 3 {
+				icon.Pix[o+c] = uint8(sum[c] / n)
+			}
+			icon.Pix[o+3] = 255
+		}
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	err = png.Encode(f, icon)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// loadWorldInfo lists the saved world, if any, on the main menu with its
+// icon and play stats.
+func (a *App) loadWorldInfo() {
+	if a.worldIconTex != 0 {
+		gl.DeleteTextures(1, &a.worldIconTex)
+		a.worldIconTex = 0
+	}
+	level, err := world.ReadLevel(worldSaveDir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("reading world metadata failed", "err", err)
+		}
+		a.mainMenu.SetWorld(nil)
+		return
+	}
+	if tex, _, _, err := graphics.LoadTexture(filepath.Join(worldSaveDir, world.IconFile)); err == nil {
+		a.worldIconTex = tex
+	}
+
+	minutes := int(level.PlayTime / 60)
+	details := fmt.Sprintf("%s, played %dh %02dm", level.GameMode, minutes/60, minutes%60)
+	if !level.LastPlayed.IsZero() {
+		details += ", last " + level.LastPlayed.Format("2006-01-02 15:04")
+	}
+	a.mainMenu.SetWorld(&menu.WorldInfo{Name: level.Name, Details: details, IconTex: a.worldIconTex})
+}
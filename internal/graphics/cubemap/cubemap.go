@@ -0,0 +1,99 @@
+// Package cubemap stitches the six square 90 degree views around a point
+// into an equirectangular panorama: longitude across, latitude down, as
+// 360 photo viewers and panorama backgrounds expect.
+package cubemap
+
+import (
+	"image"
+	"math"
+
+	"mini-mc/internal/mathutil"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Face is one side of the cube: the direction its view looks along and the
+// direction at the top of its image.
+type Face struct {
+	Front, Up mgl32.Vec3
+}
+
+// Right returns the direction towards the right edge of the face's image.
+func (f Face) Right() mgl32.Vec3 {
+	return f.Front.Cross(f.Up)
+}
+
+// Faces are the cube's sides in the order Equirect takes their images:
+// +X, -X, +Y, -Y, +Z, -Z.
+var Faces = [6]Face{
+	{Front: mgl32.Vec3{1, 0, 0}, Up: mgl32.Vec3{0, 1, 0}},
+	{Front: mgl32.Vec3{-1, 0, 0}, Up: mgl32.Vec3{0, 1, 0}},
+	{Front: mgl32.Vec3{0, 1, 0}, Up: mgl32.Vec3{0, 0, -1}},
+	{Front: mgl32.Vec3{0, -1, 0}, Up: mgl32.Vec3{0, 0, 1}},
+	{Front: mgl32.Vec3{0, 0, 1}, Up: mgl32.Vec3{0, 1, 0}},
+	{Front: mgl32.Vec3{0, 0, -1}, Up: mgl32.Vec3{0, 1, 0}},
+}
+
+// Equirect stitches the square images of Faces, top row first, into a
+// panorama width pixels across and half that high. heading, a horizontal
+// unit vector, is at the centre of the panorama; turning right moves right.
+func Equirect(faces [6]*image.RGBA, width int, heading mgl32.Vec3) *image.RGBA {
+	height := width / 2
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	up := mgl32.Vec3{0, 1, 0}
+	right := heading.Cross(up)
+	for y := range height {
+		lat := (0.5 - (float64(y)+0.5)/float64(height)) * math.Pi
+		cosLat, sinLat := float32(math.Cos(lat)), float32(math.Sin(lat))
+		for x := range width {
+			lon := ((float64(x)+0.5)/float64(width) - 0.5) * 2 * math.Pi
+			cosLon, sinLon := float32(math.Cos(lon)), float32(math.Sin(lon))
+			dir := heading.Mul(cosLat * cosLon).Add(right.Mul(cosLat * sinLon)).Add(up.Mul(sinLat))
+			o := out.PixOffset(x, y)
+			sample(faces, dir, out.Pix[o:o+4])
+		}
+	}
+	return out
+}
+
+// faceOf returns the index in Faces of the side dir points through.
+func faceOf(dir mgl32.Vec3) int {
+	axis := 0
+	for i := 1; i < 3; i++ {
+		if mathutil.Abs(dir[i]) > mathutil.Abs(dir[axis]) {
+			axis = i
+		}
+	}
+	if dir[axis] < 0 {
+		return 2*axis + 1
+	}
+	return 2 * axis
+}
+
+// sample writes into px the colour seen looking along dir, filtered
+// bilinearly within the face it falls on.
+func sample(faces [6]*image.RGBA, dir mgl32.Vec3, px []byte) {
+	i := faceOf(dir)
+	f, img := Faces[i], faces[i]
+	size := img.Rect.Dx()
+	t := dir.Dot(f.Front)
+	a, b := dir.Dot(f.Right())/t, dir.Dot(f.Up)/t // -1 to 1 across the face
+
+	fx := (a+1)/2*float32(size) - 0.5
+	fy := (1-b)/2*float32(size) - 0.5
+	x0, y0 := clamp(int(math.Floor(float64(fx))), size), clamp(int(math.Floor(float64(fy))), size)
+	x1, y1 := clamp(x0+1, size), clamp(y0+1, size)
+	tx, ty := min(max(fx-float32(x0), 0), 1), min(max(fy-float32(y0), 0), 1)
+
+	o00, o10 := img.PixOffset(x0, y0), img.PixOffset(x1, y0)
+	o01, o11 := img.PixOffset(x0, y1), img.PixOffset(x1, y1)
+	for c := range 4 {
+		top := float32(img.Pix[o00+c])*(1-tx) + float32(img.Pix[o10+c])*tx
+		bottom := float32(img.Pix[o01+c])*(1-tx) + float32(img.Pix[o11+c])*tx
+		px[c] = uint8(top*(1-ty) + bottom*ty + 0.5)
+	}
+}
+
+func clamp(i, size int) int {
+	return min(max(i, 0), size-1)
+}
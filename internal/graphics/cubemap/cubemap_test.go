@@ -0,0 +1,70 @@
+package cubemap
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// solidFaces returns faces filled with a different colour each, and the
+// colours.
+func solidFaces(size int) ([6]*image.RGBA, [6]color.RGBA) {
+	var faces [6]*image.RGBA
+	var colors [6]color.RGBA
+	for i := range faces {
+		colors[i] = color.RGBA{uint8(40 * (i + 1)), uint8(200 - 30*i), uint8(i * 7), 255}
+		faces[i] = image.NewRGBA(image.Rect(0, 0, size, size))
+		for p := 0; p < len(faces[i].Pix); p += 4 {
+			c := colors[i]
+			copy(faces[i].Pix[p:], []byte{c.R, c.G, c.B, c.A})
+		}
+	}
+	return faces, colors
+}
+
+func TestEquirectPlacesEachFace(t *testing.T) {
+	faces, colors := solidFaces(8)
+	heading := mgl32.Vec3{0, 0, -1} // -Z
+	pano := Equirect(faces, 64, heading)
+
+	// The panorama's right of -Z is +X, and behind it +Z
+	for _, tc := range []struct {
+		name string
+		x, y int
+		face int
+	}{
+		{"ahead", 32, 16, 5},
+		{"right", 48, 16, 0},
+		{"left", 16, 16, 1},
+		{"behind", 0, 16, 4},
+		{"above", 20, 0, 2},
+		{"below", 40, 31, 3},
+	} {
+		if got := pano.RGBAAt(tc.x, tc.y); got != colors[tc.face] {
+			t.Errorf("%s (%d, %d) = %v, want face %d's %v", tc.name, tc.x, tc.y, got, tc.face, colors[tc.face])
+		}
+	}
+}
+
+func TestFacesMapBackToThemselves(t *testing.T) {
+	for i, f := range Faces {
+		if got := faceOf(f.Front); got != i {
+			t.Errorf("face %d's front lands on face %d", i, got)
+		}
+		if f.Front.Dot(f.Up) != 0 {
+			t.Errorf("face %d's up is not square to its front", i)
+		}
+	}
+
+	// A marker in the top right corner of +X shows up looking that way
+	faces, _ := solidFaces(8)
+	faces[0].SetRGBA(7, 0, color.RGBA{255, 255, 255, 255})
+	dir := Faces[0].Front.Add(Faces[0].Right().Mul(0.9)).Add(Faces[0].Up.Mul(0.9))
+	px := make([]byte, 4)
+	sample(faces, dir, px)
+	if px[0] != 255 || px[1] != 255 {
+		t.Errorf("top right of +X sampled as %v, want the white marker", px)
+	}
+}
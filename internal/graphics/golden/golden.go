@@ -0,0 +1,215 @@
+// Package golden supports golden-image rendering tests: scenes are drawn into
+// an offscreen framebuffer, read back, and compared with reference PNGs under
+// testdata/ within a per-channel tolerance.
+//
+// Run the tests (they skip when no GL 4.1 context can be created):
+//
+//	go test ./internal/graphics/golden/
+//
+// Regenerate the reference images after an intended visual change:
+//
+//	go test ./internal/graphics/golden/ -update
+package golden
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Tolerance bounds how far a rendering may drift from its golden image.
+// Driver differences in rasterisation and filtering move a few pixels by a
+// few levels; a real regression changes many pixels by a lot.
+type Tolerance struct {
+	Channel        uint8   // largest per-channel difference a pixel may have and still match
+	MaxBadFraction float64 // fraction of pixels allowed to exceed Channel
+}
+
+// Diff summarises the difference between two images.
+type Diff struct {
+	BadPixels int   // pixels with a channel difference above the tolerance
+	Total     int   // pixels compared
+	MaxDelta  uint8 // largest channel difference seen
+}
+
+// BadFraction returns the fraction of pixels that did not match.
+func (d Diff) BadFraction() float64 {
+	if d.Total == 0 {
+		return 0
+	}
+	return float64(d.BadPixels) / float64(d.Total)
+}
+
+// Compare counts the pixels of got that differ from want by more than
+// tol.Channel in any channel. The images must be the same size.
+func Compare(got, want *image.RGBA, tol Tolerance) (Diff, bool, error) {
+	if got.Bounds().Size() != want.Bounds().Size() {
+		return Diff{}, false, fmt.Errorf("size %v, golden is %v", got.Bounds().Size(), want.Bounds().Size())
+	}
+	var d Diff
+	size := got.Bounds().Size()
+	for y := range size.Y {
+		gRow := got.Pix[y*got.Stride : y*got.Stride+size.X*4]
+		wRow := want.Pix[y*want.Stride : y*want.Stride+size.X*4]
+		for i := 0; i < len(gRow); i += 4 {
+			var delta uint8
+			for c := range 4 {
+				delta = max(delta, absDiff(gRow[i+c], wRow[i+c]))
+			}
+			d.MaxDelta = max(d.MaxDelta, delta)
+			if delta > tol.Channel {
+				d.BadPixels++
+			}
+			d.Total++
+		}
+	}
+	return d, d.BadFraction() <= tol.MaxBadFraction, nil
+}
+
+// DiffImage highlights in red the pixels of got that differ from want by more
+// than channel, over a dimmed copy of want, for inspecting failures.
+func DiffImage(got, want *image.RGBA, channel uint8) *image.RGBA {
+	b := want.Bounds()
+	out := image.NewRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			g, w := got.RGBAAt(x, y), want.RGBAAt(x, y)
+			delta := max(absDiff(g.R, w.R), absDiff(g.G, w.G), absDiff(g.B, w.B), absDiff(g.A, w.A))
+			if delta > channel {
+				out.SetRGBA(x, y, color.RGBA{R: 255, A: 255})
+				continue
+			}
+			out.SetRGBA(x, y, color.RGBA{R: w.R / 3, G: w.G / 3, B: w.B / 3, A: 255})
+		}
+	}
+	return out
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// LoadPNG reads a PNG file into an RGBA image.
+func LoadPNG(path string) (*image.RGBA, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba, nil
+	}
+	rgba := image.NewRGBA(img.Bounds())
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			rgba.Set(x, y, img.At(x, y))
+		}
+	}
+	return rgba, nil
+}
+
+// SavePNG writes img to path, creating parent directories as needed.
+func SavePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Target is an offscreen framebuffer with a color and a depth attachment.
+type Target struct {
+	Width, Height int
+
+	fbo   uint32
+	color uint32
+	depth uint32
+}
+
+// NewTarget creates a width x height framebuffer. A GL context must be current.
+func NewTarget(width, height int) (*Target, error) {
+	t := &Target{Width: width, Height: height}
+
+	gl.GenTextures(1, &t.color)
+	gl.BindTexture(gl.TEXTURE_2D, t.color)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, int32(width), int32(height), 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	gl.GenRenderbuffers(1, &t.depth)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, t.depth)
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH_COMPONENT24, int32(width), int32(height))
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+
+	gl.GenFramebuffers(1, &t.fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, t.fbo)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, t.color, 0)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.RENDERBUFFER, t.depth)
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		t.Dispose()
+		return nil, fmt.Errorf("framebuffer incomplete: 0x%x", status)
+	}
+	return t, nil
+}
+
+// Bind makes the target the draw framebuffer and sets the viewport to it.
+func (t *Target) Bind() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, t.fbo)
+	gl.Viewport(0, 0, int32(t.Width), int32(t.Height))
+}
+
+// ReadPixels returns the target's color attachment, top row first.
+func (t *Target) ReadPixels() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, t.Width, t.Height))
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, t.fbo)
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(0, 0, int32(t.Width), int32(t.Height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, 0)
+
+	// GL rows start at the bottom
+	row := make([]byte, img.Stride)
+	for y := range t.Height / 2 {
+		top := img.Pix[y*img.Stride : (y+1)*img.Stride]
+		bottom := img.Pix[(t.Height-1-y)*img.Stride : (t.Height-y)*img.Stride]
+		copy(row, top)
+		copy(top, bottom)
+		copy(bottom, row)
+	}
+	return img
+}
+
+// Dispose releases the framebuffer and its attachments.
+func (t *Target) Dispose() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	if t.fbo != 0 {
+		gl.DeleteFramebuffers(1, &t.fbo)
+	}
+	if t.color != 0 {
+		gl.DeleteTextures(1, &t.color)
+	}
+	if t.depth != 0 {
+		gl.DeleteRenderbuffers(1, &t.depth)
+	}
+}
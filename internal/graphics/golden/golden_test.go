@@ -0,0 +1,33 @@
+package golden
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestCompareTolerance(t *testing.T) {
+	want := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	got := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for i := range want.Pix {
+		want.Pix[i] = 100
+		got.Pix[i] = 105 // within the channel tolerance everywhere
+	}
+	got.SetRGBA(3, 4, color.RGBA{R: 200, G: 100, B: 100, A: 100})
+
+	diff, ok, err := Compare(got, want, Tolerance{Channel: 8, MaxBadFraction: 0.01})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.BadPixels != 1 || diff.MaxDelta != 100 || diff.Total != 100 {
+		t.Fatalf("diff = %+v, want 1 bad pixel of 100 with max delta 100", diff)
+	}
+	if !ok {
+		t.Fatal("1 bad pixel in 100 failed a 1% budget")
+	}
+
+	got.SetRGBA(5, 5, color.RGBA{A: 255})
+	if _, ok, _ := Compare(got, want, Tolerance{Channel: 8, MaxBadFraction: 0.01}); ok {
+		t.Fatal("2 bad pixels in 100 passed a 1% budget")
+	}
+}
@@ -0,0 +1,254 @@
+package golden
+
+import (
+	"flag"
+	"image"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"mini-mc/internal/config"
+	"mini-mc/internal/graphics/renderables/blocks"
+	"mini-mc/internal/graphics/renderer"
+	"mini-mc/internal/player"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden images instead of comparing")
+
+const (
+	sceneWidth  = 320
+	sceneHeight = 180
+
+	sceneSeed        = 1234
+	sceneRadius      = 4   // chunks generated and rendered around the camera
+	maxSettleFrames  = 600 // frames to wait for every chunk mesh to be built
+	goldenDir        = "internal/graphics/golden/testdata"
+	failureOutputDir = "internal/graphics/golden/testdata/failed"
+)
+
+// defaultTolerance absorbs driver differences in rasterisation and texture
+// filtering, plus the animated water surface.
+var defaultTolerance = Tolerance{Channel: 12, MaxBadFraction: 0.01}
+
+// glWindow holds the hidden window whose GL 4.1 context the scenes render
+// with. It is nil when no context could be created (no display and no
+// software driver); the scene tests then skip.
+var glWindow *glfw.Window
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+
+	// Shaders, textures and block models load from the project root.
+	if err := os.Chdir("../../.."); err != nil {
+		panic("cannot chdir to project root: " + err.Error())
+	}
+
+	// GLFW and every GL call stay on the main thread.
+	runtime.LockOSThread()
+
+	code := func() int {
+		if err := glfw.Init(); err != nil {
+			return m.Run()
+		}
+		defer glfw.Terminate()
+
+		glfw.WindowHint(glfw.ContextVersionMajor, 4)
+		glfw.WindowHint(glfw.ContextVersionMinor, 1)
+		glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+		glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
+		glfw.WindowHint(glfw.Visible, glfw.False)
+		window, err := glfw.CreateWindow(1, 1, "golden", nil, nil)
+		if err != nil {
+			return m.Run()
+		}
+		window.MakeContextCurrent()
+		if gl.Init() == nil {
+			glWindow = window
+		}
+		// Tests run on goroutines of their own, which take the context
+		glfw.DetachCurrentContext()
+		return m.Run()
+	}()
+	os.Exit(code)
+}
+
+// scene is a deterministic view: a fixed world seed, optional edits and a
+// fixed camera.
+type scene struct {
+	name       string
+	x, y, z    float32 // camera feet position; y is relative to the surface
+	yaw, pitch float64
+	build      func(w *world.World, surface int)
+}
+
+var scenes = []scene{
+	{name: "overview", x: 8, y: 40, z: 8, yaw: 45, pitch: -35},
+	{name: "ground_level", x: 8, y: 1, z: 8, yaw: 200, pitch: -5},
+	{
+		// A row of special-cased blocks on a platform above the terrain, to
+		// cover cutout leaves, sub-block geometry and multi-texture blocks.
+		name: "block_showcase", x: 8.5, y: 22, z: 2, yaw: 90, pitch: -20,
+		build: func(w *world.World, surface int) {
+			y := surface + 20
+			for x := 2; x <= 15; x++ {
+				for z := 6; z <= 10; z++ {
+					w.Set(x, y, z, world.BlockTypeStone)
+				}
+			}
+			row := []world.BlockType{
+				world.BlockTypeOakLeaves, world.BlockTypeFurnace, world.BlockTypeSnowLayer,
+				world.BlockTypeRail, world.BlockTypePlanksOak, world.BlockTypeOakLog,
+				world.BlockTypeSand,
+			}
+			for i, bt := range row {
+				w.Set(3+i*2, y+1, 8, bt)
+			}
+		},
+	},
+}
+
+func TestGoldenScenes(t *testing.T) {
+	if glWindow == nil {
+		t.Skip("no OpenGL 4.1 context available")
+	}
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	glWindow.MakeContextCurrent()
+	defer glfw.DetachCurrentContext()
+
+	for _, sc := range scenes {
+		// Rendered here, on the thread holding the context
+		got := renderScene(t, sc)
+		t.Run(sc.name, func(t *testing.T) {
+			path := filepath.Join(goldenDir, sc.name+".png")
+			if *update {
+				if err := SavePNG(path, got); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := LoadPNG(path)
+			if os.IsNotExist(err) {
+				t.Fatalf("no golden image %s; run with -update to create it", path)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			diff, ok, err := Compare(got, want, defaultTolerance)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				gotPath := filepath.Join(failureOutputDir, sc.name+".got.png")
+				diffPath := filepath.Join(failureOutputDir, sc.name+".diff.png")
+				SavePNG(gotPath, got)
+				SavePNG(diffPath, DiffImage(got, want, defaultTolerance.Channel))
+				t.Fatalf("%.2f%% of pixels differ (max channel delta %d); see %s and %s",
+					diff.BadFraction()*100, diff.MaxDelta, gotPath, diffPath)
+			}
+		})
+	}
+}
+
+// renderScene builds the scene's world, renders until every chunk in range
+// is meshed and returns the final frame.
+func renderScene(t *testing.T, sc scene) *image.RGBA {
+	t.Helper()
+
+	prevDistance := config.GetRenderDistance()
+	prevBobbing := config.GetViewBobbing()
+	prevShadows := config.GetShadowQuality()
+	prevWaving := config.GetFoliageWaving()
+	config.SetRenderDistance(sceneRadius)
+	config.SetViewBobbing(false)
+	config.SetShadowQuality(config.ShadowsOff)
+	config.SetFoliageWaving(false) // sways with the wall clock
+	defer config.SetRenderDistance(prevDistance)
+	defer config.SetViewBobbing(prevBobbing)
+	defer config.SetShadowQuality(prevShadows)
+	defer config.SetFoliageWaving(prevWaving)
+
+	target, err := NewTarget(sceneWidth, sceneHeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer target.Dispose()
+	target.Bind()
+
+	r, err := renderer.NewRenderer(blocks.NewBlocks())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Dispose()
+	r.UpdateViewport(sceneWidth, sceneHeight)
+
+	w := world.NewWithSeed(sceneSeed)
+	defer w.Close()
+	blocks.InitMeshSystem(max(runtime.NumCPU()-1, 1))
+	defer blocks.ShutdownMeshSystem()
+	w.StreamChunksAroundSync(sc.x, sc.z, sceneRadius)
+
+	surface := groundHeight(w, int(sc.x), int(sc.z))
+	if sc.build != nil {
+		sc.build(w, surface)
+	}
+
+	cam := player.New(w, player.GameModeCreative)
+	// Teleport, so the interpolated render position starts there too
+	cam.Teleport(mgl32.Vec3{sc.x, float32(surface) + sc.y, sc.z})
+	cam.CamYaw, cam.CamPitch = sc.yaw, sc.pitch
+
+	camChunk := world.ChunkCoord{
+		X: int(sc.x) / world.ChunkSizeX,
+		Z: int(sc.z) / world.ChunkSizeZ,
+	}
+	for frame := 0; ; frame++ {
+		r.Render(w, cam, 1.0/60)
+		blocks.ProcessMeshResults()
+		if frame > 0 && meshesSettled(w, camChunk) {
+			break
+		}
+		if frame >= maxSettleFrames {
+			t.Fatalf("chunk meshes not built after %d frames", maxSettleFrames)
+		}
+	}
+	r.Render(w, cam, 1.0/60)
+	return target.ReadPixels()
+}
+
+// groundHeight returns the y of the highest block in column (x, z). The
+// generator's SurfaceHeightAt is only an estimate, which can leave the
+// camera far above the real ground.
+func groundHeight(w *world.World, x, z int) int {
+	for y := world.ChunkSizeY - 1; y > 0; y-- {
+		if w.Get(x, y, z) != world.BlockTypeAir {
+			return y
+		}
+	}
+	return 0
+}
+
+// meshesSettled reports whether every chunk the renderer draws around the
+// camera chunk is meshed. Streaming loads a square, but only the circle of
+// render distance is meshed, so the corners are left out.
+func meshesSettled(w *world.World, camChunk world.ChunkCoord) bool {
+	if blocks.PendingMeshJobs() > 0 {
+		return false
+	}
+	for _, cc := range w.GetAllChunks() {
+		dx, dz := cc.Coord.X-camChunk.X, cc.Coord.Z-camChunk.Z
+		if dx*dx+dz*dz > sceneRadius*sceneRadius {
+			continue
+		}
+		if cc.Chunk.IsDirty() {
+			return false
+		}
+	}
+	return true
+}
@@ -1,8 +1,10 @@
 package blocks
 
 import (
-	"log"
+	"log/slog"
+	"mini-mc/internal/meshing"
 	"mini-mc/internal/world"
+	"slices"
 	"sort"
 	"unsafe"
 
@@ -11,21 +13,26 @@ import (
 
 const (
 	initialRegionBytes = 512 * 1024         // 512 KB per region initial allocation
-	maxRegionBytes     = 128 * 1024 * 1024  // 64 MB per region max
+	maxRegionBytes     = 128 * 1024 * 1024  // 128 MB per region max
 	globalMaxBytes     = 1024 * 1024 * 1024 // total GPU budget across all regions
 )
 
 // Atlas VBO/VAO management
 var (
-	atlasRegions        map[[2]int]*atlasRegion
+	atlasRegions        map[atlasKey]*atlasRegion
 	firstsScratch       []int32
 	countsScratch       []int32
 	currentFrame        uint64
 	totalAllocatedBytes int
+	quadEBO             uint32 // indices of quadIndexQuads quads, shared by every atlas VAO
 )
 
 // ---------- Helper functions ----------
 func CleanupAtlas() {
+	if quadEBO != 0 {
+		gl.DeleteBuffers(1, &quadEBO)
+		quadEBO = 0
+	}
 	if atlasRegions != nil {
 		for _, r := range atlasRegions {
 			if r.vbo != 0 {
@@ -41,8 +48,10 @@ func CleanupAtlas() {
 	currentFrame = 0
 }
 
-func regionKeyForXZ(x, z int) [2]int {
-	return [2]int{x >> 4, z >> 4}
+// regionKeyFor returns the key of the region holding column (x, z) drawn at
+// LOD step.
+func regionKeyFor(x, z, step int) atlasKey {
+	return atlasKey{x >> 4, z >> 4, step}
 }
 
 func copyAtlasBuffer(oldVBO, newVBO uint32, bytes int) {
@@ -77,6 +86,15 @@ func copyAtlasBuffer(oldVBO, newVBO uint32, bytes int) {
 func setupRegionVAO(region *atlasRegion) {
 	gl.BindVertexArray(region.vao)
 	gl.BindBuffer(gl.ARRAY_BUFFER, region.vbo)
+	setupAtlasVertexLayout()
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
+
+// setupAtlasVertexLayout points the bound VAO at the bound buffer's vertices
+// in the atlas format written by appendAtlasVerts, and at the quad indices.
+func setupAtlasVertexLayout() {
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, quadEBO)
 
 	stride := int32(6 * 2)
 
@@ -85,14 +103,11 @@ func setupRegionVAO(region *atlasRegion) {
 
 	gl.EnableVertexAttribArray(1)
 	gl.VertexAttribPointer(1, 3, gl.UNSIGNED_SHORT, false, stride, gl.PtrOffset(3*2))
-
-	gl.BindVertexArray(0)
-	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
 }
 
-func getOrCreateRegion(key [2]int) *atlasRegion {
+func getOrCreateRegion(key atlasKey) *atlasRegion {
 	if atlasRegions == nil {
-		atlasRegions = make(map[[2]int]*atlasRegion)
+		atlasRegions = make(map[atlasKey]*atlasRegion)
 	}
 	if r := atlasRegions[key]; r != nil {
 		return r
@@ -101,7 +116,7 @@ func getOrCreateRegion(key [2]int) *atlasRegion {
 		before := totalAllocatedBytes
 		evictColdRegionsGlobal(initialRegionBytes + initialRegionBytes/2)
 		if totalAllocatedBytes >= before {
-			log.Printf("CRITICAL: no evictable memory, atlas full")
+			slog.Error("atlas full and nothing left to evict")
 			return nil
 		}
 	}
@@ -122,7 +137,7 @@ func ensureRegionCapacity(r *atlasRegion, requiredBytes int) bool {
 		return true
 	}
 	if requiredBytes > maxRegionBytes {
-		log.Printf("atlas region %v out of capacity (need %d, max %d)", r.key, requiredBytes, maxRegionBytes)
+		slog.Warn("atlas region out of capacity", "region", r.key, "need", requiredBytes, "max", maxRegionBytes)
 		return false
 	}
 
@@ -131,7 +146,7 @@ func ensureRegionCapacity(r *atlasRegion, requiredBytes int) bool {
 		needed := (totalAllocatedBytes - r.capacityBytes + newCap) - globalMaxBytes
 		evictColdRegionsGlobal(needed)
 		if totalAllocatedBytes-r.capacityBytes+newCap > globalMaxBytes {
-			log.Printf("atlas region %v growth blocked: global budget would exceed %d", r.key, globalMaxBytes)
+			slog.Warn("atlas region growth blocked by global budget", "region", r.key, "budget", globalMaxBytes)
 			return false
 		}
 	}
@@ -153,7 +168,7 @@ func ensureRegionCapacity(r *atlasRegion, requiredBytes int) bool {
 	setupRegionVAO(r)
 
 	r.growthCount++
-	log.Printf("atlas region %v grew to %d bytes", r.key, r.capacityBytes)
+	slog.Debug("atlas region grew", "region", r.key, "bytes", r.capacityBytes)
 	return true
 }
 
@@ -167,6 +182,7 @@ func regionFragmentedBytes(r *atlasRegion) int {
 }
 
 func allocInRegion(r *atlasRegion, sizeShorts int) (int, bool) {
+	r.layoutVersion++
 	// Best-fit search
 	bestIdx := -1
 	for i, span := range r.freeList {
@@ -200,6 +216,7 @@ func freeInRegion(r *atlasRegion, offsetShorts, sizeShorts int) {
 	if sizeShorts <= 0 {
 		return
 	}
+	r.layoutVersion++
 	span := freeSpan{offsetShorts, sizeShorts}
 	i := sort.Search(len(r.freeList), func(j int) bool {
 		return r.freeList[j].offsetShorts >= offsetShorts
@@ -301,40 +318,58 @@ func flushAllRegionWrites() {
 }
 
 // ---------- Vertex data collection ----------
-func collectColumnVerts(x, z int) []int16 {
+func collectColumnVerts(col *columnMesh) []int16 {
 	var buf []int16
+	borders := lodSkirtBorders(col)
 	for y := range world.NumSections {
-		coord := world.ChunkCoord{X: x, Y: y, Z: z}
-		if cm := chunkMeshes[coord]; cm != nil && cm.vertexCount > 0 && len(cm.cpuVerts) > 0 {
-			baseX := x * world.ChunkSizeX
-			baseY := y * world.ChunkSizeY
-			baseZ := z * world.ChunkSizeZ
-
-			count := len(cm.cpuVerts) / 2
-			for i := range count {
-				v1 := cm.cpuVerts[i*2]
-				v2 := cm.cpuVerts[i*2+1]
-
-				lx := int(v1 & 0x1F)
-				ly := int((v1 >> 5) & 0x1FF)
-				lz := int((v1 >> 14) & 0x1F)
-				norm := int((v1 >> 19) & 0x7)
-				brightness := int((v1 >> 22) & 0xFF)
-
-				texID := int(v2 & 0xFFFF)
-				tint := int((v2 >> 16) & 0xFFFF)
-
-				wx := int16(baseX + lx)
-				wy := int16(baseY + ly)
-				wz := int16(baseZ + lz)
-
-				info := int16(norm | (brightness << 8))
-				texInfo := int16(texID)
-				extra := int16(tint)
-
-				buf = append(buf, wx, wy, wz, info, texInfo, extra)
-			}
+		coord := world.ChunkCoord{X: col.x, Y: y, Z: col.z}
+		cm := chunkMeshes[coord]
+		if cm == nil {
+			continue
 		}
+		if lod := cm.lodMesh(col.lodStep); lod != nil {
+			buf = appendLODVerts(buf, coord, lod, borders)
+		} else if cm.vertexCount > 0 && len(cm.cpuVerts) > 0 {
+			buf = appendAtlasVerts(buf, coord, cm.cpuVerts)
+		}
+	}
+	return buf
+}
+
+// appendAtlasVerts unpacks the chunk-local packed vertices of the chunk at
+// coord into buf as world-space atlas vertices, six shorts each.
+func appendAtlasVerts(buf []int16, coord world.ChunkCoord, verts []uint32) []int16 {
+	baseX := coord.X * world.ChunkSizeX
+	baseY := coord.Y * world.ChunkSizeY
+	baseZ := coord.Z * world.ChunkSizeZ
+
+	count := len(verts) / 2
+	for i := range count {
+		v1 := verts[i*2]
+		v2 := verts[i*2+1]
+
+		lx := int(v1 & 0x1F)
+		ly := int((v1 >> 5) & 0x1FF)
+		lz := int((v1 >> 14) & 0x1F)
+		norm := int((v1 >> 19) & 0x7)
+		light := int((v1 >> 22) & 0xFF)
+		varied := int((v1 >> 30) & 1)
+		foliage := int((v1 >> 31) & 1)
+
+		texID := int(v2 & 0x3FF)
+		occlusion := int((v2 >> 10) & 0x3)
+		drop := int((v2 >> 12) & 0xF)
+		tint := int((v2 >> 16) & 0xFFFF)
+
+		wx := int16(baseX + lx)
+		wy := int16(baseY + ly)
+		wz := int16(baseZ + lz)
+
+		info := int16(norm | (drop << 3) | (varied << 7) | (light << 8))
+		texInfo := int16(texID | (foliage << 12) | (occlusion << 13))
+		extra := int16(tint)
+
+		buf = append(buf, wx, wy, wz, info, texInfo, extra)
 	}
 	return buf
 }
@@ -369,7 +404,7 @@ func compactRegion(r *atlasRegion) {
 		}
 		totalAllocatedBytes -= r.capacityBytes
 		delete(atlasRegions, r.key)
-		log.Printf("atlas region %v deleted (empty)", r.key)
+		slog.Debug("atlas region deleted (empty)", "region", r.key)
 		return
 	}
 
@@ -436,6 +471,7 @@ func compactRegion(r *atlasRegion) {
 
 	r.totalFloats = currentOffsetShorts
 	r.freeList = r.freeList[:0]
+	r.layoutVersion++
 
 	gl.BindBuffer(gl.COPY_READ_BUFFER, 0)
 	gl.BindBuffer(gl.COPY_WRITE_BUFFER, 0)
@@ -450,11 +486,19 @@ func compactRegion(r *atlasRegion) {
 	r.capacityBytes = newCap
 
 	setupRegionVAO(r)
+	for _, c := range r.orderedColumns {
+		if c != nil {
+			c.listed = false
+		}
+	}
+	for _, c := range activeCols {
+		c.listed = true
+	}
 	r.orderedColumns = activeCols
 	r.activeColumns = len(activeCols)
 	r.lastCompact = currentFrame
 
-	log.Printf("atlas region %v compacted: %d bytes used, %d columns", r.key, r.totalFloats*2, len(activeCols))
+	slog.Debug("atlas region compacted", "region", r.key, "bytes", r.totalFloats*2, "columns", len(activeCols))
 }
 
 // ---------- Eviction (with flush) ----------
@@ -502,7 +546,7 @@ func evictLRUColumns(r *atlasRegion, targetFreeBytes int) int {
 	}
 	if freedBytes > 0 {
 		compactRegion(r)
-		log.Printf("atlas region %v: evicted LRU columns, freed ~%d bytes", r.key, freedBytes)
+		slog.Debug("atlas region evicted LRU columns", "region", r.key, "freed", freedBytes)
 	}
 	return freedBytes
 }
@@ -532,7 +576,7 @@ func evictColdRegionsGlobal(neededBytes int) int {
 		return candidates[i].col.drawnFrame < candidates[j].col.drawnFrame
 	})
 
-	dirtyRegions := map[[2]int]*atlasRegion{}
+	dirtyRegions := map[atlasKey]*atlasRegion{}
 	logicalFreed := 0
 	for _, cand := range candidates {
 		// Stop evicting once logical freed bytes exceed target; compaction will
@@ -556,7 +600,7 @@ func evictColdRegionsGlobal(neededBytes int) int {
 	}
 	gpuFreed := before - totalAllocatedBytes
 	if gpuFreed > 0 {
-		log.Printf("atlas global eviction: freed ~%d GPU bytes across %d regions", gpuFreed, len(dirtyRegions))
+		slog.Info("atlas global eviction", "freed", gpuFreed, "regions", len(dirtyRegions))
 	}
 	return gpuFreed
 }
@@ -586,6 +630,12 @@ func maybeCompactRegions() {
 			continue
 		}
 
+		// Columns that left the atlas stay listed until compaction, which
+		// the free list may put off for as long as their holes are reused
+		if len(r.orderedColumns) > 2*r.activeColumns+staleColumnSlack {
+			pruneOrderedColumns(r)
+		}
+
 		// Compact if high hole-fragmentation OR if the VBO has too much trailing waste.
 		fragBytes := regionFragmentedBytes(r)
 		usedBytes := r.totalFloats * 2
@@ -600,12 +650,55 @@ func maybeCompactRegions() {
 	}
 }
 
+// staleColumnSlack is how many listed columns without atlas data a region
+// tolerates beyond its active count before they are pruned
+const staleColumnSlack = 64
+
+// pruneOrderedColumns drops the columns that no longer hold atlas data from
+// r.orderedColumns, keeping the order of the rest.
+func pruneOrderedColumns(r *atlasRegion) {
+	kept := r.orderedColumns[:0]
+	for _, c := range r.orderedColumns {
+		if c == nil {
+			continue
+		}
+		if c.firstFloat < 0 || c.vertexCount <= 0 || columnMeshes[[2]int{c.x, c.z}] != c {
+			c.listed = false
+			continue
+		}
+		kept = append(kept, c)
+	}
+	clear(r.orderedColumns[len(kept):])
+	r.orderedColumns = kept
+}
+
+// releaseColumnSlot frees col's slot and takes it off its region's list right
+// away, so the region no longer draws or compacts it.
+func releaseColumnSlot(col *columnMesh) {
+	if r := atlasRegions[col.regionKey]; r != nil {
+		flushRegionWrites(r)
+		if col.vertexCount > 0 {
+			freeInRegion(r, col.firstFloat, int(col.vertexCount)*6)
+			r.activeColumns--
+		}
+		if i := slices.Index(r.orderedColumns, col); i >= 0 {
+			r.orderedColumns = slices.Delete(r.orderedColumns, i, i+1)
+			r.layoutVersion++
+		}
+	}
+	col.listed = false
+	col.vertexCount = 0
+	col.firstFloat = -1
+	col.firstVertex = -1
+}
+
 // ---------- Column mesh update (main entry point) ----------
 func ensureColumnMeshForXZ(x, z int) *columnMesh {
 	key := [2]int{x, z}
 	col := columnMeshes[key]
 	if col == nil {
 		col = &columnMesh{x: x, z: z, firstFloat: -1, firstVertex: -1, dirty: true}
+		col.lodStep = columnLODStep(x, z, 1)
 		columnMeshes[key] = col
 	}
 	if !col.dirty {
@@ -616,23 +709,22 @@ func ensureColumnMeshForXZ(x, z int) *columnMesh {
 		return col
 	}
 	col.retryFrame = 0
-
-	rkey := regionKeyForXZ(x, z)
-	r := getOrCreateRegion(rkey)
-	if r == nil {
+	// Columns evicted over the mesh memory cap stay out until the limit widens
+	if beyondMeshLimit(x, z) {
 		return col
 	}
-	col.regionKey = rkey
 
-	// Flush any pending writes for this region before modifying layout
-	flushRegionWrites(r)
-
-	buf := collectColumnVerts(x, z)
+	buf := collectColumnVerts(col)
 
+	// Empty columns (air, or solid ground with every section enclosed) never
+	// touch the atlas: only release the slot they previously held, if any.
 	if len(buf) == 0 {
 		if col.firstFloat >= 0 && col.vertexCount > 0 {
-			freeInRegion(r, col.firstFloat, int(col.vertexCount)*6)
-			r.activeColumns--
+			if r := atlasRegions[col.regionKey]; r != nil {
+				flushRegionWrites(r)
+				freeInRegion(r, col.firstFloat, int(col.vertexCount)*6)
+				r.activeColumns--
+			}
 		}
 		col.vertexCount = 0
 		col.firstFloat = -1
@@ -641,10 +733,29 @@ func ensureColumnMeshForXZ(x, z int) *columnMesh {
 		return col
 	}
 
+	rkey := regionKeyFor(x, z, col.lodStep)
+	if col.firstFloat >= 0 && col.regionKey != rkey {
+		// Switched LOD step: the new mesh goes to the step's own region
+		releaseColumnSlot(col)
+	}
+	r := getOrCreateRegion(rkey)
+	if r == nil {
+		return col
+	}
+	col.regionKey = rkey
+
+	// Flush any pending writes for this region before modifying layout
+	flushRegionWrites(r)
+
 	vertexCount := int32(len(buf) / 6)
+	minY, maxY := meshYBounds(buf)
 
 	// Same size: overwrite in-place
 	if vertexCount == col.vertexCount && col.firstFloat >= 0 {
+		if minY != col.minY || maxY != col.maxY {
+			col.minY, col.maxY = minY, maxY
+			r.layoutVersion++ // the packed bounds are stale
+		}
 		queueRegionWrite(r, col.firstFloat*2, buf)
 		col.dirty = false
 		col.firstVertex = int32(col.firstFloat / 6)
@@ -684,13 +795,19 @@ func ensureColumnMeshForXZ(x, z int) *columnMesh {
 	col.vertexCount = vertexCount
 	col.firstFloat = offsetShorts
 	col.firstVertex = int32(offsetShorts / 6)
+	col.minY, col.maxY = minY, maxY
 	col.dirty = false
 
 	if isNewColumn {
-		if r.orderedColumns == nil {
-			r.orderedColumns = make([]*columnMesh, 0, 8)
+		// A column emptied or evicted since the last compaction is still
+		// listed; appending it again would draw it twice
+		if !col.listed {
+			if r.orderedColumns == nil {
+				r.orderedColumns = make([]*columnMesh, 0, 8)
+			}
+			r.orderedColumns = append(r.orderedColumns, col)
+			col.listed = true
 		}
-		r.orderedColumns = append(r.orderedColumns, col)
 		col.drawnFrame = currentFrame
 		r.activeColumns++
 	}
@@ -699,6 +816,13 @@ func ensureColumnMeshForXZ(x, z int) *columnMesh {
 
 func setupAtlas() {
 	if atlasRegions == nil {
-		atlasRegions = make(map[[2]int]*atlasRegion)
+		atlasRegions = make(map[atlasKey]*atlasRegion)
+	}
+	if quadEBO == 0 {
+		indices := meshing.AppendQuadIndices(make([]uint32, 0, quadIndexQuads*meshing.QuadIndices), quadIndexQuads)
+		gl.GenBuffers(1, &quadEBO)
+		gl.BindBuffer(gl.ARRAY_BUFFER, quadEBO)
+		gl.BufferData(gl.ARRAY_BUFFER, len(indices)*4, gl.Ptr(indices), gl.STATIC_DRAW)
+		gl.BindBuffer(gl.ARRAY_BUFFER, 0)
 	}
 }
@@ -0,0 +1,49 @@
+package blocks
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPruneOrderedColumnsDropsColumnsOutOfAtlas(t *testing.T) {
+	columnMeshes = make(map[[2]int]*columnMesh)
+	r := &atlasRegion{}
+	for x := range 6 {
+		col := &columnMesh{x: x, vertexCount: 10, firstFloat: x * 60, listed: true}
+		columnMeshes[[2]int{x, 0}] = col
+		r.orderedColumns = append(r.orderedColumns, col)
+	}
+	emptied := columnMeshes[[2]int{1, 0}]
+	emptied.vertexCount, emptied.firstFloat = 0, -1
+	evicted := columnMeshes[[2]int{3, 0}]
+	evicted.firstFloat = -1
+	// Unloaded and loaded again: the listed column is not the live one
+	unloaded := columnMeshes[[2]int{4, 0}]
+	columnMeshes[[2]int{4, 0}] = &columnMesh{x: 4, firstFloat: -1}
+
+	pruneOrderedColumns(r)
+
+	var got []int
+	for _, c := range r.orderedColumns {
+		got = append(got, c.x)
+	}
+	if want := []int{0, 2, 5}; !slices.Equal(got, want) {
+		t.Fatalf("kept columns %v, want %v", got, want)
+	}
+	for _, c := range []*columnMesh{emptied, evicted, unloaded} {
+		if c.listed {
+			t.Errorf("pruned column %d still marked listed", c.x)
+		}
+	}
+}
+
+func TestQuadDrawsSplitLongRanges(t *testing.T) {
+	const maxVerts = quadIndexQuads * 4
+	counts, bases := appendQuadDraws(nil, nil, 100, maxVerts+8)
+	if !slices.Equal(bases, []int32{100, 100 + maxVerts}) {
+		t.Fatalf("base vertices %v", bases)
+	}
+	if !slices.Equal(counts, []int32{quadIndexQuads * 6, 2 * 6}) {
+		t.Fatalf("index counts %v", counts)
+	}
+}
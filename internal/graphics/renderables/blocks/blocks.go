@@ -1,16 +1,19 @@
 package blocks
 
 import (
-	"log"
+	"log/slog"
 	"math"
 	"mini-mc/internal/config"
 	"mini-mc/internal/graphics"
 	"mini-mc/internal/graphics/renderer"
+	"mini-mc/internal/mathutil"
+	"mini-mc/internal/meshing"
 	"mini-mc/internal/player"
 	"mini-mc/internal/profiling"
 	"mini-mc/internal/registry"
 	"mini-mc/internal/world"
 	"time"
+	"unsafe"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
@@ -32,26 +35,40 @@ type Blocks struct {
 	cachedNearby   []world.ChunkWithCoord
 
 	// Fluid Rendering
-	fluidShader    *graphics.Shader
-	fluidVAO       uint32
-	fluidVBO       uint32
-	fluidVerts     []float32 // Scratch buffer for fluid verts
-	fluidVertsCap  int
-	fluidStartTime time.Time
+	fluidShader   *graphics.Shader
+	fluidVAO      uint32
+	fluidVBO      uint32
+	fluidVerts    []float32 // Scratch buffer for fluid verts
+	fluidVertsCap int
+	fluidBatches  []translucentBatch // this frame's fluid chunks, drawn in the translucent stage
+	water         planarWater        // offscreen targets of fancy water
+	occlusion     occlusionCuller    // hardware occlusion queries of the columns
+	shadows       shadowMaps         // the sun's shadow cascades
+
+	// Translucent blocks (glass), in the atlas vertex format
+	translucentVAO      uint32
+	translucentVBO      uint32
+	translucentVerts    []int16 // Scratch buffer for translucent block verts
+	translucentVertsCap int
+	translucentBatches  []translucentBatch // this frame's translucent block chunks
+
+	startTime time.Time // clock for the fluid and foliage animations
 }
 
 func NewBlocks() *Blocks {
 	return &Blocks{
-		visibleScratch: make([]world.ChunkWithCoord, 0, 1024),
-		ensureEvery:    200 * time.Millisecond,
-		lastChunkX:     1<<31 - 1, // sentinel so first run triggers
-		lastChunkZ:     1<<31 - 1,
-		cachedPCX:      1<<31 - 1,
-		cachedPCZ:      1<<31 - 1,
-		cachedRadius:   -1,
-		cachedNearby:   make([]world.ChunkWithCoord, 0, 1024),
-		fluidVerts:     make([]float32, 0, 65536),
-		fluidVertsCap:  65536,
+		visibleScratch:      make([]world.ChunkWithCoord, 0, 1024),
+		ensureEvery:         200 * time.Millisecond,
+		lastChunkX:          1<<31 - 1, // sentinel so first run triggers
+		lastChunkZ:          1<<31 - 1,
+		cachedPCX:           1<<31 - 1,
+		cachedPCZ:           1<<31 - 1,
+		cachedRadius:        -1,
+		cachedNearby:        make([]world.ChunkWithCoord, 0, 1024),
+		fluidVerts:          make([]float32, 0, 65536),
+		fluidVertsCap:       65536,
+		translucentVerts:    make([]int16, 0, 16384),
+		translucentVertsCap: 16384,
 	}
 }
 
@@ -71,6 +88,8 @@ func (b *Blocks) Init() error {
 
 	chunkMeshes = make(map[world.ChunkCoord]*chunkMesh)
 	columnMeshes = make(map[[2]int]*columnMesh)
+	enclosedSectionCount, emptyChunkMeshCount = 0, 0
+	cpuMeshBytes, meshLimitSq = 0, 0
 
 	if err := InitTextureAtlas(); err != nil {
 		return err
@@ -78,6 +97,13 @@ func (b *Blocks) Init() error {
 
 	setupAtlas()
 
+	if err := b.occlusion.init(); err != nil {
+		return err
+	}
+	if err := b.shadows.init(); err != nil {
+		return err
+	}
+
 	// Init Fluid buffers
 	gl.GenVertexArrays(1, &b.fluidVAO)
 	gl.GenBuffers(1, &b.fluidVBO)
@@ -86,8 +112,8 @@ func (b *Blocks) Init() error {
 	// Pre-allocate some space?
 	gl.BufferData(gl.ARRAY_BUFFER, b.fluidVertsCap*4, nil, gl.DYNAMIC_DRAW)
 
-	// Layout: Pos(3), UV(2), TexID(1), Tint(3), AnimType(1) = 10 floats
-	stride := int32(10 * 4)
+	// Layout: Pos(3), UV(2), TexID(1), Tint(3), AnimType(1), Light(1)
+	stride := int32(meshing.FluidVertexFloats * 4)
 	gl.EnableVertexAttribArray(0)
 	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, stride, gl.PtrOffset(0))
 	gl.EnableVertexAttribArray(1)
@@ -98,10 +124,22 @@ func (b *Blocks) Init() error {
 	gl.VertexAttribPointer(3, 3, gl.FLOAT, false, stride, gl.PtrOffset(6*4))
 	gl.EnableVertexAttribArray(4)
 	gl.VertexAttribPointer(4, 1, gl.FLOAT, false, stride, gl.PtrOffset(9*4))
+	gl.EnableVertexAttribArray(5)
+	gl.VertexAttribPointer(5, 1, gl.FLOAT, false, stride, gl.PtrOffset(10*4))
 
 	gl.BindVertexArray(0)
 
-	b.fluidStartTime = time.Now()
+	// Init translucent block buffers
+	gl.GenVertexArrays(1, &b.translucentVAO)
+	gl.GenBuffers(1, &b.translucentVBO)
+	gl.BindVertexArray(b.translucentVAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.translucentVBO)
+	gl.BufferData(gl.ARRAY_BUFFER, b.translucentVertsCap*2, nil, gl.DYNAMIC_DRAW)
+	setupAtlasVertexLayout()
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+
+	b.startTime = time.Now()
 
 	return nil
 }
@@ -144,6 +182,15 @@ func (b *Blocks) Dispose() {
 	if b.fluidVBO != 0 {
 		gl.DeleteBuffers(1, &b.fluidVBO)
 	}
+	if b.translucentVAO != 0 {
+		gl.DeleteVertexArrays(1, &b.translucentVAO)
+	}
+	if b.translucentVBO != 0 {
+		gl.DeleteBuffers(1, &b.translucentVBO)
+	}
+	b.water.dispose()
+	b.occlusion.dispose()
+	b.shadows.dispose()
 
 	for _, m := range chunkMeshes {
 		if m != nil {
@@ -157,11 +204,12 @@ func (b *Blocks) SetViewport(width, height int) {
 }
 
 func (b *Blocks) renderBlocksInternal(ctx renderer.RenderContext) {
-	eyeY := ctx.Player.Position[1] + player.PlayerEyeHeight
+	camera := ctx.Player.RenderPosition()
+	eyeY := camera[1] + player.PlayerEyeHeight
 	eyeBlock := ctx.World.Get(
-		int(ctx.Player.Position[0]),
+		int(camera[0]),
 		int(eyeY),
-		int(ctx.Player.Position[2]),
+		int(camera[2]),
 	)
 	isUnderwater := 0
 	if eyeBlock == world.BlockTypeWater {
@@ -186,18 +234,40 @@ func (b *Blocks) renderBlocksInternal(ctx renderer.RenderContext) {
 
 		b.mainShader.SetMatrix4("proj", &ctx.Proj[0])
 		b.mainShader.SetMatrix4("view", &ctx.View[0])
-		b.mainShader.SetVector3("cameraPos", ctx.Player.Position[0], ctx.Player.Position[1], ctx.Player.Position[2])
+		b.mainShader.SetVector3("cameraPos", camera[0], camera[1], camera[2])
 		b.mainShader.SetInt("isUnderwater", int32(isUnderwater))
+		textureVariation := int32(0)
+		if config.GetTextureVariation() {
+			textureVariation = 1
+		}
+		b.mainShader.SetInt("textureVariation", textureVariation)
+		foliageWaving := int32(0)
+		if config.IsFoliageWavingActive() {
+			foliageWaving = 1
+		}
+		b.mainShader.SetInt("foliageWaving", foliageWaving)
+		ambientOcclusion := int32(0)
+		if config.GetAmbientOcclusion() {
+			ambientOcclusion = 1
+		}
+		b.mainShader.SetInt("ambientOcclusion", ambientOcclusion)
+		b.mainShader.SetFloat("time", float32(time.Since(b.startTime).Seconds()))
+
+		sun := ctx.World.SunDirection()
+		b.mainShader.SetVector3("lightDir", sun.X(), sun.Y(), sun.Z())
+		b.mainShader.SetFloat("skyDarken", ctx.World.SkyDarkening())
+		b.mainShader.SetVector4("clipPlane", 0, 0, 0, 1)
+		b.mainShader.SetVector4("lodTint", 0, 0, 0, 0)
 
-		light := mgl32.Vec3{0.3, 1.0, 0.3}.Normalize()
-		b.mainShader.SetVector3("lightDir", light.X(), light.Y(), light.Z())
+		tintRemap := currentTintRemap()
+		b.mainShader.SetMatrix3("tintRemap", &tintRemap[0])
 	}()
 
 	// Draw greedy-meshed chunks that intersect the camera frustum
-	planes := func() [6]plane {
+	planes := func() mathutil.Frustum {
 		defer profiling.Track("renderer.renderBlocks.frustumSetup")()
 		clip := ctx.Proj.Mul4(ctx.View)
-		return extractFrustumPlanes(clip)
+		return mathutil.FrustumFromMatrix(clip)
 	}()
 
 	// Hard cap for render radius to shrink candidate set pre-cull/sort
@@ -223,6 +293,8 @@ func (b *Blocks) renderBlocksInternal(ctx renderer.RenderContext) {
 	}
 	nearbyChunks = b.cachedNearby
 
+	updateColumnLODs(pcx, pcz, config.GetLODDistance())
+
 	shouldEnsure := false
 	// If any nearby chunk is dirty, rebuild immediately (reflect edits without delay)
 	hasDirty := false
@@ -248,8 +320,14 @@ func (b *Blocks) renderBlocksInternal(ctx renderer.RenderContext) {
 			if ch == nil {
 				continue
 			}
+			if !withinVerticalRadius(coord, ch, eyeY, maxRenderRadiusChunks) {
+				continue
+			}
+			if beyondMeshLimit(coord.X, coord.Z) {
+				continue
+			}
 			existing := chunkMeshes[coord]
-			needsBuild := existing == nil || ch.IsDirty()
+			needsBuild := existing == nil || ch.IsDirty() || missingLODMeshes(existing, coord)
 			if needsBuild {
 				_ = ensureChunkMesh(ctx.World, coord, ch)
 			}
@@ -257,183 +335,429 @@ func (b *Blocks) renderBlocksInternal(ctx renderer.RenderContext) {
 		b.lastEnsure = time.Now()
 		stop()
 	}
+	enforceMeshMemoryCap(pcx, pcz)
+
+	// Rebuild chunks relit since their last mesh, most urgent first
+	func() {
+		defer profiling.Track("renderer.renderBlocks.lightRemesh")()
+		eye := mgl32.Vec3{camera[0], eyeY, camera[2]}
+		submitLightRemeshes(ctx.World, eye, planes)
+	}()
 
 	// Collect visible chunks with frustum culling (for rendering only)
 	var visible []world.ChunkWithCoord
 	{
 		stop := profiling.Track("renderer.renderBlocks.collectVisible")
-		visible = make([]world.ChunkWithCoord, 0, len(nearbyChunks))
-
-		// Pre-calculate common values to avoid repeated calculations
-		chunkSizeXf := float32(world.ChunkSizeX)
-		chunkSizeYf := float32(world.ChunkSizeY)
-		chunkSizeZf := float32(world.ChunkSizeZ)
-		margin := frustumMargin
-
-		for _, cc := range nearbyChunks {
-			// Calculate chunk bounds with pre-computed constants
-			cx := float32(cc.Coord.X) * chunkSizeXf
-			cy := float32(cc.Coord.Y) * chunkSizeYf
-			cz := float32(cc.Coord.Z) * chunkSizeZf
-
-			// Apply margin directly to avoid intermediate variables
-			minx := cx - margin
-			miny := cy - margin
-			minz := cz - margin
-			maxx := cx + chunkSizeXf + margin
-			maxy := cy + chunkSizeYf + margin
-			maxz := cz + chunkSizeZf + margin
-
-			if aabbIntersectsFrustumPlanesF(minx, miny, minz, maxx, maxy, maxz, planes) {
-				visible = append(visible, cc)
-			}
-		}
+		visible = appendFrustumVisible(make([]world.ChunkWithCoord, 0, len(nearbyChunks)), nearbyChunks, planes, eyeY, maxRenderRadiusChunks)
 		stop()
 	}
 
-	gl.Disable(gl.CULL_FACE)
+	// Shadows are drawn from the light before the blocks that sample them
 	func() {
-		defer profiling.Track("renderer.renderBlocks.drawAtlas")()
-		// Aggregate visible chunks into unique XZ columns
-		type xz struct{ x, z int }
-		colSet := make(map[xz]struct{}, len(visible))
-		for _, vc := range visible {
-			colSet[xz{vc.Coord.X, vc.Coord.Z}] = struct{}{}
-		}
-		// Increment frame and mark visible columns for this frame to avoid per-frame maps
-		forMarked := false
-		for k := range colSet {
-			col := ensureColumnMeshForXZ(k.x, k.z)
-			if !forMarked {
-				currentFrame++
-				forMarked = true
-			}
-			if col != nil {
-				col.visibleFrame = currentFrame
-			}
-		}
-		flushAllRegionWrites()
-		maybeCompactRegions()
+		defer profiling.Track("renderer.renderBlocks.shadowSetup")()
+		// Centred where main.frag measures cascade distances from
+		b.shadows.render(ctx, camera)
+		b.mainShader.Use()
+		b.shadows.setUniforms(b.mainShader)
+	}()
 
-		// Draw ready columns per region using multi-draw
-		for _, r := range atlasRegions {
-			if r == nil || len(r.orderedColumns) == 0 {
-				continue
-			}
-			if cap(firstsScratch) < len(r.orderedColumns) {
-				firstsScratch = make([]int32, len(r.orderedColumns))
-				countsScratch = make([]int32, len(r.orderedColumns))
-			}
-			firsts := firstsScratch[:0]
-			counts := countsScratch[:0]
-			var lastFirst int32
-			var lastCount int32
-			hasRun := false
-			for _, c := range r.orderedColumns {
-				if c == nil {
+	// Occlusion results only hold for the player's view, and boxes drawn as
+	// lines would hide columns that are in sight
+	occlusion := config.GetOcclusionCulling() && !ctx.Offscreen && !config.GetWireframeMode()
+
+	lodTint := config.GetLODDebugTint()
+	gl.Disable(gl.CULL_FACE)
+	if config.GetPackedColumnCulling() {
+		func() {
+			defer profiling.Track("renderer.renderBlocks.drawAtlasPacked")()
+			ensureVisibleColumns(visible)
+			flushAllRegionWrites()
+			maybeCompactRegions()
+			currentFrame++
+			skipOccluded := b.occlusion.trusted(occlusion, currentFrame)
+			for _, r := range atlasRegions {
+				if r == nil || len(r.orderedColumns) == 0 {
 					continue
 				}
-				if c.visibleFrame != currentFrame || c.drawnFrame == currentFrame {
-					continue
+				refreshPackedColumns(r)
+				firstsScratch, countsScratch = cullPackedColumns(&r.packed, planes, pcx, pcz, maxRenderRadiusChunks, currentFrame, skipOccluded, firstsScratch[:0], countsScratch[:0])
+				if lodTint {
+					b.setLODTint(r.key.step)
 				}
-				if c.dirty || c.vertexCount <= 0 || c.firstFloat < 0 {
+				drawRegion(r, firstsScratch, countsScratch)
+			}
+		}()
+	} else {
+		func() {
+			defer profiling.Track("renderer.renderBlocks.drawAtlas")()
+			markVisibleColumns(visible)
+			flushAllRegionWrites()
+			maybeCompactRegions()
+			skipOccluded := b.occlusion.trusted(occlusion, currentFrame)
+
+			// Draw ready columns per region using multi-draw
+			for _, r := range atlasRegions {
+				if r == nil || len(r.orderedColumns) == 0 {
 					continue
 				}
-				if c.firstVertex < 0 {
-					c.firstVertex = int32(c.firstFloat / 4)
+				firstsScratch, countsScratch = appendVisibleColumnDraws(r, currentFrame, skipOccluded, firstsScratch[:0], countsScratch[:0])
+				if lodTint {
+					b.setLODTint(r.key.step)
 				}
-				cf := c.firstVertex
-				cc := c.vertexCount
-				if hasRun && cf == lastFirst+lastCount {
-					lastCount += cc
-					counts[len(counts)-1] = lastCount
-				} else {
-					firsts = append(firsts, cf)
-					counts = append(counts, cc)
-					lastFirst = cf
-					lastCount = cc
-					hasRun = true
-				}
-				c.drawnFrame = currentFrame
-			}
-			if len(counts) > 0 {
-				gl.BindVertexArray(r.vao)
-				gl.MultiDrawArrays(gl.TRIANGLES, &firsts[0], &counts[0], int32(len(counts)))
-				glCheckError("atlas multi-draw columns")
+				drawRegion(r, firstsScratch, countsScratch)
 			}
-		}
-	}()
+		}()
+	}
+	if lodTint {
+		b.mainShader.SetVector4("lodTint", 0, 0, 0, 0)
+	}
+	if occlusion {
+		func() {
+			defer profiling.Track("renderer.renderBlocks.occlusion")()
+			b.occlusion.run(ctx, currentFrame)
+		}()
+	} else if !ctx.Offscreen {
+		occlusionTestedCount, occlusionOccludedCount = 0, 0
+	}
 	gl.Enable(gl.CULL_FACE)
 
 	// Render Fluids
-	b.renderFluidsInternal(ctx, visible, isUnderwater)
+	b.prepareFluids(ctx, visible, isUnderwater)
+	b.prepareTranslucentBlocks(ctx, visible)
+}
+
+// appendFrustumVisible appends the chunks of nearby that lie within the
+// vertical render radius of eyeY and intersect the frustum.
+func appendFrustumVisible(dst, nearby []world.ChunkWithCoord, planes mathutil.Frustum, eyeY float32, radiusChunks int) []world.ChunkWithCoord {
+	// Pre-calculate common values to avoid repeated calculations
+	chunkSizeXf := float32(world.ChunkSizeX)
+	chunkSizeZf := float32(world.ChunkSizeZ)
+	margin := frustumMargin
+
+	for _, cc := range nearby {
+		if !withinVerticalRadius(cc.Coord, cc.Chunk, eyeY, radiusChunks) {
+			continue
+		}
+		// Calculate chunk bounds with pre-computed constants. Y only spans
+		// the occupied sections, so the empty air above low terrain does not
+		// keep a chunk in view.
+		cx := float32(cc.Coord.X) * chunkSizeXf
+		cz := float32(cc.Coord.Z) * chunkSizeZf
+		lowY, highY := occupiedYBounds(cc.Coord, cc.Chunk)
+
+		// Apply margin directly to avoid intermediate variables
+		minx := cx - margin
+		miny := lowY - margin
+		minz := cz - margin
+		maxx := cx + chunkSizeXf + margin
+		maxy := highY + margin
+		maxz := cz + chunkSizeZf + margin
+
+		if planes.IntersectsAABB(minx, miny, minz, maxx, maxy, maxz) {
+			dst = append(dst, cc)
+		}
+	}
+	return dst
+}
+
+// markVisibleColumns aggregates visible chunks into unique XZ columns, builds
+// any that are missing or dirty and stamps them visible for a new frame.
+func markVisibleColumns(visible []world.ChunkWithCoord) {
+	type xz struct{ x, z int }
+	colSet := make(map[xz]struct{}, len(visible))
+	for _, vc := range visible {
+		colSet[xz{vc.Coord.X, vc.Coord.Z}] = struct{}{}
+	}
+	// Increment frame and mark visible columns for this frame to avoid per-frame maps
+	forMarked := false
+	for k := range colSet {
+		col := ensureColumnMeshForXZ(k.x, k.z)
+		if !forMarked {
+			currentFrame++
+			forMarked = true
+		}
+		if col != nil {
+			col.visibleFrame = currentFrame
+		}
+	}
+}
+
+// appendVisibleColumnDraws appends the draw ranges of r's columns marked
+// visible in frame, merging adjacent ranges. With occlusion set, occluded
+// columns are left out.
+func appendVisibleColumnDraws(r *atlasRegion, frame uint64, occlusion bool, firsts, counts []int32) ([]int32, []int32) {
+	var lastFirst int32
+	var lastCount int32
+	hasRun := false
+	for _, c := range r.orderedColumns {
+		if c == nil {
+			continue
+		}
+		if c.visibleFrame != frame || c.drawnFrame == frame {
+			continue
+		}
+		if c.dirty || c.vertexCount <= 0 || c.firstFloat < 0 {
+			continue
+		}
+		if occlusion && c.occluded {
+			c.drawnFrame = frame
+			continue
+		}
+		if c.firstVertex < 0 {
+			c.firstVertex = int32(c.firstFloat / 4)
+		}
+		cf := c.firstVertex
+		cc := c.vertexCount
+		if hasRun && cf == lastFirst+lastCount {
+			lastCount += cc
+			counts[len(counts)-1] = lastCount
+		} else {
+			firsts = append(firsts, cf)
+			counts = append(counts, cc)
+			lastFirst = cf
+			lastCount = cc
+			hasRun = true
+		}
+		c.drawnFrame = frame
+	}
+	return firsts, counts
+}
+
+// setLODTint tints the columns drawn next by the level of detail step, for
+// config.GetLODDebugTint.
+func (b *Blocks) setLODTint(step int) {
+	c := lodDebugColor(step)
+	b.mainShader.SetVector4("lodTint", c[0], c[1], c[2], lodDebugTint)
+}
+
+// drawRegion issues one multi-draw for the given vertex ranges of r's VBO.
+func drawRegion(r *atlasRegion, firsts, counts []int32) {
+	if len(counts) == 0 {
+		return
+	}
+	gl.BindVertexArray(r.vao)
+	drawQuadRanges(firsts, counts)
+	glCheckError("atlas multi-draw columns")
+}
+
+// quadIndexQuads is how many quads the shared quad indices cover, and so
+// the most one indexed draw takes; longer ranges are split.
+const quadIndexQuads = 1 << 16
+
+// Scratch for drawQuadRanges
+var (
+	indexCountsScratch  []int32
+	baseVertexScratch   []int32
+	indexOffsetsScratch []unsafe.Pointer // all nil: every draw starts at the first index
+)
+
+// appendQuadDraws appends the index counts and base vertices of the indexed
+// draws covering count vertices of quads from vertex first.
+func appendQuadDraws(indexCounts, baseVertices []int32, first, count int32) ([]int32, []int32) {
+	const maxVerts = quadIndexQuads * meshing.QuadVertices
+	for count > 0 {
+		n := min(count, maxVerts)
+		indexCounts = append(indexCounts, n/meshing.QuadVertices*meshing.QuadIndices)
+		baseVertices = append(baseVertices, first)
+		first += n
+		count -= n
+	}
+	return indexCounts, baseVertices
+}
+
+// terrainDrawCalls counts the draw calls issued for terrain, fluids and
+// occlusion boxes, for benchmarks.
+var terrainDrawCalls int
+
+// TerrainDrawCalls returns how many draw calls terrain has taken so far;
+// the difference between two frames is what the later one drew with.
+func TerrainDrawCalls() int {
+	return terrainDrawCalls
+}
+
+// drawQuadRanges draws the given vertex ranges of the bound VAO's quads in
+// one multi-draw through the shared quad indices.
+func drawQuadRanges(firsts, counts []int32) {
+	indexCountsScratch, baseVertexScratch = indexCountsScratch[:0], baseVertexScratch[:0]
+	for i, first := range firsts {
+		indexCountsScratch, baseVertexScratch = appendQuadDraws(indexCountsScratch, baseVertexScratch, first, counts[i])
+	}
+	n := len(indexCountsScratch)
+	if n == 0 {
+		return
+	}
+	for len(indexOffsetsScratch) < n {
+		indexOffsetsScratch = append(indexOffsetsScratch, nil)
+	}
+	gl.MultiDrawElementsBaseVertex(gl.TRIANGLES, &indexCountsScratch[0], gl.UNSIGNED_INT, &indexOffsetsScratch[0], int32(n), &baseVertexScratch[0])
+	terrainDrawCalls++
+}
+
+// translucentBatch is the range of one chunk's vertices in the fluid or
+// translucent block VBO, drawn as one batch of the translucent stage.
+type translucentBatch struct {
+	first, count int32
+	depth        float32
 }
 
-func (b *Blocks) renderFluidsInternal(ctx renderer.RenderContext, visible []world.ChunkWithCoord, isUnderwater int) {
-	// Collect fluid verts from visible chunks
+// prepareFluids uploads the fluid vertices of the visible chunks, farthest
+// chunk first, and records a translucent batch per chunk. The batches are
+// drawn later, sorted together with the other translucent geometry.
+func (b *Blocks) prepareFluids(ctx renderer.RenderContext, visible []world.ChunkWithCoord, isUnderwater int) {
 	b.fluidVerts = b.fluidVerts[:0]
+	b.fluidBatches = b.fluidBatches[:0]
 
+	eye := ctx.Snapshot.Eye
 	for _, vc := range visible {
 		if cm, ok := chunkMeshes[vc.Coord]; ok && cm != nil && len(cm.fluidVerts) > 0 {
+			b.fluidBatches = append(b.fluidBatches, translucentBatch{
+				first: int32(len(b.fluidVerts) / meshing.FluidVertexFloats),
+				count: int32(len(cm.fluidVerts) / meshing.FluidVertexFloats),
+				depth: ColumnDepth(eye, vc.Coord.X, vc.Coord.Z),
+			})
 			b.fluidVerts = append(b.fluidVerts, cm.fluidVerts...)
 		}
 	}
 
+	b.water.active = false
 	if len(b.fluidVerts) == 0 {
 		return
 	}
 
-	func() {
-		defer profiling.Track("renderer.renderFluids")()
+	defer profiling.Track("renderer.prepareFluids")()
+	b.renderReflection(ctx, isUnderwater)
+
+	b.fluidShader.Use()
+	// The water samplers keep units of their own even when unused, since
+	// samplers of different types sharing unit 0 make the draw invalid.
+	b.fluidShader.SetInt("textureArray", 0)
+	b.fluidShader.SetInt("reflectionTex", 1)
+	b.fluidShader.SetInt("refractionTex", 2)
+	b.fluidShader.SetInt("rippleNormals", 3)
+	b.fluidShader.SetMatrix4("proj", &ctx.Proj[0])
+	b.fluidShader.SetMatrix4("view", &ctx.View[0])
+	camera := ctx.Player.RenderPosition()
+	b.fluidShader.SetVector3("cameraPos", camera[0], camera[1], camera[2])
+	b.fluidShader.SetInt("isUnderwater", int32(isUnderwater))
+	b.fluidShader.SetFloat("time", float32(time.Since(b.startTime).Seconds()))
+	b.fluidShader.SetFloat("skyDarken", ctx.World.SkyDarkening())
+	fancyWater := int32(0)
+	if b.water.active {
+		fancyWater = 1
+		still, flow := waterLayers()
+		b.fluidShader.SetVector2("screenSize", float32(b.water.width), float32(b.water.height))
+		b.fluidShader.SetFloat("waterPlaneY", b.water.planeY)
+		eye := ctx.Snapshot.Eye
+		b.fluidShader.SetVector3("eyePos", eye.X(), eye.Y(), eye.Z())
+		b.fluidShader.SetVector2("waterLayers", still, flow)
+	}
+	b.fluidShader.SetInt("fancyWater", fancyWater)
 
-		gl.Enable(gl.BLEND)
-		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
-		gl.Disable(gl.CULL_FACE)
-		gl.DepthMask(false)
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.fluidVBO)
+	requiredSize := len(b.fluidVerts) * 4
+	if requiredSize > b.fluidVertsCap*4 {
+		// Grow buffer
+		b.fluidVertsCap = max(len(b.fluidVerts), b.fluidVertsCap*2)
+		gl.BufferData(gl.ARRAY_BUFFER, b.fluidVertsCap*4, nil, gl.DYNAMIC_DRAW)
+	}
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(b.fluidVerts)*4, gl.Ptr(b.fluidVerts))
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
 
-		b.fluidShader.Use()
+// prepareTranslucentBlocks uploads the translucent block vertices of the
+// visible chunks and records a translucent batch per chunk, like
+// prepareFluids. They are drawn with the main shader, which keeps the
+// uniforms the opaque pass set.
+func (b *Blocks) prepareTranslucentBlocks(ctx renderer.RenderContext, visible []world.ChunkWithCoord) {
+	b.translucentVerts = b.translucentVerts[:0]
+	b.translucentBatches = b.translucentBatches[:0]
 
-		if GlobalTextureAtlas != nil {
-			gl.ActiveTexture(gl.TEXTURE0)
-			gl.BindTexture(gl.TEXTURE_2D_ARRAY, GlobalTextureAtlas.TextureID)
-			b.fluidShader.SetInt("textureArray", 0)
+	eye := ctx.Snapshot.Eye
+	for _, vc := range visible {
+		if cm, ok := chunkMeshes[vc.Coord]; ok && cm != nil && len(cm.translucentVerts) > 0 {
+			first := len(b.translucentVerts) / 6
+			b.translucentVerts = appendAtlasVerts(b.translucentVerts, vc.Coord, cm.translucentVerts)
+			b.translucentBatches = append(b.translucentBatches, translucentBatch{
+				first: int32(first),
+				count: int32(len(b.translucentVerts)/6 - first),
+				depth: ColumnDepth(eye, vc.Coord.X, vc.Coord.Z),
+			})
 		}
+	}
+	if len(b.translucentVerts) == 0 {
+		return
+	}
 
-		b.fluidShader.SetMatrix4("proj", &ctx.Proj[0])
-		b.fluidShader.SetMatrix4("view", &ctx.View[0])
-		b.fluidShader.SetVector3("cameraPos", ctx.Player.Position[0], ctx.Player.Position[1], ctx.Player.Position[2])
-		b.fluidShader.SetInt("isUnderwater", int32(isUnderwater))
-		b.fluidShader.SetFloat("time", float32(time.Since(b.fluidStartTime).Seconds()))
-
-		// Upload data
-		gl.BindVertexArray(b.fluidVAO)
-		gl.BindBuffer(gl.ARRAY_BUFFER, b.fluidVBO)
-
-		requiredSize := len(b.fluidVerts) * 4
-		if requiredSize > b.fluidVertsCap*4 {
-			// Grow buffer
-			b.fluidVertsCap = max(len(b.fluidVerts), b.fluidVertsCap*2)
-			gl.BufferData(gl.ARRAY_BUFFER, b.fluidVertsCap*4, nil, gl.DYNAMIC_DRAW)
-		}
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.translucentVBO)
+	if len(b.translucentVerts) > b.translucentVertsCap {
+		b.translucentVertsCap = max(len(b.translucentVerts), b.translucentVertsCap*2)
+		gl.BufferData(gl.ARRAY_BUFFER, b.translucentVertsCap*2, nil, gl.DYNAMIC_DRAW)
+	}
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(b.translucentVerts)*2, gl.Ptr(b.translucentVerts))
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
+
+// AppendTranslucent adds a batch per visible chunk with fluids and one per
+// visible chunk with translucent blocks. With fancy water it also captures
+// the finished opaque scene for refraction.
+func (b *Blocks) AppendTranslucent(ctx renderer.RenderContext, dst []renderer.TranslucentBatch) []renderer.TranslucentBatch {
+	if b.water.active {
+		b.water.copyRefraction()
+	}
+	for i, fb := range b.fluidBatches {
+		dst = append(dst, renderer.TranslucentBatch{Depth: fb.depth, Owner: b, Index: i})
+	}
+	for i, tb := range b.translucentBatches {
+		dst = append(dst, renderer.TranslucentBatch{Depth: tb.depth, Owner: b, Index: len(b.fluidBatches) + i})
+	}
+	return dst
+}
 
-		// Upload (Orphan)
-		// gl.BufferData(gl.ARRAY_BUFFER, b.fluidVertsCap*4, nil, gl.DYNAMIC_DRAW) // Orphan if same size?
-		// Just BufferSubData for now
-		gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(b.fluidVerts)*4, gl.Ptr(b.fluidVerts))
+// DrawTranslucent draws one chunk's fluids or, for indices past the fluid
+// batches, its translucent blocks.
+func (b *Blocks) DrawTranslucent(ctx renderer.RenderContext, index int) {
+	if index >= len(b.fluidBatches) {
+		b.drawTranslucentBlocks(b.translucentBatches[index-len(b.fluidBatches)])
+		return
+	}
+	fb := b.fluidBatches[index]
+	b.fluidShader.Use()
+	if GlobalTextureAtlas != nil {
+		gl.ActiveTexture(gl.TEXTURE0)
+		gl.BindTexture(gl.TEXTURE_2D_ARRAY, GlobalTextureAtlas.TextureID)
+	}
+	if b.water.active {
+		b.water.bindTextures()
+	}
+	gl.BindVertexArray(b.fluidVAO)
+	gl.DrawArrays(gl.TRIANGLES, fb.first, fb.count)
+	terrainDrawCalls++
+	gl.BindVertexArray(0)
+}
 
-		// Draw
-		count := int32(len(b.fluidVerts) / 10) // 10 floats per vertex
-		gl.DrawArrays(gl.TRIANGLES, 0, count)
+// drawTranslucentBlocks draws one chunk's translucent blocks.
+func (b *Blocks) drawTranslucentBlocks(tb translucentBatch) {
+	b.mainShader.Use()
+	if GlobalTextureAtlas != nil {
+		gl.ActiveTexture(gl.TEXTURE0)
+		gl.BindTexture(gl.TEXTURE_2D_ARRAY, GlobalTextureAtlas.TextureID)
+	}
+	gl.BindVertexArray(b.translucentVAO)
+	drawQuadRanges([]int32{tb.first}, []int32{tb.count})
+	gl.BindVertexArray(0)
+}
 
-		gl.BindVertexArray(0)
-		gl.DepthMask(true)
-		gl.Enable(gl.CULL_FACE)
-		gl.Disable(gl.BLEND)
-	}()
+// ColumnDepth returns the translucent stage depth of chunk column (cx, cz):
+// the squared horizontal distance from eye to the column's centre.
+// Translucent geometry is batched per column so it sorts consistently.
+func ColumnDepth(eye mgl32.Vec3, cx, cz int) float32 {
+	dx := float32(cx*world.ChunkSizeX+world.ChunkSizeX/2) - eye.X()
+	dz := float32(cz*world.ChunkSizeZ+world.ChunkSizeZ/2) - eye.Z()
+	return dx*dx + dz*dz
 }
 
 func glCheckError(label string) {
 	if err := gl.GetError(); err != gl.NO_ERROR {
-		log.Printf("gl error %s: 0x%x", label, err)
+		slog.Error("gl error", "label", label, "code", err)
 	}
 }
@@ -0,0 +1,143 @@
+package blocks
+
+import (
+	"slices"
+
+	"mini-mc/internal/mathutil"
+	"mini-mc/internal/meshing"
+	"mini-mc/internal/world"
+)
+
+// Packed column culling (experimental, toggled with config.TogglePackedColumnCulling).
+//
+// The default path frustum-tests every nearby chunk, folds the survivors into
+// a column set map and then walks each region's orderedColumns to build the
+// multi-draw lists. The packed path instead keeps, per atlas region, one flat
+// array of column AABBs and draw parameters that is rebuilt only when the
+// region layout changes; each frame is a single linear pass over it that
+// writes the draw lists directly.
+//
+// The record layout is the one a GPU-driven version would upload: bounds for
+// a culling shader and {count, instanceCount, firstIndex, baseVertex,
+// baseInstance} for glMultiDrawElementsIndirect over the shared quad indices.
+// Both need GL 4.3 (compute, indirect multi-draw) while the renderer targets
+// 4.1 core, so the commands are written on the CPU and submitted through
+// glMultiDrawElementsBaseVertex as the portable fallback.
+
+// drawElementsIndirectCommand mirrors DrawElementsIndirectCommand from the GL
+// spec.
+type drawElementsIndirectCommand struct {
+	count         uint32 // indices, meshing.QuadIndices per quad
+	instanceCount uint32
+	firstIndex    uint32
+	baseVertex    int32
+	baseInstance  uint32
+}
+
+// vertices returns how many vertices the command's quads take in the atlas.
+func (cmd drawElementsIndirectCommand) vertices() uint32 {
+	return cmd.count / meshing.QuadIndices * meshing.QuadVertices
+}
+
+// packedColumns is the culling input for one atlas region.
+type packedColumns struct {
+	bounds  []float32 // minX, minY, minZ, maxX, maxY, maxZ per column, Y tight to the mesh
+	cmds    []drawElementsIndirectCommand
+	cols    []*columnMesh
+	coords  [][2]int // column XZ in chunk units, for the radius test
+	version uint64   // atlasRegion.layoutVersion the arrays were built from
+	visible []bool   // per-frame frustum test results, scratch
+}
+
+// refreshPackedColumns rebuilds r.packed from r.orderedColumns if the region
+// layout changed since the last build.
+func refreshPackedColumns(r *atlasRegion) {
+	p := &r.packed
+	if p.version == r.layoutVersion {
+		return
+	}
+	p.bounds = p.bounds[:0]
+	p.cmds = p.cmds[:0]
+	p.cols = p.cols[:0]
+	p.coords = p.coords[:0]
+	for _, c := range r.orderedColumns {
+		if c == nil || c.vertexCount <= 0 || c.firstFloat < 0 {
+			continue
+		}
+		appendPackedColumn(p, c)
+	}
+	p.version = r.layoutVersion
+}
+
+func appendPackedColumn(p *packedColumns, c *columnMesh) {
+	minX := float32(c.x * world.ChunkSizeX)
+	minZ := float32(c.z * world.ChunkSizeZ)
+	minY, maxY := c.yBounds()
+	p.bounds = append(p.bounds,
+		minX, minY, minZ,
+		minX+world.ChunkSizeX, maxY, minZ+world.ChunkSizeZ,
+	)
+	p.cmds = append(p.cmds, drawElementsIndirectCommand{
+		count:         uint32(c.vertexCount) / meshing.QuadVertices * meshing.QuadIndices,
+		instanceCount: 1,
+		baseVertex:    int32(c.firstFloat / 6),
+	})
+	p.cols = append(p.cols, c)
+	p.coords = append(p.coords, [2]int{c.x, c.z})
+}
+
+// cullPackedColumns appends the draw ranges of columns within radiusChunks of
+// (pcx, pcz) whose bounds intersect the frustum, merging adjacent ranges.
+// Columns that pass are stamped with frame so LRU eviction sees them as used;
+// with occlusion set, those occluded are then left out of the lists.
+// The frustum test runs over all the bounds in one batch first.
+func cullPackedColumns(p *packedColumns, planes mathutil.Frustum, pcx, pcz, radiusChunks int, frame uint64, occlusion bool, firsts, counts []int32) ([]int32, []int32) {
+	p.visible = slices.Grow(p.visible[:0], len(p.cmds))[:len(p.cmds)]
+	planes.CullAABBs(p.bounds, frustumMargin, p.visible)
+
+	r2 := radiusChunks * radiusChunks
+	var lastEnd int32
+	hasRun := false
+	for i := range p.cmds {
+		if !p.visible[i] {
+			continue
+		}
+		dx := p.coords[i][0] - pcx
+		dz := p.coords[i][1] - pcz
+		if dx*dx+dz*dz > r2 {
+			continue
+		}
+		c := p.cols[i]
+		if c.dirty {
+			continue
+		}
+		c.visibleFrame = frame
+		c.drawnFrame = frame
+		if occlusion && c.occluded {
+			continue
+		}
+		cmd := p.cmds[i]
+		first, count := cmd.baseVertex, int32(cmd.vertices())
+		if hasRun && first == lastEnd {
+			counts[len(counts)-1] += count
+		} else {
+			firsts = append(firsts, first)
+			counts = append(counts, count)
+			hasRun = true
+		}
+		lastEnd = first + count
+	}
+	return firsts, counts
+}
+
+// ensureVisibleColumns builds or rebuilds the columns of visible chunks that
+// are missing or dirty. Unlike the default path it does not mark visibility;
+// cullPackedColumns does that from the packed bounds.
+func ensureVisibleColumns(visible []world.ChunkWithCoord) {
+	for _, vc := range visible {
+		col := columnMeshes[[2]int{vc.Coord.X, vc.Coord.Z}]
+		if col == nil || col.dirty {
+			ensureColumnMeshForXZ(vc.Coord.X, vc.Coord.Z)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package blocks
+
+import (
+	"slices"
+	"testing"
+
+	"mini-mc/internal/mathutil"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// cullBenchRadius matches the render radius the packed path was evaluated at.
+const cullBenchRadius = 50
+
+// setupCullScene fills the column and region tables with a disc of clean,
+// uploaded columns around the origin, laid out contiguously per region as the
+// allocator would, and returns the matching nearby chunk list. No GL calls
+// are made: both culling paths only read this bookkeeping.
+func setupCullScene(radius int) []world.ChunkWithCoord {
+	columnMeshes = make(map[[2]int]*columnMesh)
+	atlasRegions = make(map[atlasKey]*atlasRegion)
+	currentFrame = 0
+
+	var nearby []world.ChunkWithCoord
+	for x := -radius; x <= radius; x++ {
+		for z := -radius; z <= radius; z++ {
+			if x*x+z*z > radius*radius {
+				continue
+			}
+			key := regionKeyFor(x, z, 1)
+			r := atlasRegions[key]
+			if r == nil {
+				r = &atlasRegion{key: key}
+				atlasRegions[key] = r
+			}
+			const verts = 600
+			col := &columnMesh{x: x, z: z, vertexCount: verts, firstFloat: r.totalFloats, regionKey: key, lodStep: 1}
+			col.firstVertex = int32(col.firstFloat / 6)
+			r.totalFloats += verts * 6
+			r.orderedColumns = append(r.orderedColumns, col)
+			r.activeColumns++
+			r.layoutVersion++
+			columnMeshes[[2]int{x, z}] = col
+
+			coord := world.ChunkCoord{X: x, Z: z}
+			nearby = append(nearby, world.ChunkWithCoord{Coord: coord, Chunk: world.NewChunk(x, 0, z)})
+		}
+	}
+	return nearby
+}
+
+func cullBenchPlanes() mathutil.Frustum {
+	proj := mgl32.Perspective(mgl32.DegToRad(70), 16.0/9.0, 0.1, 1000)
+	view := mgl32.LookAtV(mgl32.Vec3{0, 80, 0}, mgl32.Vec3{1, 70, 0.3}, mgl32.Vec3{0, 1, 0})
+	return mathutil.FrustumFromMatrix(proj.Mul4(view))
+}
+
+// defaultDrawLists runs the per-chunk culling path without issuing draws.
+func defaultDrawLists(nearby []world.ChunkWithCoord, planes mathutil.Frustum) (firsts, counts []int32) {
+	visible := appendFrustumVisible(nil, nearby, planes, 80, cullBenchRadius)
+	markVisibleColumns(visible)
+	for _, r := range atlasRegions {
+		firsts, counts = appendVisibleColumnDraws(r, currentFrame, false, firsts, counts)
+	}
+	return firsts, counts
+}
+
+// packedDrawLists runs the packed culling path without issuing draws.
+func packedDrawLists(planes mathutil.Frustum) (firsts, counts []int32) {
+	currentFrame++
+	for _, r := range atlasRegions {
+		refreshPackedColumns(r)
+		firsts, counts = cullPackedColumns(&r.packed, planes, 0, 0, cullBenchRadius, currentFrame, false, firsts, counts)
+	}
+	return firsts, counts
+}
+
+// drawnColumns expands draw ranges into the sorted first vertices of the
+// columns they cover, so merged and unmerged lists compare equal.
+func drawnColumns(firsts, counts []int32) []int32 {
+	var starts []int32
+	for i, f := range firsts {
+		for v := f; v < f+counts[i]; v += 600 {
+			starts = append(starts, v)
+		}
+	}
+	slices.Sort(starts)
+	return starts
+}
+
+func TestPackedColumnCullingMatchesDefault(t *testing.T) {
+	nearby := setupCullScene(cullBenchRadius)
+	planes := cullBenchPlanes()
+
+	df, dc := defaultDrawLists(nearby, planes)
+	pf, pc := packedDrawLists(planes)
+	want := drawnColumns(df, dc)
+	got := drawnColumns(pf, pc)
+	if len(want) == 0 {
+		t.Fatal("default path drew nothing")
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("packed path drew %d columns, default drew %d", len(got), len(want))
+	}
+}
+
+func TestTightYBoundsCullTerrainBelowView(t *testing.T) {
+	nearby := setupCullScene(4)
+	// Plains up to y=64, looked over from high above at the horizon
+	for _, cc := range nearby {
+		cc.Chunk.SetBlock(0, 63, 0, world.BlockTypeGrass)
+	}
+	for _, col := range columnMeshes {
+		col.minY, col.maxY = 60, 64
+	}
+	proj := mgl32.Perspective(mgl32.DegToRad(70), 16.0/9.0, 0.1, 1000)
+	view := mgl32.LookAtV(mgl32.Vec3{0, 200, 0}, mgl32.Vec3{1, 200, 0}, mgl32.Vec3{0, 1, 0})
+	planes := mathutil.FrustumFromMatrix(proj.Mul4(view))
+
+	if df, _ := defaultDrawLists(nearby, planes); len(df) != 0 {
+		t.Errorf("default path drew %d ranges of terrain below the view", len(df))
+	}
+	if pf, _ := packedDrawLists(planes); len(pf) != 0 {
+		t.Errorf("packed path drew %d ranges of terrain below the view", len(pf))
+	}
+
+	// A column reaching up into the view is drawn again
+	col := columnMeshes[[2]int{3, 0}]
+	col.maxY = 250
+	atlasRegions[col.regionKey].layoutVersion++
+	if pf, _ := packedDrawLists(planes); len(pf) != 1 {
+		t.Errorf("packed path drew %d ranges, want the tall column", len(pf))
+	}
+}
+
+// BenchmarkColumnCulling compares the CPU time of building the per-frame draw
+// lists at a 50-chunk radius with the per-chunk loop and the packed arrays.
+func BenchmarkColumnCulling(b *testing.B) {
+	b.Run("default", func(b *testing.B) {
+		nearby := setupCullScene(cullBenchRadius)
+		planes := cullBenchPlanes()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for b.Loop() {
+			defaultDrawLists(nearby, planes)
+		}
+	})
+	b.Run("packed", func(b *testing.B) {
+		setupCullScene(cullBenchRadius)
+		planes := cullBenchPlanes()
+		packedDrawLists(planes) // build the packed arrays outside the timed loop
+		b.ReportAllocs()
+		b.ResetTimer()
+		for b.Loop() {
+			packedDrawLists(planes)
+		}
+	})
+}
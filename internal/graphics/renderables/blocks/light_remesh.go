@@ -0,0 +1,77 @@
+package blocks
+
+import (
+	"mini-mc/internal/mathutil"
+	"mini-mc/internal/meshing"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// lightRemeshBudget caps how many lighting rebuilds are submitted per frame,
+// so a large relight does not starve block edits and newly loaded chunks.
+const lightRemeshBudget = 16
+
+// lightRemeshes holds chunks relit since they were last meshed, handed over
+// by the world (see world.World.OnRelight). Their blocks did not change, so
+// the dirty flag does not cover them.
+var (
+	lightRemeshes       = meshing.NewRemeshQueue()
+	lightRemeshScratch  []world.ChunkCoord
+	lightRemeshDeferred []world.ChunkCoord
+)
+
+// InvalidateChunkLighting queues chunks for a rebuild after a lighting
+// change. Safe to call from any goroutine; repeated calls for a chunk before
+// it is rebuilt coalesce.
+func InvalidateChunkLighting(coords ...world.ChunkCoord) {
+	lightRemeshes.Invalidate(coords...)
+}
+
+// submitLightRemeshes sends the most urgent relit chunks to the priority mesh
+// queue: on-screen chunks first, then by distance from the eye. Chunks
+// without a mesh yet are dropped since their first mesh will be lit; chunks
+// with a job in flight wait for the next frame.
+func submitLightRemeshes(w *world.World, eye mgl32.Vec3, planes mathutil.Frustum) {
+	if meshPool == nil || lightRemeshes.Len() == 0 {
+		return
+	}
+	visible := func(c world.ChunkCoord) bool {
+		minX := float32(c.X * world.ChunkSizeX)
+		minY := float32(c.Y * world.ChunkSizeY)
+		minZ := float32(c.Z * world.ChunkSizeZ)
+		return planes.IntersectsAABB(minX, minY, minZ,
+			minX+world.ChunkSizeX, minY+world.ChunkSizeY, minZ+world.ChunkSizeZ)
+	}
+	lightRemeshScratch = lightRemeshes.Drain(eye, visible, lightRemeshBudget, lightRemeshScratch[:0])
+
+	lightRemeshDeferred = lightRemeshDeferred[:0]
+	for i, coord := range lightRemeshScratch {
+		if chunkMeshes[coord] == nil {
+			continue
+		}
+		ch := w.GetChunk(coord.X, coord.Y, coord.Z, false)
+		if ch == nil || ch.IsDirty() {
+			// A dirty chunk is rebuilt with its current light anyway
+			continue
+		}
+
+		pendingMeshMutex.RLock()
+		_, pending := pendingMeshJobs[coord]
+		pendingMeshMutex.RUnlock()
+		if pending {
+			lightRemeshDeferred = append(lightRemeshDeferred, coord)
+			continue
+		}
+
+		if !meshPool.SubmitPriorityJob(newMeshJob(w, coord, ch)) {
+			// Queue full: keep this and the rest for later frames
+			lightRemeshDeferred = append(lightRemeshDeferred, lightRemeshScratch[i:]...)
+			break
+		}
+		markMeshPending(coord)
+	}
+	if len(lightRemeshDeferred) > 0 {
+		lightRemeshes.Invalidate(lightRemeshDeferred...)
+	}
+}
@@ -0,0 +1,178 @@
+package blocks
+
+import (
+	"math"
+	"unsafe"
+
+	"mini-mc/internal/meshing"
+	"mini-mc/internal/world"
+)
+
+// Level of detail (config.GetLODDistance).
+//
+// Columns beyond the LOD distance from the player are drawn from the chunks'
+// LOD meshes at half detail, and beyond twice the distance at quarter detail
+// (see meshing/lod.go). Each step has its own atlas regions, so a column
+// switching step frees its old slot instead of resizing it among columns of
+// another step. A column only switches once it is lodHysteresis chunks past
+// the boundary, so walking along it does not swap meshes back and forth.
+//
+// Chunks far enough out get their LOD meshes built along with the full mesh,
+// which is kept for the fluids and translucent blocks and for when the player
+// comes back. Where a coarse column borders a column at another step, skirts
+// hang from its edge cells to hide the cracks between the two surfaces.
+
+// lodHysteresis is how far, in chunks, a column must pass a step's boundary
+// before switching to it
+const lodHysteresis = 2.0
+
+// lodSkirtDepth is how far skirts reach below a cell's top: the coarsest step
+var lodSkirtDepth = meshing.LODSteps[len(meshing.LODSteps)-1]
+
+var (
+	lodCenter   [2]int // player column the steps were last chosen around
+	lodDistance int    // LOD distance the steps were last chosen with
+	lodScratch  []uint32
+)
+
+// lodThreshold returns the distance in chunks beyond which columns are drawn
+// at step: lodDist for half detail, twice that for quarter detail.
+func lodThreshold(step, lodDist int) float64 {
+	return float64(lodDist * step / 2)
+}
+
+// lodStepAt returns the step a column dist chunks from the player is drawn
+// at, given the step cur it is drawn at now. Steps double from 1 up to the
+// last of meshing.LODSteps.
+func lodStepAt(dist float64, cur, lodDist int) int {
+	if lodDist <= 0 {
+		return 1
+	}
+	maxStep := meshing.LODSteps[len(meshing.LODSteps)-1]
+	step := max(cur, 1)
+	for step > 1 && dist < lodThreshold(step, lodDist)-lodHysteresis {
+		step /= 2
+	}
+	for step < maxStep && dist >= lodThreshold(step*2, lodDist)+lodHysteresis {
+		step *= 2
+	}
+	return step
+}
+
+// lodDebugTint is how strongly config.GetLODDebugTint tints columns
+const lodDebugTint = 0.4
+
+// lodDebugColor returns the colour config.GetLODDebugTint tints columns
+// drawn at step with: green at full detail, yellow at half, red at quarter.
+func lodDebugColor(step int) [3]float32 {
+	switch {
+	case step <= 1:
+		return [3]float32{0.2, 0.9, 0.2}
+	case step == 2:
+		return [3]float32{0.95, 0.85, 0.15}
+	default:
+		return [3]float32{0.95, 0.2, 0.15}
+	}
+}
+
+// lodDistanceTo returns how far column (x, z) is from lodCenter in chunks.
+func lodDistanceTo(x, z int) float64 {
+	return math.Hypot(float64(x-lodCenter[0]), float64(z-lodCenter[1]))
+}
+
+// columnLODStep returns the step column (x, z), drawn at cur, should be at.
+func columnLODStep(x, z, cur int) int {
+	return lodStepAt(lodDistanceTo(x, z), cur, lodDistance)
+}
+
+// updateColumnLODs chooses the columns' steps again when the player column
+// (pcx, pcz) or the LOD distance changed. A column that switches is rebuilt,
+// along with its neighbours, whose skirts depend on its step.
+func updateColumnLODs(pcx, pcz, distance int) {
+	if lodCenter == [2]int{pcx, pcz} && lodDistance == distance {
+		return
+	}
+	lodCenter, lodDistance = [2]int{pcx, pcz}, distance
+	for _, col := range columnMeshes {
+		step := columnLODStep(col.x, col.z, col.lodStep)
+		if step == col.lodStep {
+			continue
+		}
+		col.lodStep = step
+		col.dirty = true
+		for _, d := range [4][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}} {
+			if nb := columnMeshes[[2]int{col.x + d[0], col.z + d[1]}]; nb != nil {
+				nb.dirty = true
+			}
+		}
+	}
+}
+
+// wantLODMeshes reports whether the chunk at coord is far enough out that
+// its mesh job should build LOD meshes too.
+func wantLODMeshes(coord world.ChunkCoord) bool {
+	return lodDistance > 0 && lodDistanceTo(coord.X, coord.Z) >= lodThreshold(2, lodDistance)-2*lodHysteresis
+}
+
+// missingLODMeshes reports whether m was built without the LOD meshes its
+// chunk at coord now wants.
+func missingLODMeshes(m *chunkMesh, coord world.ChunkCoord) bool {
+	return m != nil && !m.lodBuilt && wantLODMeshes(coord)
+}
+
+// lodMesh returns m's LOD mesh at step, or nil for full detail and when m
+// has none.
+func (m *chunkMesh) lodMesh(step int) *meshing.LODMesh {
+	if step <= 1 {
+		return nil
+	}
+	for i := range m.lod {
+		if m.lod[i].Step == step {
+			return &m.lod[i]
+		}
+	}
+	return nil
+}
+
+// lodSkirtBorders returns the edges of col that face a loaded column drawn
+// at another step. Full detail columns have no skirts.
+func lodSkirtBorders(col *columnMesh) meshing.SkirtBorders {
+	if col.lodStep <= 1 {
+		return 0
+	}
+	var borders meshing.SkirtBorders
+	for _, nb := range [...]struct {
+		dx, dz int
+		border meshing.SkirtBorders
+	}{
+		{0, 1, meshing.SkirtNorth},
+		{0, -1, meshing.SkirtSouth},
+		{1, 0, meshing.SkirtEast},
+		{-1, 0, meshing.SkirtWest},
+	} {
+		if c := columnMeshes[[2]int{col.x + nb.dx, col.z + nb.dz}]; c != nil && c.lodStep != col.lodStep {
+			borders |= nb.border
+		}
+	}
+	return borders
+}
+
+// appendLODVerts is appendAtlasVerts for an LOD mesh, with skirts on borders.
+func appendLODVerts(buf []int16, coord world.ChunkCoord, lod *meshing.LODMesh, borders meshing.SkirtBorders) []int16 {
+	buf = appendAtlasVerts(buf, coord, lod.Vertices)
+	if borders == 0 {
+		return buf
+	}
+	lodScratch = lodScratch[:0]
+	meshing.AppendLODSkirts(&lodScratch, lod.Columns, lod.Step, lodSkirtDepth, borders)
+	return appendAtlasVerts(buf, coord, lodScratch)
+}
+
+// lodMeshBytes returns the bytes of the LOD meshes in lod.
+func lodMeshBytes(lod []meshing.LODMesh) int {
+	total := 0
+	for _, l := range lod {
+		total += 4*len(l.Vertices) + len(l.Columns)*int(unsafe.Sizeof(meshing.LODColumn{}))
+	}
+	return total
+}
@@ -0,0 +1,88 @@
+package blocks
+
+import (
+	"slices"
+	"testing"
+
+	"mini-mc/internal/meshing"
+)
+
+func TestLODStepHysteresis(t *testing.T) {
+	const d = 16
+	for _, tc := range []struct {
+		dist      float64
+		cur, want int
+	}{
+		{10, 1, 1},
+		{17, 1, 1}, // past the boundary, not yet past the margin
+		{18, 1, 2},
+		{15, 2, 2}, // back inside, not yet past the margin
+		{13, 2, 1},
+		{40, 1, 4}, // far out from full detail in one go
+		{33, 4, 4},
+		{29, 4, 2},
+		{5, 4, 1},
+		{100, 2, 4}, // no step past the coarsest
+	} {
+		if got := lodStepAt(tc.dist, tc.cur, d); got != tc.want {
+			t.Errorf("lodStepAt(%v, %d) = %d, want %d", tc.dist, tc.cur, got, tc.want)
+		}
+	}
+	if got := lodStepAt(100, 4, 0); got != 1 {
+		t.Errorf("LOD off: step %d, want 1", got)
+	}
+}
+
+func TestLODDebugColorPerStep(t *testing.T) {
+	steps := append([]int{1}, meshing.LODSteps[:]...)
+	seen := make(map[[3]float32]int)
+	for _, step := range steps {
+		c := lodDebugColor(step)
+		if other, ok := seen[c]; ok {
+			t.Errorf("steps %d and %d are tinted alike", other, step)
+		}
+		seen[c] = step
+	}
+}
+
+func TestLODSkirtsFaceOtherSteps(t *testing.T) {
+	columnMeshes = make(map[[2]int]*columnMesh)
+	add := func(x, z, step int) *columnMesh {
+		c := &columnMesh{x: x, z: z, lodStep: step}
+		columnMeshes[[2]int{x, z}] = c
+		return c
+	}
+	col := add(0, 0, 2)
+	add(1, 0, 2)
+	add(-1, 0, 4)
+	add(0, 1, 1)
+	if got, want := lodSkirtBorders(col), meshing.SkirtWest|meshing.SkirtNorth; got != want {
+		t.Errorf("borders %04b, want %04b", got, want)
+	}
+	if got := lodSkirtBorders(columnMeshes[[2]int{0, 1}]); got != 0 {
+		t.Errorf("full detail column has skirts %04b", got)
+	}
+}
+
+func TestColumnSwitchingStepLeavesItsRegion(t *testing.T) {
+	setupCullScene(2)
+	col := columnMeshes[[2]int{1, 1}]
+	r := atlasRegions[col.regionKey]
+	col.listed = true
+	active := r.activeColumns
+
+	releaseColumnSlot(col)
+
+	if slices.Contains(r.orderedColumns, col) || col.listed {
+		t.Fatal("column still listed in its old region")
+	}
+	if r.activeColumns != active-1 || len(r.freeList) == 0 {
+		t.Fatalf("slot not freed: %d active, free list %v", r.activeColumns, r.freeList)
+	}
+	if col.firstFloat != -1 || col.vertexCount != 0 {
+		t.Fatalf("column keeps slot %d (%d vertices)", col.firstFloat, col.vertexCount)
+	}
+	if regionKeyFor(col.x, col.z, 2) == col.regionKey {
+		t.Fatal("steps share a region")
+	}
+}
@@ -0,0 +1,163 @@
+package blocks
+
+import (
+	"log/slog"
+	"math"
+	"sort"
+
+	"mini-mc/internal/config"
+	"mini-mc/internal/world"
+)
+
+// Block meshes are held twice: packed in the GPU atlas, and as the CPU copy
+// each column is rebuilt from. When the two together pass the configured cap,
+// the columns farthest from the player are evicted and a limit radius keeps
+// them from being meshed or uploaded straight back. The limit widens again a
+// chunk at a time once usage has fallen well below the cap.
+
+const (
+	meshMemoryTargetPercent = 90 // evict down to this share of the cap
+	meshMemoryRelaxPercent  = 75 // widen the limit only below this share
+	meshLimitRelaxFrames    = 60 // frames between widening the limit by a chunk
+)
+
+var (
+	cpuMeshBytes     int    // CPU copies held by chunkMeshes
+	meshLimitCenter  [2]int // player column the limit radius is measured from
+	meshLimitSq      int    // squared limit radius in chunks; 0 means no limit
+	meshLimitRelaxAt uint64 // earliest frame the limit may widen again
+)
+
+// chunkMeshBytes returns the bytes m's CPU copies take.
+func chunkMeshBytes(m *chunkMesh) int {
+	return 4*(len(m.cpuVerts)+len(m.fluidVerts)+len(m.translucentVerts)) + lodMeshBytes(m.lod)
+}
+
+// MeshMemoryStats returns the bytes held by the GPU atlas and by the CPU mesh
+// copies, the cap on the two together, and the radius in chunks meshes are
+// limited to after an eviction (0 when unlimited).
+func MeshMemoryStats() (atlasBytes, cpuBytes, capBytes, limitRadius int) {
+	if meshLimitSq > 0 {
+		limitRadius = int(math.Sqrt(float64(meshLimitSq)))
+	}
+	return totalAllocatedBytes, cpuMeshBytes, config.GetMeshMemoryCapMB() << 20, limitRadius
+}
+
+// beyondMeshLimit reports whether column (x, z) lies outside the limit radius.
+func beyondMeshLimit(x, z int) bool {
+	if meshLimitSq == 0 {
+		return false
+	}
+	dx, dz := x-meshLimitCenter[0], z-meshLimitCenter[1]
+	return dx*dx+dz*dz >= meshLimitSq
+}
+
+// enforceMeshMemoryCap evicts the meshes farthest from player column
+// (pcx, pcz) while usage is over the cap, and widens the limit radius once
+// usage is well below it.
+func enforceMeshMemoryCap(pcx, pcz int) {
+	meshLimitCenter = [2]int{pcx, pcz}
+	capBytes := config.GetMeshMemoryCapMB() << 20
+	used := totalAllocatedBytes + cpuMeshBytes
+	if used > capBytes {
+		needed := used - capBytes*meshMemoryTargetPercent/100
+		if freed := evictFarthestMeshes(needed, config.GetKeepEvictedMeshCopies()); freed > 0 {
+			slog.Info("mesh memory over cap", "used", used, "cap", capBytes, "freed", freed, "limitSq", meshLimitSq)
+		}
+		meshLimitRelaxAt = currentFrame + meshLimitRelaxFrames
+		return
+	}
+	if meshLimitSq == 0 || used > capBytes*meshMemoryRelaxPercent/100 || currentFrame < meshLimitRelaxAt {
+		return
+	}
+	r := int(math.Sqrt(float64(meshLimitSq))) + 1
+	if r > config.GetMaxRenderRadius() {
+		meshLimitSq = 0
+	} else {
+		meshLimitSq = r * r
+	}
+	meshLimitRelaxAt = currentFrame + meshLimitRelaxFrames
+}
+
+// evictFarthestMeshes frees at least neededBytes of mesh memory, if there is
+// that much, one column at a time starting with the farthest from
+// meshLimitCenter, and pulls the limit radius in to the nearest column
+// evicted. A column gives up its atlas slot and, unless keepCopies is set,
+// its CPU copies too. Copies are dropped even with keepCopies when all the
+// atlas slots together would not free enough. Returns the bytes freed,
+// counting atlas slots by their contents rather than by what compaction
+// returns.
+func evictFarthestMeshes(neededBytes int, keepCopies bool) int {
+	flushAllRegionWrites()
+
+	type candidate struct {
+		key    [2]int
+		distSq int
+		chunks []world.ChunkCoord
+	}
+	byColumn := make(map[[2]int]*candidate, len(columnMeshes))
+	column := func(key [2]int) *candidate {
+		c := byColumn[key]
+		if c == nil {
+			dx, dz := key[0]-meshLimitCenter[0], key[1]-meshLimitCenter[1]
+			c = &candidate{key: key, distSq: dx*dx + dz*dz}
+			byColumn[key] = c
+		}
+		return c
+	}
+	atlasBytes := 0
+	for key, col := range columnMeshes {
+		if col != nil && col.firstFloat >= 0 && col.vertexCount > 0 {
+			column(key)
+			atlasBytes += int(col.vertexCount) * 12
+		}
+	}
+	for coord, m := range chunkMeshes {
+		if m != nil && chunkMeshBytes(m) > 0 {
+			c := column([2]int{coord.X, coord.Z})
+			c.chunks = append(c.chunks, coord)
+		}
+	}
+	candidates := make([]*candidate, 0, len(byColumn))
+	for _, c := range byColumn {
+		candidates = append(candidates, c)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].distSq > candidates[j].distSq
+	})
+
+	keepCopies = keepCopies && atlasBytes >= neededBytes
+	dirtyRegions := map[atlasKey]*atlasRegion{}
+	freed := 0
+	for _, cand := range candidates {
+		if freed >= neededBytes {
+			break
+		}
+		if col := columnMeshes[cand.key]; col != nil && col.firstFloat >= 0 && col.vertexCount > 0 {
+			if r := atlasRegions[col.regionKey]; r != nil {
+				freed += int(col.vertexCount) * 12
+				freeInRegion(r, col.firstFloat, int(col.vertexCount)*6)
+				r.activeColumns--
+				dirtyRegions[r.key] = r
+			}
+			col.vertexCount = 0
+			col.firstFloat = -1
+			col.firstVertex = -1
+			col.dirty = true
+		}
+		if !keepCopies {
+			for _, coord := range cand.chunks {
+				if m := chunkMeshes[coord]; m != nil {
+					freed += chunkMeshBytes(m)
+					trackMeshStats(m, -1)
+					delete(chunkMeshes, coord)
+				}
+			}
+		}
+		meshLimitSq = max(cand.distSq, 1)
+	}
+	for _, r := range dirtyRegions {
+		compactRegion(r)
+	}
+	return freed
+}
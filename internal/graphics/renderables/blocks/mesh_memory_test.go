@@ -0,0 +1,57 @@
+package blocks
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+)
+
+// setupMeshMemoryScene caches a CPU-only mesh of 1 KB for every chunk column
+// within radius of the origin. Nothing is in the atlas, so eviction makes no
+// GL calls.
+func setupMeshMemoryScene(radius int) {
+	chunkMeshes = make(map[world.ChunkCoord]*chunkMesh)
+	columnMeshes = make(map[[2]int]*columnMesh)
+	atlasRegions = make(map[atlasKey]*atlasRegion)
+	cpuMeshBytes, meshLimitSq, meshLimitCenter = 0, 0, [2]int{}
+	for x := -radius; x <= radius; x++ {
+		for z := -radius; z <= radius; z++ {
+			m := &chunkMesh{vertexCount: 256, cpuVerts: make([]uint32, 256), firstFloat: -1, firstVertex: -1}
+			chunkMeshes[world.ChunkCoord{X: x, Z: z}] = m
+			trackMeshStats(m, 1)
+		}
+	}
+}
+
+func TestEvictFarthestMeshesDropsTheOuterRing(t *testing.T) {
+	setupMeshMemoryScene(3)
+	before := cpuMeshBytes
+
+	// The four corners of the square are the farthest columns
+	freed := evictFarthestMeshes(4*1024, false)
+
+	if freed != 4*1024 || cpuMeshBytes != before-freed {
+		t.Fatalf("freed %d bytes, cpu %d -> %d", freed, before, cpuMeshBytes)
+	}
+	for _, c := range [][2]int{{3, 3}, {-3, 3}, {3, -3}, {-3, -3}} {
+		if chunkMeshes[world.ChunkCoord{X: c[0], Z: c[1]}] != nil {
+			t.Errorf("corner %v kept its mesh", c)
+		}
+	}
+	if chunkMeshes[world.ChunkCoord{X: 3, Z: 2}] == nil {
+		t.Error("mesh next to the corner evicted")
+	}
+	if !beyondMeshLimit(3, 3) || beyondMeshLimit(3, 2) {
+		t.Errorf("limit radius² %d does not sit at the evicted corners", meshLimitSq)
+	}
+}
+
+func TestEvictFarthestMeshesKeepsCopiesOnlyIfTheAtlasSuffices(t *testing.T) {
+	setupMeshMemoryScene(2)
+	before := cpuMeshBytes
+
+	// Nothing is in the atlas, so the copies have to go
+	if freed := evictFarthestMeshes(1024, true); freed != 1024 || cpuMeshBytes != before-1024 {
+		t.Errorf("freed %d bytes, cpu %d -> %d", freed, before, cpuMeshBytes)
+	}
+}
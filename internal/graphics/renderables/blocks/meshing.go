@@ -2,8 +2,10 @@ package blocks
 
 import (
 	"mini-mc/internal/meshing"
+	"mini-mc/internal/profiling"
 	"mini-mc/internal/world"
 	"sync"
+	"time"
 )
 
 // Chunk meshes cache per chunk
@@ -19,15 +21,66 @@ var meshPool *meshing.WorkerPool
 var pendingMeshJobs map[world.ChunkCoord]chan meshing.MeshResult
 var pendingMeshMutex sync.RWMutex
 
+// When each pending job was submitted, and how long the applied ones took
+var meshSubmitted map[world.ChunkCoord]time.Time
+var meshLatencies profiling.Latencies
+
 // Results channel for completed mesh jobs
 var meshResultsChannel = make(chan meshing.MeshResult, 100)
 
+// Culling counters for the profiling overlay, kept in sync with chunkMeshes
+var (
+	enclosedSectionCount int // sections skipped as fully enclosed across cached meshes
+	emptyChunkMeshCount  int // cached chunk meshes with no geometry (never take atlas space)
+)
+
+// CullingStats returns how many enclosed sections were left out of the cached
+// chunk meshes and how many cached chunk meshes are empty.
+func CullingStats() (enclosedSections, emptyChunks int) {
+	return enclosedSectionCount, emptyChunkMeshCount
+}
+
+// trackMeshStats adds (sign=1) or removes (sign=-1) a mesh from the culling
+// counters and the CPU mesh memory count.
+func trackMeshStats(m *chunkMesh, sign int) {
+	cpuMeshBytes += sign * chunkMeshBytes(m)
+	enclosedSectionCount += sign * m.enclosedSections
+	if m.vertexCount == 0 && len(m.fluidVerts) == 0 && len(m.translucentVerts) == 0 {
+		emptyChunkMeshCount += sign
+	}
+}
+
+// MeshLatencies returns how long chunk meshes took from being queued to
+// being uploaded.
+func MeshLatencies() *profiling.Latencies {
+	return &meshLatencies
+}
+
+// markMeshPending records that a mesh job for coord was submitted.
+func markMeshPending(coord world.ChunkCoord) {
+	pendingMeshMutex.Lock()
+	pendingMeshJobs[coord] = meshResultsChannel
+	meshSubmitted[coord] = time.Now()
+	pendingMeshMutex.Unlock()
+}
+
 // InitMeshSystem initializes the mesh worker pool and data structures
 func InitMeshSystem(workers int) {
 	meshPool = meshing.NewWorkerPool(workers, 200) // 200 job queue size
 	chunkMeshes = make(map[world.ChunkCoord]*chunkMesh)
 	columnMeshes = make(map[[2]int]*columnMesh)
+	enclosedSectionCount, emptyChunkMeshCount = 0, 0
+	cpuMeshBytes, meshLimitSq = 0, 0
 	pendingMeshJobs = make(map[world.ChunkCoord]chan meshing.MeshResult)
+	meshSubmitted = make(map[world.ChunkCoord]time.Time)
+	lightRemeshes = meshing.NewRemeshQueue()
+}
+
+// PendingMeshJobs returns how many chunk meshes are still being built.
+func PendingMeshJobs() int {
+	pendingMeshMutex.RLock()
+	defer pendingMeshMutex.RUnlock()
+	return len(pendingMeshJobs)
 }
 
 // ShutdownMeshSystem gracefully shuts down the mesh worker pool
@@ -58,11 +111,16 @@ func applyMeshResult(result meshing.MeshResult) {
 	// Remove from pending jobs
 	pendingMeshMutex.Lock()
 	delete(pendingMeshJobs, coord)
+	submitted, timed := meshSubmitted[coord]
+	delete(meshSubmitted, coord)
 	pendingMeshMutex.Unlock()
 
 	if result.Error != nil {
 		return // Skip on error
 	}
+	if timed {
+		meshLatencies.Add(time.Since(submitted))
+	}
 
 	// Only mark the chunk clean if its generation hasn't advanced since the job
 	// was submitted. If the generation differs, the chunk was modified while the
@@ -78,21 +136,30 @@ func applyMeshResult(result meshing.MeshResult) {
 			firstFloat:  -1,
 			firstVertex: -1,
 		}
+	} else {
+		trackMeshStats(existing, -1)
 	}
 
 	verts := result.Vertices
 	fluidVerts := result.FluidVertices
-	if len(verts) > 0 || len(fluidVerts) > 0 {
+	translucentVerts := result.Translucent
+	if len(verts) > 0 || len(fluidVerts) > 0 || len(translucentVerts) > 0 {
 		// Vertex count is just length of packed array (one uint32 per vertex)
 		existing.vertexCount = int32(len(verts))
 		// Keep CPU copy for column meshing
 		existing.cpuVerts = verts
 		existing.fluidVerts = fluidVerts
+		existing.translucentVerts = translucentVerts
 	} else {
 		existing.vertexCount = 0
 		existing.cpuVerts = nil
 		existing.fluidVerts = nil
+		existing.translucentVerts = nil
 	}
+	existing.enclosedSections = result.EnclosedSections
+	existing.lod = result.LOD
+	existing.lodBuilt = result.LODBuilt
+	trackMeshStats(existing, 1)
 	// Mark the column as dirty in all cases: even when transitioning from a full chunk to an empty one
 	// ensureColumnMeshForXZ should free the atlas slot and shrink the column.
 	if col := columnMeshes[[2]int{coord.X, coord.Z}]; col != nil {
@@ -101,6 +168,21 @@ func applyMeshResult(result meshing.MeshResult) {
 	chunkMeshes[coord] = existing
 }
 
+// newMeshJob returns the job meshing ch at coord from a snapshot of it and
+// its neighbours taken now, so the mesh worker never reads a chunk while it
+// is being edited. Jobs nearer the player run first.
+func newMeshJob(w *world.World, coord world.ChunkCoord, ch *world.Chunk) meshing.MeshJob {
+	return meshing.MeshJob{
+		World:           w.SnapshotAround(ch),
+		Chunk:           ch,
+		Coord:           coord,
+		ResultChan:      meshResultsChannel,
+		ChunkGeneration: ch.Generation(),
+		LOD:             wantLODMeshes(coord),
+		Priority:        lodDistanceTo(coord.X, coord.Z),
+	}
+}
+
 func ensureChunkMesh(w *world.World, coord world.ChunkCoord, ch *world.Chunk) *chunkMesh {
 	if ch == nil {
 		return nil
@@ -108,8 +190,10 @@ func ensureChunkMesh(w *world.World, coord world.ChunkCoord, ch *world.Chunk) *c
 
 	existing := chunkMeshes[coord]
 
-	// Return existing mesh if present and chunk is clean
-	if existing != nil && !ch.IsDirty() {
+	// Return existing mesh if present, chunk is clean and the LOD meshes
+	// its distance calls for are there
+	needsLOD := missingLODMeshes(existing, coord)
+	if existing != nil && !ch.IsDirty() && !needsLOD {
 		return existing
 	}
 
@@ -119,20 +203,14 @@ func ensureChunkMesh(w *world.World, coord world.ChunkCoord, ch *world.Chunk) *c
 	pendingMeshMutex.RUnlock()
 
 	// If chunk is dirty or has no mesh and no job is pending, submit a new mesh job
-	if (ch.IsDirty() || existing == nil) && !hasPendingJob && meshPool != nil {
-		job := meshing.MeshJob{
-			World:           w,
-			Chunk:           ch,
-			Coord:           coord,
-			ResultChan:      meshResultsChannel,
-			ChunkGeneration: ch.Generation(),
-		}
+	if (ch.IsDirty() || existing == nil || needsLOD) && !hasPendingJob && meshPool != nil {
+		job := newMeshJob(w, coord, ch)
 
 		// Chunks that already have a mesh are being updated (e.g. player broke a
 		// block). Submit them to the priority queue so they aren't delayed behind
 		// the initial-load backlog in the normal queue.
 		submitted := false
-		if existing != nil {
+		if existing != nil && ch.IsDirty() {
 			submitted = meshPool.SubmitPriorityJob(job)
 		}
 		if !submitted {
@@ -140,9 +218,7 @@ func ensureChunkMesh(w *world.World, coord world.ChunkCoord, ch *world.Chunk) *c
 		}
 
 		if submitted {
-			pendingMeshMutex.Lock()
-			pendingMeshJobs[coord] = meshResultsChannel
-			pendingMeshMutex.Unlock()
+			markMeshPending(coord)
 		}
 	}
 
@@ -150,13 +226,32 @@ func ensureChunkMesh(w *world.World, coord world.ChunkCoord, ch *world.Chunk) *c
 	return existing
 }
 
+// prepareScratch is reused by PrepareArea between frames
+var prepareScratch []world.ChunkWithCoord
+
+// PrepareArea queues meshes for the loaded chunks within radius of chunk
+// column (cx, cz), wherever the player is, and reports whether all of them
+// are built and up to date. Call it from the main thread each frame until it
+// returns true, e.g. to mesh a teleport destination before arriving.
+func PrepareArea(w *world.World, cx, cz, radius int) bool {
+	prepareScratch = w.AppendChunksInRadiusXZ(cx, cz, radius, prepareScratch[:0])
+	ready := true
+	for _, cc := range prepareScratch {
+		if ensureChunkMesh(w, cc.Coord, cc.Chunk) == nil || cc.Chunk.IsDirty() {
+			ready = false
+		}
+	}
+	return ready
+}
+
 // PruneMeshesByWorld removes cached meshes that are not in the world anymore or beyond a radius from center.
+// The radius applies horizontally and, against the chunk's occupied sections, vertically.
 // Returns number of meshes freed.
-func PruneMeshesByWorld(w *world.World, centerX, centerZ float32, radiusChunks int) int {
-	retain := make(map[world.ChunkCoord]struct{})
+func PruneMeshesByWorld(w *world.World, centerX, centerY, centerZ float32, radiusChunks int) int {
+	retain := make(map[world.ChunkCoord]*world.Chunk)
 	all := w.GetAllChunks()
 	for _, cc := range all {
-		retain[cc.Coord] = struct{}{}
+		retain[cc.Coord] = cc.Chunk
 	}
 	cx := int(centerX) / world.ChunkSizeX
 	cz := int(centerZ) / world.ChunkSizeZ
@@ -164,13 +259,16 @@ func PruneMeshesByWorld(w *world.World, centerX, centerZ float32, radiusChunks i
 	freed := 0
 	for coord, m := range chunkMeshes {
 		// Keep if present and within radius
-		_, present := retain[coord]
+		ch, present := retain[coord]
 		dx := coord.X - cx
 		dz := coord.Z - cz
-		if !present || dx*dx+dz*dz > radiusChunks*radiusChunks {
+		if !present || dx*dx+dz*dz > radiusChunks*radiusChunks || !withinVerticalRadius(coord, ch, centerY, radiusChunks) {
 			if m != nil {
+				trackMeshStats(m, -1)
 				m.cpuVerts = nil
 				m.fluidVerts = nil
+				m.translucentVerts = nil
+				m.lod = nil
 			}
 			delete(chunkMeshes, coord)
 			colKey := [2]int{coord.X, coord.Z}
@@ -204,7 +302,8 @@ func PruneMeshesByWorld(w *world.World, centerX, centerZ float32, radiusChunks i
 			col.firstVertex = -1
 			col.dirty = true
 			// We remove it from the map so it can be GC'd.
-			// The reference in atlasRegion.orderedColumns will be dropped during the next compaction
+			// The reference in atlasRegion.orderedColumns is dropped at the next
+			// compaction or prune (see pruneOrderedColumns)
 			delete(columnMeshes, key)
 		}
 	}
@@ -0,0 +1,276 @@
+package blocks
+
+import (
+	"math"
+	"path/filepath"
+
+	"mini-mc/internal/graphics"
+	"mini-mc/internal/graphics/renderer"
+	"mini-mc/internal/mathutil"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Occlusion culling (toggled with config.ToggleOcclusionCulling).
+//
+// Once the opaque columns are drawn, the box of every column that passed the
+// frustum test is drawn again with colour and depth writes off, each inside a
+// GL_ANY_SAMPLES_PASSED query. Results are only read once GL reports them
+// available, a frame or more later, so the CPU never waits on the GPU. A
+// column whose last result passed no samples is behind nearer terrain and is
+// left out of the draw lists; its box is still tested every frame, so it is
+// drawn again a frame or two after it comes into sight.
+//
+// The columns around the camera are never occluded, since the camera may be
+// inside their box. Results only hold for the player's view: the water
+// reflection and offscreen views draw every column in their frustum.
+
+var (
+	OcclusionVertShader = filepath.Join(ShadersDir, "occlusion.vert")
+	OcclusionFragShader = filepath.Join(ShadersDir, "occlusion.frag")
+)
+
+const (
+	// occlusionNearChunks is how many chunks around the camera's column are
+	// never occluded
+	occlusionNearChunks = 1
+	// occlusionBoxMargin grows the tested boxes, in blocks, so the column's
+	// own faces on its box never hide it
+	occlusionBoxMargin = 0.5
+	// occlusionSweepFrames is how often, and after how many frames untested,
+	// the queries of columns no longer drawn are recycled
+	occlusionSweepFrames = 120
+)
+
+// Counters for the profiling overlay, from the last occlusion pass
+var (
+	occlusionTestedCount   int // columns in the frustum that could be occluded
+	occlusionOccludedCount int // of those, columns left out of the frame
+)
+
+// OcclusionStats returns how many columns in the frustum were tested for
+// occlusion in the last frame and how many of them were skipped as hidden.
+func OcclusionStats() (tested, occluded int) {
+	return occlusionTestedCount, occlusionOccludedCount
+}
+
+// occlusionQuery is the query object of one column.
+type occlusionQuery struct {
+	id        uint32
+	pending   bool   // issued and its result not read yet
+	lastFrame uint64 // last frame the column was tested
+}
+
+// poll reads q's result if GL has it, reporting whether any sample passed.
+func (q *occlusionQuery) poll() (passed, ready bool) {
+	var available uint32
+	gl.GetQueryObjectuiv(q.id, gl.QUERY_RESULT_AVAILABLE, &available)
+	if available == 0 {
+		return false, false
+	}
+	var samples uint32
+	gl.GetQueryObjectuiv(q.id, gl.QUERY_RESULT, &samples)
+	q.pending = false
+	return samples != 0, true
+}
+
+// occlusionCuller issues and reads back the occlusion queries of the columns.
+type occlusionCuller struct {
+	shader   *graphics.Shader
+	vao, vbo uint32
+	queries  map[*columnMesh]*occlusionQuery
+	free     []uint32 // query objects recycled from columns out of view
+	ranFrame uint64   // frame of the last pass, 0 before the first
+}
+
+func (o *occlusionCuller) init() error {
+	var err error
+	o.shader, err = graphics.NewShader(OcclusionVertShader, OcclusionFragShader)
+	if err != nil {
+		return err
+	}
+	o.queries = make(map[*columnMesh]*occlusionQuery)
+
+	verts := unitCubeTriangles()
+	gl.GenVertexArrays(1, &o.vao)
+	gl.GenBuffers(1, &o.vbo)
+	gl.BindVertexArray(o.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, o.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(verts)*4, gl.Ptr(verts), gl.STATIC_DRAW)
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 3*4, gl.PtrOffset(0))
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	return nil
+}
+
+func (o *occlusionCuller) dispose() {
+	for _, q := range o.queries {
+		o.free = append(o.free, q.id)
+	}
+	if len(o.free) > 0 {
+		gl.DeleteQueries(int32(len(o.free)), &o.free[0])
+	}
+	o.free, o.queries = nil, nil
+	if o.vao != 0 {
+		gl.DeleteVertexArrays(1, &o.vao)
+	}
+	if o.vbo != 0 {
+		gl.DeleteBuffers(1, &o.vbo)
+	}
+}
+
+// trusted reports whether the results on the columns can be used to skip
+// them in frame: occlusion is enabled and the pass ran the frame before, so
+// the results are for the view being drawn and not left from an old one.
+func (o *occlusionCuller) trusted(enabled bool, frame uint64) bool {
+	return enabled && o.ranFrame != 0 && o.ranFrame+1 == frame
+}
+
+// run tests the columns stamped visible in frame against the depth drawn so
+// far and reads back the results of earlier frames that are ready.
+func (o *occlusionCuller) run(ctx renderer.RenderContext, frame uint64) {
+	eye := ctx.Snapshot.Eye
+	ecx := mathutil.FloorDiv(int(math.Floor(float64(eye.X()))), world.ChunkSizeX)
+	ecz := mathutil.FloorDiv(int(math.Floor(float64(eye.Z()))), world.ChunkSizeZ)
+
+	o.shader.Use()
+	o.shader.SetMatrix4("proj", &ctx.Proj[0])
+	o.shader.SetMatrix4("view", &ctx.View[0])
+	gl.ColorMask(false, false, false, false)
+	gl.DepthMask(false)
+	gl.BindVertexArray(o.vao)
+
+	tested, occluded := 0, 0
+	for _, r := range atlasRegions {
+		if r == nil {
+			continue
+		}
+		for _, c := range r.orderedColumns {
+			if c == nil {
+				continue
+			}
+			q := o.queries[c]
+			if c.visibleFrame != frame || c.dirty || c.vertexCount <= 0 {
+				// Out of view: forget the result, reading any in flight so
+				// sweep can recycle the query
+				c.occluded = false
+				if q != nil && q.pending {
+					q.poll()
+				}
+				continue
+			}
+			tested++
+			if mathutil.Abs(c.x-ecx) <= occlusionNearChunks && mathutil.Abs(c.z-ecz) <= occlusionNearChunks {
+				c.occluded = false
+				continue
+			}
+			if q == nil {
+				q = o.newQuery()
+				o.queries[c] = q
+			}
+			q.lastFrame = frame
+			if q.pending {
+				passed, ready := q.poll()
+				if !ready {
+					// Still in flight: keep the last result
+					if c.occluded {
+						occluded++
+					}
+					continue
+				}
+				c.occluded = !passed
+			}
+			if c.occluded {
+				occluded++
+			}
+			o.issue(q, c)
+		}
+	}
+
+	gl.BindVertexArray(0)
+	gl.DepthMask(true)
+	gl.ColorMask(true, true, true, true)
+	glCheckError("occlusion queries")
+
+	if frame%occlusionSweepFrames == 0 {
+		o.sweep(frame)
+	}
+	o.ranFrame = frame
+	occlusionTestedCount, occlusionOccludedCount = tested, occluded
+}
+
+// issue draws c's box inside q's query.
+func (o *occlusionCuller) issue(q *occlusionQuery, c *columnMesh) {
+	minY, maxY := c.yBounds()
+	minX := float32(c.x*world.ChunkSizeX) - occlusionBoxMargin
+	minZ := float32(c.z*world.ChunkSizeZ) - occlusionBoxMargin
+	o.shader.SetVector3("boxMin", minX, minY-occlusionBoxMargin, minZ)
+	o.shader.SetVector3("boxMax",
+		minX+world.ChunkSizeX+2*occlusionBoxMargin,
+		maxY+occlusionBoxMargin,
+		minZ+world.ChunkSizeZ+2*occlusionBoxMargin)
+	gl.BeginQuery(gl.ANY_SAMPLES_PASSED, q.id)
+	gl.DrawArrays(gl.TRIANGLES, 0, 36)
+	terrainDrawCalls++
+	gl.EndQuery(gl.ANY_SAMPLES_PASSED)
+	q.pending = true
+}
+
+func (o *occlusionCuller) newQuery() *occlusionQuery {
+	q := &occlusionQuery{}
+	if n := len(o.free); n > 0 {
+		q.id = o.free[n-1]
+		o.free = o.free[:n-1]
+	} else {
+		gl.GenQueries(1, &q.id)
+	}
+	return q
+}
+
+// sweep recycles the queries of columns untested for occlusionSweepFrames,
+// including those of columns dropped from the atlas, once their results are
+// read.
+func (o *occlusionCuller) sweep(frame uint64) {
+	for c, q := range o.queries {
+		if frame-q.lastFrame < occlusionSweepFrames {
+			continue
+		}
+		if q.pending {
+			if _, ready := q.poll(); !ready {
+				continue
+			}
+		}
+		o.free = append(o.free, q.id)
+		delete(o.queries, c)
+	}
+}
+
+// unitCubeTriangles returns the 36 vertices of the triangles of the cube from
+// (0,0,0) to (1,1,1).
+func unitCubeTriangles() []float32 {
+	// Each face as a corner and the two edges spanning it
+	faces := [6][3][3]float32{
+		{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}, // -Z
+		{{0, 0, 1}, {1, 0, 0}, {0, 1, 0}}, // +Z
+		{{0, 0, 0}, {0, 0, 1}, {0, 1, 0}}, // -X
+		{{1, 0, 0}, {0, 0, 1}, {0, 1, 0}}, // +X
+		{{0, 0, 0}, {1, 0, 0}, {0, 0, 1}}, // -Y
+		{{0, 1, 0}, {1, 0, 0}, {0, 0, 1}}, // +Y
+	}
+	verts := make([]float32, 0, 36*3)
+	for _, f := range faces {
+		o, u, v := f[0], f[1], f[2]
+		corner := func(a, b float32) {
+			verts = append(verts, o[0]+a*u[0]+b*v[0], o[1]+a*u[1]+b*v[1], o[2]+a*u[2]+b*v[2])
+		}
+		corner(0, 0)
+		corner(1, 0)
+		corner(1, 1)
+		corner(0, 0)
+		corner(1, 1)
+		corner(0, 1)
+	}
+	return verts
+}
@@ -0,0 +1,71 @@
+package blocks
+
+import "testing"
+
+func TestOccludedColumnsLeftOutOfDrawLists(t *testing.T) {
+	nearby := setupCullScene(cullBenchRadius)
+	planes := cullBenchPlanes()
+	df, dc := defaultDrawLists(nearby, planes)
+	all := len(drawnColumns(df, dc))
+
+	hidden := 0
+	for _, col := range columnMeshes {
+		if col.visibleFrame == currentFrame && col.x > 3 {
+			col.occluded = true
+			hidden++
+		}
+	}
+	if hidden == 0 {
+		t.Fatal("no visible column to occlude")
+	}
+
+	// Without occlusion the results are ignored
+	if df, dc := defaultDrawLists(nearby, planes); len(drawnColumns(df, dc)) != all {
+		t.Errorf("default path drew %d columns with occlusion off, want %d", len(drawnColumns(df, dc)), all)
+	}
+
+	visible := appendFrustumVisible(nil, nearby, planes, 80, cullBenchRadius)
+	markVisibleColumns(visible)
+	var firsts, counts []int32
+	for _, r := range atlasRegions {
+		firsts, counts = appendVisibleColumnDraws(r, currentFrame, true, firsts, counts)
+	}
+	if got := len(drawnColumns(firsts, counts)); got != all-hidden {
+		t.Errorf("default path drew %d columns, want %d of %d", got, all-hidden, all)
+	}
+
+	currentFrame++
+	firsts, counts = firsts[:0], counts[:0]
+	for _, r := range atlasRegions {
+		refreshPackedColumns(r)
+		firsts, counts = cullPackedColumns(&r.packed, planes, 0, 0, cullBenchRadius, currentFrame, true, firsts, counts)
+	}
+	if got := len(drawnColumns(firsts, counts)); got != all-hidden {
+		t.Errorf("packed path drew %d columns, want %d of %d", got, all-hidden, all)
+	}
+	// Occluded columns still count as visible, so they keep being tested and
+	// are not evicted as unused
+	for _, col := range columnMeshes {
+		if col.occluded && (col.visibleFrame != currentFrame || col.drawnFrame != currentFrame) {
+			t.Fatalf("occluded column %d,%d not stamped with the frame", col.x, col.z)
+		}
+	}
+}
+
+func TestOcclusionResultsTrustedOnlyFromLastFrame(t *testing.T) {
+	var o occlusionCuller
+	if o.trusted(true, 1) {
+		t.Error("trusted before any pass")
+	}
+	o.ranFrame = 5
+	if !o.trusted(true, 6) {
+		t.Error("results of the last frame not trusted")
+	}
+	if o.trusted(false, 6) {
+		t.Error("trusted with occlusion off")
+	}
+	// An offscreen view drawn in between takes a frame
+	if o.trusted(true, 7) {
+		t.Error("results of two frames ago trusted")
+	}
+}
@@ -0,0 +1,226 @@
+package blocks
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+
+	"mini-mc/internal/config"
+	"mini-mc/internal/graphics"
+	"mini-mc/internal/graphics/renderer"
+	"mini-mc/internal/mathutil"
+	"mini-mc/internal/profiling"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Sun shadows (config.GetShadowQuality). Before the opaque world is drawn,
+// the block columns around the eye are drawn again as seen from the sun, or
+// the moon by night, into a depth texture array with one layer per cascade.
+// Cascade i covers a sphere of shadowCascadeRadii[i] blocks around the eye,
+// so nearby shadows get the most texels. main.frag picks the smallest
+// cascade holding a fragment and filters its lookup with PCF.
+
+const (
+	maxShadowCascades = 3
+	shadowCasterReach = 96 // blocks towards the light beyond a cascade whose blocks still cast into it
+	shadowTextureUnit = 4  // after the fluid shader's water textures
+	shadowFadeHeight  = 0.2
+	moonShadowLight   = 0.5 // strength of the moon's shadows against the sun's
+)
+
+var shadowCascadeRadii = [maxShadowCascades]float32{16, 48, 128}
+
+// shadowSettings is what each config.ShadowQuality draws.
+type shadowSettings struct {
+	cascades  int
+	mapSize   int // texels along each side of a cascade
+	pcfRadius int // taps either side of the centre when filtering
+}
+
+var shadowQualities = [...]shadowSettings{
+	config.ShadowsOff:  {},
+	config.ShadowsLow:  {cascades: 2, mapSize: 1024, pcfRadius: 1},
+	config.ShadowsHigh: {cascades: 3, mapSize: 2048, pcfRadius: 2},
+}
+
+// shadowMaps holds the shadow pass's target and this frame's cascades.
+type shadowMaps struct {
+	shader   *graphics.Shader
+	fbo      uint32
+	depthTex uint32 // depth texture array, a layer per cascade
+	size     int    // of depthTex
+	layers   int
+
+	settings shadowSettings // cascades is 0 while no shadows are drawn
+	matrices [maxShadowCascades]mgl32.Mat4
+	light    mgl32.Vec3 // towards the light
+	strength float32
+}
+
+func (sm *shadowMaps) init() error {
+	var err error
+	sm.shader, err = graphics.NewShader(ShadowVertShader, ShadowFragShader)
+	return err
+}
+
+// shadowLight returns the direction towards the light casting shadows with
+// the sun in direction sun, and how strong its shadows are: the sun's by
+// day and the moon's, fainter, by night. Both fade as their light nears the
+// horizon, where shadows would stretch without end.
+func shadowLight(sun mgl32.Vec3) (mgl32.Vec3, float32) {
+	light, strength := sun, float32(1)
+	if sun.Y() < 0 {
+		light, strength = sun.Mul(-1), moonShadowLight
+	}
+	return light, strength * mathutil.Clamp(light.Y()/shadowFadeHeight, 0, 1)
+}
+
+// cascadeMatrix returns the light's view-projection of a cascade covering a
+// sphere of radius blocks about centre, drawn into mapSize texels square.
+// The centre is snapped to whole texels so shadow edges don't crawl as the
+// eye moves.
+func cascadeMatrix(centre, light mgl32.Vec3, radius float32, mapSize int) mgl32.Mat4 {
+	up := mgl32.Vec3{0, 0, 1}
+	if mathutil.Abs(light.Z()) > 0.99 {
+		up = mgl32.Vec3{1, 0, 0}
+	}
+	view := mgl32.LookAtV(mgl32.Vec3{}, light.Mul(-1), up)
+	c := view.Mul4x1(centre.Vec4(1))
+	texel := 2 * radius / float32(mapSize)
+	cx := float32(math.Floor(float64(c.X()/texel))) * texel
+	cy := float32(math.Floor(float64(c.Y()/texel))) * texel
+	// Looking along -light, distance from the light's plane through the
+	// origin is -z; casters up to shadowCasterReach nearer the light count
+	proj := mgl32.Ortho(cx-radius, cx+radius, cy-radius, cy+radius, -c.Z()-radius-shadowCasterReach, -c.Z()+radius)
+	return proj.Mul4(view)
+}
+
+// ensureTargets creates the depth texture array and its framebuffer on first
+// use and resizes them to layers of size×size.
+func (sm *shadowMaps) ensureTargets(size, layers int) {
+	if sm.fbo != 0 && size == sm.size && layers == sm.layers {
+		return
+	}
+	if sm.fbo == 0 {
+		gl.GenFramebuffers(1, &sm.fbo)
+		gl.GenTextures(1, &sm.depthTex)
+	}
+	sm.size, sm.layers = size, layers
+
+	gl.BindTexture(gl.TEXTURE_2D_ARRAY, sm.depthTex)
+	gl.TexImage3D(gl.TEXTURE_2D_ARRAY, 0, gl.DEPTH_COMPONENT24, int32(size), int32(size), int32(layers), 0, gl.DEPTH_COMPONENT, gl.FLOAT, nil)
+	// Linear filtering with comparison gives each PCF tap a bilinear blend
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_COMPARE_MODE, gl.COMPARE_REF_TO_TEXTURE)
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_COMPARE_FUNC, gl.LEQUAL)
+	// Beyond the map everything is lit
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_BORDER)
+	gl.TexParameteri(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_BORDER)
+	border := [4]float32{1, 1, 1, 1}
+	gl.TexParameterfv(gl.TEXTURE_2D_ARRAY, gl.TEXTURE_BORDER_COLOR, &border[0])
+	gl.BindTexture(gl.TEXTURE_2D_ARRAY, 0)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, sm.fbo)
+	gl.FramebufferTextureLayer(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, sm.depthTex, 0, 0)
+	gl.DrawBuffer(gl.NONE)
+	gl.ReadBuffer(gl.NONE)
+	if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		slog.Error("shadow framebuffer incomplete", "status", status)
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+}
+
+// dispose frees the shadow target.
+func (sm *shadowMaps) dispose() {
+	if sm.fbo != 0 {
+		gl.DeleteFramebuffers(1, &sm.fbo)
+		gl.DeleteTextures(1, &sm.depthTex)
+	}
+	sm.fbo, sm.depthTex, sm.size, sm.layers = 0, 0, 0, 0
+	sm.settings = shadowSettings{}
+}
+
+// render draws the cascades around eye for this frame, or records that
+// there are none when shadows are off or the light is too low.
+func (sm *shadowMaps) render(ctx renderer.RenderContext, eye mgl32.Vec3) {
+	settings := shadowQualities[config.GetShadowQuality()]
+	light, strength := shadowLight(ctx.World.SunDirection())
+	if settings.cascades == 0 || strength <= 0 {
+		sm.settings = shadowSettings{}
+		return
+	}
+	defer profiling.Track("renderer.renderBlocks.shadows")()
+
+	var viewport [4]int32
+	var target int32 // the framebuffer being drawn, the window's or a capture's
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	gl.GetIntegerv(gl.DRAW_FRAMEBUFFER_BINDING, &target)
+	sm.ensureTargets(settings.mapSize, settings.cascades)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, sm.fbo)
+	gl.Viewport(0, 0, int32(settings.mapSize), int32(settings.mapSize))
+	gl.Disable(gl.CULL_FACE)
+	gl.Enable(gl.POLYGON_OFFSET_FILL)
+	gl.PolygonOffset(1.5, 4)
+
+	sm.shader.Use()
+	if GlobalTextureAtlas != nil {
+		gl.ActiveTexture(gl.TEXTURE0)
+		gl.BindTexture(gl.TEXTURE_2D_ARRAY, GlobalTextureAtlas.TextureID)
+	}
+	sm.shader.SetInt("textureArray", 0)
+	pcx := int(math.Floor(float64(eye.X()))) / world.ChunkSizeX
+	pcz := int(math.Floor(float64(eye.Z()))) / world.ChunkSizeZ
+	for i := range settings.cascades {
+		radius := shadowCascadeRadii[i]
+		m := cascadeMatrix(eye, light, radius, settings.mapSize)
+		sm.matrices[i] = m
+		gl.FramebufferTextureLayer(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, sm.depthTex, 0, int32(i))
+		gl.Clear(gl.DEPTH_BUFFER_BIT)
+		sm.shader.SetMatrix4("lightViewProj", &m[0])
+
+		planes := mathutil.FrustumFromMatrix(m)
+		radiusChunks := int(radius+shadowCasterReach)/world.ChunkSizeX + 1
+		for _, r := range atlasRegions {
+			if r == nil || len(r.orderedColumns) == 0 {
+				continue
+			}
+			refreshPackedColumns(r)
+			firstsScratch, countsScratch = cullPackedColumns(&r.packed, planes, pcx, pcz, radiusChunks, currentFrame, false, firstsScratch[:0], countsScratch[:0])
+			drawRegion(r, firstsScratch, countsScratch)
+		}
+	}
+
+	gl.Disable(gl.POLYGON_OFFSET_FILL)
+	gl.Enable(gl.CULL_FACE)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(target))
+	gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+	sm.settings, sm.light, sm.strength = settings, light, strength
+}
+
+// setUniforms binds the cascades to shadowTextureUnit and tells shader, the
+// main block shader, how to sample them.
+func (sm *shadowMaps) setUniforms(shader *graphics.Shader) {
+	// The sampler keeps its own unit even with shadows off: two samplers of
+	// different types on one unit fail every draw
+	shader.SetInt("shadowMap", shadowTextureUnit)
+	shader.SetInt("shadowCascades", int32(sm.settings.cascades))
+	if sm.settings.cascades == 0 {
+		return
+	}
+	gl.ActiveTexture(gl.TEXTURE0 + shadowTextureUnit)
+	gl.BindTexture(gl.TEXTURE_2D_ARRAY, sm.depthTex)
+	gl.ActiveTexture(gl.TEXTURE0)
+	for i := range sm.settings.cascades {
+		shader.SetMatrix4(fmt.Sprintf("shadowMatrices[%d]", i), &sm.matrices[i][0])
+		shader.SetFloat(fmt.Sprintf("shadowRadii[%d]", i), shadowCascadeRadii[i])
+	}
+	shader.SetFloat("shadowTexel", 1/float32(sm.size))
+	shader.SetInt("pcfRadius", int32(sm.settings.pcfRadius))
+	shader.SetFloat("shadowStrength", sm.strength)
+	shader.SetVector3("shadowLightDir", sm.light.X(), sm.light.Y(), sm.light.Z())
+}
@@ -0,0 +1,63 @@
+package blocks
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestShadowLight(t *testing.T) {
+	noon := mgl32.Vec3{0, 1, 0}
+	if light, strength := shadowLight(noon); light != noon || strength != 1 {
+		t.Errorf("noon: light %v, strength %v; want the sun at full strength", light, strength)
+	}
+	midnight := mgl32.Vec3{0, -1, 0}
+	if light, strength := shadowLight(midnight); light != noon || strength != moonShadowLight {
+		t.Errorf("midnight: light %v, strength %v; want the moon overhead at %v", light, strength, moonShadowLight)
+	}
+	if _, strength := shadowLight(mgl32.Vec3{1, 0, 0}); strength != 0 {
+		t.Errorf("sun on the horizon casts shadows of strength %v", strength)
+	}
+	low := mgl32.Vec3{1, shadowFadeHeight / 2, 0}.Normalize()
+	if _, strength := shadowLight(low); strength <= 0 || strength >= 1 {
+		t.Errorf("low sun strength %v, want fading", strength)
+	}
+}
+
+func TestCascadeMatrix(t *testing.T) {
+	light := mgl32.Vec3{-1, 2, 0}.Normalize()
+	centre := mgl32.Vec3{103.3, 70.2, -41.7}
+	const radius, size = 16, 1024
+	m := cascadeMatrix(centre, light, radius, size)
+	project := func(p mgl32.Vec3) mgl32.Vec3 { return m.Mul4x1(p.Vec4(1)).Vec3() }
+
+	// The whole sphere about the centre is on the map, within its depth range
+	for _, d := range []mgl32.Vec3{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}, {0, 0, 1}, {0, 0, -1}, light, light.Mul(-1)} {
+		p := project(centre.Add(d.Mul(radius * 0.99)))
+		for i := range 3 {
+			if p[i] < -1 || p[i] > 1 {
+				t.Errorf("point %v from the centre is off the map at %v", d.Mul(radius), p)
+			}
+		}
+	}
+	// Nearer the light means nearer in depth
+	if near, far := project(centre.Add(light)), project(centre); near.Z() >= far.Z() {
+		t.Errorf("depth towards the light %v, at the centre %v", near.Z(), far.Z())
+	}
+	// Casters well above the sphere still land in the depth range
+	if p := project(centre.Add(light.Mul(radius + shadowCasterReach*0.9))); p.Z() < -1 {
+		t.Errorf("caster %v blocks towards the light is clipped at depth %v", radius+shadowCasterReach*0.9, p.Z())
+	}
+
+	// Snapping keeps world points on the same texel grid as the centre moves
+	texelOf := func(m mgl32.Mat4, p mgl32.Vec3) float64 {
+		x := float64(m.Mul4x1(p.Vec4(1)).X()) * size / 2
+		return x - math.Round(x)
+	}
+	moved := cascadeMatrix(centre.Add(mgl32.Vec3{0.37, 0, 0.12}), light, radius, size)
+	origin := mgl32.Vec3{100, 64, -40}
+	if a, b := texelOf(m, origin), texelOf(moved, origin); math.Abs(a-b) > 1e-2 {
+		t.Errorf("a world point sits %v into its texel, then %v after moving", a, b)
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"image"
 	"image/draw"
 	_ "image/png"
-	"log"
+	"log/slog"
 	"mini-mc/internal/registry"
 	"mini-mc/internal/world"
 	"os"
@@ -36,7 +36,7 @@ func InitTextureAtlas() error {
 	width, height := 0, 0
 
 	for _, name := range textureFiles {
-		path := "assets/textures/blocks/" + name
+		path := registry.TexturePath(name)
 		f, err := os.Open(path)
 		if err != nil {
 			return fmt.Errorf("failed to open texture %s: %v", path, err)
@@ -75,7 +75,7 @@ func InitTextureAtlas() error {
 		} else if dx != width || dy != height {
 			// Resize/Resample if mismatch (Nearest Neighbor)
 			// e.g. 32x32 -> 16x16
-			log.Printf("Resizing texture %s from %dx%d to %dx%d", name, dx, dy, width, height)
+			slog.Debug("resizing texture", "name", name, "from", [2]int{dx, dy}, "to", [2]int{width, height})
 
 			resized := image.NewRGBA(image.Rect(0, 0, width, height))
 
@@ -158,7 +158,7 @@ func InitTextureAtlas() error {
 		TextureID: texture,
 	}
 
-	log.Printf("Loaded %d textures into array (size: %dx%d)", len(images), width, height)
+	slog.Info("loaded block textures", "count", len(images), "size", [2]int{width, height})
 	return nil
 }
 
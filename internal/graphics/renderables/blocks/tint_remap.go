@@ -0,0 +1,42 @@
+package blocks
+
+import (
+	"mini-mc/internal/config"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// tintRemaps maps each colorblind mode to a colour matrix applied to biome
+// tints (grass, leaves) in the block vertex shader. Every row sums to 1 so
+// untinted faces (white) are unaffected.
+var tintRemaps = [config.ColorblindModeCount]mgl32.Mat3{
+	config.ColorblindOff: mgl32.Ident3(),
+	// Red-green: foliage green and dirt brown collapse to similar olive tones.
+	// Pull green into the blue channel so foliage reads teal against dirt.
+	config.ColorblindDeuteranopia: mgl32.Mat3FromRows(
+		mgl32.Vec3{0.6, 0.2, 0.2},
+		mgl32.Vec3{0.0, 0.9, 0.1},
+		mgl32.Vec3{0.0, 0.7, 0.3},
+	),
+	config.ColorblindProtanopia: mgl32.Mat3FromRows(
+		mgl32.Vec3{0.5, 0.3, 0.2},
+		mgl32.Vec3{0.0, 0.9, 0.1},
+		mgl32.Vec3{0.0, 0.7, 0.3},
+	),
+	// Blue-yellow: yellow-green foliage and sand look alike. Drop some red and
+	// add blue so foliage separates from sand.
+	config.ColorblindTritanopia: mgl32.Mat3FromRows(
+		mgl32.Vec3{0.8, 0.1, 0.1},
+		mgl32.Vec3{0.0, 1.0, 0.0},
+		mgl32.Vec3{0.0, 0.4, 0.6},
+	),
+}
+
+// currentTintRemap returns the tint matrix for the configured colorblind mode,
+// or identity when foliage remapping is turned off.
+func currentTintRemap() mgl32.Mat3 {
+	if !config.GetColorblindFoliage() {
+		return mgl32.Ident3()
+	}
+	return tintRemaps[config.GetColorblindMode()]
+}
@@ -2,6 +2,8 @@ package blocks
 
 import (
 	"path/filepath"
+
+	"mini-mc/internal/meshing"
 )
 
 const (
@@ -13,6 +15,9 @@ var (
 	MainFragShader  = filepath.Join(ShadersDir, "main.frag")
 	FluidVertShader = filepath.Join(ShadersDir, "fluid.vert")
 	FluidFragShader = filepath.Join(ShadersDir, "fluid.frag")
+
+	ShadowVertShader = filepath.Join(ShadersDir, "shadow.vert")
+	ShadowFragShader = filepath.Join(ShadersDir, "shadow.frag")
 )
 
 type atlasWrite struct {
@@ -26,8 +31,15 @@ type freeSpan struct {
 	sizeShorts   int
 }
 
+// atlasKey names an atlas region: a square of 16x16 columns, at
+// one LOD step. Columns drawn at different steps never share a region.
+type atlasKey struct {
+	x, z int // region coordinates, column coordinates >> 4
+	step int // lodStep of the region's columns
+}
+
 type atlasRegion struct {
-	key            [2]int
+	key            atlasKey
 	vao            uint32
 	vbo            uint32
 	capacityBytes  int
@@ -37,16 +49,24 @@ type atlasRegion struct {
 	pendingWrites  []atlasWrite
 	lastCompact    uint64
 	growthCount    int
-	activeColumns  int // number of columnMeshes with vertexCount>0 referencing this region
+	activeColumns  int           // number of columnMeshes with vertexCount>0 referencing this region
+	layoutVersion  uint64        // bumped whenever a column's slot is allocated, freed or moved, or its Y bounds change
+	packed         packedColumns // culling input for the packed column path
 }
 
 type chunkMesh struct {
 	vertexCount int32
 	cpuVerts    []uint32 // Packed vertices
 	fluidVerts  []float32
-	firstFloat  int    // offset into atlas in shorts
-	firstVertex int32  // offset into atlas in vertices
-	regionKey   [2]int // atlas region owning this mesh data
+	firstFloat  int      // offset into atlas in shorts
+	firstVertex int32    // offset into atlas in vertices
+	regionKey   atlasKey // atlas region owning this mesh data
+
+	translucentVerts []uint32 // packed like cpuVerts, blended instead of atlased
+	enclosedSections int      // sections the mesher skipped as fully enclosed
+
+	lod      []meshing.LODMesh // coarser meshes for distant columns, one per meshing.LODSteps
+	lodBuilt bool              // the last mesh job built lod (it is nil for empty chunks)
 }
 
 type columnMesh struct {
@@ -55,13 +75,13 @@ type columnMesh struct {
 	vertexCount  int32
 	firstFloat   int
 	dirty        bool
-	firstVertex  int32  // offset into atlas in vertices (firstFloat/4)
-	drawnFrame   uint64 // last frame this column participated in a merged draw call
-	visibleFrame uint64 // last frame this column was marked visible
-	regionKey    [2]int // atlas region owning this column data
-	retryFrame   uint64 // earliest frame at which a failed alloc may be retried
-}
-
-type plane struct {
-	a, b, c, d float32
+	firstVertex  int32    // offset into atlas in vertices (firstFloat/4)
+	drawnFrame   uint64   // last frame this column participated in a merged draw call
+	visibleFrame uint64   // last frame this column was marked visible
+	regionKey    atlasKey // atlas region owning this column data
+	retryFrame   uint64   // earliest frame at which a failed alloc may be retried
+	minY, maxY   int16    // world Y span of the mesh, for culling (see yBounds)
+	occluded     bool     // hidden behind nearer terrain at the last occlusion query (see occlusion.go)
+	listed       bool     // in its region's orderedColumns
+	lodStep      int      // blocks per cell the column is drawn at, 1 for full detail (see lod.go)
 }
@@ -0,0 +1,61 @@
+package blocks
+
+import "mini-mc/internal/world"
+
+// occupiedYBounds returns the world Y span of the occupied sections of the
+// chunk at coord. When ch is nil or holds no blocks, the chunk's full Y span
+// is used instead.
+func occupiedYBounds(coord world.ChunkCoord, ch *world.Chunk) (minY, maxY float32) {
+	baseY := coord.Y * world.ChunkSizeY
+	lo, hi := 0, world.ChunkSizeY
+	if ch != nil {
+		if l, h, ok := ch.OccupiedYRange(); ok {
+			lo, hi = l, h
+		}
+	}
+	return float32(baseY + lo), float32(baseY + hi)
+}
+
+// verticalGap returns how many blocks y lies above or below the occupied
+// sections of the chunk at coord (0 when y is inside them).
+func verticalGap(coord world.ChunkCoord, ch *world.Chunk, y float32) float32 {
+	minY, maxY := occupiedYBounds(coord, ch)
+	switch {
+	case y < minY:
+		return minY - y
+	case y > maxY:
+		return y - maxY
+	}
+	return 0
+}
+
+// meshYBounds returns the lowest and highest Y of the vertices in an atlas
+// vertex buffer (six shorts each, Y second).
+func meshYBounds(buf []int16) (minY, maxY int16) {
+	if len(buf) < 6 {
+		return 0, 0
+	}
+	minY, maxY = buf[1], buf[1]
+	for i := 7; i < len(buf); i += 6 {
+		minY = min(minY, buf[i])
+		maxY = max(maxY, buf[i])
+	}
+	return minY, maxY
+}
+
+// yBounds returns the world Y span of the column's mesh, or the full chunk
+// height before it has been built.
+func (c *columnMesh) yBounds() (minY, maxY float32) {
+	if c.maxY <= c.minY {
+		return 0, world.ChunkSizeY
+	}
+	return float32(c.minY), float32(c.maxY)
+}
+
+// withinVerticalRadius reports whether the chunk at coord has blocks within
+// radiusChunks chunk widths of y vertically. Radius checks elsewhere are
+// XZ-only, so this keeps deep cave chunks and high air chunks out of the
+// candidate set once worlds stack chunks along Y.
+func withinVerticalRadius(coord world.ChunkCoord, ch *world.Chunk, y float32, radiusChunks int) bool {
+	return verticalGap(coord, ch, y) <= float32(radiusChunks*world.ChunkSizeX)
+}
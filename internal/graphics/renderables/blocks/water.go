@@ -0,0 +1,253 @@
+package blocks
+
+import (
+	"log/slog"
+	"math"
+
+	"mini-mc/internal/config"
+	"mini-mc/internal/graphics/renderer"
+	"mini-mc/internal/mathutil"
+	"mini-mc/internal/meshing"
+	"mini-mc/internal/profiling"
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Fancy water (config.GetFancyWater). After the opaque world is drawn, it is
+// drawn again mirrored in a horizontal water plane, clipped to what lies
+// above the plane, into an offscreen target. Before the translucent stage the
+// opaque scene is copied for refraction. The fluid shader blends the two on
+// water tops at the plane height; water at other heights keeps the plain look.
+
+const (
+	reflectionScale        = 2  // the reflection target is the screen size divided by this
+	reflectionRadiusChunks = 12 // how far around the player the reflection draws columns
+	rippleMapSize          = 64
+)
+
+// Fluid vertex fields read when picking the water plane
+const (
+	fluidVertexY         = 1
+	fluidVertexTexID     = 5
+	fluidVertexFlowAngle = 9
+)
+
+// planarWater holds the offscreen targets of fancy water.
+type planarWater struct {
+	reflectFBO   uint32
+	reflectTex   uint32
+	reflectDepth uint32 // depth renderbuffer of reflectFBO
+	refractTex   uint32
+	rippleTex    uint32
+	width        int // screen size the targets were made for
+	height       int
+
+	planeY float32 // height of the water plane this frame
+	active bool    // the reflection was drawn this frame
+}
+
+// waterPlaneY picks the water plane from fluid vertices: the height of the
+// still water top horizontally nearest to eye among those below it.
+// stillLayer is the texture layer of still water.
+func waterPlaneY(verts []float32, eye mgl32.Vec3, stillLayer float32) (float32, bool) {
+	best := float32(math.MaxFloat32)
+	var y float32
+	found := false
+	for i := 0; i+meshing.FluidVertexFloats <= len(verts); i += meshing.FluidVertexFloats {
+		v := verts[i : i+meshing.FluidVertexFloats]
+		if v[fluidVertexFlowAngle] != -1 || v[fluidVertexTexID] != stillLayer || v[fluidVertexY] >= eye.Y() {
+			continue
+		}
+		dx, dz := v[0]-eye.X(), v[2]-eye.Z()
+		if d := dx*dx + dz*dz; d < best {
+			best, y, found = d, v[fluidVertexY], true
+		}
+	}
+	return y, found
+}
+
+// waterLayers returns the texture layers of still and flowing water.
+func waterLayers() (still, flow float32) {
+	return float32(registry.TextureMap["water_still.png"]), float32(registry.TextureMap["water_flow.png"])
+}
+
+// mirrorY returns the transform that reflects the world in the plane y = h.
+func mirrorY(h float32) mgl32.Mat4 {
+	return mgl32.Translate3D(0, h, 0).Mul4(mgl32.Scale3D(1, -1, 1)).Mul4(mgl32.Translate3D(0, -h, 0))
+}
+
+// rippleNormalMap returns a size×size RGB normal map of a height field made
+// of sine waves with whole periods across the tile, so it repeats without
+// seams. Normals are in tangent space, x and y across the surface and z up,
+// each component packed from [-1, 1] into a byte.
+func rippleNormalMap(size int) []uint8 {
+	waves := [...]struct{ kx, ky, slope, phase float64 }{
+		{1, 2, 0.35, 0},
+		{3, -1, 0.25, 1.3},
+		{-2, 5, 0.15, 2.1},
+		{7, 3, 0.08, 0.4},
+		{-5, -6, 0.06, 4.0},
+	}
+	out := make([]uint8, 0, size*size*3)
+	for y := range size {
+		for x := range size {
+			var sx, sy float64
+			for _, w := range waves {
+				a := 2*math.Pi*(w.kx*float64(x)+w.ky*float64(y))/float64(size) + w.phase
+				sx += w.slope * w.kx * math.Cos(a) / math.Hypot(w.kx, w.ky)
+				sy += w.slope * w.ky * math.Cos(a) / math.Hypot(w.kx, w.ky)
+			}
+			n := mgl32.Vec3{float32(-sx), float32(-sy), 1}.Normalize()
+			for _, c := range n {
+				out = append(out, uint8(math.Round(float64(c*0.5+0.5)*255)))
+			}
+		}
+	}
+	return out
+}
+
+// ensureTargets creates the offscreen targets on first use and resizes them
+// to a width×height screen.
+func (pw *planarWater) ensureTargets(width, height int) {
+	if pw.reflectFBO != 0 && width == pw.width && height == pw.height {
+		return
+	}
+	if pw.reflectFBO == 0 {
+		gl.GenFramebuffers(1, &pw.reflectFBO)
+		gl.GenTextures(1, &pw.reflectTex)
+		gl.GenRenderbuffers(1, &pw.reflectDepth)
+		gl.GenTextures(1, &pw.refractTex)
+
+		ripples := rippleNormalMap(rippleMapSize)
+		gl.GenTextures(1, &pw.rippleTex)
+		gl.BindTexture(gl.TEXTURE_2D, pw.rippleTex)
+		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGB8, rippleMapSize, rippleMapSize, 0, gl.RGB, gl.UNSIGNED_BYTE, gl.Ptr(ripples))
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.REPEAT)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.REPEAT)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR_MIPMAP_LINEAR)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+		gl.GenerateMipmap(gl.TEXTURE_2D)
+	}
+	pw.width, pw.height = width, height
+	rw, rh := int32(max(width/reflectionScale, 1)), int32(max(height/reflectionScale, 1))
+
+	for _, t := range []struct {
+		id   uint32
+		w, h int32
+	}{{pw.reflectTex, rw, rh}, {pw.refractTex, int32(width), int32(height)}} {
+		gl.BindTexture(gl.TEXTURE_2D, t.id)
+		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, t.w, t.h, 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	}
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	gl.BindRenderbuffer(gl.RENDERBUFFER, pw.reflectDepth)
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH_COMPONENT24, rw, rh)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, pw.reflectFBO)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, pw.reflectTex, 0)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.RENDERBUFFER, pw.reflectDepth)
+	if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		slog.Error("water reflection framebuffer incomplete", "status", status)
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+}
+
+// dispose frees the offscreen targets.
+func (pw *planarWater) dispose() {
+	if pw.reflectFBO != 0 {
+		gl.DeleteFramebuffers(1, &pw.reflectFBO)
+		gl.DeleteRenderbuffers(1, &pw.reflectDepth)
+		textures := []uint32{pw.reflectTex, pw.refractTex, pw.rippleTex}
+		gl.DeleteTextures(int32(len(textures)), &textures[0])
+	}
+	*pw = planarWater{}
+}
+
+// renderReflection draws the opaque world mirrored in the water plane into
+// the reflection target, if fancy water is on and there is still water below
+// the eye. It records in b.water whether the fluid shader can use it.
+func (b *Blocks) renderReflection(ctx renderer.RenderContext, isUnderwater int) {
+	pw := &b.water
+	pw.active = false
+	if !config.GetFancyWater() || isUnderwater != 0 {
+		return
+	}
+	eye := ctx.Snapshot.Eye
+	still, _ := waterLayers()
+	planeY, ok := waterPlaneY(b.fluidVerts, eye, still)
+	if !ok {
+		return
+	}
+	defer profiling.Track("renderer.renderBlocks.reflection")()
+
+	var viewport [4]int32
+	var target int32 // the framebuffer being drawn, the window's or a capture's
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	gl.GetIntegerv(gl.DRAW_FRAMEBUFFER_BINDING, &target)
+	pw.ensureTargets(int(viewport[2]), int(viewport[3]))
+	pw.planeY = planeY
+
+	view := ctx.View.Mul4(mirrorY(planeY))
+	planes := mathutil.FrustumFromMatrix(ctx.Proj.Mul4(view))
+	pcx := int(math.Floor(float64(eye.X()))) / world.ChunkSizeX
+	pcz := int(math.Floor(float64(eye.Z()))) / world.ChunkSizeZ
+	radius := min(config.GetMaxRenderRadius(), reflectionRadiusChunks)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, pw.reflectFBO)
+	gl.Viewport(0, 0, int32(max(pw.width/reflectionScale, 1)), int32(max(pw.height/reflectionScale, 1)))
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	gl.Enable(gl.CLIP_DISTANCE0)
+	gl.Disable(gl.CULL_FACE)
+
+	b.mainShader.Use()
+	if GlobalTextureAtlas != nil {
+		gl.ActiveTexture(gl.TEXTURE0)
+		gl.BindTexture(gl.TEXTURE_2D_ARRAY, GlobalTextureAtlas.TextureID)
+	}
+	b.mainShader.SetMatrix4("view", &view[0])
+	b.mainShader.SetVector4("clipPlane", 0, 1, 0, -planeY)
+	for _, r := range atlasRegions {
+		if r == nil || len(r.orderedColumns) == 0 {
+			continue
+		}
+		refreshPackedColumns(r)
+		firstsScratch, countsScratch = cullPackedColumns(&r.packed, planes, pcx, pcz, radius, currentFrame, false, firstsScratch[:0], countsScratch[:0])
+		drawRegion(r, firstsScratch, countsScratch)
+	}
+	b.mainShader.SetMatrix4("view", &ctx.View[0])
+	b.mainShader.SetVector4("clipPlane", 0, 0, 0, 1)
+
+	gl.Enable(gl.CULL_FACE)
+	gl.Disable(gl.CLIP_DISTANCE0)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(target))
+	gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+	pw.active = true
+}
+
+// copyRefraction copies the opaque scene on screen into the refraction
+// texture. It runs once the opaque stage is finished.
+func (pw *planarWater) copyRefraction() {
+	var viewport [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	gl.BindTexture(gl.TEXTURE_2D, pw.refractTex)
+	gl.CopyTexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, viewport[0], viewport[1], min(viewport[2], int32(pw.width)), min(viewport[3], int32(pw.height)))
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+}
+
+// bindTextures binds the reflection, refraction and ripple textures to units
+// 1 to 3, where the fluid shader samples them.
+func (pw *planarWater) bindTextures() {
+	for i, t := range []uint32{pw.reflectTex, pw.refractTex, pw.rippleTex} {
+		gl.ActiveTexture(gl.TEXTURE1 + uint32(i))
+		gl.BindTexture(gl.TEXTURE_2D, t)
+	}
+	gl.ActiveTexture(gl.TEXTURE0)
+}
@@ -0,0 +1,75 @@
+package blocks
+
+import (
+	"math"
+	"testing"
+
+	"mini-mc/internal/meshing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// fluidVertex returns one fluid vertex at (x, y, z) with the given texture
+// layer and flow angle.
+func fluidVertex(x, y, z, texID, flowAngle float32) []float32 {
+	v := make([]float32, meshing.FluidVertexFloats)
+	v[0], v[fluidVertexY], v[2] = x, y, z
+	v[fluidVertexTexID] = texID
+	v[fluidVertexFlowAngle] = flowAngle
+	return v
+}
+
+func TestWaterPlaneIsTheNearestStillWaterBelowTheEye(t *testing.T) {
+	const still, flow = 7, 8
+	var verts []float32
+	verts = append(verts, fluidVertex(40, 62.9, 0, still, -1)...) // far sea
+	verts = append(verts, fluidVertex(3, 70.9, 2, still, -1)...)  // pond above the eye
+	verts = append(verts, fluidVertex(2, 64.5, 1, flow, 0.3)...)  // stream
+	verts = append(verts, fluidVertex(1, 62.8, -1, still, -2)...) // side face
+	verts = append(verts, fluidVertex(9, 58.9, 4, still, -1)...)  // lake
+
+	y, ok := waterPlaneY(verts, mgl32.Vec3{0, 68, 0}, still)
+	if !ok || y != 58.9 {
+		t.Errorf("plane = %v, %v; want the lake at 58.9", y, ok)
+	}
+	if _, ok := waterPlaneY(verts, mgl32.Vec3{0, 50, 0}, still); ok {
+		t.Error("found a plane with all water above the eye")
+	}
+}
+
+func TestMirrorYReflectsInThePlane(t *testing.T) {
+	got := mirrorY(62.5).Mul4x1(mgl32.Vec4{3, 70, -2, 1})
+	if want := (mgl32.Vec4{3, 55, -2, 1}); !got.ApproxEqual(want) {
+		t.Errorf("mirrored point = %v, want %v", got, want)
+	}
+}
+
+func TestRippleNormalMapPointsUpAtAnySize(t *testing.T) {
+	const size = 32
+	m := rippleNormalMap(size)
+	if len(m) != size*size*3 {
+		t.Fatalf("len = %d", len(m))
+	}
+	decode := func(x, y int) mgl32.Vec3 {
+		i := ((y%size)*size + x%size) * 3
+		return mgl32.Vec3{float32(m[i]), float32(m[i+1]), float32(m[i+2])}.Mul(2.0 / 255).Sub(mgl32.Vec3{1, 1, 1})
+	}
+	for y := range size {
+		for x := range size {
+			n := decode(x, y)
+			if l := n.Len(); math.Abs(float64(l)-1) > 0.02 || n.Z() < 0.5 {
+				t.Fatalf("normal at (%d,%d) = %v", x, y, n)
+			}
+		}
+	}
+	// A map twice the size holds the same waves, so every other texel matches
+	big := rippleNormalMap(2 * size)
+	for y := range size {
+		for x := range size {
+			i, j := (y*size+x)*3, (2*y*2*size+2*x)*3
+			if m[i] != big[j] || m[i+1] != big[j+1] || m[i+2] != big[j+2] {
+				t.Fatalf("texel (%d,%d) differs between tile sizes", x, y)
+			}
+		}
+	}
+}
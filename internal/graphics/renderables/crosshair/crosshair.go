@@ -1,6 +1,7 @@
 package crosshair
 
 import (
+	"mini-mc/internal/config"
 	"mini-mc/internal/graphics"
 	"mini-mc/internal/graphics/renderer"
 	"mini-mc/internal/profiling"
@@ -101,6 +102,11 @@ func (c *Crosshair) Dispose() {
 	}
 }
 
+// Stage draws the crosshair over the finished world.
+func (c *Crosshair) Stage() renderer.Stage {
+	return renderer.StageOverlay
+}
+
 // SetViewport updates the crosshair viewport dimensions
 func (c *Crosshair) SetViewport(width, height int) {
 	c.width = float32(width)
@@ -136,13 +142,20 @@ func (c *Crosshair) renderCrosshair(screenWidth, screenHeight int32) {
 	// Enable blending for crosshair
 	gl.Enable(gl.BLEND)
 
-	// Minecraft's special blend function for crosshair visibility
-	// GL_ONE_MINUS_DST_COLOR (775) and GL_ONE_MINUS_SRC_ALPHA (769)
-	// This creates an inverse effect that makes crosshair visible on any background
-	gl.BlendFuncSeparate(gl.ONE_MINUS_DST_COLOR, gl.ONE_MINUS_SRC_ALPHA, gl.ONE, gl.ZERO)
-
 	c.shader.Use()
 
+	if config.GetHighContrast() {
+		// Solid tinted crosshair; the inverse blend below can wash out on mid-grey backgrounds
+		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+		c.shader.SetVector3("tint", 1.0, 0.9, 0.0)
+	} else {
+		// Minecraft's special blend function for crosshair visibility
+		// GL_ONE_MINUS_DST_COLOR (775) and GL_ONE_MINUS_SRC_ALPHA (769)
+		// This creates an inverse effect that makes crosshair visible on any background
+		gl.BlendFuncSeparate(gl.ONE_MINUS_DST_COLOR, gl.ONE_MINUS_SRC_ALPHA, gl.ONE, gl.ZERO)
+		c.shader.SetVector3("tint", 1.0, 1.0, 1.0)
+	}
+
 	// Set screen dimensions for proper positioning
 	c.shader.SetInt("screenWidth", screenWidth)
 	c.shader.SetInt("screenHeight", screenHeight)
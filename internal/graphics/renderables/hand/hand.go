@@ -64,6 +64,10 @@ func (h *Hand) Init() error {
 
 // Render renders the first-person hand
 func (h *Hand) Render(ctx renderer.RenderContext) {
+	// In third person the whole body is drawn in the world instead
+	if ctx.Player.Perspective != player.FirstPerson {
+		return
+	}
 	func() {
 		defer profiling.Track("renderer.renderHand")()
 		h.renderHand(ctx.Player, ctx.DT, ctx.Camera)
@@ -80,6 +84,11 @@ func (h *Hand) Dispose() {
 	}
 }
 
+// Stage draws the hand over the finished world.
+func (h *Hand) Stage() renderer.Stage {
+	return renderer.StageOverlay
+}
+
 // SetViewport updates viewport dimensions (not needed for hand)
 func (h *Hand) SetViewport(width, height int) {
 	// Hand rendering doesn't need viewport dimensions
@@ -234,7 +243,7 @@ func (h *Hand) renderHand(p *player.Player, dt float64, camera *graphics.Camera)
 	// Render either item or hand (like Minecraft ItemRenderer.java:406-411)
 	if p.EquippedItem != nil && h.items != nil {
 		itemModel := mgl32.Ident4()
-		itemModel = h.setupViewBobbing(p, itemModel, dt)
+		itemModel = h.setupViewBobbing(p, itemModel, float64(p.PartialTick))
 		itemModel = h.setupHandSway(p, itemModel, dt)
 
 		// Item used transformations (bobbing during swing)
@@ -256,7 +265,7 @@ func (h *Hand) renderHand(p *player.Player, dt float64, camera *graphics.Camera)
 		h.items.RenderHand(p.EquippedItem, proj, itemModel)
 	} else { // Show hand even when sneaking
 		model := mgl32.Ident4()
-		model = h.setupViewBobbing(p, model, dt)
+		model = h.setupViewBobbing(p, model, float64(p.PartialTick))
 		model = h.setupHandSway(p, model, dt)
 		model = model.Mul4(mgl32.Translate3D(asX, asY, asZ))
 
@@ -298,11 +307,11 @@ func (h *Hand) renderHand(p *player.Player, dt float64, camera *graphics.Camera)
 	}
 }
 
-func (h *Hand) setupViewBobbing(p *player.Player, model mgl32.Mat4, dt float64) mgl32.Mat4 {
+func (h *Hand) setupViewBobbing(p *player.Player, model mgl32.Mat4, partialTicks float64) mgl32.Mat4 {
 	f := p.DistanceWalkedModified - p.PrevDistanceWalkedModified
-	f1 := -(p.DistanceWalkedModified + f*dt)
-	f2 := p.PrevHeadBobYaw + (p.HeadBobYaw-p.PrevHeadBobYaw)*dt
-	f3 := p.PrevHeadBobPitch + (p.HeadBobPitch-p.PrevHeadBobPitch)*dt
+	f1 := -(p.DistanceWalkedModified + f*partialTicks)
+	f2 := p.PrevHeadBobYaw + (p.HeadBobYaw-p.PrevHeadBobYaw)*partialTicks
+	f3 := p.PrevHeadBobPitch + (p.HeadBobPitch-p.PrevHeadBobPitch)*partialTicks
 
 	const deg2rad = math.Pi / 180.0
 
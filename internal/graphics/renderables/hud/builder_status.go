@@ -0,0 +1,18 @@
+package hud
+
+import (
+	"mini-mc/internal/config"
+	"mini-mc/internal/player"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// renderBuilderStatus shows that builder mode is on, and its mirror axis,
+// above the held item's name.
+func (h *HUD) renderBuilderStatus(p *player.Player) {
+	text := "Builder mode | Mirror: " + p.Builder.Mirror.String()
+	size := 0.3 * config.GetHUDTextScale()
+	w, _ := h.fontRenderer.Measure(text, size)
+	y := h.height - 22*float32(config.GetGUIScale()) - 80
+	h.fontRenderer.Render(text, (h.width-w)/2, y, size, mgl32.Vec3{1.0, 0.9, 0.4})
+}
@@ -0,0 +1,112 @@
+package hud
+
+import (
+	"strings"
+	"time"
+
+	"mini-mc/internal/command"
+	"mini-mc/internal/config"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Chat lines show above the hotbar for chatLineDuration after they arrive,
+// the last second fading out. While the prompt is open the last
+// chatOpenLines show whatever their age, and chatMaxLines are kept.
+const (
+	chatLineDuration = 10 * time.Second
+	chatClosedLines  = 10
+	chatOpenLines    = 20
+	chatMaxLines     = 100
+	chatWidth        = 320 // in GUI pixels, before the GUI scale
+)
+
+// chatLine is a line of chat or command output and when it arrived.
+type chatLine struct {
+	text  string
+	at    time.Time
+	error bool // a command failed; shown in red
+}
+
+// AddChatLine adds text to the chat, a line for each line of it.
+func (h *HUD) AddChatLine(text string) {
+	h.addChatLines(text, false)
+}
+
+// AddChatError adds a command's error to the chat.
+func (h *HUD) AddChatError(text string) {
+	h.addChatLines(text, true)
+}
+
+func (h *HUD) addChatLines(text string, isError bool) {
+	now := time.Now()
+	for line := range strings.Lines(text) {
+		h.chatLines = append(h.chatLines, chatLine{text: strings.TrimRight(line, "\n"), at: now, error: isError})
+	}
+	if n := len(h.chatLines); n > chatMaxLines {
+		h.chatLines = append(h.chatLines[:0], h.chatLines[n-chatMaxLines:]...)
+	}
+}
+
+// SetChatPrompt shows the prompt being typed into under the chat, or hides
+// it when nil.
+func (h *HUD) SetChatPrompt(p *command.Prompt) {
+	h.chatPrompt = p
+}
+
+// renderChat draws the chat in the bottom-left corner: the recent lines
+// over the hotbar, or with the prompt open, more lines over the prompt.
+func (h *HUD) renderChat() {
+	ts := config.GetHUDTextScale()
+	gui := float32(config.GetGUIScale())
+	scale := 0.3 * ts
+	lineStep := 14 * ts
+	pad := 4 * ts
+	width := min(chatWidth*gui, h.width-4*gui)
+	x := 2 * gui
+
+	bottom := h.height - 48*gui
+	shown := chatClosedLines
+	if h.chatPrompt != nil {
+		boxH := lineStep + 2*pad
+		bottom = h.height - boxH - 2*gui
+		h.renderChatPrompt(x, bottom, h.width-2*x, boxH, scale, pad)
+		shown = chatOpenLines
+	}
+
+	now := time.Now()
+	y := bottom
+	for i := len(h.chatLines) - 1; i >= 0 && i >= len(h.chatLines)-shown; i-- {
+		line := h.chatLines[i]
+		alpha := float32(1)
+		if h.chatPrompt == nil {
+			left := chatLineDuration - now.Sub(line.at)
+			if left <= 0 {
+				break
+			}
+			alpha = float32(min(1, left.Seconds()))
+		}
+		h.uiRenderer.DrawFilledRect(x, y-lineStep, width, lineStep, mgl32.Vec3{0, 0, 0}, 0.5*alpha)
+		color := mgl32.Vec3{1, 1, 1}
+		if line.error {
+			color = mgl32.Vec3{1, 0.33, 0.33}
+		}
+		h.uiRenderer.DrawText(line.text, x+pad, y-lineStep*0.25, scale, color.Mul(alpha))
+		y -= lineStep
+	}
+}
+
+// renderChatPrompt draws the line being typed in a box with its top left at
+// x, y, and a blinking caret.
+func (h *HUD) renderChatPrompt(x, y, w, boxH, scale, pad float32) {
+	p := h.chatPrompt
+	text := p.Text()
+	h.uiRenderer.DrawFilledRect(x, y, w, boxH, mgl32.Vec3{0, 0, 0}, 0.5)
+	_, th := h.uiRenderer.MeasureText("A", scale) // the prompt may be empty
+	h.uiRenderer.DrawText(text, x+pad, y+pad+th, scale, mgl32.Vec3{1, 1, 1})
+
+	if time.Now().UnixMilli()/500%2 == 0 {
+		before, _ := h.uiRenderer.MeasureText(string([]rune(text)[:p.Caret()]), scale)
+		h.uiRenderer.DrawFilledRect(x+pad+before, y+pad, max(1, scale*3), boxH-2*pad, mgl32.Vec3{1, 1, 1}, 0.9)
+	}
+}
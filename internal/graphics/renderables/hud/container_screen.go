@@ -2,8 +2,10 @@ package hud
 
 import (
 	"fmt"
+	"mini-mc/internal/config"
 	"mini-mc/internal/inventory"
 	"mini-mc/internal/player"
+	"slices"
 	"time"
 
 	"github.com/go-gl/glfw/v3.3/glfw"
@@ -21,19 +23,30 @@ type ContainerScreen struct {
 	Width, Height float32
 	Scale         float32
 
-	backgroundTex uint32
+	backgroundTex uint32 // 0 when drawBackground draws it all
 	backgroundW   float32
 	backgroundH   float32
 
 	hoveredSlotIndex int
 
+	// drawBackground, if set, draws screen-specific parts (e.g. progress
+	// bars) on top of the background texture but below the items.
+	drawBackground func()
+
 	// Double click tracking
 	lastClickSlotIndex int
 	lastClickTime      time.Time
+
+	// Drag tracking: pressing a button on a slot while holding a stack starts
+	// a drag, and the slots the mouse passes over before the release share
+	// the stack out (see Container.DragSplit).
+	dragging   bool
+	dragButton glfw.MouseButton
+	dragSlots  []int
 }
 
 func NewContainerScreen(hud *HUD, p *player.Player, c *inventory.Container, tex uint32, w, h float32) *ContainerScreen {
-	scale := float32(2.0)
+	scale := float32(config.GetGUIScale())
 	// Center on screen
 	screenW := hud.width
 	screenH := hud.height
@@ -80,7 +93,12 @@ func (s *ContainerScreen) Render(mouseX, mouseY float64) {
 	v1 := s.backgroundH / 256.0
 	color := mgl32.Vec3{1.0, 1.0, 1.0}
 
-	s.HUD.uiRenderer.DrawTexturedRect(s.X, s.Y, s.Width, s.Height, s.backgroundTex, 0, 0, u1, v1, color, 1.0)
+	if s.backgroundTex != 0 {
+		s.HUD.uiRenderer.DrawTexturedRect(s.X, s.Y, s.Width, s.Height, s.backgroundTex, 0, 0, u1, v1, color, 1.0)
+	}
+	if s.drawBackground != nil {
+		s.drawBackground()
+	}
 
 	// Flush background so items draw on top
 	s.HUD.uiRenderer.Flush()
@@ -98,6 +116,7 @@ func (s *ContainerScreen) Render(mouseX, mouseY float64) {
 		stack := slot.GetStack()
 		if stack != nil {
 			s.HUD.itemRenderer.RenderGUI(stack, slotX, slotY, itemSize)
+			s.HUD.renderDurabilityBar(stack, slotX, slotY, s.Scale)
 
 			if stack.Count > 1 {
 				countText := fmt.Sprintf("%d", stack.Count)
@@ -110,8 +129,11 @@ func (s *ContainerScreen) Render(mouseX, mouseY float64) {
 		if mx >= slotX && mx < slotX+itemSize && my >= slotY && my < slotY+itemSize {
 			s.hoveredSlotIndex = i
 			s.HUD.uiRenderer.DrawFilledRect(slotX, slotY, itemSize, itemSize, mgl32.Vec3{1, 1, 1}, 0.5)
+		} else if s.dragging && slices.Contains(s.dragSlots, i) {
+			s.HUD.uiRenderer.DrawFilledRect(slotX, slotY, itemSize, itemSize, mgl32.Vec3{1, 1, 1}, 0.35)
 		}
 	}
+	s.extendDrag()
 
 	// Flush overlays (so they are drawn over items but UNDER cursor)
 	s.HUD.uiRenderer.Flush()
@@ -119,6 +141,7 @@ func (s *ContainerScreen) Render(mouseX, mouseY float64) {
 	cursor := s.Player.Inventory.CursorStack
 	if cursor != nil {
 		s.HUD.itemRenderer.RenderGUI(cursor, mx-itemSize/2, my-itemSize/2, itemSize)
+		s.HUD.renderDurabilityBar(cursor, mx-itemSize/2, my-itemSize/2, s.Scale)
 
 		if cursor.Count > 1 {
 			countText := fmt.Sprintf("%d", cursor.Count)
@@ -130,52 +153,98 @@ func (s *ContainerScreen) Render(mouseX, mouseY float64) {
 }
 
 func (s *ContainerScreen) HandleClick(x, y float64, button glfw.MouseButton, action glfw.Action) bool {
-	if action != glfw.Press {
+	if action == glfw.Release {
+		return s.finishDrag(button)
+	}
+	if action != glfw.Press || s.dragging {
 		return false
 	}
 
-	mx := float32(x)
-	my := float32(y)
-	itemSize := 16 * s.Scale
+	clickedSlotIndex := s.slotAt(float32(x), float32(y))
+	if clickedSlotIndex == -1 {
+		return false
+	}
+
+	// Map glfw button to inventory button
+	invBtn, ok := inventoryButton(button)
+	if !ok {
+		return false // Unknown button
+	}
 
-	clickedSlotIndex := -1
+	// Double click detection
+	isDoubleClick := false
+	if button == glfw.MouseButtonLeft {
+		if clickedSlotIndex == s.lastClickSlotIndex && time.Since(s.lastClickTime) < 300*time.Millisecond {
+			isDoubleClick = true
+		}
+		s.lastClickSlotIndex = clickedSlotIndex
+		s.lastClickTime = time.Now()
+	}
+
+	// Putting down a held stack waits for the release, in case it is dragged
+	if !isDoubleClick && s.Container.CanDragInto(clickedSlotIndex, s.Player.Inventory.CursorStack) {
+		s.dragging = true
+		s.dragButton = button
+		s.dragSlots = []int{clickedSlotIndex}
+		return true
+	}
 
+	s.Container.SlotClick(clickedSlotIndex, invBtn, isDoubleClick, s.Player.Inventory)
+	return true
+}
+
+// extendDrag adds the hovered slot to the drag in progress, if it can take
+// items and the held stack has one to spare for it.
+func (s *ContainerScreen) extendDrag() {
+	cursor := s.Player.Inventory.CursorStack
+	i := s.hoveredSlotIndex
+	if !s.dragging || i == -1 || slices.Contains(s.dragSlots, i) || len(s.dragSlots) >= cursor.Count {
+		return
+	}
+	if s.Container.CanDragInto(i, cursor) {
+		s.dragSlots = append(s.dragSlots, i)
+	}
+}
+
+// finishDrag ends the drag when its button is released: a drag that never
+// left its first slot is an ordinary click on it.
+func (s *ContainerScreen) finishDrag(button glfw.MouseButton) bool {
+	if !s.dragging || button != s.dragButton {
+		return false
+	}
+	s.dragging = false
+	invBtn, _ := inventoryButton(button)
+	if len(s.dragSlots) == 1 {
+		s.Container.SlotClick(s.dragSlots[0], invBtn, false, s.Player.Inventory)
+	} else {
+		s.Container.DragSplit(s.dragSlots, invBtn, s.Player.Inventory)
+	}
+	s.dragSlots = nil
+	return true
+}
+
+// slotAt returns the index of the slot at screen position (mx, my), or -1.
+func (s *ContainerScreen) slotAt(mx, my float32) int {
+	itemSize := 16 * s.Scale
 	for i, slot := range s.Container.Slots {
 		slotX := s.X + float32(slot.X)*s.Scale
 		slotY := s.Y + float32(slot.Y)*s.Scale
 
 		if mx >= slotX && mx < slotX+itemSize && my >= slotY && my < slotY+itemSize {
-			clickedSlotIndex = i
-			break
+			return i
 		}
 	}
+	return -1
+}
 
-	if clickedSlotIndex != -1 {
-		// Map glfw button to inventory button
-		var invBtn inventory.MouseButton
-		if button == glfw.MouseButtonLeft {
-			invBtn = inventory.MouseButtonLeft
-		} else if button == glfw.MouseButtonRight {
-			invBtn = inventory.MouseButtonRight
-		} else {
-			return false // Unknown button
-		}
-
-		// Double click detection
-		isDoubleClick := false
-		if button == glfw.MouseButtonLeft {
-			if clickedSlotIndex == s.lastClickSlotIndex && time.Since(s.lastClickTime) < 300*time.Millisecond {
-				isDoubleClick = true
-			}
-			s.lastClickSlotIndex = clickedSlotIndex
-			s.lastClickTime = time.Now()
-		}
-
-		s.Container.SlotClick(clickedSlotIndex, invBtn, isDoubleClick, s.Player.Inventory)
-		return true
+func inventoryButton(button glfw.MouseButton) (inventory.MouseButton, bool) {
+	switch button {
+	case glfw.MouseButtonLeft:
+		return inventory.MouseButtonLeft, true
+	case glfw.MouseButtonRight:
+		return inventory.MouseButtonRight, true
 	}
-
-	return false
+	return 0, false
 }
 
 func (s *ContainerScreen) Close() {}
@@ -0,0 +1,39 @@
+package hud
+
+import (
+	"fmt"
+	"mini-mc/internal/graphics"
+	"mini-mc/internal/inventory"
+	"mini-mc/internal/player"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// CraftingTableScreen shows a crafting table's 3x3 grid and its result above
+// the player's inventory (MC's GuiCrafting layout).
+type CraftingTableScreen struct {
+	*ContainerScreen
+}
+
+func NewCraftingTableScreen(hud *HUD, p *player.Player, grid *inventory.CraftingGrid) *CraftingTableScreen {
+	container := inventory.NewCraftingTableContainer(p.Inventory, grid)
+
+	tex, err := graphics.GetTexture("assets/textures/gui/crafting_table.png")
+	if err != nil {
+		panic(fmt.Errorf("failed to load crafting table texture: %v", err))
+	}
+
+	s := &CraftingTableScreen{
+		ContainerScreen: NewContainerScreen(hud, p, container, tex, 176, 166),
+	}
+	s.Init()
+	return s
+}
+
+func (s *CraftingTableScreen) Render(mouseX, mouseY float64) {
+	s.ContainerScreen.Render(mouseX, mouseY)
+
+	labelColor := mgl32.Vec3{0.25, 0.25, 0.25}
+	s.HUD.fontRenderer.Render("Crafting", s.X+28*s.Scale, s.Y+6*s.Scale, 0.35, labelColor)
+	s.HUD.fontRenderer.Render("Inventory", s.X+8*s.Scale, s.Y+72*s.Scale, 0.35, labelColor)
+}
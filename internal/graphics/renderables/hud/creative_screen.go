@@ -0,0 +1,79 @@
+package hud
+
+import (
+	"mini-mc/internal/inventory"
+	"mini-mc/internal/player"
+	"mini-mc/internal/registry"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// CreativeScreen is the creative inventory: a scrolling picker of every
+// block, taken from without running out, above the player's hotbar. There
+// is no texture for it, so the panel is drawn in flat colours.
+type CreativeScreen struct {
+	*ContainerScreen
+	palette *inventory.CreativePalette
+}
+
+// The panel is the usual 176 pixels wide, with the palette's rows and the
+// hotbar below them.
+const (
+	creativeScreenW = 176
+	creativeScreenH = 136
+)
+
+func NewCreativeScreen(hud *HUD, p *player.Player) *CreativeScreen {
+	palette := inventory.NewCreativePalette(registry.CreativeBlocks())
+	container := inventory.NewCreativeContainer(p.Inventory, palette)
+
+	s := &CreativeScreen{
+		ContainerScreen: NewContainerScreen(hud, p, container, 0, creativeScreenW, creativeScreenH),
+		palette:         palette,
+	}
+	s.drawBackground = s.drawPanel
+	s.Init()
+	return s
+}
+
+// drawPanel draws the panel, a sunken well under each slot and the scroll
+// bar beside the palette.
+func (s *CreativeScreen) drawPanel() {
+	ui := s.HUD.uiRenderer
+	ui.DrawFilledRect(s.X, s.Y, s.Width, s.Height, mgl32.Vec3{0.78, 0.78, 0.78}, 1.0)
+
+	well := 18 * s.Scale
+	for _, slot := range s.Container.Slots {
+		x := s.X + float32(slot.X-1)*s.Scale
+		y := s.Y + float32(slot.Y-1)*s.Scale
+		ui.DrawFilledRect(x, y, well, well, mgl32.Vec3{0.55, 0.55, 0.55}, 1.0)
+	}
+
+	// The thumb's place along the track shows how far the palette has scrolled
+	trackX := s.X + 170*s.Scale
+	trackY := s.Y + 18*s.Scale
+	trackH := float32(inventory.CreativeRows*18) * s.Scale
+	ui.DrawFilledRect(trackX, trackY, 4*s.Scale, trackH, mgl32.Vec3{0.45, 0.45, 0.45}, 1.0)
+	thumbH := 15 * s.Scale
+	var t float32
+	if maxOffset := s.palette.MaxOffset(); maxOffset > 0 {
+		t = float32(s.palette.Offset) / float32(maxOffset)
+	}
+	ui.DrawFilledRect(trackX, trackY+t*(trackH-thumbH), 4*s.Scale, thumbH, mgl32.Vec3{0.95, 0.95, 0.95}, 1.0)
+}
+
+func (s *CreativeScreen) Render(mouseX, mouseY float64) {
+	s.ContainerScreen.Render(mouseX, mouseY)
+
+	s.HUD.fontRenderer.Render("Creative", s.X+8*s.Scale, s.Y+6*s.Scale, 0.35, mgl32.Vec3{0.25, 0.25, 0.25})
+}
+
+// HandleScroll scrolls the palette a row per notch of the wheel.
+func (s *CreativeScreen) HandleScroll(yoff float64) {
+	switch {
+	case yoff > 0:
+		s.palette.Scroll(-1)
+	case yoff < 0:
+		s.palette.Scroll(1)
+	}
+}
@@ -0,0 +1,269 @@
+package hud
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"runtime"
+	"time"
+
+	"mini-mc/internal/config"
+	"mini-mc/internal/player"
+	"mini-mc/internal/profiling"
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// The debug screen (F3) takes the place of the corner widgets: where the
+// player is and what is around them down the left, the process and the GPU
+// down the right, and a pie of where the renderer's time goes in the bottom
+// right corner, drawn from the profiling.Track sections under pieRoot.
+const (
+	pieRoot      = "renderer."
+	pieCells     = 48 // rows of rects across the pie
+	memStatsTime = time.Second
+)
+
+// Towards which way along an axis each facingNames point looks.
+var facingAxes = [4]string{"positive X", "positive Z", "negative X", "negative Z"}
+
+// debugScreen is what the debug screen keeps between frames.
+type debugScreen struct {
+	shown bool
+
+	gl       [3]string // renderer, vendor and version, read on first show
+	mem      runtime.MemStats
+	memRead  time.Time
+	sections []profiling.Section
+
+	// Sections loaded and the bytes their blocks take, read with mem
+	storedSections, storedBytes int
+}
+
+// ToggleDebugScreen shows or hides the debug screen.
+func (h *HUD) ToggleDebugScreen() {
+	h.debug.shown = !h.debug.shown
+}
+
+// renderDebugScreen draws the debug screen and returns the y of the line
+// below the left column, where the profiling lines carry on.
+func (h *HUD) renderDebugScreen(p *player.Player, w *world.World) float32 {
+	d := &h.debug
+	if d.gl[0] == "" {
+		d.gl = [3]string{
+			gl.GoStr(gl.GetString(gl.RENDERER)),
+			gl.GoStr(gl.GetString(gl.VENDOR)),
+			gl.GoStr(gl.GetString(gl.VERSION)),
+		}
+	}
+	// Reading the memory stats stops the world, so not every frame
+	if time.Since(d.memRead) >= memStatsTime {
+		runtime.ReadMemStats(&d.mem)
+		d.storedSections, d.storedBytes = w.SectionStorage()
+		d.memRead = time.Now()
+	}
+
+	ts := config.GetHUDTextScale()
+	scale := 0.3 * ts
+	lineStep := 14 * ts
+	margin := 2 * ts
+	left := h.debugLeftLines(p, w)
+	h.renderDebugColumn(left, margin, margin, scale, lineStep, false)
+	h.renderDebugColumn(h.debugRightLines(), h.width-margin, margin, scale, lineStep, true)
+	h.renderProfilerPie(ts)
+	return margin + float32(len(left)+1)*lineStep
+}
+
+// debugLeftLines returns the lines about the player and the world around
+// them.
+func (h *HUD) debugLeftLines(p *player.Player, w *world.World) []string {
+	pos := p.Position
+	bx := int(math.Floor(float64(pos[0])))
+	by := int(math.Floor(float64(pos[1])))
+	bz := int(math.Floor(float64(pos[2])))
+	yaw := math.Mod(p.CamYaw, 360)
+	if yaw < 0 {
+		yaw += 360
+	}
+	facing := int(math.Floor(yaw/90+0.5)) & 3
+
+	lines := []string{
+		fmt.Sprintf("mini-mc (%d fps)", h.currentFPS),
+		"",
+		fmt.Sprintf("XYZ: %.3f / %.5f / %.3f", pos[0], pos[1], pos[2]),
+		fmt.Sprintf("Block: %d %d %d", bx, by, bz),
+		fmt.Sprintf("Chunk: %d %d %d in %d %d %d",
+			bx&(world.ChunkSizeX-1), by&(world.SectionHeight-1), bz&(world.ChunkSizeZ-1),
+			bx>>4, by>>4, bz>>4),
+		fmt.Sprintf("Facing: %s (Towards %s) (%.1f / %.1f)", facingNames[facing], facingAxes[facing], yaw, p.CamPitch),
+		fmt.Sprintf("Biome: %s", world.GetBiomeForCoords(float64(bx), float64(bz), w.Seed()).Name),
+		fmt.Sprintf("Light: %d sky, %d block (sky darkened by %.0f)",
+			w.SkyLight(bx, by, bz), w.BlockLight(bx, by, bz), w.SkyDarkening()),
+		fmt.Sprintf("Day %d, %s", w.Day()+1, w.Clock()),
+	}
+	if p.HasHoveredBlock {
+		b := p.HoveredBlock
+		name := "unknown"
+		if def := registry.BlockDefs[w.Get(b[0], b[1], b[2])]; def != nil {
+			name = def.Name
+		}
+		lines = append(lines, fmt.Sprintf("Looking at: %s %d %d %d", name, b[0], b[1], b[2]))
+	}
+	return lines
+}
+
+// debugRightLines returns the lines about the process and the GPU.
+func (h *HUD) debugRightLines() []string {
+	d := &h.debug
+	ps := &h.profilingStats
+	const mb = 1 << 20
+	return []string{
+		fmt.Sprintf("%s %s, %d CPUs", runtime.Version(), runtime.GOARCH, runtime.NumCPU()),
+		fmt.Sprintf("Mem: %d%% %d/%dMB", d.mem.HeapAlloc*100/max(d.mem.HeapSys, 1), d.mem.HeapAlloc/mb, d.mem.HeapSys/mb),
+		fmt.Sprintf("Allocated: %dMB from the OS, %d GCs", d.mem.Sys/mb, d.mem.NumGC),
+		fmt.Sprintf("Blocks: %dMB in %d sections", d.storedBytes/mb, d.storedSections),
+		fmt.Sprintf("Goroutines: %d", runtime.NumGoroutine()),
+		"",
+		fmt.Sprintf("Display: %.0fx%.0f", h.width, h.height),
+		d.gl[0],
+		d.gl[1],
+		d.gl[2],
+		fmt.Sprintf("Mesh memory: %dMB GPU, %dMB CPU", ps.meshAtlasBytes/mb, ps.meshCPUBytes/mb),
+	}
+}
+
+// renderDebugColumn draws lines down from y on a dark backing, starting at x
+// or ending there when right is set. Blank lines leave a gap.
+func (h *HUD) renderDebugColumn(lines []string, x, y, scale, lineStep float32, right bool) {
+	pad := 2 * scale / 0.3
+	for _, line := range lines {
+		if line != "" {
+			tw, _ := h.uiRenderer.MeasureText(line, scale)
+			lx := x
+			if right {
+				lx = x - tw - 2*pad
+			}
+			h.uiRenderer.DrawFilledRect(lx, y, tw+2*pad, lineStep, mgl32.Vec3{0.3, 0.3, 0.3}, 0.55)
+			h.uiRenderer.DrawText(line, lx+pad, y+lineStep*0.75, scale, mgl32.Vec3{0.88, 0.88, 0.88})
+		}
+		y += lineStep
+	}
+}
+
+// renderProfilerPie draws the share of the render time each section of
+// pieRoot takes as a pie in the bottom right corner, with a legend under it.
+func (h *HUD) renderProfilerPie(ts float32) {
+	d := &h.debug
+	d.sections = append(d.sections[:0], profiling.Breakdown(pieRoot)...)
+	total := h.profilingStats.avgFrameTime
+	var tracked time.Duration
+	for _, s := range d.sections {
+		tracked += s.Time
+	}
+	if other := total - tracked; other > 0 {
+		d.sections = append(d.sections, profiling.Section{Name: "other", Time: other})
+	}
+	total = max(total, tracked)
+	if total <= 0 {
+		return
+	}
+
+	scale := 0.3 * ts
+	lineStep := 13 * ts
+	radius := 60 * ts
+	margin := 10 * ts
+	legendH := float32(len(d.sections)) * lineStep
+	cx := h.width - margin - 2*radius - 60*ts
+	top := h.height - margin - legendH - 2*radius - lineStep
+
+	shares := make([]float32, len(d.sections))
+	for i, s := range d.sections {
+		shares[i] = float32(s.Time) / float32(total)
+	}
+	panelW := 2*radius + 120*ts
+	h.uiRenderer.DrawFilledRect(cx-60*ts, top-lineStep, panelW, h.height-top+lineStep, mgl32.Vec3{0, 0, 0}, 0.5)
+	title := fmt.Sprintf("%s %.2fms", pieRoot[:len(pieRoot)-1], float64(total.Microseconds())/1000)
+	h.uiRenderer.DrawText(title, cx-56*ts, top-lineStep*0.2, scale, mgl32.Vec3{1, 1, 1})
+
+	cell := 2 * radius / pieCells
+	for _, span := range pieSpans(pieCells, shares) {
+		color := sectionColor(d.sections[span.section].Name)
+		h.uiRenderer.DrawFilledRect(cx+float32(span.x)*cell, top+float32(span.y)*cell, float32(span.w)*cell, cell, color, 1)
+	}
+
+	y := top + 2*radius + lineStep
+	for i, s := range d.sections {
+		color := sectionColor(s.Name)
+		h.uiRenderer.DrawFilledRect(cx-56*ts, y+lineStep*0.15, lineStep*0.7, lineStep*0.7, color, 1)
+		text := fmt.Sprintf("%s %.1f%% %.2fms", s.Name, shares[i]*100, float64(s.Time.Microseconds())/1000)
+		h.uiRenderer.DrawText(text, cx-56*ts+lineStep, y+lineStep*0.8, scale, color)
+		y += lineStep
+	}
+}
+
+// pieSpan is a run of cells along a row of a pie, all in one section.
+type pieSpan struct {
+	x, y, w int
+	section int
+}
+
+// pieSpans cuts a pie cells across into runs along each row, each cell
+// going to the section whose slice holds its centre. Slices go clockwise
+// from the top in order, sized by shares, which add up to 1.
+func pieSpans(cells int, shares []float32) []pieSpan {
+	var spans []pieSpan
+	r := float64(cells) / 2
+	for y := range cells {
+		dy := float64(y) + 0.5 - r
+		cur := pieSpan{section: -1}
+		for x := range cells {
+			dx := float64(x) + 0.5 - r
+			section := -1
+			if dx*dx+dy*dy <= r*r {
+				// Clockwise from the top; y runs down the screen
+				angle := math.Atan2(dx, -dy)
+				if angle < 0 {
+					angle += 2 * math.Pi
+				}
+				section = pieSection(shares, float32(angle/(2*math.Pi)))
+			}
+			if section == cur.section {
+				cur.w++
+				continue
+			}
+			if cur.section >= 0 {
+				spans = append(spans, cur)
+			}
+			cur = pieSpan{x: x, y: y, w: 1, section: section}
+		}
+		if cur.section >= 0 {
+			spans = append(spans, cur)
+		}
+	}
+	return spans
+}
+
+// pieSection returns which slice turn, the fraction of the way round,
+// falls in.
+func pieSection(shares []float32, turn float32) int {
+	var end float32
+	for i, s := range shares {
+		end += s
+		if turn < end {
+			return i
+		}
+	}
+	return len(shares) - 1
+}
+
+// sectionColor returns the colour a section always has in the pie, from a
+// hash of its name as MC's profiler does.
+func sectionColor(name string) mgl32.Vec3 {
+	f := fnv.New32a()
+	f.Write([]byte(name))
+	c := f.Sum32()&0xaaaaaa + 0x444444
+	return mgl32.Vec3{float32(c>>16&0xff) / 255, float32(c>>8&0xff) / 255, float32(c&0xff) / 255}
+}
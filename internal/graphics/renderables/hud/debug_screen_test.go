@@ -0,0 +1,52 @@
+package hud
+
+import "testing"
+
+func TestPieSpans(t *testing.T) {
+	const cells = 40
+	shares := []float32{0.5, 0.25, 0.25}
+	area := make([]int, len(shares))
+	covered := make(map[[2]int]bool)
+	for _, s := range pieSpans(cells, shares) {
+		area[s.section] += s.w
+		for x := s.x; x < s.x+s.w; x++ {
+			if covered[[2]int{x, s.y}] {
+				t.Fatalf("cell %d,%d drawn twice", x, s.y)
+			}
+			covered[[2]int{x, s.y}] = true
+		}
+	}
+
+	total := area[0] + area[1] + area[2]
+	for i, s := range shares {
+		got := float32(area[i]) / float32(total)
+		if got < s-0.02 || got > s+0.02 {
+			t.Errorf("section %d covers %.3f of the pie, want %.2f", i, got, s)
+		}
+	}
+	// Clockwise from the top: the first half is the right, the last quarter
+	// the top left
+	cases := []struct {
+		x, y, section int
+	}{
+		{cells - 3, cells / 2, 0},
+		{cells/2 - 4, cells - 3, 1},
+		{2, cells/2 - 4, 2},
+		{cells/2 - 3, 2, 2},
+	}
+	for _, c := range cases {
+		if !covered[[2]int{c.x, c.y}] {
+			t.Errorf("cell %d,%d not drawn", c.x, c.y)
+		}
+	}
+	for _, s := range pieSpans(cells, shares) {
+		for _, c := range cases {
+			if c.y == s.y && c.x >= s.x && c.x < s.x+s.w && s.section != c.section {
+				t.Errorf("cell %d,%d in section %d, want %d", c.x, c.y, s.section, c.section)
+			}
+		}
+	}
+	if covered[[2]int{0, 0}] {
+		t.Error("corner outside the pie drawn")
+	}
+}
@@ -0,0 +1,22 @@
+package hud
+
+import (
+	"mini-mc/internal/item"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// renderDurabilityBar draws the wear bar under a damaged item's icon, laid out
+// like MC's: a 13x2 black track at (2,13) inside the 16px slot with a 1px tall
+// fill that shifts from green to red as durability runs out.
+func (h *HUD) renderDurabilityBar(stack *item.ItemStack, slotX, slotY, scale float32) {
+	if stack == nil || !stack.IsDamaged() {
+		return
+	}
+	frac := stack.DurabilityFraction()
+	x := slotX + 2*scale
+	y := slotY + 13*scale
+	h.uiRenderer.DrawFilledRect(x, y, 13*scale, 2*scale, mgl32.Vec3{0, 0, 0}, 1.0)
+	color := mgl32.Vec3{1 - frac, frac, 0}
+	h.uiRenderer.DrawFilledRect(x, y, float32(int(13*frac+0.5))*scale, scale, color, 1.0)
+}
@@ -0,0 +1,26 @@
+package hud
+
+import (
+	"mini-mc/internal/config"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// SetFade sets the opacity of a black overlay over the world and HUD, from
+// 0 (hidden) to 1. With loading set a terrain loading message is shown on it.
+func (h *HUD) SetFade(alpha float32, loading bool) {
+	h.fade = mgl32.Clamp(alpha, 0, 1)
+	h.fadeLoading = loading
+}
+
+// renderFade draws the fade overlay and its loading message.
+func (h *HUD) renderFade() {
+	h.uiRenderer.DrawFilledRect(0, 0, h.width, h.height, mgl32.Vec3{0, 0, 0}, h.fade)
+	if !h.fadeLoading {
+		return
+	}
+	text := "Loading terrain..."
+	scale := 0.4 * config.GetHUDTextScale()
+	tw, th := h.uiRenderer.MeasureText(text, scale)
+	h.uiRenderer.DrawText(text, (h.width-tw)/2, (h.height-th)/2, scale, mgl32.Vec3{1, 1, 1})
+}
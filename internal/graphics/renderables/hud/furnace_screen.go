@@ -0,0 +1,66 @@
+package hud
+
+import (
+	"fmt"
+	"mini-mc/internal/blockentity"
+	"mini-mc/internal/graphics"
+	"mini-mc/internal/inventory"
+	"mini-mc/internal/player"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// FurnaceScreen shows a furnace's input, fuel and output slots with the
+// burn flame and smelting arrow (MC's GuiFurnace layout).
+type FurnaceScreen struct {
+	*ContainerScreen
+	furnace *blockentity.Furnace
+}
+
+func NewFurnaceScreen(hud *HUD, p *player.Player, f *blockentity.Furnace) *FurnaceScreen {
+	container := inventory.NewFurnaceContainer(p.Inventory, f)
+
+	tex, err := graphics.GetTexture("assets/textures/gui/furnace.png")
+	if err != nil {
+		panic(fmt.Errorf("failed to load furnace texture: %v", err))
+	}
+
+	s := &FurnaceScreen{
+		ContainerScreen: NewContainerScreen(hud, p, container, tex, 176, 166),
+		furnace:         f,
+	}
+	s.drawBackground = s.drawProgress
+	s.Init()
+	return s
+}
+
+// drawProgress overlays the lit part of the flame (sprite at 176,0) and the
+// filled part of the arrow (sprite at 176,14) from the furnace texture.
+func (s *FurnaceScreen) drawProgress() {
+	white := mgl32.Vec3{1, 1, 1}
+	if s.furnace.IsBurning() {
+		// The flame shrinks from the top as fuel burns down
+		h := float32(int(s.furnace.BurnProgress()*13) + 1)
+		s.drawSprite(57, 36+14-h, 176, 14-h, 14, h, white)
+	}
+	if w := float32(int(s.furnace.CookProgress() * 24)); w > 0 {
+		s.drawSprite(79, 34, 176, 14, w, 17, white)
+	}
+}
+
+// drawSprite draws a w x h region at (u,v) in the background texture to
+// (x,y) in screen-texture pixels.
+func (s *FurnaceScreen) drawSprite(x, y, u, v, w, h float32, color mgl32.Vec3) {
+	s.HUD.uiRenderer.DrawTexturedRect(s.X+x*s.Scale, s.Y+y*s.Scale, w*s.Scale, h*s.Scale, s.backgroundTex,
+		u/256, v/256, (u+w)/256, (v+h)/256, color, 1.0)
+}
+
+func (s *FurnaceScreen) Render(mouseX, mouseY float64) {
+	s.ContainerScreen.Render(mouseX, mouseY)
+
+	labelColor := mgl32.Vec3{0.25, 0.25, 0.25}
+	title := "Furnace"
+	w, _ := s.HUD.fontRenderer.Measure(title, 0.35)
+	s.HUD.fontRenderer.Render(title, s.X+s.Width/2-w/2, s.Y+6*s.Scale, 0.35, labelColor)
+	s.HUD.fontRenderer.Render("Inventory", s.X+8*s.Scale, s.Y+72*s.Scale, 0.35, labelColor)
+}
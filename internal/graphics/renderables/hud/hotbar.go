@@ -2,6 +2,8 @@ package hud
 
 import (
 	"fmt"
+	"log/slog"
+	"mini-mc/internal/config"
 	"mini-mc/internal/graphics"
 	"mini-mc/internal/registry"
 
@@ -18,7 +20,7 @@ func (h *HUD) renderHotbar(p *player.Player) {
 	screenWidth := h.width
 	screenHeight := h.height
 
-	scale := float32(2.0)
+	scale := float32(config.GetGUIScale())
 	hbW := 182 * scale
 	hbH := 22 * scale
 
@@ -33,7 +35,7 @@ func (h *HUD) renderHotbar(p *player.Player) {
 	texWidgets, err := graphics.GetTexture("assets/textures/gui/widgets.png")
 	if err != nil {
 		// Log error or fallback?
-		fmt.Printf("Error loading widgets texture: %v\n", err)
+		slog.Error("loading widgets texture failed", "err", err)
 	}
 
 	h.uiRenderer.DrawTexturedRect(x, y, hbW, hbH, texWidgets, 0, 0, u1, v1, color, 1.0)
@@ -59,7 +61,7 @@ func (h *HUD) renderHotbar(p *player.Player) {
 	selU1 := float32(24) / 256.0
 	selV1 := float32(22+24) / 256.0
 
-	h.uiRenderer.DrawTexturedRect(selXScreen, selYScreen, selW, selH, texWidgets, selU0, selV0, selU1, selV1, color, 1.0)
+	h.uiRenderer.DrawTexturedRect(selXScreen, selYScreen, selW, selH, texWidgets, selU0, selV0, selU1, selV1, currentPalette().selection, 1.0)
 
 	// IMPORTANT: UI quads are FIFO-batched; flush backgrounds before rendering items/text so they don't draw over items.
 	h.uiRenderer.Flush()
@@ -88,6 +90,7 @@ func (h *HUD) renderHotbar(p *player.Player) {
 
 			// Render Item with animation scale
 			h.itemRenderer.RenderGUIScaled(stack, slotX, slotY, renderWidth, renderHeight)
+			h.renderDurabilityBar(stack, baseSlotX, baseSlotY, scale)
 
 			// Render Count if > 1 (always at base position)
 			if stack.Count > 1 {
@@ -108,9 +111,10 @@ func (h *HUD) renderHotbar(p *player.Player) {
 			name = def.Name
 		}
 		// Center text
-		w, _ := h.fontRenderer.Measure(name, 0.4)
+		size := 0.4 * config.GetHUDTextScale()
+		w, _ := h.fontRenderer.Measure(name, size)
 		tx := (screenWidth - w) / 2
 		ty := y - 60
-		h.fontRenderer.Render(name, tx, ty, 0.4, mgl32.Vec3{1, 1, 1})
+		h.fontRenderer.Render(name, tx, ty, size, mgl32.Vec3{1, 1, 1})
 	}
 }
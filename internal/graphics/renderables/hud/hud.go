@@ -1,13 +1,19 @@
 package hud
 
 import (
+	"mini-mc/internal/blockentity"
+	"mini-mc/internal/command"
 	"mini-mc/internal/graphics/renderables/font"
 	"mini-mc/internal/graphics/renderables/items"
 	"mini-mc/internal/graphics/renderables/playermodel"
 	"mini-mc/internal/graphics/renderables/ui"
 	"mini-mc/internal/graphics/renderer"
+	"mini-mc/internal/logging"
+	"mini-mc/internal/netsim"
 	"mini-mc/internal/player"
+	"mini-mc/internal/presence"
 	"mini-mc/internal/profiling"
+	"mini-mc/internal/world"
 	"path/filepath"
 	"time"
 
@@ -22,7 +28,22 @@ type HUD struct {
 	uiRenderer    *ui.UI
 	itemRenderer  *items.Items
 	playerModel   *playermodel.PlayerModel
+	lowHealth     lowHealthBorder
 	showProfiling bool
+	showLogViewer bool
+	logEntries    []logging.Entry       // reused by renderLogViewer
+	pregen        *world.PregenProgress // nil unless a pregeneration job is shown
+	growth        *world.RingProgress   // nil unless a render distance increase is loading
+	fade          float32               // black overlay opacity while teleporting
+	fadeLoading   bool                  // fade is waiting on terrain
+	playerList    []presence.Entry      // shown while the list key is held
+	showNetGraph  bool
+	netStats      *netsim.Stats // nil while not connected
+	nowPlaying    string        // music track in the toast; "" when none is shown
+	nowPlayingAt  time.Time
+	chatLines     []chatLine      // oldest first
+	chatPrompt    *command.Prompt // nil while the chat is closed
+	debug         debugScreen
 
 	// Viewport dimensions
 	width  float32
@@ -32,6 +53,7 @@ type HUD struct {
 	frames       int
 	lastFPSCheck time.Time
 	currentFPS   int
+	debugTop     float32 // y of the first debug line, under the top left widgets
 
 	// Enhanced profiling metrics
 	profilingStats ProfilingStats
@@ -54,7 +76,17 @@ func NewHUD() *HUD {
 func (h *HUD) SetInventoryOpen(open bool, p *player.Player) {
 	if open {
 		if !h.currentScreen.IsActive() {
-			h.currentScreen = NewInventoryScreen(h, p)
+			furnace, isFurnace := p.OpenBlockEntity.(*blockentity.Furnace)
+			switch {
+			case isFurnace:
+				h.currentScreen = NewFurnaceScreen(h, p, furnace)
+			case p.Workbench != nil:
+				h.currentScreen = NewCraftingTableScreen(h, p, p.Workbench)
+			case p.GameMode == player.GameModeCreative:
+				h.currentScreen = NewCreativeScreen(h, p)
+			default:
+				h.currentScreen = NewInventoryScreen(h, p)
+			}
 		}
 	} else {
 		if h.currentScreen.IsActive() {
@@ -104,6 +136,10 @@ func (h *HUD) Init() error {
 	}
 	h.playerModel = playerModel
 
+	if err := h.lowHealth.init(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -116,8 +152,15 @@ func (h *HUD) Render(ctx renderer.RenderContext) {
 		h.frames = 0
 	}
 
+	// Low-health border sits underneath the rest of the HUD
+	h.renderLowHealthBorder(ctx.Player)
+
 	// Render World-Level HUD elements (Hotbar, Health, Food) which should be dimmed by menus
 	h.renderHotbar(ctx.Player)
+	h.renderSprintStatus(ctx.Player)
+	if ctx.Player.BuilderActive() {
+		h.renderBuilderStatus(ctx.Player)
+	}
 	if ctx.Player.GameMode != player.GameModeCreative {
 		h.renderHealth(ctx.Player)
 		h.renderFood(ctx.Player)
@@ -135,15 +178,48 @@ func (h *HUD) Render(ctx renderer.RenderContext) {
 		}
 	}
 
-	// Render Debug Info (FPS, Coords) - Always on top
-	h.renderPlayerPosition(ctx.Player)
-	h.renderFPS()
+	h.renderChat()
+
+	if len(h.playerList) > 0 {
+		h.renderPlayerList()
+	}
+
+	if h.nowPlaying != "" {
+		h.renderNowPlaying()
+	}
+
+	if h.fade > 0 {
+		h.renderFade()
+	}
+
+	// Info widgets (coordinates, clock, FPS), or the debug screen in their
+	// place, and debug info - always on top
+	if h.debug.shown {
+		h.debugTop = h.renderDebugScreen(ctx.Player, ctx.World)
+	} else {
+		h.debugTop = h.renderWidgets(ctx.Player, ctx.World)
+	}
+	if h.growth != nil {
+		h.renderRenderDistanceGrowth()
+	}
+
+	if h.pregen != nil {
+		h.renderPregenProgress()
+	}
+
+	if h.showLogViewer {
+		h.renderLogViewer()
+	}
+
+	if h.showNetGraph {
+		h.renderNetGraph()
+	}
 
 	// Render profiling info if enabled
 	if h.showProfiling {
 		func() {
 			defer profiling.Track("renderer.hud")()
-			h.RenderProfilingInfo()
+			h.RenderProfilingInfo(ctx.Player)
 		}()
 	}
 
@@ -155,8 +231,18 @@ func (h *HUD) HandleInventoryClick(x, y float64, button glfw.MouseButton, action
 	return h.currentScreen.HandleClick(x, y, button, action)
 }
 
-// MoveHoveredItemToHotbar moves the hovered item to the specified hotbar slot
-func (h *HUD) MoveHoveredItemToHotbar(hotbarSlot int) {
+// HandleInventoryScroll passes the mouse wheel to the open screen, if it
+// scrolls.
+func (h *HUD) HandleInventoryScroll(yoff float64) {
+	if s, ok := h.currentScreen.(interface{ HandleScroll(yoff float64) }); ok {
+		s.HandleScroll(yoff)
+	}
+}
+
+// MoveHoveredItemToHotbar moves the hovered item to the specified hotbar
+// slot, swapping it with what is there. Output slots can only go to an empty
+// hotbar slot.
+func (h *HUD) MoveHoveredItemToHotbar(p *player.Player, hotbarSlot int) {
 	hoveredSlot := h.currentScreen.GetHoveredSlot()
 	if hoveredSlot == -1 {
 		return
@@ -167,20 +253,20 @@ func (h *HUD) MoveHoveredItemToHotbar(hotbarSlot int) {
 		return
 	}
 
-	targetSlotIndex := 27 + hotbarSlot
-
-	if hoveredSlot < 0 || hoveredSlot >= len(container.Slots) {
-		return
-	}
-	if targetSlotIndex < 0 || targetSlotIndex >= len(container.Slots) {
+	sourceSlot := container.GetSlot(hoveredSlot)
+	targetSlot := container.HotbarSlot(p.Inventory, hotbarSlot)
+	if sourceSlot == nil || targetSlot == nil || sourceSlot == targetSlot {
 		return
 	}
 
-	sourceSlot := container.Slots[hoveredSlot]
-	targetSlot := container.Slots[targetSlotIndex]
-
 	sourceStack := sourceSlot.GetStack()
 	targetStack := targetSlot.GetStack()
+	if sourceSlot.IsOutput() {
+		if sourceStack != nil && targetStack == nil {
+			targetSlot.PutStack(sourceSlot.TakeOutput())
+		}
+		return
+	}
 
 	// Swap them
 	sourceSlot.PutStack(targetStack)
@@ -193,6 +279,7 @@ func (h *HUD) Dispose() {
 	if h.playerModel != nil {
 		h.playerModel.Dispose()
 	}
+	h.lowHealth.dispose()
 }
 
 // RenderText renders text using the font renderer
@@ -210,6 +297,11 @@ func (h *HUD) FontRenderer() *font.FontRenderer {
 	return h.fontRenderer
 }
 
+// Stage draws the HUD over the finished world.
+func (h *HUD) Stage() renderer.Stage {
+	return renderer.StageOverlay
+}
+
 // SetViewport updates the HUD viewport dimensions
 func (h *HUD) SetViewport(width, height int) {
 	h.width = float32(width)
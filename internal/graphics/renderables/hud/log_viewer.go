@@ -0,0 +1,57 @@
+package hud
+
+import (
+	"log/slog"
+
+	"mini-mc/internal/config"
+	"mini-mc/internal/logging"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// logViewerLines is how many of the most recent records the viewer shows.
+const logViewerLines = 20
+
+// ToggleLogViewer toggles the recent-log overlay
+func (h *HUD) ToggleLogViewer() {
+	h.showLogViewer = !h.showLogViewer
+}
+
+// renderLogViewer draws the latest info-and-above log records over the
+// bottom of the screen, coloured by level.
+func (h *HUD) renderLogViewer() {
+	h.logEntries = logging.Recent(h.logEntries[:0], slog.LevelInfo)
+	entries := h.logEntries
+	if len(entries) > logViewerLines {
+		entries = entries[len(entries)-logViewerLines:]
+	}
+
+	ts := config.GetHUDTextScale()
+	scale := 0.3 * ts
+	lineStep := float32(14) * ts
+	panelH := lineStep*logViewerLines + 8*ts
+	top := h.height - panelH - 60*ts // keep clear of the hotbar
+	h.uiRenderer.DrawFilledRect(0, top, h.width, panelH, mgl32.Vec3{0, 0, 0}, 0.6)
+
+	if len(entries) == 0 {
+		h.uiRenderer.DrawText("No log messages", 8, top+lineStep, scale, mgl32.Vec3{0.6, 0.6, 0.6})
+		return
+	}
+	y := top + lineStep
+	for _, e := range entries {
+		text := e.Time.Format("15:04:05") + " " + e.Level.String() + " " + e.Message
+		h.uiRenderer.DrawText(text, 8, y, scale, logLevelColor(e.Level))
+		y += lineStep
+	}
+}
+
+func logLevelColor(l slog.Level) mgl32.Vec3 {
+	switch {
+	case l >= slog.LevelError:
+		return mgl32.Vec3{1.0, 0.33, 0.33}
+	case l >= slog.LevelWarn:
+		return mgl32.Vec3{1.0, 0.85, 0.3}
+	default:
+		return mgl32.Vec3{0.85, 0.85, 0.85}
+	}
+}
@@ -0,0 +1,81 @@
+package hud
+
+import (
+	"mini-mc/internal/config"
+	"mini-mc/internal/graphics"
+	"mini-mc/internal/player"
+	"path/filepath"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+var (
+	vignetteVertShader = filepath.Join("assets", "shaders", "hud", "vignette.vert")
+	vignetteFragShader = filepath.Join("assets", "shaders", "hud", "vignette.frag")
+)
+
+const (
+	lowHealthInnerRadius = 0.6  // where the tint starts, with the screen edges at 1
+	lowHealthBaseAlpha   = 0.18 // steady tint at zero health
+	lowHealthPulseAlpha  = 0.30 // extra tint at the peak of a heartbeat
+)
+
+// lowHealthBorder is the full-screen pass behind renderLowHealthBorder.
+type lowHealthBorder struct {
+	shader *graphics.Shader
+	vao    uint32 // empty; the vertex shader makes a full-screen triangle
+}
+
+func (b *lowHealthBorder) init() error {
+	var err error
+	b.shader, err = graphics.NewShader(vignetteVertShader, vignetteFragShader)
+	if err != nil {
+		return err
+	}
+	gl.GenVertexArrays(1, &b.vao)
+	return nil
+}
+
+func (b *lowHealthBorder) dispose() {
+	if b.vao != 0 {
+		gl.DeleteVertexArrays(1, &b.vao)
+	}
+}
+
+// renderLowHealthBorder draws a red vignette over the screen while the
+// player is at low health, clear in the middle and fading in radially
+// towards the edges. It is brightened by the heartbeat pulse so the border
+// throbs in time with OnHeartbeat. Nothing is drawn with the low-health
+// effect turned off. It draws straight away rather than through the UI
+// queue, so call it before anything is queued to keep it under the HUD.
+func (h *HUD) renderLowHealthBorder(p *player.Player) {
+	if !config.GetLowHealthEffect() {
+		return
+	}
+	intensity := p.LowHealthIntensity()
+	if intensity <= 0 {
+		return
+	}
+	pulse := p.HeartbeatPulse()
+	if config.GetDisableFlashing() {
+		// Hold the border at its mid-pulse brightness instead of throbbing.
+		pulse = 0.5
+	}
+	alpha := intensity * (lowHealthBaseAlpha + lowHealthPulseAlpha*pulse)
+	color := currentPalette().danger
+
+	s := h.lowHealth.shader
+	s.Use()
+	s.SetVector3("color", color.X(), color.Y(), color.Z())
+	s.SetFloat("alpha", alpha)
+	s.SetFloat("inner", lowHealthInnerRadius)
+
+	gl.Disable(gl.DEPTH_TEST)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.BindVertexArray(h.lowHealth.vao)
+	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	gl.BindVertexArray(0)
+	gl.Disable(gl.BLEND)
+	gl.Enable(gl.DEPTH_TEST)
+}
@@ -0,0 +1,81 @@
+package hud
+
+import (
+	"fmt"
+	"time"
+
+	"mini-mc/internal/config"
+	"mini-mc/internal/netsim"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// ToggleNetGraph toggles the network graph
+func (h *HUD) ToggleNetGraph() {
+	h.showNetGraph = !h.showNetGraph
+}
+
+// SetNetStats sets the connection the network graph shows; nil while not
+// connected to a server.
+func (h *HUD) SetNetStats(s *netsim.Stats) {
+	h.netStats = s
+}
+
+// renderNetGraph draws the traffic of the last netsim.HistoryLength
+// intervals as bars, bytes in above the axis and bytes out below, with the
+// latest RTT and rates underneath, in the bottom-right corner. The network
+// being simulated, if any, is named above it.
+func (h *HUD) renderNetGraph() {
+	ts := config.GetHUDTextScale()
+	scale := 0.3 * ts
+	lineStep := 12 * ts
+	barW := 2 * ts
+	graphW := float32(netsim.HistoryLength) * barW
+	graphH := 40 * ts
+	x := h.width - graphW - 8*ts
+	y := h.height - graphH - 3*lineStep - 60*ts // keep clear of the hotbar
+	white := mgl32.Vec3{1, 1, 1}
+
+	h.uiRenderer.DrawFilledRect(x-4*ts, y-4*ts, graphW+8*ts, graphH+3*lineStep+8*ts, mgl32.Vec3{0, 0, 0}, 0.5)
+	if h.netStats == nil {
+		h.uiRenderer.DrawText("Not connected", x, y+lineStep, scale, white)
+		return
+	}
+
+	samples := h.netStats.Samples(time.Now())
+	peak := float64(1024) // 1 KB/s keeps a quiet connection from filling the graph
+	for _, s := range samples {
+		peak = max(peak, s.BytesIn, s.BytesOut)
+	}
+	mid := y + graphH/2
+	start := x + graphW - float32(len(samples))*barW // newest at the right
+	for i, s := range samples {
+		bx := start + float32(i)*barW
+		in := float32(s.BytesIn/peak) * graphH / 2
+		out := float32(s.BytesOut/peak) * graphH / 2
+		h.uiRenderer.DrawFilledRect(bx, mid-in, barW, in, mgl32.Vec3{0.3, 0.85, 0.3}, 0.9)
+		h.uiRenderer.DrawFilledRect(bx, mid, barW, out, mgl32.Vec3{0.35, 0.6, 1.0}, 0.9)
+	}
+	h.uiRenderer.DrawFilledRect(x, mid, graphW, ts/2, white, 0.4)
+
+	var last netsim.Sample
+	if len(samples) > 0 {
+		last = samples[len(samples)-1]
+	}
+	rtt := "RTT -"
+	if d := h.netStats.RTT(); d > 0 {
+		rtt = "RTT " + d.Round(time.Millisecond).String()
+	}
+	ty := y + graphH + lineStep
+	h.uiRenderer.DrawText(rtt, x, ty, scale, white)
+	h.uiRenderer.DrawText(fmt.Sprintf("in  %.0f/s %.1f KB/s", last.PacketsIn, last.BytesIn/1024), x, ty+lineStep, scale, mgl32.Vec3{0.3, 0.85, 0.3})
+	h.uiRenderer.DrawText(fmt.Sprintf("out %.0f/s %.1f KB/s", last.PacketsOut, last.BytesOut/1024), x, ty+2*lineStep, scale, mgl32.Vec3{0.35, 0.6, 1.0})
+	if sim := netsim.Configured(); sim.Enabled() {
+		// Above the graph, as it is wider
+		text := "Simulating " + sim.String()
+		tw, _ := h.uiRenderer.MeasureText(text, scale)
+		tx := x + graphW - tw
+		h.uiRenderer.DrawFilledRect(tx-4*ts, y-4*ts-lineStep, tw+8*ts, lineStep, mgl32.Vec3{0, 0, 0}, 0.5)
+		h.uiRenderer.DrawText(text, tx, y-6*ts, scale, mgl32.Vec3{1.0, 0.85, 0.3})
+	}
+}
@@ -0,0 +1,44 @@
+package hud
+
+import (
+	"time"
+
+	"mini-mc/internal/config"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// nowPlayingDuration is how long the now-playing toast shows, the last
+// second fading out.
+const nowPlayingDuration = 5 * time.Second
+
+// ShowNowPlaying shows a toast naming the music track that started.
+func (h *HUD) ShowNowPlaying(track string) {
+	h.nowPlaying = track
+	h.nowPlayingAt = time.Now()
+}
+
+// renderNowPlaying draws the now-playing toast in the top-right corner.
+func (h *HUD) renderNowPlaying() {
+	age := time.Since(h.nowPlayingAt)
+	if age >= nowPlayingDuration {
+		h.nowPlaying = ""
+		return
+	}
+	alpha := float32(min(1, (nowPlayingDuration - age).Seconds()))
+
+	ts := config.GetHUDTextScale()
+	scale := 0.3 * ts
+	pad := 6 * ts
+	title := "Now playing"
+	tw, th := h.uiRenderer.MeasureText(h.nowPlaying, scale)
+	titleW, _ := h.uiRenderer.MeasureText(title, scale)
+	w := max(tw, titleW) + 2*pad
+	boxH := 2*th + 3*pad
+	x := h.width - w - 8*ts
+	y := 8 * ts
+
+	h.uiRenderer.DrawFilledRect(x, y, w, boxH, mgl32.Vec3{0, 0, 0}, 0.6*alpha)
+	h.uiRenderer.DrawText(title, x+pad, y+pad+th, scale, mgl32.Vec3{1.0, 0.85, 0.3}.Mul(alpha))
+	h.uiRenderer.DrawText(h.nowPlaying, x+pad, y+2*pad+2*th, scale, mgl32.Vec3{1, 1, 1}.Mul(alpha))
+}
@@ -0,0 +1,55 @@
+package hud
+
+import (
+	"mini-mc/internal/config"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// hudPalette holds the colour-coded HUD colours for one colorblind mode.
+type hudPalette struct {
+	// heartU is the icons.png column of the full heart (the half heart is 9px to its right).
+	heartU float32
+	// selection tints the hotbar selection frame.
+	selection mgl32.Vec3
+	// danger is the low-health border colour.
+	danger mgl32.Vec3
+}
+
+// Heart columns in icons.png (MC 1.8 layout).
+const (
+	heartUNormal     = 52.0  // red
+	heartUAbsorption = 160.0 // gold
+)
+
+var hudPalettes = [config.ColorblindModeCount]hudPalette{
+	config.ColorblindOff: {
+		heartU:    heartUNormal,
+		selection: mgl32.Vec3{1.0, 1.0, 1.0},
+		danger:    mgl32.Vec3{0.7, 0.0, 0.0},
+	},
+	// Red-green deficiencies: red hearts and border read as dark brown, so use
+	// the bright gold hearts and an orange border; tint the selection blue.
+	config.ColorblindDeuteranopia: {
+		heartU:    heartUAbsorption,
+		selection: mgl32.Vec3{0.35, 0.7, 1.0},
+		danger:    mgl32.Vec3{0.9, 0.6, 0.0},
+	},
+	config.ColorblindProtanopia: {
+		heartU:    heartUAbsorption,
+		selection: mgl32.Vec3{0.35, 0.7, 1.0},
+		danger:    mgl32.Vec3{0.95, 0.65, 0.0},
+	},
+	// Blue-yellow deficiency: red is still distinct, but the default white
+	// selection frame washes out against bright skies, so tint it magenta.
+	config.ColorblindTritanopia: {
+		heartU:    heartUNormal,
+		selection: mgl32.Vec3{1.0, 0.4, 0.6},
+		danger:    mgl32.Vec3{0.8, 0.0, 0.1},
+	},
+}
+
+// currentPalette returns the HUD palette for the configured colorblind mode.
+func currentPalette() hudPalette {
+	return hudPalettes[config.GetColorblindMode()]
+}
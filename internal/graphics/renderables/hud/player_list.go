@@ -0,0 +1,58 @@
+package hud
+
+import (
+	"mini-mc/internal/config"
+	"mini-mc/internal/presence"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// SetPlayerList shows the connected players while the list key is held;
+// nil hides the list.
+func (h *HUD) SetPlayerList(entries []presence.Entry) {
+	h.playerList = entries
+}
+
+// renderPlayerList draws the tab list: one row per player, centred at the
+// top of the screen, with their latency as signal bars.
+func (h *HUD) renderPlayerList() {
+	ts := config.GetHUDTextScale()
+	scale := 0.3 * ts
+	rowH := 12 * ts
+	pad := 2 * ts
+	barsW := 12 * ts
+
+	nameW := float32(0)
+	for _, e := range h.playerList {
+		w, _ := h.uiRenderer.MeasureText(e.Name, scale)
+		nameW = max(nameW, w)
+	}
+	rowW := nameW + 4*pad + barsW
+	x := (h.width - rowW) / 2
+	y := 10 * ts
+
+	h.uiRenderer.DrawFilledRect(x-pad, y-pad, rowW+2*pad, float32(len(h.playerList))*rowH+2*pad, mgl32.Vec3{0, 0, 0}, 0.5)
+	for i, e := range h.playerList {
+		rowY := y + float32(i)*rowH
+		h.uiRenderer.DrawFilledRect(x, rowY, rowW, rowH-ts, mgl32.Vec3{1, 1, 1}, 0.13)
+		h.uiRenderer.DrawText(e.Name, x+pad, rowY+rowH*0.75, scale, mgl32.Vec3{1, 1, 1})
+		h.renderSignalBars(x+rowW-barsW-pad, rowY+pad, barsW, rowH-3*ts, e.SignalBars())
+	}
+}
+
+// renderSignalBars draws five bars of rising height in w x hgt, the first
+// n lit; with n 0 all are drawn dark red for an unknown ping.
+func (h *HUD) renderSignalBars(x, y, w, hgt float32, n int) {
+	barW := w / 5
+	for i := range 5 {
+		barH := hgt * float32(i+1) / 5
+		color := mgl32.Vec3{0.2, 0.2, 0.2}
+		switch {
+		case n == 0:
+			color = mgl32.Vec3{0.4, 0.1, 0.1}
+		case i < n:
+			color = mgl32.Vec3{0.3, 0.9, 0.3}
+		}
+		h.uiRenderer.DrawFilledRect(x+float32(i)*barW, y+hgt-barH, barW*0.7, barH, color, 1)
+	}
+}
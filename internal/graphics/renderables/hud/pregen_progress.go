@@ -0,0 +1,50 @@
+package hud
+
+import (
+	"fmt"
+	"time"
+
+	"mini-mc/internal/config"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// SetPregenProgress shows the progress of a world pregeneration job; nil
+// hides the bar.
+func (h *HUD) SetPregenProgress(p *world.PregenProgress) {
+	h.pregen = p
+}
+
+// renderPregenProgress draws a progress bar with the column count and ETA
+// at the top of the screen.
+func (h *HUD) renderPregenProgress() {
+	p := h.pregen
+	ts := config.GetHUDTextScale()
+	barW, barH := 300*ts, 6*ts
+	x := (h.width - barW) / 2
+	y := 30 * ts
+
+	var text string
+	switch {
+	case p.Err != nil:
+		text = "Pregeneration failed: " + p.Err.Error()
+	case p.Finished:
+		text = fmt.Sprintf("Pregenerated %d/%d columns in %s", p.Done, p.Total, p.Elapsed.Round(time.Second))
+	default:
+		text = fmt.Sprintf("Pregenerating %d/%d columns (%.0f%%)", p.Done, p.Total, p.Fraction()*100)
+		if p.Done > 0 {
+			text += ", ETA " + p.ETA().Round(time.Second).String()
+		}
+	}
+	scale := 0.3 * ts
+	tw, _ := h.uiRenderer.MeasureText(text, scale)
+	h.uiRenderer.DrawText(text, (h.width-tw)/2, y-4*ts, scale, mgl32.Vec3{1, 1, 1})
+
+	h.uiRenderer.DrawFilledRect(x, y, barW, barH, mgl32.Vec3{0, 0, 0}, 0.6)
+	color := mgl32.Vec3{0.3, 0.85, 0.3}
+	if p.Err != nil {
+		color = mgl32.Vec3{1.0, 0.33, 0.33}
+	}
+	h.uiRenderer.DrawFilledRect(x, y, barW*float32(p.Fraction()), barH, color, 1)
+}
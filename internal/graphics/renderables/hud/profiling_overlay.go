@@ -6,8 +6,10 @@ import (
 	"strings"
 	"time"
 
+	"mini-mc/internal/config"
 	"mini-mc/internal/player"
 	"mini-mc/internal/profiling"
+	"mini-mc/internal/world"
 
 	"github.com/go-gl/mathgl/mgl32"
 )
@@ -52,6 +54,20 @@ type ProfilingStats struct {
 
 	lastPreRenderDuration  time.Duration
 	lastSwapEventsDuration time.Duration
+
+	enclosedSections int
+	emptyChunkMeshes int
+	occlusionTested  int
+	occludedColumns  int
+
+	meshAtlasBytes  int
+	meshCPUBytes    int
+	meshCapBytes    int
+	meshLimitRadius int
+
+	entityUpdates    world.EntityUpdateStats
+	renderedEntities int
+	distantEntities  int
 }
 
 // Profiling methods for external updates
@@ -80,6 +96,35 @@ func (h *HUD) ProfilingSetPhases(preRender, swapEvents time.Duration) {
 	h.profilingStats.lastSwapEventsDuration = swapEvents
 }
 
+// ProfilingSetCulling stores the mesh culling counters from the block renderer
+func (h *HUD) ProfilingSetCulling(enclosedSections, emptyChunks int) {
+	h.profilingStats.enclosedSections = enclosedSections
+	h.profilingStats.emptyChunkMeshes = emptyChunks
+}
+
+// ProfilingSetOcclusion stores how many columns in the frustum the block
+// renderer tested for occlusion and how many it skipped as hidden
+func (h *HUD) ProfilingSetOcclusion(tested, occluded int) {
+	h.profilingStats.occlusionTested = tested
+	h.profilingStats.occludedColumns = occluded
+}
+
+// ProfilingSetMeshMemory stores the block mesh memory held in the GPU atlas
+// and as CPU copies, its cap, and the radius meshes are limited to (0 for none)
+func (h *HUD) ProfilingSetMeshMemory(atlasBytes, cpuBytes, capBytes, limitRadius int) {
+	h.profilingStats.meshAtlasBytes = atlasBytes
+	h.profilingStats.meshCPUBytes = cpuBytes
+	h.profilingStats.meshCapBytes = capBytes
+	h.profilingStats.meshLimitRadius = limitRadius
+}
+
+// ProfilingSetEntities stores how entities were ticked and drawn this frame
+func (h *HUD) ProfilingSetEntities(updates world.EntityUpdateStats, rendered, distant int) {
+	h.profilingStats.entityUpdates = updates
+	h.profilingStats.renderedEntities = rendered
+	h.profilingStats.distantEntities = distant
+}
+
 // ProfilingSetRenderDuration stores the render() call duration for this frame
 func (h *HUD) ProfilingSetRenderDuration(d time.Duration) {
 	h.profilingStats.frameDuration = d
@@ -110,35 +155,24 @@ func (h *HUD) ProfilingSetRenderDuration(d time.Duration) {
 	h.profilingStats.maxFrameTime = max
 }
 
-func (h *HUD) renderPlayerPosition(p *player.Player) {
-	// Build text and draw at top-left
-	// Calculate horiz speed (m/s)
-	speed := math.Sqrt(float64(p.Velocity[0]*p.Velocity[0] + p.Velocity[2]*p.Velocity[2]))
-
-	chunkX := int(math.Floor(float64(p.Position[0]) / 16))
-	chunkZ := int(math.Floor(float64(p.Position[2]) / 16))
-
-	yawDeg := math.Mod(float64(p.CamYaw), 360)
-	if yawDeg < 0 {
-		yawDeg += 360
-	}
-
-	text := fmt.Sprintf("Pos: %.1f, %.1f, %.1f | Chunk: %d, %d | Yaw: %.0f° | Speed: %.2f", p.Position[0], p.Position[1], p.Position[2], chunkX, chunkZ, yawDeg, speed)
-	color := mgl32.Vec3{1.0, 1.0, 1.0}
-	h.fontRenderer.Render(text, 10, 30, 0.35, color)
+// SetRenderDistanceGrowth shows how much of a render distance increase has
+// loaded; nil hides it.
+func (h *HUD) SetRenderDistanceGrowth(p *world.RingProgress) {
+	h.growth = p
 }
 
-// renderFPS renders the current FPS value on screen
-func (h *HUD) renderFPS() {
-	text := fmt.Sprintf("FPS: %d", h.currentFPS)
-	x := float32(10)
-	y := float32(46)
-	color := mgl32.Vec3{1.0, 1.0, 1.0}
-	h.fontRenderer.Render(text, x, y, 0.3, color)
+// renderRenderDistanceGrowth renders the chunks of a render distance
+// increase loaded so far, under the top left widgets
+func (h *HUD) renderRenderDistanceGrowth() {
+	p := h.growth
+	text := fmt.Sprintf("Render distance %d -> %d: %d/%d chunks", p.Inner, p.Outer, p.Loaded, p.Total)
+	ts := config.GetHUDTextScale()
+	h.fontRenderer.Render(text, 10, h.debugTop, 0.3*ts, mgl32.Vec3{1.0, 1.0, 0.6})
 }
 
-// RenderProfilingInfo renders the current profiling information on screen
-func (h *HUD) RenderProfilingInfo() {
+// RenderProfilingInfo renders the current profiling information on screen,
+// with p's horizontal speed
+func (h *HUD) RenderProfilingInfo(p *player.Player) {
 	lines := make([]string, 0, 64)
 
 	// Frame timing
@@ -148,6 +182,10 @@ func (h *HUD) RenderProfilingInfo() {
 	avgMs := float64(h.profilingStats.avgFrameTime.Microseconds()) / 1000.0
 	lines = append(lines, fmt.Sprintf("Frame(render): %.2fms (%.2fms avg) | Tracked(render): %.2fms", frameMs, avgMs, trackedMs))
 
+	// Horizontal speed (m/s)
+	speed := math.Sqrt(float64(p.Velocity[0]*p.Velocity[0] + p.Velocity[2]*p.Velocity[2]))
+	lines = append(lines, fmt.Sprintf("Speed: %.2f", speed))
+
 	// Update from main loop
 	if h.profilingStats.lastUpdateDuration > 0 {
 		updateMs := float64(h.profilingStats.lastUpdateDuration.Microseconds()) / 1000.0
@@ -187,15 +225,33 @@ func (h *HUD) RenderProfilingInfo() {
 	collectMs := float64(profiling.SumWithPrefix("renderer.renderBlocks.collectVisible").Microseconds()) / 1000.0
 	ensureMs := float64(profiling.SumWithPrefix("renderer.renderBlocks.ensureMeshes").Microseconds()) / 1000.0
 	drawMs := float64(profiling.SumWithPrefix("renderer.renderBlocks.drawAtlas").Microseconds()) / 1000.0
+	occlusionMs := float64(profiling.SumWithPrefix("renderer.renderBlocks.occlusion").Microseconds()) / 1000.0
 	highlightMs := float64(profiling.SumWithPrefix("renderer.renderHighlightedBlock").Microseconds()) / 1000.0
 	handMs := float64(profiling.SumWithPrefix("renderer.renderHand").Microseconds()) / 1000.0
 	crossMs := float64(profiling.SumWithPrefix("renderer.renderCrosshair").Microseconds()) / 1000.0
 	dirMs := float64(profiling.SumWithPrefix("renderer.renderDirection").Microseconds()) / 1000.0
-	if frustumMs+collectMs+ensureMs+drawMs+highlightMs+handMs+crossMs+dirMs > 0 {
-		lines = append(lines, fmt.Sprintf("Blocks -> frustum: %.2fms, collect: %.2fms, ensure: %.2fms, draw: %.2fms", frustumMs, collectMs, ensureMs, drawMs))
+	if frustumMs+collectMs+ensureMs+drawMs+occlusionMs+highlightMs+handMs+crossMs+dirMs > 0 {
+		lines = append(lines, fmt.Sprintf("Blocks -> frustum: %.2fms, collect: %.2fms, ensure: %.2fms, draw: %.2fms, occlusion: %.2fms", frustumMs, collectMs, ensureMs, drawMs, occlusionMs))
 		lines = append(lines, fmt.Sprintf("Overlays -> highlight: %.2fms, hand: %.2fms, crosshair: %.2fms, direction: %.2fms", highlightMs, handMs, crossMs, dirMs))
 	}
 
+	lines = append(lines, fmt.Sprintf("Culling -> enclosed sections skipped: %d, empty chunks (no atlas): %d, occluded columns: %d/%d",
+		h.profilingStats.enclosedSections, h.profilingStats.emptyChunkMeshes, h.profilingStats.occludedColumns, h.profilingStats.occlusionTested))
+
+	ps := &h.profilingStats
+	meshLine := fmt.Sprintf("Mesh memory -> atlas: %.1fMB, cpu: %.1fMB, total: %.1f/%dMB",
+		float64(ps.meshAtlasBytes)/(1<<20), float64(ps.meshCPUBytes)/(1<<20),
+		float64(ps.meshAtlasBytes+ps.meshCPUBytes)/(1<<20), ps.meshCapBytes>>20)
+	if ps.meshLimitRadius > 0 {
+		meshLine += fmt.Sprintf(" (evicted beyond %d chunks)", ps.meshLimitRadius)
+	}
+	lines = append(lines, meshLine)
+
+	eu := h.profilingStats.entityUpdates
+	lines = append(lines, fmt.Sprintf("Entities -> ticked: %d, throttled: %d, frozen: %d (sim %d chunks) | drawn: %d, too far: %d (render %d chunks)",
+		eu.Full, eu.Throttled, eu.Frozen, config.GetEntitySimulationDistance(),
+		h.profilingStats.renderedEntities, h.profilingStats.distantEntities, config.GetEntityRenderDistance()))
+
 	// Top N tracked lines
 	if top := profiling.TopN(10); top != "" {
 		for line := range strings.SplitSeq(top, ", ") {
@@ -206,9 +262,10 @@ func (h *HUD) RenderProfilingInfo() {
 	}
 
 	textColor := mgl32.Vec3{1.0, 1.0, 1.0}
-	startY := float32(60)
-	lineStep := float32(17)
-	h.fontRenderer.RenderLines(lines, 10, startY, lineStep, 0.375, textColor)
+	ts := config.GetHUDTextScale()
+	startY := h.debugTop - 2*ts
+	lineStep := float32(17) * ts
+	h.fontRenderer.RenderLines(lines, 10, startY, lineStep, 0.375*ts, textColor)
 }
 
 // Helper methods for profiling data management
@@ -0,0 +1,28 @@
+package hud
+
+import (
+	"mini-mc/internal/config"
+	"mini-mc/internal/player"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// renderSprintStatus shows, left of the hotbar in grey, that the player is
+// sprinting or has toggled sprint on and will sprint when moving forward.
+func (h *HUD) renderSprintStatus(p *player.Player) {
+	var text string
+	switch {
+	case p.SprintToggled():
+		text = "Sprint (Toggled)"
+	case p.IsSprinting:
+		text = "Sprinting"
+	default:
+		return
+	}
+	scale := float32(config.GetGUIScale())
+	size := 0.25 * config.GetHUDTextScale()
+	w, _ := h.fontRenderer.Measure(text, size)
+	x := (h.width-182*scale)/2 - w - 6*scale
+	y := h.height - 8*scale
+	h.fontRenderer.Render(text, x, y, size, mgl32.Vec3{0.65, 0.65, 0.65})
+}
@@ -1,9 +1,10 @@
 package hud
 
 import (
-	"fmt"
+	"log/slog"
 	"math"
 
+	"mini-mc/internal/config"
 	"mini-mc/internal/graphics"
 
 	"mini-mc/internal/player"
@@ -14,7 +15,7 @@ import (
 func (h *HUD) renderHealth(p *player.Player) {
 	screenWidth := h.width
 	screenHeight := h.height
-	scale := float32(2.0)
+	scale := float32(config.GetGUIScale())
 
 	hbH := 22.0 * scale
 	yHotbar := screenHeight - hbH - 10.0
@@ -33,8 +34,14 @@ func (h *HUD) renderHealth(p *player.Player) {
 
 	uEmpty := float32(16.0) / texW
 	vEmpty := float32(0.0) / texH
-	uFull := float32(52.0) / texW
-	uHalf := float32(61.0) / texW
+	// The containers blink white while a hit's cooldown runs, as in MC,
+	// unless flashing effects are disabled
+	if p.HurtTicks > 0 && p.HurtTicks/3%2 == 1 && !config.GetDisableFlashing() {
+		uEmpty = float32(25.0) / texW
+	}
+	heartU := currentPalette().heartU
+	uFull := heartU / texW
+	uHalf := (heartU + 9.0) / texW
 
 	uWidth := float32(9.0) / texW
 	vHeight := float32(9.0) / texH
@@ -46,7 +53,7 @@ func (h *HUD) renderHealth(p *player.Player) {
 
 	texIcons, err := graphics.GetTexture("assets/textures/gui/icons.png")
 	if err != nil {
-		fmt.Printf("Error loading icons texture: %v\n", err)
+		slog.Error("loading icons texture failed", "err", err)
 		return
 	}
 
@@ -68,7 +75,7 @@ func (h *HUD) renderHealth(p *player.Player) {
 func (h *HUD) renderFood(p *player.Player) {
 	screenWidth := h.width
 	screenHeight := h.height
-	scale := float32(2.0)
+	scale := float32(config.GetGUIScale())
 
 	hbH := 22.0 * scale
 	yHotbar := screenHeight - hbH - 10.0
@@ -0,0 +1,78 @@
+package hud
+
+import (
+	"fmt"
+	"math"
+
+	"mini-mc/internal/config"
+	"mini-mc/internal/player"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Compass points by yaw: 0 degrees looks along +X, 90 along +Z
+var facingNames = [4]string{"East", "South", "West", "North"}
+
+// facingName returns the compass point nearest yaw degrees.
+func facingName(yaw float64) string {
+	return facingNames[int(math.Floor(yaw/90+0.5))&3]
+}
+
+// widgetText returns the line widget w shows.
+func (h *HUD) widgetText(w config.HUDWidget, p *player.Player, wld *world.World) string {
+	switch w {
+	case config.WidgetCoordinates:
+		chunkX := int(math.Floor(float64(p.Position[0]) / world.ChunkSizeX))
+		chunkZ := int(math.Floor(float64(p.Position[2]) / world.ChunkSizeZ))
+		return fmt.Sprintf("XYZ: %.1f / %.1f / %.1f | Chunk: %d, %d", p.Position[0], p.Position[1], p.Position[2], chunkX, chunkZ)
+	case config.WidgetFacing:
+		yaw := math.Mod(p.CamYaw, 360)
+		if yaw < 0 {
+			yaw += 360
+		}
+		return fmt.Sprintf("Facing: %s (%.0f°)", facingName(yaw), yaw)
+	case config.WidgetClock:
+		return fmt.Sprintf("Day %d, %s", wld.Day()+1, wld.Clock())
+	default:
+		return fmt.Sprintf("FPS: %d", h.currentFPS)
+	}
+}
+
+// renderWidgets draws the widgets that are switched on, stacked in their
+// corners in config.HUDWidget order, and returns the y of the line below the
+// top left stack, where the debug lines carry on.
+func (h *HUD) renderWidgets(p *player.Player, wld *world.World) float32 {
+	ts := config.GetHUDTextScale()
+	scale := 0.35 * ts
+	lineStep := 16 * ts
+	margin := 10 * ts
+	color := mgl32.Vec3{1.0, 1.0, 1.0}
+
+	var lines [config.HUDCornerCount][]string
+	for w := range config.HUDWidgetCount {
+		if config.GetHUDWidgetShown(w) {
+			c := config.GetHUDWidgetCorner(w)
+			lines[c] = append(lines[c], h.widgetText(w, p, wld))
+		}
+	}
+
+	top := 30 * ts
+	for c, stack := range lines {
+		corner := config.HUDCorner(c)
+		y := top
+		if corner.Bottom() {
+			y = h.height - margin - float32(len(stack)-1)*lineStep
+		}
+		for _, text := range stack {
+			x := margin
+			if corner.Right() {
+				tw, _ := h.fontRenderer.Measure(text, scale)
+				x = h.width - margin - tw
+			}
+			h.fontRenderer.Render(text, x, y, scale, color)
+			y += lineStep
+		}
+	}
+	return top + float32(len(lines[config.CornerTopLeft]))*lineStep
+}
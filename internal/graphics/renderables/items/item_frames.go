@@ -0,0 +1,69 @@
+package items
+
+import (
+	"mini-mc/internal/blockentity"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// itemFrameRenderDistance is how far (in blocks) item frames are drawn.
+const itemFrameRenderDistance = 48
+
+// Framed item placement in frame model space (block centred on the origin,
+// front facing -Z): just in front of the backboard, at half size.
+const (
+	framedItemDepth = 0.40
+	framedItemScale = 0.5
+)
+
+// facingYaw returns the Y rotation (degrees) that turns the frame model's
+// front (-Z) to point along facing.
+func facingYaw(facing uint8) float32 {
+	switch world.BlockFace(facing) {
+	case world.FaceNorth: // +Z
+		return 180
+	case world.FaceEast: // +X
+		return -90
+	case world.FaceWest: // -X
+		return 90
+	}
+	return 0
+}
+
+// renderItemFrames draws the frames collected for this frame and the items
+// inside them, laid flat against the backboard. The shader and atlas must
+// already be bound.
+func (i *Items) renderItemFrames() {
+	frameMesh := i.meshCache[world.BlockTypeItemFrame]
+	for _, ref := range i.frameRefs {
+		frame, ok := ref.Entity.(*blockentity.ItemFrame)
+		if !ok {
+			continue
+		}
+
+		base := mgl32.Translate3D(float32(ref.Pos.X)+0.5, float32(ref.Pos.Y)+0.5, float32(ref.Pos.Z)+0.5).
+			Mul4(mgl32.HomogRotate3DY(mgl32.DegToRad(facingYaw(frame.Facing()))))
+
+		if frameMesh != nil {
+			model := base.Mul4(mgl32.Translate3D(-0.5, -0.5, -0.5))
+			i.shader.SetMatrix4("model", &model[0])
+			i.drawBlock(world.BlockTypeItemFrame, frameMesh)
+		}
+
+		if frame.Item == nil {
+			continue
+		}
+		mesh := i.meshCache[frame.Item.Type]
+		if mesh == nil {
+			continue
+		}
+		rot := float32(frame.Rotation) * 360 / blockentity.ItemFrameRotations
+		model := base.Mul4(mgl32.Translate3D(0, 0, framedItemDepth)).
+			Mul4(mgl32.HomogRotate3DZ(mgl32.DegToRad(rot))).
+			Mul4(mgl32.Scale3D(framedItemScale, framedItemScale, framedItemScale)).
+			Mul4(mgl32.Translate3D(-0.5, -0.5, -0.5))
+		i.shader.SetMatrix4("model", &model[0])
+		i.drawBlock(frame.Item.Type, mesh)
+	}
+}
@@ -2,6 +2,7 @@ package items
 
 import (
 	"math"
+	"mini-mc/internal/config"
 	"mini-mc/internal/entity"
 	"mini-mc/internal/graphics"
 	"mini-mc/internal/graphics/renderables/blocks"
@@ -24,6 +25,18 @@ type Items struct {
 	// Viewport dimensions for GUI rendering
 	width  float32
 	height float32
+
+	// Reused per frame by renderItemFrames
+	frameRefs []world.BlockEntityRef
+}
+
+// Items and vehicles drawn and skipped for distance by the last Render
+var renderedEntities, distantEntities int
+
+// EntityRenderStats returns how many items and vehicles the last frame drew
+// and how many it skipped as beyond the entity render distance.
+func EntityRenderStats() (rendered, distant int) {
+	return renderedEntities, distantEntities
 }
 
 func NewItems() *Items {
@@ -81,7 +94,10 @@ func (i *Items) Init() error {
 
 func (i *Items) Render(ctx renderer.RenderContext) {
 	entities := ctx.World.GetEntities()
-	if len(entities) == 0 {
+	eye := ctx.Snapshot.Eye
+	renderedEntities, distantEntities = 0, 0
+	i.frameRefs = ctx.World.AppendBlockEntitiesInRadius(eye.X(), eye.Y(), eye.Z(), itemFrameRenderDistance, i.frameRefs[:0])
+	if len(entities) == 0 && len(i.frameRefs) == 0 {
 		return
 	}
 
@@ -98,9 +114,24 @@ func (i *Items) Render(ctx renderer.RenderContext) {
 
 	gl.BindVertexArray(0)
 
+	i.renderItemFrames()
+
+	maxDist := float32(config.GetEntityRenderDistance() * world.ChunkSizeX)
+	maxDistSq := maxDist * maxDist
 	for _, ent := range entities {
-		itemEnt, ok := ent.(*entity.ItemEntity)
-		if !ok {
+		vehicle, isVehicle := ent.(entity.Vehicle)
+		itemEnt, isItem := ent.(*entity.ItemEntity)
+		if !isVehicle && !isItem {
+			// Mobs are drawn and counted by the playermodel package
+			continue
+		}
+		if p := ent.Position(); (p.X()-eye.X())*(p.X()-eye.X())+(p.Z()-eye.Z())*(p.Z()-eye.Z()) > maxDistSq {
+			distantEntities++
+			continue
+		}
+		renderedEntities++
+		if isVehicle {
+			i.renderVehicle(vehicle, ctx.EntityPartialTick)
 			continue
 		}
 
@@ -118,12 +149,13 @@ func (i *Items) Render(ctx renderer.RenderContext) {
 		// 49-64 items: 5 copies
 		renderCount := getStackRenderCount(itemEnt.Stack.Count)
 
-		// Animation logic (bobbing & rotation)
-		age := float32(itemEnt.Age * 20.0) // Convert seconds to ticks approx
+		// Animation logic (bobbing & rotation), aged and placed between ticks
+		partial := min(max(ctx.EntityPartialTick, 0), 1+entity.MaxExtrapolation)
+		age := float32(itemEnt.Age*20.0) + partial // Convert seconds to ticks approx
 		hover := float32(math.Sin(float64(age/10.0+float32(itemEnt.HoverStart))))*0.1 + 0.25
 		rot := (age/20.0 + float32(itemEnt.HoverStart)) * (180.0 / math.Pi)
 
-		pos := itemEnt.Position()
+		pos := entity.RenderTransform(itemEnt, ctx.EntityPartialTick).Pos
 
 		// Render multiple items for stacks
 		for j := 0; j < renderCount; j++ {
@@ -0,0 +1,32 @@
+package items
+
+import (
+	"mini-mc/internal/entity"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// renderVehicle draws a vehicle with the item model it picks (see
+// entity.Modeled) scaled to the entity's width. The models' length runs along +X, which is the heading at
+// yaw 0. It is drawn partialTicks between its last two ticks. The shader and
+// atlas must already be bound.
+func (i *Items) renderVehicle(vehicle entity.Vehicle, partialTicks float32) {
+	m, ok := vehicle.(entity.Modeled)
+	if !ok {
+		return
+	}
+	modelType := m.Model()
+	mesh := i.meshCache[modelType]
+	if mesh == nil {
+		return
+	}
+	t := entity.RenderTransform(vehicle, partialTicks)
+	pos := t.Pos
+	size, _ := vehicle.GetBounds()
+	model := mgl32.Translate3D(pos.X(), pos.Y(), pos.Z()).
+		Mul4(mgl32.HomogRotate3DY(mgl32.DegToRad(-t.Yaw))).
+		Mul4(mgl32.Scale3D(size, size, size)).
+		Mul4(mgl32.Translate3D(-0.5, 0, -0.5))
+	i.shader.SetMatrix4("model", &model[0])
+	i.drawBlock(modelType, mesh)
+}
@@ -0,0 +1,219 @@
+// Package particles draws short-lived camera-facing quads: block fragments
+// from breaking and placing, dust from landing and sprinting, water splashes
+// and sparks from hitting entities.
+package particles
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+
+	"mini-mc/internal/graphics"
+	"mini-mc/internal/graphics/renderables/blocks"
+	"mini-mc/internal/graphics/renderer"
+	"mini-mc/internal/profiling"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+const (
+	ShadersDir = "assets/shaders/particles"
+
+	// Per-instance floats: centre (3), size, uv rect (4), layer, colour (4)
+	instanceFloats = 13
+)
+
+// quadCorners is a unit quad as a triangle strip, in camera right/up units.
+var quadCorners = []float32{
+	-0.5, -0.5,
+	0.5, -0.5,
+	-0.5, 0.5,
+	0.5, 0.5,
+}
+
+// Particles renders a particle System. Spawn effects through System.
+type Particles struct {
+	*System
+
+	shader      *graphics.Shader
+	vao         uint32
+	quadVBO     uint32
+	instanceVBO uint32
+	instances   []float32 // reused per frame
+
+	order   []drawOrder     // reused per frame
+	batches []particleBatch // this frame's runs of instances, one per chunk column
+}
+
+// drawOrder places a particle in the instance buffer.
+type drawOrder struct {
+	index    int
+	cx, cz   int     // chunk column
+	colDepth float32 // depth of the column in the translucent stage
+	depth    float32 // squared distance from the eye
+}
+
+// particleBatch is a run of instances in one chunk column, drawn as one
+// batch of the translucent stage.
+type particleBatch struct {
+	first, count int32
+	depth        float32
+}
+
+// NewParticles creates the particle renderable.
+func NewParticles() *Particles {
+	return &Particles{System: NewSystem(time.Now().UnixNano())}
+}
+
+// Init compiles the shader and sets up the instanced quad.
+func (p *Particles) Init() error {
+	var err error
+	p.shader, err = graphics.NewShader(ShadersDir+"/particle.vert", ShadersDir+"/particle.frag")
+	if err != nil {
+		return err
+	}
+
+	gl.GenVertexArrays(1, &p.vao)
+	gl.BindVertexArray(p.vao)
+
+	gl.GenBuffers(1, &p.quadVBO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, p.quadVBO)
+	gl.BufferData(gl.ARRAY_BUFFER, len(quadCorners)*4, gl.Ptr(quadCorners), gl.STATIC_DRAW)
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, 2*4, gl.PtrOffset(0))
+
+	gl.GenBuffers(1, &p.instanceVBO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, p.instanceVBO)
+	gl.BufferData(gl.ARRAY_BUFFER, MaxParticles*instanceFloats*4, nil, gl.STREAM_DRAW)
+	for loc := uint32(1); loc <= 4; loc++ {
+		gl.EnableVertexAttribArray(loc)
+		gl.VertexAttribDivisor(loc, 1)
+	}
+	p.pointInstances(0)
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.BindVertexArray(0)
+	return nil
+}
+
+// pointInstances points the instance attributes at the buffer from instance
+// first on. GL 4.1 has no base instance for instanced draws, so each batch
+// moves the pointers instead. The VAO and instance VBO must be bound.
+func (p *Particles) pointInstances(first int32) {
+	stride := int32(instanceFloats * 4)
+	base := int(first) * instanceFloats
+	for _, a := range []struct {
+		loc    uint32
+		size   int32
+		offset int
+	}{
+		{1, 4, 0}, // centre + size
+		{2, 4, 4}, // uv rect
+		{3, 1, 8}, // layer
+		{4, 4, 9}, // colour
+	} {
+		gl.VertexAttribPointer(a.loc, a.size, gl.FLOAT, false, stride, gl.PtrOffset((base+a.offset)*4))
+	}
+}
+
+// Render advances the particles by the frame time and uploads them for the
+// translucent stage, grouped by chunk column and farthest first.
+func (p *Particles) Render(ctx renderer.RenderContext) {
+	defer profiling.Track("renderer.renderParticles")()
+	p.Update(ctx.DT, ctx.World)
+	p.batches = p.batches[:0]
+	if len(p.particles) == 0 {
+		return
+	}
+
+	eye := ctx.Snapshot.Eye
+	p.order = p.order[:0]
+	for i, pt := range p.particles {
+		cx := int(math.Floor(float64(pt.pos.X()) / 16))
+		cz := int(math.Floor(float64(pt.pos.Z()) / 16))
+		p.order = append(p.order, drawOrder{
+			index:    i,
+			cx:       cx,
+			cz:       cz,
+			colDepth: blocks.ColumnDepth(eye, cx, cz),
+			depth:    pt.pos.Sub(eye).LenSqr(),
+		})
+	}
+	slices.SortFunc(p.order, func(a, b drawOrder) int {
+		return cmp.Or(
+			cmp.Compare(b.colDepth, a.colDepth),
+			cmp.Compare(a.cx, b.cx),
+			cmp.Compare(a.cz, b.cz),
+			cmp.Compare(b.depth, a.depth),
+		)
+	})
+
+	p.instances = p.instances[:0]
+	for i, o := range p.order {
+		if i == 0 || o.cx != p.order[i-1].cx || o.cz != p.order[i-1].cz {
+			p.batches = append(p.batches, particleBatch{first: int32(i), depth: o.colDepth})
+		}
+		p.batches[len(p.batches)-1].count++
+
+		pt := &p.particles[o.index]
+		alpha := float32(1)
+		if pt.fade {
+			alpha = 1 - pt.age/pt.life
+		}
+		p.instances = append(p.instances,
+			pt.pos.X(), pt.pos.Y(), pt.pos.Z(), pt.size,
+			pt.uv[0], pt.uv[1], pt.uv[2], pt.uv[3],
+			pt.layer,
+			pt.color.X(), pt.color.Y(), pt.color.Z(), alpha)
+	}
+
+	p.shader.Use()
+	p.shader.SetMatrix4("view", &ctx.View[0])
+	p.shader.SetMatrix4("proj", &ctx.Proj[0])
+	p.shader.SetInt("textureArray", 0)
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, p.instanceVBO)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(p.instances)*4, gl.Ptr(p.instances))
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+}
+
+// AppendTranslucent adds a batch per chunk column holding particles.
+func (p *Particles) AppendTranslucent(ctx renderer.RenderContext, dst []renderer.TranslucentBatch) []renderer.TranslucentBatch {
+	for i, b := range p.batches {
+		dst = append(dst, renderer.TranslucentBatch{Depth: b.depth, Owner: p, Index: i})
+	}
+	return dst
+}
+
+// DrawTranslucent draws the particles of one chunk column.
+func (p *Particles) DrawTranslucent(ctx renderer.RenderContext, index int) {
+	b := p.batches[index]
+	p.shader.Use()
+	if blocks.GlobalTextureAtlas != nil {
+		gl.ActiveTexture(gl.TEXTURE0)
+		gl.BindTexture(gl.TEXTURE_2D_ARRAY, blocks.GlobalTextureAtlas.TextureID)
+	}
+	gl.BindVertexArray(p.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, p.instanceVBO)
+	p.pointInstances(b.first)
+	gl.DrawArraysInstanced(gl.TRIANGLE_STRIP, 0, 4, b.count)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.BindVertexArray(0)
+}
+
+// Dispose releases the GL objects.
+func (p *Particles) Dispose() {
+	if p.vao != 0 {
+		gl.DeleteVertexArrays(1, &p.vao)
+	}
+	if p.quadVBO != 0 {
+		gl.DeleteBuffers(1, &p.quadVBO)
+	}
+	if p.instanceVBO != 0 {
+		gl.DeleteBuffers(1, &p.instanceVBO)
+	}
+}
+
+// SetViewport is a no-op; particles draw in world space.
+func (p *Particles) SetViewport(width, height int) {}
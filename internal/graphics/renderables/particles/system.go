@@ -0,0 +1,273 @@
+package particles
+
+import (
+	"math"
+	"math/rand"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// MaxParticles caps how many particles are alive at once; spawns beyond it
+// are dropped, as in 1.8.9.
+const MaxParticles = 4000
+
+// Block fragment motion follows 1.8.9's EntityDiggingFX, converted from
+// per-tick to per-second units.
+const (
+	fragmentGravity  = 16.0 // blocks/s²
+	fragmentDrag     = 0.98 // per tick
+	fragmentFriction = 0.7  // per tick, on the ground
+	fragmentShade    = 0.6  // fragments are darker than the block face
+
+	sparkGravity = 10.0
+	sparkDrag    = 0.7
+
+	// Landing dust follows 1.8.9's EntityLivingBase.updateFallState: none
+	// for falls of dustMinFall blocks or less, then more the further the
+	// fall, up to maxDust.
+	dustMinFall = 3.0
+	maxDust     = 100
+
+	splashGravity = 12.0
+	splashDrag    = 0.98
+)
+
+// BlockSource is the world as particles see it: enough to land on blocks.
+type BlockSource interface {
+	Get(x, y, z int) world.BlockType
+}
+
+// particle is one camera-facing quad.
+type particle struct {
+	pos, vel mgl32.Vec3
+	size     float32    // edge length in blocks
+	layer    float32    // texture layer, or -1 for a flat colour
+	uv       [4]float32 // u0, v0, du, dv within the layer
+	color    mgl32.Vec3
+	age      float32 // seconds
+	life     float32
+	gravity  float32
+	drag     float32 // velocity kept per tick
+	fade     bool    // alpha falls to zero over the particle's life
+	onGround bool
+}
+
+// System simulates particles. It is kept apart from the GL renderer so it
+// can run without a context.
+type System struct {
+	particles []particle
+	rnd       *rand.Rand
+}
+
+// NewSystem creates an empty particle system. seed only drives the cosmetic
+// spread of particles.
+func NewSystem(seed int64) *System {
+	return &System{rnd: rand.New(rand.NewSource(seed))}
+}
+
+// Len returns the number of live particles.
+func (s *System) Len() int {
+	return len(s.particles)
+}
+
+func (s *System) add(p particle) {
+	if len(s.particles) < MaxParticles {
+		s.particles = append(s.particles, p)
+	}
+}
+
+// BlockFragment describes the look of a block's fragments: its texture layer
+// and tint.
+type BlockFragment struct {
+	Layer int
+	Tint  mgl32.Vec3
+}
+
+// SpawnBlockBreak bursts a broken block at (x, y, z) into a 4x4x4 grid of
+// fragments flying outwards, like 1.8.9.
+func (s *System) SpawnBlockBreak(x, y, z int, f BlockFragment) {
+	s.spawnFragments(x, y, z, f, 4, 1)
+}
+
+// SpawnBlockPlace puffs a few slow fragments off a placed block.
+func (s *System) SpawnBlockPlace(x, y, z int, f BlockFragment) {
+	s.spawnFragments(x, y, z, f, 2, 0.5)
+}
+
+// spawnFragments spawns n³ fragments on a grid through the block, each
+// pushed away from the block centre.
+func (s *System) spawnFragments(x, y, z int, f BlockFragment, n int, speed float32) {
+	for i := range n {
+		for j := range n {
+			for k := range n {
+				off := mgl32.Vec3{
+					(float32(i) + 0.5) / float32(n),
+					(float32(j) + 0.5) / float32(n),
+					(float32(k) + 0.5) / float32(n),
+				}
+				p := s.fragment(f, off.Sub(mgl32.Vec3{0.5, 0.5, 0.5}), speed)
+				p.pos = mgl32.Vec3{float32(x), float32(y), float32(z)}.Add(off)
+				s.add(p)
+			}
+		}
+	}
+}
+
+// fragment builds one block fragment moving roughly along dir. The speed
+// spread and upward kick match 1.8.9's EntityFX constructor.
+func (s *System) fragment(f BlockFragment, dir mgl32.Vec3, speed float32) particle {
+	r := s.rnd
+	v := mgl32.Vec3{
+		dir.X() + (r.Float32()*2-1)*0.4,
+		dir.Y() + (r.Float32()*2-1)*0.4,
+		dir.Z() + (r.Float32()*2-1)*0.4,
+	}
+	if l := v.Len(); l > 0 {
+		v = v.Mul((r.Float32() + r.Float32() + 1) * 0.15 * 0.4 / l)
+	}
+	v[1] += 0.1
+	v = v.Mul(20 * speed) // blocks/tick to blocks/s
+
+	// A random 4x4 pixel patch of the block's 16x16 texture
+	u0, v0 := math.Floor(r.Float64()*3)/4, math.Floor(r.Float64()*3)/4
+	return particle{
+		vel:     v,
+		size:    0.2 * (r.Float32()*0.5 + 0.5),
+		layer:   float32(f.Layer),
+		uv:      [4]float32{float32(u0), float32(v0), 0.25, 0.25},
+		color:   f.Tint.Mul(fragmentShade),
+		life:    float32(int(4/(r.Float64()*0.9+0.1))) / 20,
+		gravity: fragmentGravity,
+		drag:    fragmentDrag,
+	}
+}
+
+// SpawnLandingDust kicks up fragments of the block landed on around pos,
+// the player's feet, after a fall of fallDistance blocks.
+func (s *System) SpawnLandingDust(pos mgl32.Vec3, f BlockFragment, fallDistance float32) {
+	if fallDistance <= dustMinFall {
+		return
+	}
+	d := min(0.2+float32(math.Ceil(float64(fallDistance-dustMinFall)))/15, 10)
+	r := s.rnd
+	for range min(int(150*d), maxDust) {
+		dir := mgl32.Vec3{float32(r.NormFloat64()), float32(r.NormFloat64()), float32(r.NormFloat64())}
+		p := s.fragment(f, dir.Mul(0.15), 1)
+		p.pos = pos.Add(mgl32.Vec3{0, 0.1, 0})
+		s.add(p)
+	}
+}
+
+// SpawnRunningDust throws up one fragment of the block under a sprinting
+// player's feet at pos, flying back against their velocity vel (blocks/s),
+// as 1.8.9's Entity.createRunningParticles does each tick.
+func (s *System) SpawnRunningDust(pos, vel mgl32.Vec3, f BlockFragment, width float32) {
+	r := s.rnd
+	// 1.8.9 aims against the per-tick motion, four times over
+	p := s.fragment(f, mgl32.Vec3{-vel.X() / 5, 1.5, -vel.Z() / 5}, 1)
+	p.pos = pos.Add(mgl32.Vec3{(r.Float32() - 0.5) * width, 0.1, (r.Float32() - 0.5) * width})
+	s.add(p)
+}
+
+// SpawnSplash sprays water up around pos, on the surface, where a body
+// width blocks wide fell in; f is the water's look.
+func (s *System) SpawnSplash(pos mgl32.Vec3, f BlockFragment, width float32) {
+	r := s.rnd
+	for range 1 + int(width*20) {
+		p := s.fragment(f, mgl32.Vec3{}, 1)
+		p.pos = pos.Add(mgl32.Vec3{(r.Float32()*2 - 1) * width, 0, (r.Float32()*2 - 1) * width})
+		p.vel = mgl32.Vec3{(r.Float32()*2 - 1) * 1.5, 2 + r.Float32()*2, (r.Float32()*2 - 1) * 1.5}
+		p.size *= 0.6
+		p.color = f.Tint
+		p.life = 0.4 + r.Float32()*0.4
+		p.gravity = splashGravity
+		p.drag = splashDrag
+		p.fade = true
+		s.add(p)
+	}
+}
+
+// SpawnItemBreak bursts a tool that broke in the hand of a player with
+// eyes at eye, looking along front, into fragments of its texture f, in
+// front of them and a little low, as 1.8.9's renderBrokenItemStack does.
+func (s *System) SpawnItemBreak(eye, front mgl32.Vec3, f BlockFragment) {
+	r := s.rnd
+	for range 5 {
+		p := s.fragment(f, mgl32.Vec3{}, 1)
+		p.pos = eye.Add(front.Mul(0.6)).Add(mgl32.Vec3{(r.Float32() - 0.5) * 0.3, -r.Float32()*0.6 - 0.3, (r.Float32() - 0.5) * 0.3})
+		p.vel = front.Mul(0.5).Add(mgl32.Vec3{(r.Float32() - 0.5) * 2, 2 + r.Float32()*2, (r.Float32() - 0.5) * 2})
+		p.color = f.Tint
+		s.add(p)
+	}
+}
+
+// SpawnHit sparkles around pos, where an entity was hit. Critical hits get
+// more, brighter sparks.
+func (s *System) SpawnHit(pos mgl32.Vec3, crit bool) {
+	count, color := 6, mgl32.Vec3{0.9, 0.9, 0.9}
+	if crit {
+		count, color = 16, mgl32.Vec3{1.0, 0.85, 0.45}
+	}
+	r := s.rnd
+	for range count {
+		dir := mgl32.Vec3{r.Float32()*2 - 1, r.Float32()*2 - 1, r.Float32()*2 - 1}
+		shade := r.Float32()*0.3 + 0.7
+		s.add(particle{
+			pos:     pos.Add(dir.Mul(0.3)),
+			vel:     dir.Mul(6).Add(mgl32.Vec3{0, 2, 0}),
+			size:    0.1 + r.Float32()*0.05,
+			layer:   -1,
+			color:   color.Mul(shade),
+			life:    0.3 + r.Float32()*0.2,
+			gravity: sparkGravity,
+			drag:    sparkDrag,
+			fade:    true,
+		})
+	}
+}
+
+// Update ages and moves the particles over dt seconds, landing them on solid
+// blocks and dropping expired ones.
+func (s *System) Update(dt float64, w BlockSource) {
+	fdt := float32(dt)
+	live := s.particles[:0]
+	for _, p := range s.particles {
+		p.age += fdt
+		if p.age >= p.life {
+			continue
+		}
+		p.vel[1] -= p.gravity * fdt
+		p.vel = p.vel.Mul(float32(math.Pow(float64(p.drag), dt*20)))
+
+		p.onGround = false
+		for axis := range 3 {
+			next := p.pos
+			next[axis] += p.vel[axis] * fdt
+			if solidAt(w, next) {
+				if axis == 1 && p.vel[1] < 0 {
+					p.onGround = true
+				}
+				p.vel[axis] = 0
+				continue
+			}
+			p.pos = next
+		}
+		if p.onGround {
+			f := float32(math.Pow(fragmentFriction, dt*20))
+			p.vel[0] *= f
+			p.vel[2] *= f
+		}
+		live = append(live, p)
+	}
+	clear(s.particles[len(live):])
+	s.particles = live
+}
+
+func solidAt(w BlockSource, pos mgl32.Vec3) bool {
+	x := int(math.Floor(float64(pos.X())))
+	y := int(math.Floor(float64(pos.Y())))
+	z := int(math.Floor(float64(pos.Z())))
+	return world.BlockSolidTable[w.Get(x, y, z)]
+}
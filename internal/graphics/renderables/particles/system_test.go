@@ -0,0 +1,107 @@
+package particles
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// floor is solid stone below y = 64.
+type floor struct{}
+
+func (floor) Get(x, y, z int) world.BlockType {
+	if y < 64 {
+		return world.BlockTypeStone
+	}
+	return world.BlockTypeAir
+}
+
+func TestBlockBreakFragments(t *testing.T) {
+	world.BlockSolidTable[world.BlockTypeStone] = true
+	s := NewSystem(1)
+	s.SpawnBlockBreak(0, 64, 0, BlockFragment{Layer: 3, Tint: mgl32.Vec3{1, 1, 1}})
+	if s.Len() != 64 {
+		t.Fatalf("break spawned %d fragments, want 64", s.Len())
+	}
+
+	landed := false
+	for range 30 {
+		s.Update(1.0/60, floor{})
+		for _, p := range s.particles {
+			if p.pos.Y() < 64 {
+				t.Fatalf("fragment fell through the floor to %v", p.pos)
+			}
+			landed = landed || p.onGround
+		}
+	}
+	if !landed {
+		t.Error("no fragment landed on the floor")
+	}
+
+	for range 130 { // longest life is 40 ticks
+		s.Update(1.0/60, floor{})
+	}
+	if s.Len() != 0 {
+		t.Errorf("%d fragments outlived their life", s.Len())
+	}
+}
+
+func TestParticleCap(t *testing.T) {
+	s := NewSystem(1)
+	for range MaxParticles/16 + 10 {
+		s.SpawnHit(mgl32.Vec3{0, 70, 0}, true)
+	}
+	if s.Len() != MaxParticles {
+		t.Errorf("%d particles alive, want the cap of %d", s.Len(), MaxParticles)
+	}
+}
+
+func TestLandingDustGrowsWithTheFall(t *testing.T) {
+	f := BlockFragment{Layer: 1, Tint: mgl32.Vec3{1, 1, 1}}
+	counts := make([]int, 0, 3)
+	for _, fall := range []float32{2.5, 4, 10} {
+		s := NewSystem(1)
+		s.SpawnLandingDust(mgl32.Vec3{0, 64, 0}, f, fall)
+		counts = append(counts, s.Len())
+	}
+	if counts[0] != 0 {
+		t.Errorf("a %v block fall raised %d dust", 2.5, counts[0])
+	}
+	if !(counts[1] > 0 && counts[2] > counts[1] && counts[2] <= maxDust) {
+		t.Errorf("dust for falls of 4 and 10 blocks: %v", counts[1:])
+	}
+}
+
+func TestSplashAndRunningDustRise(t *testing.T) {
+	world.BlockSolidTable[world.BlockTypeStone] = true
+	s := NewSystem(1)
+	s.SpawnSplash(mgl32.Vec3{0, 64, 0}, BlockFragment{Layer: 2, Tint: mgl32.Vec3{1, 1, 1}}, 0.6)
+	s.SpawnRunningDust(mgl32.Vec3{0, 64, 0}, mgl32.Vec3{5.6, 0, 0}, BlockFragment{Layer: 1}, 0.6)
+	if s.Len() != 14 {
+		t.Fatalf("%d particles, want 13 drops and a fragment", s.Len())
+	}
+	for i, p := range s.particles {
+		if p.vel.Y() <= 0 {
+			t.Errorf("particle %d starts moving down: %v", i, p.vel)
+		}
+	}
+	if dust := s.particles[13]; dust.vel.X() >= 0 {
+		t.Errorf("running dust flies along with the runner: %v", dust.vel)
+	}
+}
+
+func TestItemBreakInFrontOfTheEyes(t *testing.T) {
+	s := NewSystem(1)
+	eye, front := mgl32.Vec3{0, 65.62, 0}, mgl32.Vec3{1, 0, 0}
+	s.SpawnItemBreak(eye, front, BlockFragment{Layer: 3, Tint: mgl32.Vec3{1, 1, 1}})
+	if s.Len() != 5 {
+		t.Fatalf("%d fragments, want 5", s.Len())
+	}
+	for i, p := range s.particles {
+		if p.pos.X() <= eye.X() || p.pos.Y() >= eye.Y() || p.vel.Y() <= 0 || p.layer != 3 {
+			t.Errorf("fragment %d at %v moving %v on layer %v; want ahead, below the eyes, rising, from the tool", i, p.pos, p.vel, p.layer)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package playermodel
+
+import (
+	"mini-mc/internal/graphics/renderer"
+)
+
+// Ghost draws the body walking a recording played back, in the opaque
+// stage. The session poses it each frame while a recording plays.
+type Ghost struct {
+	model *PlayerModel
+	pose  WorldPose
+	shown bool
+}
+
+// NewGhost creates the renderable for a replay's ghost.
+func NewGhost() *Ghost {
+	return &Ghost{model: NewPlayerModel()}
+}
+
+func (g *Ghost) Init() error {
+	return g.model.Init()
+}
+
+// Show draws the ghost standing as pose from the next frame on.
+func (g *Ghost) Show(pose WorldPose) {
+	g.pose = pose
+	g.shown = true
+}
+
+// Hide stops drawing the ghost.
+func (g *Ghost) Hide() {
+	g.shown = false
+}
+
+func (g *Ghost) Render(ctx renderer.RenderContext) {
+	if g.shown {
+		g.model.RenderWorldPlayer(ctx.View, ctx.Proj, g.pose)
+	}
+}
+
+func (g *Ghost) Dispose() {
+	g.model.Dispose()
+}
+
+func (g *Ghost) SetViewport(width, height int) {}
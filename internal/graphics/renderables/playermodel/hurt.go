@@ -0,0 +1,30 @@
+package playermodel
+
+import (
+	"mini-mc/internal/config"
+	"mini-mc/internal/graphics"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// hurtOverlay is the red mixed over a mob while it flashes after a hit
+var hurtOverlay = mgl32.Vec4{1, 0, 0, 0.3}
+
+// setOverlay sets shader's overlay colour: the hurt flash while flashing,
+// none otherwise or with flashing effects disabled.
+func setOverlay(shader *graphics.Shader, flashing bool) {
+	if flashing && !config.GetDisableFlashing() {
+		shader.SetVector4("overlayColor", hurtOverlay[0], hurtOverlay[1], hurtOverlay[2], hurtOverlay[3])
+		return
+	}
+	shader.SetVector4("overlayColor", 0, 0, 0, 0)
+}
+
+// fallOver returns the rotation tipping a dying mob tilt degrees onto its
+// side about its feet, applied after its heading so it falls sideways.
+func fallOver(tilt float32) mgl32.Mat4 {
+	if tilt == 0 {
+		return mgl32.Ident4()
+	}
+	return mgl32.HomogRotate3DZ(mgl32.DegToRad(tilt))
+}
@@ -0,0 +1,212 @@
+package playermodel
+
+import (
+	"math"
+
+	"mini-mc/internal/config"
+	"mini-mc/internal/entity"
+	"mini-mc/internal/graphics"
+	"mini-mc/internal/graphics/renderer"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Mobs drawn and skipped for distance by the last Render
+var renderedMobs, distantMobs int
+
+// MobRenderStats returns how many mobs the last frame drew and how many it
+// skipped as beyond the entity render distance.
+func MobRenderStats() (rendered, distant int) {
+	return renderedMobs, distantMobs
+}
+
+// mobBox is one box of a mob model, in pixels with the model facing +Z and
+// standing on y = 0, and where its net starts in the 64x64 texture. Legs
+// swing about their top, in step with the other legs of the same phase.
+type mobBox struct {
+	x, y, z, w, h, d float32
+	u, v             float32
+	leg              bool
+	phase            float32 // +1 or -1: which pair of legs it swings with
+}
+
+var (
+	pigBoxes = []mobBox{
+		{x: -4, y: 8, z: 6, w: 8, h: 8, d: 8, u: 0, v: 0},     // head
+		{x: -2, y: 9, z: 14, w: 4, h: 3, d: 1, u: 32, v: 0},   // snout
+		{x: -5, y: 6, z: -8, w: 10, h: 8, d: 16, u: 0, v: 16}, // body
+		{x: -5, y: 0, z: 3, w: 4, h: 6, d: 4, u: 0, v: 40, leg: true, phase: 1},
+		{x: 1, y: 0, z: 3, w: 4, h: 6, d: 4, u: 0, v: 40, leg: true, phase: -1},
+		{x: -5, y: 0, z: -7, w: 4, h: 6, d: 4, u: 0, v: 40, leg: true, phase: -1},
+		{x: 1, y: 0, z: -7, w: 4, h: 6, d: 4, u: 0, v: 40, leg: true, phase: 1},
+	}
+	chickenBoxes = []mobBox{
+		{x: -3, y: 4, z: -4, w: 6, h: 6, d: 8, u: 0, v: 0},   // body
+		{x: -2, y: 9, z: 3, w: 4, h: 6, d: 3, u: 28, v: 0},   // head
+		{x: -2, y: 12, z: 6, w: 4, h: 2, d: 2, u: 42, v: 0},  // bill
+		{x: -1, y: 10, z: 6, w: 2, h: 2, d: 2, u: 42, v: 4},  // wattle
+		{x: -4, y: 6, z: -3, w: 1, h: 4, d: 6, u: 12, v: 14}, // wings
+		{x: 3, y: 6, z: -3, w: 1, h: 4, d: 6, u: 12, v: 14},
+		{x: -3, y: 0, z: -1, w: 3, h: 5, d: 3, u: 0, v: 14, leg: true, phase: 1},
+		{x: 0, y: 0, z: -1, w: 3, h: 5, d: 3, u: 0, v: 14, leg: true, phase: -1},
+	}
+)
+
+// mobPart is a box of a mob model uploaded to the GPU.
+type mobPart struct {
+	vao, vbo uint32
+	count    int32
+	box      mobBox
+}
+
+type mobModel struct {
+	texture uint32
+	parts   []mobPart
+}
+
+// Mobs draws the mobs in the world, in the opaque stage: passive mobs with
+// the player model's shader and cube helpers, zombies as players in their
+// own skin.
+type Mobs struct {
+	shader *graphics.Shader
+	models [2]mobModel // by entity.MobKind
+	zombie *PlayerModel
+}
+
+// NewMobs creates the renderable for mobs.
+func NewMobs() *Mobs {
+	return &Mobs{zombie: newSkinnedModel("assets/textures/entity/zombie.png")}
+}
+
+func (m *Mobs) Init() error {
+	var err error
+	m.shader, err = graphics.NewShader(PlayerVertShader, PlayerFragShader)
+	if err != nil {
+		return err
+	}
+	if err := m.zombie.Init(); err != nil {
+		return err
+	}
+	for kind, model := range map[entity.MobKind]struct {
+		texture string
+		boxes   []mobBox
+	}{
+		entity.MobPig:     {"assets/textures/entity/pig.png", pigBoxes},
+		entity.MobChicken: {"assets/textures/entity/chicken.png", chickenBoxes},
+	} {
+		mm := &m.models[kind]
+		mm.texture, _, _, err = graphics.LoadTexture(model.texture)
+		if err != nil {
+			return err
+		}
+		for _, b := range model.boxes {
+			var vertices []float32
+			addBox(&vertices, b.x, b.y, b.z, b.w, b.h, b.d, b.u, b.v)
+			p := mobPart{box: b}
+			p.count = createVAO(&p.vao, &p.vbo, vertices)
+			mm.parts = append(mm.parts, p)
+		}
+	}
+	return nil
+}
+
+func (m *Mobs) Render(ctx renderer.RenderContext) {
+	if ctx.World == nil {
+		return
+	}
+	eye := ctx.Snapshot.Eye
+	viewProj := ctx.Proj.Mul4(ctx.View)
+	maxDist := float32(config.GetEntityRenderDistance() * world.ChunkSizeX)
+	maxDistSq := maxDist * maxDist
+	renderedMobs, distantMobs = 0, 0
+	drawn := false
+	for _, e := range ctx.World.GetEntities() {
+		z, isZombie := e.(*entity.Zombie)
+		mob, isMob := e.(*entity.Mob)
+		if !isZombie && !isMob {
+			continue
+		}
+		if p := e.Position(); (p.X()-eye.X())*(p.X()-eye.X())+(p.Z()-eye.Z())*(p.Z()-eye.Z()) > maxDistSq {
+			distantMobs++
+			continue
+		}
+		renderedMobs++
+		if isZombie {
+			t := entity.RenderTransform(z, ctx.EntityPartialTick)
+			m.zombie.RenderWorldPlayer(ctx.View, ctx.Proj, WorldPose{
+				Pos: t.Pos, Yaw: t.Yaw,
+				LimbSwing: z.LimbSwing, LimbAmount: z.LimbAmount,
+				Reaching: true,
+				Flashing: z.Flashing(), DeathTilt: z.DeathTilt(),
+			})
+			// The player model leaves its own shader bound
+			drawn = false
+			continue
+		}
+		if !drawn {
+			m.shader.Use()
+			m.shader.SetMatrix4("proj", &viewProj[0])
+			m.shader.SetInt("skinTexture", 0)
+			gl.ActiveTexture(gl.TEXTURE0)
+			gl.Disable(gl.CULL_FACE)
+			drawn = true
+		}
+		m.renderMob(mob, ctx.EntityPartialTick)
+	}
+	if drawn {
+		gl.BindVertexArray(0)
+		gl.Enable(gl.CULL_FACE)
+	}
+}
+
+// renderMob draws mob partialTicks between its last two ticks, facing the
+// way it walks, with its legs swinging as MC's quadruped model does. It
+// flashes red when hurt and falls onto its side as it dies.
+func (m *Mobs) renderMob(mob *entity.Mob, partialTicks float32) {
+	model := &m.models[mob.Kind]
+	t := entity.RenderTransform(mob, partialTicks)
+	pos := t.Pos
+	base := mgl32.Translate3D(pos[0], pos[1], pos[2]).
+		Mul4(mgl32.HomogRotate3DY(mgl32.DegToRad(90 - t.Yaw))).
+		Mul4(fallOver(mob.DeathTilt())).
+		Mul4(mgl32.Scale3D(0.0625, 0.0625, 0.0625))
+
+	m.shader.SetVector3("lightPos", pos[0], pos[1]+16, pos[2])
+	m.shader.SetVector3("viewPos", pos[0], pos[1]+16, pos[2])
+	setOverlay(m.shader, mob.Flashing())
+	gl.BindTexture(gl.TEXTURE_2D, model.texture)
+
+	swing := float32(math.Cos(float64(mob.LimbSwing*0.6662))) * 1.4 * mob.LimbAmount
+	for _, p := range model.parts {
+		mat := base
+		if b := p.box; b.leg {
+			px, py, pz := b.x+b.w/2, b.y+b.h, b.z+b.d/2
+			mat = base.Mul4(mgl32.Translate3D(px, py, pz)).
+				Mul4(mgl32.HomogRotate3DX(swing * b.phase)).
+				Mul4(mgl32.Translate3D(-px, -py, -pz))
+		}
+		m.shader.SetMatrix4("model", &mat[0])
+		gl.BindVertexArray(p.vao)
+		gl.DrawArrays(gl.TRIANGLES, 0, p.count)
+	}
+}
+
+func (m *Mobs) Dispose() {
+	m.zombie.Dispose()
+	for i := range m.models {
+		model := &m.models[i]
+		for j := range model.parts {
+			p := &model.parts[j]
+			gl.DeleteVertexArrays(1, &p.vao)
+			gl.DeleteBuffers(1, &p.vbo)
+		}
+		model.parts = nil
+		if model.texture != 0 {
+			gl.DeleteTextures(1, &model.texture)
+		}
+	}
+}
+
+func (m *Mobs) SetViewport(width, height int) {}
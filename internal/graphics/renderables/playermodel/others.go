@@ -0,0 +1,39 @@
+package playermodel
+
+import (
+	"mini-mc/internal/graphics/renderer"
+)
+
+// Others draws the other players in a multiplayer world, in the opaque
+// stage. The session tells it where they are each frame; it draws nobody
+// in single player.
+type Others struct {
+	model *PlayerModel
+	poses []WorldPose
+}
+
+// NewOthers creates the renderable for other players.
+func NewOthers() *Others {
+	return &Others{model: NewPlayerModel()}
+}
+
+func (o *Others) Init() error {
+	return o.model.Init()
+}
+
+// Set replaces the players drawn from the next frame on.
+func (o *Others) Set(poses []WorldPose) {
+	o.poses = append(o.poses[:0], poses...)
+}
+
+func (o *Others) Render(ctx renderer.RenderContext) {
+	for _, pose := range o.poses {
+		o.model.RenderWorldPlayer(ctx.View, ctx.Proj, pose)
+	}
+}
+
+func (o *Others) Dispose() {
+	o.model.Dispose()
+}
+
+func (o *Others) SetViewport(width, height int) {}
@@ -50,11 +50,18 @@ type PlayerModel struct {
 	headVertexCount int32
 
 	texture uint32
+	skin    string // texture path
 }
 
 // NewPlayerModel creates a new player model renderable
 func NewPlayerModel() *PlayerModel {
-	return &PlayerModel{}
+	return &PlayerModel{skin: "assets/textures/entity/steve.png"}
+}
+
+// newSkinnedModel creates a player-shaped model wearing another skin, for
+// mobs such as zombies.
+func newSkinnedModel(skin string) *PlayerModel {
+	return &PlayerModel{skin: skin}
 }
 
 // Init initializes the player rendering system
@@ -73,8 +80,7 @@ func (m *PlayerModel) Init() error {
 	m.setupLeftLegVO()
 	m.setupHeadVO()
 
-	// Load skin (Steve)
-	m.texture, _, _, err = graphics.LoadTexture("assets/textures/entity/steve.png")
+	m.texture, _, _, err = graphics.LoadTexture(m.skin)
 	if err != nil {
 		return err
 	}
@@ -227,6 +233,74 @@ func (m *PlayerModel) RenderInventoryPlayer(p *player.Player, startX, startY flo
 	gl.Disable(gl.DEPTH_TEST)
 }
 
+// WorldPose is how a player stands in the world for RenderWorldPlayer.
+type WorldPose struct {
+	Pos        mgl32.Vec3 // feet
+	Yaw, Pitch float32    // degrees, as the player's camera
+	LimbSwing  float32    // distance walked, which sets the phase of the stride
+	LimbAmount float32    // 0 standing to 1 walking, how far the limbs swing
+	Reaching   bool       // arms held straight out ahead, as a zombie's
+	Flashing   bool       // tinted red after a hit
+	DeathTilt  float32    // degrees fallen onto the side while dying
+}
+
+// RenderWorldPlayer draws another player in the world, with the same skin
+// and parts as the inventory model. The body faces the way they look, the
+// head tilts with their pitch, and arms and legs swing as MC's biped model
+// does when walking. A hurt zombie flashes and falls over as other mobs
+// do. Face culling is back on afterwards, as the opaque
+// stage has it.
+func (m *PlayerModel) RenderWorldPlayer(view, proj mgl32.Mat4, pose WorldPose) {
+	viewProj := proj.Mul4(view)
+
+	// The model faces +Z; the camera looks along (cos yaw, sin yaw)
+	bodyModel := mgl32.Translate3D(pose.Pos[0], pose.Pos[1], pose.Pos[2]).
+		Mul4(mgl32.HomogRotate3DY(mgl32.DegToRad(90 - pose.Yaw))).
+		Mul4(fallOver(pose.DeathTilt)).
+		Mul4(mgl32.Scale3D(0.0625*0.9375, 0.0625*0.9375, 0.0625*0.9375))
+
+	m.shader.Use()
+	m.shader.SetMatrix4("proj", &viewProj[0])
+	m.shader.SetVector3("lightPos", pose.Pos[0], pose.Pos[1]+16, pose.Pos[2])
+	m.shader.SetVector3("viewPos", pose.Pos[0], pose.Pos[1]+16, pose.Pos[2])
+	setOverlay(m.shader, pose.Flashing)
+
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, m.texture)
+	m.shader.SetInt("skinTexture", 0)
+	gl.Disable(gl.CULL_FACE)
+
+	draw := func(model mgl32.Mat4, vao uint32, count int32) {
+		m.shader.SetMatrix4("model", &model[0])
+		gl.BindVertexArray(vao)
+		gl.DrawArrays(gl.TRIANGLES, 0, count)
+	}
+	// limb rotates a part about the pivot at height y by angle around X
+	limb := func(x, y, angle float32) mgl32.Mat4 {
+		return bodyModel.Mul4(mgl32.Translate3D(x, y, 0)).
+			Mul4(mgl32.HomogRotate3DX(angle)).
+			Mul4(mgl32.Translate3D(-x, -y, 0))
+	}
+
+	phase := float64(pose.LimbSwing * 0.6662)
+	legSwing := float32(math.Cos(phase)) * 1.4 * pose.LimbAmount
+	armSwing := float32(math.Cos(phase)) * pose.LimbAmount
+	armRaise := float32(0)
+	if pose.Reaching {
+		armRaise, armSwing = -math.Pi/2, armSwing*0.1
+	}
+
+	draw(bodyModel, m.torsoVAO, m.torsoVertexCount)
+	draw(limb(-2, 12, legSwing), m.rightLegVAO, m.rightLegVertexCount)
+	draw(limb(2, 12, -legSwing), m.leftLegVAO, m.leftLegVertexCount)
+	draw(limb(-5, 22, armRaise-armSwing), m.rightArmVAO, m.rightArmVertexCount)
+	draw(limb(5, 22, armRaise+armSwing), m.leftArmVAO, m.leftArmVertexCount)
+	draw(limb(0, 24, -mgl32.DegToRad(pose.Pitch)), m.headVAO, m.headVertexCount)
+
+	gl.BindVertexArray(0)
+	gl.Enable(gl.CULL_FACE)
+}
+
 func (m *PlayerModel) Dispose() {
 	if m.torsoVAO != 0 {
 		gl.DeleteVertexArrays(1, &m.torsoVAO)
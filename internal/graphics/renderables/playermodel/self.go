@@ -0,0 +1,51 @@
+package playermodel
+
+import (
+	"mini-mc/internal/graphics/renderer"
+	"mini-mc/internal/player"
+)
+
+// Self draws the player's own body in third person, in the opaque stage.
+// In first person only the hand shows.
+type Self struct {
+	model *PlayerModel
+}
+
+// NewSelf creates the renderable for the player's own body.
+func NewSelf() *Self {
+	return &Self{model: NewPlayerModel()}
+}
+
+func (s *Self) Init() error {
+	return s.model.Init()
+}
+
+func (s *Self) Render(ctx renderer.RenderContext) {
+	p := ctx.Player
+	if p == nil || p.Perspective == player.FirstPerson || p.IsDead() {
+		return
+	}
+	s.model.RenderWorldPlayer(ctx.View, ctx.Proj, selfPose(p))
+}
+
+// selfPose returns how the player stands this frame. The stride follows
+// DistanceWalkedModified between the last two ticks, which grows by 0.6 a
+// block where the model's LimbSwing grows by 4, and the limbs swing as far
+// as MC's do for the distance covered in the last tick.
+func selfPose(p *player.Player) WorldPose {
+	walked := p.DistanceWalkedModified - p.PrevDistanceWalkedModified
+	distance := p.PrevDistanceWalkedModified + walked*float64(p.PartialTick)
+	return WorldPose{
+		Pos:        p.RenderPosition(),
+		Yaw:        float32(p.CamYaw),
+		Pitch:      float32(p.CamPitch),
+		LimbSwing:  float32(distance / 0.6 * 4),
+		LimbAmount: min(float32(walked/0.6*4), 1),
+	}
+}
+
+func (s *Self) Dispose() {
+	s.model.Dispose()
+}
+
+func (s *Self) SetViewport(width, height int) {}
@@ -0,0 +1,297 @@
+package sky
+
+import (
+	"math"
+	"math/rand"
+	"path/filepath"
+
+	"mini-mc/internal/graphics"
+	"mini-mc/internal/graphics/renderer"
+	"mini-mc/internal/profiling"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	ShadersDir  = "assets/shaders/sky"
+	TexturesDir = "assets/textures/environment"
+)
+
+var (
+	VertShader          = filepath.Join(ShadersDir, "sky.vert")
+	FragShader          = filepath.Join(ShadersDir, "sky.frag")
+	CelestialVertShader = filepath.Join(ShadersDir, "celestial.vert")
+	CelestialFragShader = filepath.Join(ShadersDir, "celestial.frag")
+	SunTexture          = filepath.Join(TexturesDir, "sun.png")
+	MoonTexture         = filepath.Join(TexturesDir, "moon.png")
+)
+
+// The sun, moon and stars sit on a sphere skyRadius blocks around the eye,
+// turning about the Z axis with the world's celestial angle as the sun does.
+const (
+	skyRadius    = 100
+	sunSize      = 30 // half the side of the sun's quad
+	moonSize     = 20
+	starCount    = 1500
+	starSeed     = 10842
+	starMinSize  = 0.2
+	starMaxSize  = 0.35
+	starMaxLight = 0.75 // star brightness in the dead of night
+)
+
+// The sky overhead and along the horizon at noon and at midnight; the dome
+// blends between them by the world's daylight.
+var (
+	dayZenith    = mgl32.Vec3{0.40, 0.65, 0.95}
+	dayHorizon   = mgl32.Vec3{0.70, 0.85, 0.95}
+	nightZenith  = mgl32.Vec3{0.005, 0.005, 0.02}
+	nightHorizon = mgl32.Vec3{0.03, 0.03, 0.06}
+)
+
+// Sky draws the sky behind the world: a dome shading from the horizon to the
+// zenith with a glow where the sun rises and sets, the sun and moon crossing
+// it with the time of day, and stars at night. It goes first in the opaque
+// stage and writes no depth, so everything else draws over it.
+type Sky struct {
+	domeShader      *graphics.Shader
+	celestialShader *graphics.Shader
+	domeVAO         uint32 // empty; the vertex shader makes a full-screen triangle
+
+	quadVAO, quadVBO uint32 // the sun's quad, then the moon's
+	starVAO, starVBO uint32
+	starVertices     int32
+
+	sunTex, moonTex uint32
+}
+
+// NewSky creates a new sky renderable
+func NewSky() *Sky {
+	return &Sky{}
+}
+
+// Init loads the sky's shaders and textures and builds the sun, moon and
+// star meshes.
+func (s *Sky) Init() error {
+	var err error
+	s.domeShader, err = graphics.NewShader(VertShader, FragShader)
+	if err != nil {
+		return err
+	}
+	s.celestialShader, err = graphics.NewShader(CelestialVertShader, CelestialFragShader)
+	if err != nil {
+		return err
+	}
+	s.sunTex, _, _, err = graphics.LoadTexture(SunTexture)
+	if err != nil {
+		return err
+	}
+	s.moonTex, _, _, err = graphics.LoadTexture(MoonTexture)
+	if err != nil {
+		return err
+	}
+
+	gl.GenVertexArrays(1, &s.domeVAO)
+	var quads []float32
+	quads = appendQuad(quads, skyRadius, sunSize)
+	quads = appendQuad(quads, -skyRadius, moonSize)
+	createVAO(&s.quadVAO, &s.quadVBO, quads)
+	stars := starField(starCount, starSeed)
+	s.starVertices = createVAO(&s.starVAO, &s.starVBO, stars)
+	return nil
+}
+
+// Render draws the sky with depth testing and writes off.
+func (s *Sky) Render(ctx renderer.RenderContext) {
+	if ctx.World == nil {
+		return
+	}
+	defer profiling.Track("renderer.renderSky")()
+
+	// The sky is infinitely far away, so it turns with the eye but never
+	// moves with it
+	view := ctx.View
+	view[12], view[13], view[14] = 0, 0, 0
+	viewProj := ctx.Proj.Mul4(view)
+	invViewProj := viewProj.Inv()
+
+	daylight := ctx.World.Daylight()
+	sun := ctx.World.SunDirection()
+	zenith, horizon := skyColors(daylight)
+	sunset := sunsetColor(sun.Y())
+	sunsetDir := mgl32.Vec3{sun.X(), 0, 0}
+	if sunsetDir.Len() > 0 {
+		sunsetDir = sunsetDir.Normalize()
+	}
+
+	gl.DepthMask(false)
+	gl.Disable(gl.DEPTH_TEST)
+
+	s.domeShader.Use()
+	s.domeShader.SetMatrix4("invViewProj", &invViewProj[0])
+	s.domeShader.SetVector3("zenithColor", zenith.X(), zenith.Y(), zenith.Z())
+	s.domeShader.SetVector3("horizonColor", horizon.X(), horizon.Y(), horizon.Z())
+	s.domeShader.SetVector4("sunsetColor", sunset.X(), sunset.Y(), sunset.Z(), sunset.W())
+	s.domeShader.SetVector3("sunsetDir", sunsetDir.X(), sunsetDir.Y(), sunsetDir.Z())
+	gl.BindVertexArray(s.domeVAO)
+	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+
+	// The sun, moon and stars add their light to the dome behind them
+	model := mgl32.HomogRotate3DZ(float32(ctx.World.CelestialAngle() * 2 * math.Pi))
+	s.celestialShader.Use()
+	s.celestialShader.SetMatrix4("viewProj", &viewProj[0])
+	s.celestialShader.SetMatrix4("model", &model[0])
+	s.celestialShader.SetInt("tex", 0)
+	gl.Disable(gl.CULL_FACE)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE)
+
+	if light := starBrightness(daylight); light > 0 {
+		s.celestialShader.SetBool("textured", false)
+		s.celestialShader.SetVector4("color", light, light, light, light)
+		gl.BindVertexArray(s.starVAO)
+		gl.DrawArrays(gl.TRIANGLES, 0, s.starVertices)
+	}
+
+	s.celestialShader.SetBool("textured", true)
+	s.celestialShader.SetVector4("color", 1, 1, 1, 1)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindVertexArray(s.quadVAO)
+	gl.BindTexture(gl.TEXTURE_2D, s.sunTex)
+	gl.DrawArrays(gl.TRIANGLES, 0, 6)
+	gl.BindTexture(gl.TEXTURE_2D, s.moonTex)
+	gl.DrawArrays(gl.TRIANGLES, 6, 6)
+
+	gl.BindVertexArray(0)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.Disable(gl.BLEND)
+	gl.Enable(gl.CULL_FACE)
+	gl.Enable(gl.DEPTH_TEST)
+	gl.DepthMask(true)
+}
+
+// Dispose cleans up OpenGL resources
+func (s *Sky) Dispose() {
+	for _, vao := range []*uint32{&s.domeVAO, &s.quadVAO, &s.starVAO} {
+		if *vao != 0 {
+			gl.DeleteVertexArrays(1, vao)
+		}
+	}
+	for _, vbo := range []*uint32{&s.quadVBO, &s.starVBO} {
+		if *vbo != 0 {
+			gl.DeleteBuffers(1, vbo)
+		}
+	}
+	for _, tex := range []*uint32{&s.sunTex, &s.moonTex} {
+		if *tex != 0 {
+			gl.DeleteTextures(1, tex)
+		}
+	}
+}
+
+// SetViewport is a no-op; the dome covers whatever the viewport is.
+func (s *Sky) SetViewport(width, height int) {}
+
+// createVAO uploads vertices of position and texture coordinates and returns
+// how many there are.
+func createVAO(vao, vbo *uint32, vertices []float32) int32 {
+	gl.GenVertexArrays(1, vao)
+	gl.GenBuffers(1, vbo)
+	gl.BindVertexArray(*vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, *vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STATIC_DRAW)
+	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 5*4, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, 5*4, gl.PtrOffset(3*4))
+	gl.EnableVertexAttribArray(1)
+	gl.BindVertexArray(0)
+	return int32(len(vertices) / 5)
+}
+
+// appendQuad adds the two triangles of a square 2*size across lying flat at
+// height y, facing the eye at the origin.
+func appendQuad(vertices []float32, y, size float32) []float32 {
+	return append(vertices,
+		-size, y, -size, 0, 0,
+		size, y, -size, 1, 0,
+		size, y, size, 1, 1,
+		-size, y, -size, 0, 0,
+		size, y, size, 1, 1,
+		-size, y, size, 0, 1,
+	)
+}
+
+// starField returns the quads of count stars scattered evenly over the sky
+// sphere, each a random size and turned a random way. The same seed always
+// gives the same stars.
+func starField(count int, seed int64) []float32 {
+	rnd := rand.New(rand.NewSource(seed))
+	vertices := make([]float32, 0, count*6*5)
+	for placed := 0; placed < count; {
+		// Points picked in the unit cube and kept inside the ball are spread
+		// evenly over directions
+		p := mgl32.Vec3{rnd.Float32()*2 - 1, rnd.Float32()*2 - 1, rnd.Float32()*2 - 1}
+		if l := p.Len(); l >= 1 || l < 0.1 {
+			continue
+		}
+		n := p.Normalize()
+		centre := n.Mul(skyRadius)
+		size := starMinSize + rnd.Float32()*(starMaxSize-starMinSize)
+
+		up := mgl32.Vec3{0, 1, 0}
+		if abs32(n.Y()) > 0.99 {
+			up = mgl32.Vec3{1, 0, 0}
+		}
+		t1 := n.Cross(up).Normalize()
+		t2 := n.Cross(t1)
+		roll := float64(rnd.Float32()) * 2 * math.Pi
+		var corners [4]mgl32.Vec3
+		for i := range corners {
+			a := roll + float64(i)*math.Pi/2
+			offset := t1.Mul(float32(math.Cos(a))).Add(t2.Mul(float32(math.Sin(a))))
+			corners[i] = centre.Add(offset.Mul(size))
+		}
+		for _, i := range [6]int{0, 1, 2, 0, 2, 3} {
+			c := corners[i]
+			vertices = append(vertices, c.X(), c.Y(), c.Z(), 0, 0)
+		}
+		placed++
+	}
+	return vertices
+}
+
+// skyColors returns the colour of the sky overhead and at the horizon with
+// the given daylight.
+func skyColors(daylight float32) (zenith, horizon mgl32.Vec3) {
+	zenith = dayZenith.Sub(nightZenith).Mul(daylight).Add(nightZenith)
+	horizon = dayHorizon.Sub(nightHorizon).Mul(daylight).Add(nightHorizon)
+	return zenith, horizon
+}
+
+// sunsetColor returns the glow along the horizon with the sun at height
+// sunY (the sin of its elevation), its strength in W. It is strongest with
+// the sun on the horizon and gone once it is 0.4 above or below.
+func sunsetColor(sunY float32) mgl32.Vec4 {
+	const span = 0.4
+	if sunY <= -span || sunY >= span {
+		return mgl32.Vec4{}
+	}
+	f := sunY/span*0.5 + 0.5
+	strength := 1 - (1-float32(math.Sin(float64(f)*math.Pi)))*0.99
+	return mgl32.Vec4{f*0.3 + 0.7, f*f*0.7 + 0.2, 0.2, strength * strength}
+}
+
+// starBrightness returns how bright the stars shine with the given daylight:
+// brightest at midnight, gone before the day is fully light.
+func starBrightness(daylight float32) float32 {
+	dark := max(1-daylight*1.25, 0)
+	return dark * dark * starMaxLight
+}
+
+func abs32(x float32) float32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
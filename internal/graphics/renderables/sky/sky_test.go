@@ -0,0 +1,67 @@
+package sky
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestStarField(t *testing.T) {
+	stars := starField(200, 1)
+	if len(stars) != 200*6*5 {
+		t.Fatalf("got %d floats, want %d", len(stars), 200*6*5)
+	}
+	up := 0
+	for i := 0; i < len(stars); i += 5 {
+		p := mgl32.Vec3{stars[i], stars[i+1], stars[i+2]}
+		if l := p.Len(); l < skyRadius-0.01 || l > skyRadius+starMaxSize {
+			t.Fatalf("star corner %v is %v from the eye, want about %v", p, l, skyRadius)
+		}
+		if p.Y() > 0 {
+			up++
+		}
+	}
+	if up < len(stars)/5/4 || up > len(stars)/5*3/4 {
+		t.Errorf("%d of %d star corners above the horizon, want about half", up, len(stars)/5)
+	}
+
+	again := starField(200, 1)
+	for i := range stars {
+		if stars[i] != again[i] {
+			t.Fatal("the same seed gave different stars")
+		}
+	}
+}
+
+func TestSkyColors(t *testing.T) {
+	if zenith, horizon := skyColors(1); zenith != dayZenith || horizon != dayHorizon {
+		t.Errorf("noon sky = %v, %v", zenith, horizon)
+	}
+	if zenith, horizon := skyColors(0); zenith != nightZenith || horizon != nightHorizon {
+		t.Errorf("midnight sky = %v, %v", zenith, horizon)
+	}
+}
+
+func TestSunsetColor(t *testing.T) {
+	if c := sunsetColor(1); c.W() != 0 {
+		t.Errorf("noon glow = %v, want none", c)
+	}
+	if c := sunsetColor(-1); c.W() != 0 {
+		t.Errorf("midnight glow = %v, want none", c)
+	}
+	if c := sunsetColor(0); c.W() < 0.99 {
+		t.Errorf("glow with the sun on the horizon = %v, want full", c)
+	}
+	if low, high := sunsetColor(0.1), sunsetColor(0.3); low.W() <= high.W() {
+		t.Errorf("glow grows as the sun climbs: %v then %v", low.W(), high.W())
+	}
+}
+
+func TestStarBrightness(t *testing.T) {
+	if got := starBrightness(1); got != 0 {
+		t.Errorf("stars by day = %v, want 0", got)
+	}
+	if got := starBrightness(0); got != starMaxLight {
+		t.Errorf("stars at midnight = %v, want %v", got, starMaxLight)
+	}
+}
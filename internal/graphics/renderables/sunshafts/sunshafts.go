@@ -0,0 +1,169 @@
+package sunshafts
+
+import (
+	"mini-mc/internal/config"
+	"mini-mc/internal/graphics"
+	"mini-mc/internal/graphics/renderer"
+	"mini-mc/internal/profiling"
+	"path/filepath"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	ShadersDir = "assets/shaders/sunshafts"
+)
+
+var (
+	VertShader = filepath.Join(ShadersDir, "sunshafts.vert")
+	FragShader = filepath.Join(ShadersDir, "sunshafts.frag")
+)
+
+const (
+	shaftStrength = 1.2  // brightness of the shafts looking straight at the noon sun
+	sunsetHeight  = 0.25 // sun height (sin of elevation) below which the shafts fade and redden
+)
+
+// The sun's light high in the sky and as it sets
+var (
+	noonSunColor   = mgl32.Vec3{1.0, 0.95, 0.8}
+	sunsetSunColor = mgl32.Vec3{1.0, 0.55, 0.25}
+)
+
+// SunShafts draws shafts of sunlight over the finished world: the sky left
+// uncovered in the depth buffer glows around the sun, and that glow is
+// blurred radially away from the sun's position on screen and added to the
+// frame.
+type SunShafts struct {
+	shader   *graphics.Shader
+	vao      uint32 // empty; the vertex shader makes a full-screen triangle
+	depthTex uint32 // copy of the frame's depth
+	width    int    // of depthTex
+	height   int
+}
+
+// NewSunShafts creates a new sun shafts renderable
+func NewSunShafts() *SunShafts {
+	return &SunShafts{}
+}
+
+// Init initializes the sun shafts shader
+func (s *SunShafts) Init() error {
+	var err error
+	s.shader, err = graphics.NewShader(VertShader, FragShader)
+	if err != nil {
+		return err
+	}
+	gl.GenVertexArrays(1, &s.vao)
+	return nil
+}
+
+// Render adds the shafts to the frame when they are on and the player looks
+// towards the sun by day.
+func (s *SunShafts) Render(ctx renderer.RenderContext) {
+	if !config.GetSunShafts() {
+		return
+	}
+	sun := ctx.World.SunDirection()
+	strength := shaftIntensity(ctx.Snapshot.Front, sun)
+	if strength <= 0 {
+		return
+	}
+	pos, ok := sunScreenPos(ctx.Proj, ctx.View, sun)
+	if !ok {
+		return
+	}
+	defer profiling.Track("renderer.renderSunShafts")()
+
+	var viewport [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+	s.ensureDepth(int(viewport[2]), int(viewport[3]))
+	gl.BindTexture(gl.TEXTURE_2D, s.depthTex)
+	gl.CopyTexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, viewport[0], viewport[1], viewport[2], viewport[3])
+
+	color := sunColor(sun).Mul(strength)
+	s.shader.Use()
+	gl.ActiveTexture(gl.TEXTURE0)
+	s.shader.SetInt("depthTex", 0)
+	s.shader.SetVector2("sunPos", pos.X(), pos.Y())
+	s.shader.SetFloat("aspect", float32(viewport[2])/float32(max(viewport[3], 1)))
+	s.shader.SetVector3("sunColor", color.X(), color.Y(), color.Z())
+
+	gl.Disable(gl.DEPTH_TEST)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.ONE, gl.ONE)
+	gl.BindVertexArray(s.vao)
+	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	gl.BindVertexArray(0)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.Disable(gl.BLEND)
+	gl.Enable(gl.DEPTH_TEST)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+}
+
+// ensureDepth (re)allocates the depth copy at width x height.
+func (s *SunShafts) ensureDepth(width, height int) {
+	if s.depthTex != 0 && s.width == width && s.height == height {
+		return
+	}
+	if s.depthTex == 0 {
+		gl.GenTextures(1, &s.depthTex)
+	}
+	s.width, s.height = width, height
+	gl.BindTexture(gl.TEXTURE_2D, s.depthTex)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.DEPTH_COMPONENT24, int32(width), int32(height), 0, gl.DEPTH_COMPONENT, gl.FLOAT, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+}
+
+// Dispose cleans up OpenGL resources
+func (s *SunShafts) Dispose() {
+	if s.vao != 0 {
+		gl.DeleteVertexArrays(1, &s.vao)
+	}
+	if s.depthTex != 0 {
+		gl.DeleteTextures(1, &s.depthTex)
+	}
+}
+
+// Stage draws the shafts over the finished world, before the hand and HUD.
+func (s *SunShafts) Stage() renderer.Stage {
+	return renderer.StageOverlay
+}
+
+// SetViewport is a no-op; the depth copy follows the GL viewport.
+func (s *SunShafts) SetViewport(width, height int) {}
+
+// shaftIntensity returns how strong the shafts are looking along front with
+// the sun in direction sun: strongest looking straight at it, gone once it
+// is 90 degrees away or below the horizon.
+func shaftIntensity(front, sun mgl32.Vec3) float32 {
+	facing := front.Dot(sun)
+	if facing <= 0 || sun.Y() <= 0 {
+		return 0
+	}
+	daylight := min(sun.Y()/sunsetHeight, 1)
+	return shaftStrength * facing * facing * daylight
+}
+
+// sunColor returns the colour of the sun's light, reddening as it sets.
+func sunColor(sun mgl32.Vec3) mgl32.Vec3 {
+	t := min(max(sun.Y()/sunsetHeight, 0), 1)
+	return sunsetSunColor.Add(noonSunColor.Sub(sunsetSunColor).Mul(t))
+}
+
+// sunScreenPos returns where the sun, infinitely far in direction sun,
+// appears on screen, with 0-1 spanning the viewport. It is false when the
+// sun is behind the camera.
+func sunScreenPos(proj, view mgl32.Mat4, sun mgl32.Vec3) (mgl32.Vec2, bool) {
+	dir := view.Mul4x1(sun.Vec4(0))
+	clip := proj.Mul4x1(dir)
+	if clip.W() <= 1e-6 {
+		return mgl32.Vec2{}, false
+	}
+	return mgl32.Vec2{clip.X()/clip.W()*0.5 + 0.5, clip.Y()/clip.W()*0.5 + 0.5}, true
+}
@@ -0,0 +1,52 @@
+package sunshafts
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestSunScreenPos(t *testing.T) {
+	proj := mgl32.Perspective(mgl32.DegToRad(60), 1.5, 0.1, 1000)
+	eye := mgl32.Vec3{10, 70, -3}
+	look := func(front mgl32.Vec3) mgl32.Mat4 {
+		return mgl32.LookAtV(eye, eye.Add(front), mgl32.Vec3{0, 1, 0})
+	}
+	sun := mgl32.Vec3{-1, 1, 0}.Normalize()
+
+	pos, ok := sunScreenPos(proj, look(sun), sun)
+	if !ok || absf(pos.X()-0.5) > 1e-4 || absf(pos.Y()-0.5) > 1e-4 {
+		t.Errorf("looking at the sun it is at %v (%v), want the centre", pos, ok)
+	}
+	pos, ok = sunScreenPos(proj, look(mgl32.Vec3{-1, 0, 0}), sun)
+	if !ok || absf(pos.X()-0.5) > 1e-4 || pos.Y() <= 1 {
+		t.Errorf("looking at the horizon below it the sun is at %v (%v), want above the screen", pos, ok)
+	}
+	if _, ok = sunScreenPos(proj, look(mgl32.Vec3{1, 0, 0}), sun); ok {
+		t.Error("sun behind the camera is on screen")
+	}
+}
+
+func TestShaftIntensity(t *testing.T) {
+	sun := mgl32.Vec3{0, 1, 0}
+	if got := shaftIntensity(sun, sun); absf(got-shaftStrength) > 1e-6 {
+		t.Errorf("looking at the noon sun = %v, want %v", got, shaftStrength)
+	}
+	if side := shaftIntensity(mgl32.Vec3{0.6, 0.8, 0}, sun); side <= 0 || side >= shaftStrength {
+		t.Errorf("looking beside the sun = %v, want between 0 and full", side)
+	}
+	if got := shaftIntensity(mgl32.Vec3{1, 0, 0}, sun); got != 0 {
+		t.Errorf("looking away from the sun = %v, want 0", got)
+	}
+	set := mgl32.Vec3{-1, 0, 0}
+	if got := shaftIntensity(set, set); got != 0 {
+		t.Errorf("sun on the horizon = %v, want 0", got)
+	}
+}
+
+func absf(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
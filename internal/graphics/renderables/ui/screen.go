@@ -0,0 +1,61 @@
+package ui
+
+import "github.com/go-gl/glfw/v3.3/glfw"
+
+// Screen is how the UI maps onto a window. The UI is laid out in units of
+// one pixel at 100% display scaling, so it is the same size on a HiDPI
+// screen as on any other: the framebuffer's pixels divided by the monitor's
+// content scale. GLFW reports the cursor in window coordinates, which are
+// framebuffer pixels on Windows and X11 but points on macOS, so they are
+// converted separately.
+type Screen struct {
+	Width, Height float32 // layout size, in UI units
+	cursorScale   float32 // UI units per window coordinate
+}
+
+// newScreen works out the layout from the window's size, its framebuffer's
+// size and the content scale.
+func newScreen(winW, fbW, fbH int, contentScale float32) Screen {
+	if contentScale <= 0 {
+		contentScale = 1
+	}
+	s := Screen{
+		Width:       float32(fbW) / contentScale,
+		Height:      float32(fbH) / contentScale,
+		cursorScale: 1,
+	}
+	if winW > 0 {
+		s.cursorScale = s.Width / float32(winW)
+	}
+	return s
+}
+
+// ScreenOf returns the layout of window as it is now.
+func ScreenOf(window *glfw.Window) Screen {
+	winW, _ := window.GetSize()
+	fbW, fbH := window.GetFramebufferSize()
+	scale, _ := window.GetContentScale()
+	return newScreen(winW, fbW, fbH, scale)
+}
+
+// Cursor converts a position in window coordinates, as GLFW reports the
+// cursor, to UI units.
+func (s Screen) Cursor(x, y float64) (float32, float32) {
+	return float32(x) * s.cursorScale, float32(y) * s.cursorScale
+}
+
+// Size returns the layout size rounded to whole units, for SetViewport.
+func (s Screen) Size() (int, int) {
+	return int(s.Width + 0.5), int(s.Height + 0.5)
+}
+
+// LayoutSize returns the size window's UI is laid out in.
+func LayoutSize(window *glfw.Window) (float32, float32) {
+	s := ScreenOf(window)
+	return s.Width, s.Height
+}
+
+// CursorPos returns the cursor position over window in UI units.
+func CursorPos(window *glfw.Window) (float32, float32) {
+	return ScreenOf(window).Cursor(window.GetCursorPos())
+}
@@ -0,0 +1,30 @@
+package ui
+
+import "testing"
+
+func TestScreenLayout(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		winW, fbW, fbH      int
+		scale               float32
+		wantW, wantH        float32
+		cursorX, wantCursor float32
+	}{
+		// Window and framebuffer alike at 100%
+		{"plain", 1280, 1280, 720, 1, 1280, 720, 640, 640},
+		// macOS Retina: the window is in points, the framebuffer twice as big
+		{"retina", 1280, 2560, 1440, 2, 1280, 720, 640, 640},
+		// Windows at 150%: the window is in pixels like the framebuffer
+		{"scaled", 1920, 1920, 1080, 1.5, 1280, 720, 960, 640},
+		// Before GLFW knows the monitor
+		{"unknown scale", 800, 800, 600, 0, 800, 600, 400, 400},
+	} {
+		s := newScreen(tc.winW, tc.fbW, tc.fbH, tc.scale)
+		if s.Width != tc.wantW || s.Height != tc.wantH {
+			t.Errorf("%s: layout %vx%v, want %vx%v", tc.name, s.Width, s.Height, tc.wantW, tc.wantH)
+		}
+		if x, _ := s.Cursor(float64(tc.cursorX), 0); x != tc.wantCursor {
+			t.Errorf("%s: cursor x %v maps to %v, want %v", tc.name, tc.cursorX, x, tc.wantCursor)
+		}
+	}
+}
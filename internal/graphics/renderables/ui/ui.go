@@ -269,8 +269,7 @@ func (u *UI) DrawSlider(x, y, w, h float32, value float32, window any, steps int
 
 	// Mouse interaction with drag capture and snapping
 	if glfwWindow, ok := window.(*glfw.Window); ok {
-		cx, cy := glfwWindow.GetCursorPos()
-		mouseX, mouseY := float32(cx), float32(cy)
+		mouseX, mouseY := CursorPos(glfwWindow)
 		leftDown := glfwWindow.GetMouseButton(glfw.MouseButtonLeft) == glfw.Press
 
 		inside := mouseY >= y && mouseY <= y+h && mouseX >= x && mouseX <= x+w
@@ -321,6 +320,11 @@ func (u *UI) DrawSlider(x, y, w, h float32, value float32, window any, steps int
 	return value
 }
 
+// SliderActive reports whether the slider with sliderID is being dragged.
+func (u *UI) SliderActive(sliderID string) bool {
+	return u.isDraggingSlider && u.activeSliderID == sliderID
+}
+
 func (u *UI) Flush() {
 	if len(u.cmds) == 0 {
 		return
@@ -472,6 +476,11 @@ func (u *UI) Flush() {
 	u.cmds = u.cmds[:0]
 }
 
+// Stage draws the UI over the finished world.
+func (u *UI) Stage() renderer.Stage {
+	return renderer.StageOverlay
+}
+
 // SetViewport updates the UI viewport dimensions
 func (u *UI) SetViewport(width, height int) {
 	u.width = float32(width)
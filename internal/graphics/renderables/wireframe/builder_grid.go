@@ -0,0 +1,101 @@
+package wireframe
+
+import (
+	"mini-mc/internal/graphics/renderer"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// builderGridRadius is how many blocks the builder mode grid reaches out
+// from the targeted block across its face.
+const builderGridRadius = 3
+
+// builderGridFloats is the size of the grid's vertex data: a line along
+// each of the face's two axes at each grid line position.
+const builderGridFloats = (2*builderGridRadius + 2) * 2 * 2 * 3
+
+// Builder mode line colours: the grid on the targeted face, and the blocks
+// placing would fill.
+var (
+	builderGridColor    = mgl32.Vec3{1.0, 1.0, 1.0}
+	builderPreviewColor = mgl32.Vec3{1.0, 0.9, 0.4}
+)
+
+func (w *Wireframe) setupGridVAO() {
+	gl.GenVertexArrays(1, &w.gridVAO)
+	gl.BindVertexArray(w.gridVAO)
+	gl.GenBuffers(1, &w.gridVBO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, w.gridVBO)
+	gl.BufferData(gl.ARRAY_BUFFER, builderGridFloats*4, nil, gl.DYNAMIC_DRAW)
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointerWithOffset(0, 3, gl.FLOAT, false, 3*4, 0)
+}
+
+// appendFaceGrid appends the lines of a grid of blocks lying on the face of
+// block b with the given normal, builderGridRadius blocks out each way,
+// lifted just off the face so it is not hidden by it.
+func appendFaceGrid(verts []float32, b, normal [3]int) []float32 {
+	n := 0
+	for i, d := range normal {
+		if d != 0 {
+			n = i
+		}
+	}
+	u, v := (n+1)%3, (n+2)%3
+	plane := float32(b[n]) - 0.002
+	if normal[n] > 0 {
+		plane = float32(b[n]+1) + 0.002
+	}
+	point := func(pu, pv float32) {
+		var p [3]float32
+		p[n], p[u], p[v] = plane, pu, pv
+		verts = append(verts, p[0], p[1], p[2])
+	}
+	var lo, hi [3]float32
+	for _, a := range []int{u, v} {
+		lo[a] = float32(b[a] - builderGridRadius)
+		hi[a] = float32(b[a] + builderGridRadius + 1)
+	}
+	for k := -builderGridRadius; k <= builderGridRadius+1; k++ {
+		cu, cv := float32(b[u]+k), float32(b[v]+k)
+		point(cu, lo[v])
+		point(cu, hi[v])
+		point(lo[u], cv)
+		point(hi[u], cv)
+	}
+	return verts
+}
+
+// renderBuilderGrid draws the builder mode grid on the face the player is
+// looking at and outlines the blocks placing would fill.
+func (w *Wireframe) renderBuilderGrid(ctx renderer.RenderContext) {
+	p := ctx.Player
+	w.shader.Use()
+	w.shader.SetMatrix4("proj", &ctx.Proj[0])
+	w.shader.SetMatrix4("view", &ctx.View[0])
+	gl.LineWidth(1.0)
+
+	if p.HasHoveredBlock {
+		w.gridVerts = appendFaceGrid(w.gridVerts[:0], p.HoveredBlock, p.HoveredFace)
+		identity := mgl32.Ident4()
+		w.shader.SetMatrix4("model", &identity[0])
+		w.shader.SetVector3("color", builderGridColor.X(), builderGridColor.Y(), builderGridColor.Z())
+		gl.BindVertexArray(w.gridVAO)
+		gl.BindBuffer(gl.ARRAY_BUFFER, w.gridVBO)
+		gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(w.gridVerts)*4, gl.Ptr(w.gridVerts))
+		gl.DrawArrays(gl.LINES, 0, int32(len(w.gridVerts)/3))
+	}
+
+	w.shader.SetVector3("color", builderPreviewColor.X(), builderPreviewColor.Y(), builderPreviewColor.Z())
+	gl.BindVertexArray(w.vao)
+	for _, box := range p.BuilderPreview() {
+		lo, hi := box[0], box[1]
+		size := mgl32.Vec3{float32(hi[0] - lo[0] + 1), float32(hi[1] - lo[1] + 1), float32(hi[2] - lo[2] + 1)}
+		centre := mgl32.Vec3{float32(lo[0]), float32(lo[1]), float32(lo[2])}.Add(size.Mul(0.5))
+		model := mgl32.Translate3D(centre.X(), centre.Y(), centre.Z()).
+			Mul4(mgl32.Scale3D(size.X()+0.01, size.Y()+0.01, size.Z()+0.01))
+		w.shader.SetMatrix4("model", &model[0])
+		gl.DrawArrays(gl.LINES, 0, 24)
+	}
+}
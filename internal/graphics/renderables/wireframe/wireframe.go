@@ -1,6 +1,7 @@
 package wireframe
 
 import (
+	"mini-mc/internal/config"
 	"mini-mc/internal/graphics"
 	"mini-mc/internal/graphics/renderer"
 	"mini-mc/internal/profiling"
@@ -24,6 +25,11 @@ type Wireframe struct {
 	shader *graphics.Shader
 	vao    uint32
 	vbo    uint32
+
+	// Builder mode grid lines, rebuilt each frame
+	gridVAO   uint32
+	gridVBO   uint32
+	gridVerts []float32
 }
 
 // NewWireframe creates a new wireframe renderable
@@ -42,18 +48,28 @@ func (w *Wireframe) Init() error {
 
 	// Setup VAO and VBO
 	w.setupWireframeVAO()
+	w.setupGridVAO()
 
 	return nil
 }
 
-// Render renders the wireframe for highlighted blocks
+// Render renders the wireframe for highlighted blocks, red for a moment on
+// a block the player was refused placing at unless flashing effects are
+// disabled, and the builder mode grid when that is on
 func (w *Wireframe) Render(ctx renderer.RenderContext) {
-	if ctx.Player.HasHoveredBlock {
+	denied := ctx.Player.PlaceDeniedFlash()
+	if ctx.Player.HasHoveredBlock && (denied == 0 || ctx.Player.HoveredBlock != ctx.Player.DeniedBlock) {
 		func() {
 			defer profiling.Track("renderer.renderHighlightedBlock")()
 			w.renderHighlightedBlock(ctx.Player.HoveredBlock, ctx.View, ctx.Proj)
 		}()
 	}
+	if denied > 0 {
+		w.renderDeniedBlock(ctx.Player.DeniedBlock, denied, ctx.View, ctx.Proj)
+	}
+	if ctx.Player.BuilderActive() {
+		w.renderBuilderGrid(ctx)
+	}
 }
 
 // Dispose cleans up OpenGL resources
@@ -64,6 +80,12 @@ func (w *Wireframe) Dispose() {
 	if w.vbo != 0 {
 		gl.DeleteBuffers(1, &w.vbo)
 	}
+	if w.gridVAO != 0 {
+		gl.DeleteVertexArrays(1, &w.gridVAO)
+	}
+	if w.gridVBO != 0 {
+		gl.DeleteBuffers(1, &w.gridVBO)
+	}
 }
 
 // SetViewport updates viewport dimensions (not needed for wireframe)
@@ -78,7 +100,7 @@ func (w *Wireframe) setupWireframeVAO() {
 	gl.GenBuffers(1, &w.vbo)
 	gl.BindBuffer(gl.ARRAY_BUFFER, w.vbo)
 
-	// Cube wireframe vertices from world.CubeWireframeVertices
+	// The 12 edges of a unit cube centred on the origin, as line pairs
 	vertices := []float32{
 		// Front face
 		-0.5, -0.5, 0.5, 0.5, -0.5, 0.5,
@@ -105,21 +127,48 @@ func (w *Wireframe) setupWireframeVAO() {
 }
 
 func (w *Wireframe) renderHighlightedBlock(blockPos [3]int, view, projection mgl32.Mat4) {
+	w.useBlockOutline(blockPos, view, projection)
+	lineWidth := float32(1.0)
+	if config.GetHighContrast() {
+		// Bright yellow, thicker outline (not all core-profile drivers honour widths above 1)
+		w.shader.SetVector3("color", 1.0, 0.9, 0.0)
+		lineWidth = 2.0
+	} else {
+		w.shader.SetVector3("color", 0.0, 0.0, 0.0) // Black outline
+	}
+
+	gl.BindVertexArray(w.vao)
+	gl.LineWidth(lineWidth)
+	gl.DrawArrays(gl.LINES, 0, 24) // 24 vertices for cube wireframe
+}
+
+// renderDeniedBlock draws the outline of a block a placement was refused
+// at, red fading to the usual outline colour as flash goes from 1 to 0.
+func (w *Wireframe) renderDeniedBlock(blockPos [3]int, flash float32, view, projection mgl32.Mat4) {
+	w.useBlockOutline(blockPos, view, projection)
+	base := mgl32.Vec3{0.0, 0.0, 0.0}
+	if config.GetHighContrast() {
+		base = mgl32.Vec3{1.0, 0.9, 0.0}
+	}
+	c := base.Mul(1 - flash).Add(mgl32.Vec3{1.0, 0.15, 0.1}.Mul(flash))
+	w.shader.SetVector3("color", c[0], c[1], c[2])
+
+	gl.BindVertexArray(w.vao)
+	gl.LineWidth(2.0)
+	gl.DrawArrays(gl.LINES, 0, 24)
+}
+
+// useBlockOutline binds the shader with the cube outline placed around the
+// block at blockPos, a little larger so it is not hidden by the faces.
+func (w *Wireframe) useBlockOutline(blockPos [3]int, view, projection mgl32.Mat4) {
 	w.shader.Use()
 	w.shader.SetMatrix4("proj", &projection[0])
 	w.shader.SetMatrix4("view", &view[0])
 
-	// Create model matrix for the highlighted block
 	model := mgl32.Translate3D(
 		float32(blockPos[0])+0.5,
 		float32(blockPos[1])+0.5,
 		float32(blockPos[2])+0.5,
 	).Mul4(mgl32.Scale3D(1.01, 1.01, 1.01))
-
 	w.shader.SetMatrix4("model", &model[0])
-	w.shader.SetVector3("color", 0.0, 0.0, 0.0) // Black outline
-
-	gl.BindVertexArray(w.vao)
-	gl.LineWidth(1.0)
-	gl.DrawArrays(gl.LINES, 0, 24) // 24 vertices for cube wireframe
 }
@@ -16,6 +16,17 @@ type RenderContext struct {
 	DT     float64
 	View   mgl32.Mat4
 	Proj   mgl32.Mat4
+
+	// Snapshot is the player's camera for the frame, which View is built from
+	Snapshot player.CameraSnapshot
+
+	// EntityPartialTick is how far from their last two ticks entities are
+	// drawn (see entity.RenderTransform)
+	EntityPartialTick float32
+
+	// Offscreen is set when drawing a view other than the player's own, such
+	// as a panorama face, so per-view state like occlusion results is left be
+	Offscreen bool
 }
 
 // Renderable interface defines the lifecycle for renderable features
@@ -25,3 +36,42 @@ type Renderable interface {
 	Dispose()
 	SetViewport(width, height int)
 }
+
+// Stage is the part of a frame a renderable draws in. A frame draws the
+// opaque stage, then the translucent stage, then the overlay stage.
+type Stage int
+
+const (
+	// StageOpaque draws depth-writing world geometry. Renderables that do
+	// not implement StagedRenderable draw here.
+	StageOpaque Stage = iota
+	// StageOverlay draws over the finished world: first-person and
+	// screen-space elements such as the hand, crosshair and HUD.
+	StageOverlay
+)
+
+// StagedRenderable is implemented by renderables that draw outside the
+// opaque stage.
+type StagedRenderable interface {
+	Stage() Stage
+}
+
+// TranslucentRenderable contributes blended geometry to the translucent
+// stage. The stage runs after all opaque geometry is drawn and draws the
+// batches of every renderable together, farthest first, with alpha blending
+// on, depth testing against the opaque depth, depth writes off and face
+// culling off. Renderables must not change that state themselves.
+type TranslucentRenderable interface {
+	// AppendTranslucent appends the batches to draw this frame. It runs
+	// after the opaque stage.
+	AppendTranslucent(ctx RenderContext, dst []TranslucentBatch) []TranslucentBatch
+	// DrawTranslucent draws the batch with the given index.
+	DrawTranslucent(ctx RenderContext, index int)
+}
+
+// TranslucentBatch is geometry drawn in one go within the translucent stage.
+type TranslucentBatch struct {
+	Depth float32               // squared distance from the eye; farther batches draw first
+	Owner TranslucentRenderable // draws the batch
+	Index int                   // passed back to Owner.DrawTranslucent
+}
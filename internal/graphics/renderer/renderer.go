@@ -1,11 +1,29 @@
 package renderer
 
 import (
+	"cmp"
+	"slices"
+
+	"mini-mc/internal/config"
 	"mini-mc/internal/graphics"
 	"mini-mc/internal/player"
 	"mini-mc/internal/world"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// lowHealthFOVPulse is the peak FOV reduction (degrees) of a heartbeat at zero health.
+const lowHealthFOVPulse = 3.0
+
+// sprintFOVBoost is how many degrees sprinting widens the configured FOV.
+const sprintFOVBoost = 10.0
+
+// The sky colour at noon and at midnight; the clear colour blends between
+// them by the world's daylight.
+var (
+	daySky   = mgl32.Vec3{0.53, 0.81, 0.92}
+	nightSky = mgl32.Vec3{0.01, 0.01, 0.03}
 )
 
 // Renderer orchestrates rendering via renderable features
@@ -13,6 +31,14 @@ type Renderer struct {
 	renderables []Renderable
 	camera      *graphics.Camera
 
+	// Renderables by stage, in the order given to NewRenderer
+	opaque      []Renderable
+	translucent []TranslucentRenderable
+	overlay     []Renderable
+	batches     []TranslucentBatch // reused each frame
+
+	entityPartialTick float32 // see SetEntityPartialTick
+
 	// FOV transition
 	targetFOV  float32
 	currentFOV float32
@@ -32,8 +58,8 @@ func NewRenderer(rs ...Renderable) (*Renderer, error) {
 	renderer := &Renderer{
 		renderables: rs,
 		camera:      camera,
-		targetFOV:   60.0,
-		currentFOV:  60.0,
+		targetFOV:   float32(config.GetFOV()),
+		currentFOV:  float32(config.GetFOV()),
 	}
 
 	// Initialize all renderables
@@ -41,6 +67,14 @@ func NewRenderer(rs ...Renderable) (*Renderer, error) {
 		if err := r.Init(); err != nil {
 			return nil, err
 		}
+		if s, ok := r.(StagedRenderable); ok && s.Stage() == StageOverlay {
+			renderer.overlay = append(renderer.overlay, r)
+		} else {
+			renderer.opaque = append(renderer.opaque, r)
+		}
+		if t, ok := r.(TranslucentRenderable); ok {
+			renderer.translucent = append(renderer.translucent, t)
+		}
 	}
 
 	return renderer, nil
@@ -48,15 +82,13 @@ func NewRenderer(rs ...Renderable) (*Renderer, error) {
 
 // Render executes the main render loop
 func (r *Renderer) Render(w *world.World, p *player.Player, dt float64) {
-	// Clear the screen
-	gl.ClearColor(0.53, 0.81, 0.92, 1.0)
-	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	clearToSky(w)
 
 	// Update FOV smoothly based on sprinting and horizontal speed
 	{
 		// Base and sprint FOVs
-		normalFOV := float32(60.0)
-		sprintFOV := float32(70.0)
+		normalFOV := float32(config.GetFOV())
+		sprintFOV := normalFOV + sprintFOVBoost
 		// Horizontal speed magnitude
 		hs := float32(p.Velocity[0]*p.Velocity[0] + p.Velocity[2]*p.Velocity[2])
 		isMovingFast := hs > 0.01
@@ -79,28 +111,113 @@ func (r *Renderer) Render(w *world.World, p *player.Player, dt float64) {
 				r.currentFOV = r.targetFOV
 			}
 		}
-		// Apply
+		// Apply, with a slight squeeze on each low-health heartbeat
 		r.camera.FOV = r.currentFOV
+		if !config.GetReducedMotion() && !config.GetDisableFlashing() {
+			r.camera.FOV -= lowHealthFOVPulse * p.HeartbeatPulse() * p.LowHealthIntensity()
+		}
 	}
 
-	// Compute view and projection matrices
-	view := p.GetViewMatrix()
+	// Compute view and projection matrices, from behind or in front of the
+	// player in third person
+	cam := p.RenderCamera()
+	view := p.ViewMatrix(cam)
 	projection := r.camera.GetProjectionMatrix()
 
 	// Create render context
 	ctx := RenderContext{
-		Camera: r.camera,
-		World:  w,
-		Player: p,
-		DT:     dt,
-		View:   view,
-		Proj:   projection,
+		Camera:   r.camera,
+		World:    w,
+		Player:   p,
+		DT:       dt,
+		View:     view,
+		Proj:     projection,
+		Snapshot: cam,
+
+		EntityPartialTick: r.entityPartialTick,
+		Offscreen:         true,
 	}
 
-	// Render all features
-	for _, renderable := range r.renderables {
+	for _, renderable := range r.opaque {
+		renderable.Render(ctx)
+	}
+	r.renderTranslucent(ctx)
+	for _, renderable := range r.overlay {
+		renderable.Render(ctx)
+	}
+}
+
+// RenderView draws the world from the player's eye looking along front, with
+// up at the top, through a square projection fov degrees wide, into the
+// bound framebuffer and viewport. Only the opaque and translucent stages
+// run: the overlays belong to the player's own view. Used for panoramas.
+func (r *Renderer) RenderView(w *world.World, p *player.Player, front, up mgl32.Vec3, fov float32) {
+	clearToSky(w)
+
+	cam := p.Camera()
+	cam.Front = front
+	ctx := RenderContext{
+		Camera:   r.camera,
+		World:    w,
+		Player:   p,
+		View:     mgl32.LookAtV(cam.Eye, cam.Eye.Add(front), up),
+		Proj:     mgl32.Perspective(mgl32.DegToRad(fov), 1, r.camera.NearPlane, r.camera.FarPlane),
+		Snapshot: cam,
+
+		EntityPartialTick: r.entityPartialTick,
+	}
+
+	for _, renderable := range r.opaque {
 		renderable.Render(ctx)
 	}
+	r.renderTranslucent(ctx)
+}
+
+// clearToSky clears the bound framebuffer to the sky, blue by day fading to
+// near black at night.
+func clearToSky(w *world.World) {
+	sky := daySky.Sub(nightSky).Mul(w.Daylight()).Add(nightSky)
+	gl.ClearColor(sky.X(), sky.Y(), sky.Z(), 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+}
+
+// SetEntityPartialTick sets how far between their last two ticks entities
+// are drawn from the next frame on.
+func (r *Renderer) SetEntityPartialTick(partialTicks float32) {
+	r.entityPartialTick = partialTicks
+}
+
+// renderTranslucent draws the translucent batches of all renderables back to
+// front, so blended surfaces composite correctly over each other and over
+// the opaque world.
+func (r *Renderer) renderTranslucent(ctx RenderContext) {
+	r.batches = r.batches[:0]
+	for _, t := range r.translucent {
+		r.batches = t.AppendTranslucent(ctx, r.batches)
+	}
+	if len(r.batches) == 0 {
+		return
+	}
+	SortTranslucent(r.batches)
+
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.DepthMask(false)
+	gl.Disable(gl.CULL_FACE)
+	for _, b := range r.batches {
+		b.Owner.DrawTranslucent(ctx, b.Index)
+	}
+	gl.Enable(gl.CULL_FACE)
+	gl.DepthMask(true)
+	gl.Disable(gl.BLEND)
+}
+
+// SortTranslucent orders batches farthest first. Batches at the same depth
+// keep their order, so a renderable listed earlier draws first.
+func SortTranslucent(batches []TranslucentBatch) {
+	slices.SortStableFunc(batches, func(a, b TranslucentBatch) int {
+		return cmp.Compare(b.Depth, a.Depth)
+	})
 }
 
 // Dispose cleans up all renderables in reverse order
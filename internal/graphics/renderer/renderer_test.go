@@ -0,0 +1,34 @@
+package renderer
+
+import "testing"
+
+type fakeTranslucent struct{ name string }
+
+func (f *fakeTranslucent) AppendTranslucent(ctx RenderContext, dst []TranslucentBatch) []TranslucentBatch {
+	return dst
+}
+
+func (f *fakeTranslucent) DrawTranslucent(ctx RenderContext, index int) {}
+
+func TestSortTranslucentFarthestFirstAndStable(t *testing.T) {
+	fluids := &fakeTranslucent{"fluids"}
+	particles := &fakeTranslucent{"particles"}
+	batches := []TranslucentBatch{
+		{Depth: 10, Owner: fluids, Index: 0},
+		{Depth: 50, Owner: fluids, Index: 1},
+		{Depth: 10, Owner: particles, Index: 0},
+		{Depth: 90, Owner: particles, Index: 1},
+	}
+	SortTranslucent(batches)
+
+	want := []struct {
+		owner string
+		index int
+	}{{"particles", 1}, {"fluids", 1}, {"fluids", 0}, {"particles", 0}}
+	for i, w := range want {
+		got := batches[i]
+		if got.Owner.(*fakeTranslucent).name != w.owner || got.Index != w.index {
+			t.Fatalf("batch %d = %s #%d, want %s #%d", i, got.Owner.(*fakeTranslucent).name, got.Index, w.owner, w.index)
+		}
+	}
+}
@@ -57,11 +57,26 @@ func (s *Shader) SetFloat(name string, value float32) {
 	gl.Uniform1f(gl.GetUniformLocation(s.ID, gl.Str(name+"\x00")), value)
 }
 
+// SetVector2 sets a vector2 uniform
+func (s *Shader) SetVector2(name string, x, y float32) {
+	gl.Uniform2f(gl.GetUniformLocation(s.ID, gl.Str(name+"\x00")), x, y)
+}
+
 // SetVector3 sets a vector3 uniform
 func (s *Shader) SetVector3(name string, x, y, z float32) {
 	gl.Uniform3f(gl.GetUniformLocation(s.ID, gl.Str(name+"\x00")), x, y, z)
 }
 
+// SetVector4 sets a vector4 uniform
+func (s *Shader) SetVector4(name string, x, y, z, w float32) {
+	gl.Uniform4f(gl.GetUniformLocation(s.ID, gl.Str(name+"\x00")), x, y, z, w)
+}
+
+// SetMatrix3 sets a 3x3 matrix uniform
+func (s *Shader) SetMatrix3(name string, value *float32) {
+	gl.UniformMatrix3fv(gl.GetUniformLocation(s.ID, gl.Str(name+"\x00")), 1, false, value)
+}
+
 // SetMatrix4 sets a 4x4 matrix uniform
 func (s *Shader) SetMatrix4(name string, value *float32) {
 	gl.UniformMatrix4fv(gl.GetUniformLocation(s.ID, gl.Str(name+"\x00")), 1, false, value)
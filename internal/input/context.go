@@ -0,0 +1,66 @@
+package input
+
+// Context is what the player's input currently goes to. Contexts stack: a
+// screen opened over the game is pushed, and closing it pops back to what
+// was under it.
+type Context int
+
+const (
+	ContextMenu      Context = iota // a full-screen menu with no world behind it
+	ContextGameplay                 // moving around the world
+	ContextInventory                // the inventory or a container screen over the game
+	ContextPaused                   // the pause menu or one of its pages
+	ContextDead                     // the death screen
+	ContextChat                     // the chat prompt, taking the keyboard
+)
+
+// CapturesCursor reports whether the context hides the cursor and turns
+// mouse movement into camera movement.
+func (c Context) CapturesCursor() bool {
+	return c == ContextGameplay
+}
+
+// ContextStack is the stack of open input contexts. The zero value is empty,
+// which counts as ContextMenu.
+type ContextStack struct {
+	contexts []Context
+}
+
+// Top returns the context receiving input.
+func (s *ContextStack) Top() Context {
+	if len(s.contexts) == 0 {
+		return ContextMenu
+	}
+	return s.contexts[len(s.contexts)-1]
+}
+
+// Push opens c over the current context.
+func (s *ContextStack) Push(c Context) {
+	s.contexts = append(s.contexts, c)
+}
+
+// Pop closes the topmost c and everything opened over it. It does nothing
+// if c is not open.
+func (s *ContextStack) Pop(c Context) {
+	for i := len(s.contexts) - 1; i >= 0; i-- {
+		if s.contexts[i] == c {
+			s.contexts = s.contexts[:i]
+			return
+		}
+	}
+}
+
+// Contains reports whether c is open anywhere on the stack.
+func (s *ContextStack) Contains(c Context) bool {
+	for _, open := range s.contexts {
+		if open == c {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset closes every context.
+func (s *ContextStack) Reset() {
+	s.contexts = s.contexts[:0]
+}
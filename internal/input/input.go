@@ -32,6 +32,20 @@ const (
 	ActionHotbar9
 	ActionToggleWireframe
 	ActionToggleProfiling
+	ActionToggleColumnCulling
+	ActionToggleOcclusionCulling
+	ActionToggleLODDebugTint
+	ActionToggleLogViewer
+	ActionToggleNetGraph
+	ActionToggleDebugScreen
+	ActionPlayerList
+	ActionToggleBuilderMode
+	ActionCycleMirrorAxis
+	ActionPanoramaShot
+	ActionCyclePerspective
+	ActionChat
+	ActionCommand
+	ActionToggleFullscreen
 	ActionMouseLeft
 	ActionMouseRight
 	ActionMouseMiddle
@@ -92,6 +106,20 @@ func NewInputManager() *InputManager {
 	im.BindKey(glfw.Key9, ActionHotbar9)
 	im.BindKey(glfw.KeyF, ActionToggleWireframe)
 	im.BindKey(glfw.KeyV, ActionToggleProfiling)
+	im.BindKey(glfw.KeyC, ActionToggleColumnCulling)
+	im.BindKey(glfw.KeyO, ActionToggleOcclusionCulling)
+	im.BindKey(glfw.KeyL, ActionToggleLODDebugTint)
+	im.BindKey(glfw.KeyGraveAccent, ActionToggleLogViewer)
+	im.BindKey(glfw.KeyN, ActionToggleNetGraph)
+	im.BindKey(glfw.KeyTab, ActionPlayerList)
+	im.BindKey(glfw.KeyB, ActionToggleBuilderMode)
+	im.BindKey(glfw.KeyM, ActionCycleMirrorAxis)
+	im.BindKey(glfw.KeyF2, ActionPanoramaShot)
+	im.BindKey(glfw.KeyF5, ActionCyclePerspective)
+	im.BindKey(glfw.KeyF3, ActionToggleDebugScreen)
+	im.BindKey(glfw.KeyT, ActionChat)
+	im.BindKey(glfw.KeySlash, ActionCommand)
+	im.BindKey(glfw.KeyF11, ActionToggleFullscreen)
 
 	// Set default mouse button bindings
 	im.BindMouseButton(glfw.MouseButtonLeft, ActionMouseLeft)
@@ -232,6 +260,20 @@ func (im *InputManager) PostUpdate() {
 	}
 }
 
+// ReleaseAll lets go of every held action, as if all keys and buttons were
+// released, so nothing stays held while another screen takes the keyboard.
+func (im *InputManager) ReleaseAll() {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	for i := range ActionCount {
+		if im.currentState[i] {
+			im.justReleased[i] = true
+		}
+		im.currentState[i] = false
+	}
+}
+
 // IsActive returns true if the action is currently being held down
 func (im *InputManager) IsActive(action Action) bool {
 	if action < 0 || action >= ActionCount {
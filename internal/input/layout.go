@@ -0,0 +1,71 @@
+package input
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// movementActions are in the order of the labels BindMovementKeys takes
+var movementActions = [4]Action{ActionMoveForward, ActionMoveLeft, ActionMoveBackward, ActionMoveRight}
+
+// BindMovementKeys binds forward, left, back and right to the keys with the
+// given labels, replacing their previous keys. A label is looked up in the
+// system's layout first, so the key printed with it is the one that moves;
+// when no key has that name the key in its US position is used.
+func (im *InputManager) BindMovementKeys(labels [4]string) {
+	keys := [4]glfw.Key{glfw.KeyW, glfw.KeyA, glfw.KeyS, glfw.KeyD}
+	for i, label := range labels {
+		if k, ok := keyForLabel(label); ok {
+			keys[i] = k
+		}
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	for key, actions := range im.keyToActions {
+		actions = slices.DeleteFunc(actions, func(a Action) bool {
+			return slices.Contains(movementActions[:], a)
+		})
+		if len(actions) == 0 {
+			delete(im.keyToActions, key)
+		} else {
+			im.keyToActions[key] = actions
+		}
+	}
+	for i, key := range keys {
+		im.keyToActions[key] = append(im.keyToActions[key], movementActions[i])
+		im.currentState[movementActions[i]] = false
+	}
+}
+
+// labelledKeys are the keys searched for a movement label. Every keyboard
+// has them, which matters as GetKeyName fails on keys without a scancode.
+var labelledKeys = func() []glfw.Key {
+	keys := []glfw.Key{glfw.KeyComma, glfw.KeyPeriod, glfw.KeySemicolon}
+	for k := glfw.KeyA; k <= glfw.KeyZ; k++ {
+		keys = append(keys, k)
+	}
+	return keys
+}()
+
+// keyForLabel returns the key the system's layout names label, falling back
+// to the key of that name on a US layout.
+func keyForLabel(label string) (glfw.Key, bool) {
+	for _, k := range labelledKeys {
+		if strings.EqualFold(glfw.GetKeyName(k, 0), label) {
+			return k, true
+		}
+	}
+	if len(label) != 1 {
+		return glfw.KeyUnknown, false
+	}
+	switch c := strings.ToUpper(label)[0]; {
+	case c >= 'A' && c <= 'Z':
+		return glfw.KeyA + glfw.Key(c-'A'), true
+	case c == ',':
+		return glfw.KeyComma, true
+	}
+	return glfw.KeyUnknown, false
+}
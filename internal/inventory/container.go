@@ -39,6 +39,17 @@ func (c *Container) GetSlot(index int) *Slot {
 	return nil
 }
 
+// HotbarSlot returns the slot showing hotbar slot i of inv, or nil if the
+// container does not show it.
+func (c *Container) HotbarSlot(inv *Inventory, i int) *Slot {
+	for _, s := range c.Slots {
+		if s.inventory == ItemHolder(inv) && s.index == i {
+			return s
+		}
+	}
+	return nil
+}
+
 // SlotClick handles interactions with a slot
 // It returns true if something happened
 func (c *Container) SlotClick(slotIndex int, button MouseButton, isDoubleClick bool, playerInventory *Inventory) bool {
@@ -50,6 +61,23 @@ func (c *Container) SlotClick(slotIndex int, button MouseButton, isDoubleClick b
 	cursor := playerInventory.CursorStack
 	itemInSlot := slot.GetStack()
 
+	// Output slots can only be taken from
+	if slot.IsOutput() {
+		if itemInSlot == nil || isDoubleClick {
+			return false
+		}
+		if cursor == nil {
+			playerInventory.CursorStack = slot.TakeOutput()
+			return true
+		}
+		if cursor.CanStackWith(*itemInSlot) && cursor.Count+itemInSlot.Count <= cursor.GetMaxStackSize() {
+			taken := slot.TakeOutput()
+			cursor.Count += taken.Count
+			return true
+		}
+		return false
+	}
+
 	// Handle double-click: collect all items of same type
 	if isDoubleClick {
 		handleClickDoubleClick(c, slotIndex, playerInventory)
@@ -62,7 +90,7 @@ func (c *Container) SlotClick(slotIndex int, button MouseButton, isDoubleClick b
 			// Place one item from cursor stack into slot
 			if itemInSlot == nil {
 				// Empty slot: place one item
-				newStack := item.NewItemStack(cursor.Type, 1)
+				newStack := cursor.WithCount(1)
 				slot.PutStack(&newStack)
 				cursor.Count--
 				if cursor.Count <= 0 {
@@ -70,7 +98,7 @@ func (c *Container) SlotClick(slotIndex int, button MouseButton, isDoubleClick b
 				}
 			} else if itemInSlot.IsItemEqual(*cursor) {
 				// Same item: merge one item if there's space
-				if itemInSlot.Count < slot.GetMaxStackSize() {
+				if itemInSlot.Count < stackLimit(slot, itemInSlot) {
 					itemInSlot.Count++
 					cursor.Count--
 					if cursor.Count <= 0 {
@@ -84,7 +112,7 @@ func (c *Container) SlotClick(slotIndex int, button MouseButton, isDoubleClick b
 			// But our simple logic: pick up half?
 			// Let's implement standard pick up half
 			half := (itemInSlot.Count + 1) / 2
-			newCursor := item.NewItemStack(itemInSlot.Type, half)
+			newCursor := itemInSlot.WithCount(half)
 			playerInventory.CursorStack = &newCursor
 
 			itemInSlot.Count -= half
@@ -107,7 +135,7 @@ func (c *Container) SlotClick(slotIndex int, button MouseButton, isDoubleClick b
 				playerInventory.CursorStack = nil
 			} else if itemInSlot.IsItemEqual(*cursor) {
 				// Same item type: merge stacks
-				space := slot.GetMaxStackSize() - itemInSlot.Count
+				space := stackLimit(slot, itemInSlot) - itemInSlot.Count
 				if space > 0 {
 					toAdd := min(cursor.Count, space)
 					itemInSlot.Count += toAdd
@@ -128,6 +156,61 @@ func (c *Container) SlotClick(slotIndex int, button MouseButton, isDoubleClick b
 	return false
 }
 
+// CanDragInto reports whether a cursor stack dragged across the slot may
+// drop items into it: it is not an output and is empty or holds the same
+// item with room to spare.
+func (c *Container) CanDragInto(slotIndex int, cursor *item.ItemStack) bool {
+	slot := c.GetSlot(slotIndex)
+	if slot == nil || slot.IsOutput() || cursor == nil {
+		return false
+	}
+	inSlot := slot.GetStack()
+	return inSlot == nil || (inSlot.IsItemEqual(*cursor) && inSlot.Count < stackLimit(slot, inSlot))
+}
+
+// DragSplit spreads the cursor stack over the slots it was dragged across,
+// as MC does: the left button shares it out evenly, keeping what doesn't
+// divide on the cursor, and the right button drops one item into each.
+// Slots that can't take items are skipped, as are slots beyond the number
+// of items held.
+func (c *Container) DragSplit(slotIndices []int, button MouseButton, playerInventory *Inventory) bool {
+	cursor := playerInventory.CursorStack
+	var targets []*Slot
+	for _, i := range slotIndices {
+		if c.CanDragInto(i, cursor) && len(targets) < cursor.Count {
+			targets = append(targets, c.GetSlot(i))
+		}
+	}
+	if len(targets) == 0 {
+		return false
+	}
+
+	each := 1
+	if button == MouseButtonLeft {
+		each = cursor.Count / len(targets)
+	}
+	for _, slot := range targets {
+		inSlot := slot.GetStack()
+		if inSlot == nil {
+			stack := cursor.WithCount(0)
+			inSlot = &stack
+			slot.PutStack(inSlot)
+		}
+		n := min(each, stackLimit(slot, inSlot)-inSlot.Count)
+		inSlot.Count += n
+		cursor.Count -= n
+	}
+	if cursor.Count <= 0 {
+		playerInventory.CursorStack = nil
+	}
+	return true
+}
+
+// stackLimit returns how many items of stack fit in slot.
+func stackLimit(slot *Slot, stack *item.ItemStack) int {
+	return min(slot.GetMaxStackSize(), stack.GetMaxStackSize())
+}
+
 func handleClickDoubleClick(c *Container, clickedSlotIndex int, playerInventory *Inventory) {
 	cursor := playerInventory.CursorStack
 
@@ -137,8 +220,8 @@ func handleClickDoubleClick(c *Container, clickedSlotIndex int, playerInventory
 
 		// Collect matching items from other slots to cursor
 		for i, slot := range c.Slots {
-			if i == clickedSlotIndex {
-				continue // Skip the source slot (already handled by previous click or current cursor)
+			if i == clickedSlotIndex || slot.IsOutput() {
+				continue // Skip the source slot (already handled by previous click or current cursor) and outputs
 			}
 
 			itemInSlot := slot.GetStack()
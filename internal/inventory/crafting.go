@@ -0,0 +1,145 @@
+package inventory
+
+import (
+	"mini-mc/internal/item"
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+)
+
+// craftResult returns what the stacks of a crafting grid, width to a row,
+// make: a registered recipe's output, or the repair of two damaged items of
+// the same type into one.
+func craftResult(grid []*item.ItemStack, width int) *item.ItemStack {
+	types := make([]world.BlockType, len(grid))
+	var inputs []item.ItemStack
+	for i, s := range grid {
+		if s != nil {
+			types[i] = s.Type
+			inputs = append(inputs, *s)
+		}
+	}
+	if r, ok := registry.MatchCrafting(types, width); ok {
+		result := item.NewItemStack(r.Output, r.Count)
+		return &result
+	}
+	if len(inputs) != 2 {
+		return nil
+	}
+	result, ok := item.RepairCombine(inputs[0], inputs[1])
+	if !ok {
+		return nil
+	}
+	return &result
+}
+
+// takeCraftResult crafts the grid's current result, consuming one item from
+// every occupied slot. Returns nil if nothing can be crafted.
+func takeCraftResult(grid []*item.ItemStack, width int) *item.ItemStack {
+	result := craftResult(grid, width)
+	if result == nil {
+		return nil
+	}
+	for i, s := range grid {
+		if s == nil {
+			continue
+		}
+		s.Count--
+		if s.Count <= 0 {
+			grid[i] = nil
+		}
+	}
+	return result
+}
+
+// clearGrid empties a crafting grid and returns its contents.
+func clearGrid(grid []*item.ItemStack) []item.ItemStack {
+	var out []item.ItemStack
+	for i, s := range grid {
+		if s != nil {
+			out = append(out, *s)
+			grid[i] = nil
+		}
+	}
+	return out
+}
+
+// CraftingResult returns what the 2x2 crafting matrix currently produces, or
+// nil.
+func (inv *Inventory) CraftingResult() *item.ItemStack {
+	return craftResult(inv.CraftingMatrix[:], 2)
+}
+
+// TakeCraftingResult crafts the current result, consuming one item from every
+// occupied matrix slot. Returns nil if nothing can be crafted.
+func (inv *Inventory) TakeCraftingResult() *item.ItemStack {
+	return takeCraftResult(inv.CraftingMatrix[:], 2)
+}
+
+// TakeOutput implements output slots for the inventory: taking the crafting
+// result crafts it.
+func (inv *Inventory) TakeOutput(index int) *item.ItemStack {
+	if index == CraftingResultIndex {
+		return inv.TakeCraftingResult()
+	}
+	stack := inv.GetItem(index)
+	inv.SetItem(index, nil)
+	return stack
+}
+
+// ClearCraftingMatrix empties the crafting matrix and returns its contents so
+// the caller can put them back into the inventory (or drop them).
+func (inv *Inventory) ClearCraftingMatrix() []item.ItemStack {
+	return clearGrid(inv.CraftingMatrix[:])
+}
+
+// Crafting table grid slot indices, as used by a CraftingGrid's GetItem/SetItem
+const (
+	CraftingGridSize        = 9
+	CraftingGridResultIndex = CraftingGridSize
+)
+
+// CraftingGrid is the 3x3 grid of a crafting table. Like the inventory's 2x2
+// matrix it belongs to the player using it, not the table, and is emptied
+// back into their inventory when the screen closes.
+type CraftingGrid struct {
+	Matrix [CraftingGridSize]*item.ItemStack
+}
+
+// NewCraftingGrid creates an empty crafting table grid.
+func NewCraftingGrid() *CraftingGrid {
+	return &CraftingGrid{}
+}
+
+// GetItem returns the stack in a grid slot, or the current result at
+// CraftingGridResultIndex.
+func (g *CraftingGrid) GetItem(index int) *item.ItemStack {
+	if index >= 0 && index < CraftingGridSize {
+		return g.Matrix[index]
+	}
+	if index == CraftingGridResultIndex {
+		return craftResult(g.Matrix[:], 3)
+	}
+	return nil
+}
+
+// SetItem sets the stack in a grid slot. The result is read-only.
+func (g *CraftingGrid) SetItem(index int, stack *item.ItemStack) {
+	if index >= 0 && index < CraftingGridSize {
+		g.Matrix[index] = stack
+	}
+}
+
+// TakeOutput crafts the grid's result when it is taken.
+func (g *CraftingGrid) TakeOutput(index int) *item.ItemStack {
+	if index == CraftingGridResultIndex {
+		return takeCraftResult(g.Matrix[:], 3)
+	}
+	stack := g.GetItem(index)
+	g.SetItem(index, nil)
+	return stack
+}
+
+// Clear empties the grid and returns its contents.
+func (g *CraftingGrid) Clear() []item.ItemStack {
+	return clearGrid(g.Matrix[:])
+}
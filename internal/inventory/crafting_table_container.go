@@ -0,0 +1,30 @@
+package inventory
+
+// NewCraftingTableContainer creates a container for a crafting table screen:
+// the player's main inventory and hotbar (in the same order as the player
+// container, so hotbar number keys work) followed by the 3x3 grid and its
+// result.
+func NewCraftingTableContainer(inv *Inventory, grid *CraftingGrid) *Container {
+	c := NewContainer()
+
+	// Main Inventory Slots (Indices 9-35)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 9; j++ {
+			c.AddSlot(NewSlot(inv, j+(i+1)*9, 8+j*18, 84+i*18))
+		}
+	}
+
+	// Hotbar Slots (Indices 0-8)
+	for i := 0; i < 9; i++ {
+		c.AddSlot(NewSlot(inv, i, 8+i*18, 142))
+	}
+
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			c.AddSlot(NewSlot(grid, j+i*3, 30+j*18, 17+i*18))
+		}
+	}
+	c.AddSlot(NewOutputSlot(grid, CraftingGridResultIndex, 124, 35))
+
+	return c
+}
@@ -0,0 +1,74 @@
+package inventory
+
+import (
+	"testing"
+
+	"mini-mc/internal/item"
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+)
+
+func TestCraftingTableConsumesInputs(t *testing.T) {
+	saved := registry.CraftingRecipes
+	t.Cleanup(func() { registry.CraftingRecipes = saved })
+	registry.CraftingRecipes = nil
+	registry.RegisterShaped(world.BlockTypeStick, 4, []string{"#", "#"}, map[rune]registry.Ingredient{'#': registry.AnyPlanks})
+
+	inv := New()
+	grid := NewCraftingGrid()
+	c := NewCraftingTableContainer(inv, grid)
+	planks := item.NewItemStack(world.BlockTypePlanksOak, 3)
+	grid.Matrix[4] = &planks
+	if grid.GetItem(CraftingGridResultIndex) != nil {
+		t.Fatal("one planks makes something")
+	}
+	more := item.NewItemStack(world.BlockTypePlanksBirch, 1)
+	grid.Matrix[7] = &more
+
+	result := len(c.Slots) - 1
+	c.SlotClick(result, MouseButtonLeft, false, inv)
+	if cur := inv.CursorStack; cur == nil || cur.Type != world.BlockTypeStick || cur.Count != 4 {
+		t.Fatalf("cursor = %+v, want 4 sticks", cur)
+	}
+	if grid.Matrix[4].Count != 2 || grid.Matrix[7] != nil {
+		t.Fatalf("grid left %+v, %+v; want 2 planks and nothing", grid.Matrix[4], grid.Matrix[7])
+	}
+	if c.SlotClick(result, MouseButtonLeft, false, inv); inv.CursorStack.Count != 4 {
+		t.Errorf("crafted without the second planks: cursor = %+v", inv.CursorStack)
+	}
+
+	if left := grid.Clear(); len(left) != 1 || left[0].Count != 2 {
+		t.Errorf("Clear() = %+v, want the 2 planks", left)
+	}
+}
+
+func TestDragSplit(t *testing.T) {
+	inv := New()
+	c := NewPlayerContainer(inv)
+	stone := item.NewItemStack(world.BlockTypeStone, 62)
+	inv.MainInventory[10] = &stone
+
+	cursor := item.NewItemStack(world.BlockTypeStone, 11)
+	inv.CursorStack = &cursor
+	// Slots 0-2 show inventory slots 9-11; 10 holds 62 stone, room for 2
+	if !c.DragSplit([]int{0, 1, 2}, MouseButtonLeft, inv) {
+		t.Fatal("drag did nothing")
+	}
+	if inv.MainInventory[9].Count != 3 || inv.MainInventory[10].Count != 64 || inv.MainInventory[11].Count != 3 {
+		t.Fatalf("left drag gave %d, %d, %d; want 3, 64, 3",
+			inv.MainInventory[9].Count, inv.MainInventory[10].Count, inv.MainInventory[11].Count)
+	}
+	if inv.CursorStack == nil || inv.CursorStack.Count != 3 {
+		t.Fatalf("cursor = %+v, want 3 left over", inv.CursorStack)
+	}
+
+	// The full slot is skipped and the rest get one each
+	c.DragSplit([]int{1, 3, 4, 5}, MouseButtonRight, inv)
+	if inv.MainInventory[12].Count != 1 || inv.MainInventory[13].Count != 1 || inv.MainInventory[14].Count != 1 {
+		t.Fatalf("right drag gave %v, %v, %v; want one each",
+			inv.MainInventory[12], inv.MainInventory[13], inv.MainInventory[14])
+	}
+	if inv.CursorStack != nil {
+		t.Errorf("cursor = %+v, want empty", inv.CursorStack)
+	}
+}
@@ -0,0 +1,80 @@
+package inventory
+
+import (
+	"mini-mc/internal/item"
+	"mini-mc/internal/world"
+)
+
+// Creative palette layout: rows of 9 shown at a time, scrolled a row at a time
+const (
+	CreativeColumns = 9
+	CreativeRows    = 5
+)
+
+// CreativePalette is the endless supply of every block the creative picker
+// shows. Its slot indices are relative to the first row scrolled into view.
+type CreativePalette struct {
+	Items  []item.ItemStack
+	Offset int // index of the first item shown
+}
+
+// NewCreativePalette returns a palette holding one of each of types.
+func NewCreativePalette(types []world.BlockType) *CreativePalette {
+	p := &CreativePalette{Items: make([]item.ItemStack, len(types))}
+	for i, t := range types {
+		p.Items[i] = item.NewItemStack(t, 1)
+	}
+	return p
+}
+
+// GetItem returns the item shown in slot index, or nil past the end.
+func (p *CreativePalette) GetItem(index int) *item.ItemStack {
+	i := p.Offset + index
+	if index < 0 || i >= len(p.Items) {
+		return nil
+	}
+	return &p.Items[i]
+}
+
+// SetItem does nothing; the palette never changes.
+func (p *CreativePalette) SetItem(index int, stack *item.ItemStack) {}
+
+// TakeOutput returns a new single item like the one in slot index, leaving
+// the palette as it was.
+func (p *CreativePalette) TakeOutput(index int) *item.ItemStack {
+	shown := p.GetItem(index)
+	if shown == nil {
+		return nil
+	}
+	stack := shown.WithCount(1)
+	return &stack
+}
+
+// MaxOffset is the largest Offset that still fills the last row shown.
+func (p *CreativePalette) MaxOffset() int {
+	rows := (len(p.Items) + CreativeColumns - 1) / CreativeColumns
+	return max(rows-CreativeRows, 0) * CreativeColumns
+}
+
+// Scroll moves the palette by rows, clamped to its ends.
+func (p *CreativePalette) Scroll(rows int) {
+	p.Offset = min(max(p.Offset+rows*CreativeColumns, 0), p.MaxOffset())
+}
+
+// NewCreativeContainer creates a container for the creative picker: the
+// palette's rows followed by the player's hotbar.
+func NewCreativeContainer(inv *Inventory, palette *CreativePalette) *Container {
+	c := NewContainer()
+
+	for i := 0; i < CreativeRows; i++ {
+		for j := 0; j < CreativeColumns; j++ {
+			c.AddSlot(NewOutputSlot(palette, j+i*CreativeColumns, 8+j*18, 18+i*18))
+		}
+	}
+
+	for i := 0; i < HotbarSize; i++ {
+		c.AddSlot(NewSlot(inv, i, 8+i*18, 112))
+	}
+
+	return c
+}
@@ -0,0 +1,41 @@
+package inventory
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+)
+
+func TestCreativePickerNeverRunsOut(t *testing.T) {
+	inv := New()
+	palette := NewCreativePalette([]world.BlockType{world.BlockTypeStone, world.BlockTypeGlass})
+	c := NewCreativeContainer(inv, palette)
+
+	for range 2 {
+		c.SlotClick(1, MouseButtonLeft, false, inv)
+	}
+	if cur := inv.CursorStack; cur == nil || cur.Type != world.BlockTypeGlass || cur.Count != 2 {
+		t.Fatalf("cursor = %+v, want 2 glass", cur)
+	}
+	if got := palette.GetItem(1); got == nil || got.Type != world.BlockTypeGlass {
+		t.Errorf("palette slot after taking = %+v, want glass", got)
+	}
+
+	hotbar := c.HotbarSlot(inv, 4)
+	if hotbar == nil {
+		t.Fatal("hotbar slot 4 not in the container")
+	}
+	c.SlotClick(c.indexOf(hotbar), MouseButtonLeft, false, inv)
+	if got := inv.MainInventory[4]; got == nil || got.Type != world.BlockTypeGlass {
+		t.Errorf("hotbar slot 4 = %+v, want glass", got)
+	}
+}
+
+func (c *Container) indexOf(s *Slot) int {
+	for i, slot := range c.Slots {
+		if slot == s {
+			return i
+		}
+	}
+	return -1
+}
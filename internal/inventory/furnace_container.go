@@ -0,0 +1,34 @@
+package inventory
+
+// Furnace slot indices, as used by a furnace's GetItem/SetItem
+const (
+	FurnaceInputSlot = iota
+	FurnaceFuelSlot
+	FurnaceOutputSlot
+	FurnaceSlotCount
+)
+
+// NewFurnaceContainer creates a container for a furnace screen: the player's
+// main inventory and hotbar (in the same order as the player container, so
+// hotbar number keys work) followed by the furnace's input, fuel and output.
+func NewFurnaceContainer(inv *Inventory, furnace ItemHolder) *Container {
+	c := NewContainer()
+
+	// Main Inventory Slots (Indices 9-35)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 9; j++ {
+			c.AddSlot(NewSlot(inv, j+(i+1)*9, 8+j*18, 84+i*18))
+		}
+	}
+
+	// Hotbar Slots (Indices 0-8)
+	for i := 0; i < 9; i++ {
+		c.AddSlot(NewSlot(inv, i, 8+i*18, 142))
+	}
+
+	c.AddSlot(NewSlot(furnace, FurnaceInputSlot, 56, 17))
+	c.AddSlot(NewSlot(furnace, FurnaceFuelSlot, 56, 53))
+	c.AddSlot(NewOutputSlot(furnace, FurnaceOutputSlot, 116, 35))
+
+	return c
+}
@@ -7,6 +7,8 @@ import (
 // GetItem returns the item stack at the given global index
 // 0-35: Main Inventory (including hotbar)
 // 36-39: Armor Inventory
+// 40-43: Crafting Matrix (2x2)
+// 44: Crafting Result (computed from the matrix)
 func (inv *Inventory) GetItem(index int) *item.ItemStack {
 	if index >= 0 && index < MainInventorySize {
 		return inv.MainInventory[index]
@@ -14,15 +16,24 @@ func (inv *Inventory) GetItem(index int) *item.ItemStack {
 	if index >= MainInventorySize && index < MainInventorySize+ArmorInventorySize {
 		return inv.ArmorInventory[index-MainInventorySize]
 	}
+	if index >= CraftingMatrixStart && index < CraftingMatrixStart+CraftingMatrixSize {
+		return inv.CraftingMatrix[index-CraftingMatrixStart]
+	}
+	if index == CraftingResultIndex {
+		return inv.CraftingResult()
+	}
 	return nil
 }
 
-// SetItem sets the item stack at the given global index
+// SetItem sets the item stack at the given global index.
+// The crafting result is read-only; use TakeCraftingResult.
 func (inv *Inventory) SetItem(index int, stack *item.ItemStack) {
 	if index >= 0 && index < MainInventorySize {
 		inv.MainInventory[index] = stack
 	} else if index >= MainInventorySize && index < MainInventorySize+ArmorInventorySize {
 		inv.ArmorInventory[index-MainInventorySize] = stack
+	} else if index >= CraftingMatrixStart && index < CraftingMatrixStart+CraftingMatrixSize {
+		inv.CraftingMatrix[index-CraftingMatrixStart] = stack
 	}
 }
 
@@ -30,14 +41,19 @@ const (
 	MainInventorySize  = 36
 	ArmorInventorySize = 4
 	HotbarSize         = 9
+
+	CraftingMatrixSize  = 4
+	CraftingMatrixStart = MainInventorySize + ArmorInventorySize
+	CraftingResultIndex = CraftingMatrixStart + CraftingMatrixSize
 )
 
 type Inventory struct {
 	// Main inventory includes hotbar (indices 0-8) and main storage (9-35)
 	MainInventory  [MainInventorySize]*item.ItemStack
 	ArmorInventory [ArmorInventorySize]*item.ItemStack
-	CurrentItem    int             // Index 0-8
-	CursorStack    *item.ItemStack // Item held by mouse cursor
+	CraftingMatrix [CraftingMatrixSize]*item.ItemStack // 2x2 grid in the inventory screen
+	CurrentItem    int                                 // Index 0-8
+	CursorStack    *item.ItemStack                     // Item held by mouse cursor
 }
 
 func New() *Inventory {
@@ -100,7 +116,7 @@ func (inv *Inventory) AddItem(stack *item.ItemStack) bool {
 			toAdd := min(stack.Count, maxStack)
 
 			// Create new stack in slot
-			newItem := item.NewItemStack(stack.Type, toAdd)
+			newItem := stack.WithCount(toAdd)
 			inv.MainInventory[emptySlot] = &newItem
 
 			// Trigger pickup animation for the new slot
@@ -116,6 +132,22 @@ func (inv *Inventory) AddItem(stack *item.ItemStack) bool {
 	return true
 }
 
+// CanAdd reports whether at least one item of stack would fit.
+func (inv *Inventory) CanAdd(stack item.ItemStack) bool {
+	if stack.Count <= 0 {
+		return false
+	}
+	for _, existing := range inv.MainInventory {
+		if existing == nil {
+			return true
+		}
+		if stack.IsStackable() && existing.IsItemEqual(stack) && existing.Count < existing.GetMaxStackSize() {
+			return true
+		}
+	}
+	return false
+}
+
 // GetFirstEmptyStack returns the index of the first empty slot in main inventory
 func (inv *Inventory) GetFirstEmptyStack() int {
 	for i := range len(inv.MainInventory) {
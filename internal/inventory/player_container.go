@@ -32,5 +32,17 @@ func NewPlayerContainer(inv *Inventory) *Container {
 		c.AddSlot(NewSlot(inv, 36+i, x, y))
 	}
 
+	// Add Crafting Matrix Slots (Indices 40-43), 2x2
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			x := 88 + j*18
+			y := 26 + i*18
+			c.AddSlot(NewSlot(inv, CraftingMatrixStart+j+i*2, x, y))
+		}
+	}
+
+	// Add Crafting Result Slot (Index 44)
+	c.AddSlot(NewOutputSlot(inv, CraftingResultIndex, 144, 36))
+
 	return c
 }
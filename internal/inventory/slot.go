@@ -4,15 +4,29 @@ import (
 	"mini-mc/internal/item"
 )
 
+// ItemHolder is anything whose indexed stacks can be shown in slots, like
+// the player inventory or a furnace.
+type ItemHolder interface {
+	GetItem(index int) *item.ItemStack
+	SetItem(index int, stack *item.ItemStack)
+}
+
+// outputTaker is implemented by holders whose output slots do more than hand
+// over the stack when taken from (crafting consumes its inputs).
+type outputTaker interface {
+	TakeOutput(index int) *item.ItemStack
+}
+
 // Slot represents a single slot in a container
 type Slot struct {
-	inventory *Inventory
+	inventory ItemHolder
 	index     int
+	output    bool
 	X, Y      int
 }
 
 // NewSlot creates a new slot
-func NewSlot(inv *Inventory, index, x, y int) *Slot {
+func NewSlot(inv ItemHolder, index, x, y int) *Slot {
 	return &Slot{
 		inventory: inv,
 		index:     index,
@@ -21,6 +35,13 @@ func NewSlot(inv *Inventory, index, x, y int) *Slot {
 	}
 }
 
+// NewOutputSlot creates a slot that items can be taken from but not put into
+func NewOutputSlot(inv ItemHolder, index, x, y int) *Slot {
+	s := NewSlot(inv, index, x, y)
+	s.output = true
+	return s
+}
+
 // GetStack returns the item stack in this slot.
 // It delegates to the inventory's GetItem method which handles
 // mapping global indices to specific internal arrays (Main/Armor).
@@ -44,6 +65,24 @@ func (s *Slot) OnSlotChanged() {
 	// Trigger updates if needed
 }
 
+// IsOutput reports whether the slot is an output that can only be taken from
+func (s *Slot) IsOutput() bool {
+	return s.output
+}
+
+// TakeOutput removes and returns the stack in an output slot
+func (s *Slot) TakeOutput() *item.ItemStack {
+	if s.inventory == nil {
+		return nil
+	}
+	if t, ok := s.inventory.(outputTaker); ok {
+		return t.TakeOutput(s.index)
+	}
+	stack := s.GetStack()
+	s.PutStack(nil)
+	return stack
+}
+
 // GetMaxStackSize returns max stack size for this slot
 func (s *Slot) GetMaxStackSize() int {
 	return 64 // Standard max stack size
@@ -0,0 +1,68 @@
+package item
+
+import "mini-mc/internal/world"
+
+// maxUses is the durability of each damageable item (MC 1.8 values).
+var maxUses = map[world.BlockType]int{
+	world.BlockTypeWoodenPickaxe: 59,
+	world.BlockTypeStonePickaxe:  131,
+	world.BlockTypeIronPickaxe:   250,
+}
+
+// repairBonusPercent is the extra durability granted when two items are
+// combined, as a percentage of the maximum.
+const repairBonusPercent = 5
+
+// GetMaxDamage returns how many uses the item has before breaking, or 0 if it
+// is not damageable.
+func (s ItemStack) GetMaxDamage() int {
+	return maxUses[s.Type]
+}
+
+// IsDamageable returns whether the item wears down with use
+func (s ItemStack) IsDamageable() bool {
+	return s.GetMaxDamage() > 0
+}
+
+// IsDamaged returns whether a damageable item has lost any durability
+func (s ItemStack) IsDamaged() bool {
+	return s.IsDamageable() && s.Damage > 0
+}
+
+// DurabilityFraction returns the remaining durability in [0,1]; 1 for items
+// that are not damageable.
+func (s ItemStack) DurabilityFraction() float32 {
+	maxDamage := s.GetMaxDamage()
+	if maxDamage <= 0 {
+		return 1
+	}
+	return float32(maxDamage-s.Damage) / float32(maxDamage)
+}
+
+// DamageItem wears the item down by amount uses. It returns true when the item
+// broke; the stack's Count is then 0 and the caller should remove it.
+func (s *ItemStack) DamageItem(amount int) bool {
+	if !s.IsDamageable() || amount <= 0 {
+		return false
+	}
+	s.Damage += amount
+	if s.Damage > s.GetMaxDamage() {
+		s.Count--
+		s.Damage = 0
+		return s.Count <= 0
+	}
+	return false
+}
+
+// RepairCombine merges two damaged items of the same type into one, as the
+// crafting grid does: remaining uses are added plus a small bonus.
+func RepairCombine(a, b ItemStack) (ItemStack, bool) {
+	if a.Type != b.Type || !a.IsDamageable() || a.Count != 1 || b.Count != 1 {
+		return ItemStack{}, false
+	}
+	maxDamage := a.GetMaxDamage()
+	remaining := (maxDamage - a.Damage) + (maxDamage - b.Damage) + maxDamage*repairBonusPercent/100
+	result := NewItemStack(a.Type, 1)
+	result.Damage = max(maxDamage-remaining, 0)
+	return result, true
+}
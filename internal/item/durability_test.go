@@ -0,0 +1,40 @@
+package item
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+)
+
+func TestDamageItemBreaksAtZero(t *testing.T) {
+	s := NewItemStack(world.BlockTypeWoodenPickaxe, 1)
+	maxDamage := s.GetMaxDamage()
+	if s.DamageItem(maxDamage) {
+		t.Fatal("tool broke with one use left")
+	}
+	if !s.IsDamaged() || s.DurabilityFraction() != 0 {
+		t.Fatalf("damage = %d, fraction = %v; want fully worn", s.Damage, s.DurabilityFraction())
+	}
+	if !s.DamageItem(1) || s.Count != 0 {
+		t.Fatalf("tool did not break on its last use (count %d)", s.Count)
+	}
+}
+
+func TestRepairCombine(t *testing.T) {
+	a := NewItemStack(world.BlockTypeStonePickaxe, 1)
+	b := NewItemStack(world.BlockTypeStonePickaxe, 1)
+	a.Damage, b.Damage = 100, 100
+
+	got, ok := RepairCombine(a, b)
+	if !ok {
+		t.Fatal("RepairCombine rejected two matching tools")
+	}
+	// 31 + 31 uses left, plus 5% of 131 (6)
+	if want := 131 - 68; got.Damage != want {
+		t.Fatalf("repaired damage = %d, want %d", got.Damage, want)
+	}
+
+	if _, ok := RepairCombine(a, NewItemStack(world.BlockTypeIronPickaxe, 1)); ok {
+		t.Fatal("RepairCombine accepted mismatched tools")
+	}
+}
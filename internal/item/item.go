@@ -7,6 +7,10 @@ type ItemStack struct {
 	Type  world.BlockType
 	Count int
 
+	// Damage is the number of uses consumed from a damageable item (tools).
+	// It is always 0 for other items.
+	Damage int
+
 	// AnimationsToGo is the number of animation frames remaining.
 	// Set to 5 when item is picked up, decremented each tick.
 	AnimationsToGo int
@@ -20,30 +24,41 @@ func NewItemStack(t world.BlockType, count int) ItemStack {
 	}
 }
 
+// WithCount returns a copy of the stack holding count items. Metadata such as
+// Damage is kept; the pickup animation is not.
+func (s ItemStack) WithCount(count int) ItemStack {
+	s.Count = count
+	s.AnimationsToGo = 0
+	return s
+}
+
 // GetMaxStackSize returns the maximum stack size for this item
 func (s ItemStack) GetMaxStackSize() int {
+	if s.IsDamageable() {
+		return 1
+	}
 	return 64
 }
 
 // IsStackable returns if the item can be stacked
 func (s ItemStack) IsStackable() bool {
-	return true
+	return s.GetMaxStackSize() > 1
 }
 
-// IsItemEqual checks if two stacks contain the same item type
+// IsItemEqual checks if two stacks contain the same item type (and, for
+// damageable items, the same damage)
 func (s ItemStack) IsItemEqual(other ItemStack) bool {
-	return s.Type == other.Type
+	return s.Type == other.Type && s.Damage == other.Damage
 }
 
 // CanStackWith returns true if this stack can be merged with another.
 // Checks same item type. In the future, could also check NBT data.
 func (s ItemStack) CanStackWith(other ItemStack) bool {
-	// Must be same item type
-	if s.Type != other.Type {
+	// Must be same item type and a stackable one
+	if s.Type != other.Type || !s.IsStackable() {
 		return false
 	}
-	// Future: Add NBT/metadata comparison here if needed
-	return true
+	return s.Damage == other.Damage
 }
 
 // Animation constants
@@ -0,0 +1,81 @@
+// Package logging sets up the game's leveled logger. Records go to the
+// console, to a size-rotated file under the log directory, and to an
+// in-memory ring that the in-game log viewer reads. Code logs through
+// log/slog; the standard log package is routed through it as well.
+package logging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Setup installs the logger as slog's default, writing records at level and
+// above. It returns a closer for the log file.
+func Setup(dir string, level slog.Level) (io.Closer, error) {
+	file, err := openRotatingFile(dir, "latest.log", maxLogBytes, keepLogFiles)
+	if err != nil {
+		return nil, err
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	slog.SetDefault(slog.New(fanout{
+		slog.NewTextHandler(os.Stderr, opts),
+		slog.NewTextHandler(file, opts),
+		newRingHandler(recent, level),
+	}))
+	return file, nil
+}
+
+// ParseLevel parses debug, info, warn or error (case-insensitive); an empty
+// string means info.
+func ParseLevel(s string) (slog.Level, error) {
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.ToUpper(s))); err != nil {
+		return 0, errors.New("log level must be debug, info, warn or error")
+	}
+	return l, nil
+}
+
+// fanout passes each record to every handler that accepts its level.
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, l slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, l) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFileKeepsNewestBackups(t *testing.T) {
+	dir := t.TempDir()
+	r, err := openRotatingFile(dir, "latest.log", 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"latest.log":   "fourth\n",
+		"latest.log.1": "third\n",
+		"latest.log.2": "second\n",
+	} {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "latest.log.3")); !os.IsNotExist(err) {
+		t.Error("kept more backups than asked")
+	}
+}
+
+func TestRingKeepsRecentRecordsAboveLevel(t *testing.T) {
+	r := &ring{entries: make([]Entry, 3)}
+	logger := slog.New(newRingHandler(r, slog.LevelInfo)).With("chunk", 7).WithGroup("atlas")
+	logger.Debug("dropped")
+	logger.Info("one")
+	logger.Warn("two", "bytes", 42)
+	logger.Info("three")
+	logger.Error("four")
+
+	got := r.appendSince(nil, slog.LevelInfo)
+	var msgs []string
+	for _, e := range got {
+		msgs = append(msgs, e.Message)
+	}
+	want := []string{"two chunk=7 atlas.bytes=42", "three chunk=7", "four chunk=7"}
+	if strings.Join(msgs, "|") != strings.Join(want, "|") {
+		t.Errorf("ring = %q, want %q", msgs, want)
+	}
+	if warn := r.appendSince(nil, slog.LevelWarn); len(warn) != 2 {
+		t.Errorf("%d entries at warn and above, want 2", len(warn))
+	}
+	if !logger.Handler().Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("handler rejects its own level")
+	}
+}
@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// recentCapacity is how many records the in-game viewer can scroll back.
+const recentCapacity = 256
+
+// Entry is one formatted log record kept for the in-game viewer.
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string // message followed by its attributes as key=value
+}
+
+// ring keeps the most recent entries.
+type ring struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+var recent = &ring{entries: make([]Entry, recentCapacity)}
+
+func (r *ring) add(e Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// appendSince appends entries at minLevel or above, oldest first.
+func (r *ring) appendSince(dst []Entry, minLevel slog.Level) []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	start, n := 0, r.next
+	if r.full {
+		start, n = r.next, len(r.entries)
+	}
+	for i := range n {
+		e := r.entries[(start+i)%len(r.entries)]
+		if e.Level >= minLevel {
+			dst = append(dst, e)
+		}
+	}
+	return dst
+}
+
+// Recent appends the kept records at minLevel or above to dst, oldest first.
+func Recent(dst []Entry, minLevel slog.Level) []Entry {
+	return recent.appendSince(dst, minLevel)
+}
+
+// ringHandler formats records into a ring.
+type ringHandler struct {
+	r      *ring
+	level  slog.Level
+	prefix string // group path for attribute keys
+	attrs  string // preformatted attributes from WithAttrs
+}
+
+func newRingHandler(r *ring, level slog.Level) *ringHandler {
+	return &ringHandler{r: r, level: level}
+}
+
+func (h *ringHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= h.level
+}
+
+func (h *ringHandler) Handle(_ context.Context, rec slog.Record) error {
+	var b strings.Builder
+	b.WriteString(rec.Message)
+	b.WriteString(h.attrs)
+	rec.Attrs(func(a slog.Attr) bool {
+		writeAttr(&b, h.prefix, a)
+		return true
+	})
+	h.r.add(Entry{Time: rec.Time, Level: rec.Level, Message: b.String()})
+	return nil
+}
+
+func (h *ringHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	b.WriteString(h.attrs)
+	for _, a := range attrs {
+		writeAttr(&b, h.prefix, a)
+	}
+	h2 := *h
+	h2.attrs = b.String()
+	return &h2
+}
+
+func (h *ringHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+func writeAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			writeAttr(b, prefix, ga)
+		}
+		return
+	}
+	fmt.Fprintf(b, " %s%s=%v", prefix, a.Key, a.Value.Any())
+}
@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	maxLogBytes  = 4 << 20 // rotate once the current file passes this size
+	keepLogFiles = 3       // rotated files kept besides the current one
+)
+
+// rotatingFile is a log file that is renamed aside (name.1, name.2, ...)
+// when it grows past maxBytes, and on open so each run starts a fresh file.
+type rotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	keep     int
+	f        *os.File
+	size     int64
+}
+
+func openRotatingFile(dir, name string, maxBytes int64, keep int) (*rotatingFile, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	r := &rotatingFile{path: filepath.Join(dir, name), maxBytes: maxBytes, keep: keep}
+	if err := r.rotate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return 0, os.ErrClosed
+	}
+	if r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts name -> name.1 -> ... -> name.keep, dropping the oldest, and
+// opens an empty current file. Caller holds mu (or owns r exclusively).
+func (r *rotatingFile) rotate() error {
+	if r.f != nil {
+		r.f.Close()
+		r.f = nil
+	}
+	os.Remove(r.backup(r.keep))
+	for i := r.keep - 1; i >= 1; i-- {
+		os.Rename(r.backup(i), r.backup(i+1))
+	}
+	if err := os.Rename(r.path, r.backup(1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	r.f, r.size = f, 0
+	return nil
+}
+
+func (r *rotatingFile) backup(i int) string {
+	return fmt.Sprintf("%s.%d", r.path, i)
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
@@ -0,0 +1,11 @@
+package mathutil
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// AABBsOverlap reports whether two boxes overlap. Boxes that only touch do
+// not.
+func AABBsOverlap(aMin, aMax, bMin, bMax mgl32.Vec3) bool {
+	return aMin[0] < bMax[0] && aMax[0] > bMin[0] &&
+		aMin[1] < bMax[1] && aMax[1] > bMin[1] &&
+		aMin[2] < bMax[2] && aMax[2] > bMin[2]
+}
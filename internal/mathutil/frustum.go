@@ -0,0 +1,112 @@
+package mathutil
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Plane is the plane A*x + B*y + C*z + D = 0, with the normal pointing to
+// the inside of the frustum.
+type Plane struct {
+	A, B, C, D float32
+}
+
+// Frustum is the six planes of a view frustum: left, right, bottom, top,
+// near, far.
+type Frustum [6]Plane
+
+// FrustumFromMatrix extracts the normalised planes of the combined
+// projection*view matrix clip.
+func FrustumFromMatrix(clip mgl32.Mat4) Frustum {
+	// Matrix is in column-major order in mgl32
+	m00, m01, m02, m03 := clip[0], clip[4], clip[8], clip[12]
+	m10, m11, m12, m13 := clip[1], clip[5], clip[9], clip[13]
+	m20, m21, m22, m23 := clip[2], clip[6], clip[10], clip[14]
+	m30, m31, m32, m33 := clip[3], clip[7], clip[11], clip[15]
+
+	return Frustum{
+		normalizePlane(Plane{m30 + m00, m31 + m01, m32 + m02, m33 + m03}), // left = m3 + m0
+		normalizePlane(Plane{m30 - m00, m31 - m01, m32 - m02, m33 - m03}), // right = m3 - m0
+		normalizePlane(Plane{m30 + m10, m31 + m11, m32 + m12, m33 + m13}), // bottom = m3 + m1
+		normalizePlane(Plane{m30 - m10, m31 - m11, m32 - m12, m33 - m13}), // top = m3 - m1
+		normalizePlane(Plane{m30 + m20, m31 + m21, m32 + m22, m33 + m23}), // near = m3 + m2
+		normalizePlane(Plane{m30 - m20, m31 - m21, m32 - m22, m33 - m23}), // far = m3 - m2
+	}
+}
+
+func normalizePlane(p Plane) Plane {
+	l := float32(math.Sqrt(float64(p.A*p.A + p.B*p.B + p.C*p.C)))
+	if l == 0 {
+		return p
+	}
+	return Plane{p.A / l, p.B / l, p.C / l, p.D / l}
+}
+
+// IntersectsAABB reports whether the box may be inside the frustum: it is
+// only rejected when its corner furthest along some plane's normal is
+// outside that plane.
+func (f *Frustum) IntersectsAABB(minx, miny, minz, maxx, maxy, maxz float32) bool {
+	for i := range f {
+		p := &f[i]
+		px, py, pz := maxx, maxy, maxz
+		if p.A < 0 {
+			px = minx
+		}
+		if p.B < 0 {
+			py = miny
+		}
+		if p.C < 0 {
+			pz = minz
+		}
+		if p.A*px+p.B*py+p.C*pz+p.D < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// CullAABBs tests many boxes against the frustum in one call. bounds holds
+// minX, minY, minZ, maxX, maxY, maxZ per box, and visible[i] is set to
+// whether box i, grown by margin on every side, passes IntersectsAABB.
+//
+// The corner to test against each plane depends only on the signs of the
+// plane's normal, and growing a box moves that corner out along all three
+// axes, so both are worked out once per call rather than per box. What is
+// left per box is a multiply-add per plane over the packed bounds, without
+// the per-axis selects, until a plane rejects it.
+func (f *Frustum) CullAABBs(bounds []float32, margin float32, visible []bool) {
+	type corner struct {
+		a, b, c, d float32
+		x, y, z    int // offsets of the corner's coordinates in a box
+	}
+	var corners [6]corner
+	for i, p := range f {
+		c := corner{a: p.A, b: p.B, c: p.C, x: 3, y: 4, z: 5}
+		if p.A < 0 {
+			c.x = 0
+		}
+		if p.B < 0 {
+			c.y = 1
+		}
+		if p.C < 0 {
+			c.z = 2
+		}
+		c.d = p.D + margin*(Abs(p.A)+Abs(p.B)+Abs(p.C))
+		corners[i] = c
+	}
+
+	n := min(len(bounds)/6, len(visible))
+	for i := range n {
+		b := bounds[i*6 : i*6+6 : i*6+6]
+		in := true
+		for j := range corners {
+			c := &corners[j]
+			if c.a*b[c.x]+c.b*b[c.y]+c.c*b[c.z]+c.d < 0 {
+				in = false
+				break
+			}
+		}
+		visible[i] = in
+	}
+}
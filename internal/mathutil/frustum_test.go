@@ -0,0 +1,91 @@
+package mathutil
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func testFrustum() Frustum {
+	proj := mgl32.Perspective(mgl32.DegToRad(70), 16.0/9.0, 0.1, 1000)
+	view := mgl32.LookAtV(mgl32.Vec3{0, 80, 0}, mgl32.Vec3{1, 70, 0.3}, mgl32.Vec3{0, 1, 0})
+	return FrustumFromMatrix(proj.Mul4(view))
+}
+
+// chunkBoxes returns the bounds of a disc of radius chunk columns, 16x256x16
+// each, around the origin.
+func chunkBoxes(radius int) []float32 {
+	var bounds []float32
+	for x := -radius; x <= radius; x++ {
+		for z := -radius; z <= radius; z++ {
+			if x*x+z*z > radius*radius {
+				continue
+			}
+			minX, minZ := float32(x*16), float32(z*16)
+			bounds = append(bounds, minX, 0, minZ, minX+16, 256, minZ+16)
+		}
+	}
+	return bounds
+}
+
+func TestCullAABBsMatchesIntersectsAABB(t *testing.T) {
+	f := testFrustum()
+	rng := rand.New(rand.NewPCG(1, 2))
+	bounds := chunkBoxes(20)
+	for range 2000 {
+		x, y, z := rng.Float32()*600-300, rng.Float32()*200-20, rng.Float32()*600-300
+		bounds = append(bounds, x, y, z, x+rng.Float32()*20, y+rng.Float32()*20, z+rng.Float32()*20)
+	}
+	const margin = 1
+	visible := make([]bool, len(bounds)/6)
+	f.CullAABBs(bounds, margin, visible)
+
+	seen := 0
+	for i, got := range visible {
+		b := bounds[i*6 : i*6+6]
+		want := f.IntersectsAABB(b[0]-margin, b[1]-margin, b[2]-margin, b[3]+margin, b[4]+margin, b[5]+margin)
+		if got != want {
+			t.Fatalf("box %d %v: batch %v, single %v", i, b, got, want)
+		}
+		if got {
+			seen++
+		}
+	}
+	if seen == 0 || seen == len(visible) {
+		t.Fatalf("%d of %d boxes visible; the test does not tell anything", seen, len(visible))
+	}
+}
+
+func TestAABBsOverlap(t *testing.T) {
+	a0, a1 := mgl32.Vec3{0, 0, 0}, mgl32.Vec3{1, 1, 1}
+	if !AABBsOverlap(a0, a1, mgl32.Vec3{0.5, 0.5, 0.5}, mgl32.Vec3{2, 2, 2}) {
+		t.Error("overlapping boxes reported apart")
+	}
+	if AABBsOverlap(a0, a1, mgl32.Vec3{1, 0, 0}, mgl32.Vec3{2, 1, 1}) {
+		t.Error("touching boxes reported overlapping")
+	}
+}
+
+// BenchmarkFrustumCulling compares testing the boxes of a 50-chunk disc one
+// call each with culling them all in one CullAABBs call.
+func BenchmarkFrustumCulling(b *testing.B) {
+	f := testFrustum()
+	bounds := chunkBoxes(50)
+	visible := make([]bool, len(bounds)/6)
+	const margin = 1
+
+	b.Run("per box", func(b *testing.B) {
+		for b.Loop() {
+			for i := range visible {
+				bb := bounds[i*6 : i*6+6]
+				visible[i] = f.IntersectsAABB(bb[0]-margin, bb[1]-margin, bb[2]-margin, bb[3]+margin, bb[4]+margin, bb[5]+margin)
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		for b.Loop() {
+			f.CullAABBs(bounds, margin, visible)
+		}
+	})
+}
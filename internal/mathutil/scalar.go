@@ -0,0 +1,47 @@
+// Package mathutil holds the small math helpers shared across the game:
+// integer division that rounds down, interpolation, and frustum and AABB
+// tests, including a batch cull over many boxes per call.
+package mathutil
+
+// Number is any type the scalar helpers work on.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+// FloorDiv divides a by b rounding towards negative infinity, so block -1
+// lands in chunk -1 rather than 0.
+func FloorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// Mod returns a modulo b with the sign of b: the position inside a chunk of
+// any block coordinate, negative ones included.
+func Mod(a, b int) int {
+	r := a % b
+	if r != 0 && (r < 0) != (b < 0) {
+		r += b
+	}
+	return r
+}
+
+// Abs returns the absolute value of v.
+func Abs[T Number](v T) T {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// Clamp limits v to [lo, hi].
+func Clamp[T Number](v, lo, hi T) T {
+	return min(max(v, lo), hi)
+}
+
+// Lerp interpolates linearly from a to b by t.
+func Lerp[T ~float32 | ~float64](a, b, t T) T {
+	return a + t*(b-a)
+}
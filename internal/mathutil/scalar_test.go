@@ -0,0 +1,24 @@
+package mathutil
+
+import "testing"
+
+func TestFloorDivAndMod(t *testing.T) {
+	for _, tc := range []struct{ a, b, div, mod int }{
+		{17, 16, 1, 1},
+		{16, 16, 1, 0},
+		{0, 16, 0, 0},
+		{-1, 16, -1, 15},
+		{-16, 16, -1, 0},
+		{-17, 16, -2, 15},
+	} {
+		if got := FloorDiv(tc.a, tc.b); got != tc.div {
+			t.Errorf("FloorDiv(%d, %d) = %d, want %d", tc.a, tc.b, got, tc.div)
+		}
+		if got := Mod(tc.a, tc.b); got != tc.mod {
+			t.Errorf("Mod(%d, %d) = %d, want %d", tc.a, tc.b, got, tc.mod)
+		}
+		if FloorDiv(tc.a, tc.b)*tc.b+Mod(tc.a, tc.b) != tc.a {
+			t.Errorf("FloorDiv and Mod of %d, %d do not add back up", tc.a, tc.b)
+		}
+	}
+}
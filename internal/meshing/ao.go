@@ -0,0 +1,106 @@
+package meshing
+
+import (
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+)
+
+// Ambient occlusion darkens the corners of greedy-meshed faces next to other
+// blocks, the classic three-neighbour way: a corner is darker for each of
+// the two blocks beside it and the one diagonal to it in front of the face,
+// and fully dark when both side blocks are there. Each vertex carries its
+// occlusion level, 0 (open) to 3, and faces only merge when all four of
+// their corners match, so a merged quad's corners stand for every face in
+// it.
+
+// aoSampler looks up occluding blocks in a chunk and the eight around it
+// without going through the chunk store for each block.
+type aoSampler struct {
+	chunks   [3][3]*world.Chunk // [x][z], the chunk being meshed in the middle
+	occluder *[256]bool
+}
+
+func newAOSampler(w *world.World, c *world.Chunk) *aoSampler {
+	s := &aoSampler{occluder: registry.OccluderTable()}
+	for dx := range 3 {
+		for dz := range 3 {
+			if dx == 1 && dz == 1 {
+				s.chunks[dx][dz] = c
+				continue
+			}
+			s.chunks[dx][dz] = w.GetChunk(c.X+dx-1, c.Y, c.Z+dz-1, false)
+		}
+	}
+	return s
+}
+
+// opaque reports whether the block at local (x, y, z), at most one block
+// outside the chunk sideways, occludes.
+func (s *aoSampler) opaque(x, y, z int) bool {
+	if y < 0 || y >= world.ChunkSizeY {
+		return false
+	}
+	cx, cz := 1, 1
+	switch {
+	case x < 0:
+		cx = 0
+	case x >= world.ChunkSizeX:
+		cx = 2
+	}
+	switch {
+	case z < 0:
+		cz = 0
+	case z >= world.ChunkSizeZ:
+		cz = 2
+	}
+	c := s.chunks[cx][cz]
+	if c == nil {
+		return false
+	}
+	return s.occluder[c.GetBlock(x&(world.ChunkSizeX-1), y, z&(world.ChunkSizeZ-1))]
+}
+
+// faceOcclusion returns the occlusion of the four corners of the face of
+// block (x, y, z) along normal n, whose plane is spanned by axes u and v,
+// packed 2 bits each. Corner i lies on the + side of u if i&1 is set and on
+// the + side of v if i&2 is.
+func (s *aoSampler) faceOcclusion(x, y, z int, n, u, v [3]int) uint8 {
+	ax, ay, az := x+n[0], y+n[1], z+n[2] // the block in front of the face
+	var packed uint8
+	for i := range 4 {
+		du, dv := -1, -1
+		if i&1 != 0 {
+			du = 1
+		}
+		if i&2 != 0 {
+			dv = 1
+		}
+		side1 := s.opaque(ax+du*u[0], ay+du*u[1], az+du*u[2])
+		side2 := s.opaque(ax+dv*v[0], ay+dv*v[1], az+dv*v[2])
+		corner := s.opaque(ax+du*u[0]+dv*v[0], ay+du*u[1]+dv*v[1], az+du*u[2]+dv*v[2])
+		level := uint8(3)
+		if !side1 || !side2 {
+			level = b2u(side1) + b2u(side2) + b2u(corner)
+		}
+		packed |= level << (2 * i)
+	}
+	return packed
+}
+
+// quadOcclusion picks the occlusion of the corners a quad's vertices lie
+// at, in vertex order, out of faceOcclusion's result.
+func quadOcclusion(packed uint8, c0, c1, c2, c3 int) [4]uint8 {
+	return [4]uint8{
+		packed >> (2 * c0) & 3,
+		packed >> (2 * c1) & 3,
+		packed >> (2 * c2) & 3,
+		packed >> (2 * c3) & 3,
+	}
+}
+
+func b2u(b bool) uint8 {
+	if b {
+		return 1
+	}
+	return 0
+}
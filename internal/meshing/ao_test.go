@@ -0,0 +1,39 @@
+package meshing
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+)
+
+func TestFaceOcclusion(t *testing.T) {
+	w := world.New()
+	defer w.Close()
+	c := w.GetChunk(0, 0, 0, true)
+	c.SetBlock(8, 10, 8, world.BlockTypeStone)
+	// Two blocks standing on it, beside the +x and +z corners of its top
+	c.SetBlock(9, 11, 8, world.BlockTypeStone)
+	c.SetBlock(8, 11, 9, world.BlockTypeStone)
+
+	occl := newAOSampler(w, c).faceOcclusion(8, 10, 8, [3]int{0, 1, 0}, [3]int{1, 0, 0}, [3]int{0, 0, 1})
+	got := quadOcclusion(occl, 0, 1, 2, 3)
+	// -x-z is open, +x-z and -x+z have one side block, +x+z has both
+	if want := [4]uint8{0, 1, 1, 3}; got != want {
+		t.Errorf("corner occlusion = %v, want %v", got, want)
+	}
+}
+
+func TestEmitQuadSplitsAwayFromDarkCorner(t *testing.T) {
+	var verts []uint32
+	emitQuad(&verts, 0, 0, 0, 1, 0, 0, 1, 0, 1, 0, 0, 1, 4, 1, 0xFFFF, false, fullLight, [4]uint8{3, 0, 0, 0})
+	if len(verts) != QuadVertices*VertexStride {
+		t.Fatalf("got %d uint32s, want one quad", len(verts))
+	}
+	// Split along v1-v3: the quad starts at v1, so the dark v0 is its last
+	// vertex and only appears in the second triangle
+	for i, want := range []uint32{0, 0, 0, 3} {
+		if got := verts[i*VertexStride+1] >> occlusionShift & 3; got != want {
+			t.Fatalf("vertex %d occlusion = %d, want %d", i, got, want)
+		}
+	}
+}
@@ -32,6 +32,12 @@ func meshCustomBlock(vertices *[]uint32, w *world.World, c *world.Chunk, x, y, z
 		return 0
 	}
 
+	// Foliage vertices are flagged for the wind animation
+	var flags uint32
+	if def.Foliage {
+		flags = foliageBit
+	}
+
 	for _, elem := range def.Elements {
 		// Optimization for Hybrid Rendering:
 		// If the block is Solid (like Grass), the Greedy Mesher handles the Top/Bottom faces (for optimization).
@@ -54,7 +60,7 @@ func meshCustomBlock(vertices *[]uint32, w *world.World, c *world.Chunk, x, y, z
 
 			// determine normal
 			var nm byte
-			var nx, ny, nz float32
+			var nx, ny, nz int
 			switch dir {
 			case "up":
 				nm, nx, ny, nz = 4, 0, 1, 0
@@ -69,29 +75,15 @@ func meshCustomBlock(vertices *[]uint32, w *world.World, c *world.Chunk, x, y, z
 			case "east":
 				nm, nx, ny, nz = 2, 1, 0, 0
 			}
-			_ = nx
-			_ = ny
-			_ = nz // unused logic vars
 
 			texID := getTexID(dir)
 
 			// Tint?
 			tint := uint16(0xFFFF)
 			if face.TintIndex != nil && *face.TintIndex > -1 && def.TintColor != 0 {
-				// Simplified tint logic
-				r := (def.TintColor >> 16) & 0xFF
-				g := (def.TintColor >> 8) & 0xFF
-				b := def.TintColor & 0xFF
-				r5, g6, b5 := (r>>3)&0x1F, (g>>2)&0x3F, (b>>3)&0x1F
-				tint = uint16((r5 << 11) | (g6 << 5) | b5)
-			}
-			brightness := byte(204)
-			if nm == 4 {
-				brightness = 255
-			}
-			if nm == 5 {
-				brightness = 128
+				tint = registry.PackRGB565(registry.BiomeColor(def, w.BiomeColors(c), x, z))
 			}
+			light := blockFaceLight(w, c, x, y, z, nx, ny, nz)
 
 			// Coordinates
 			// Convert Element From/To (0-16) to Local Integer +0 or +1
@@ -245,6 +237,10 @@ func meshCustomBlock(vertices *[]uint32, w *world.World, c *world.Chunk, x, y, z
 					if neighborDef.IsSolid && !neighborDef.IsTransparent {
 						emit = false
 					}
+					// Nor between two blocks of glass
+					if neighborDef == def && def.HidesOwnFaces {
+						emit = false
+					}
 				}
 			}
 
@@ -252,21 +248,13 @@ func meshCustomBlock(vertices *[]uint32, w *world.World, c *world.Chunk, x, y, z
 				continue
 			}
 
-			// Emit Quad (2 Triangles) — uses package-level packVertex from greedy.go.
-			// Tri 1: qa, qb, qc
-			v1, v2 := packVertex(qa[0], qa[1], qa[2], nm, texID, brightness, tint)
-			*vertices = append(*vertices, v1, v2)
-			v1, v2 = packVertex(qb[0], qb[1], qb[2], nm, texID, brightness, tint)
-			*vertices = append(*vertices, v1, v2)
-			v1, v2 = packVertex(qc[0], qc[1], qc[2], nm, texID, brightness, tint)
-			*vertices = append(*vertices, v1, v2)
-
-			// Tri 2: qc, qd, qa
-			*vertices = append(*vertices, v1, v2) // reuse qc
-			v1, v2 = packVertex(qd[0], qd[1], qd[2], nm, texID, brightness, tint)
-			*vertices = append(*vertices, v1, v2)
-			v1, v2 = packVertex(qa[0], qa[1], qa[2], nm, texID, brightness, tint) // reuse qa
-			*vertices = append(*vertices, v1, v2)
+			// Emit the quad, drawn as the triangles qa,qb,qc and qc,qd,qa
+			var q [4][2]uint32
+			for i, corner := range [4][3]int{qa, qb, qc, qd} {
+				v1, v2 := packVertex(corner[0], corner[1], corner[2], nm, texID, light, tint)
+				q[i] = [2]uint32{v1 | flags, v2}
+			}
+			appendQuad(vertices, q)
 		}
 	}
 }
@@ -0,0 +1,26 @@
+package meshing
+
+import (
+	"testing"
+
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+)
+
+func TestCustomModelFlagsFoliage(t *testing.T) {
+	w := world.New()
+	defer w.Close()
+	c := w.GetChunk(0, 0, 0, true)
+	c.SetBlock(4, 10, 4, world.BlockTypeOakLeaves)
+
+	var verts []uint32
+	meshCustomBlock(&verts, w, c, 4, 10, 4, registry.BlockDefs[world.BlockTypeOakLeaves])
+	if len(verts) == 0 {
+		t.Fatal("leaves produced no vertices")
+	}
+	for i := 0; i < len(verts); i += VertexStride {
+		if verts[i]&foliageBit == 0 {
+			t.Fatalf("leaf vertex %d is not flagged as foliage", i/VertexStride)
+		}
+	}
+}
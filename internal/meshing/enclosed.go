@@ -0,0 +1,70 @@
+package meshing
+
+import (
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+)
+
+// sectionMask flags sections of a chunk column, indexed by section Y.
+type sectionMask [world.NumSections]bool
+
+// has reports whether secIdx is flagged. A nil mask flags nothing.
+func (m *sectionMask) has(secIdx int) bool {
+	return m != nil && m[secIdx]
+}
+
+// findEnclosedSections flags sections that cannot produce any visible face:
+// every block is an opaque full cube and every block touching the section from
+// outside (above, below and the four horizontal neighbours) is an occluder.
+// Only whole-section scans are used, so this runs before any face is meshed.
+// The bottom and top sections are never flagged because their outer faces
+// border the world edge, and sections next to unloaded chunks are kept so
+// their border faces exist until the neighbour loads.
+func findEnclosedSections(c *world.Chunk, neighbors *[6]*world.Chunk) (mask sectionMask, count int) {
+	fullCube := registry.FullCubeTable()
+	occluder := registry.OccluderTable()
+
+	// Horizontal neighbours: +X, -X, +Z, -Z
+	sides := [4]*world.Chunk{neighbors[0], neighbors[1], neighbors[4], neighbors[5]}
+	for _, n := range sides {
+		if n == nil {
+			return mask, 0
+		}
+	}
+
+	// Cache per-section occluder checks for this chunk; each is reused by the
+	// sections directly above and below it.
+	var occluderChecked, occluderFull sectionMask
+	isOccluderFull := func(secIdx int) bool {
+		if !occluderChecked[secIdx] {
+			occluderChecked[secIdx] = true
+			occluderFull[secIdx] = c.SectionAllMatch(secIdx, occluder)
+		}
+		return occluderFull[secIdx]
+	}
+
+	for secIdx := 1; secIdx < world.NumSections-1; secIdx++ {
+		if c.IsSectionEmpty(secIdx) || !c.SectionAllMatch(secIdx, fullCube) {
+			continue
+		}
+		// Full cubes are occluders too.
+		occluderChecked[secIdx] = true
+		occluderFull[secIdx] = true
+
+		if !isOccluderFull(secIdx-1) || !isOccluderFull(secIdx+1) {
+			continue
+		}
+		enclosed := true
+		for _, n := range sides {
+			if !n.SectionAllMatch(secIdx, occluder) {
+				enclosed = false
+				break
+			}
+		}
+		if enclosed {
+			mask[secIdx] = true
+			count++
+		}
+	}
+	return mask, count
+}
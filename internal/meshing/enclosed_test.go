@@ -0,0 +1,89 @@
+package meshing
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+)
+
+// fillStone fills chunks (-1..1, -1..1) with stone from y=0 up to height-1.
+func fillStone(w *world.World, height int) {
+	for cx := -1; cx <= 1; cx++ {
+		for cz := -1; cz <= 1; cz++ {
+			c := w.GetChunk(cx, 0, cz, true)
+			for x := range world.ChunkSizeX {
+				for z := range world.ChunkSizeZ {
+					for y := range height {
+						c.SetBlock(x, y, z, world.BlockTypeStone)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestFindEnclosedSections_SolidGround(t *testing.T) {
+	w := world.New()
+	defer w.Close()
+	fillStone(w, 64)
+
+	c := w.GetChunk(0, 0, 0, false)
+	neighbors := [6]*world.Chunk{
+		w.GetChunk(1, 0, 0, false), w.GetChunk(-1, 0, 0, false),
+		nil, nil,
+		w.GetChunk(0, 0, 1, false), w.GetChunk(0, 0, -1, false),
+	}
+	mask, count := findEnclosedSections(c, &neighbors)
+
+	// Section 0 borders the world bottom and section 3 has air above it.
+	if count != 2 || !mask[1] || !mask[2] || mask[0] || mask[3] {
+		t.Fatalf("enclosed = %v (count %d), want sections 1 and 2", mask, count)
+	}
+
+	// A hole in a neighbouring chunk exposes the section next to it.
+	w.GetChunk(1, 0, 0, false).SetBlock(0, 20, 5, world.BlockTypeAir)
+	mask, count = findEnclosedSections(c, &neighbors)
+	if count != 1 || mask[1] || !mask[2] {
+		t.Fatalf("after hole enclosed = %v (count %d), want only section 2", mask, count)
+	}
+
+	// Unloaded neighbours keep every section meshable.
+	neighbors[0] = nil
+	if _, count = findEnclosedSections(c, &neighbors); count != 0 {
+		t.Fatalf("with unloaded neighbour count = %d, want 0", count)
+	}
+}
+
+func TestBuildGreedyMesh_SkippingEnclosedKeepsGeometry(t *testing.T) {
+	w := world.New()
+	defer w.Close()
+	fillStone(w, 64)
+	// Carve a cave in the centre chunk so the mesh is not trivial.
+	c := w.GetChunk(0, 0, 0, false)
+	for x := 4; x < 8; x++ {
+		for y := 52; y < 56; y++ {
+			c.SetBlock(x, y, 6, world.BlockTypeAir)
+		}
+	}
+
+	pool := NewDirectionWorkerPool(6, 32)
+	pool.Start()
+	verts, _, enclosed := buildGreedyMesh(w, c, pool)
+	if enclosed != 1 {
+		t.Fatalf("enclosed = %d, want 1 (the cave exposes sections 2 and 3)", enclosed)
+	}
+
+	want := 0
+	neighbors := [6]*world.Chunk{
+		w.GetChunk(1, 0, 0, false), w.GetChunk(-1, 0, 0, false),
+		nil, nil,
+		w.GetChunk(0, 0, 1, false), w.GetChunk(0, 0, -1, false),
+	}
+	dirs := [6][3]int{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}, {0, 0, 1}, {0, 0, -1}}
+	for i, d := range dirs {
+		want += len(buildGreedyForDirection(w, c, d[0], d[1], d[2], neighbors[i], nil))
+	}
+	if len(verts) != want {
+		t.Fatalf("mesh with skipping has %d uint32s, without %d", len(verts), want)
+	}
+}
@@ -2,6 +2,7 @@ package meshing
 
 import (
 	"math"
+	"mini-mc/internal/mathutil"
 	"mini-mc/internal/registry"
 	"mini-mc/internal/world"
 )
@@ -97,8 +98,12 @@ func getMetaLocal(c *world.Chunk, nb neighbors6, lx, ly, lz int) uint8 {
 	return nc.GetMeta(nlx, nly, nlz)
 }
 
-// BuildFluidMesh generates vertices for fluid blocks (water/lava) in the chunk.
-// Vertex format: Pos(3), UV(2), TexID(1), Tint(3), FlowAngle(1) = 10 floats.
+// FluidVertexFloats is the size of a fluid vertex: Pos(3), UV(2), TexID(1),
+// Tint(3), FlowAngle(1), Light(1).
+const FluidVertexFloats = 11
+
+// BuildFluidMesh generates vertices for fluid blocks (water/lava) in the chunk,
+// FluidVertexFloats floats each.
 func BuildFluidMesh(w *world.World, c *world.Chunk) []float32 {
 	var vertices []float32
 
@@ -115,6 +120,8 @@ func BuildFluidMesh(w *world.World, c *world.Chunk) []float32 {
 		w.GetChunk(c.X, c.Y, c.Z-1, false), // 5: -Z north
 	}
 
+	bc := w.BiomeColors(c)
+
 	// Base world coordinates for this chunk.
 	baseX := c.X * world.ChunkSizeX
 	baseY := c.Y * world.ChunkSizeY
@@ -138,7 +145,7 @@ func BuildFluidMesh(w *world.World, c *world.Chunk) []float32 {
 						continue
 					}
 
-					renderFluidBlock(c, nb, lx, y, lz, baseX, baseY, baseZ, blockType, &vertices)
+					renderFluidBlock(c, nb, lx, y, lz, baseX, baseY, baseZ, blockType, bc, &vertices)
 				}
 			}
 		}
@@ -147,7 +154,7 @@ func BuildFluidMesh(w *world.World, c *world.Chunk) []float32 {
 	return vertices
 }
 
-func renderFluidBlock(c *world.Chunk, nb neighbors6, lx, ly, lz int, baseX, baseY, baseZ int, blockType world.BlockType, vertices *[]float32) {
+func renderFluidBlock(c *world.Chunk, nb neighbors6, lx, ly, lz int, baseX, baseY, baseZ int, blockType world.BlockType, bc *world.BiomeColors, vertices *[]float32) {
 	// World-space position of this block.
 	wx, wy, wz := baseX+lx, baseY+ly, baseZ+lz
 
@@ -155,6 +162,7 @@ func renderFluidBlock(c *world.Chunk, nb neighbors6, lx, ly, lz int, baseX, base
 	isLava := blockType == world.BlockTypeLava
 
 	var stillTex, flowTex int
+	tint := [3]float32{1, 1, 1}
 
 	if isLava {
 		stillTex = registry.TextureMap["lava_still.png"]
@@ -162,7 +170,13 @@ func renderFluidBlock(c *world.Chunk, nb neighbors6, lx, ly, lz int, baseX, base
 	} else {
 		stillTex = registry.TextureMap["water_still.png"]
 		flowTex = registry.TextureMap["water_flow.png"]
+		water := bc.Water[lx*world.ChunkSizeZ+lz]
+		tint = [3]float32{float32(water>>16&0xFF) / 255, float32(water>>8&0xFF) / 255, float32(water&0xFF) / 255}
 	}
+	// Lit like the surface: the fluid's own light or that of the block
+	// above, whichever is brighter. The shader darkens the sky part by the
+	// time of day.
+	light := float32(brighterLight(c.Light(lx, ly, lz), c.Light(lx, ly+1, lz)))
 
 	// Neighbor visibility checks — all mutex-free via chunk-local lookups.
 	shouldRenderFace := func(dlx, dly, dlz int) bool {
@@ -222,14 +236,14 @@ func renderFluidBlock(c *world.Chunk, nb neighbors6, lx, ly, lz int, baseX, base
 		u4, v4 := float32(1.0), float32(0.0)
 
 		// Tri 1: NW, SW, SE
-		emitVertex(vertices, float32(wx), float32(wy)+f7, float32(wz), u1, v1, texID, flowAngle)
-		emitVertex(vertices, float32(wx), float32(wy)+f8, float32(wz)+1.0, u2, v2, texID, flowAngle)
-		emitVertex(vertices, float32(wx)+1.0, float32(wy)+f9, float32(wz)+1.0, u3, v3, texID, flowAngle)
+		emitVertex(vertices, float32(wx), float32(wy)+f7, float32(wz), u1, v1, texID, tint, flowAngle, light)
+		emitVertex(vertices, float32(wx), float32(wy)+f8, float32(wz)+1.0, u2, v2, texID, tint, flowAngle, light)
+		emitVertex(vertices, float32(wx)+1.0, float32(wy)+f9, float32(wz)+1.0, u3, v3, texID, tint, flowAngle, light)
 
 		// Tri 2: NW, SE, NE
-		emitVertex(vertices, float32(wx), float32(wy)+f7, float32(wz), u1, v1, texID, flowAngle)
-		emitVertex(vertices, float32(wx)+1.0, float32(wy)+f9, float32(wz)+1.0, u3, v3, texID, flowAngle)
-		emitVertex(vertices, float32(wx)+1.0, float32(wy)+f10, float32(wz), u4, v4, texID, flowAngle)
+		emitVertex(vertices, float32(wx), float32(wy)+f7, float32(wz), u1, v1, texID, tint, flowAngle, light)
+		emitVertex(vertices, float32(wx)+1.0, float32(wy)+f9, float32(wz)+1.0, u3, v3, texID, tint, flowAngle, light)
+		emitVertex(vertices, float32(wx)+1.0, float32(wy)+f10, float32(wz), u4, v4, texID, tint, flowAngle, light)
 	}
 
 	// Render Bottom
@@ -237,13 +251,13 @@ func renderFluidBlock(c *world.Chunk, nb neighbors6, lx, ly, lz int, baseX, base
 		texID := float32(stillTex)
 		yBottom := float32(wy)
 
-		emitVertex(vertices, float32(wx), yBottom, float32(wz)+1.0, 0, 1, texID, -3.0)
-		emitVertex(vertices, float32(wx), yBottom, float32(wz), 0, 0, texID, -3.0)
-		emitVertex(vertices, float32(wx)+1.0, yBottom, float32(wz), 1, 0, texID, -3.0)
+		emitVertex(vertices, float32(wx), yBottom, float32(wz)+1.0, 0, 1, texID, tint, -3.0, light)
+		emitVertex(vertices, float32(wx), yBottom, float32(wz), 0, 0, texID, tint, -3.0, light)
+		emitVertex(vertices, float32(wx)+1.0, yBottom, float32(wz), 1, 0, texID, tint, -3.0, light)
 
-		emitVertex(vertices, float32(wx), yBottom, float32(wz)+1.0, 0, 1, texID, -3.0)
-		emitVertex(vertices, float32(wx)+1.0, yBottom, float32(wz), 1, 0, texID, -3.0)
-		emitVertex(vertices, float32(wx)+1.0, yBottom, float32(wz)+1.0, 1, 1, texID, -3.0)
+		emitVertex(vertices, float32(wx), yBottom, float32(wz)+1.0, 0, 1, texID, tint, -3.0, light)
+		emitVertex(vertices, float32(wx)+1.0, yBottom, float32(wz), 1, 0, texID, tint, -3.0, light)
+		emitVertex(vertices, float32(wx)+1.0, yBottom, float32(wz)+1.0, 1, 1, texID, tint, -3.0, light)
 	}
 
 	// Sides: flow texture, scroll downward (-2.0 sentinel)
@@ -254,14 +268,14 @@ func renderFluidBlock(c *world.Chunk, nb neighbors6, lx, ly, lz int, baseX, base
 		v2 := 1.0 - h2
 
 		// Tri 1
-		emitVertex(vertices, float32(wx)+x1, float32(wy)+h1, float32(wz)+z1, uStart, v1, texID, -2.0)
-		emitVertex(vertices, float32(wx)+x2, float32(wy)+h2, float32(wz)+z2, uStart+1.0, v2, texID, -2.0)
-		emitVertex(vertices, float32(wx)+x2, float32(wy), float32(wz)+z2, uStart+1.0, 1.0, texID, -2.0)
+		emitVertex(vertices, float32(wx)+x1, float32(wy)+h1, float32(wz)+z1, uStart, v1, texID, tint, -2.0, light)
+		emitVertex(vertices, float32(wx)+x2, float32(wy)+h2, float32(wz)+z2, uStart+1.0, v2, texID, tint, -2.0, light)
+		emitVertex(vertices, float32(wx)+x2, float32(wy), float32(wz)+z2, uStart+1.0, 1.0, texID, tint, -2.0, light)
 
 		// Tri 2
-		emitVertex(vertices, float32(wx)+x1, float32(wy)+h1, float32(wz)+z1, uStart, v1, texID, -2.0)
-		emitVertex(vertices, float32(wx)+x2, float32(wy), float32(wz)+z2, uStart+1.0, 1.0, texID, -2.0)
-		emitVertex(vertices, float32(wx)+x1, float32(wy), float32(wz)+z1, uStart, 1.0, texID, -2.0)
+		emitVertex(vertices, float32(wx)+x1, float32(wy)+h1, float32(wz)+z1, uStart, v1, texID, tint, -2.0, light)
+		emitVertex(vertices, float32(wx)+x2, float32(wy), float32(wz)+z2, uStart+1.0, 1.0, texID, tint, -2.0, light)
+		emitVertex(vertices, float32(wx)+x1, float32(wy), float32(wz)+z1, uStart, 1.0, texID, tint, -2.0, light)
 	}
 
 	if renderNorth { // -Z
@@ -284,9 +298,10 @@ func renderFluidBlock(c *world.Chunk, nb neighbors6, lx, ly, lz int, baseX, base
 //	-2.0 = side face (scroll downward)
 //	-3.0 = bottom face (no animation)
 //	>=0  = directional flowing top (angle in radians, XZ plane)
-func emitVertex(vertices *[]float32, x, y, z float32, u, v float32, texID float32, flowAngle float32) {
-	r, g, b := float32(1.0), float32(1.0), float32(1.0)
-	*vertices = append(*vertices, x, y, z, u, v, texID, r, g, b, flowAngle)
+//
+// light is the packed sky<<4 | block light the vertex is drawn with.
+func emitVertex(vertices *[]float32, x, y, z float32, u, v float32, texID float32, tint [3]float32, flowAngle float32, light float32) {
+	*vertices = append(*vertices, x, y, z, u, v, texID, tint[0], tint[1], tint[2], flowAngle, light)
 }
 
 // computeFlowAngleLocal is the chunk-local variant of computeFlowAngle.
@@ -411,9 +426,9 @@ func getFluidHeight(w *world.World, wx, wy, wz int, blockType world.BlockType) f
 // worldToLocal resolves world coordinates to a chunk, its 6 neighbors, and
 // local coordinates.  Returns nil chunk when the position is unloaded.
 func worldToLocal(w *world.World, wx, wy, wz int) (*world.Chunk, neighbors6, int, int, int) {
-	cx := floorDivM(wx, world.ChunkSizeX)
-	cy := floorDivM(wy, world.ChunkSizeY)
-	cz := floorDivM(wz, world.ChunkSizeZ)
+	cx := mathutil.FloorDiv(wx, world.ChunkSizeX)
+	cy := mathutil.FloorDiv(wy, world.ChunkSizeY)
+	cz := mathutil.FloorDiv(wz, world.ChunkSizeZ)
 	c := w.GetChunk(cx, cy, cz, false)
 	if c == nil {
 		return nil, neighbors6{}, 0, 0, 0
@@ -426,23 +441,8 @@ func worldToLocal(w *world.World, wx, wy, wz int) (*world.Chunk, neighbors6, int
 		w.GetChunk(cx, cy, cz+1, false),
 		w.GetChunk(cx, cy, cz-1, false),
 	}
-	lx := modM(wx, world.ChunkSizeX)
-	ly := modM(wy, world.ChunkSizeY)
-	lz := modM(wz, world.ChunkSizeZ)
+	lx := mathutil.Mod(wx, world.ChunkSizeX)
+	ly := mathutil.Mod(wy, world.ChunkSizeY)
+	lz := mathutil.Mod(wz, world.ChunkSizeZ)
 	return c, nb, lx, ly, lz
 }
-
-func floorDivM(a, b int) int {
-	if a >= 0 {
-		return a / b
-	}
-	return (a - b + 1) / b
-}
-
-func modM(a, b int) int {
-	r := a % b
-	if r < 0 {
-		r += b
-	}
-	return r
-}
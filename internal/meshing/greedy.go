@@ -15,6 +15,7 @@ type directionJob struct {
 	chunk         *world.Chunk
 	nx, ny, nz    int
 	neighborChunk *world.Chunk
+	skip          *sectionMask
 	resultChan    chan []uint32
 }
 
@@ -74,13 +75,14 @@ func (p *DirectionWorkerPool) Start() {
 // worker is the worker goroutine that processes direction jobs
 func (p *DirectionWorkerPool) worker(id int) {
 	for job := range p.jobQueue {
-		result := buildGreedyForDirection(job.world, job.chunk, job.nx, job.ny, job.nz, job.neighborChunk)
+		result := buildGreedyForDirection(job.world, job.chunk, job.nx, job.ny, job.nz, job.neighborChunk, job.skip)
 		job.resultChan <- result
 	}
 }
 
 // SubmitJob submits a direction job to the pool and returns a result channel
-func (p *DirectionWorkerPool) SubmitJob(w *world.World, c *world.Chunk, nx, ny, nz int, neighborChunk *world.Chunk) chan []uint32 {
+// skip flags sections that are left out of the mesh; it may be nil.
+func (p *DirectionWorkerPool) SubmitJob(w *world.World, c *world.Chunk, nx, ny, nz int, neighborChunk *world.Chunk, skip *sectionMask) chan []uint32 {
 	resultChan := resultChanPool.Get().(chan []uint32)
 	job := directionJob{
 		world:         w,
@@ -89,49 +91,105 @@ func (p *DirectionWorkerPool) SubmitJob(w *world.World, c *world.Chunk, nx, ny,
 		ny:            ny,
 		nz:            nz,
 		neighborChunk: neighborChunk,
+		skip:          skip,
 		resultChan:    resultChan,
 	}
 	p.jobQueue <- job
 	return resultChan
 }
 
-// packVertex encodes local x,y,z, normal, brightness, textureID and tint into two uint32s.
-// V1 Layout: X[4:0] Y[13:5] Z[18:14] N[21:19] B[29:22]
-// V2 Layout: T[15:0] C[31:16]
-func packVertex(x, y, z int, normal byte, texID int, brightness byte, tint uint16) (uint32, uint32) {
-	v1 := uint32(x) | (uint32(y) << 5) | (uint32(z) << 14) | (uint32(normal) << 19) | (uint32(brightness) << 22)
+// packVertex encodes local x,y,z, normal, light, textureID and tint into two uint32s.
+// V1 Layout: X[4:0] Y[13:5] Z[18:14] N[21:19] L[29:22] V[30] F[31]
+// V2 Layout: T[9:0] A[11:10] D[15:12] C[31:16]
+// L is the light the vertex is drawn with, sky<<4 | block (see light.go).
+// A is the vertex's ambient occlusion level, 0 (open) to 3; emitQuad sets it.
+// D lowers the vertex by D/16 of a block; it is always 0 here (see packVertexLowered).
+// V marks faces of natural blocks whose texture varies per block (see emitQuad).
+// F marks foliage vertices, which the shader sways in the wind.
+func packVertex(x, y, z int, normal byte, texID int, light uint8, tint uint16) (uint32, uint32) {
+	v1 := uint32(x) | (uint32(y) << 5) | (uint32(z) << 14) | (uint32(normal) << 19) | (uint32(light) << 22)
 	v2 := uint32(texID) | (uint32(tint) << 16)
 	return v1, v2
 }
 
-// emitQuad appends two triangles (6 vertices, 12 uint32s) to the vertices slice.
+// packVertexLowered is packVertex for partial-height blocks: the vertex is
+// drawn drop/16 of a block below y (drop 0-15).
+func packVertexLowered(x, y, z, drop int, normal byte, texID int, light uint8, tint uint16) (uint32, uint32) {
+	v1, v2 := packVertex(x, y, z, normal, texID, light, tint)
+	return v1, v2 | (uint32(drop&0xF) << 12)
+}
+
+// Flags in the first packed word
+const (
+	variedBit  = 1 << 30 // V
+	foliageBit = 1 << 31 // F
+)
+
+// occlusionShift is the position of A in the second packed word.
+const occlusionShift = 10
+
+// emitQuad appends a quad (4 vertices, 8 uint32s) to the vertices slice.
 // Triangle 1: v0,v1,v2  Triangle 2: v2,v3,v0
-func emitQuad(vertices *[]uint32, x0, y0, z0, x1, y1, z1, x2, y2, z2, x3, y3, z3 int, encodedNormal byte, texID int, tint uint16) {
-	// Calculate brightness based on normal (Top=255, Bottom=128, Sides=204)
-	var brightness byte = 204 // Sides (0.8 * 255)
-	if encodedNormal == 4 {   // Top
-		brightness = 255 // 1.0 * 255
-	} else if encodedNormal == 5 { // Bottom
-		brightness = 128 // 0.5 * 255
+// With varied set, the shader rotates and tints the texture per block
+// position, so the quad may span several blocks without tiling uniformly.
+// light is the face's packed light. occl is each vertex's ambient occlusion
+// level. When v0 and v2 are the more occluded pair, the quad is split along
+// v1-v3 instead, so the darkness of a corner does not bleed along the
+// diagonal.
+func emitQuad(vertices *[]uint32, x0, y0, z0, x1, y1, z1, x2, y2, z2, x3, y3, z3 int, encodedNormal byte, texID int, tint uint16, varied bool, light uint8, occl [4]uint8) {
+	v1a, v2a := packVertex(x0, y0, z0, encodedNormal, texID, light, tint)
+	v1b, v2b := packVertex(x1, y1, z1, encodedNormal, texID, light, tint)
+	v1c, v2c := packVertex(x2, y2, z2, encodedNormal, texID, light, tint)
+	v1d, v2d := packVertex(x3, y3, z3, encodedNormal, texID, light, tint)
+	if varied {
+		v1a, v1b, v1c, v1d = v1a|variedBit, v1b|variedBit, v1c|variedBit, v1d|variedBit
 	}
+	v2a |= uint32(occl[0]) << occlusionShift
+	v2b |= uint32(occl[1]) << occlusionShift
+	v2c |= uint32(occl[2]) << occlusionShift
+	v2d |= uint32(occl[3]) << occlusionShift
 
-	v1a, v2a := packVertex(x0, y0, z0, encodedNormal, texID, brightness, tint)
-	v1b, v2b := packVertex(x1, y1, z1, encodedNormal, texID, brightness, tint)
-	v1c, v2c := packVertex(x2, y2, z2, encodedNormal, texID, brightness, tint)
-	v1d, v2d := packVertex(x3, y3, z3, encodedNormal, texID, brightness, tint)
+	if occl[0]+occl[2] > occl[1]+occl[3] {
+		appendQuad(vertices, [4][2]uint32{{v1b, v2b}, {v1c, v2c}, {v1d, v2d}, {v1a, v2a}})
+		return
+	}
+	appendQuad(vertices, [4][2]uint32{{v1a, v2a}, {v1b, v2b}, {v1c, v2c}, {v1d, v2d}})
+}
 
-	*vertices = append(*vertices, v1a, v2a, v1b, v2b, v1c, v2c, v1c, v2c, v1d, v2d, v1a, v2a)
+// faceKey packs what two faces must share to merge into one greedy quad
+// into a non-zero mask value; occl is the face's packed corner occlusion and
+// light its packed light.
+func faceKey(texID int, tint uint16, varied bool, occl, light uint8) int {
+	key := int(light)<<40 | int(occl)<<32 | int(tint)<<16 | texID
+	if varied {
+		key |= 1 << 15
+	}
+	return key + 1
 }
 
-// BuildGreedyMeshForChunk builds a greedy-meshed triangle list (packed uint32)
+// splitFaceKey reverses faceKey.
+func splitFaceKey(key int) (texID int, tint uint16, varied bool, occl, light uint8) {
+	val := key - 1
+	return val & 0x7FFF, uint16(val >> 16), val&(1<<15) != 0, uint8(val >> 32), uint8(val >> 40)
+}
+
+// BuildGreedyMeshForChunk builds a greedy-meshed quad list (packed uint32, see quad.go)
 // for the given chunk using world coordinates to decide face visibility across chunk borders.
 // Uses the provided worker pool to process all 6 directions in parallel.
 // Returns []uint32 where each vertex is 2 packed uint32s containing:
-// V1: X (5), Y (9), Z (5), Normal (3), Brightness (8)
+// V1: X (5), Y (9), Z (5), Normal (3), Light (8)
 // V2: TextureID (16), Tint (16 bits RGB565)
 func BuildGreedyMeshForChunk(w *world.World, c *world.Chunk, pool *DirectionWorkerPool) []uint32 {
+	vertices, _, _ := buildGreedyMesh(w, c, pool)
+	return vertices
+}
+
+// buildGreedyMesh is BuildGreedyMeshForChunk that also returns the vertices
+// of translucent blocks, in the same format but kept apart for blending, and
+// reports how many fully enclosed sections were skipped.
+func buildGreedyMesh(w *world.World, c *world.Chunk, pool *DirectionWorkerPool) (vertices, translucent []uint32, enclosedCount int) {
 	if c == nil {
-		return nil
+		return nil, nil, 0
 	}
 
 	// Pre-fetch neighbor chunks once to avoid repeated RWMutex acquisitions during meshing.
@@ -144,6 +202,24 @@ func BuildGreedyMeshForChunk(w *world.World, c *world.Chunk, pool *DirectionWork
 		w.GetChunk(c.X, c.Y, c.Z-1, false), // -Z (south)
 	}
 
+	// Sections buried in opaque blocks produce no faces; leave them out of
+	// every pass. If nothing else is left, skip meshing altogether.
+	enclosed, enclosedCount := findEnclosedSections(c, &neighbors)
+	hasMeshable := false
+	for secIdx := range world.NumSections {
+		if !c.IsSectionEmpty(secIdx) && !enclosed[secIdx] {
+			hasMeshable = true
+			break
+		}
+	}
+	if !hasMeshable {
+		return nil, nil, enclosedCount
+	}
+	var skip *sectionMask
+	if enclosedCount > 0 {
+		skip = &enclosed
+	}
+
 	// Submit all 6 direction jobs to the worker pool.
 	// Use a fixed-size array to avoid a heap allocation for the slice header.
 	var directions [6]struct {
@@ -183,7 +259,7 @@ func BuildGreedyMeshForChunk(w *world.World, c *world.Chunk, pool *DirectionWork
 	}{nz: -1, neighborChunk: neighbors[5]}
 
 	for i := range directions {
-		directions[i].resultChan = pool.SubmitJob(w, c, directions[i].nx, directions[i].ny, directions[i].nz, directions[i].neighborChunk)
+		directions[i].resultChan = pool.SubmitJob(w, c, directions[i].nx, directions[i].ny, directions[i].nz, directions[i].neighborChunk, skip)
 	}
 
 	// Collect results from all directions
@@ -196,7 +272,7 @@ func BuildGreedyMeshForChunk(w *world.World, c *world.Chunk, pool *DirectionWork
 	}
 
 	// Combine all results into a single slice
-	vertices := make([]uint32, 0, totalSize)
+	vertices = make([]uint32, 0, totalSize)
 	for _, result := range results {
 		vertices = append(vertices, result...)
 	}
@@ -209,7 +285,7 @@ func BuildGreedyMeshForChunk(w *world.World, c *world.Chunk, pool *DirectionWork
 	for x := 0; x < world.ChunkSizeX; x++ {
 		for z := 0; z < world.ChunkSizeZ; z++ {
 			for secIdx := 0; secIdx < world.NumSections; secIdx++ {
-				if c.IsSectionEmpty(secIdx) {
+				if c.IsSectionEmpty(secIdx) || skip.has(secIdx) {
 					continue // skip entire 16-block Y section
 				}
 				baseY := secIdx * world.SectionHeight
@@ -225,6 +301,29 @@ func BuildGreedyMeshForChunk(w *world.World, c *world.Chunk, pool *DirectionWork
 						continue
 					}
 
+					if def.EntityRendered {
+						continue
+					}
+
+					// Snow layer height comes from metadata, not the model.
+					if bt == world.BlockTypeSnowLayer {
+						meshSnowLayer(&vertices, w, c, x, y, z, def)
+						continue
+					}
+					if bt == world.BlockTypeRail {
+						meshRail(&vertices, c, x, y, z)
+						continue
+					}
+					if world.IsPlant(bt) {
+						meshPlant(&vertices, w, c, x, y, z, def)
+						continue
+					}
+
+					if def.Translucent {
+						meshCustomBlock(&translucent, w, c, x, y, z, def)
+						continue
+					}
+
 					// Transparent blocks (leaves) and complex/non-solid blocks are handled by custom model pass.
 					if !def.IsSolid || def.IsTransparent || len(def.Elements) > 1 {
 						// Appends directly into vertices to avoid an intermediate allocation.
@@ -235,14 +334,15 @@ func BuildGreedyMeshForChunk(w *world.World, c *world.Chunk, pool *DirectionWork
 		}
 	}
 
-	return vertices
+	return vertices, translucent, enclosedCount
 }
 
 // buildGreedyForDirection performs 2D greedy meshing for one face direction.
 // The direction is specified by a normal (nx,ny,nz) where exactly one component is -1 or +1 and the others are 0.
 // neighborChunk is the pre-fetched chunk adjacent in the (nx,ny,nz) direction; may be nil if not loaded.
-// It returns packed vertices forming triangles.
-func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, neighborChunk *world.Chunk) []uint32 {
+// Sections flagged in skip (may be nil) are treated like empty sections.
+// It returns packed vertices forming quads.
+func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, neighborChunk *world.Chunk, skip *sectionMask) []uint32 {
 	// Determine the axis fixed by the face normal and the two in-plane axes (u,v)
 	// We will iterate layers along the normal axis, and build a UxV mask for each layer.
 	var (
@@ -251,6 +351,8 @@ func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, nei
 	)
 	// Pre-allocate to reduce grow-copy allocations from repeated appends.
 	vertices := make([]uint32, 0, 512)
+	bc := w.BiomeColors(c)
+	ao := newAOSampler(w, c)
 
 	// Build per-layer masks and greedy-merge
 	if nx != 0 { // Faces perpendicular to X axis, plane is Y-Z
@@ -270,7 +372,7 @@ func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, nei
 
 			for y := range sy {
 				// Skip entire 16-block sections that are empty
-				if c.IsSectionEmpty(y >> 4) {
+				if c.IsSectionEmpty(y>>4) || skip.has(y>>4) {
 					continue
 				}
 				for z := range sz {
@@ -322,9 +424,10 @@ func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, nei
 						if nx < 0 {
 							faceIdx = 3
 						}
-						texID := registry.GetTexLayerFast(bt, faceIdx)
-						tint := registry.GetTintFast(bt, faceIdx)
-						mask[y*sz+z] = (int(tint)<<16 | texID) + 1
+						texID := registry.GetStateTexLayerFast(c.GetState(x, y, z), faceIdx)
+						tint := registry.GetBiomeTintFast(bt, faceIdx, bc, x, z)
+						occl := ao.faceOcclusion(x, y, z, [3]int{nx, 0, 0}, [3]int{0, 1, 0}, [3]int{0, 0, 1})
+						mask[y*sz+z] = faceKey(texID, tint, registry.IsVariedFast(bt), occl, ao.light(x+nx, y, z))
 					}
 				}
 			}
@@ -335,9 +438,7 @@ func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, nei
 					i++
 					continue
 				}
-				val := mask[i] - 1
-				texID := val & 0xFFFF
-				tint := uint16(val >> 16)
+				texID, tint, varied, occl, light := splitFaceKey(mask[i])
 
 				z0 := i % sz
 				y0 := i / sz
@@ -373,7 +474,8 @@ func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, nei
 						fx, y0+hHeight, z0,
 						fx, y0+hHeight, z0+wWidth,
 						fx, y0, z0+wWidth,
-						encodedNormal, texID, tint,
+						encodedNormal, texID, tint, varied, light,
+						quadOcclusion(occl, 0, 1, 3, 2),
 					)
 				} else { // -X
 					emitQuad(
@@ -382,7 +484,8 @@ func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, nei
 						fx, y0, z0+wWidth,
 						fx, y0+hHeight, z0+wWidth,
 						fx, y0+hHeight, z0,
-						encodedNormal, texID, tint,
+						encodedNormal, texID, tint, varied, light,
+						quadOcclusion(occl, 0, 2, 3, 1),
 					)
 				}
 				// zero-out mask
@@ -401,7 +504,7 @@ func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, nei
 	if ny != 0 { // Faces perpendicular to Y axis, plane is X-Z
 		for y := range sy {
 			// Skip layers whose section is empty
-			if c.IsSectionEmpty(y >> 4) {
+			if c.IsSectionEmpty(y>>4) || skip.has(y>>4) {
 				continue
 			}
 			maskPtr := maskPool.Get().([]int)
@@ -463,8 +566,9 @@ func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, nei
 							faceIdx = 5
 						}
 						texID := registry.GetTexLayerFast(bt, faceIdx)
-						tint := registry.GetTintFast(bt, faceIdx)
-						mask[x*sz+z] = (int(tint)<<16 | texID) + 1
+						tint := registry.GetBiomeTintFast(bt, faceIdx, bc, x, z)
+						occl := ao.faceOcclusion(x, y, z, [3]int{0, ny, 0}, [3]int{1, 0, 0}, [3]int{0, 0, 1})
+						mask[x*sz+z] = faceKey(texID, tint, registry.IsVariedFast(bt), occl, ao.light(x, y+ny, z))
 					}
 				}
 			}
@@ -475,9 +579,7 @@ func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, nei
 					i++
 					continue
 				}
-				val := mask[i] - 1
-				texID := val & 0xFFFF
-				tint := uint16(val >> 16)
+				texID, tint, varied, occl, light := splitFaceKey(mask[i])
 
 				x0 := i / sz
 				z0 := i % sz
@@ -512,7 +614,8 @@ func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, nei
 						x0, fy, z0+wWidth,
 						x0+hHeight, fy, z0+wWidth,
 						x0+hHeight, fy, z0,
-						encodedNormal, texID, tint,
+						encodedNormal, texID, tint, varied, light,
+						quadOcclusion(occl, 0, 2, 3, 1),
 					)
 				} else { // -Y
 					emitQuad(
@@ -521,7 +624,8 @@ func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, nei
 						x0+hHeight, fy, z0,
 						x0+hHeight, fy, z0+wWidth,
 						x0, fy, z0+wWidth,
-						encodedNormal, texID, tint,
+						encodedNormal, texID, tint, varied, light,
+						quadOcclusion(occl, 0, 1, 3, 2),
 					)
 				}
 				for xx := x0; xx < x0+hHeight; xx++ {
@@ -551,7 +655,7 @@ func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, nei
 		for x := range sx {
 			for y := range sy {
 				// Skip Y values whose section is empty — mask entry stays zero.
-				if c.IsSectionEmpty(y >> 4) {
+				if c.IsSectionEmpty(y>>4) || skip.has(y>>4) {
 					continue
 				}
 				bt := c.GetBlock(x, y, z)
@@ -600,9 +704,10 @@ func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, nei
 					if nz < 0 {
 						faceIdx = 1
 					}
-					texID := registry.GetTexLayerFast(bt, faceIdx)
-					tint := registry.GetTintFast(bt, faceIdx)
-					mask[x*sy+y] = (int(tint)<<16 | texID) + 1
+					texID := registry.GetStateTexLayerFast(c.GetState(x, y, z), faceIdx)
+					tint := registry.GetBiomeTintFast(bt, faceIdx, bc, x, z)
+					occl := ao.faceOcclusion(x, y, z, [3]int{0, 0, nz}, [3]int{1, 0, 0}, [3]int{0, 1, 0})
+					mask[x*sy+y] = faceKey(texID, tint, registry.IsVariedFast(bt), occl, ao.light(x, y, z+nz))
 				}
 			}
 		}
@@ -617,9 +722,7 @@ func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, nei
 				i++
 				continue
 			}
-			val := mask[i] - 1
-			texID := val & 0xFFFF
-			tint := uint16(val >> 16)
+			texID, tint, varied, occl, light := splitFaceKey(mask[i])
 
 			x0 := i / sy
 			y0 := i % sy
@@ -654,7 +757,8 @@ func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, nei
 					x0+hHeight, y0, fz,
 					x0+hHeight, y0+wWidth, fz,
 					x0, y0+wWidth, fz,
-					encodedNormal, texID, tint,
+					encodedNormal, texID, tint, varied, light,
+					quadOcclusion(occl, 0, 1, 3, 2),
 				)
 			} else { // -Z
 				emitQuad(
@@ -663,7 +767,8 @@ func buildGreedyForDirection(w *world.World, c *world.Chunk, nx, ny, nz int, nei
 					x0, y0+wWidth, fz,
 					x0+hHeight, y0+wWidth, fz,
 					x0+hHeight, y0, fz,
-					encodedNormal, texID, tint,
+					encodedNormal, texID, tint, varied, light,
+					quadOcclusion(occl, 0, 2, 3, 1),
 				)
 			}
 			for xx := x0; xx < x0+hHeight; xx++ {
@@ -0,0 +1,64 @@
+package meshing
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+)
+
+func TestGreedyMarksVariedFaces(t *testing.T) {
+	w := world.New()
+	defer w.Close()
+	c := w.GetChunk(0, 0, 0, true)
+	// A grass patch next to a planks patch, one block high
+	for x := range 8 {
+		for z := range world.ChunkSizeZ {
+			c.SetBlock(x, 10, z, world.BlockTypeGrass)
+			c.SetBlock(x+8, 10, z, world.BlockTypePlanksOak)
+		}
+	}
+
+	verts := buildGreedyForDirection(w, c, 0, 1, 0, nil, nil)
+	if len(verts) != 2*QuadVertices*VertexStride {
+		t.Fatalf("top faces have %d uint32s, want two quads", len(verts))
+	}
+	// Quads come out in x order: grass first, then planks
+	for i := 0; i < len(verts); i += VertexStride {
+		grass := i < QuadVertices*VertexStride
+		if varied := verts[i]&variedBit != 0; varied != grass {
+			t.Fatalf("vertex %d varied = %v, want %v", i/VertexStride, varied, grass)
+		}
+	}
+}
+
+func TestFaceKeyRoundTrip(t *testing.T) {
+	for _, varied := range []bool{false, true} {
+		key := faceKey(4095, 0xFFFF, varied, 0xFF, 0xFE)
+		if key == 0 {
+			t.Fatal("face key must not be zero")
+		}
+		tex, tint, v, occl, light := splitFaceKey(key)
+		if tex != 4095 || tint != 0xFFFF || v != varied || occl != 0xFF || light != 0xFE {
+			t.Fatalf("round trip = %d %#x %v %#x %#x, want 4095 0xffff %v 0xff 0xfe", tex, tint, v, occl, light, varied)
+		}
+	}
+}
+
+func TestGlassGoesToTheTranslucentMesh(t *testing.T) {
+	w := world.New()
+	defer w.Close()
+	c := w.GetChunk(0, 0, 0, true)
+	c.SetBlock(4, 10, 4, world.BlockTypeGlass)
+	c.SetBlock(5, 10, 4, world.BlockTypeStone)
+
+	pool := NewDirectionWorkerPool(6, 32)
+	pool.Start()
+	verts, translucent, _ := buildGreedyMesh(w, c, pool)
+	// The stone is seen through the glass, but the glass face against it is hidden
+	if len(verts) != 6*QuadVertices*VertexStride {
+		t.Fatalf("opaque mesh has %d uint32s, want the stone's six faces", len(verts))
+	}
+	if len(translucent) != 5*QuadVertices*VertexStride {
+		t.Fatalf("translucent mesh has %d uint32s, want the glass's five faces", len(translucent))
+	}
+}
@@ -0,0 +1,59 @@
+package meshing
+
+import "mini-mc/internal/world"
+
+// Faces are drawn with the light of the block in front of them, packed
+// sky<<4 | block into the byte of the first vertex word that used to hold a
+// per-face brightness; the shader turns it into brightness and shades faces
+// by their normal and darkens sky light by the time of day. Greedy faces
+// only merge when their light matches.
+
+// fullLight is the light of open sky, used where no light is known.
+const fullLight = world.MaxLight << 4
+
+// light returns the packed light at local (x, y, z), at most one block
+// outside the chunk sideways. Chunks that are not loaded count as open sky.
+func (s *aoSampler) light(x, y, z int) uint8 {
+	if y < 0 {
+		return 0
+	}
+	cx, cz := 1, 1
+	switch {
+	case x < 0:
+		cx = 0
+	case x >= world.ChunkSizeX:
+		cx = 2
+	}
+	switch {
+	case z < 0:
+		cz = 0
+	case z >= world.ChunkSizeZ:
+		cz = 2
+	}
+	c := s.chunks[cx][cz]
+	if c == nil {
+		return fullLight
+	}
+	return c.Light(x&(world.ChunkSizeX-1), y, z&(world.ChunkSizeZ-1))
+}
+
+// blockFaceLight returns the light the face of the block at local (x, y, z)
+// facing (dx, dy, dz) is drawn with: the brighter, per channel, of the
+// block's own light and that of the block in front, since blocks that let
+// light in hold it themselves and opaque ones hold none.
+func blockFaceLight(w *world.World, c *world.Chunk, x, y, z, dx, dy, dz int) uint8 {
+	own := c.Light(x, y, z)
+	fx, fy, fz := x+dx, y+dy, z+dz
+	var front uint8
+	if fx >= 0 && fx < world.ChunkSizeX && fz >= 0 && fz < world.ChunkSizeZ {
+		front = c.Light(fx, fy, fz)
+	} else {
+		front = w.Light(c.X*world.ChunkSizeX+fx, fy, c.Z*world.ChunkSizeZ+fz)
+	}
+	return brighterLight(own, front)
+}
+
+// brighterLight returns the brighter of two packed lights per channel.
+func brighterLight(a, b uint8) uint8 {
+	return max(a>>4, b>>4)<<4 | max(a&world.MaxLight, b&world.MaxLight)
+}
@@ -0,0 +1,173 @@
+package meshing
+
+import (
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+)
+
+// Distant chunks are drawn from level-of-detail meshes. The chunk is split
+// into cells of step³ blocks; a cell is solid when at least half its blocks
+// hide adjacent faces, and shows the block its highest such block is, so
+// grass stays on top of hills. One quad is emitted per face between a solid
+// cell and an open one. LOD meshes take full sky light and no ambient
+// occlusion: from that far the shading would not be seen.
+
+// LODSteps are the cell sizes LOD meshes are built at, finest first.
+var LODSteps = [...]int{2, 4}
+
+// LODMesh is a chunk meshed at Step blocks per cell.
+type LODMesh struct {
+	Step     int
+	Vertices []uint32    // packed quads like the full mesh (see quad.go)
+	Columns  []LODColumn // surface of each cell column, for AppendLODSkirts
+}
+
+// BuildLODMeshes meshes c at every step of LODSteps.
+func BuildLODMeshes(w *world.World, c *world.Chunk) []LODMesh {
+	meshes := make([]LODMesh, 0, len(LODSteps))
+	for _, step := range LODSteps {
+		meshes = append(meshes, BuildLODMesh(w, c, step))
+	}
+	return meshes
+}
+
+// lodGrid is a chunk downsampled to cells: the block state each cell shows,
+// air for open cells, indexed (x*ny+y)*n+z.
+type lodGrid struct {
+	n, ny int
+	cells []world.BlockState
+}
+
+func (g *lodGrid) at(x, y, z int) world.BlockState {
+	return g.cells[(x*g.ny+y)*g.n+z]
+}
+
+// sampleLODCell returns the state cell (cx, cy, cz) of c shows at step.
+func sampleLODCell(c *world.Chunk, step, cx, cy, cz int, occluder *[256]bool) world.BlockState {
+	x0, y0, z0 := cx*step, cy*step, cz*step
+	solid := 0
+	top := world.StateAir
+	for y := y0 + step - 1; y >= y0; y-- {
+		for x := x0; x < x0+step; x++ {
+			for z := z0; z < z0+step; z++ {
+				bt := c.GetBlock(x, y, z)
+				if !occluder[bt] {
+					continue
+				}
+				solid++
+				if top.Type == world.BlockTypeAir {
+					top = c.GetState(x, y, z)
+				}
+			}
+		}
+	}
+	if 2*solid < step*step*step {
+		return world.StateAir
+	}
+	return top
+}
+
+func sampleLODGrid(c *world.Chunk, step int) lodGrid {
+	occluder := registry.OccluderTable()
+	g := lodGrid{n: world.ChunkSizeX / step, ny: world.ChunkSizeY / step}
+	g.cells = make([]world.BlockState, g.n*g.ny*g.n)
+	for x := range g.n {
+		for y := range g.ny {
+			if c.IsSectionEmpty(y * step / world.SectionHeight) {
+				continue
+			}
+			for z := range g.n {
+				g.cells[(x*g.ny+y)*g.n+z] = sampleLODCell(c, step, x, y, z, occluder)
+			}
+		}
+	}
+	return g
+}
+
+// BuildLODMesh meshes c at step blocks per cell (a divisor of the chunk
+// size). Cell faces on the chunk's sides are tested against the neighbouring
+// chunks sampled at the same step; sides facing unloaded chunks are left
+// closed.
+func BuildLODMesh(w *world.World, c *world.Chunk, step int) LODMesh {
+	g := sampleLODGrid(c, step)
+	bc := w.BiomeColors(c)
+	occluder := registry.OccluderTable()
+
+	// Only the cells along the shared side are sampled from each neighbour
+	neighbours := [4]*world.Chunk{
+		w.GetChunk(c.X, c.Y, c.Z+1, false), // north (+Z)
+		w.GetChunk(c.X, c.Y, c.Z-1, false), // south (-Z)
+		w.GetChunk(c.X+1, c.Y, c.Z, false), // east (+X)
+		w.GetChunk(c.X-1, c.Y, c.Z, false), // west (-X)
+	}
+	open := func(x, y, z int) bool {
+		if y < 0 || y >= g.ny {
+			return y >= g.ny
+		}
+		var nb *world.Chunk
+		switch {
+		case z >= g.n:
+			nb, z = neighbours[0], 0
+		case z < 0:
+			nb, z = neighbours[1], g.n-1
+		case x >= g.n:
+			nb, x = neighbours[2], 0
+		case x < 0:
+			nb, x = neighbours[3], g.n-1
+		default:
+			return g.at(x, y, z).Type == world.BlockTypeAir
+		}
+		if nb == nil {
+			return false
+		}
+		return sampleLODCell(nb, step, x, y, z, occluder).Type == world.BlockTypeAir
+	}
+
+	mesh := LODMesh{Step: step, Columns: make([]LODColumn, g.n*g.n)}
+	vertices := make([]uint32, 0, 512)
+	for cx := range g.n {
+		for cz := range g.n {
+			x0, z0 := cx*step, cz*step
+			x1, z1 := x0+step, z0+step
+			col := &mesh.Columns[cx*g.n+cz]
+			for cy := range g.ny {
+				st := g.at(cx, cy, cz)
+				if st.Type == world.BlockTypeAir {
+					continue
+				}
+				y0, y1 := cy*step, (cy+1)*step
+				face := func(faceIdx int) (int, uint16) {
+					return registry.GetStateTexLayerFast(st, faceIdx), registry.GetBiomeTintFast(st.Type, faceIdx, bc, x0, z0)
+				}
+				if open(cx, cy, cz+1) {
+					tex, tint := face(0)
+					emitQuad(&vertices, x0, y0, z1, x1, y0, z1, x1, y1, z1, x0, y1, z1, 0, tex, tint, false, fullLight, [4]uint8{})
+				}
+				if open(cx, cy, cz-1) {
+					tex, tint := face(1)
+					emitQuad(&vertices, x0, y0, z0, x0, y1, z0, x1, y1, z0, x1, y0, z0, 1, tex, tint, false, fullLight, [4]uint8{})
+				}
+				if open(cx+1, cy, cz) {
+					tex, tint := face(2)
+					emitQuad(&vertices, x1, y0, z0, x1, y1, z0, x1, y1, z1, x1, y0, z1, 2, tex, tint, false, fullLight, [4]uint8{})
+				}
+				if open(cx-1, cy, cz) {
+					tex, tint := face(3)
+					emitQuad(&vertices, x0, y0, z0, x0, y0, z1, x0, y1, z1, x0, y1, z0, 3, tex, tint, false, fullLight, [4]uint8{})
+				}
+				if open(cx, cy+1, cz) {
+					tex, tint := face(4)
+					emitQuad(&vertices, x0, y1, z0, x0, y1, z1, x1, y1, z1, x1, y1, z0, 4, tex, tint, false, fullLight, [4]uint8{})
+				}
+				if open(cx, cy-1, cz) {
+					tex, tint := face(5)
+					emitQuad(&vertices, x0, y0, z0, x1, y0, z0, x1, y0, z1, x0, y0, z1, 5, tex, tint, false, fullLight, [4]uint8{})
+				}
+				tex, tint := face(2)
+				*col = LODColumn{Top: y1, TexID: tex, Tint: tint}
+			}
+		}
+	}
+	mesh.Vertices = vertices
+	return mesh
+}
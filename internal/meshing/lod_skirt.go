@@ -0,0 +1,69 @@
+package meshing
+
+import "mini-mc/internal/world"
+
+// Borders between chunks meshed at different levels of detail do not line
+// up: a coarse cell's top sits up to step-1 blocks away from the finer
+// surface next to it, leaving cracks the sky shows through. Skirts hide them
+// by hanging a vertical strip below each border cell's top on the chunk edge,
+// deep enough to reach the neighbour's surface at any coarser level.
+
+// SkirtBorders selects the chunk edges that face a neighbour at a different
+// level of detail.
+type SkirtBorders uint8
+
+const (
+	SkirtNorth SkirtBorders = 1 << iota // +Z edge
+	SkirtSouth                          // -Z edge
+	SkirtEast                           // +X edge
+	SkirtWest                           // -X edge
+)
+
+// LODColumn is the top surface of one coarse cell column of a chunk.
+type LODColumn struct {
+	Top   int // local Y just above the highest block; 0 for an empty column
+	TexID int // side texture shown on the skirt
+	Tint  uint16
+}
+
+// AppendLODSkirts appends skirts for the selected borders of a chunk meshed
+// at step blocks per cell. cols holds (16/step)² columns indexed x*n+z. Each
+// skirt spans the cell's width and drops depth blocks below its top; depth
+// should be at least the largest step used by neighbouring chunks.
+func AppendLODSkirts(vertices *[]uint32, cols []LODColumn, step, depth int, borders SkirtBorders) {
+	n := world.ChunkSizeX / step
+	for i := range n {
+		a, b := i*step, (i+1)*step
+		if borders&SkirtEast != 0 {
+			if top, bottom, col, ok := skirtSpan(cols[(n-1)*n+i], depth); ok {
+				x := world.ChunkSizeX
+				emitQuad(vertices, x, bottom, a, x, top, a, x, top, b, x, bottom, b, 2, col.TexID, col.Tint, false, fullLight, [4]uint8{})
+			}
+		}
+		if borders&SkirtWest != 0 {
+			if top, bottom, col, ok := skirtSpan(cols[i], depth); ok {
+				emitQuad(vertices, 0, bottom, a, 0, bottom, b, 0, top, b, 0, top, a, 3, col.TexID, col.Tint, false, fullLight, [4]uint8{})
+			}
+		}
+		if borders&SkirtNorth != 0 {
+			if top, bottom, col, ok := skirtSpan(cols[i*n+n-1], depth); ok {
+				z := world.ChunkSizeZ
+				emitQuad(vertices, a, bottom, z, b, bottom, z, b, top, z, a, top, z, 0, col.TexID, col.Tint, false, fullLight, [4]uint8{})
+			}
+		}
+		if borders&SkirtSouth != 0 {
+			if top, bottom, col, ok := skirtSpan(cols[i*n], depth); ok {
+				emitQuad(vertices, a, bottom, 0, a, top, 0, b, top, 0, b, bottom, 0, 1, col.TexID, col.Tint, false, fullLight, [4]uint8{})
+			}
+		}
+	}
+}
+
+// skirtSpan returns the vertical extent of a column's skirt; ok is false for
+// empty columns, which have no surface to extend.
+func skirtSpan(col LODColumn, depth int) (top, bottom int, c LODColumn, ok bool) {
+	if col.Top <= 0 {
+		return 0, 0, col, false
+	}
+	return col.Top, max(col.Top-depth, 0), col, true
+}
@@ -0,0 +1,35 @@
+package meshing
+
+import "testing"
+
+func TestAppendLODSkirtsCoversSelectedBorders(t *testing.T) {
+	const step, n = 4, 4
+	cols := make([]LODColumn, n*n)
+	for i := range cols {
+		cols[i] = LODColumn{Top: 64, TexID: 7, Tint: 0xFFFF}
+	}
+	cols[0].Top = 0 // empty corner column: no skirt on either of its edges
+
+	var verts []uint32
+	AppendLODSkirts(&verts, cols, step, step, SkirtEast|SkirtSouth)
+
+	// 4 east quads, 3 south quads (the empty corner is skipped), 4 vertices
+	// of 2 words each
+	if got, want := len(verts), 7*QuadVertices*VertexStride; got != want {
+		t.Fatalf("len = %d, want %d", got, want)
+	}
+	eastX, minY, maxY := 0, 1<<9, 0
+	for i := 0; i < len(verts); i += 2 {
+		x, y := int(verts[i]&31), int(verts[i]>>5&511)
+		minY, maxY = min(minY, y), max(maxY, y)
+		if verts[i]>>19&7 == 2 {
+			eastX = max(eastX, x)
+			if x != 16 {
+				t.Fatalf("east skirt vertex at x=%d, want the chunk edge 16", x)
+			}
+		}
+	}
+	if eastX != 16 || minY != 60 || maxY != 64 {
+		t.Fatalf("skirts span x=%d y=%d..%d, want x=16 y=60..64", eastX, minY, maxY)
+	}
+}
@@ -0,0 +1,54 @@
+package meshing
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+)
+
+func TestLODMeshOfFlatGround(t *testing.T) {
+	w := world.New()
+	defer w.Close()
+	c := w.GetChunk(0, 0, 0, true)
+	for x := range world.ChunkSizeX {
+		for z := range world.ChunkSizeZ {
+			for y := range 64 {
+				c.SetBlock(x, y, z, world.BlockTypeStone)
+			}
+		}
+	}
+	// A lone block fills too little of its cell to show
+	c.SetBlock(5, 64, 5, world.BlockTypeStone)
+	// An empty chunk to the east opens that side
+	w.GetChunk(1, 0, 0, true)
+
+	const step = 4
+	m := BuildLODMesh(w, c, step)
+	n := world.ChunkSizeX / step
+	tops, east := 0, 0
+	for i := 0; i < len(m.Vertices); i += QuadVertices * VertexStride {
+		v := m.Vertices[i]
+		switch v >> 19 & 7 {
+		case 4:
+			tops++
+			if y := int(v >> 5 & 511); y != 64 {
+				t.Fatalf("top face at y=%d, want 64", y)
+			}
+		case 2:
+			east++
+			if x := int(v & 31); x != world.ChunkSizeX {
+				t.Fatalf("east face at x=%d, want the chunk edge", x)
+			}
+		default:
+			t.Fatalf("unexpected face with normal %d", v>>19&7)
+		}
+	}
+	if tops != n*n || east != n*64/step {
+		t.Fatalf("%d top and %d east faces, want %d and %d", tops, east, n*n, n*64/step)
+	}
+	for i, col := range m.Columns {
+		if col.Top != 64 {
+			t.Fatalf("column %d top = %d, want 64", i, col.Top)
+		}
+	}
+}
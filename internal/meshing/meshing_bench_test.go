@@ -32,7 +32,7 @@ func BenchmarkGreedyDirection(b *testing.B) {
 		b.ResetTimer()
 
 		for b.Loop() {
-			verts := buildGreedyForDirection(w, c, 1, 0, 0, nil)
+			verts := buildGreedyForDirection(w, c, 1, 0, 0, nil, nil)
 			lastVertCount = len(verts) / 2
 		}
 
@@ -52,7 +52,7 @@ func BenchmarkGreedyDirection(b *testing.B) {
 		b.ResetTimer()
 
 		for b.Loop() {
-			verts := buildGreedyForDirection(w, c, 0, 1, 0, nil)
+			verts := buildGreedyForDirection(w, c, 0, 1, 0, nil, nil)
 			lastVertCount = len(verts) / 2
 		}
 
@@ -80,7 +80,7 @@ func BenchmarkFluidMesh(b *testing.B) {
 
 		for b.Loop() {
 			verts := BuildFluidMesh(w, c)
-			lastVertCount = len(verts) / 10
+			lastVertCount = len(verts) / FluidVertexFloats
 		}
 
 		b.ReportMetric(float64(lastVertCount), "vertices/op")
@@ -103,7 +103,7 @@ func BenchmarkFluidMesh(b *testing.B) {
 
 		for b.Loop() {
 			verts := BuildFluidMesh(w, c)
-			lastVertCount = len(verts) / 10
+			lastVertCount = len(verts) / FluidVertexFloats
 		}
 
 		b.ReportMetric(float64(lastVertCount), "vertices/op")
@@ -130,7 +130,7 @@ func BenchmarkFluidMesh(b *testing.B) {
 
 		for b.Loop() {
 			verts := BuildFluidMesh(w, c)
-			lastVertCount = len(verts) / 10
+			lastVertCount = len(verts) / FluidVertexFloats
 		}
 
 		b.ReportMetric(float64(lastVertCount), "vertices/op")
@@ -0,0 +1,40 @@
+package meshing
+
+import (
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+)
+
+// meshPlant emits the plant at local (x, y, z) as two quads crossing
+// diagonally through the block, each both ways round so they show from
+// either side. They take the normal of a south face, whose texture runs along
+// X and up Y, which both diagonals span. Only the top corners sway, so the
+// plant stays rooted.
+func meshPlant(vertices *[]uint32, w *world.World, c *world.Chunk, x, y, z int, def *registry.BlockDefinition) {
+	texID := 0
+	if idx, ok := registry.TextureMap[def.TextureSide]; ok {
+		texID = idx
+	}
+	tint := uint16(0xFFFF)
+	if def.TintColor != 0 {
+		tint = registry.PackRGB565(registry.BiomeColor(def, w.BiomeColors(c), x, z))
+	}
+	light := c.Light(x, y, z)
+
+	diagonals := [2][2][2]int{
+		{{x, z}, {x + 1, z + 1}},
+		{{x, z + 1}, {x + 1, z}},
+	}
+	for _, d := range diagonals {
+		a, b := d[0], d[1]
+		var q [4][2]uint32
+		q[0][0], q[0][1] = packVertex(a[0], y, a[1], 0, texID, light, tint)
+		q[1][0], q[1][1] = packVertex(b[0], y, b[1], 0, texID, light, tint)
+		q[2][0], q[2][1] = packVertex(b[0], y+1, b[1], 0, texID, light, tint)
+		q[3][0], q[3][1] = packVertex(a[0], y+1, a[1], 0, texID, light, tint)
+		q[2][0] |= foliageBit
+		q[3][0] |= foliageBit
+		appendQuad(vertices, q)
+		appendQuad(vertices, [4][2]uint32{q[0], q[3], q[2], q[1]})
+	}
+}
@@ -1,6 +1,7 @@
 package meshing
 
 import (
+	"container/heap"
 	"context"
 	"mini-mc/internal/world"
 	"sync"
@@ -8,34 +9,85 @@ import (
 
 // MeshJob represents a meshing job request
 type MeshJob struct {
-	World           *world.World
-	Chunk           *world.Chunk
+	World           *world.World // snapshot the chunk is meshed from (see world.SnapshotAround)
+	Chunk           *world.Chunk // the live chunk, echoed in the result
 	Coord           world.ChunkCoord
 	ResultChan      chan MeshResult
-	ChunkGeneration uint64 // snapshot of chunk.Generation() at submission time
+	ChunkGeneration uint64  // snapshot of chunk.Generation() at submission time
+	LOD             bool    // also build the chunk's LOD meshes (see lod.go)
+	Priority        float64 // queued jobs run lowest first, e.g. by distance to the player
 }
 
 // MeshResult contains the result of a meshing operation
 type MeshResult struct {
-	Coord           world.ChunkCoord
-	Chunk           *world.Chunk // The chunk that was meshed; used to call SetClean after applying
-	Vertices        []uint32     // Packed vertices
-	FluidVertices   []float32    // Fluid vertices (custom format)
-	Error           error
-	ChunkGeneration uint64 // echoed from the job; compared against chunk.Generation() in applyMeshResult
+	Coord            world.ChunkCoord
+	Chunk            *world.Chunk // The chunk that was meshed; used to call SetClean after applying
+	Vertices         []uint32     // Packed vertices
+	Translucent      []uint32     // Packed vertices of translucent blocks (glass), blended
+	FluidVertices    []float32    // Fluid vertices (custom format)
+	EnclosedSections int          // sections skipped because no face could be visible
+	LOD              []LODMesh    // one per LODSteps when the job asked for them
+	LODBuilt         bool         // echoed from the job's LOD flag
+	Error            error
+	ChunkGeneration  uint64 // echoed from the job; compared against chunk.Generation() in applyMeshResult
+}
+
+// queuedJob is a job waiting in a WorkerPool.
+type queuedJob struct {
+	job    MeshJob
+	urgent bool   // submitted with SubmitPriorityJob
+	seq    uint64 // submission order, to keep equal priorities first come first served
+}
+
+// jobHeap orders queued jobs: urgent ones first, then by ascending Priority.
+type jobHeap []queuedJob
+
+func (h jobHeap) Len() int { return len(h) }
+
+func (h jobHeap) Less(i, j int) bool {
+	if h[i].urgent != h[j].urgent {
+		return h[i].urgent
+	}
+	if h[i].job.Priority != h[j].job.Priority {
+		return h[i].job.Priority < h[j].job.Priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h jobHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *jobHeap) Push(x any) { *h = append(*h, x.(queuedJob)) }
+
+func (h *jobHeap) Pop() any {
+	old := *h
+	n := len(old)
+	j := old[n-1]
+	old[n-1] = queuedJob{} // drop the job's snapshot
+	*h = old[:n-1]
+	return j
 }
 
 // WorkerPool manages goroutines for mesh generation
 type WorkerPool struct {
-	jobQueue         chan MeshJob
-	priorityJobQueue chan MeshJob // checked before jobQueue; for player-interaction updates
-	workers          int
-	ctx              context.Context
-	cancel           context.CancelFunc
-	wg               sync.WaitGroup
-	directionPool    *DirectionWorkerPool
+	mu       sync.Mutex
+	ready    *sync.Cond // signalled when a job is queued, room frees up or the pool shuts down
+	queue    jobHeap
+	queued   int // non-urgent jobs in queue
+	urgent   int // urgent jobs in queue
+	seq      uint64
+	closed   bool
+	capacity int // most non-urgent jobs queued at once
+
+	workers       int
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+	directionPool *DirectionWorkerPool
 }
 
+// urgentCapacity is the most urgent jobs queued at once
+const urgentCapacity = 64
+
 // NewWorkerPool creates a new mesh worker pool
 func NewWorkerPool(workers int, queueSize int) *WorkerPool {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -50,13 +102,13 @@ func NewWorkerPool(workers int, queueSize int) *WorkerPool {
 	directionPool.Start()
 
 	pool := &WorkerPool{
-		jobQueue:         make(chan MeshJob, queueSize),
-		priorityJobQueue: make(chan MeshJob, 64),
-		workers:          workers,
-		ctx:              ctx,
-		cancel:           cancel,
-		directionPool:    directionPool,
+		capacity:      queueSize,
+		workers:       workers,
+		ctx:           ctx,
+		cancel:        cancel,
+		directionPool: directionPool,
 	}
+	pool.ready = sync.NewCond(&pool.mu)
 
 	// Start worker goroutines
 	for i := range workers {
@@ -67,48 +119,98 @@ func NewWorkerPool(workers int, queueSize int) *WorkerPool {
 	return pool
 }
 
-// SubmitJob submits a mesh generation job to the normal (low-priority) queue.
-// Returns true if the job was accepted, false if the queue is full.
-func (p *WorkerPool) SubmitJob(job MeshJob) bool {
-	select {
-	case p.jobQueue <- job:
-		return true
-	default:
+// push queues job if there is room for it. Hold p.mu.
+func (p *WorkerPool) push(job MeshJob, urgent bool) bool {
+	if p.closed {
 		return false
 	}
+	if urgent {
+		if p.urgent >= urgentCapacity {
+			return false
+		}
+		p.urgent++
+	} else {
+		if p.queued >= p.capacity {
+			return false
+		}
+		p.queued++
+	}
+	p.seq++
+	heap.Push(&p.queue, queuedJob{job: job, urgent: urgent, seq: p.seq})
+	p.ready.Broadcast()
+	return true
 }
 
-// SubmitPriorityJob submits a job to the high-priority queue (checked before normal jobs).
-// Use this for player-interaction updates so they are not delayed by initial-load backlog.
-// Returns true if accepted, false if the priority queue is full.
+// SubmitJob queues a mesh generation job behind any urgent ones, ordered by
+// its Priority. Returns true if the job was accepted, false if the queue is
+// full.
+func (p *WorkerPool) SubmitJob(job MeshJob) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.push(job, false)
+}
+
+// SubmitPriorityJob queues a job ahead of every job submitted with
+// SubmitJob. Use this for player-interaction updates so they are not delayed
+// by initial-load backlog. Returns true if accepted, false if the urgent
+// jobs are at capacity.
 func (p *WorkerPool) SubmitPriorityJob(job MeshJob) bool {
-	select {
-	case p.priorityJobQueue <- job:
-		return true
-	default:
-		return false
-	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.push(job, true)
 }
 
 // SubmitJobBlocking submits a job and blocks until it's queued
 func (p *WorkerPool) SubmitJobBlocking(job MeshJob) {
-	select {
-	case p.jobQueue <- job:
-	case <-p.ctx.Done():
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for !p.closed && !p.push(job, false) {
+		p.ready.Wait()
 	}
 }
 
+// next waits for the most urgent queued job; ok is false once the pool
+// shuts down.
+func (p *WorkerPool) next() (job MeshJob, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.queue) == 0 && !p.closed {
+		p.ready.Wait()
+	}
+	if p.closed {
+		return MeshJob{}, false
+	}
+	q := heap.Pop(&p.queue).(queuedJob)
+	if q.urgent {
+		p.urgent--
+	} else {
+		p.queued--
+	}
+	p.ready.Broadcast() // room for blocked submitters
+	return q.job, true
+}
+
 // processJob executes a single mesh job and sends the result.
 func (p *WorkerPool) processJob(job MeshJob) {
-	vertices := BuildGreedyMeshForChunk(job.World, job.Chunk, p.directionPool)
-	fluidVertices := BuildFluidMesh(job.World, job.Chunk)
+	c := job.World.GetChunk(job.Coord.X, job.Coord.Y, job.Coord.Z, false)
+	if c == nil {
+		c = job.Chunk
+	}
+	vertices, translucent, enclosed := buildGreedyMesh(job.World, c, p.directionPool)
+	fluidVertices := BuildFluidMesh(job.World, c)
 
 	result := MeshResult{
-		Coord:           job.Coord,
-		Chunk:           job.Chunk,
-		Vertices:        vertices,
-		FluidVertices:   fluidVertices,
-		ChunkGeneration: job.ChunkGeneration,
+		Coord:            job.Coord,
+		Chunk:            job.Chunk,
+		Vertices:         vertices,
+		Translucent:      translucent,
+		FluidVertices:    fluidVertices,
+		EnclosedSections: enclosed,
+		ChunkGeneration:  job.ChunkGeneration,
+		LODBuilt:         job.LOD,
+	}
+	if job.LOD {
+		result.LOD = BuildLODMeshes(job.World, c)
 	}
 
 	select {
@@ -117,41 +219,34 @@ func (p *WorkerPool) processJob(job MeshJob) {
 	}
 }
 
-// worker is the worker goroutine that processes mesh jobs.
-// Priority queue is always drained first so player-interaction jobs
-// are not delayed by the initial-load backlog in the normal queue.
+// worker is the worker goroutine that processes mesh jobs, most urgent
+// first.
 func (p *WorkerPool) worker(id int) {
 	defer p.wg.Done()
 
 	for {
-		// Non-blocking drain of priority queue first
-		select {
-		case job := <-p.priorityJobQueue:
-			p.processJob(job)
-			continue
-		default:
-		}
-
-		// Block waiting on either queue; priority still wins
-		select {
-		case job := <-p.priorityJobQueue:
-			p.processJob(job)
-		case job := <-p.jobQueue:
-			p.processJob(job)
-		case <-p.ctx.Done():
+		job, ok := p.next()
+		if !ok {
 			return
 		}
+		p.processJob(job)
 	}
 }
 
 // Shutdown gracefully shuts down the worker pool
 func (p *WorkerPool) Shutdown() {
 	p.cancel()
-	close(p.jobQueue)
+	p.mu.Lock()
+	p.closed = true
+	p.queue = nil
+	p.ready.Broadcast()
+	p.mu.Unlock()
 	p.wg.Wait()
 }
 
 // GetQueueLength returns the current number of jobs in the queue
 func (p *WorkerPool) GetQueueLength() int {
-	return len(p.jobQueue)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.queue)
 }
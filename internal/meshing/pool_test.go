@@ -0,0 +1,40 @@
+package meshing
+
+import (
+	"slices"
+	"testing"
+
+	"mini-mc/internal/world"
+)
+
+func TestWorkerPoolRunsUrgentThenNearestJobs(t *testing.T) {
+	p := NewWorkerPool(0, 3) // no workers: jobs stay queued for next
+	defer p.Shutdown()
+
+	for i, prio := range []float64{5, 1, 3} {
+		if !p.SubmitJob(MeshJob{Coord: world.ChunkCoord{X: i}, Priority: prio}) {
+			t.Fatalf("job %d refused", i)
+		}
+	}
+	if p.SubmitJob(MeshJob{Priority: 0}) {
+		t.Fatal("job accepted past the queue size")
+	}
+	if !p.SubmitPriorityJob(MeshJob{Coord: world.ChunkCoord{X: 9}, Priority: 100}) {
+		t.Fatal("urgent job refused")
+	}
+
+	var order []int
+	for range 4 {
+		job, ok := p.next()
+		if !ok {
+			t.Fatal("pool closed")
+		}
+		order = append(order, job.Coord.X)
+	}
+	if want := []int{9, 1, 2, 0}; !slices.Equal(order, want) {
+		t.Fatalf("jobs ran in order %v, want %v", order, want)
+	}
+	if !p.SubmitJob(MeshJob{}) {
+		t.Error("no room after the queue drained")
+	}
+}
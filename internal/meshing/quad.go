@@ -0,0 +1,35 @@
+package meshing
+
+// Block meshes are lists of quads, four packed vertices each. A quad is drawn
+// as the triangles 0,1,2 and 2,3,0 of its vertices, so every quad takes the
+// same six indices offset by its first vertex and one index buffer serves all
+// of them (see AppendQuadIndices). That stores four vertices per quad rather
+// than the six of two separate triangles.
+const (
+	QuadVertices = 4 // vertices per quad
+	QuadIndices  = 6 // indices per quad, two triangles
+)
+
+// quadTriangles are the indices of a quad's two triangles into its vertices
+var quadTriangles = [QuadIndices]uint32{0, 1, 2, 2, 3, 0}
+
+// AppendQuadIndices appends the indices drawing quads consecutive quads to
+// dst, counting vertices from the first quad's.
+func AppendQuadIndices(dst []uint32, quads int) []uint32 {
+	for q := range quads {
+		base := uint32(q * QuadVertices)
+		for _, i := range quadTriangles {
+			dst = append(dst, base+i)
+		}
+	}
+	return dst
+}
+
+// appendQuad appends the quad of packed vertices q, drawn as the triangles
+// q0,q1,q2 and q2,q3,q0.
+func appendQuad(vertices *[]uint32, q [QuadVertices][VertexStride]uint32) {
+	*vertices = append(*vertices,
+		q[0][0], q[0][1], q[1][0], q[1][1],
+		q[2][0], q[2][1], q[3][0], q[3][1],
+	)
+}
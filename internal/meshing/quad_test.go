@@ -0,0 +1,14 @@
+package meshing
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAppendQuadIndices(t *testing.T) {
+	got := AppendQuadIndices(nil, 2)
+	want := []uint32{0, 1, 2, 2, 3, 0, 4, 5, 6, 6, 7, 4}
+	if !slices.Equal(got, want) {
+		t.Fatalf("indices = %v, want %v", got, want)
+	}
+}
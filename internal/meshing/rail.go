@@ -0,0 +1,58 @@
+package meshing
+
+import (
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+)
+
+// railDrop lowers flat track to 1/16 above the block's bottom.
+const railDrop = 15
+
+// meshRail emits the track quad for the rail at local (x, y, z), flat or
+// sloped to the next block up depending on its shape. It is emitted both ways
+// up so it stays visible from below.
+func meshRail(vertices *[]uint32, c *world.Chunk, x, y, z int) {
+	shape := world.RailShape(c.GetMeta(x, y, z))
+	if shape >= world.NumRailShapes {
+		shape = world.RailNorthSouth
+	}
+	texID := 0
+	if idx, ok := registry.TextureMap[registry.RailTextures[shape]]; ok {
+		texID = idx
+	}
+
+	x0, z0 := x, z
+	x1, z1 := x+1, z+1
+	corners := [4][2]int{{x0, z0}, {x0, z1}, {x1, z1}, {x1, z0}}
+
+	// raised reports whether a corner sits on the high end of a slope
+	raised := func(cx, cz int) bool {
+		if !shape.IsAscending() {
+			return false
+		}
+		_, high := shape.Exits()
+		switch high {
+		case world.FaceNorth:
+			return cz == z1
+		case world.FaceSouth:
+			return cz == z0
+		case world.FaceEast:
+			return cx == x1
+		default:
+			return cx == x0
+		}
+	}
+
+	light := c.Light(x, y, z)
+	var top, bottom [4][2]uint32
+	for i, corner := range corners {
+		drop := railDrop
+		if raised(corner[0], corner[1]) {
+			drop = 0
+		}
+		top[i][0], top[i][1] = packVertexLowered(corner[0], y+1, corner[1], drop, 4, texID, light, 0xFFFF)
+		bottom[3-i][0], bottom[3-i][1] = packVertexLowered(corner[0], y+1, corner[1], drop, 5, texID, light, 0xFFFF)
+	}
+	appendQuad(vertices, top)
+	appendQuad(vertices, bottom)
+}
@@ -0,0 +1,87 @@
+package meshing
+
+import (
+	"sort"
+	"sync"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// RemeshQueue collects chunks whose meshes went stale without a block change
+// in the chunk itself, such as a torch relighting every chunk around it.
+// Invalidating a chunk that is already queued is a no-op, so a burst of
+// updates within a frame costs one rebuild per chunk.
+type RemeshQueue struct {
+	mu      sync.Mutex
+	pending map[world.ChunkCoord]struct{}
+	scratch []remeshCandidate
+}
+
+type remeshCandidate struct {
+	coord   world.ChunkCoord
+	visible bool
+	dist2   float32
+}
+
+// NewRemeshQueue creates an empty queue.
+func NewRemeshQueue() *RemeshQueue {
+	return &RemeshQueue{pending: make(map[world.ChunkCoord]struct{})}
+}
+
+// Invalidate queues chunks for a rebuild. Safe to call from any goroutine.
+func (q *RemeshQueue) Invalidate(coords ...world.ChunkCoord) {
+	q.mu.Lock()
+	for _, c := range coords {
+		q.pending[c] = struct{}{}
+	}
+	q.mu.Unlock()
+}
+
+// Len returns the number of queued chunks.
+func (q *RemeshQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// Drain removes up to limit chunks from the queue, most urgent first, and
+// appends them to dst. Chunks for which visible reports true come before the
+// rest; within each group nearer chunk centres come first. The caller should
+// Invalidate again any chunk it could not rebuild.
+func (q *RemeshQueue) Drain(eye mgl32.Vec3, visible func(world.ChunkCoord) bool, limit int, dst []world.ChunkCoord) []world.ChunkCoord {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pending) == 0 || limit <= 0 {
+		return dst
+	}
+
+	candidates := q.scratch[:0]
+	for c := range q.pending {
+		centre := mgl32.Vec3{
+			(float32(c.X) + 0.5) * world.ChunkSizeX,
+			(float32(c.Y) + 0.5) * world.ChunkSizeY,
+			(float32(c.Z) + 0.5) * world.ChunkSizeZ,
+		}
+		d := centre.Sub(eye)
+		candidates = append(candidates, remeshCandidate{
+			coord:   c,
+			visible: visible == nil || visible(c),
+			dist2:   d.Dot(d),
+		})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].visible != candidates[j].visible {
+			return candidates[i].visible
+		}
+		return candidates[i].dist2 < candidates[j].dist2
+	})
+
+	for i := 0; i < len(candidates) && i < limit; i++ {
+		dst = append(dst, candidates[i].coord)
+		delete(q.pending, candidates[i].coord)
+	}
+	q.scratch = candidates[:0]
+	return dst
+}
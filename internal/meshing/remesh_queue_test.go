@@ -0,0 +1,37 @@
+package meshing
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestRemeshQueueCoalescesAndPrioritizes(t *testing.T) {
+	q := NewRemeshQueue()
+	near := world.ChunkCoord{X: 0, Y: 0, Z: 0}
+	far := world.ChunkCoord{X: 5, Y: 0, Z: 0}
+	behind := world.ChunkCoord{X: -1, Y: 0, Z: 0}
+
+	// A burst of invalidations touching the same chunks
+	q.Invalidate(far, near, behind)
+	q.Invalidate(near, far)
+	if q.Len() != 3 {
+		t.Fatalf("Len = %d, want 3 after coalescing", q.Len())
+	}
+
+	// Looking towards +X: the chunk behind is off screen despite being close
+	visible := func(c world.ChunkCoord) bool { return c.X >= 0 }
+	got := q.Drain(mgl32.Vec3{8, 64, 8}, visible, 2, nil)
+	if len(got) != 2 || got[0] != near || got[1] != far {
+		t.Fatalf("Drain = %v, want [near far]", got)
+	}
+	if q.Len() != 1 {
+		t.Fatalf("Len = %d after drain, want 1", q.Len())
+	}
+	got = q.Drain(mgl32.Vec3{8, 64, 8}, visible, 2, got[:0])
+	if len(got) != 1 || got[0] != behind {
+		t.Fatalf("second Drain = %v, want [behind]", got)
+	}
+}
@@ -0,0 +1,96 @@
+package meshing
+
+import (
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+)
+
+// meshSnowLayer emits a box covering the bottom layers*2/16 of the block.
+// The top edge is pushed down with the vertex drop field since the packed
+// vertex format only carries whole-block Y coordinates.
+func meshSnowLayer(vertices *[]uint32, w *world.World, c *world.Chunk, x, y, z int, def *registry.BlockDefinition) {
+	layers := world.SnowLayers(c.GetMeta(x, y, z))
+	drop := 16 - layers*16/world.MaxSnowLayers
+
+	texTop, texSide, texBot := 0, 0, 0
+	if idx, ok := registry.TextureMap[def.TextureTop]; ok {
+		texTop = idx
+	}
+	if idx, ok := registry.TextureMap[def.TextureSide]; ok {
+		texSide = idx
+	}
+	if idx, ok := registry.TextureMap[def.TextureBot]; ok {
+		texBot = idx
+	}
+
+	x0, y0, z0 := x, y, z
+	x1, y1, z1 := x+1, y+1, z+1
+
+	// Quad corners in the same winding as meshCustomBlock; the 4th value marks
+	// corners on the top edge that get lowered.
+	type corner struct{ x, y, z, top int }
+	faces := [6]struct {
+		nm         byte
+		dx, dy, dz int
+		tex        int
+		q          [4]corner
+	}{
+		{4, 0, 1, 0, texTop, [4]corner{{x0, y1, z0, 1}, {x0, y1, z1, 1}, {x1, y1, z1, 1}, {x1, y1, z0, 1}}},
+		{5, 0, -1, 0, texBot, [4]corner{{x0, y0, z0, 0}, {x1, y0, z0, 0}, {x1, y0, z1, 0}, {x0, y0, z1, 0}}},
+		{1, 0, 0, -1, texSide, [4]corner{{x1, y0, z0, 0}, {x0, y0, z0, 0}, {x0, y1, z0, 1}, {x1, y1, z0, 1}}},
+		{0, 0, 0, 1, texSide, [4]corner{{x0, y0, z1, 0}, {x1, y0, z1, 0}, {x1, y1, z1, 1}, {x0, y1, z1, 1}}},
+		{3, -1, 0, 0, texSide, [4]corner{{x0, y0, z0, 0}, {x0, y0, z1, 0}, {x0, y1, z1, 1}, {x0, y1, z0, 1}}},
+		{2, 1, 0, 0, texSide, [4]corner{{x1, y0, z1, 0}, {x1, y0, z0, 0}, {x1, y1, z0, 1}, {x1, y1, z1, 1}}},
+	}
+
+	for _, f := range faces {
+		// The top face of a partial layer never touches the block above.
+		partialTop := f.dy == 1 && layers < world.MaxSnowLayers
+		if !partialTop && snowFaceHidden(w, c, x+f.dx, y+f.dy, z+f.dz, layers, f.dy != 0) {
+			continue
+		}
+
+		light := blockFaceLight(w, c, x, y, z, f.dx, f.dy, f.dz)
+
+		var packed [4][2]uint32
+		for i, q := range f.q {
+			d := 0
+			if q.top == 1 {
+				d = drop
+			}
+			packed[i][0], packed[i][1] = packVertexLowered(q.x, q.y, q.z, d, f.nm, f.tex, light, 0xFFFF)
+		}
+		appendQuad(vertices, packed)
+	}
+}
+
+// snowFaceHidden reports whether the neighbor at local (nx, ny, nz) fully covers
+// the adjacent face of a snow layer. Side faces are also hidden by neighboring
+// snow that is at least as tall.
+func snowFaceHidden(w *world.World, c *world.Chunk, nx, ny, nz, layers int, vertical bool) bool {
+	var nbt world.BlockType
+	var meta uint8
+	if nx >= 0 && nx < world.ChunkSizeX && ny >= 0 && ny < world.ChunkSizeY && nz >= 0 && nz < world.ChunkSizeZ {
+		nbt = c.GetBlock(nx, ny, nz)
+		if nbt == world.BlockTypeSnowLayer {
+			meta = c.GetMeta(nx, ny, nz)
+		}
+	} else {
+		wx := c.X*world.ChunkSizeX + nx
+		wy := c.Y*world.ChunkSizeY + ny
+		wz := c.Z*world.ChunkSizeZ + nz
+		nbt = w.Get(wx, wy, wz)
+		if nbt == world.BlockTypeSnowLayer {
+			meta = w.GetMeta(wx, wy, wz)
+		}
+	}
+
+	if nbt == world.BlockTypeAir {
+		return false
+	}
+	if nbt == world.BlockTypeSnowLayer {
+		return !vertical && world.SnowLayers(meta) >= layers
+	}
+	nDef := registry.BlockDefs[nbt]
+	return nDef != nil && nDef.IsSolid && !nDef.IsTransparent
+}
@@ -0,0 +1,164 @@
+package net
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"mini-mc/internal/netsim"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// receiveQueueLength is how many packets may wait for the game loop.
+const receiveQueueLength = 4096
+
+// Client is a connection to a server. Packets arrive on a goroutine of its
+// own and wait for the game loop to take them with Poll; keep-alives are
+// answered there without waiting.
+type Client struct {
+	// From the server's LoginSuccess
+	PlayerID int32
+	Name     string
+	Seed     int64
+	GameMode uint8
+	Spawn    mgl32.Vec3
+	Time     float64
+
+	conn      *conn
+	received  chan Packet
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	mu  sync.Mutex
+	err error // why the connection ended, once received is closed
+}
+
+// Dial connects to the server at addr and logs in as name, asking to be
+// sent chunks viewDistance around the player.
+func Dial(addr, name string, viewDistance int) (*Client, error) {
+	c, err := net.DialTimeout("tcp", addr, loginTimeout)
+	if err != nil {
+		return nil, err
+	}
+	cn := newConn(c)
+	cn.send(&Login{Version: ProtocolVersion, Name: name, ViewDistance: uint8(min(viewDistance, maxViewDistance))})
+
+	cn.c.SetReadDeadline(time.Now().Add(loginTimeout))
+	p, err := cn.read()
+	if err != nil {
+		cn.abort()
+		return nil, err
+	}
+	cn.c.SetReadDeadline(time.Time{})
+	var ok *LoginSuccess
+	switch p := p.(type) {
+	case *LoginSuccess:
+		ok = p
+	case *Disconnect:
+		cn.abort()
+		return nil, errors.New(p.Reason)
+	default:
+		cn.abort()
+		return nil, fmt.Errorf("unexpected %T logging in", p)
+	}
+
+	cl := &Client{
+		PlayerID: ok.PlayerID,
+		Name:     ok.Name,
+		Seed:     ok.Seed,
+		GameMode: ok.GameMode,
+		Spawn:    ok.Spawn,
+		Time:     ok.Time,
+		conn:     cn,
+		received: make(chan Packet, receiveQueueLength),
+		closed:   make(chan struct{}),
+	}
+	go cl.readLoop()
+	return cl, nil
+}
+
+func (cl *Client) readLoop() {
+	defer close(cl.received)
+	for {
+		p, err := cl.conn.read()
+		if err != nil {
+			cl.setErr(err)
+			return
+		}
+		switch p := p.(type) {
+		case *KeepAlive:
+			cl.conn.send(p)
+			continue
+		case *Disconnect:
+			cl.setErr(errors.New(p.Reason))
+			cl.conn.abort()
+			return
+		case *PlayerList:
+			// The server measures the ping; the netgraph shows it too
+			for _, e := range p.Entries {
+				if e.Name == cl.Name && e.Ping >= 0 {
+					cl.Stats().ObserveRTT(e.Ping)
+				}
+			}
+		}
+		select {
+		case cl.received <- p:
+		case <-cl.closed:
+			return
+		}
+	}
+}
+
+// setErr records the first reason the connection ended.
+func (cl *Client) setErr(err error) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if cl.err == nil {
+		cl.err = err
+	}
+}
+
+// Poll returns the packets received since the last call, up to max. ok is
+// false once the connection has ended and everything received was taken;
+// Err then says why.
+func (cl *Client) Poll(max int) (packets []Packet, ok bool) {
+	for len(packets) < max {
+		select {
+		case p, open := <-cl.received:
+			if !open {
+				return packets, false
+			}
+			packets = append(packets, p)
+		default:
+			return packets, true
+		}
+	}
+	return packets, true
+}
+
+// Send queues p for the server.
+func (cl *Client) Send(p Packet) {
+	cl.conn.send(p)
+}
+
+// Err returns why the connection ended, or nil while it is open.
+func (cl *Client) Err() error {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return cl.err
+}
+
+// Stats returns the connection's traffic meter.
+func (cl *Client) Stats() *netsim.Stats {
+	return cl.conn.stats()
+}
+
+// Close sends what is queued and closes the connection.
+func (cl *Client) Close() {
+	cl.setErr(errors.New("disconnected"))
+	cl.closeOnce.Do(func() { close(cl.closed) })
+	cl.conn.close()
+}
@@ -0,0 +1,101 @@
+package net
+
+import (
+	"bufio"
+	"net"
+	"sync"
+
+	"mini-mc/internal/netsim"
+)
+
+// sendQueueLength is how many packets may wait to be written to a
+// connection. A peer that falls this far behind is dropped rather than
+// holding up the game loop.
+const sendQueueLength = 1024
+
+// conn is one end of a connection. Packets are written in order by a
+// goroutine of their own, so sending never blocks the caller.
+type conn struct {
+	c *netsim.Conn
+	r *bufio.Reader
+
+	mu      sync.Mutex
+	out     chan Packet
+	closing bool // out is closed; nothing more is sent
+	done    chan struct{}
+}
+
+func newConn(c net.Conn) *conn {
+	wrapped := netsim.Wrap(c, nil)
+	cn := &conn{
+		c:    wrapped,
+		r:    bufio.NewReader(wrapped),
+		out:  make(chan Packet, sendQueueLength),
+		done: make(chan struct{}),
+	}
+	go cn.writeLoop()
+	return cn
+}
+
+func (cn *conn) writeLoop() {
+	defer close(cn.done)
+	w := bufio.NewWriter(cn.c)
+	for p := range cn.out {
+		if err := WritePacket(w, p); err != nil {
+			break
+		}
+		// Write out when nothing else is waiting, so a burst goes together
+		if len(cn.out) == 0 && w.Flush() != nil {
+			break
+		}
+	}
+	cn.c.Close()
+	for range cn.out {
+	}
+}
+
+// send queues p. It reports false, and closes the connection, if the
+// queue is full.
+func (cn *conn) send(p Packet) bool {
+	cn.mu.Lock()
+	defer cn.mu.Unlock()
+	if cn.closing {
+		return false
+	}
+	select {
+	case cn.out <- p:
+		return true
+	default:
+		cn.closing = true
+		close(cn.out)
+		cn.c.Conn.Close()
+		return false
+	}
+}
+
+// close sends what is queued, then closes the connection. It does not wait
+// for that; the reading end sees the connection end.
+func (cn *conn) close() {
+	cn.mu.Lock()
+	defer cn.mu.Unlock()
+	if !cn.closing {
+		cn.closing = true
+		close(cn.out)
+	}
+}
+
+// abort closes the connection at once, dropping what is queued.
+func (cn *conn) abort() {
+	cn.close()
+	cn.c.Conn.Close()
+}
+
+// read reads the next packet.
+func (cn *conn) read() (Packet, error) {
+	return ReadPacket(cn.r)
+}
+
+// stats returns the connection's traffic meter.
+func (cn *conn) stats() *netsim.Stats {
+	return cn.c.Stats()
+}
@@ -0,0 +1,318 @@
+package net
+
+import (
+	"time"
+
+	"mini-mc/internal/presence"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Packet IDs. New packets go at the end; the IDs are on the wire.
+const (
+	idLogin uint8 = iota + 1
+	idLoginSuccess
+	idDisconnect
+	idKeepAlive
+	idChunkData
+	idUnloadChunk
+	idBlockChange
+	idPlayerPosition
+	idSpawnPlayer
+	idMovePlayer
+	idDestroyPlayer
+	idChat
+	idTimeUpdate
+	idPlayerList
+	idViewDistance
+)
+
+// newPacket returns an empty packet of the type with the given ID, or nil.
+func newPacket(id uint8) Packet {
+	switch id {
+	case idLogin:
+		return &Login{}
+	case idLoginSuccess:
+		return &LoginSuccess{}
+	case idDisconnect:
+		return &Disconnect{}
+	case idKeepAlive:
+		return &KeepAlive{}
+	case idChunkData:
+		return &ChunkData{}
+	case idUnloadChunk:
+		return &UnloadChunk{}
+	case idBlockChange:
+		return &BlockChange{}
+	case idPlayerPosition:
+		return &PlayerPosition{}
+	case idSpawnPlayer:
+		return &SpawnPlayer{}
+	case idMovePlayer:
+		return &MovePlayer{}
+	case idDestroyPlayer:
+		return &DestroyPlayer{}
+	case idChat:
+		return &Chat{}
+	case idTimeUpdate:
+		return &TimeUpdate{}
+	case idPlayerList:
+		return &PlayerList{}
+	case idViewDistance:
+		return &ViewDistance{}
+	}
+	return nil
+}
+
+// Login is the first packet a client sends.
+type Login struct {
+	Version      int32
+	Name         string
+	ViewDistance uint8 // in chunks
+}
+
+// LoginSuccess accepts a login. The client builds its world from Seed and
+// fills it with the chunks the server sends.
+type LoginSuccess struct {
+	PlayerID int32
+	Name     string // the name the player was given, which may differ from the one asked for
+	Seed     int64
+	GameMode uint8
+	Spawn    mgl32.Vec3
+	Time     float64 // ticks, see world.World.Time
+}
+
+// Disconnect is the last packet the server sends a client, saying why.
+type Disconnect struct {
+	Reason string
+}
+
+// KeepAlive is sent by the server every second and echoed by the client;
+// the round trip is the player's ping. A client that stops answering is
+// dropped.
+type KeepAlive struct {
+	Nonce int64 // the server's clock when sent, in nanoseconds
+}
+
+// ChunkData is a chunk column, as world.EncodeChunk writes it.
+type ChunkData struct {
+	X, Z int32
+	Data []byte
+}
+
+// UnloadChunk tells a client to drop a column it was sent.
+type UnloadChunk struct {
+	X, Z int32
+}
+
+// BlockChange is a block set by a player (client to server) or changed in
+// the world (server to client).
+type BlockChange struct {
+	X, Y, Z int32
+	State   world.BlockState
+}
+
+// PlayerPosition is where a client's player is, sent every tick it moves.
+type PlayerPosition struct {
+	Pos        mgl32.Vec3
+	Yaw, Pitch float32
+	OnGround   bool
+}
+
+// SpawnPlayer shows another player to a client.
+type SpawnPlayer struct {
+	PlayerID   int32
+	Name       string
+	Pos        mgl32.Vec3
+	Yaw, Pitch float32
+}
+
+// MovePlayer moves another player a client is shown.
+type MovePlayer struct {
+	PlayerID   int32
+	Pos        mgl32.Vec3
+	Yaw, Pitch float32
+}
+
+// DestroyPlayer removes another player from a client, when they leave.
+type DestroyPlayer struct {
+	PlayerID int32
+}
+
+// Chat is a line of chat: typed by a player (client to server) or shown to
+// everyone (server to client).
+type Chat struct {
+	Text string
+}
+
+// TimeUpdate corrects a client's clock, which runs on between updates.
+type TimeUpdate struct {
+	Time float64
+}
+
+// PlayerList is everyone connected and their ping, for the tab list.
+type PlayerList struct {
+	Entries []presence.Entry
+}
+
+// ViewDistance changes how far around its player a client is sent chunks.
+type ViewDistance struct {
+	Chunks uint8
+}
+
+func (*Login) packetID() uint8 { return idLogin }
+func (p *Login) encode(e *encoder) {
+	e.i32(p.Version)
+	e.string(p.Name)
+	e.u8(p.ViewDistance)
+}
+func (p *Login) decode(d *decoder) {
+	p.Version = d.i32()
+	p.Name = d.string()
+	p.ViewDistance = d.u8()
+}
+
+func (*LoginSuccess) packetID() uint8 { return idLoginSuccess }
+func (p *LoginSuccess) encode(e *encoder) {
+	e.i32(p.PlayerID)
+	e.string(p.Name)
+	e.i64(p.Seed)
+	e.u8(p.GameMode)
+	e.vec3(p.Spawn)
+	e.f64(p.Time)
+}
+func (p *LoginSuccess) decode(d *decoder) {
+	p.PlayerID = d.i32()
+	p.Name = d.string()
+	p.Seed = d.i64()
+	p.GameMode = d.u8()
+	p.Spawn = d.vec3()
+	p.Time = d.f64()
+}
+
+func (*Disconnect) packetID() uint8     { return idDisconnect }
+func (p *Disconnect) encode(e *encoder) { e.string(p.Reason) }
+func (p *Disconnect) decode(d *decoder) { p.Reason = d.string() }
+
+func (*KeepAlive) packetID() uint8     { return idKeepAlive }
+func (p *KeepAlive) encode(e *encoder) { e.i64(p.Nonce) }
+func (p *KeepAlive) decode(d *decoder) { p.Nonce = d.i64() }
+
+func (*ChunkData) packetID() uint8 { return idChunkData }
+func (p *ChunkData) encode(e *encoder) {
+	e.i32(p.X)
+	e.i32(p.Z)
+	e.bytes(p.Data)
+}
+func (p *ChunkData) decode(d *decoder) {
+	p.X = d.i32()
+	p.Z = d.i32()
+	p.Data = d.bytes()
+}
+
+func (*UnloadChunk) packetID() uint8 { return idUnloadChunk }
+func (p *UnloadChunk) encode(e *encoder) {
+	e.i32(p.X)
+	e.i32(p.Z)
+}
+func (p *UnloadChunk) decode(d *decoder) {
+	p.X = d.i32()
+	p.Z = d.i32()
+}
+
+func (*BlockChange) packetID() uint8 { return idBlockChange }
+func (p *BlockChange) encode(e *encoder) {
+	e.i32(p.X)
+	e.i32(p.Y)
+	e.i32(p.Z)
+	e.u8(uint8(p.State.Type))
+	e.u8(p.State.Meta)
+}
+func (p *BlockChange) decode(d *decoder) {
+	p.X = d.i32()
+	p.Y = d.i32()
+	p.Z = d.i32()
+	p.State.Type = world.BlockType(d.u8())
+	p.State.Meta = d.u8()
+}
+
+func (*PlayerPosition) packetID() uint8 { return idPlayerPosition }
+func (p *PlayerPosition) encode(e *encoder) {
+	e.vec3(p.Pos)
+	e.f32(p.Yaw)
+	e.f32(p.Pitch)
+	e.bool(p.OnGround)
+}
+func (p *PlayerPosition) decode(d *decoder) {
+	p.Pos = d.vec3()
+	p.Yaw = d.f32()
+	p.Pitch = d.f32()
+	p.OnGround = d.bool()
+}
+
+func (*SpawnPlayer) packetID() uint8 { return idSpawnPlayer }
+func (p *SpawnPlayer) encode(e *encoder) {
+	e.i32(p.PlayerID)
+	e.string(p.Name)
+	e.vec3(p.Pos)
+	e.f32(p.Yaw)
+	e.f32(p.Pitch)
+}
+func (p *SpawnPlayer) decode(d *decoder) {
+	p.PlayerID = d.i32()
+	p.Name = d.string()
+	p.Pos = d.vec3()
+	p.Yaw = d.f32()
+	p.Pitch = d.f32()
+}
+
+func (*MovePlayer) packetID() uint8 { return idMovePlayer }
+func (p *MovePlayer) encode(e *encoder) {
+	e.i32(p.PlayerID)
+	e.vec3(p.Pos)
+	e.f32(p.Yaw)
+	e.f32(p.Pitch)
+}
+func (p *MovePlayer) decode(d *decoder) {
+	p.PlayerID = d.i32()
+	p.Pos = d.vec3()
+	p.Yaw = d.f32()
+	p.Pitch = d.f32()
+}
+
+func (*DestroyPlayer) packetID() uint8     { return idDestroyPlayer }
+func (p *DestroyPlayer) encode(e *encoder) { e.i32(p.PlayerID) }
+func (p *DestroyPlayer) decode(d *decoder) { p.PlayerID = d.i32() }
+
+func (*Chat) packetID() uint8     { return idChat }
+func (p *Chat) encode(e *encoder) { e.string(p.Text) }
+func (p *Chat) decode(d *decoder) { p.Text = d.string() }
+
+func (*TimeUpdate) packetID() uint8     { return idTimeUpdate }
+func (p *TimeUpdate) encode(e *encoder) { e.f64(p.Time) }
+func (p *TimeUpdate) decode(d *decoder) { p.Time = d.f64() }
+
+func (*PlayerList) packetID() uint8 { return idPlayerList }
+func (p *PlayerList) encode(e *encoder) {
+	e.i32(int32(len(p.Entries)))
+	for _, entry := range p.Entries {
+		e.string(entry.Name)
+		e.i64(int64(entry.Ping))
+	}
+}
+func (p *PlayerList) decode(d *decoder) {
+	n := d.i32()
+	if n < 0 || int(n) > len(d.buf) {
+		d.err = errPacket
+		return
+	}
+	p.Entries = make([]presence.Entry, n)
+	for i := range p.Entries {
+		p.Entries[i] = presence.Entry{Name: d.string(), Ping: time.Duration(d.i64())}
+	}
+}
+
+func (*ViewDistance) packetID() uint8     { return idViewDistance }
+func (p *ViewDistance) encode(e *encoder) { e.u8(p.Chunks) }
+func (p *ViewDistance) decode(d *decoder) { p.Chunks = d.u8() }
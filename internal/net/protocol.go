@@ -0,0 +1,170 @@
+// Package net is the multiplayer protocol: the packets a server and its
+// players exchange, how they are framed on a TCP connection, and the
+// server and client ends built on them.
+//
+// Each packet is a big-endian uint32 length followed by that many bytes: a
+// packet ID byte and the packet's fields, big-endian, with strings and byte
+// slices prefixed by a uint32 length.
+package net
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// ProtocolVersion is sent on login; a server refuses clients speaking
+// another.
+const ProtocolVersion = 1
+
+// maxPacketSize is the longest packet read. The largest is a compressed
+// chunk, which is far smaller.
+const maxPacketSize = 4 << 20
+
+var errPacket = errors.New("malformed packet")
+
+// Packet is a message on a connection. The packet types are the structs in
+// packets.go.
+type Packet interface {
+	packetID() uint8
+	encode(e *encoder)
+	decode(d *decoder)
+}
+
+// WritePacket writes p as one frame.
+func WritePacket(w io.Writer, p Packet) error {
+	e := &encoder{buf: make([]byte, 5, 64)}
+	e.buf[4] = p.packetID()
+	p.encode(e)
+	binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+	_, err := w.Write(e.buf)
+	return err
+}
+
+// ReadPacket reads one frame and returns its packet.
+func ReadPacket(r *bufio.Reader) (Packet, error) {
+	var size uint32
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	if size < 1 || size > maxPacketSize {
+		return nil, errPacket
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	p := newPacket(buf[0])
+	if p == nil {
+		return nil, fmt.Errorf("unknown packet ID %d", buf[0])
+	}
+	d := &decoder{buf: buf[1:]}
+	p.decode(d)
+	if d.err != nil || len(d.buf) != 0 {
+		return nil, fmt.Errorf("packet %T: %w", p, errPacket)
+	}
+	return p, nil
+}
+
+// encoder appends packet fields to buf.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) u8(v uint8)    { e.buf = append(e.buf, v) }
+func (e *encoder) i32(v int32)   { e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v)) }
+func (e *encoder) i64(v int64)   { e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v)) }
+func (e *encoder) f32(v float32) { e.buf = binary.BigEndian.AppendUint32(e.buf, math.Float32bits(v)) }
+func (e *encoder) f64(v float64) { e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(v)) }
+
+func (e *encoder) bool(v bool) {
+	if v {
+		e.u8(1)
+	} else {
+		e.u8(0)
+	}
+}
+
+func (e *encoder) vec3(v mgl32.Vec3) {
+	e.f32(v[0])
+	e.f32(v[1])
+	e.f32(v[2])
+}
+
+func (e *encoder) bytes(b []byte) {
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *encoder) string(s string) {
+	e.bytes([]byte(s))
+}
+
+// decoder reads packet fields from buf. The first short read sets err and
+// every later field reads as zero.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) take(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(d.buf) {
+		d.err = errPacket
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *decoder) u8() uint8 {
+	if b := d.take(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (d *decoder) u32() uint32 {
+	if b := d.take(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (d *decoder) u64() uint64 {
+	if b := d.take(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (d *decoder) i32() int32   { return int32(d.u32()) }
+func (d *decoder) i64() int64   { return int64(d.u64()) }
+func (d *decoder) f32() float32 { return math.Float32frombits(d.u32()) }
+func (d *decoder) f64() float64 { return math.Float64frombits(d.u64()) }
+func (d *decoder) bool() bool   { return d.u8() != 0 }
+
+func (d *decoder) vec3() mgl32.Vec3 {
+	return mgl32.Vec3{d.f32(), d.f32(), d.f32()}
+}
+
+func (d *decoder) bytes() []byte {
+	n := d.u32()
+	if n > uint32(len(d.buf)) {
+		d.err = errPacket
+		return nil
+	}
+	return append([]byte(nil), d.take(int(n))...)
+}
+
+func (d *decoder) string() string {
+	return string(d.bytes())
+}
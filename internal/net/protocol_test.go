@@ -0,0 +1,54 @@
+package net
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"mini-mc/internal/presence"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestPacketsRoundTrip(t *testing.T) {
+	packets := []Packet{
+		&Login{Version: ProtocolVersion, Name: "Alex", ViewDistance: 8},
+		&LoginSuccess{PlayerID: 3, Name: "Alex2", Seed: -42, GameMode: 1, Spawn: mgl32.Vec3{0.5, 70, -3.5}, Time: 6000},
+		&ChunkData{X: -2, Z: 7, Data: []byte{1, 2, 3}},
+		&BlockChange{X: -1, Y: 64, Z: 9, State: world.BlockState{Type: world.BlockTypeRail, Meta: 2}},
+		&PlayerPosition{Pos: mgl32.Vec3{1, 2, 3}, Yaw: -90, Pitch: 12.5, OnGround: true},
+		&PlayerList{Entries: []presence.Entry{{Name: "Alex", Ping: 80 * time.Millisecond}, {Name: "Steve", Ping: -1}}},
+		&Chat{Text: "<Alex> hi"},
+	}
+	var buf bytes.Buffer
+	for _, p := range packets {
+		if err := WritePacket(&buf, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := bufio.NewReader(&buf)
+	for _, want := range packets {
+		got, err := ReadPacket(r)
+		if err != nil {
+			t.Fatalf("reading %T: %v", want, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("read %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestTruncatedPacketRejected(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePacket(&buf, &Chat{Text: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	frame := buf.Bytes()
+	frame[3] -= 2 // claim a shorter frame than the string inside
+	if _, err := ReadPacket(bufio.NewReader(bytes.NewReader(frame[:len(frame)-2]))); err == nil {
+		t.Error("truncated chat packet accepted")
+	}
+}
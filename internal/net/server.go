@@ -0,0 +1,580 @@
+package net
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"mini-mc/internal/mathutil"
+	"mini-mc/internal/presence"
+	"mini-mc/internal/registry"
+	"mini-mc/internal/sim"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	loginTimeout     = 10 * time.Second
+	keepAliveTimeout = 30 * time.Second // dropped when no keep-alive is answered for this long
+
+	chunksPerTick   = 4  // columns sent to each player a tick
+	maxViewDistance = 32 // in chunks; farther requests are clamped
+	unloadMargin    = 2  // chunks past the view distance a column is kept before it is unloaded
+	maxChatLength   = 100
+
+	// autosaveTicks is how often edited chunks are saved, as in the game
+	autosaveTicks = 45 * world.TicksPerSecond
+
+	// simDistance is how many chunks around each player entities update
+	// every tick, the game's default; further out they slow, then freeze
+	simDistance = 8
+
+	// A player changes only blocks whose centre is within maxReach of their
+	// eyes, as MC's server checks: the game's reach with room for the
+	// player having moved since
+	maxReach        = 6
+	playerEyeHeight = 1.62 // as player.PlayerEyeHeight
+)
+
+// Game modes the server gives players, with the values of player.GameMode.
+const (
+	GameModeSurvival uint8 = iota
+	GameModeCreative
+)
+
+// Server runs a world for remote players. Connections are accepted and read
+// on goroutines of their own, which hand what they receive to the goroutine
+// calling Tick (or Run); the world and the players are only touched there.
+type Server struct {
+	// GameMode is given to players as they join: GameModeSurvival or
+	// GameModeCreative.
+	GameMode uint8
+
+	world   *world.World
+	engine  *sim.Engine
+	ln      net.Listener
+	players *presence.List
+
+	work     chan func() // for the tick goroutine
+	quit     chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	// Owned by the tick goroutine
+	clients map[int32]*remotePlayer
+	nextID  int32
+	changed map[world.BlockPos]struct{} // blocks changed this tick, to send
+	foci    [][2]float32                // where the players are, for the engine
+	ticks   int
+
+	mu     sync.Mutex
+	conns  map[*conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// remotePlayer is a connected player, as the server sees them.
+type remotePlayer struct {
+	conn         *conn
+	id           int32 // 0 until joined
+	name         string
+	viewDistance int
+	gameMode     uint8
+
+	pos        mgl32.Vec3
+	yaw, pitch float32
+	moved      bool // since other players were last told
+
+	lastHeard time.Time       // last keep-alive answered
+	sent      map[[2]int]bool // columns the player has
+}
+
+// Listen starts serving w on addr, e.g. ":25565". Nothing happens in the
+// world until Tick or Run is called.
+func Listen(addr string, w *world.World) (*Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	engine := sim.NewEngine(w)
+	engine.SimDistance = simDistance * world.ChunkSizeX
+	engine.SetFoci(nil)
+	s := &Server{
+		world:   w,
+		engine:  engine,
+		ln:      ln,
+		work:    make(chan func(), 256),
+		quit:    make(chan struct{}),
+		stop:    make(chan struct{}),
+		clients: make(map[int32]*remotePlayer),
+		changed: make(map[world.BlockPos]struct{}),
+		conns:   make(map[*conn]struct{}),
+	}
+	s.players = &presence.List{OnMessage: func(text string) {
+		slog.Info(text)
+		s.broadcast(&Chat{Text: text}, nil)
+	}}
+	w.OnBlockChange(func(x, y, z int) {
+		s.changed[world.BlockPos{X: x, Y: y, Z: z}] = struct{}{}
+	})
+	s.wg.Add(1)
+	go s.accept()
+	slog.Info("server listening", "addr", ln.Addr())
+	return s, nil
+}
+
+// Addr returns the address the server listens on.
+func (s *Server) Addr() net.Addr {
+	return s.ln.Addr()
+}
+
+// Close stops listening, drops every connection and waits for them to end.
+// The world is left for the caller to save and close.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.quit)
+	for c := range s.conns {
+		c.abort()
+	}
+	s.mu.Unlock()
+	err := s.ln.Close()
+	s.wg.Wait()
+	s.world.OnBlockChange(nil)
+	return err
+}
+
+// Run ticks the world 20 times a second until Stop or Close is called.
+func (s *Server) Run() {
+	ticker := time.NewTicker(time.Second / world.TicksPerSecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-s.quit:
+			return
+		case <-ticker.C:
+			s.Tick()
+		}
+	}
+}
+
+// Stop makes Run return. It does not wait for it to.
+func (s *Server) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// Tick runs one game tick: what the connections received, the world, and
+// the chunks, block changes and movement sent back.
+func (s *Server) Tick() {
+	for drained := false; !drained; {
+		select {
+		case f := <-s.work:
+			f()
+		default:
+			drained = true
+		}
+	}
+
+	s.foci = s.foci[:0]
+	for _, rp := range s.clients {
+		s.foci = append(s.foci, [2]float32{rp.pos[0], rp.pos[2]})
+	}
+	s.engine.SetFoci(s.foci)
+	s.engine.Tick()
+
+	for _, rp := range s.clients {
+		s.sendChunks(rp)
+	}
+	s.sendBlockChanges()
+	s.sendMoves()
+
+	if s.ticks%world.TicksPerSecond == 0 {
+		s.everySecond()
+	}
+	if s.ticks > 0 && s.ticks%autosaveTicks == 0 {
+		if err := s.world.Autosave(); err != nil {
+			slog.Error("autosave failed", "err", err)
+		}
+	}
+	s.ticks++
+}
+
+// everySecond pings the players, drops those that stopped answering, sends
+// the player list and the time, and evicts chunks nobody is near.
+func (s *Server) everySecond() {
+	now := time.Now()
+	for _, rp := range s.clients {
+		if now.Sub(rp.lastHeard) > keepAliveTimeout {
+			s.disconnect(rp, "Timed out")
+			continue
+		}
+		rp.conn.send(&KeepAlive{Nonce: now.UnixNano()})
+	}
+	s.broadcast(&PlayerList{Entries: s.players.Entries()}, nil)
+	s.broadcast(&TimeUpdate{Time: s.world.Time()}, nil)
+
+	positions := make([]mgl32.Vec3, 0, len(s.clients))
+	farthest := 0
+	for _, rp := range s.clients {
+		positions = append(positions, rp.pos)
+		farthest = max(farthest, rp.viewDistance)
+	}
+	// Eviction is by distance; cover the corners of the square sent
+	s.world.EvictChunksFarFromAll(positions, int(float64(farthest+unloadMargin)*math.Sqrt2)+1)
+}
+
+// SaveAll writes the world to disk from the tick goroutine and waits for
+// it. Run must be running.
+func (s *Server) SaveAll() error {
+	return s.call(s.world.Save)
+}
+
+// Players returns the names of the connected players.
+func (s *Server) Players() []string {
+	return s.players.Names()
+}
+
+// Kick disconnects a player, telling them why.
+func (s *Server) Kick(name, reason string) error {
+	return s.call(func() error {
+		for _, rp := range s.clients {
+			if strings.EqualFold(rp.name, name) {
+				s.disconnect(rp, reason)
+				return nil
+			}
+		}
+		return errors.New("no player named " + name)
+	})
+}
+
+// GameRule returns the value of a game rule of the world.
+func (s *Server) GameRule(name string) (string, bool) {
+	var v string
+	var ok bool
+	s.call(func() error {
+		if level := s.world.Level(); level != nil {
+			v, ok = level.GameRule(name)
+		}
+		return nil
+	})
+	return v, ok
+}
+
+// SetGameRule sets a game rule of the world.
+func (s *Server) SetGameRule(name, value string) error {
+	return s.call(func() error {
+		level := s.world.Level()
+		if level == nil {
+			return errors.New("the world has no game rules")
+		}
+		return level.SetGameRule(name, value)
+	})
+}
+
+// call runs f on the tick goroutine and returns its error.
+func (s *Server) call(f func() error) error {
+	done := make(chan error, 1)
+	if !s.run(func() { done <- f() }) {
+		return errors.New("server closed")
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-s.quit:
+		return errors.New("server closed")
+	}
+}
+
+// run hands f to the tick goroutine. It reports false if the server closed
+// first.
+func (s *Server) run(f func()) bool {
+	select {
+	case s.work <- f:
+		return true
+	case <-s.quit:
+		return false
+	}
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+	for {
+		c, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			c.Close()
+			return
+		}
+		cn := newConn(c)
+		s.conns[cn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go s.serve(cn, c.RemoteAddr().String())
+	}
+}
+
+// serve reads a connection's login, then its packets, until it ends.
+func (s *Server) serve(cn *conn, addr string) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, cn)
+		s.mu.Unlock()
+		cn.abort()
+	}()
+
+	cn.c.SetReadDeadline(time.Now().Add(loginTimeout))
+	p, err := cn.read()
+	login, ok := p.(*Login)
+	if err != nil || !ok {
+		slog.Warn("bad login", "addr", addr, "err", err)
+		return
+	}
+	if login.Version != ProtocolVersion {
+		cn.send(&Disconnect{Reason: fmt.Sprintf("Server speaks protocol %d, not %d", ProtocolVersion, login.Version)})
+		cn.close()
+		<-cn.done
+		return
+	}
+	cn.c.SetReadDeadline(time.Time{})
+
+	rp := &remotePlayer{conn: cn}
+	if !s.run(func() { s.join(rp, login, addr) }) {
+		return
+	}
+	for {
+		p, err := cn.read()
+		if err != nil {
+			s.run(func() { s.leave(rp) })
+			return
+		}
+		s.run(func() { s.handle(rp, p) })
+	}
+}
+
+// join admits a logged-in player.
+func (s *Server) join(rp *remotePlayer, login *Login, addr string) {
+	name, err := s.players.Join(login.Name)
+	if err != nil {
+		rp.conn.send(&Disconnect{Reason: err.Error()})
+		rp.conn.close()
+		return
+	}
+	s.nextID++
+	rp.id, rp.name = s.nextID, name
+	rp.viewDistance = min(max(int(login.ViewDistance), 2), maxViewDistance)
+	rp.gameMode = s.GameMode
+	rp.lastHeard = time.Now()
+	rp.sent = make(map[[2]int]bool)
+	spawn := s.world.Spawn()
+	rp.pos = mgl32.Vec3{float32(spawn.X) + 0.5, float32(spawn.Y), float32(spawn.Z) + 0.5}
+	slog.Info("player logged in", "name", name, "addr", addr, "id", rp.id)
+
+	rp.conn.send(&LoginSuccess{
+		PlayerID: rp.id,
+		Name:     name,
+		Seed:     s.world.Seed(),
+		GameMode: s.GameMode,
+		Spawn:    rp.pos,
+		Time:     s.world.Time(),
+	})
+	for _, other := range s.clients {
+		rp.conn.send(spawnPacket(other))
+	}
+	s.broadcast(spawnPacket(rp), nil)
+	s.clients[rp.id] = rp
+	s.broadcast(&PlayerList{Entries: s.players.Entries()}, nil)
+}
+
+// column returns the chunk column the player is in.
+func (rp *remotePlayer) column() (int, int) {
+	return mathutil.FloorDiv(int(math.Floor(float64(rp.pos[0]))), world.ChunkSizeX),
+		mathutil.FloorDiv(int(math.Floor(float64(rp.pos[2]))), world.ChunkSizeZ)
+}
+
+func spawnPacket(rp *remotePlayer) *SpawnPlayer {
+	return &SpawnPlayer{PlayerID: rp.id, Name: rp.name, Pos: rp.pos, Yaw: rp.yaw, Pitch: rp.pitch}
+}
+
+// leave removes a player whose connection ended.
+func (s *Server) leave(rp *remotePlayer) {
+	if _, ok := s.clients[rp.id]; !ok {
+		return
+	}
+	delete(s.clients, rp.id)
+	rp.conn.close()
+	s.broadcast(&DestroyPlayer{PlayerID: rp.id}, nil)
+	s.players.Leave(rp.name)
+}
+
+// disconnect sends a player the reason and drops them.
+func (s *Server) disconnect(rp *remotePlayer, reason string) {
+	slog.Info("disconnecting player", "name", rp.name, "reason", reason)
+	rp.conn.send(&Disconnect{Reason: reason})
+	s.leave(rp)
+}
+
+// handle acts on a packet from a player.
+func (s *Server) handle(rp *remotePlayer, p Packet) {
+	if _, ok := s.clients[rp.id]; !ok {
+		return
+	}
+	switch p := p.(type) {
+	case *KeepAlive:
+		rp.lastHeard = time.Now()
+		rtt := time.Since(time.Unix(0, p.Nonce))
+		s.players.SetPing(rp.name, rtt)
+		rp.conn.stats().ObserveRTT(rtt)
+	case *PlayerPosition:
+		rp.pos, rp.yaw, rp.pitch = p.Pos, p.Yaw, p.Pitch
+		rp.moved = true
+	case *BlockChange:
+		s.applyBlockChange(rp, p)
+	case *Chat:
+		text := strings.TrimSpace(p.Text)
+		if text == "" {
+			return
+		}
+		if len(text) > maxChatLength {
+			text = text[:maxChatLength]
+		}
+		line := "<" + rp.name + "> " + text
+		slog.Info(line)
+		s.broadcast(&Chat{Text: line}, nil)
+	case *ViewDistance:
+		rp.viewDistance = min(max(int(p.Chunks), 2), maxViewDistance)
+	}
+}
+
+// applyBlockChange makes a change a player made to their copy of the world
+// in the server's, with the updates placing or breaking the block brings.
+// Changes in chunks the player was not sent are ignored. Those out of the
+// player's reach, and breaking or placing blocks only creative players may,
+// are refused and the player sent the block back.
+func (s *Server) applyBlockChange(rp *remotePlayer, p *BlockChange) {
+	x, y, z := int(p.X), int(p.Y), int(p.Z)
+	column := [2]int{mathutil.FloorDiv(x, world.ChunkSizeX), mathutil.FloorDiv(z, world.ChunkSizeZ)}
+	if y < 0 || y >= world.ChunkSizeY || !rp.sent[column] || s.world.GetChunk(column[0], 0, column[1], false) == nil {
+		return
+	}
+	if t := p.State.Type; t != world.BlockTypeAir && registry.Blocks[t] == nil {
+		return
+	}
+	eye := rp.pos.Add(mgl32.Vec3{0, playerEyeHeight, 0})
+	centre := mgl32.Vec3{float32(x) + 0.5, float32(y) + 0.5, float32(z) + 0.5}
+	old := s.world.GetState(x, y, z)
+	if centre.Sub(eye).Len() > maxReach ||
+		rp.gameMode != GameModeCreative && (creativeOnly(old.Type) || creativeOnly(p.State.Type)) {
+		rp.conn.send(&BlockChange{X: p.X, Y: p.Y, Z: p.Z, State: old})
+		return
+	}
+	s.world.SetState(x, y, z, p.State)
+	s.world.NotifyNeighbors(x, y, z)
+	switch p.State.Type {
+	case world.BlockTypeWater:
+		s.world.ScheduleBlockTick(x, y, z, world.WaterTickRate, 0)
+	case world.BlockTypeLava:
+		s.world.ScheduleBlockTick(x, y, z, world.LavaTickRate, 0)
+	case world.BlockTypeSnowLayer:
+		s.world.ScheduleBlockTick(x, y, z, world.SnowTickRate, 0)
+	}
+}
+
+// creativeOnly reports whether only creative players may break or place
+// blocks of type t: those that cannot be mined, such as bedrock.
+func creativeOnly(t world.BlockType) bool {
+	def := registry.BlockDefs[t]
+	return def != nil && def.Hardness < 0
+}
+
+// sendChunks sends a player the nearest loaded columns in their view they
+// do not have yet, a few a tick, and unloads those they moved away from.
+func (s *Server) sendChunks(rp *remotePlayer) {
+	cx, cz := rp.column()
+	s.world.StreamChunksAroundAsync(rp.pos[0], rp.pos[2], rp.viewDistance)
+
+	for column := range rp.sent {
+		if max(mathutil.Abs(column[0]-cx), mathutil.Abs(column[1]-cz)) > rp.viewDistance+unloadMargin {
+			delete(rp.sent, column)
+			rp.conn.send(&UnloadChunk{X: int32(column[0]), Z: int32(column[1])})
+		}
+	}
+
+	sent := 0
+	for r := 0; r <= rp.viewDistance && sent < chunksPerTick; r++ {
+		for dx := -r; dx <= r && sent < chunksPerTick; dx++ {
+			for dz := -r; dz <= r && sent < chunksPerTick; dz++ {
+				if max(mathutil.Abs(dx), mathutil.Abs(dz)) != r {
+					continue
+				}
+				column := [2]int{cx + dx, cz + dz}
+				if rp.sent[column] {
+					continue
+				}
+				c := s.world.GetChunk(column[0], 0, column[1], false)
+				if c == nil {
+					continue
+				}
+				data, err := world.EncodeChunk(c)
+				if err != nil {
+					slog.Error("encoding chunk", "chunk", column, "err", err)
+					continue
+				}
+				rp.sent[column] = true
+				rp.conn.send(&ChunkData{X: int32(column[0]), Z: int32(column[1]), Data: data})
+				sent++
+			}
+		}
+	}
+}
+
+// sendBlockChanges sends the blocks changed this tick to the players that
+// have them.
+func (s *Server) sendBlockChanges() {
+	for pos := range s.changed {
+		column := [2]int{mathutil.FloorDiv(pos.X, world.ChunkSizeX), mathutil.FloorDiv(pos.Z, world.ChunkSizeZ)}
+		p := &BlockChange{X: int32(pos.X), Y: int32(pos.Y), Z: int32(pos.Z), State: s.world.GetState(pos.X, pos.Y, pos.Z)}
+		for _, rp := range s.clients {
+			if rp.sent[column] {
+				rp.conn.send(p)
+			}
+		}
+	}
+	clear(s.changed)
+}
+
+// sendMoves tells everyone else where the players that moved are.
+func (s *Server) sendMoves() {
+	for _, rp := range s.clients {
+		if !rp.moved {
+			continue
+		}
+		rp.moved = false
+		s.broadcast(&MovePlayer{PlayerID: rp.id, Pos: rp.pos, Yaw: rp.yaw, Pitch: rp.pitch}, rp)
+	}
+}
+
+// broadcast sends p to every player but except.
+func (s *Server) broadcast(p Packet, except *remotePlayer) {
+	for _, rp := range s.clients {
+		if rp != except {
+			rp.conn.send(p)
+		}
+	}
+}
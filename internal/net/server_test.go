@@ -0,0 +1,175 @@
+package net
+
+import (
+	"testing"
+	"time"
+
+	"mini-mc/internal/entity"
+	"mini-mc/internal/item"
+	"mini-mc/internal/mathutil"
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// waitFor polls c until a packet matches, failing the test after a while.
+func waitFor[P Packet](t *testing.T, c *Client, match func(P) bool) P {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		packets, ok := c.Poll(256)
+		for _, p := range packets {
+			if p, is := p.(P); is && match(p) {
+				return p
+			}
+		}
+		if !ok {
+			t.Fatalf("connection ended: %v", c.Err())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	var zero P
+	t.Fatalf("no %T arrived", zero)
+	return zero
+}
+
+func TestServerRelaysBlocksAndPlayers(t *testing.T) {
+	w := world.New()
+	defer w.Close()
+	s, err := Listen("127.0.0.1:0", w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	go s.Run()
+
+	alex, err := Dial(s.Addr().String(), "Alex", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer alex.Close()
+	steve, err := Dial(s.Addr().String(), "Alex", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer steve.Close()
+	if steve.Name != "Alex2" {
+		t.Errorf("second Alex named %q", steve.Name)
+	}
+
+	// Both are sent the column they spawn in
+	cx, cz := int32(mathutil.FloorDiv(int(alex.Spawn[0]), world.ChunkSizeX)), int32(mathutil.FloorDiv(int(alex.Spawn[2]), world.ChunkSizeZ))
+	atSpawn := func(p *ChunkData) bool { return p.X == cx && p.Z == cz }
+	chunk := waitFor(t, alex, atSpawn)
+	if _, err := world.DecodeChunk(world.ChunkCoord{X: int(cx), Z: int(cz)}, chunk.Data); err != nil {
+		t.Fatalf("decoding spawn chunk: %v", err)
+	}
+	waitFor(t, steve, atSpawn)
+
+	// A block one breaks breaks for the other
+	x, y, z := int32(alex.Spawn[0]), int32(alex.Spawn[1])-1, int32(alex.Spawn[2])
+	if w.Get(int(x), int(y), int(z)) == world.BlockTypeAir {
+		t.Fatal("spawned on air")
+	}
+	alex.Send(&BlockChange{X: x, Y: y, Z: z, State: world.StateAir})
+	waitFor(t, steve, func(p *BlockChange) bool {
+		return p.X == x && p.Y == y && p.Z == z && p.State.IsAir()
+	})
+
+	// And so does where they walk
+	to := alex.Spawn.Add(mgl32.Vec3{1, 0, 0})
+	alex.Send(&PlayerPosition{Pos: to, Yaw: 90})
+	waitFor(t, steve, func(p *MovePlayer) bool { return p.PlayerID == alex.PlayerID && p.Pos == to })
+
+	// Leaving removes the player from the other's world
+	alex.Close()
+	waitFor(t, steve, func(p *DestroyPlayer) bool { return p.PlayerID == alex.PlayerID })
+}
+
+func TestServerMovesEntitiesNearPlayers(t *testing.T) {
+	w := world.New()
+	defer w.Close()
+	s, err := Listen("127.0.0.1:0", w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	go s.Run()
+
+	alex, err := Dial(s.Addr().String(), "Alex", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer alex.Close()
+
+	// Dropped beside the player, far from the origin, and above the ground
+	start := alex.Spawn.Add(mgl32.Vec3{0, 20, 0})
+	var drop *entity.ItemEntity
+	s.call(func() error {
+		drop = entity.NewItemEntity(w, w.Rand(), start, item.NewItemStack(world.BlockTypeDirt, 1))
+		w.AddEntity(drop)
+		return nil
+	})
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		var pos mgl32.Vec3
+		s.call(func() error { pos = drop.Pos; return nil })
+		if pos.Y() < start.Y()-1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("dropped item still at %v, dropped at %v", pos, start)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServerRefusesBlockChangesPlayersCannotMake(t *testing.T) {
+	bedrock := registry.BlockDefs[world.BlockTypeBedrock]
+	registry.BlockDefs[world.BlockTypeBedrock] = &registry.BlockDefinition{ID: world.BlockTypeBedrock, IsSolid: true, Hardness: -1}
+	t.Cleanup(func() { registry.BlockDefs[world.BlockTypeBedrock] = bedrock })
+
+	w := world.New()
+	defer w.Close()
+	s, err := Listen("127.0.0.1:0", w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	go s.Run()
+
+	alex, err := Dial(s.Addr().String(), "Alex", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer alex.Close()
+	cx, cz := int32(mathutil.FloorDiv(int(alex.Spawn[0]), world.ChunkSizeX)), int32(mathutil.FloorDiv(int(alex.Spawn[2]), world.ChunkSizeZ))
+	waitFor(t, alex, func(p *ChunkData) bool { return p.X == cx && p.Z == cz })
+
+	x, y, z := int32(alex.Spawn[0]), int32(alex.Spawn[1])-1, int32(alex.Spawn[2])
+	refused := func(x, y, z int32) {
+		t.Helper()
+		var want world.BlockState
+		s.call(func() error { want = w.GetState(int(x), int(y), int(z)); return nil })
+		alex.Send(&BlockChange{X: x, Y: y, Z: z, State: world.StateAir})
+		waitFor(t, alex, func(p *BlockChange) bool {
+			return p.X == x && p.Y == y && p.Z == z && p.State == want
+		})
+		var got world.BlockState
+		s.call(func() error { got = w.GetState(int(x), int(y), int(z)); return nil })
+		if got != want {
+			t.Fatalf("block at %d %d %d changed to %v", x, y, z, got)
+		}
+	}
+
+	// Far below the player's feet, out of reach
+	if w.Get(int(x), 1, int(z)) == world.BlockTypeAir {
+		t.Fatal("no ground deep under spawn")
+	}
+	refused(x, 1, z)
+
+	// Bedrock underfoot, which survival players cannot break
+	s.call(func() error { w.Set(int(x), int(y), int(z), world.BlockTypeBedrock); return nil })
+	refused(x, y, z)
+}
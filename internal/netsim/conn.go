@@ -0,0 +1,232 @@
+package netsim
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+// queueLength is how many packets may be in flight each way before writes
+// block.
+const queueLength = 256
+
+// packet is data in flight, due at at. A non-nil err ends the stream.
+type packet struct {
+	at   time.Time
+	data []byte
+	err  error
+}
+
+// delayLine delivers packets in order, each no earlier than it is due.
+type delayLine struct {
+	settings Settings
+	rnd      *rand.Rand
+	last     time.Time // when the previous packet is due; a stream never reorders
+	queue    chan packet
+}
+
+func newDelayLine(s Settings, seed int64) *delayLine {
+	return &delayLine{settings: s, rnd: rand.New(rand.NewSource(seed)), queue: make(chan packet, queueLength)}
+}
+
+// due returns when a packet sent now arrives.
+func (l *delayLine) due(now time.Time) time.Time {
+	at := now.Add(l.settings.Latency)
+	if l.settings.Jitter > 0 {
+		at = at.Add(time.Duration(l.rnd.Int63n(int64(l.settings.Jitter) + 1)))
+	}
+	if l.settings.Loss > 0 && l.rnd.Float64() < l.settings.Loss {
+		at = at.Add(RetransmitDelay)
+	}
+	if at.Before(l.last) {
+		at = l.last
+	}
+	l.last = at
+	return at
+}
+
+// run delivers queued packets until the queue is closed or deliver fails.
+func (l *delayLine) run(deliver func(packet) error) {
+	for p := range l.queue {
+		if d := time.Until(p.at); d > 0 {
+			time.Sleep(d)
+		}
+		if deliver(p) != nil {
+			break
+		}
+	}
+	for range l.queue {
+	}
+}
+
+// Conn is a connection whose traffic is metered and, if its settings are
+// enabled, delayed as they say. Writes return as soon as the data is
+// queued; a write error shows up on a later Write or on Close, and write
+// deadlines apply to the delayed writes to the underlying connection.
+type Conn struct {
+	net.Conn
+	stats *Stats
+
+	// Set when simulating: app is the end the caller reads from, fed by
+	// in with what arrives on the underlying connection.
+	out, in *delayLine
+	app     net.Conn
+
+	mu     sync.Mutex // held while queueing a write
+	closed bool
+	done   chan struct{} // closed once the queued writes are sent
+
+	errMu    sync.Mutex // separate from mu, which a full queue holds up
+	writeErr error
+}
+
+// Wrap meters c into stats, which may be nil, and simulates the network
+// set by Configure on it.
+func Wrap(c net.Conn, stats *Stats) *Conn {
+	return WrapWith(c, stats, Configured())
+}
+
+// WrapWith is Wrap with the given settings.
+func WrapWith(c net.Conn, stats *Stats, s Settings) *Conn {
+	if stats == nil {
+		stats = &Stats{}
+	}
+	w := &Conn{Conn: c, stats: stats, done: make(chan struct{})}
+	if !s.Enabled() {
+		close(w.done)
+		return w
+	}
+
+	seed := time.Now().UnixNano()
+	w.out = newDelayLine(s, seed)
+	w.in = newDelayLine(s, seed+1)
+	var feed net.Conn
+	w.app, feed = net.Pipe()
+
+	go func() {
+		defer close(w.done)
+		w.out.run(func(p packet) error {
+			_, err := c.Write(p.data)
+			if err != nil {
+				w.errMu.Lock()
+				w.writeErr = err
+				w.errMu.Unlock()
+			}
+			return err
+		})
+	}()
+	go w.in.run(func(p packet) error {
+		if p.err != nil {
+			feed.Close()
+			return p.err
+		}
+		w.stats.AddReceived(len(p.data))
+		_, err := feed.Write(p.data)
+		return err
+	})
+	go func() {
+		defer close(w.in.queue)
+		for {
+			buf := make([]byte, 32<<10)
+			n, err := c.Read(buf)
+			if n > 0 {
+				w.in.queue <- packet{at: w.in.due(time.Now()), data: buf[:n]}
+			}
+			if err != nil {
+				w.in.queue <- packet{at: w.in.due(time.Now()), err: err}
+				return
+			}
+		}
+	}()
+	return w
+}
+
+// Stats returns the connection's traffic meter.
+func (c *Conn) Stats() *Stats {
+	return c.stats
+}
+
+// Read reads data that has arrived. When simulating, each chunk counts as
+// a packet as it arrives rather than as it is read.
+func (c *Conn) Read(b []byte) (int, error) {
+	if c.app == nil {
+		n, err := c.Conn.Read(b)
+		if n > 0 {
+			c.stats.AddReceived(n)
+		}
+		return n, err
+	}
+	return c.app.Read(b)
+}
+
+// Write sends b as one packet.
+func (c *Conn) Write(b []byte) (int, error) {
+	if c.out == nil {
+		n, err := c.Conn.Write(b)
+		if n > 0 {
+			c.stats.AddSent(n)
+		}
+		return n, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return 0, net.ErrClosed
+	}
+	if err := c.err(); err != nil {
+		return 0, err
+	}
+	c.out.queue <- packet{at: c.out.due(time.Now()), data: append([]byte(nil), b...)}
+	c.stats.AddSent(len(b))
+	return len(b), nil
+}
+
+// Close sends what is still queued, as late as it is due, then closes the
+// connection.
+func (c *Conn) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return net.ErrClosed
+	}
+	c.closed = true
+	if c.out != nil {
+		close(c.out.queue)
+	}
+	c.mu.Unlock()
+
+	<-c.done
+	err := c.Conn.Close()
+	if c.app != nil {
+		c.app.Close()
+	}
+	if werr := c.err(); werr != nil && !errors.Is(werr, net.ErrClosed) {
+		return werr
+	}
+	return err
+}
+
+// err returns the error of a failed delayed write.
+func (c *Conn) err() error {
+	c.errMu.Lock()
+	defer c.errMu.Unlock()
+	return c.writeErr
+}
+
+// SetDeadline sets the read and write deadlines.
+func (c *Conn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.Conn.SetWriteDeadline(t)
+}
+
+// SetReadDeadline sets the deadline for Read.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	if c.app != nil {
+		return c.app.SetReadDeadline(t)
+	}
+	return c.Conn.SetReadDeadline(t)
+}
@@ -0,0 +1,95 @@
+// Package netsim makes a connection behave like a bad network, for testing
+// prediction and reconciliation without one. A wrapped connection delays
+// what it sends and receives by a latency plus random jitter, and treats a
+// share of its writes as lost: as on TCP, a lost packet still arrives, but
+// only after a retransmission timeout, holding up everything behind it.
+//
+// It also meters traffic for the netgraph, whether or not any of that is
+// simulated.
+package netsim
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetransmitDelay is how much later than usual a lost packet arrives; it is
+// TCP's minimum retransmission timeout.
+const RetransmitDelay = 200 * time.Millisecond
+
+// Settings is the network to simulate, in each direction.
+type Settings struct {
+	Latency time.Duration // one-way delay added to every packet
+	Jitter  time.Duration // up to this much more, at random
+	Loss    float64       // share of packets lost and retransmitted, 0 to 1
+}
+
+// Enabled reports whether s changes anything.
+func (s Settings) Enabled() bool {
+	return s.Latency > 0 || s.Jitter > 0 || s.Loss > 0
+}
+
+func (s Settings) String() string {
+	return fmt.Sprintf("latency=%s,jitter=%s,loss=%g", s.Latency, s.Jitter, s.Loss)
+}
+
+// ParseSettings reads settings written like
+// "latency=120ms,jitter=30ms,loss=0.05". Omitted values are zero, and an
+// empty string simulates nothing.
+func ParseSettings(text string) (Settings, error) {
+	var s Settings
+	if text == "" {
+		return s, nil
+	}
+	for _, field := range strings.Split(text, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			return Settings{}, fmt.Errorf("netsim: %q is not key=value", field)
+		}
+		var err error
+		switch key {
+		case "latency":
+			s.Latency, err = time.ParseDuration(value)
+		case "jitter":
+			s.Jitter, err = time.ParseDuration(value)
+		case "loss":
+			s.Loss, err = strconv.ParseFloat(value, 64)
+			if err == nil && (s.Loss < 0 || s.Loss > 1) {
+				err = errors.New("must be between 0 and 1")
+			}
+		default:
+			return Settings{}, fmt.Errorf("netsim: unknown setting %q", key)
+		}
+		if err != nil {
+			return Settings{}, fmt.Errorf("netsim: %s: %w", key, err)
+		}
+		if s.Latency < 0 || s.Jitter < 0 {
+			return Settings{}, fmt.Errorf("netsim: %s must not be negative", key)
+		}
+	}
+	return s, nil
+}
+
+var (
+	devMu       sync.RWMutex
+	devSettings Settings
+)
+
+// Configure sets what connections wrapped from now on simulate. The game
+// reads it from MINI_MC_NETSIM at startup.
+func Configure(s Settings) {
+	devMu.Lock()
+	defer devMu.Unlock()
+	devSettings = s
+}
+
+// Configured returns the settings new connections get.
+func Configured() Settings {
+	devMu.RLock()
+	defer devMu.RUnlock()
+	return devSettings
+}
@@ -0,0 +1,124 @@
+package netsim
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestParseSettings(t *testing.T) {
+	s, err := ParseSettings("latency=120ms, jitter=30ms,loss=0.05")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Settings{Latency: 120 * time.Millisecond, Jitter: 30 * time.Millisecond, Loss: 0.05}
+	if s != want {
+		t.Errorf("got %v, want %v", s, want)
+	}
+	if s, err := ParseSettings(""); err != nil || s.Enabled() {
+		t.Errorf("empty settings = %v, %v", s, err)
+	}
+	for _, bad := range []string{"latency", "latency=fast", "loss=2", "jitter=-5ms", "bandwidth=1"} {
+		if _, err := ParseSettings(bad); err == nil {
+			t.Errorf("ParseSettings(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestConnDelaysInOrder(t *testing.T) {
+	a, b := net.Pipe()
+	latency := 40 * time.Millisecond
+	stats := &Stats{}
+	c := WrapWith(a, stats, Settings{Latency: latency, Jitter: 30 * time.Millisecond})
+
+	start := time.Now()
+	for i := range 20 {
+		if _, err := c.Write([]byte{byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if time.Since(start) >= latency {
+		t.Fatal("writes waited for the simulated latency")
+	}
+	got := make([]byte, 20)
+	if _, err := io.ReadFull(b, got); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) < latency {
+		t.Error("data arrived before the latency passed")
+	}
+	for i, v := range got {
+		if v != byte(i) {
+			t.Fatalf("arrived out of order: %v", got)
+		}
+	}
+
+	// The other direction is delayed too
+	start = time.Now()
+	go b.Write([]byte("pong"))
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(c, buf); err != nil || string(buf) != "pong" {
+		t.Fatalf("read %q, %v", buf, err)
+	}
+	if time.Since(start) < latency {
+		t.Error("reply arrived before the latency passed")
+	}
+
+	go io.Copy(io.Discard, b)
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Write([]byte{1}); err == nil {
+		t.Error("write after close succeeded")
+	}
+	if stats.packetsOut != 20 || stats.bytesOut != 20 || stats.packetsIn != 1 || stats.bytesIn != 4 {
+		t.Errorf("metered %d/%d out, %d/%d in", stats.packetsOut, stats.bytesOut, stats.packetsIn, stats.bytesIn)
+	}
+}
+
+func TestConnLossRetransmits(t *testing.T) {
+	a, b := net.Pipe()
+	c := WrapWith(a, nil, Settings{Latency: time.Millisecond, Loss: 1})
+	defer c.Close()
+
+	start := time.Now()
+	c.Write([]byte{1})
+	if _, err := b.Read(make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) < RetransmitDelay {
+		t.Error("lost packet arrived without a retransmission delay")
+	}
+}
+
+func TestStatsSamples(t *testing.T) {
+	var s Stats
+	t0 := time.Unix(0, 0)
+	s.Samples(t0)
+	for range 5 {
+		s.AddSent(100)
+	}
+	s.AddReceived(40)
+	s.ObserveRTT(100 * time.Millisecond)
+	s.ObserveRTT(180 * time.Millisecond)
+
+	if got := s.Samples(t0.Add(SampleInterval / 2)); len(got) != 0 {
+		t.Fatalf("sampled before the interval ended: %v", got)
+	}
+	got := s.Samples(t0.Add(SampleInterval * 2))
+	if len(got) != 1 {
+		t.Fatalf("got %d samples, want 1", len(got))
+	}
+	want := Sample{RTT: 110 * time.Millisecond, PacketsOut: 10, BytesOut: 1000, PacketsIn: 2, BytesIn: 80}
+	if got[0] != want {
+		t.Errorf("sample = %+v, want %+v", got[0], want)
+	}
+
+	for i := range HistoryLength + 10 {
+		s.Samples(t0.Add(SampleInterval * time.Duration(3+i)))
+	}
+	if n := len(s.Samples(t0)); n != HistoryLength {
+		t.Errorf("kept %d samples, want %d", n, HistoryLength)
+	}
+}
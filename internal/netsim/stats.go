@@ -0,0 +1,97 @@
+package netsim
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// The netgraph shows rates averaged over SampleInterval, for the last
+// HistoryLength intervals.
+const (
+	SampleInterval = 250 * time.Millisecond
+	HistoryLength  = 120
+)
+
+// Sample is the traffic over one interval, in per-second rates.
+type Sample struct {
+	RTT        time.Duration // smoothed, as of the end of the interval
+	PacketsIn  float64
+	PacketsOut float64
+	BytesIn    float64
+	BytesOut   float64
+}
+
+// Stats meters a connection's traffic. It is safe for concurrent use.
+type Stats struct {
+	mu  sync.Mutex
+	rtt time.Duration // smoothed; 0 until measured
+
+	start                time.Time // of the current interval
+	packetsIn, bytesIn   int
+	packetsOut, bytesOut int
+	history              []Sample // oldest first
+}
+
+// AddSent counts a packet of n bytes sent.
+func (s *Stats) AddSent(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.packetsOut++
+	s.bytesOut += n
+}
+
+// AddReceived counts a packet of n bytes received.
+func (s *Stats) AddReceived(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.packetsIn++
+	s.bytesIn += n
+}
+
+// ObserveRTT records a measured round trip. The RTT shown is smoothed like
+// TCP's, each measurement moving it an eighth of the way.
+func (s *Stats) ObserveRTT(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rtt == 0 {
+		s.rtt = d
+	} else {
+		s.rtt += (d - s.rtt) / 8
+	}
+}
+
+// RTT returns the smoothed round trip, or 0 if none was measured.
+func (s *Stats) RTT() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rtt
+}
+
+// Samples closes the current interval if it has run its length by now and
+// returns the history, oldest first. Traffic since the last call is spread
+// over however long it has been, so calling it less often than every
+// SampleInterval only coarsens the graph.
+func (s *Stats) Samples(now time.Time) []Sample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.start.IsZero() {
+		s.start = now
+	}
+	if elapsed := now.Sub(s.start); elapsed >= SampleInterval {
+		perSecond := float64(time.Second) / float64(elapsed)
+		if len(s.history) == HistoryLength {
+			s.history = slices.Delete(s.history, 0, 1)
+		}
+		s.history = append(s.history, Sample{
+			RTT:        s.rtt,
+			PacketsIn:  float64(s.packetsIn) * perSecond,
+			PacketsOut: float64(s.packetsOut) * perSecond,
+			BytesIn:    float64(s.bytesIn) * perSecond,
+			BytesOut:   float64(s.bytesOut) * perSecond,
+		})
+		s.start = now
+		s.packetsIn, s.bytesIn, s.packetsOut, s.bytesOut = 0, 0, 0, 0
+	}
+	return slices.Clone(s.history)
+}
@@ -1,16 +1,30 @@
 package physics
 
 import (
-	"fmt"
+	"log/slog"
 	"math"
 	"time"
 
+	"mini-mc/internal/mathutil"
 	"mini-mc/internal/profiling"
 	"mini-mc/internal/world"
 
 	"github.com/go-gl/mathgl/mgl32"
 )
 
+// blockCollisionHeight returns the collision height of the block at (x, y, z):
+// 0 when it can be walked through, 1 for a full cube, in between for partial blocks.
+func blockCollisionHeight(w *world.World, x, y, z int) float32 {
+	bt := w.Get(x, y, z)
+	if bt == world.BlockTypeSnowLayer {
+		return world.CollisionHeight(bt, w.GetMeta(x, y, z))
+	}
+	if world.BlockSolidTable[bt] {
+		return 1
+	}
+	return 0
+}
+
 // Checks if a position collides with any block in the world
 func Collides(pos mgl32.Vec3, width, height float32, w *world.World) bool {
 	now := time.Now()
@@ -29,13 +43,13 @@ func Collides(pos mgl32.Vec3, width, height float32, w *world.World) bool {
 	for x := minX - 1; x <= maxX+1; x++ {
 		for y := minY - 1; y <= maxY+1; y++ {
 			for z := minZ - 1; z <= maxZ+1; z++ {
-				if world.BlockSolidTable[w.Get(x, y, z)] {
+				if h := blockCollisionHeight(w, x, y, z); h > 0 {
 					iterations++
 					blockMinX := float32(x)
 					blockMaxX := float32(x) + 1.0
-					// Standard mapping: Y range is [y, y+1)
+					// Standard mapping: Y range is [y, y+h)
 					blockMinY := float32(y)
-					blockMaxY := float32(y) + 1.0
+					blockMaxY := float32(y) + h
 					blockMinZ := float32(z)
 					blockMaxZ := float32(z) + 1.0
 
@@ -57,7 +71,7 @@ func Collides(pos mgl32.Vec3, width, height float32, w *world.World) bool {
 
 	d := time.Since(now)
 	if d > 10*time.Millisecond {
-		fmt.Println(maxX, maxY, maxZ, iterations)
+		slog.Debug("slow collision check", "duration", d, "max", [3]int{maxX, maxY, maxZ}, "iterations", iterations)
 	}
 
 	return false
@@ -91,9 +105,9 @@ func FindGroundLevel(x, z float32, playerPos mgl32.Vec3, width, height float32,
 			}
 			// Search from player feet downwards
 			for by := int(math.Floor(float64(playerPos.Y()))); by >= 0; by-- {
-				if world.BlockSolidTable[w.Get(bx, by, bz)] {
-					// Top of block is at y+1
-					groundY := float32(by) + 1.0
+				if h := blockCollisionHeight(w, bx, by, bz); h > 0 {
+					// Top of block is at y+h
+					groundY := float32(by) + h
 					if groundY > maxGroundY {
 						maxGroundY = groundY
 					}
@@ -107,25 +121,15 @@ func FindGroundLevel(x, z float32, playerPos mgl32.Vec3, width, height float32,
 
 // IntersectsBlock checks if the player's AABB would intersect with the given block coordinates
 func IntersectsBlock(playerPos mgl32.Vec3, width, height float32, bx, by, bz int) bool {
-	blockMinX := float32(bx)
-	blockMaxX := float32(bx) + 1.0
 	// Standard mapping: Y range is [y, y+1)
-	blockMinY := float32(by)
-	blockMaxY := float32(by) + 1.0
-	blockMinZ := float32(bz)
-	blockMaxZ := float32(bz) + 1.0
+	blockMin := mgl32.Vec3{float32(bx), float32(by), float32(bz)}
+	blockMax := blockMin.Add(mgl32.Vec3{1, 1, 1})
 
 	// Player half-width around X/Z and height along Y
-	playerMinX := playerPos.X() - width/2
-	playerMaxX := playerPos.X() + width/2
-	playerMinY := playerPos.Y()
-	playerMaxY := playerPos.Y() + height
-	playerMinZ := playerPos.Z() - width/2
-	playerMaxZ := playerPos.Z() + width/2
-
-	return playerMinX < blockMaxX && playerMaxX > blockMinX &&
-		playerMinY < blockMaxY && playerMaxY > blockMinY &&
-		playerMinZ < blockMaxZ && playerMaxZ > blockMinZ
+	playerMin := mgl32.Vec3{playerPos.X() - width/2, playerPos.Y(), playerPos.Z() - width/2}
+	playerMax := mgl32.Vec3{playerPos.X() + width/2, playerPos.Y() + height, playerPos.Z() + width/2}
+
+	return mathutil.AABBsOverlap(playerMin, playerMax, blockMin, blockMax)
 }
 
 // FindCeilingLevel finds the lowest ceiling (bottom face of a block) above the player's head
@@ -158,7 +162,7 @@ func FindCeilingLevel(x, z float32, playerPos mgl32.Vec3, width, height float32,
 				continue
 			}
 			for by := startY; by <= 255; by++ {
-				if world.BlockSolidTable[w.Get(bx, by, bz)] {
+				if blockCollisionHeight(w, bx, by, bz) > 0 {
 					// Bottom of block is at by
 					ceilingY := float32(by)
 					if ceilingY < minCeilingY {
@@ -18,10 +18,17 @@ const (
 type RaycastResult struct {
 	HitPosition      [3]int
 	AdjacentPosition [3]int
+	State            world.BlockState // of the block hit
 	Distance         float32
 	Hit              bool
 }
 
+// targetable reports whether the crosshair stops at a block: solid blocks,
+// and snow layers, item frames, rails and plants despite not being solid.
+func targetable(bt world.BlockType) bool {
+	return world.BlockSolidTable[bt] || bt == world.BlockTypeSnowLayer || bt == world.BlockTypeItemFrame || bt == world.BlockTypeRail || world.IsPlant(bt)
+}
+
 // Raycast performs a ray casting operation from a starting point in a given direction
 func Raycast(start mgl32.Vec3, direction mgl32.Vec3, minDist, maxDist float32, w *world.World) RaycastResult {
 	defer profiling.Track("physics.Raycast")()
@@ -140,13 +147,13 @@ func Raycast(start mgl32.Vec3, direction mgl32.Vec3, minDist, maxDist float32, w
 			continue
 		}
 
-		// Check if block is solid
-		if world.BlockSolidTable[w.Get(bx, by, bz)] {
+		if st := w.GetState(bx, by, bz); targetable(st.Type) {
 			if dist < minDist {
 				continue
 			}
 
 			result.HitPosition = [3]int{bx, by, bz}
+			result.State = st
 
 			// Adjacent position is the one we stepped from
 			adj := [3]int{bx, by, bz}
@@ -167,3 +174,33 @@ func Raycast(start mgl32.Vec3, direction mgl32.Vec3, minDist, maxDist float32, w
 
 	return result
 }
+
+// RayIntersectsAABB returns the distance along direction at which a ray from
+// start first enters the box [minB, maxB], using the slab method.
+func RayIntersectsAABB(start, direction, minB, maxB mgl32.Vec3) (float32, bool) {
+	tMin := float32(math.Inf(-1))
+	tMax := float32(math.Inf(1))
+	for axis := 0; axis < 3; axis++ {
+		if direction[axis] == 0 {
+			if start[axis] < minB[axis] || start[axis] > maxB[axis] {
+				return 0, false
+			}
+			continue
+		}
+		inv := 1 / direction[axis]
+		t1 := (minB[axis] - start[axis]) * inv
+		t2 := (maxB[axis] - start[axis]) * inv
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tMin = max(tMin, t1)
+		tMax = min(tMax, t2)
+		if tMin > tMax {
+			return 0, false
+		}
+	}
+	if tMax < 0 {
+		return 0, false
+	}
+	return max(tMin, 0), true
+}
@@ -0,0 +1,60 @@
+package player
+
+import (
+	"mini-mc/internal/entity"
+	"mini-mc/internal/physics"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// A hit deals a bare hand's damage, half again for a critical one, as in
+// 1.8.9.
+const (
+	attackDamage   = 1
+	criticalDamage = 1.5
+)
+
+// hoveredMob returns the mob under the crosshair, if it is within reach and
+// nearer than the hovered block. Mobs falling over after death are skipped.
+func (p *Player) hoveredMob() entity.Damageable {
+	cam := p.Camera()
+	front, eye := cam.Front, cam.Eye
+
+	reach := float32(physics.MaxReachDistance)
+	if result := physics.Raycast(eye, front, physics.MinReachDistance, physics.MaxReachDistance, p.World); result.Hit {
+		reach = result.Distance
+	}
+
+	var nearest entity.Damageable
+	for _, e := range p.World.GetEntities() {
+		mob, ok := e.(entity.Damageable)
+		if !ok || mob.IsDead() || mob.DeathTilt() > 0 {
+			continue
+		}
+		box := mob.AABB()
+		if dist, hit := physics.RayIntersectsAABB(eye, front, box.Min, box.Max); hit && dist <= reach {
+			nearest, reach = mob, dist
+		}
+	}
+	return nearest
+}
+
+// attackMob hits the hovered mob, knocking it away from the player. It
+// reports whether a mob was under the crosshair, even if it was still
+// flashing from the last hit and took no damage.
+func (p *Player) attackMob() bool {
+	mob := p.hoveredMob()
+	if mob == nil {
+		return false
+	}
+	crit := p.isCriticalHit()
+	damage := float32(attackDamage)
+	if crit {
+		damage *= criticalDamage
+	}
+	if mob.Damage(damage, p.Position) && p.OnEntityHit != nil {
+		_, h := mob.GetBounds()
+		p.OnEntityHit(mob.Position().Add(mgl32.Vec3{0, h / 2, 0}), crit)
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package player
+
+import (
+	"mini-mc/internal/blockentity"
+	"mini-mc/internal/entity"
+	"mini-mc/internal/item"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// useBlockEntity handles right-clicking a block that has a block entity.
+func (p *Player) useBlockEntity(be world.BlockEntity) {
+	switch be := be.(type) {
+	case *blockentity.Furnace:
+		p.OpenBlockEntityScreen(be)
+	case *blockentity.ItemFrame:
+		held := p.Inventory.GetCurrentItem()
+		if be.Interact(held) && p.GameMode != GameModeCreative {
+			held.Count--
+			if held.Count <= 0 {
+				p.Inventory.MainInventory[p.Inventory.CurrentItem] = nil
+			}
+		}
+	}
+}
+
+// OpenBlockEntityScreen opens the screen of a block entity (e.g. a furnace)
+// in place of the player inventory.
+func (p *Player) OpenBlockEntityScreen(be world.BlockEntity) {
+	if p.IsInventoryOpen {
+		return
+	}
+	p.OpenBlockEntity = be
+	p.SetInventoryOpen(true)
+}
+
+// placeBlockEntity attaches a fresh block entity to a block the player just
+// placed, if its type has one.
+func (p *Player) placeBlockEntity(x, y, z int, t world.BlockType, meta uint8) {
+	if be := blockentity.New(t, meta); be != nil {
+		p.World.SetBlockEntity(x, y, z, be)
+	}
+}
+
+// breakAttachedFrames breaks item frames hanging on the block at (x, y, z),
+// which was just removed.
+func (p *Player) breakAttachedFrames(x, y, z int) {
+	for _, face := range []world.BlockFace{world.FaceNorth, world.FaceSouth, world.FaceEast, world.FaceWest} {
+		dx, dz := world.FacingOffset(uint8(face))
+		fx, fz := x+dx, z+dz
+		if p.World.Get(fx, y, fz) != world.BlockTypeItemFrame || p.World.GetMeta(fx, y, fz) != uint8(face) {
+			continue
+		}
+		p.World.Set(fx, y, fz, world.BlockTypeAir)
+		if p.OnBlockBreak != nil {
+			p.OnBlockBreak(fx, y, fz, world.BlockTypeItemFrame, uint8(face))
+		}
+		p.removeBlockEntity(fx, y, fz)
+		if p.GameMode != GameModeCreative {
+			p.World.AddEntity(entity.NewItemEntity(p.World, p.World.Rand(), mgl32.Vec3{float32(fx) + 0.5, float32(y) + 0.5, float32(fz) + 0.5},
+				item.NewItemStack(world.BlockTypeItemFrame, 1)))
+		}
+	}
+}
+
+// removeBlockEntity detaches the block entity of a broken block and spills its
+// contents like MC does.
+func (p *Player) removeBlockEntity(x, y, z int) {
+	be := p.World.RemoveBlockEntity(x, y, z)
+	if be == nil {
+		return
+	}
+	if p.OpenBlockEntity == be {
+		p.SetInventoryOpen(false)
+	}
+	dropper, ok := be.(blockentity.Dropper)
+	if !ok {
+		return
+	}
+	rnd := p.World.Rand()
+	for _, stack := range dropper.Drops() {
+		pos := mgl32.Vec3{
+			float32(x) + rnd.Float32()*0.8 + 0.1,
+			float32(y) + rnd.Float32()*0.8 + 0.1,
+			float32(z) + rnd.Float32()*0.8 + 0.1,
+		}
+		p.World.AddEntity(entity.NewItemEntity(p.World, rnd, pos, stack))
+	}
+}
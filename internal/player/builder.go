@@ -0,0 +1,175 @@
+package player
+
+import (
+	"math"
+
+	"mini-mc/internal/world"
+)
+
+// Builder mode is a creative building assist. Holding the place button and
+// dragging fills a line or plane of the held block from where the drag
+// started to the block being looked at when it is let go, and placements
+// can be mirrored across a plane through the block the player stood in
+// when mirroring was turned on. All of it goes through TryPlace.
+
+// MaxBuildFillLength caps how many blocks a drag fills along each axis.
+const MaxBuildFillLength = 32
+
+// MirrorAxis is the axis builder mode mirrors placements along.
+type MirrorAxis int
+
+const (
+	MirrorNone MirrorAxis = iota
+	MirrorX               // across the plane x = origin, east to west
+	MirrorZ               // across the plane z = origin, north to south
+	mirrorAxisCount
+)
+
+// String returns the axis's name for the HUD.
+func (a MirrorAxis) String() string {
+	switch a {
+	case MirrorX:
+		return "X"
+	case MirrorZ:
+		return "Z"
+	}
+	return "off"
+}
+
+// BuilderMode is the state of the builder mode assist.
+type BuilderMode struct {
+	Enabled bool
+	Mirror  MirrorAxis
+	// MirrorOrigin is the block the mirror plane runs through the middle of
+	MirrorOrigin [3]int
+
+	dragging   bool
+	dragStart  BlockPlacement
+	dragNormal [3]int // of the face the drag started on
+}
+
+// BuilderActive reports whether builder mode applies, which it only does
+// in creative.
+func (p *Player) BuilderActive() bool {
+	return p.Builder.Enabled && p.GameMode == GameModeCreative
+}
+
+// ToggleBuilderMode turns builder mode on or off, dropping any drag under
+// way. It stays off outside creative.
+func (p *Player) ToggleBuilderMode() {
+	p.Builder.Enabled = !p.Builder.Enabled && p.GameMode == GameModeCreative
+	p.Builder.dragging = false
+}
+
+// CycleMirrorAxis moves builder mode on to the next mirror axis, putting
+// the mirror plane through the block the player stands in.
+func (p *Player) CycleMirrorAxis() {
+	p.Builder.Mirror = (p.Builder.Mirror + 1) % mirrorAxisCount
+	for i := range 3 {
+		p.Builder.MirrorOrigin[i] = int(math.Floor(float64(p.Position[i])))
+	}
+}
+
+// startBuildDrag starts a fill from pl, placed against a face with the
+// given normal.
+func (p *Player) startBuildDrag(pl BlockPlacement, normal [3]int) {
+	p.Builder.dragging = true
+	p.Builder.dragStart = pl
+	p.Builder.dragNormal = normal
+}
+
+// finishBuildDrag places the blocks of the drag under way, if any.
+func (p *Player) finishBuildDrag() {
+	if !p.Builder.dragging {
+		return
+	}
+	p.Builder.dragging = false
+	if !p.BuilderActive() {
+		return
+	}
+	lo, hi := p.buildFillRegion()
+	var batch []BlockPlacement
+	for x := lo[0]; x <= hi[0]; x++ {
+		for y := lo[1]; y <= hi[1]; y++ {
+			for z := lo[2]; z <= hi[2]; z++ {
+				pl := p.Builder.dragStart
+				pl.X, pl.Y, pl.Z = x, y, z
+				batch = append(batch, pl)
+				if p.Builder.Mirror != MirrorNone {
+					batch = append(batch, p.mirrored(pl))
+				}
+			}
+		}
+	}
+	p.TryPlace(batch)
+}
+
+// buildFillRegion returns the corners of the blocks the drag under way
+// fills: the box from its start to the space in front of the face looked at
+// now, flattened onto the start face's plane when it would be a solid and
+// cut to MaxBuildFillLength along each axis.
+func (p *Player) buildFillRegion() (lo, hi [3]int) {
+	start := [3]int{p.Builder.dragStart.X, p.Builder.dragStart.Y, p.Builder.dragStart.Z}
+	end := start
+	if p.HasHoveredBlock {
+		for i := range 3 {
+			end[i] = p.HoveredBlock[i] + p.HoveredFace[i]
+		}
+	}
+	if end[0] != start[0] && end[1] != start[1] && end[2] != start[2] {
+		for i, n := range p.Builder.dragNormal {
+			if n != 0 {
+				end[i] = start[i]
+			}
+		}
+	}
+	for i := range 3 {
+		end[i] = min(max(end[i], start[i]-MaxBuildFillLength+1), start[i]+MaxBuildFillLength-1)
+		lo[i], hi[i] = min(start[i], end[i]), max(start[i], end[i])
+	}
+	return lo, hi
+}
+
+// mirrored returns pl reflected across the mirror plane, turned to face the
+// other way if it faces along the mirror axis.
+func (p *Player) mirrored(pl BlockPlacement) BlockPlacement {
+	o := p.Builder.MirrorOrigin
+	acrossX := p.Builder.Mirror == MirrorX
+	if acrossX {
+		pl.X = 2*o[0] - pl.X
+	} else {
+		pl.Z = 2*o[2] - pl.Z
+	}
+	if facesHorizontally(pl.State.Type) {
+		pl.State.Meta = world.MirrorFacing(pl.State.Meta, acrossX)
+	}
+	return pl
+}
+
+// BuilderPreview returns the boxes, as inclusive corner blocks, that
+// releasing the place button would fill: the drag's region, or the space in
+// front of the face looked at when not dragging, and its mirror image.
+func (p *Player) BuilderPreview() [][2][3]int {
+	var lo, hi [3]int
+	switch {
+	case p.Builder.dragging:
+		lo, hi = p.buildFillRegion()
+	case p.HasHoveredBlock:
+		for i := range 3 {
+			lo[i] = p.HoveredBlock[i] + p.HoveredFace[i]
+		}
+		hi = lo
+	default:
+		return nil
+	}
+	boxes := [][2][3]int{{lo, hi}}
+	if p.Builder.Mirror != MirrorNone {
+		a := p.mirrored(BlockPlacement{X: lo[0], Y: lo[1], Z: lo[2]})
+		b := p.mirrored(BlockPlacement{X: hi[0], Y: hi[1], Z: hi[2]})
+		boxes = append(boxes, [2][3]int{
+			{min(a.X, b.X), lo[1], min(a.Z, b.Z)},
+			{max(a.X, b.X), hi[1], max(a.Z, b.Z)},
+		})
+	}
+	return boxes
+}
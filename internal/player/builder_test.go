@@ -0,0 +1,127 @@
+package player
+
+import (
+	"testing"
+
+	"mini-mc/internal/config"
+	"mini-mc/internal/item"
+	"mini-mc/internal/world"
+)
+
+func holding(p *Player, t world.BlockType, count int) *item.ItemStack {
+	s := item.NewItemStack(t, count)
+	p.Inventory.MainInventory[p.Inventory.CurrentItem] = &s
+	return &s
+}
+
+func TestTryPlaceChecksBatch(t *testing.T) {
+	p, w := newPickupPlayer(t)
+	stack := holding(p, world.BlockTypeDirt, 3)
+	at := func(x, y, z int) BlockPlacement {
+		return BlockPlacement{X: x, Y: y, Z: z, State: world.BlockState{Type: world.BlockTypeDirt}}
+	}
+
+	batch := []BlockPlacement{
+		at(0, groundY+1, 0), // inside the player
+		at(2, groundY-1, 0), // into the floor
+		at(2, groundY, 0),
+		at(2, groundY, 0), // repeated
+		at(3, groundY, 0),
+		at(4, groundY, 0),
+		at(5, groundY, 0), // out of dirt by now
+	}
+	if n, denied := p.TryPlace(batch); n != 3 || denied != DeniedIntersectsPlayer {
+		t.Fatalf("placed %d blocks, refused as %v, want 3, intersects player", n, denied)
+	}
+	for x := 2; x <= 5; x++ {
+		if got, want := w.Get(x, groundY, 0) == world.BlockTypeDirt, x < 5; got != want {
+			t.Errorf("dirt at x=%d: %v, want %v", x, got, want)
+		}
+	}
+	if w.Get(0, groundY+1, 0) != world.BlockTypeAir || stack.Count != 0 || p.Inventory.GetCurrentItem() != nil {
+		t.Errorf("player's block filled or dirt left over: %d", stack.Count)
+	}
+}
+
+func TestTryPlaceReportsDenial(t *testing.T) {
+	p, w := newPickupPlayer(t)
+	holding(p, world.BlockTypeDirt, 5)
+	var got []PlaceDenial
+	p.OnPlaceDenied = func(x, y, z int, reason PlaceDenial) { got = append(got, reason) }
+	at := func(x, y, z int, bt world.BlockType) BlockPlacement {
+		return BlockPlacement{X: x, Y: y, Z: z, State: world.BlockState{Type: bt}}
+	}
+
+	for _, tc := range []struct {
+		pl   BlockPlacement
+		want PlaceDenial
+	}{
+		{at(2, -1, 0, world.BlockTypeDirt), DeniedOutOfBounds},
+		{at(2, groundY-1, 0, world.BlockTypeDirt), DeniedOccupied},
+		{at(2, groundY+5, 0, world.BlockTypeRail), DeniedNoSupport},
+		{at(0, groundY+1, 0, world.BlockTypeDirt), DeniedIntersectsPlayer},
+	} {
+		if n, denied := p.TryPlace([]BlockPlacement{tc.pl}); n != 0 || denied != tc.want {
+			t.Errorf("placing %v: placed %d, refused as %v, want %v", tc.pl, n, denied, tc.want)
+		}
+		if p.DeniedBlock != [3]int{tc.pl.X, tc.pl.Y, tc.pl.Z} || p.PlaceDeniedFlash() != 1 {
+			t.Errorf("placing %v: flash at %v (%v)", tc.pl, p.DeniedBlock, p.PlaceDeniedFlash())
+		}
+	}
+	if len(got) != 4 {
+		t.Errorf("OnPlaceDenied fired %d times, want 4", len(got))
+	}
+
+	// A batch that places something does not flash
+	got = nil
+	p.deniedTimer = 0
+	batch := []BlockPlacement{at(2, groundY-1, 0, world.BlockTypeDirt), at(2, groundY, 0, world.BlockTypeDirt)}
+	if n, denied := p.TryPlace(batch); n != 1 || denied != DeniedOccupied || len(got) != 0 || p.PlaceDeniedFlash() != 0 {
+		t.Errorf("partial batch: placed %d, refused as %v, %d denials", n, denied, len(got))
+	}
+	if w.Get(2, groundY, 0) != world.BlockTypeDirt {
+		t.Error("dirt not placed")
+	}
+
+	// With flashing disabled a refusal still fires but the outline stays
+	prev := config.GetDisableFlashing()
+	config.SetDisableFlashing(true)
+	defer config.SetDisableFlashing(prev)
+	if _, denied := p.TryPlace([]BlockPlacement{at(2, -1, 0, world.BlockTypeDirt)}); denied != DeniedOutOfBounds || len(got) != 1 || p.PlaceDeniedFlash() != 0 {
+		t.Errorf("flashing disabled: refused as %v, %d denials, flash %v", denied, len(got), p.PlaceDeniedFlash())
+	}
+}
+
+func TestBuilderDragFillsMirroredLine(t *testing.T) {
+	p, w := newPickupPlayer(t)
+	p.GameMode = GameModeCreative
+	holding(p, world.BlockTypeItemFrame, 1)
+	p.ToggleBuilderMode()
+	p.CycleMirrorAxis() // across x = 0, through the player
+
+	// Drag along the floor from (2, 0) to (2, 3)
+	start := BlockPlacement{X: 2, Y: groundY, Z: 0, State: world.BlockState{Type: world.BlockTypeItemFrame, Meta: uint8(world.FaceEast)}}
+	p.startBuildDrag(start, [3]int{0, 1, 0})
+	p.HasHoveredBlock, p.HoveredBlock, p.HoveredFace = true, [3]int{2, groundY - 1, 3}, [3]int{0, 1, 0}
+	if got := p.BuilderPreview(); len(got) != 2 || got[1] != [2][3]int{{-2, groundY, 0}, {-2, groundY, 3}} {
+		t.Errorf("preview = %v", got)
+	}
+	p.finishBuildDrag()
+
+	for z := range 4 {
+		if st := w.GetState(2, groundY, z); st.Type != world.BlockTypeItemFrame || st.Meta != uint8(world.FaceEast) {
+			t.Errorf("block at (2, %d) = %v", z, st)
+		}
+		if st := w.GetState(-2, groundY, z); st.Type != world.BlockTypeItemFrame || st.Meta != uint8(world.FaceWest) {
+			t.Errorf("mirrored block at (-2, %d) = %v", z, st)
+		}
+	}
+	if p.Inventory.GetCurrentItem() == nil {
+		t.Error("creative builder mode used up the held stack")
+	}
+
+	p.GameMode = GameModeSurvival
+	if p.BuilderActive() {
+		t.Error("builder mode applies in survival")
+	}
+}
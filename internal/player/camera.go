@@ -21,7 +21,7 @@ func (p *Player) HandleMouseMovement(w *glfw.Window, xpos, ypos float64) {
 	p.LastMouseX = xpos
 	p.LastMouseY = ypos
 
-	sensitivity := 0.1
+	sensitivity := float64(config.GetMouseSensitivity())
 	xoffset *= sensitivity
 	yoffset *= sensitivity
 
@@ -69,7 +69,7 @@ func (p *Player) UpdateCameraBob() {
 	p.PrevCameraYaw = p.CameraYaw
 	p.PrevCameraPitch = p.CameraPitch
 
-	if !config.GetViewBobbing() {
+	if !config.IsViewBobbingActive() {
 		p.CameraYaw += (0.0 - p.CameraYaw) * 0.1
 		p.CameraPitch += (0.0 - p.CameraPitch) * 0.1
 		return
@@ -115,18 +115,53 @@ func (p *Player) GetFrontVector() mgl32.Vec3 {
 	return mgl32.Vec3{fx, fy, fz}.Normalize()
 }
 
-func (p *Player) GetViewMatrix() mgl32.Mat4 {
-	return p.GetViewMatrixWithPartialTicks(0.0)
+// CameraSnapshot is the camera of one frame: where the eye is between the
+// last two game ticks and where it looks. The renderer draws from it and
+// the block interaction rays start from it, so the highlighted block is the
+// one under the crosshair on screen rather than at the latest tick.
+type CameraSnapshot struct {
+	Eye         mgl32.Vec3
+	Front       mgl32.Vec3 // unit length
+	PartialTick float32
+}
+
+// Camera returns the camera for the frame, at PartialTick.
+func (p *Player) Camera() CameraSnapshot {
+	return p.cameraAt(p.PartialTick)
+}
+
+// cameraAt returns the camera partialTicks of the way from the last tick to
+// this one.
+func (p *Player) cameraAt(partialTicks float32) CameraSnapshot {
+	return CameraSnapshot{
+		Eye:         p.positionAt(partialTicks).Add(mgl32.Vec3{0, p.eyeHeight(), 0}),
+		Front:       p.GetFrontVector(),
+		PartialTick: partialTicks,
+	}
+}
+
+// SetPartialTick sets how far the frame about to be drawn is between game
+// ticks, and aims the block highlight from that frame's camera.
+func (p *Player) SetPartialTick(partialTicks float32) {
+	p.PartialTick = partialTicks
+	if p.IsInventoryOpen {
+		p.HasHoveredBlock = false
+	} else {
+		p.UpdateHoveredBlock()
+	}
 }
 
-func (p *Player) GetViewMatrixWithPartialTicks(partialTicks float32) mgl32.Mat4 {
-	eyePos := p.GetEyePosition()
-	front := p.GetFrontVector()
-	target := eyePos.Add(front)
+// GetViewMatrix returns the camera's view for the frame, at PartialTick.
+func (p *Player) GetViewMatrix() mgl32.Mat4 {
+	return p.ViewMatrix(p.Camera())
+}
 
-	viewMatrix := mgl32.LookAtV(eyePos, target, mgl32.Vec3{0, 1, 0})
+// ViewMatrix returns the view from cam, with view bobbing when it is on.
+func (p *Player) ViewMatrix(cam CameraSnapshot) mgl32.Mat4 {
+	partialTicks := cam.PartialTick
+	viewMatrix := mgl32.LookAtV(cam.Eye, cam.Eye.Add(cam.Front), mgl32.Vec3{0, 1, 0})
 
-	if !config.GetViewBobbing() {
+	if !config.IsViewBobbingActive() {
 		return viewMatrix
 	}
 
@@ -165,17 +200,22 @@ func (p *Player) GetHandEquipProgress() float32 {
 	return p.EquipProgress
 }
 
+// updateEquippedItem drives the equip animation like MC's
+// ItemRenderer.updateEquippedItem: whenever the selected slot or the stack in
+// it changes (including the stack running out), the hand dips down, the shown
+// item is swapped at the bottom, and the hand comes back up.
 func (p *Player) updateEquippedItem(dt float32) {
 	itemstack := p.Inventory.GetCurrentItem()
-	flag := false
+	if itemstack != nil && itemstack.Count <= 0 {
+		itemstack = nil
+	}
 
+	flag := p.EquippedSlot != p.Inventory.CurrentItem
 	if p.EquippedItem != nil && itemstack != nil {
-		if p.EquippedItem.Type != itemstack.Type {
+		if !p.EquippedItem.IsItemEqual(*itemstack) {
 			flag = true
 		}
-	} else if p.EquippedItem == nil && itemstack == nil {
-		flag = false
-	} else {
+	} else if p.EquippedItem != nil || itemstack != nil {
 		flag = true
 	}
 
@@ -197,5 +237,6 @@ func (p *Player) updateEquippedItem(dt float32) {
 
 	if p.EquipProgress < 0.1 {
 		p.EquippedItem = itemstack
+		p.EquippedSlot = p.Inventory.CurrentItem
 	}
 }
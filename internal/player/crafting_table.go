@@ -0,0 +1,36 @@
+package player
+
+import (
+	"mini-mc/internal/inventory"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// workbenchReach is how far, in blocks from its centre, the player may walk
+// from a crafting table before its screen closes, as in MC.
+const workbenchReach = 8
+
+// OpenWorkbench opens the 3x3 crafting grid of the crafting table at (x, y, z)
+// in place of the player inventory.
+func (p *Player) OpenWorkbench(x, y, z int) {
+	if p.IsInventoryOpen {
+		return
+	}
+	p.Workbench = inventory.NewCraftingGrid()
+	p.WorkbenchPos = [3]int{x, y, z}
+	p.SetInventoryOpen(true)
+}
+
+// tickWorkbench closes the crafting table screen once the table is gone or
+// the player has moved out of its reach.
+func (p *Player) tickWorkbench() {
+	if p.Workbench == nil {
+		return
+	}
+	x, y, z := p.WorkbenchPos[0], p.WorkbenchPos[1], p.WorkbenchPos[2]
+	centre := mgl32.Vec3{float32(x) + 0.5, float32(y) + 0.5, float32(z) + 0.5}
+	if p.World.Get(x, y, z) != world.BlockTypeCraftingTable || p.Position.Sub(centre).Len() > workbenchReach {
+		p.SetInventoryOpen(false)
+	}
+}
@@ -0,0 +1,51 @@
+package player
+
+import (
+	"testing"
+
+	"mini-mc/internal/item"
+	"mini-mc/internal/world"
+)
+
+// equipDips steps the equip animation until it settles and reports whether
+// the hand went down on the way.
+func equipDips(p *Player) bool {
+	dipped := false
+	for range 60 {
+		p.updateEquippedItem(1.0 / 60)
+		dipped = dipped || p.EquipProgress < 0.1
+	}
+	return dipped
+}
+
+func TestEquipAnimation(t *testing.T) {
+	w := world.NewEmpty()
+	defer w.Close()
+	p := New(w, GameModeSurvival)
+	for i := range 2 {
+		s := item.NewItemStack(world.BlockTypeStone, 10)
+		p.Inventory.MainInventory[i] = &s
+	}
+	equipDips(p)
+	if p.EquipProgress != 1 || p.EquippedItem != p.Inventory.MainInventory[0] {
+		t.Fatalf("hand not raised with slot 0 stack: progress %v", p.EquipProgress)
+	}
+
+	// Using part of the stack does not re-equip
+	p.Inventory.MainInventory[0].Count--
+	if equipDips(p) {
+		t.Error("hand dipped when the held stack shrank")
+	}
+
+	// Switching to another slot holding the same item still dips
+	p.Inventory.SetCurrentItem(1)
+	if !equipDips(p) || p.EquippedItem != p.Inventory.MainInventory[1] || p.EquipProgress != 1 {
+		t.Error("switching to a slot with the same item did not re-equip")
+	}
+
+	// Using up the stack dips and comes back empty-handed
+	p.Inventory.MainInventory[1] = nil
+	if !equipDips(p) || p.EquippedItem != nil || p.EquipProgress != 1 {
+		t.Error("consuming the held stack did not re-equip to an empty hand")
+	}
+}
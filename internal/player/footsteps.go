@@ -0,0 +1,91 @@
+package player
+
+import (
+	"math"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Footsteps follow 1.8.9's Entity.moveEntity: one each time
+// DistanceWalkedModified passes a whole number, so a step every 1/0.6
+// blocks walked, none while sneaking. Jumping and landing from a fall of
+// more than landStepFall blocks also step; a landing that hurts is louder
+// and lower, as in EntityLivingBase.fall.
+const (
+	stepVolume   = 0.15
+	landStepFall = 1.0
+	hardLandGain = 0.5
+	hardLandTone = 0.75
+)
+
+// tickFootsteps steps whenever the walked distance reaches the next whole
+// number.
+func (p *Player) tickFootsteps() {
+	if !p.OnGround || p.IsSneaking || p.IsFlying || p.IsInWater() {
+		return
+	}
+	if p.DistanceWalkedModified > float64(p.nextStepDistance) {
+		p.nextStepDistance = int(p.DistanceWalkedModified) + 1
+		p.step(stepVolume, 1)
+	}
+}
+
+// step reports a footfall on the block under the player, if any, for its
+// sound; volume and pitch scale the block's step sound.
+func (p *Player) step(volume, pitch float32) {
+	if p.OnStep == nil {
+		return
+	}
+	if block, pos, ok := p.groundBlock(); ok {
+		p.OnStep(pos, block, volume, pitch)
+	}
+}
+
+// land reports a landing after a fall of distance blocks, for the dust it
+// raises, then takes any fall damage.
+func (p *Player) land(distance float32) {
+	if block, pos, ok := p.groundBlock(); ok && p.OnLand != nil {
+		p.OnLand(pos, block, distance)
+	}
+	p.Fall(distance, 1.0)
+}
+
+// tickSplash reports the player falling into water, once as they enter it.
+func (p *Player) tickSplash() {
+	inWater := p.IsInWater()
+	if inWater && !p.inWaterLastTick && p.OnSplash != nil {
+		y := float32(math.Floor(float64(p.Position.Y()))) + 1
+		p.OnSplash(mgl32.Vec3{p.Position.X(), y, p.Position.Z()}, p.Velocity)
+	}
+	p.inWaterLastTick = inWater
+}
+
+// tickSprintDust kicks up dust from the ground every tick the player sprints
+// on it.
+func (p *Player) tickSprintDust() {
+	if !p.IsSprinting || !p.OnGround || p.IsFlying || p.IsInWater() || p.OnSprintDust == nil {
+		return
+	}
+	if block, pos, ok := p.groundBlock(); ok {
+		p.OnSprintDust(pos, p.Velocity, block)
+	}
+}
+
+// groundBlock returns the block the player stands on and the point under
+// their feet on its top, or ok false in the air.
+func (p *Player) groundBlock() (block world.BlockType, pos mgl32.Vec3, ok bool) {
+	x := int(math.Floor(float64(p.Position.X())))
+	y := int(math.Floor(float64(p.Position.Y()) - 0.2))
+	z := int(math.Floor(float64(p.Position.Z())))
+	block = p.World.Get(x, y, z)
+	// Snow lying on a block is what is trodden on
+	if p.World.Get(x, y+1, z) == world.BlockTypeSnowLayer {
+		block = world.BlockTypeSnowLayer
+	}
+	if block == world.BlockTypeAir {
+		return block, pos, false
+	}
+	return block, mgl32.Vec3{p.Position.X(), float32(y) + 1, p.Position.Z()}, true
+}
@@ -0,0 +1,194 @@
+package player
+
+import (
+	"math"
+
+	"mini-mc/internal/entity"
+	"mini-mc/internal/inventory"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Hunger follows 1.8.9's FoodStats: what the player does adds exhaustion,
+// every exhaustionPerPoint of which uses up a point of saturation, or of
+// food once saturation is gone. Every foodTickInterval ticks a player fed
+// to regenFoodLevel heals half a heart, if the naturalRegeneration rule
+// allows, and a starving one loses half a heart, down to starveFloor.
+const (
+	exhaustionPerPoint = 4.0
+	foodTickInterval   = 80
+	regenFoodLevel     = 18
+	starveFloor        = 1 // health starving leaves, as on normal difficulty
+
+	// Exhaustion for each action
+	exhaustSprint     = 0.1 // per block
+	exhaustJump       = 0.2
+	exhaustSprintJump = 0.8
+	exhaustBreak      = 0.025
+	exhaustDamage     = 0.3
+	exhaustRegen      = 3.0
+
+	// Below voidDepth the void hurts by voidDamage each tick, in any mode
+	voidDepth  = -64
+	voidDamage = 4
+
+	// hurtCooldownTicks is how long after a hit further damage only counts
+	// for what it exceeds that hit by; the hearts flash meanwhile.
+	hurtCooldownTicks = 10
+)
+
+// ApplyDamage hurts a survival player by amount, in half hearts.
+func (p *Player) ApplyDamage(amount float32) {
+	if p.GameMode == GameModeCreative {
+		return
+	}
+	p.hurt(amount)
+}
+
+// hurt takes amount off the player's health, less whatever a hit in the
+// last hurtCooldownTicks already took, and kills them at zero.
+func (p *Player) hurt(amount float32) {
+	if p.IsDead() || amount <= 0 {
+		return
+	}
+	if p.HurtTicks > 0 {
+		if amount <= p.lastDamage {
+			return
+		}
+		amount, p.lastDamage = amount-p.lastDamage, amount
+	} else {
+		p.lastDamage = amount
+		p.HurtTicks = hurtCooldownTicks
+	}
+	p.AddExhaustion(exhaustDamage)
+	p.Health = max(p.Health-amount, 0)
+	if p.Health == 0 {
+		p.die()
+	}
+}
+
+// IsDead reports whether the player has died and not yet respawned.
+func (p *Player) IsDead() bool {
+	return p.Health <= 0
+}
+
+// die stops whatever the player was doing and, unless the keepInventory
+// rule is on, scatters their items where they fell.
+func (p *Player) die() {
+	p.Dismount()
+	if p.IsInventoryOpen {
+		p.SetInventoryOpen(false)
+	}
+	p.stopSprinting()
+	p.IsFlying = false
+	p.ResetMining()
+	p.Velocity = mgl32.Vec3{}
+	if !p.gameRule("keepInventory") {
+		p.dropInventory()
+	}
+}
+
+// dropInventory throws every stack the player carries out around them.
+func (p *Player) dropInventory() {
+	inv := p.Inventory
+	if inv == nil || p.World == nil {
+		return
+	}
+	stacks := inv.ClearCraftingMatrix()
+	if inv.CursorStack != nil {
+		stacks = append(stacks, *inv.CursorStack)
+		inv.CursorStack = nil
+	}
+	for i := range inventory.MainInventorySize + inventory.ArmorInventorySize {
+		if s := inv.GetItem(i); s != nil {
+			stacks = append(stacks, *s)
+			inv.SetItem(i, nil)
+		}
+	}
+	rnd := p.World.Rand()
+	pos := p.GetEyePosition().Add(mgl32.Vec3{0, -0.3, 0})
+	for _, stack := range stacks {
+		drop := entity.NewItemEntity(p.World, rnd, pos, stack)
+		angle := rnd.Float64() * 2 * math.Pi
+		speed := rnd.Float64() * 2
+		drop.Vel = mgl32.Vec3{float32(math.Cos(angle) * speed), 4, float32(math.Sin(angle) * speed)}
+		drop.PickupDelay = 2
+		p.World.AddEntity(drop)
+	}
+	p.EquippedItem = nil
+}
+
+// Revive brings a dead player back to life, healed and fed, where they
+// fell; the caller moves them to their spawn point.
+func (p *Player) Revive() {
+	p.Health = p.MaxHealth
+	p.FoodLevel = p.MaxFoodLevel
+	p.FoodSaturation = 5
+	p.foodExhaustion = 0
+	p.foodTimer = 0
+	p.HurtTicks = 0
+	p.FallDistance = 0
+}
+
+// AddExhaustion adds to the exhaustion that wears down saturation and food.
+// Creative players never tire.
+func (p *Player) AddExhaustion(amount float32) {
+	if p.GameMode == GameModeCreative {
+		return
+	}
+	p.foodExhaustion = min(p.foodExhaustion+amount, 40)
+}
+
+// tickHealth runs one game tick of hunger, healing, starving and the void.
+func (p *Player) tickHealth() {
+	if p.HurtTicks > 0 {
+		p.HurtTicks--
+	}
+	if p.Position.Y() < voidDepth {
+		p.hurt(voidDamage)
+	}
+	if p.IsDead() || p.GameMode == GameModeCreative {
+		return
+	}
+
+	if p.foodExhaustion > exhaustionPerPoint {
+		p.foodExhaustion -= exhaustionPerPoint
+		if p.FoodSaturation > 0 {
+			p.FoodSaturation = max(p.FoodSaturation-1, 0)
+		} else {
+			p.FoodLevel = max(p.FoodLevel-1, 0)
+		}
+	}
+
+	switch {
+	case p.FoodLevel >= regenFoodLevel && p.Health < p.MaxHealth && p.gameRule("naturalRegeneration"):
+		p.foodTimer++
+		if p.foodTimer >= foodTickInterval {
+			p.Health = min(p.Health+1, p.MaxHealth)
+			p.AddExhaustion(exhaustRegen)
+			p.foodTimer = 0
+		}
+	case p.FoodLevel <= 0:
+		p.foodTimer++
+		if p.foodTimer >= foodTickInterval {
+			if p.Health > starveFloor {
+				p.hurt(1)
+			}
+			p.foodTimer = 0
+		}
+	default:
+		p.foodTimer = 0
+	}
+}
+
+// gameRule returns a boolean game rule of the player's world, or its
+// default in a world without level metadata, such as a server's.
+func (p *Player) gameRule(name string) bool {
+	if p.World != nil {
+		if level := p.World.Level(); level != nil {
+			return level.GameRuleBool(name)
+		}
+	}
+	return world.DefaultGameRules[name] == "true"
+}
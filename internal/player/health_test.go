@@ -0,0 +1,86 @@
+package player
+
+import (
+	"testing"
+
+	"mini-mc/internal/item"
+	"mini-mc/internal/world"
+)
+
+func TestDamageCooldownAndDeath(t *testing.T) {
+	p, w := newPickupPlayer(t)
+	dirt := item.NewItemStack(world.BlockTypeDirt, 10)
+	p.Inventory.MainInventory[3] = &dirt
+
+	p.ApplyDamage(4)
+	p.ApplyDamage(3) // within the cooldown and weaker: ignored
+	p.ApplyDamage(6) // stronger: only the excess counts
+	if p.Health != 14 {
+		t.Fatalf("health %v after hits in one cooldown, want 14", p.Health)
+	}
+	for range hurtCooldownTicks {
+		p.tickHealth()
+	}
+	p.ApplyDamage(3)
+	if p.Health != 11 {
+		t.Fatalf("health %v after the cooldown, want 11", p.Health)
+	}
+
+	p.HurtTicks = 0
+	p.ApplyDamage(40)
+	if !p.IsDead() || p.Health != 0 {
+		t.Fatalf("health %v after a fatal hit", p.Health)
+	}
+	if p.Inventory.MainInventory[3] != nil || len(w.GetEntities()) != 1 {
+		t.Fatalf("inventory not dropped: slot %v, %d entities", p.Inventory.MainInventory[3], len(w.GetEntities()))
+	}
+
+	p.Revive()
+	if p.IsDead() || p.Health != p.MaxHealth || p.FoodLevel != p.MaxFoodLevel {
+		t.Fatalf("revived with health %v, food %v", p.Health, p.FoodLevel)
+	}
+}
+
+func TestHungerRegenAndStarving(t *testing.T) {
+	p, _ := newPickupPlayer(t)
+	p.FoodSaturation = 0
+	p.AddExhaustion(exhaustionPerPoint + 0.5)
+	p.tickHealth()
+	if p.FoodLevel != 19 {
+		t.Fatalf("food %v after exhaustion, want 19", p.FoodLevel)
+	}
+
+	// Fed players heal half a heart every foodTickInterval ticks
+	p.Health = 10
+	for range foodTickInterval {
+		p.tickHealth()
+	}
+	if p.Health != 11 {
+		t.Fatalf("health %v after regenerating, want 11", p.Health)
+	}
+
+	// Starving takes half a heart at a time, down to starveFloor
+	p.FoodLevel, p.Health = 0, 2
+	for range 3 * foodTickInterval {
+		p.tickHealth()
+	}
+	if p.Health != starveFloor {
+		t.Fatalf("health %v after starving, want %v", p.Health, starveFloor)
+	}
+}
+
+func TestVoidKillsInCreative(t *testing.T) {
+	p, _ := newPickupPlayer(t)
+	p.GameMode = GameModeCreative
+	p.ApplyDamage(10)
+	if p.Health != p.MaxHealth {
+		t.Fatal("creative player took damage")
+	}
+	p.Position[1] = voidDepth - 10
+	for range 50 {
+		p.tickHealth()
+	}
+	if !p.IsDead() {
+		t.Fatalf("creative player survived the void with %v health", p.Health)
+	}
+}
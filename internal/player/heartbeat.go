@@ -0,0 +1,76 @@
+package player
+
+import (
+	"math"
+
+	"mini-mc/internal/config"
+)
+
+const (
+	LowHealthThreshold = 6.0 // 3 hearts
+
+	heartbeatSlowInterval = 1.1  // seconds between beats at the threshold
+	heartbeatFastInterval = 0.55 // seconds between beats at half a heart
+	heartbeatPulseLength  = 0.35 // seconds a beat's pulse takes to fade out
+)
+
+// updateHeartbeat advances the low-health heartbeat. While health is below
+// LowHealthThreshold it emits a beat at a rate that rises as health falls;
+// each beat fires OnHeartbeat (the hook for the heartbeat sound) and restarts
+// the pulse envelope read by HeartbeatPulse, so visuals and sound stay in step.
+func (p *Player) updateHeartbeat(dt float64) {
+	if p.heartbeatPulseTime >= 0 {
+		p.heartbeatPulseTime += dt
+		if p.heartbeatPulseTime > heartbeatPulseLength {
+			p.heartbeatPulseTime = -1
+		}
+	}
+
+	if !p.IsLowHealth() || !config.GetLowHealthEffect() {
+		p.heartbeatTimer = 0
+		return
+	}
+
+	p.heartbeatTimer -= dt
+	if p.heartbeatTimer > 0 {
+		return
+	}
+
+	// Lerp the interval from slow (at the threshold) to fast (at 1 HP).
+	t := 1.0 - float64(p.Health-1)/(LowHealthThreshold-1)
+	t = math.Max(0, math.Min(1, t))
+	p.heartbeatTimer = heartbeatSlowInterval + (heartbeatFastInterval-heartbeatSlowInterval)*t
+	p.heartbeatPulseTime = 0
+
+	if p.OnHeartbeat != nil {
+		p.OnHeartbeat(p.LowHealthIntensity())
+	}
+}
+
+// IsLowHealth reports whether the player is alive, in survival, and below LowHealthThreshold.
+func (p *Player) IsLowHealth() bool {
+	return p.GameMode != GameModeCreative && p.Health > 0 && p.Health < LowHealthThreshold
+}
+
+// LowHealthIntensity returns 0 at or above LowHealthThreshold, rising to 1 at zero health.
+func (p *Player) LowHealthIntensity() float32 {
+	if !p.IsLowHealth() {
+		return 0
+	}
+	return 1 - p.Health/LowHealthThreshold
+}
+
+// HeartbeatPulse returns the current beat envelope in [0, 1]: a fast attack
+// followed by a decay over heartbeatPulseLength. Zero between beats or when
+// the low-health effect is disabled.
+func (p *Player) HeartbeatPulse() float32 {
+	if p.heartbeatPulseTime < 0 || !config.GetLowHealthEffect() {
+		return 0
+	}
+	t := p.heartbeatPulseTime / heartbeatPulseLength
+	const attack = 0.15
+	if t < attack {
+		return float32(t / attack)
+	}
+	return float32(1 - (t-attack)/(1-attack))
+}
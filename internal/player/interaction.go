@@ -5,6 +5,7 @@ import (
 	"mini-mc/internal/item"
 	"mini-mc/internal/physics"
 	"mini-mc/internal/profiling"
+	"mini-mc/internal/registry"
 	"mini-mc/internal/world"
 
 	"github.com/go-gl/glfw/v3.3/glfw"
@@ -12,46 +13,65 @@ import (
 )
 
 func (p *Player) HandleMouseButton(button glfw.MouseButton, action glfw.Action) {
+	// A builder mode drag fills its blocks when the button is let go
+	if action == glfw.Release && button == glfw.MouseButtonRight {
+		p.finishBuildDrag()
+		return
+	}
+	// Vehicles are entities, so they are handled before any block is targeted
+	if action == glfw.Press && button == glfw.MouseButtonRight && p.useVehicle() {
+		return
+	}
+	if action == glfw.Press && button == glfw.MouseButtonRight && !p.HasHoveredBlock {
+		p.checkPlaceReach()
+		return
+	}
 	if action == glfw.Press && p.HasHoveredBlock {
 		if button == glfw.MouseButtonLeft {
 			// Left click logic moved to Update for continuous breaking
 		}
 		if button == glfw.MouseButtonRight {
 			// Place block
-			front := p.GetFrontVector()
-			rayStart := p.GetEyePosition()
-			result := physics.Raycast(rayStart, front, physics.MinReachDistance, physics.MaxReachDistance, p.World)
+			cam := p.Camera()
+			front := cam.Front
+			result := physics.Raycast(cam.Eye, front, physics.MinReachDistance, physics.MaxReachDistance, p.World)
 			if result.Hit {
+				// Right-clicking a block entity (furnace, item frame) or a crafting
+				// table uses it unless sneaking
+				hx, hy, hz := result.HitPosition[0], result.HitPosition[1], result.HitPosition[2]
+				if be := p.World.BlockEntityAt(hx, hy, hz); be != nil && !p.IsSneaking {
+					p.TriggerHandSwing()
+					p.useBlockEntity(be)
+					return
+				}
+				if p.World.Get(hx, hy, hz) == world.BlockTypeCraftingTable && !p.IsSneaking {
+					p.TriggerHandSwing()
+					p.OpenWorkbench(hx, hy, hz)
+					return
+				}
+
 				// Get selected item from inventory
 				selectedStack := p.Inventory.GetCurrentItem()
-				if selectedStack != nil && selectedStack.Count > 0 && selectedStack.Type != world.BlockTypeAir {
-					// Clamp Y placement into [0,255]
-					if result.AdjacentPosition[1] >= 0 && result.AdjacentPosition[1] <= 255 {
-						ax, ay, az := result.AdjacentPosition[0], result.AdjacentPosition[1], result.AdjacentPosition[2]
-						// Allow placement if empty and either not intersecting player
-						// or the block's top is at/below the player's feet (pillar-up case)
-						targetTop := float32(ay)
-						placingUnderFeet := targetTop <= p.Position[1]+0.001
-						width, height := p.GetBounds()
-						if p.World.IsAir(ax, ay, az) && (placingUnderFeet || !physics.IntersectsBlock(p.Position, width, height, ax, ay, az)) {
-							// Place the selected block type
-							p.World.Set(ax, ay, az, selectedStack.Type)
-							p.World.NotifyNeighbors(ax, ay, az)
-							// Schedule initial tick for fluid blocks so they begin flowing
-							if selectedStack.Type == world.BlockTypeWater {
-								p.World.ScheduleBlockTick(ax, ay, az, world.WaterTickRate, 0)
-							} else if selectedStack.Type == world.BlockTypeLava {
-								p.World.ScheduleBlockTick(ax, ay, az, world.LavaTickRate, 0)
-							}
-							p.TriggerHandSwing()
-							// Consume item if not in creative mode
-							if p.GameMode != GameModeCreative {
-								selectedStack.Count--
-								if selectedStack.Count <= 0 {
-									p.Inventory.MainInventory[p.Inventory.CurrentItem] = nil
-								}
+				if selectedStack != nil && selectedStack.Count > 0 && selectedStack.Type != world.BlockTypeAir && !isHeldOnlyItem(selectedStack.Type) {
+					if selectedStack.Type == world.BlockTypeSnowLayer && p.World.StackSnowLayer(hx, hy, hz) {
+						// Clicking a partial snow layer with snow thickens it instead of placing a new block
+						p.TriggerHandSwing()
+						if p.OnBlockPlace != nil {
+							st := p.World.GetState(hx, hy, hz)
+							p.OnBlockPlace(hx, hy, hz, st.Type, st.Meta)
+						}
+						if p.GameMode != GameModeCreative {
+							selectedStack.Count--
+							if selectedStack.Count <= 0 {
+								p.Inventory.MainInventory[p.Inventory.CurrentItem] = nil
 							}
 						}
+					} else if pl, ok := placementFor(selectedStack.Type, result.HitPosition, result.AdjacentPosition, front); ok {
+						if p.BuilderActive() {
+							p.startBuildDrag(pl, [3]int{pl.X - hx, pl.Y - hy, pl.Z - hz})
+						} else {
+							p.TryPlace([]BlockPlacement{pl})
+						}
 					}
 				}
 			}
@@ -59,6 +79,12 @@ func (p *Player) HandleMouseButton(button glfw.MouseButton, action glfw.Action)
 	}
 }
 
+// isHeldOnlyItem reports whether t is an item (e.g. a tool) that cannot be placed.
+func isHeldOnlyItem(t world.BlockType) bool {
+	def := registry.BlockDefs[t]
+	return def != nil && def.IsItem
+}
+
 func (p *Player) HandleScroll(yoff float64) {
 	// Scroll to change inventory slot
 	// yoff > 0 is up, yoff < 0 is down
@@ -75,92 +101,89 @@ func (p *Player) HandleNumKey(slot int) {
 	}
 }
 
+// Pickup box around the player's feet position. It is far larger than the
+// player's own 0.6x1.8 box so items are easy to walk over, and reaches a
+// block above and below to catch items on steps and in holes.
+const (
+	pickupHalfWidth = 1.0
+	pickupBelow     = 1.0
+	pickupAbove     = PlayerHeight + 1.0
+)
+
+// CheckEntityCollisions sweeps the item entities around the player once per
+// update: items within entity.MagnetRadius that the inventory has room for
+// are pulled in, and items inside the pickup box go into the inventory. A
+// stack that only partly fits stays in the world with the leftover count.
 func (p *Player) CheckEntityCollisions(dt float64) {
 	defer profiling.Track("player.Update.collisionChecks.total")()
-	// Minecraft-style pickup: only when player collides with item
-	// No magnet effect - items don't move towards player
-	entities := p.World.GetEntities()
-
-	// Player collision box: 0.6 width, 1.8 height
-	// Item collision box: 0.25x0.25x0.25
-	// Check if item is within pickup range (based on collision boxes)
-	// Using larger collision box for easier pickup
-	playerPos := p.Position
-	playerHalfWidth := float32(1.0) // Half of 2.0 (very large for easier pickup)
-	playerHeight := float32(1.8)
-	itemHalfSize := float32(0.125) // Half of 0.25
-
-	for _, e := range entities {
-		if itemEnt, ok := e.(*entity.ItemEntity); ok {
-			if itemEnt.IsDead() {
-				continue
-			}
 
-			// Check pickup delay (must be 0 or less)
-			if itemEnt.PickupDelay > 0 {
-				continue
+	pos := mgl32.Vec3(p.Position)
+	reach := float32(max(pickupHalfWidth, entity.MagnetRadius))
+	centre := pos.Add(mgl32.Vec3{0, (pickupAbove - pickupBelow) / 2, 0})
+	halfHeight := float32(pickupAbove+pickupBelow)/2 + entity.MagnetRadius
+	nearby := p.World.GetNearbyEntities(centre.X(), centre.Y(), centre.Z(), reach, halfHeight, reach)
+
+	for _, e := range nearby {
+		itemEnt, ok := e.(*entity.ItemEntity)
+		if !ok {
+			if b, ok := e.(world.Body); ok {
+				p.pushAgainst(b, dt)
 			}
+			continue
+		}
+		if !itemEnt.CanBePickedUp() {
+			continue
+		}
+		itemPos := itemEnt.Position()
 
-			// Owner check: if item has owner, only allow pickup if:
-			// - owner is empty (no owner)
-			// - age >= 5800 (200 ticks before despawn at 6000)
-			// - owner matches player name (we don't have player names, so skip this)
-			if itemEnt.Owner != "" {
-				// Age in ticks (assuming 20 ticks per second)
-				ageInTicks := itemEnt.Age * 20.0
-				if ageInTicks < 5800 {
-					// Item was recently dropped by owner, can't pick up yet
-					continue
-				}
+		if inPickupBox(pos, itemPos) {
+			count := itemEnt.Stack.Count
+			if p.Inventory.AddItem(&itemEnt.Stack) {
+				// Fully taken: fly the now-empty entity into the player
+				itemEnt.StartPickupAnimation(p.GetEyePosition())
 			}
-
-			itemPos := itemEnt.Position()
-
-			// Don't pick up items that are below the player's feet
-			// Player Y position is at feet level, so if item Y is lower, skip it
-			// Allow tolerance (1.5) for items below feet (e.g. in holes or lower blocks)
-			if itemPos.Y() < playerPos.Y()-1.5 {
-				continue
+			if itemEnt.Stack.Count < count && p.OnItemPickup != nil {
+				p.OnItemPickup(itemPos)
 			}
+			continue
+		}
 
-			// AABB collision check between player and item
-			// Player AABB: (pos.x - 0.3, pos.y, pos.z - 0.3) to (pos.x + 0.3, pos.y + 1.8, pos.z + 0.3)
-			// Item AABB: (item.x - 0.125, item.y, item.z - 0.125) to (item.x + 0.125, item.y + 0.25, item.z + 0.125)
-			// Extend player Y range upward to pick up items on blocks above
-			playerMinX := playerPos.X() - playerHalfWidth
-			playerMaxX := playerPos.X() + playerHalfWidth
-			playerMinY := playerPos.Y() - 1.0                // Extend downward to pick up items below
-			playerMaxY := playerPos.Y() + playerHeight + 1.0 // Extend upward to pick up items on blocks above
-			playerMinZ := playerPos.Z() - playerHalfWidth
-			playerMaxZ := playerPos.Z() + playerHalfWidth
-
-			itemMinX := itemPos.X() - itemHalfSize
-			itemMaxX := itemPos.X() + itemHalfSize
-			itemMinY := itemPos.Y()
-			itemMaxY := itemPos.Y() + 0.25
-			itemMinZ := itemPos.Z() - itemHalfSize
-			itemMaxZ := itemPos.Z() + itemHalfSize
-
-			// Check AABB overlap
-			if playerMaxX >= itemMinX && playerMinX <= itemMaxX &&
-				playerMaxY >= itemMinY && playerMinY <= itemMaxY &&
-				playerMaxZ >= itemMinZ && playerMinZ <= itemMaxZ {
-
-				// Try to add item to inventory
-				if p.Inventory.AddItem(&itemEnt.Stack) {
-					// Item was successfully added (or partially added)
-					// If stack is now empty, start pickup animation (visual only)
-					if itemEnt.Stack.Count <= 0 && !itemEnt.IsPickingUp {
-						// Target position: player's body center (eye level - 0.3)
-						targetPos := p.GetEyePosition().Sub(mgl32.Vec3{0, 0, 0})
-						itemEnt.StartPickupAnimation(targetPos)
-					}
-				}
-			}
+		// Magnet towards the closest point of the player's body
+		body := mgl32.Vec3{pos.X(), mgl32.Clamp(itemPos.Y(), pos.Y(), pos.Y()+PlayerHeight), pos.Z()}
+		if itemPos.Sub(body).Len() <= entity.MagnetRadius && p.Inventory.CanAdd(itemEnt.Stack) {
+			itemEnt.AttractTo(body, dt)
 		}
 	}
 }
 
+// pushAgainst pushes the player and an overlapping body apart, so the player
+// cannot stand inside mobs or vehicles. Items are picked up instead, and a
+// riding player moves with the vehicle.
+func (p *Player) pushAgainst(b world.Body, dt float64) {
+	if p.Vehicle != nil {
+		return
+	}
+	pos := mgl32.Vec3(p.Position)
+	w, h := p.GetBounds()
+	bw, bh := b.GetBounds()
+	if !world.BoxesOverlap(pos, w, h, b.Position(), bw, bh) {
+		return
+	}
+	dvx, dvz := world.Separation(pos, b.Position(), dt)
+	p.Velocity[0] += dvx
+	p.Velocity[2] += dvz
+	b.Push(-dvx, -dvz)
+}
+
+// inPickupBox reports whether an item's box overlaps the pickup box of a
+// player standing at pos.
+func inPickupBox(pos, itemPos mgl32.Vec3) bool {
+	const itemHalf = entity.ItemEntityWidth / 2
+	return itemPos.X()+itemHalf >= pos.X()-pickupHalfWidth && itemPos.X()-itemHalf <= pos.X()+pickupHalfWidth &&
+		itemPos.Y()+entity.ItemEntityHeight >= pos.Y()-pickupBelow && itemPos.Y() <= pos.Y()+pickupAbove &&
+		itemPos.Z()+itemHalf >= pos.Z()-pickupHalfWidth && itemPos.Z()-itemHalf <= pos.Z()+pickupHalfWidth
+}
+
 // DropCursorItem drops the item currently held by the cursor
 func (p *Player) DropCursorItem() {
 	if p.Inventory.CursorStack == nil {
@@ -187,7 +210,7 @@ func (p *Player) DropHeldItem(dropStack bool) {
 		p.Inventory.MainInventory[p.Inventory.CurrentItem] = nil
 	} else {
 		// Drop only one
-		dropped = item.NewItemStack(stack.Type, 1)
+		dropped = stack.WithCount(1)
 		stack.Count--
 	}
 
@@ -205,18 +228,22 @@ func (p *Player) spawnItemEntity(stack item.ItemStack) {
 	velocity := front.Mul(5.0)
 	velocity[1] += 1.0 // Slight upward toss
 
-	itemEnt := entity.NewItemEntity(p.World, pos, stack)
+	itemEnt := entity.NewItemEntity(p.World, p.World.Rand(), pos, stack)
 	itemEnt.Vel = velocity
 	p.World.AddEntity(itemEnt)
 }
 
+// UpdateHoveredBlock finds the block under the crosshair, casting from the
+// camera of the frame being drawn.
 func (p *Player) UpdateHoveredBlock() {
-	front := p.GetFrontVector()
-	rayStart := p.GetEyePosition()
-	result := physics.Raycast(rayStart, front, physics.MinReachDistance, physics.MaxReachDistance, p.World)
+	cam := p.Camera()
+	result := physics.Raycast(cam.Eye, cam.Front, physics.MinReachDistance, physics.MaxReachDistance, p.World)
 
 	p.HasHoveredBlock = result.Hit
 	if result.Hit {
 		p.HoveredBlock = result.HitPosition
+		for i := range 3 {
+			p.HoveredFace[i] = result.AdjacentPosition[i] - result.HitPosition[i]
+		}
 	}
 }
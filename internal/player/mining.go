@@ -1,7 +1,8 @@
 package player
 
 import (
-	"math/rand"
+	"math"
+
 	"mini-mc/internal/entity"
 	"mini-mc/internal/item"
 	"mini-mc/internal/registry"
@@ -63,39 +64,102 @@ func (p *Player) UpdateMining(dt float64, justPressed bool) {
 		return
 	}
 
-	def, ok := registry.Blocks[blockType]
-	hardness := float32(1.0) // Default
-	if ok {
-		hardness = def.Hardness
-	}
-
-	if hardness < 0 {
+	rate := p.breakRate(blockType)
+	if rate <= 0 {
 		// Unbreakable
 		p.BreakProgress = 0
 		return
 	}
+	p.BreakProgress += float32(dt) * rate
 
-	// Break speed formula (simplified)
-	breakSpeed := float32(1.0)
-	if p.IsFlying {
-		breakSpeed *= 5.0 // Flying breaks faster (if enabled)
+	if p.BreakProgress >= 1.0 {
+		p.BreakBlock()
 	}
+}
 
-	// Increment progress
-	p.BreakProgress += float32(dt) * breakSpeed / hardness
+// MC counts breaking progress in ticks and divides the mining speed by these
+// for blocks the held item can and cannot harvest.
+const (
+	miningTicksPerSecond = 20
+	harvestDivisor       = 30
+	noHarvestDivisor     = 100
+)
 
-	if p.BreakProgress >= 1.0 {
-		p.BreakBlock()
+// breakRate returns how much of a block of blockType the player breaks per
+// second of holding the button, as MC 1.8 works it out: 1.5 seconds per
+// point of hardness by hand, faster with the right tool, five times slower
+// off the ground or in water, and slower still for blocks that need a tool
+// the player is not holding. It is 0 for unbreakable blocks.
+func (p *Player) breakRate(blockType world.BlockType) float32 {
+	def, ok := registry.Blocks[blockType]
+	if !ok {
+		return miningTicksPerSecond / float32(harvestDivisor) // hardness 1
+	}
+	if def.Hardness < 0 {
+		return 0
+	}
+	if def.Hardness == 0 {
+		return float32(math.Inf(1))
+	}
+
+	speed := p.miningSpeed(def)
+	if !p.OnGround && !p.IsFlying {
+		speed /= 5
 	}
+	if p.IsInWater() {
+		speed /= 5
+	}
+	divisor := float32(harvestDivisor)
+	if !p.canHarvest(def) {
+		divisor = noHarvestDivisor
+	}
+	return speed / def.Hardness / divisor * miningTicksPerSecond
+}
+
+// heldTool returns the definition of the held item if it is a tool.
+func (p *Player) heldTool() *registry.BlockDefinition {
+	stack := p.Inventory.GetCurrentItem()
+	if stack == nil {
+		return nil
+	}
+	if def := registry.BlockDefs[stack.Type]; def != nil && def.Tool != registry.ToolNone && def.MiningSpeed > 0 {
+		return def
+	}
+	return nil
+}
+
+// miningSpeed returns how many times faster than by hand the held item
+// mines a block.
+func (p *Player) miningSpeed(block *registry.BlockDefinition) float32 {
+	if tool := p.heldTool(); tool != nil && block.Tool != registry.ToolNone && tool.Tool == block.Tool {
+		return tool.MiningSpeed
+	}
+	return 1
+}
+
+// canHarvest reports whether breaking a block drops it with the held item.
+func (p *Player) canHarvest(block *registry.BlockDefinition) bool {
+	if !block.NeedsTool {
+		return true
+	}
+	tool := p.heldTool()
+	return tool != nil && tool.Tool == block.Tool
 }
 
 func (p *Player) BreakBlock() {
 	x, y, z := p.BreakingBlock[0], p.BreakingBlock[1], p.BreakingBlock[2]
-	blockType := p.World.Get(x, y, z)
+	st := p.World.GetState(x, y, z)
+	blockType, meta := st.Type, st.Meta
 
-	if blockType != world.BlockTypeAir {
+	if !st.IsAir() {
 		p.World.Set(x, y, z, world.BlockTypeAir)
+		p.AddExhaustion(exhaustBreak)
+		if p.OnBlockBreak != nil {
+			p.OnBlockBreak(x, y, z, blockType, meta)
+		}
 		p.World.NotifyNeighbors(x, y, z)
+		p.removeBlockEntity(x, y, z)
+		p.breakAttachedFrames(x, y, z)
 
 		if p.GameMode != GameModeCreative {
 			// Determine drops
@@ -106,22 +170,46 @@ func (p *Player) BreakBlock() {
 			if ok {
 				dropType = def.GetItemDropped()
 				dropCount = def.QuantityDropped()
+				if !p.canHarvest(def) {
+					dropCount = 0
+				}
 			}
 
 			if dropCount > 0 {
 				// Create item entity in the world
 				// Start slightly above the bottom of the block, with random horizontal offset
-				offsetX := (rand.Float64() * 0.7) + 0.15
+				rnd := p.World.Rand()
+				offsetX := (rnd.Float64() * 0.7) + 0.15
 				offsetY := 0.8
-				offsetZ := (rand.Float64() * 0.7) + 0.15
+				offsetZ := (rnd.Float64() * 0.7) + 0.15
 
 				pos := mgl32.Vec3{float32(x) + float32(offsetX), float32(y) + float32(offsetY), float32(z) + float32(offsetZ)}
-				itemEnt := entity.NewItemEntity(p.World, pos, item.NewItemStack(dropType, dropCount))
+				itemEnt := entity.NewItemEntity(p.World, rnd, pos, item.NewItemStack(dropType, dropCount))
 				p.World.AddEntity(itemEnt)
 			}
 		}
 
+		if p.GameMode != GameModeCreative {
+			p.damageHeldTool(1)
+		}
+
 		// Reset mining
 		p.ResetMining()
 	}
 }
+
+// damageHeldTool wears down the tool in the selected hotbar slot, removing it
+// and firing OnToolBreak when it runs out.
+func (p *Player) damageHeldTool(amount int) {
+	stack := p.Inventory.GetCurrentItem()
+	if stack == nil || !stack.IsDamageable() {
+		return
+	}
+	tool := *stack
+	if stack.DamageItem(amount) {
+		p.Inventory.MainInventory[p.Inventory.CurrentItem] = nil
+		if p.OnToolBreak != nil {
+			p.OnToolBreak(tool, p.GetEyePosition())
+		}
+	}
+}
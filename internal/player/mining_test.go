@@ -0,0 +1,70 @@
+package player
+
+import (
+	"testing"
+
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+)
+
+// withMiningDefs registers stone and the wooden pickaxe as InitRegistry
+// would, without loading their models.
+func withMiningDefs(t *testing.T) {
+	stone, pick := registry.BlockDefs[world.BlockTypeStone], registry.BlockDefs[world.BlockTypeWoodenPickaxe]
+	t.Cleanup(func() {
+		registry.BlockDefs[world.BlockTypeStone], registry.BlockDefs[world.BlockTypeWoodenPickaxe] = stone, pick
+		delete(registry.Blocks, world.BlockTypeStone)
+		delete(registry.Blocks, world.BlockTypeWoodenPickaxe)
+	})
+	for _, def := range []*registry.BlockDefinition{
+		{ID: world.BlockTypeStone, IsSolid: true, Hardness: 1.5, Tool: registry.ToolPickaxe, NeedsTool: true,
+			GetItemDropped: func() world.BlockType { return world.BlockTypeCobblestone }, QuantityDropped: func() int { return 1 }},
+		{ID: world.BlockTypeWoodenPickaxe, IsItem: true, Tool: registry.ToolPickaxe, MiningSpeed: 2},
+	} {
+		registry.Blocks[def.ID], registry.BlockDefs[def.ID] = def, def
+	}
+}
+
+// holdToBreak holds the button on the block at (1, groundY-1, 0) for the
+// given seconds, a twentieth of a second at a time.
+func holdToBreak(p *Player, seconds float64) {
+	p.HasHoveredBlock, p.HoveredBlock = true, [3]int{1, groundY - 1, 0}
+	for i := 0; i < int(seconds*20); i++ {
+		p.UpdateMining(1.0/20, i == 0)
+	}
+}
+
+func TestHoldToBreakTakesHardnessTime(t *testing.T) {
+	withMiningDefs(t)
+	p, w := newPickupPlayer(t)
+	p.OnGround = true
+
+	// Stone by hand: 1.5 hardness, needs a pickaxe, so 7.5 seconds
+	holdToBreak(p, 7)
+	if w.Get(1, groundY-1, 0) != world.BlockTypeStone || p.BreakProgress < 0.9 {
+		t.Fatalf("stone broke or barely started by hand: progress %.2f", p.BreakProgress)
+	}
+	holdToBreak(p, 0.6)
+	if w.Get(1, groundY-1, 0) != world.BlockTypeAir || len(w.GetEntities()) != 0 {
+		t.Errorf("stone mined by hand should break without a drop")
+	}
+
+	// With a wooden pickaxe: 1.5 * 1.5 / 2 seconds, and it drops
+	w.Set(1, groundY-1, 0, world.BlockTypeStone)
+	holding(p, world.BlockTypeWoodenPickaxe, 1)
+	holdToBreak(p, 1.2)
+	if w.Get(1, groundY-1, 0) != world.BlockTypeAir || len(w.GetEntities()) != 1 {
+		t.Errorf("pickaxe did not break stone in time with a drop")
+	}
+}
+
+func TestCreativeBreaksInstantly(t *testing.T) {
+	withMiningDefs(t)
+	p, w := newPickupPlayer(t)
+	p.GameMode = GameModeCreative
+
+	holdToBreak(p, 0.05)
+	if w.Get(1, groundY-1, 0) != world.BlockTypeAir {
+		t.Error("creative click did not break the block")
+	}
+}
@@ -1,8 +1,9 @@
 package player
 
 import (
-	"fmt"
+	"log/slog"
 	"math"
+	"mini-mc/internal/config"
 	"mini-mc/internal/input"
 	"mini-mc/internal/physics"
 	"mini-mc/internal/profiling"
@@ -20,7 +21,8 @@ const (
 	SprintMultiplier = 1.3
 	SneakMultiplier  = 0.3
 
-	JumpVelocity    = 9.4
+	JumpVelocity    = 8.4  // MC: 0.42 blocks/tick
+	StepHeight      = 0.6  // MC Entity.stepHeight: max ledge walked onto without jumping
 	AirAcceleration = 0.02 // jumpMovementFactor
 	AirDrag         = 0.98 // Default air drag per tick
 	GroundDrag      = 0.91 // Default ground drag (before friction)
@@ -32,6 +34,8 @@ const (
 	WaterUpAccel         = 16.0 // MC: motionY += 0.04/tick → 0.04*(20^2) = 16 m/s²
 	WaterUpSpeed         = 2.0  // safety cap (natural terminal ~1.79 m/s from drag equilibrium)
 	WaterSurfacePopSpeed = 3.5  // exit velocity when leaving water surface → ~0.19 block consistent bob
+
+	secondsPerTick = 0.05 // game tick length (20 TPS), the step movement runs at
 )
 
 // IsInWater checks if the player's body is in water.
@@ -45,15 +49,10 @@ func (p *Player) IsInWater() bool {
 	return p.World.Get(x, midY, z) == world.BlockTypeWater
 }
 
-func (p *Player) UpdatePosition(dt float64, im *input.InputManager) {
-	start := time.Now()
-	defer func() {
-		d := time.Since(start)
-		if d > 10*time.Millisecond {
-			fmt.Println(d)
-		}
-	}()
-	defer profiling.Track("player.Update.Position")()
+// updateMovementInput reads the movement keys each frame: the double taps
+// that toggle flight and start sprinting, sprint and sneak, and a jump
+// press, held for the next game tick so a press between ticks is not lost.
+func (p *Player) updateMovementInput(dt float64, im *input.InputManager) {
 	// Update flight mode double-tap timer
 	if p.lastSpacePressTime >= 0 {
 		p.lastSpacePressTime += dt
@@ -97,35 +96,32 @@ func (p *Player) UpdatePosition(dt float64, im *input.InputManager) {
 
 	// Handle sprint and sneak
 	if !p.IsInventoryOpen {
-		forwardJustPressed := im.JustPressed(input.ActionMoveForward)
-
-		// Sprint toggle: either press sprint key or double-tap forward
-		if im.IsActive(input.ActionSprint) {
-			p.IsSprinting = true
-		}
-
-		// Double-tap forward to sprint
-		if forwardJustPressed {
-			if p.lastForwardPressTime >= 0 && p.lastForwardPressTime < 0.3 {
-				// Double tap detected
-				p.IsSprinting = true
-				p.lastForwardPressTime = -1
-			} else {
-				p.lastForwardPressTime = 0
-			}
-		}
-
-		if im.IsActive(input.ActionSneak) {
-			p.IsSneaking = true
-			p.IsSprinting = false
-		} else {
-			p.IsSneaking = false
-		}
+		p.IsSneaking = im.IsActive(input.ActionSneak)
+		p.updateSprint(im)
 	} else {
-		p.IsSprinting = false
+		p.stopSprinting()
 		p.IsSneaking = false
 	}
 
+	if im.JustPressed(input.ActionJump) {
+		p.jumpPressed = true
+	}
+}
+
+// tickMovement moves the player by one game tick of walking, swimming or
+// flying. The physics runs at the fixed tick rate whatever the frame rate;
+// RenderPosition smooths the steps for drawing.
+func (p *Player) tickMovement(im *input.InputManager) {
+	start := time.Now()
+	defer func() {
+		d := time.Since(start)
+		if d > 10*time.Millisecond {
+			slog.Debug("slow movement update", "duration", d)
+		}
+	}()
+	defer profiling.Track("player.Update.Position")()
+	dt := secondsPerTick
+
 	p.PrevPosition = p.Position
 	p.PrevDistanceWalkedModified = p.DistanceWalkedModified
 
@@ -149,9 +145,12 @@ func (p *Player) UpdatePosition(dt float64, im *input.InputManager) {
 
 		// Stop sprinting if not moving forward
 		if forward <= 0 {
-			p.IsSprinting = false
+			p.stopSprinting()
 		}
 	}
+	if !p.canSprint() {
+		p.stopSprinting()
+	}
 
 	// Calculate movement based on camera direction
 	yaw := float32(p.CamYaw)
@@ -165,7 +164,7 @@ func (p *Player) UpdatePosition(dt float64, im *input.InputManager) {
 	strafeX := float32(math.Cos(yawRad + math.Pi/2))
 	strafeZ := float32(math.Sin(yawRad + math.Pi/2))
 
-	modeDistance := float32(dt * 20.0) // For Drag scaling (time dilation)
+	modeDistance := float32(dt * 20.0) // For Drag scaling (time dilation); 1 per tick
 	accelScale := modeDistance * 20.0  // For Acceleration scaling (force)
 
 	// Helper to apply movement
@@ -192,6 +191,13 @@ func (p *Player) UpdatePosition(dt float64, im *input.InputManager) {
 		}
 	}
 
+	if p.IsFlying || p.IsInWater() {
+		// Jump assists only apply to walking; don't carry them into or out of flight/swimming.
+		p.coyoteTicksLeft = 0
+		p.jumpBufferTicksLeft = 0
+		p.jumpPressed = false
+	}
+
 	if p.IsFlying {
 		// Flight mode physics
 
@@ -267,27 +273,26 @@ func (p *Player) UpdatePosition(dt float64, im *input.InputManager) {
 			}
 		}
 
-		// Apply input acceleration
-		correction := float32(1.0)
-		if currentFriction < 0.999 { // Avoid divide by zero
-			lnD := math.Log(float64(currentFriction))
-			correction = float32(-lnD / (1.0 - float64(currentFriction)))
-		}
-
-		applyMovement(strafe, forward, accel*correction)
+		// Apply input acceleration; stepped once a tick, as in MC, it needs no
+		// correction for the drag applied between steps
+		applyMovement(strafe, forward, accel)
 
 		// Jump
-		if !p.IsInventoryOpen && im.IsActive(input.ActionJump) && p.OnGround {
+		if !p.IsInventoryOpen && p.updateJumpAssist(im) {
 			p.Velocity[1] = JumpVelocity
+			p.step(stepVolume, 1)
 			p.OnGround = false
 			p.JumpStartY = p.Position[1]
 			p.MaxJumpHeight = 0
 
 			// Sprint jump boost
 			if p.IsSprinting {
+				p.AddExhaustion(exhaustSprintJump)
 				jumpBoost := float32(0.125 * 20.0) // Reduced to 2.5 m/s to match feel
 				p.Velocity[0] += frontX * jumpBoost
 				p.Velocity[2] += frontZ * jumpBoost
+			} else {
+				p.AddExhaustion(exhaustJump)
 			}
 		}
 	}
@@ -357,9 +362,9 @@ func (p *Player) UpdatePosition(dt float64, im *input.InputManager) {
 		} else {
 			p.Position[0] = newPos[0]
 		}
-	} else {
+	} else if !p.tryStepUp(testPosX) {
 		p.Velocity[0] = 0
-		p.IsSprinting = false
+		p.stopSprinting()
 		collidedX = true
 	}
 
@@ -372,9 +377,9 @@ func (p *Player) UpdatePosition(dt float64, im *input.InputManager) {
 		} else {
 			p.Position[2] = newPos[2]
 		}
-	} else {
+	} else if !p.tryStepUp(testPosZ) {
 		p.Velocity[2] = 0
-		p.IsSprinting = false
+		p.stopSprinting()
 		collidedZ = true
 	}
 
@@ -471,6 +476,12 @@ func (p *Player) UpdatePosition(dt float64, im *input.InputManager) {
 	positionChange := p.Position.Sub(p.PrevPosition)
 	distanceMoved := math.Sqrt(float64(positionChange.X()*positionChange.X() + positionChange.Z()*positionChange.Z()))
 	p.DistanceWalkedModified = p.DistanceWalkedModified + distanceMoved*0.6
+	p.tickFootsteps()
+	p.tickSprintDust()
+	p.tickSplash()
+	if p.IsSprinting && !p.IsFlying {
+		p.AddExhaustion(exhaustSprint * float32(distanceMoved))
+	}
 
 	// Update fall state
 	dy := p.Position.Y() - p.PrevPosition[1]
@@ -485,6 +496,69 @@ func (p *Player) UpdatePosition(dt float64, im *input.InputManager) {
 	}
 }
 
+// updateJumpAssist advances the coyote-time and jump-buffer timers by one
+// game tick and reports whether the player should jump this tick.
+//
+// Coyote time lets a jump through for a few ticks after walking off a ledge;
+// jump buffering remembers a press made shortly before landing. Both are
+// consumed by a jump, so neither can grant a second jump mid-air.
+func (p *Player) updateJumpAssist(im *input.InputManager) bool {
+	if p.OnGround {
+		p.coyoteTicksLeft = float64(config.GetCoyoteTicks())
+	}
+	pressed := p.jumpPressed
+	p.jumpPressed = false
+	if pressed {
+		p.jumpBufferTicksLeft = float64(config.GetJumpBufferTicks())
+	}
+
+	canJump := p.OnGround || p.coyoteTicksLeft > 0
+	wantsJump := im.IsActive(input.ActionJump) || p.jumpBufferTicksLeft > 0
+	if canJump && wantsJump {
+		p.coyoteTicksLeft = 0
+		p.jumpBufferTicksLeft = 0
+		return true
+	}
+
+	// The windows count the ticks after the last on the ground and after
+	// the press, so a window of N ticks allows N late jumps
+	if !p.OnGround && p.coyoteTicksLeft > 0 {
+		p.coyoteTicksLeft--
+	}
+	if !pressed && p.jumpBufferTicksLeft > 0 {
+		p.jumpBufferTicksLeft--
+	}
+	return false
+}
+
+// tryStepUp moves the player onto a low obstacle (such as a few snow layers)
+// at target when it is no taller than StepHeight and there is headroom above it.
+// Only applies while walking on the ground.
+func (p *Player) tryStepUp(target mgl32.Vec3) bool {
+	if !p.OnGround || p.IsFlying {
+		return false
+	}
+	width, height := p.GetBounds()
+
+	raised := target
+	raised[1] += StepHeight
+	if physics.Collides(raised, width, height, p.World) {
+		return false
+	}
+
+	ground := physics.FindGroundLevel(raised[0], raised[2], raised, width, height, p.World)
+	if float32IsInfNeg(ground) || ground <= target[1] || ground > target[1]+StepHeight {
+		return false
+	}
+
+	target[1] = ground
+	if physics.Collides(target, width, height, p.World) {
+		return false
+	}
+	p.Position = target
+	return true
+}
+
 func (p *Player) UpdateFallState(dy float64, onGround bool) {
 	if p.IsFlying {
 		p.FallDistance = 0
@@ -499,8 +573,7 @@ func (p *Player) UpdateFallState(dy float64, onGround bool) {
 
 	if onGround {
 		if p.FallDistance > 0 {
-			// Apply fall damage
-			p.Fall(p.FallDistance, 1.0)
+			p.land(p.FallDistance)
 			p.FallDistance = 0
 		}
 	} else if dy < 0 {
@@ -519,8 +592,23 @@ func (p *Player) Fall(distance float32, damageMultiplier float32) {
 
 	if damage > 0 {
 		p.ApplyDamage(float32(damage))
-		// TODO: Play fall sound
+		p.step(hardLandGain, hardLandTone)
+	} else if distance > landStepFall {
+		p.step(stepVolume, 1)
+	}
+}
+
+// Teleport moves the player to pos, leaving any vehicle and dropping all
+// motion so the jump is not counted as a fall.
+func (p *Player) Teleport(pos mgl32.Vec3) {
+	if p.Vehicle != nil {
+		p.Vehicle.SetRider(nil)
+		p.Vehicle = nil
 	}
+	p.Position = pos
+	p.PrevPosition = pos
+	p.Velocity = mgl32.Vec3{}
+	p.FallDistance = 0
 }
 
 // float32IsInfNeg reports whether v is negative infinity.
@@ -0,0 +1,349 @@
+package player
+
+import (
+	"math"
+	"testing"
+
+	"mini-mc/internal/config"
+	"mini-mc/internal/input"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// groundY is the top of the flat stone test floor.
+const groundY = 64
+
+// withSolidStone makes stone solid for the length of a test; the world
+// package does not import the registry that normally fills the table.
+func withSolidStone(t *testing.T) {
+	solid := world.BlockSolidTable
+	t.Cleanup(func() { world.BlockSolidTable = solid })
+	world.BlockSolidTable[world.BlockTypeStone] = true
+}
+
+// movementSim drives a survival player over a flat stone floor without a
+// window: keys are fed to a bare InputManager and the movement is stepped a
+// game tick at a time, as the game loop would.
+type movementSim struct {
+	p  *Player
+	im *input.InputManager
+}
+
+func newMovementSim(t *testing.T) *movementSim {
+	t.Helper()
+	withSolidStone(t)
+	w := world.NewEmpty()
+	t.Cleanup(w.Close)
+	for x := -8; x < 160; x++ {
+		for z := -8; z < 8; z++ {
+			w.Set(x, groundY-1, z, world.BlockTypeStone)
+		}
+	}
+	p := New(w, GameModeSurvival)
+	p.Position = [3]float32{0.5, groundY, 0.5} // yaw 0 faces +X
+	s := &movementSim{p: p, im: input.NewInputManager()}
+	s.step(5) // settle onto the floor
+	return s
+}
+
+func (s *movementSim) press(keys ...glfw.Key) {
+	for _, k := range keys {
+		s.im.HandleKeyEvent(k, glfw.Press)
+	}
+}
+
+func (s *movementSim) release(keys ...glfw.Key) {
+	for _, k := range keys {
+		s.im.HandleKeyEvent(k, glfw.Release)
+	}
+}
+
+func (s *movementSim) step(ticks int) {
+	for range ticks {
+		s.p.updateMovementInput(secondsPerTick, s.im)
+		s.p.tickMovement(s.im)
+		s.im.PostUpdate()
+	}
+}
+
+// frames runs n frames of dt seconds, ticking the movement whenever a game
+// tick falls due as the game loop does. It returns the position after each
+// tick.
+func (s *movementSim) frames(n int, dt float64, acc *float64) []mgl32.Vec3 {
+	var ticks []mgl32.Vec3
+	for range n {
+		s.p.updateMovementInput(dt, s.im)
+		for *acc += dt; *acc >= secondsPerTick; *acc -= secondsPerTick {
+			s.p.tickMovement(s.im)
+			ticks = append(ticks, s.p.Position)
+		}
+		s.im.PostUpdate()
+	}
+	return ticks
+}
+
+// seconds converts a duration to whole game ticks.
+func seconds(sec float64) int {
+	return int(math.Round(sec / secondsPerTick))
+}
+
+// jumpHeight is the apex of a standing jump above the floor, in blocks.
+func jumpHeight(t *testing.T) float64 {
+	s := newMovementSim(t)
+	s.press(glfw.KeySpace)
+	s.step(1)
+	s.release(glfw.KeySpace)
+	apex := s.p.Position[1]
+	for range seconds(1) {
+		s.step(1)
+		apex = max(apex, s.p.Position[1])
+	}
+	return float64(apex - groundY)
+}
+
+// jumpAirtime is how long a standing jump stays off the ground, in seconds.
+func jumpAirtime(t *testing.T) float64 {
+	s := newMovementSim(t)
+	s.press(glfw.KeySpace)
+	s.step(1)
+	s.release(glfw.KeySpace)
+	for tick := 1; tick < seconds(2); tick++ {
+		s.step(1)
+		if s.p.OnGround {
+			return float64(tick+1) * secondsPerTick
+		}
+	}
+	return math.Inf(1)
+}
+
+// groundSpeed is the steady horizontal speed while holding keys, in m/s.
+func groundSpeed(keys ...glfw.Key) func(t *testing.T) float64 {
+	return func(t *testing.T) float64 {
+		s := newMovementSim(t)
+		s.press(keys...)
+		s.step(seconds(2)) // reach steady state
+		x0 := s.p.Position[0]
+		s.step(seconds(2))
+		return float64(s.p.Position[0]-x0) / 2
+	}
+}
+
+// sprintJumpSpeed is the average speed while sprint-jumping continuously.
+func sprintJumpSpeed(t *testing.T) float64 {
+	s := newMovementSim(t)
+	s.press(glfw.KeyW, glfw.KeyLeftControl, glfw.KeySpace)
+	s.step(seconds(2))
+	x0 := s.p.Position[0]
+	s.step(seconds(3))
+	return float64(s.p.Position[0]-x0) / 3
+}
+
+// terminalVelocityTime is how long a fall from rest takes to reach 95% of
+// terminal velocity, in seconds.
+func terminalVelocityTime(t *testing.T) float64 {
+	s := newMovementSim(t)
+	s.p.Position = [3]float32{100.5, 2000, 0.5}
+	for tick := range seconds(20) {
+		s.step(1)
+		if s.p.Velocity[1] <= 0.95*TerminalVelocity {
+			return float64(tick+1) * secondsPerTick
+		}
+	}
+	return math.Inf(1)
+}
+
+// groundFrictionDecay is the fraction of horizontal speed kept over one game
+// tick (1/20 s) when sliding to a stop on the ground.
+func groundFrictionDecay(t *testing.T) float64 {
+	s := newMovementSim(t)
+	s.press(glfw.KeyW)
+	s.step(seconds(1))
+	s.release(glfw.KeyW)
+	s.step(1)
+	v0 := s.p.Velocity[0]
+	s.step(seconds(secondsPerTick))
+	return float64(s.p.Velocity[0] / v0)
+}
+
+// TestMovementSnapshot is a regression snapshot of player movement: want is
+// this game's own recorded value, so a change in feel fails here and a
+// deliberate tuning change updates want. It is not a vanilla accuracy test.
+// Each row also carries the Minecraft 1.8.9 figure the movement was tuned
+// from; a row further than tol from it must say why in deviation, so the
+// known differences stay listed here rather than hidden in want.
+func TestMovementSnapshot(t *testing.T) {
+	// MC scales the movement input by 0.98 each tick; the game does not, so
+	// ground speeds come out 1/0.98 of vanilla's
+	const noInputScale = "no 0.98 input scale"
+	tests := []struct {
+		name      string
+		measure   func(t *testing.T) float64
+		want      float64
+		tol       float64
+		vanilla   float64
+		deviation string
+	}{
+		{"jump height (blocks)", jumpHeight, 1.252, 0.02, 1.2522, ""},
+		{"jump airtime (s)", jumpAirtime, 0.583, 0.02, 0.60, ""},
+		{"walk speed (m/s)", groundSpeed(glfw.KeyW), 4.405, 0.05, 4.317, noInputScale},
+		{"sprint speed (m/s)", groundSpeed(glfw.KeyW, glfw.KeyLeftControl), 5.727, 0.05, 5.612, noInputScale},
+		{"sneak speed (m/s)", groundSpeed(glfw.KeyW, glfw.KeyLeftShift), 1.322, 0.05, 1.295, ""},
+		{"sprint-jump speed (m/s)", sprintJumpSpeed, 7.702, 0.1, 7.127,
+			noInputScale + " covers 0.15 m/s; the rest is not tracked down, the jump boost being smaller than MC's"},
+		{"time to 95% terminal velocity (s)", terminalVelocityTime, 7.40, 0.1, 7.40, ""},
+		{"ground friction decay per tick", groundFrictionDecay, 0.546, 0.005, 0.546, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.measure(t)
+			if math.Abs(got-tt.want) > tt.tol {
+				t.Errorf("got %.4f, want %.4f ± %.4f", got, tt.want, tt.tol)
+			}
+			switch deviates := math.Abs(tt.want-tt.vanilla) > tt.tol; {
+			case deviates && tt.deviation == "":
+				t.Errorf("want %.4f is off vanilla 1.8.9 (%.4f) by more than %.4f with no deviation noted", tt.want, tt.vanilla, tt.tol)
+			case !deviates && tt.deviation != "":
+				t.Errorf("want %.4f is within %.4f of vanilla 1.8.9 (%.4f); drop the deviation note", tt.want, tt.tol, tt.vanilla)
+			}
+		})
+	}
+}
+
+// TestMovementFrameRateIndependent checks that movement depends only on the
+// game ticks run, not on how the frames between them fell.
+func TestMovementFrameRateIndependent(t *testing.T) {
+	run := func(fps int) []mgl32.Vec3 {
+		s := newMovementSim(t)
+		var acc float64
+		s.press(glfw.KeyW, glfw.KeyLeftControl, glfw.KeySpace)
+		ticks := s.frames(fps/4, 1/float64(fps), &acc) // let go of jump after a quarter of a second
+		s.release(glfw.KeySpace)
+		return append(ticks, s.frames(fps*7/4, 1/float64(fps), &acc)...)
+	}
+	slow, fast := run(40), run(240)
+	if d := len(slow) - len(fast); d < -1 || d > 1 {
+		t.Fatalf("ticks in 2 s: %d at 40 FPS, %d at 240 FPS", len(slow), len(fast))
+	}
+	for i := range min(len(slow), len(fast)) {
+		if slow[i] != fast[i] {
+			t.Fatalf("tick %d: at 40 FPS %v, at 240 FPS %v", i, slow[i], fast[i])
+		}
+	}
+}
+
+// TestSprintKeyModes checks that a held sprint key sprints only while held,
+// a toggled one keeps sprinting after release and resumes after sneaking,
+// and hunger stops either.
+func TestSprintKeyModes(t *testing.T) {
+	defer config.SetSprintMode(config.GetSprintMode())
+
+	config.SetSprintMode(config.SprintHold)
+	s := newMovementSim(t)
+	s.press(glfw.KeyW, glfw.KeyLeftControl)
+	s.step(1)
+	if !s.p.IsSprinting {
+		t.Fatal("hold: not sprinting with the key held")
+	}
+	s.release(glfw.KeyLeftControl)
+	s.step(1)
+	if s.p.IsSprinting {
+		t.Error("hold: still sprinting after letting go of the key")
+	}
+
+	config.SetSprintMode(config.SprintToggle)
+	s = newMovementSim(t)
+	s.press(glfw.KeyW, glfw.KeyLeftControl)
+	s.step(1)
+	s.release(glfw.KeyLeftControl)
+	s.step(1)
+	if !s.p.IsSprinting {
+		t.Fatal("toggle: stopped sprinting after letting go of the key")
+	}
+	s.press(glfw.KeyLeftShift)
+	s.step(1)
+	if s.p.IsSprinting {
+		t.Error("toggle: still sprinting while sneaking")
+	}
+	s.release(glfw.KeyLeftShift)
+	s.step(1)
+	if !s.p.IsSprinting {
+		t.Error("toggle: sprint did not resume after sneaking")
+	}
+	s.p.FoodLevel = sprintFoodLevel
+	s.step(1)
+	if s.p.IsSprinting {
+		t.Error("toggle: still sprinting while hungry")
+	}
+	s.p.FoodLevel = s.p.MaxFoodLevel
+	s.press(glfw.KeyLeftControl)
+	s.step(1)
+	if s.p.IsSprinting || s.p.SprintToggled() {
+		t.Error("toggle: second press did not switch sprint off")
+	}
+}
+
+// coyoteJump reports whether a jump pressed on the lateTh tick after
+// walking off a ledge leaves the ground.
+func coyoteJump(t *testing.T, late int) bool {
+	s := newMovementSim(t)
+	for x := 2; x < 160; x++ {
+		for z := -8; z < 8; z++ {
+			s.p.World.Set(x, groundY-1, z, world.BlockTypeAir)
+		}
+	}
+	s.press(glfw.KeyW)
+	for s.p.OnGround {
+		s.step(1)
+	}
+	s.step(late - 1)
+	s.press(glfw.KeySpace)
+	s.step(1)
+	return s.p.Velocity[1] > 0
+}
+
+// bufferedJump reports whether a jump tapped early ticks before the first
+// tick back on the ground is carried out on landing.
+func bufferedJump(t *testing.T, early int) bool {
+	drop := func() *movementSim {
+		s := newMovementSim(t)
+		s.p.Position[1] = groundY + 3
+		s.p.OnGround = false
+		s.p.coyoteTicksLeft = 0 // dropped, not walked off a ledge
+		return s
+	}
+	s := drop()
+	fall := 0
+	for ; !s.p.OnGround; fall++ {
+		s.step(1)
+	}
+	s = drop()
+	s.step(fall - early)
+	s.press(glfw.KeySpace)
+	s.step(1)
+	s.release(glfw.KeySpace)
+	s.step(early)
+	return s.p.Velocity[1] > 0
+}
+
+// TestJumpAssistWindows checks that coyote time and jump buffering each
+// allow exactly their configured number of ticks, no more.
+func TestJumpAssistWindows(t *testing.T) {
+	defer config.SetCoyoteTicks(config.GetCoyoteTicks())
+	defer config.SetJumpBufferTicks(config.GetJumpBufferTicks())
+
+	for _, window := range []int{0, 1, 2, 5} {
+		config.SetCoyoteTicks(window)
+		config.SetJumpBufferTicks(window)
+		for ticks := 1; ticks <= window+2; ticks++ {
+			want := ticks <= window
+			if got := coyoteJump(t, ticks); got != want {
+				t.Errorf("coyote time %d: jump %d ticks after the ledge = %v, want %v", window, ticks, got, want)
+			}
+			if got := bufferedJump(t, ticks); got != want {
+				t.Errorf("jump buffer %d: jump %d ticks before landing = %v, want %v", window, ticks, got, want)
+			}
+		}
+	}
+}
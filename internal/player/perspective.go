@@ -0,0 +1,69 @@
+package player
+
+import (
+	"mini-mc/internal/physics"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Perspective is where the world is seen from, cycled with F5 as in MC.
+type Perspective uint8
+
+const (
+	FirstPerson      Perspective = iota
+	ThirdPersonBack              // behind the player, looking where they look
+	ThirdPersonFront             // in front of the player, looking back at them
+	perspectiveCount
+)
+
+// The third-person camera sits thirdPersonDistance blocks from the eye, or
+// nearer when blocks are in the way. Rays are cast from the corners of a
+// small box about the eye, cameraClearance across, so the near plane stays
+// out of the blocks too.
+const (
+	thirdPersonDistance = 4.0
+	cameraClearance     = 0.1
+)
+
+// CyclePerspective switches to the next perspective, back to first person
+// after the front view.
+func (p *Player) CyclePerspective() {
+	p.Perspective = (p.Perspective + 1) % perspectiveCount
+}
+
+// RenderCamera returns the camera the world is drawn from this frame: the
+// eye's in first person, otherwise pulled back from it. Rays and sounds
+// still come from Camera, the player's own eye.
+func (p *Player) RenderCamera() CameraSnapshot {
+	cam := p.Camera()
+	if p.Perspective == FirstPerson {
+		return cam
+	}
+	// back is the way from the eye to the camera
+	back := cam.Front.Mul(-1)
+	if p.Perspective == ThirdPersonFront {
+		back, cam.Front = cam.Front, back
+	}
+	cam.Eye = cam.Eye.Add(back.Mul(p.cameraDistance(cam.Eye, back)))
+	return cam
+}
+
+// cameraDistance returns how far the camera can go from eye along dir before
+// it would meet a block, up to thirdPersonDistance.
+func (p *Player) cameraDistance(eye, dir mgl32.Vec3) float32 {
+	dist := float32(thirdPersonDistance)
+	if p.World == nil {
+		return dist
+	}
+	for i := range 8 {
+		offset := mgl32.Vec3{
+			float32(i&1*2-1) * cameraClearance,
+			float32(i>>1&1*2-1) * cameraClearance,
+			float32(i>>2&1*2-1) * cameraClearance,
+		}
+		if r := physics.Raycast(eye.Add(offset), dir, 0, dist, p.World); r.Hit && r.Distance < dist {
+			dist = r.Distance
+		}
+	}
+	return dist
+}
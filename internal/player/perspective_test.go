@@ -0,0 +1,63 @@
+package player
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestCyclePerspective(t *testing.T) {
+	p := &Player{}
+	for _, want := range []Perspective{ThirdPersonBack, ThirdPersonFront, FirstPerson} {
+		p.CyclePerspective()
+		if p.Perspective != want {
+			t.Fatalf("Perspective = %d, want %d", p.Perspective, want)
+		}
+	}
+}
+
+func TestRenderCamera(t *testing.T) {
+	withSolidStone(t)
+	w := world.NewEmpty()
+	t.Cleanup(w.Close)
+	p := New(w, GameModeSurvival)
+	p.Position = mgl32.Vec3{0.5, groundY, 0.5} // yaw 0 looks along +X
+	p.PrevPosition = p.Position
+	eye := p.Camera().Eye
+
+	near := func(got, want mgl32.Vec3) bool { return got.Sub(want).Len() < 1e-4 }
+	if cam := p.RenderCamera(); !near(cam.Eye, eye) {
+		t.Errorf("first person eye = %v, want %v", cam.Eye, eye)
+	}
+
+	p.Perspective = ThirdPersonBack
+	cam := p.RenderCamera()
+	if want := eye.Add(mgl32.Vec3{-thirdPersonDistance, 0, 0}); !near(cam.Eye, want) {
+		t.Errorf("back eye = %v, want %v", cam.Eye, want)
+	}
+	if !near(cam.Front, mgl32.Vec3{1, 0, 0}) {
+		t.Errorf("back front = %v, want +X", cam.Front)
+	}
+
+	p.Perspective = ThirdPersonFront
+	cam = p.RenderCamera()
+	if want := eye.Add(mgl32.Vec3{thirdPersonDistance, 0, 0}); !near(cam.Eye, want) {
+		t.Errorf("front eye = %v, want %v", cam.Eye, want)
+	}
+	if !near(cam.Front, mgl32.Vec3{-1, 0, 0}) {
+		t.Errorf("front front = %v, want -X", cam.Front)
+	}
+
+	// A wall two blocks behind pulls the camera in to stay in front of it
+	for y := groundY - 2; y < groundY+4; y++ {
+		for z := -3; z <= 3; z++ {
+			w.Set(-2, y, z, world.BlockTypeStone)
+		}
+	}
+	p.Perspective = ThirdPersonBack
+	if x := p.RenderCamera().Eye.X(); x < -1 || x > -0.5 {
+		t.Errorf("eye x with a wall behind = %v, want between -1 and -0.5", x)
+	}
+}
@@ -0,0 +1,81 @@
+package player
+
+import (
+	"testing"
+
+	"mini-mc/internal/entity"
+	"mini-mc/internal/item"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func newPickupPlayer(t *testing.T) (*Player, *world.World) {
+	t.Helper()
+	withSolidStone(t)
+	w := world.NewEmpty()
+	t.Cleanup(w.Close)
+	for x := -4; x < 4; x++ {
+		for z := -4; z < 4; z++ {
+			w.Set(x, groundY-1, z, world.BlockTypeStone)
+		}
+	}
+	p := New(w, GameModeSurvival)
+	p.Position = [3]float32{0.5, groundY, 0.5}
+	return p, w
+}
+
+func dropItem(w *world.World, pos mgl32.Vec3, stack item.ItemStack) *entity.ItemEntity {
+	e := entity.NewItemEntity(w, w.Rand(), pos, stack)
+	e.Vel = mgl32.Vec3{}
+	e.PickupDelay = 0
+	w.AddEntity(e)
+	return e
+}
+
+func TestPickupLeavesLeftoverInWorld(t *testing.T) {
+	p, w := newPickupPlayer(t)
+	// One free slot short of full: 60 dirt in slot 0, stone everywhere else
+	for i := range p.Inventory.MainInventory {
+		s := item.NewItemStack(world.BlockTypeStone, 64)
+		p.Inventory.MainInventory[i] = &s
+	}
+	dirt := item.NewItemStack(world.BlockTypeDirt, 60)
+	p.Inventory.MainInventory[0] = &dirt
+
+	e := dropItem(w, mgl32.Vec3{0.8, groundY, 0.5}, item.NewItemStack(world.BlockTypeDirt, 10))
+	p.CheckEntityCollisions(1.0 / 20)
+
+	if dirt.Count != 64 {
+		t.Fatalf("slot holds %d dirt, want 64", dirt.Count)
+	}
+	if e.Stack.Count != 6 || e.IsPickingUp || e.IsDead() {
+		t.Fatalf("leftover entity: count %d, picking up %v, dead %v; want 6 left in the world",
+			e.Stack.Count, e.IsPickingUp, e.IsDead())
+	}
+}
+
+func TestMagnetPullsOnlyPickableItemsInRange(t *testing.T) {
+	p, w := newPickupPlayer(t)
+	near := dropItem(w, mgl32.Vec3{1.9, groundY, 0.5}, item.NewItemStack(world.BlockTypeDirt, 1))
+	far := dropItem(w, mgl32.Vec3{2.5, groundY, 0.5}, item.NewItemStack(world.BlockTypeDirt, 1))
+	delayed := dropItem(w, mgl32.Vec3{0.5, groundY, 1.9}, item.NewItemStack(world.BlockTypeDirt, 1))
+	delayed.PickupDelay = 0.5
+	despawning := dropItem(w, mgl32.Vec3{0.2, groundY, 0.2}, item.NewItemStack(world.BlockTypeDirt, 1))
+	despawning.Age = entity.DespawnAge
+
+	p.CheckEntityCollisions(1.0 / 20)
+
+	if near.Vel.X() >= 0 {
+		t.Errorf("item 1.4 blocks away has velocity %v, want pulled towards -X", near.Vel)
+	}
+	if far.Vel != (mgl32.Vec3{}) {
+		t.Errorf("item out of magnet range was moved: %v", far.Vel)
+	}
+	if delayed.Vel != (mgl32.Vec3{}) {
+		t.Errorf("item still in its pickup delay was moved: %v", delayed.Vel)
+	}
+	if despawning.IsPickingUp || despawning.Stack.Count != 1 {
+		t.Error("despawning item was picked up")
+	}
+}
@@ -0,0 +1,220 @@
+package player
+
+import (
+	"math"
+
+	"mini-mc/internal/config"
+	"mini-mc/internal/physics"
+	"mini-mc/internal/registry"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// BlockPlacement is a block the player asks to place.
+type BlockPlacement struct {
+	X, Y, Z int
+	State   world.BlockState
+}
+
+// placementFor returns the block the held stack would place against the
+// block hit by a ray along front: in the space in front of the hit face,
+// facing the player, or hanging on the face for item frames. ok is false
+// when the block cannot go there at all.
+func placementFor(t world.BlockType, hit, adjacent [3]int, front mgl32.Vec3) (pl BlockPlacement, ok bool) {
+	pl = BlockPlacement{X: adjacent[0], Y: adjacent[1], Z: adjacent[2], State: world.BlockState{Type: t}}
+	switch {
+	case t == world.BlockTypeItemFrame:
+		pl.State.Meta, ok = world.FacingFromNormal(adjacent[0]-hit[0], adjacent[1]-hit[1], adjacent[2]-hit[2])
+		return pl, ok
+	case t == world.BlockTypeRail:
+		// Rails start out along the view axis until they connect
+		pl.State.Meta = uint8(world.RailNorthSouth)
+		if math.Abs(float64(front[0])) > math.Abs(float64(front[2])) {
+			pl.State.Meta = uint8(world.RailEastWest)
+		}
+	case registry.HasFacingFast(t):
+		pl.State.Meta = world.FacingTowards(front[0], front[2])
+	}
+	return pl, true
+}
+
+// facesHorizontally reports whether blocks of type t store a horizontal
+// facing in their metadata.
+func facesHorizontally(t world.BlockType) bool {
+	return t == world.BlockTypeItemFrame || registry.HasFacingFast(t)
+}
+
+// PlaceDenial is why a placement was refused.
+type PlaceDenial int
+
+const (
+	PlaceAllowed           PlaceDenial = iota
+	DeniedOutOfBounds                  // below or above the world
+	DeniedOccupied                     // the space is not air
+	DeniedNoSupport                    // a rail with nothing under it, a plant off grass or dirt
+	DeniedIntersectsPlayer             // the block would go inside the player
+	DeniedOutOfReach                   // the block looked at is past the reach distance
+)
+
+// String returns a short description of the reason
+func (d PlaceDenial) String() string {
+	switch d {
+	case PlaceAllowed:
+		return "allowed"
+	case DeniedOutOfBounds:
+		return "out of bounds"
+	case DeniedOccupied:
+		return "occupied"
+	case DeniedNoSupport:
+		return "no support"
+	case DeniedIntersectsPlayer:
+		return "intersects player"
+	default:
+		return "out of reach"
+	}
+}
+
+// placeDeniedFlash is how long, in seconds, the outline stays red after a
+// placement is refused.
+const placeDeniedFlash = 0.25
+
+// canPlace returns why pl may not be placed, or PlaceAllowed: it must be in
+// the world's height, into air, on something for rails, on grass or dirt
+// for plants, and not inside the player unless it is below their feet
+// (pillaring up).
+func (p *Player) canPlace(pl BlockPlacement) PlaceDenial {
+	if pl.Y < 0 || pl.Y >= world.ChunkSizeY {
+		return DeniedOutOfBounds
+	}
+	if !p.World.IsAir(pl.X, pl.Y, pl.Z) {
+		return DeniedOccupied
+	}
+	if pl.State.Type == world.BlockTypeRail && !p.World.CanPlaceRail(pl.X, pl.Y, pl.Z) {
+		return DeniedNoSupport
+	}
+	if world.IsPlant(pl.State.Type) && !p.World.CanPlacePlant(pl.X, pl.Y, pl.Z) {
+		return DeniedNoSupport
+	}
+	placingUnderFeet := float32(pl.Y) <= p.Position[1]+0.001
+	width, height := p.GetBounds()
+	if !placingUnderFeet && physics.IntersectsBlock(p.Position, width, height, pl.X, pl.Y, pl.Z) {
+		return DeniedIntersectsPlayer
+	}
+	return PlaceAllowed
+}
+
+// TryPlace is the one way the player places blocks. It checks every
+// placement in the batch first, drops those that cannot be placed or repeat
+// an earlier position, and those the held stack has run out for outside
+// creative, then places the rest together, taking them from the held stack.
+// It returns how many blocks were placed and why the first placement
+// dropped by the checks was refused, PlaceAllowed when none was. When
+// nothing is placed because of the checks the refusal is signalled (see
+// denyPlacement).
+func (p *Player) TryPlace(batch []BlockPlacement) (placed int, denied PlaceDenial) {
+	stack := p.Inventory.GetCurrentItem()
+	if stack == nil || stack.Count <= 0 {
+		return 0, PlaceAllowed
+	}
+	available := len(batch)
+	if p.GameMode != GameModeCreative {
+		available = stack.Count
+	}
+
+	valid := make([]BlockPlacement, 0, len(batch))
+	seen := make(map[world.BlockPos]bool, len(batch))
+	var deniedAt BlockPlacement
+	for _, pl := range batch {
+		pos := world.BlockPos{X: pl.X, Y: pl.Y, Z: pl.Z}
+		if len(valid) == available || seen[pos] {
+			continue
+		}
+		if d := p.canPlace(pl); d != PlaceAllowed {
+			if denied == PlaceAllowed {
+				denied, deniedAt = d, pl
+			}
+			continue
+		}
+		seen[pos] = true
+		valid = append(valid, pl)
+	}
+	if len(valid) == 0 {
+		if denied != PlaceAllowed {
+			p.denyPlacement([3]int{deniedAt.X, deniedAt.Y, deniedAt.Z}, denied)
+		}
+		return 0, denied
+	}
+
+	for _, pl := range valid {
+		p.placeBlock(pl)
+	}
+	p.TriggerHandSwing()
+	if p.GameMode != GameModeCreative {
+		stack.Count -= len(valid)
+		if stack.Count <= 0 {
+			p.Inventory.MainInventory[p.Inventory.CurrentItem] = nil
+		}
+	}
+	return len(valid), denied
+}
+
+// denyPlacement flashes the outline of the block at pos red and fires
+// OnPlaceDenied.
+func (p *Player) denyPlacement(pos [3]int, reason PlaceDenial) {
+	p.DeniedBlock = pos
+	p.deniedTimer = placeDeniedFlash
+	if p.OnPlaceDenied != nil {
+		p.OnPlaceDenied(pos[0], pos[1], pos[2], reason)
+	}
+}
+
+// checkPlaceReach is for a right click that hits nothing in reach while
+// holding a placeable block: when a ray twice as long hits a block, the
+// block was just too far away and the placement is refused as out of reach.
+func (p *Player) checkPlaceReach() {
+	stack := p.Inventory.GetCurrentItem()
+	if stack == nil || stack.Count <= 0 || stack.Type == world.BlockTypeAir || isHeldOnlyItem(stack.Type) {
+		return
+	}
+	cam := p.Camera()
+	result := physics.Raycast(cam.Eye, cam.Front, physics.MinReachDistance, 2*physics.MaxReachDistance, p.World)
+	if result.Hit {
+		p.denyPlacement(result.HitPosition, DeniedOutOfReach)
+	}
+}
+
+// PlaceDeniedFlash returns how red the outline of DeniedBlock is drawn,
+// from 1 just after a placement was refused down to 0. It is always 0 with
+// flashing effects disabled, leaving the usual outline and the click.
+func (p *Player) PlaceDeniedFlash() float32 {
+	if p.deniedTimer <= 0 || config.GetDisableFlashing() {
+		return 0
+	}
+	return float32(p.deniedTimer / placeDeniedFlash)
+}
+
+// placeBlock puts a checked placement into the world with everything that
+// comes with it: its block entity, rail shape, neighbour updates, the place
+// event and the first tick of blocks that flow or melt.
+func (p *Player) placeBlock(pl BlockPlacement) {
+	x, y, z, t := pl.X, pl.Y, pl.Z, pl.State.Type
+	p.World.SetState(x, y, z, pl.State)
+	p.placeBlockEntity(x, y, z, t, pl.State.Meta)
+	if t == world.BlockTypeRail {
+		p.World.UpdateRailShape(x, y, z)
+	}
+	p.World.NotifyNeighbors(x, y, z)
+	if p.OnBlockPlace != nil {
+		p.OnBlockPlace(x, y, z, t, p.World.GetMeta(x, y, z))
+	}
+	// Schedule initial tick for fluid blocks so they begin flowing
+	switch t {
+	case world.BlockTypeWater:
+		p.World.ScheduleBlockTick(x, y, z, world.WaterTickRate, 0)
+	case world.BlockTypeLava:
+		p.World.ScheduleBlockTick(x, y, z, world.LavaTickRate, 0)
+	case world.BlockTypeSnowLayer:
+		p.World.ScheduleBlockTick(x, y, z, world.SnowTickRate, 0)
+	}
+}
@@ -7,24 +7,30 @@ import (
 
 func (p *Player) Update(dt float64, im *input.InputManager) {
 	defer profiling.Track("player.Update.total")()
-	// Update hovered block
-	if !p.IsInventoryOpen {
-		p.UpdateHoveredBlock()
-	} else {
+	if p.IsDead() {
+		return
+	}
+	// The hovered block is the one highlighted last frame (see SetPartialTick)
+	if p.IsInventoryOpen {
 		p.HasHoveredBlock = false
 	}
 
 	// Check collisions with items
 	p.CheckEntityCollisions(dt)
 
-	// Process movement (handles flight timer as well)
-	p.UpdatePosition(dt, im)
+	// Movement keys; the movement itself runs in Tick
+	if p.Vehicle == nil {
+		p.updateMovementInput(dt, im)
+	}
 
 	// Mining logic
 	justPressed := im.JustPressed(input.ActionMouseLeft)
 	isHeld := im.IsActive(input.ActionMouseLeft)
 
-	if !p.IsInventoryOpen && (justPressed || isHeld) {
+	if !p.IsInventoryOpen && justPressed && (p.attackMob() || p.attackVehicle()) {
+		p.TriggerHandSwing()
+		p.ResetMining()
+	} else if !p.IsInventoryOpen && (justPressed || isHeld) {
 		if p.HasHoveredBlock {
 			p.UpdateMining(dt, justPressed && !isHeld)
 		} else if justPressed {
@@ -39,12 +45,6 @@ func (p *Player) Update(dt float64, im *input.InputManager) {
 		p.breakCooldown -= dt
 	}
 
-	// Updates head bobbing animation based on player movement
-	p.UpdateHeadBob()
-
-	// Update camera bobbing (for view bobbing)
-	p.UpdateCameraBob()
-
 	// Update equipped item animation
 	p.updateEquippedItem(float32(dt))
 
@@ -63,11 +63,42 @@ func (p *Player) Update(dt float64, im *input.InputManager) {
 		p.HandSwingProgress = 0
 	}
 
+	// Fade the refused placement flash
+	if p.deniedTimer > 0 {
+		p.deniedTimer -= dt
+	}
+
 	// Update render arm sway
 	p.UpdateRenderArm(dt)
 
+	// Low-health heartbeat (drives the HUD border and FOV pulse)
+	p.updateHeartbeat(dt)
+
 	// Update inventory item animations (for pickup pop effect)
 	if p.Inventory != nil {
 		p.Inventory.UpdateAnimations()
 	}
 }
+
+// Tick runs one game tick of the player's health and hunger, their
+// movement or steering the vehicle they ride, and the view bobbing that
+// follows it. Update reads the keys it acts on each frame. A dead player
+// stays where they fell.
+func (p *Player) Tick(im *input.InputManager) {
+	p.tickHealth()
+	if p.IsDead() {
+		return
+	}
+	p.tickWorkbench()
+	if p.Vehicle != nil {
+		p.updateRiding(im)
+	} else {
+		p.tickMovement(im)
+	}
+
+	// Updates head bobbing animation based on player movement
+	p.UpdateHeadBob()
+
+	// Update camera bobbing (for view bobbing)
+	p.UpdateCameraBob()
+}
@@ -0,0 +1,78 @@
+package player
+
+import (
+	"mini-mc/internal/config"
+	"mini-mc/internal/input"
+)
+
+// sprintFoodLevel is the food level at or below which a survival player
+// cannot sprint, as in 1.8.9.
+const sprintFoodLevel = 6
+
+// updateSprint starts and stops sprinting from the sprint key and forward
+// double taps. In hold mode the key sprints while it is held; in toggle mode
+// a press switches sprinting on until the next press. Either way sprinting
+// only runs while moving forward, and ends on sneaking, running into a wall
+// or going hungry; a toggled sprint picks up again once it can. A double tap
+// of forward sprints until one of those stops it.
+func (p *Player) updateSprint(im *input.InputManager) {
+	doubleTap := false
+	if im.JustPressed(input.ActionMoveForward) {
+		if p.lastForwardPressTime >= 0 && p.lastForwardPressTime < 0.3 {
+			doubleTap = p.OnGround || p.IsFlying
+			p.lastForwardPressTime = -1
+		} else {
+			p.lastForwardPressTime = 0
+		}
+	}
+
+	var keyWants bool
+	if config.GetSprintMode() == config.SprintToggle {
+		if im.JustPressed(input.ActionSprint) {
+			p.sprintToggled = !p.sprintToggled
+		}
+		keyWants = p.sprintToggled
+	} else {
+		p.sprintToggled = false
+		keyWants = im.IsActive(input.ActionSprint)
+	}
+
+	switch {
+	case p.IsSneaking:
+		p.stopSprinting()
+	case keyWants && !p.sprintFromKey:
+		if p.canStartSprint(im) {
+			p.IsSprinting = true
+			p.sprintFromKey = true
+		}
+	case !keyWants && p.sprintFromKey:
+		p.stopSprinting()
+	}
+	if doubleTap && !p.IsSprinting && !p.IsSneaking && p.canStartSprint(im) {
+		p.IsSprinting = true
+	}
+}
+
+// canStartSprint reports whether sprinting may begin now: moving forward
+// and not too hungry.
+func (p *Player) canStartSprint(im *input.InputManager) bool {
+	return im.IsActive(input.ActionMoveForward) && p.canSprint()
+}
+
+// canSprint reports whether the player is fed enough to sprint. Flying
+// players, and creative ones, never go hungry.
+func (p *Player) canSprint() bool {
+	return p.GameMode == GameModeCreative || p.IsFlying || p.FoodLevel > sprintFoodLevel
+}
+
+// stopSprinting ends the current sprint. A toggled sprint stays toggled and
+// resumes when it can.
+func (p *Player) stopSprinting() {
+	p.IsSprinting = false
+	p.sprintFromKey = false
+}
+
+// SprintToggled reports whether the sprint key has been toggled on.
+func (p *Player) SprintToggled() bool {
+	return p.sprintToggled
+}
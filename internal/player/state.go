@@ -1,6 +1,7 @@
 package player
 
 import (
+	"mini-mc/internal/entity"
 	"mini-mc/internal/inventory"
 	"mini-mc/internal/item"
 	"mini-mc/internal/world"
@@ -20,6 +21,14 @@ const (
 	GameModeCreative
 )
 
+// String returns the mode's name as saved in world metadata.
+func (m GameMode) String() string {
+	if m == GameModeCreative {
+		return "creative"
+	}
+	return "survival"
+}
+
 type Player struct {
 	GameMode     GameMode
 	PrevPosition mgl32.Vec3
@@ -38,12 +47,16 @@ type Player struct {
 	CamPitch         float64
 	LastMouseX       float64
 	LastMouseY       float64
-	MouseX           float64 // Current absolute mouse X
-	MouseY           float64 // Current absolute mouse Y
+	MouseX           float64 // Current cursor X, in UI units (see ui.Screen)
+	MouseY           float64 // Current cursor Y, in UI units
 	FirstMouse       bool
 
+	// Perspective is where the world is drawn from, see RenderCamera
+	Perspective Perspective
+
 	DistanceWalkedModified     float64
 	PrevDistanceWalkedModified float64
+	nextStepDistance           int // DistanceWalkedModified at the next footstep
 
 	// View bobbing animation
 	PrevCameraYaw   float32
@@ -59,8 +72,12 @@ type Player struct {
 
 	// Interaction
 	HoveredBlock    [3]int
+	HoveredFace     [3]int // normal of the face of HoveredBlock looked at
 	HasHoveredBlock bool
 
+	// Builder is the creative building assist, see BuilderMode
+	Builder BuilderMode
+
 	// Mining state
 	IsBreaking    bool
 	BreakingBlock [3]int
@@ -71,6 +88,16 @@ type Player struct {
 	// Inventory
 	Inventory       *inventory.Inventory
 	IsInventoryOpen bool
+	// OpenBlockEntity is the block entity whose screen is open in place of the
+	// inventory (e.g. a furnace), or nil.
+	OpenBlockEntity world.BlockEntity
+	// Workbench is the grid of the crafting table whose screen is open, or
+	// nil; WorkbenchPos is where that table stands.
+	Workbench    *inventory.CraftingGrid
+	WorkbenchPos [3]int
+
+	// Vehicle is the boat or minecart the player is riding, or nil.
+	Vehicle entity.Vehicle
 
 	// Hand animation state
 	handSwingTimer    float64
@@ -78,12 +105,16 @@ type Player struct {
 	HandSwingProgress float32
 	EquipProgress     float32
 	EquippedItem      *item.ItemStack
+	EquippedSlot      int // hotbar slot EquippedItem was taken from
 
 	// Block breaking cooldown
 	breakCooldown float64
 
 	// Water state tracking
 	wasInWater bool
+	// inWaterLastTick is whether the player was in water at the end of the
+	// last tick, to splash once on the way in.
+	inWaterLastTick bool
 
 	// Flight mode double-tap detection
 	lastSpacePressTime float64
@@ -92,8 +123,57 @@ type Player struct {
 	// Forward double-tap detection for sprint
 	lastForwardPressTime float64
 
+	// Sprint key state (see updateSprint)
+	sprintToggled bool // toggle mode: the key switched sprinting on
+	sprintFromKey bool // the current sprint was started by the key, not a double tap
+
+	// Jump assist timers, in game ticks (see updateJumpAssist)
+	coyoteTicksLeft     float64
+	jumpBufferTicksLeft float64
+	jumpPressed         bool // since the last tick
+
+	// PartialTick is how far the frame drawn is from the last game tick to
+	// the next, 0-1, for smoothing movement between ticks.
+	PartialTick float32
+
 	// Events
 	OnInventoryStateChange func(isOpen bool)
+	// OnHeartbeat fires on each low-health heartbeat with the current LowHealthIntensity.
+	OnHeartbeat func(intensity float32)
+	// OnToolBreak fires when a held tool runs out of durability, for the break
+	// sound and particle burst.
+	OnToolBreak func(tool item.ItemStack, pos mgl32.Vec3)
+	// OnBlockBreak and OnBlockPlace fire when the player breaks or places a
+	// block, for its sound and particles. Breaking passes the old block.
+	OnBlockBreak func(x, y, z int, block world.BlockType, meta uint8)
+	OnBlockPlace func(x, y, z int, block world.BlockType, meta uint8)
+	// OnPlaceDenied fires when a placement is refused, with the block that
+	// was aimed at and why.
+	OnPlaceDenied func(x, y, z int, reason PlaceDenial)
+	// OnEntityHit fires when the player hits an entity; crit is set for a
+	// falling hit.
+	OnEntityHit func(pos mgl32.Vec3, crit bool)
+	// OnStep fires for each footstep, jump and landing with the block trodden
+	// on and how loud and high its step sound plays, relative to normal.
+	OnStep func(pos mgl32.Vec3, block world.BlockType, volume, pitch float32)
+	// OnItemPickup fires when the player picks up items lying at pos.
+	OnItemPickup func(pos mgl32.Vec3)
+	// OnLand fires when the player lands on a block after falling
+	// fallDistance blocks, and OnSprintDust each tick they sprint on one,
+	// for the dust kicked up at pos, under their feet.
+	OnLand       func(pos mgl32.Vec3, block world.BlockType, fallDistance float32)
+	OnSprintDust func(pos, vel mgl32.Vec3, block world.BlockType)
+	// OnSplash fires when the player falls into water, with where they broke
+	// the surface and how fast they were moving.
+	OnSplash func(pos, vel mgl32.Vec3)
+
+	// Refused placement flash (see denyPlacement)
+	DeniedBlock [3]int
+	deniedTimer float64
+
+	// Low-health heartbeat state (see updateHeartbeat)
+	heartbeatTimer     float64
+	heartbeatPulseTime float64 // seconds since the last beat, -1 when idle
 
 	Health       float32
 	MaxHealth    float32
@@ -101,6 +181,13 @@ type Player struct {
 	MaxFoodLevel float32
 	FallDistance float32
 
+	// Hunger and hurt state (see health.go)
+	FoodSaturation float32
+	foodExhaustion float32
+	foodTimer      int     // game ticks toward the next heal or starve
+	HurtTicks      int     // game ticks left of the hurt flash and cooldown
+	lastDamage     float32 // the hit that started HurtTicks
+
 	// Jump diagnostics
 	JumpStartY    float32
 	MaxJumpHeight float32
@@ -130,6 +217,7 @@ func New(world *world.World, mode GameMode) *Player {
 		lastSpacePressTime:   -1,
 		lastSpaceState:       false,
 		lastForwardPressTime: -1,
+		heartbeatPulseTime:   -1,
 		PrevCameraYaw:        0,
 		CameraYaw:            0,
 		PrevCameraPitch:      0,
@@ -142,6 +230,7 @@ func New(world *world.World, mode GameMode) *Player {
 		MaxHealth:            20.0,
 		FoodLevel:            20.0,
 		MaxFoodLevel:         20.0,
+		FoodSaturation:       5.0,
 		FallDistance:         0,
 		JumpStartY:           0,
 		MaxJumpHeight:        0,
@@ -154,32 +243,55 @@ func (p *Player) SetInventoryOpen(open bool) {
 		return
 	}
 	p.IsInventoryOpen = open
+	if !open {
+		p.OpenBlockEntity = nil
+
+		// Items left in the crafting grids go back to the inventory, or drop if it is full
+		left := p.Inventory.ClearCraftingMatrix()
+		if p.Workbench != nil {
+			left = append(left, p.Workbench.Clear()...)
+			p.Workbench = nil
+		}
+		for _, stack := range left {
+			if !p.Inventory.AddItem(&stack) {
+				p.spawnItemEntity(stack)
+			}
+		}
+	}
 	if p.OnInventoryStateChange != nil {
 		p.OnInventoryStateChange(open)
 	}
 }
 
+// FeetPosition returns where the player stands, for the mobs hunting them.
+func (p *Player) FeetPosition() mgl32.Vec3 {
+	return p.Position
+}
+
 func (p *Player) GetEyePosition() mgl32.Vec3 {
-	eyeOffset := PlayerEyeHeight
+	return p.Position.Add(mgl32.Vec3{0, p.eyeHeight(), 0})
+}
+
+// RenderPosition returns where the player is drawn: between their position
+// at the last two game ticks, PartialTick of the way along.
+func (p *Player) RenderPosition() mgl32.Vec3 {
+	return p.positionAt(p.PartialTick)
+}
+
+// positionAt returns the position partialTicks of the way from the last
+// tick's to this one's.
+func (p *Player) positionAt(partialTicks float32) mgl32.Vec3 {
+	return p.PrevPosition.Add(p.Position.Sub(p.PrevPosition).Mul(partialTicks))
+}
+
+func (p *Player) eyeHeight() float32 {
 	if p.IsSneaking {
-		eyeOffset -= 0.08
+		return PlayerEyeHeight - 0.08
 	}
-	return p.Position.Add(mgl32.Vec3{0, float32(eyeOffset), 0})
+	return PlayerEyeHeight
 }
 
 func (p *Player) GetBounds() (width, height float32) {
 	// Player width is 0.6 (radius 0.3 * 2), height is 1.8
 	return 0.6, PlayerHeight
 }
-
-func (p *Player) ApplyDamage(amount float32) {
-	if p.GameMode == GameModeCreative {
-		return
-	}
-
-	p.Health -= amount
-	if p.Health < 0 {
-		p.Health = 0
-		// TODO: Handle death (respawn, etc)
-	}
-}
@@ -0,0 +1,203 @@
+package player
+
+import (
+	"math"
+
+	"mini-mc/internal/entity"
+	"mini-mc/internal/input"
+	"mini-mc/internal/item"
+	"mini-mc/internal/physics"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// boatPlaceStep is the ray step used to find water or ground for a boat.
+const boatPlaceStep = 0.1
+
+// hoveredVehicle returns the vehicle under the crosshair, if it is within
+// reach and nearer than the hovered block.
+func (p *Player) hoveredVehicle() entity.Vehicle {
+	cam := p.Camera()
+	front, eye := cam.Front, cam.Eye
+
+	reach := float32(physics.MaxReachDistance)
+	if result := physics.Raycast(eye, front, physics.MinReachDistance, physics.MaxReachDistance, p.World); result.Hit {
+		reach = result.Distance
+	}
+
+	var nearest entity.Vehicle
+	for _, e := range p.World.GetEntities() {
+		vehicle, ok := e.(entity.Vehicle)
+		if !ok || vehicle.IsDead() {
+			continue
+		}
+		w, h := vehicle.GetBounds()
+		pos := vehicle.Position()
+		minB := pos.Sub(mgl32.Vec3{w / 2, 0, w / 2})
+		maxB := pos.Add(mgl32.Vec3{w / 2, h, w / 2})
+		if dist, hit := physics.RayIntersectsAABB(eye, front, minB, maxB); hit && dist <= reach {
+			nearest, reach = vehicle, dist
+		}
+	}
+	return nearest
+}
+
+// useVehicle handles a right-click for vehicles: mounting the hovered one or
+// placing a held boat or minecart. It reports whether the click was consumed.
+func (p *Player) useVehicle() bool {
+	if p.Vehicle != nil {
+		return false
+	}
+	if vehicle := p.hoveredVehicle(); vehicle != nil && vehicle.Rider() == nil {
+		p.Mount(vehicle)
+		return true
+	}
+
+	held := p.Inventory.GetCurrentItem()
+	if held == nil || held.Count <= 0 {
+		return false
+	}
+	var vehicle entity.Vehicle
+	switch held.Type {
+	case world.BlockTypeBoat:
+		if pos, ok := p.findBoatPlacement(); ok {
+			vehicle = entity.NewBoat(p.World, pos, float32(p.CamYaw))
+		}
+	case world.BlockTypeMinecart:
+		// Minecarts only go on rails
+		if p.HasHoveredBlock {
+			x, y, z := p.HoveredBlock[0], p.HoveredBlock[1], p.HoveredBlock[2]
+			if p.World.Get(x, y, z) == world.BlockTypeRail {
+				pos := mgl32.Vec3{float32(x) + 0.5, float32(y), float32(z) + 0.5}
+				vehicle = entity.NewMinecart(p.World, pos, float32(p.CamYaw))
+			}
+		}
+	}
+	if vehicle == nil {
+		return false
+	}
+	p.World.AddEntity(vehicle)
+	p.TriggerHandSwing()
+	if p.GameMode != GameModeCreative {
+		held.Count--
+		if held.Count <= 0 {
+			p.Inventory.MainInventory[p.Inventory.CurrentItem] = nil
+		}
+	}
+	return true
+}
+
+// findBoatPlacement marches along the view ray to the first water or solid
+// block in reach and returns where a boat dropped there would sit.
+func (p *Player) findBoatPlacement() (mgl32.Vec3, bool) {
+	cam := p.Camera()
+	front, eye := cam.Front, cam.Eye
+	for dist := float32(physics.MinReachDistance); dist <= physics.MaxReachDistance; dist += boatPlaceStep {
+		pt := eye.Add(front.Mul(dist))
+		x := int(math.Floor(float64(pt.X())))
+		y := int(math.Floor(float64(pt.Y())))
+		z := int(math.Floor(float64(pt.Z())))
+		bt := p.World.Get(x, y, z)
+		if bt != world.BlockTypeWater && !world.BlockSolidTable[bt] {
+			continue
+		}
+		if world.BlockSolidTable[p.World.Get(x, y+1, z)] {
+			return mgl32.Vec3{}, false
+		}
+		return mgl32.Vec3{pt.X(), float32(y + 1), pt.Z()}, true
+	}
+	return mgl32.Vec3{}, false
+}
+
+// attackVehicle breaks the hovered vehicle, dropping it as an item outside
+// creative mode. It reports whether a vehicle was hit.
+func (p *Player) attackVehicle() bool {
+	vehicle := p.hoveredVehicle()
+	if vehicle == nil || vehicle.Rider() != nil {
+		return false
+	}
+	vehicle.SetDead()
+	if p.OnEntityHit != nil {
+		p.OnEntityHit(vehicle.Position().Add(mgl32.Vec3{0, 0.5, 0}), p.isCriticalHit())
+	}
+	if p.GameMode != GameModeCreative {
+		dropType := world.BlockTypeBoat
+		if _, ok := vehicle.(*entity.Minecart); ok {
+			dropType = world.BlockTypeMinecart
+		}
+		pos := vehicle.Position().Add(mgl32.Vec3{0, 0.5, 0})
+		p.World.AddEntity(entity.NewItemEntity(p.World, p.World.Rand(), pos, item.NewItemStack(dropType, 1)))
+	}
+	return true
+}
+
+// isCriticalHit reports whether a hit now would be a critical one: like
+// 1.8.9, while falling and not on the ground, flying, riding or in water.
+func (p *Player) isCriticalHit() bool {
+	return p.Velocity[1] < 0 && !p.OnGround && !p.IsFlying && p.Vehicle == nil && !p.IsInWater()
+}
+
+// Mount seats the player in a vehicle, which takes over movement until the
+// player dismounts.
+func (p *Player) Mount(vehicle entity.Vehicle) {
+	p.Vehicle = vehicle
+	vehicle.SetRider(p)
+	p.stopSprinting()
+	p.IsSneaking = false
+	p.IsFlying = false
+	p.Velocity = mgl32.Vec3{}
+	p.Position = vehicle.SeatPosition()
+}
+
+// Dismount leaves the current vehicle and stands the player on top of it.
+func (p *Player) Dismount() {
+	vehicle := p.Vehicle
+	if vehicle == nil {
+		return
+	}
+	p.Vehicle = nil
+	vehicle.SetRider(nil)
+	_, h := vehicle.GetBounds()
+	p.Position = vehicle.Position().Add(mgl32.Vec3{0, h, 0})
+	p.PrevPosition = p.Position
+	p.Velocity = mgl32.Vec3{}
+	p.FallDistance = 0
+}
+
+// FollowVehicle moves the player to the vehicle's seat; called by the
+// vehicle after it moves.
+func (p *Player) FollowVehicle(seat mgl32.Vec3) {
+	p.Position = seat
+}
+
+// updateRiding replaces normal movement while in a vehicle: WASD steers it
+// and sneak gets out.
+func (p *Player) updateRiding(im *input.InputManager) {
+	p.PrevPosition = p.Position
+	p.Velocity = mgl32.Vec3{}
+	p.FallDistance = 0
+	p.OnGround = false
+
+	if p.Vehicle.IsDead() || (!p.IsInventoryOpen && im.IsActive(input.ActionSneak)) {
+		p.Dismount()
+		return
+	}
+
+	var forward, turn float32
+	if !p.IsInventoryOpen {
+		if im.IsActive(input.ActionMoveForward) {
+			forward++
+		}
+		if im.IsActive(input.ActionMoveBackward) {
+			forward--
+		}
+		if im.IsActive(input.ActionMoveRight) {
+			turn++
+		}
+		if im.IsActive(input.ActionMoveLeft) {
+			turn--
+		}
+	}
+	p.Vehicle.Steer(forward, turn)
+}
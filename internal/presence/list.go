@@ -0,0 +1,158 @@
+// Package presence tracks the players connected to a world: who is online,
+// their latency, and the join and leave messages sent to everyone else. It
+// does not depend on a transport; the server calls it as connections come
+// and go, and the client shows the list it is sent.
+package presence
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxNameLength is the longest player name, as in 1.8.9.
+const MaxNameLength = 16
+
+// Entry is one connected player.
+type Entry struct {
+	Name string
+	Ping time.Duration // round trip; negative while unmeasured
+}
+
+// SignalBars returns how many of the five signal bars the tab list shows
+// for the entry, with 1.8.9's thresholds. It is 0 while the ping is unknown.
+func (e Entry) SignalBars() int {
+	switch {
+	case e.Ping < 0:
+		return 0
+	case e.Ping < 150*time.Millisecond:
+		return 5
+	case e.Ping < 300*time.Millisecond:
+		return 4
+	case e.Ping < 600*time.Millisecond:
+		return 3
+	case e.Ping < time.Second:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// List is the set of connected players. It is safe for concurrent use.
+type List struct {
+	mu      sync.Mutex
+	entries []Entry
+
+	// OnMessage, if set, receives the join and leave messages to broadcast.
+	// It is called without the list locked.
+	OnMessage func(text string)
+}
+
+// ValidName reports whether name can be a player name: 1 to MaxNameLength
+// letters, digits or underscores.
+func ValidName(name string) bool {
+	if name == "" || len(name) > MaxNameLength {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// Join adds a player and announces them. If the name is taken, ignoring
+// case, the player gets the first free name with a number appended; the
+// name actually given is returned.
+func (l *List) Join(requested string) (string, error) {
+	if !ValidName(requested) {
+		return "", fmt.Errorf("invalid player name %q", requested)
+	}
+	l.mu.Lock()
+	name := requested
+	for n := 2; l.indexLocked(name) >= 0; n++ {
+		suffix := strconv.Itoa(n)
+		name = requested[:min(len(requested), MaxNameLength-len(suffix))] + suffix
+	}
+	l.entries = append(l.entries, Entry{Name: name, Ping: -1})
+	slices.SortFunc(l.entries, func(a, b Entry) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	l.mu.Unlock()
+
+	l.announce(name + " joined the game")
+	return name, nil
+}
+
+// Leave removes a player and announces it.
+func (l *List) Leave(name string) error {
+	l.mu.Lock()
+	i := l.indexLocked(name)
+	if i < 0 {
+		l.mu.Unlock()
+		return errors.New("no player named " + name)
+	}
+	name = l.entries[i].Name
+	l.entries = slices.Delete(l.entries, i, i+1)
+	l.mu.Unlock()
+
+	l.announce(name + " left the game")
+	return nil
+}
+
+// SetPing records a player's latency.
+func (l *List) SetPing(name string, ping time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if i := l.indexLocked(name); i >= 0 {
+		l.entries[i].Ping = ping
+	}
+}
+
+// Replace sets the list to the entries a server sent, without announcing
+// anyone; the server sends the messages itself.
+func (l *List) Replace(entries []Entry) {
+	entries = slices.Clone(entries)
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = entries
+}
+
+// Entries returns the connected players sorted by name.
+func (l *List) Entries() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.entries)
+}
+
+// Names returns the names of the connected players, sorted.
+func (l *List) Names() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	names := make([]string, len(l.entries))
+	for i, e := range l.entries {
+		names[i] = e.Name
+	}
+	return names
+}
+
+// indexLocked finds a player by name, ignoring case.
+func (l *List) indexLocked(name string) int {
+	return slices.IndexFunc(l.entries, func(e Entry) bool {
+		return strings.EqualFold(e.Name, name)
+	})
+}
+
+func (l *List) announce(text string) {
+	if l.OnMessage != nil {
+		l.OnMessage(text)
+	}
+}
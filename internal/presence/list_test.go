@@ -0,0 +1,64 @@
+package presence
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestJoinLeaveAndCollisions(t *testing.T) {
+	var messages []string
+	l := &List{OnMessage: func(text string) { messages = append(messages, text) }}
+
+	for _, name := range []string{"steve", "Alex", "Steve"} {
+		if _, err := l.Join(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	long, err := l.Join("ABCDEFGHIJKLMNOP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := l.Join("abcdefghijklmnop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if long != "ABCDEFGHIJKLMNOP" || again != "abcdefghijklmno2" {
+		t.Errorf("long names %q, %q", long, again)
+	}
+	if _, err := l.Join("no spaces"); err == nil {
+		t.Error("name with a space accepted")
+	}
+
+	want := []string{"abcdefghijklmno2", "ABCDEFGHIJKLMNOP", "Alex", "steve", "Steve2"}
+	if got := l.Names(); !slices.Equal(got, want) {
+		t.Errorf("names %v, want %v", got, want)
+	}
+
+	if err := l.Leave("STEVE"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Leave("herobrine"); err == nil {
+		t.Error("unknown player left")
+	}
+	if messages[2] != "Steve2 joined the game" || messages[len(messages)-1] != "steve left the game" {
+		t.Errorf("messages %q", messages)
+	}
+}
+
+func TestSignalBars(t *testing.T) {
+	l := &List{}
+	name, _ := l.Join("alex")
+	if bars := l.Entries()[0].SignalBars(); bars != 0 {
+		t.Errorf("unmeasured ping shows %d bars", bars)
+	}
+	for _, c := range []struct {
+		ping time.Duration
+		bars int
+	}{{20 * time.Millisecond, 5}, {200 * time.Millisecond, 4}, {700 * time.Millisecond, 2}, {3 * time.Second, 1}} {
+		l.SetPing(name, c.ping)
+		if bars := l.Entries()[0].SignalBars(); bars != c.bars {
+			t.Errorf("ping %v shows %d bars, want %d", c.ping, bars, c.bars)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package profiling
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// BenchmarkFrame is one frame of a benchmark run.
+type BenchmarkFrame struct {
+	At            time.Duration // since the run started
+	Frame         time.Duration // since the frame before
+	Render        time.Duration // drawing it, on the CPU
+	DrawCalls     int           // terrain draw calls
+	PendingMeshes int           // chunk meshes still being built after it
+	Pos           [3]float32    // where the camera's feet were
+}
+
+// Benchmark is what a benchmark run measured, for comparing builds of the
+// renderer over the same path.
+type Benchmark struct {
+	Seed           int64
+	RenderDistance int
+	Renderer       string // the GL renderer, as the driver names it
+	CPUs           int
+	GoVersion      string
+
+	Frames         []BenchmarkFrame
+	ChunkLatencies []time.Duration // from being asked for to loaded
+	MeshLatencies  []time.Duration // from being queued to uploaded
+}
+
+// Stats sums up a set of durations, in milliseconds.
+type Stats struct {
+	Count int     `json:"count"`
+	Mean  float64 `json:"mean"`
+	P50   float64 `json:"p50"`
+	P95   float64 `json:"p95"`
+	P99   float64 `json:"p99"`
+	Max   float64 `json:"max"`
+}
+
+// BenchmarkSummary is the report of a benchmark run.
+type BenchmarkSummary struct {
+	Seed           int64   `json:"seed"`
+	RenderDistance int     `json:"renderDistance"`
+	Renderer       string  `json:"renderer"`
+	CPUs           int     `json:"cpus"`
+	GoVersion      string  `json:"goVersion"`
+	Seconds        float64 `json:"seconds"`
+	Frames         int     `json:"frames"`
+	AverageFPS     float64 `json:"averageFps"`
+
+	FrameTime    Stats `json:"frameTimeMs"`
+	RenderTime   Stats `json:"renderTimeMs"`
+	ChunkLatency Stats `json:"chunkLatencyMs"`
+	MeshLatency  Stats `json:"meshLatencyMs"`
+
+	DrawCallsMean float64 `json:"drawCallsMean"`
+	DrawCallsMax  int     `json:"drawCallsMax"`
+}
+
+// statsOf sums up ds.
+func statsOf(ds []time.Duration) Stats {
+	if len(ds) == 0 {
+		return Stats{}
+	}
+	sorted := slices.Sorted(slices.Values(ds))
+	ms := func(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
+	// The nearest rank: the smallest value at least p of them are up to
+	at := func(p float64) float64 {
+		return ms(sorted[max(int(math.Ceil(p*float64(len(sorted))))-1, 0)])
+	}
+	var total time.Duration
+	for _, d := range ds {
+		total += d
+	}
+	return Stats{
+		Count: len(ds),
+		Mean:  ms(total / time.Duration(len(ds))),
+		P50:   at(0.50),
+		P95:   at(0.95),
+		P99:   at(0.99),
+		Max:   ms(sorted[len(sorted)-1]),
+	}
+}
+
+// Summary returns the report of the run.
+func (b *Benchmark) Summary() BenchmarkSummary {
+	s := BenchmarkSummary{
+		Seed:           b.Seed,
+		RenderDistance: b.RenderDistance,
+		Renderer:       b.Renderer,
+		CPUs:           b.CPUs,
+		GoVersion:      b.GoVersion,
+		Frames:         len(b.Frames),
+		ChunkLatency:   statsOf(b.ChunkLatencies),
+		MeshLatency:    statsOf(b.MeshLatencies),
+	}
+	if len(b.Frames) == 0 {
+		return s
+	}
+	frames := make([]time.Duration, len(b.Frames))
+	renders := make([]time.Duration, len(b.Frames))
+	draws := 0
+	for i, f := range b.Frames {
+		frames[i], renders[i] = f.Frame, f.Render
+		draws += f.DrawCalls
+		s.DrawCallsMax = max(s.DrawCallsMax, f.DrawCalls)
+	}
+	s.Seconds = b.Frames[len(b.Frames)-1].At.Seconds()
+	if s.Seconds > 0 {
+		s.AverageFPS = float64(len(b.Frames)) / s.Seconds
+	}
+	s.FrameTime = statsOf(frames)
+	s.RenderTime = statsOf(renders)
+	s.DrawCallsMean = float64(draws) / float64(len(b.Frames))
+	return s
+}
+
+// WriteJSON writes the run's Summary as indented JSON.
+func (b *Benchmark) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b.Summary())
+}
+
+// WriteCSV writes the run's frames, one a row under a header.
+func (b *Benchmark) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"time_s", "frame_ms", "render_ms", "draw_calls", "pending_meshes", "x", "y", "z"})
+	ms := func(d time.Duration) string { return strconv.FormatFloat(float64(d.Microseconds())/1000, 'f', 3, 64) }
+	coord := func(v float32) string { return strconv.FormatFloat(float64(v), 'f', 2, 32) }
+	for _, f := range b.Frames {
+		cw.Write([]string{
+			strconv.FormatFloat(f.At.Seconds(), 'f', 3, 64),
+			ms(f.Frame),
+			ms(f.Render),
+			strconv.Itoa(f.DrawCalls),
+			strconv.Itoa(f.PendingMeshes),
+			coord(f.Pos[0]), coord(f.Pos[1]), coord(f.Pos[2]),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,42 @@
+package profiling
+
+import (
+	"sync"
+	"time"
+)
+
+// maxLatencySamples bounds the samples a Latencies holds between calls to
+// Take; past it the oldest are dropped.
+const maxLatencySamples = 4096
+
+// Latencies collects how long jobs of one kind took from being asked for
+// to being done, such as chunk generation, for benchmark reports. The zero
+// value is ready to use, from several goroutines.
+type Latencies struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int // the oldest sample, overwritten next once full
+}
+
+// Add records a job that took d.
+func (l *Latencies) Add(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.samples) < maxLatencySamples {
+		l.samples = append(l.samples, d)
+		return
+	}
+	l.samples[l.next] = d
+	l.next = (l.next + 1) % maxLatencySamples
+}
+
+// Take appends the samples added since the last Take to into, oldest
+// first, and forgets them.
+func (l *Latencies) Take(into []time.Duration) []time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	into = append(into, l.samples[l.next:]...)
+	into = append(into, l.samples[:l.next]...)
+	l.samples, l.next = l.samples[:0], 0
+	return into
+}
@@ -114,6 +114,79 @@ func Add(name string, d time.Duration) {
 	mu.Unlock()
 }
 
+// Section is one part of a Breakdown.
+type Section struct {
+	Name string        // the part of the tracked names after the prefix, up to the next dot
+	Time time.Duration // per frame, averaged over the last second
+}
+
+// Breakdown returns the time tracked under prefix per frame over the last
+// second, grouped by the next part of the name and longest first. A name
+// that is a whole group, such as "renderer.renderBlocks" under "renderer.",
+// is taken to time everything under it, so it stands for the group.
+func Breakdown(prefix string) []Section {
+	cutoff := time.Now().Add(-1 * time.Second)
+	aggregated := make(map[string]time.Duration)
+	frames := 0
+	mu.Lock()
+	for _, s := range rollingSamples {
+		if s.t.Before(cutoff) {
+			continue
+		}
+		frames++
+		for k, v := range s.totals {
+			aggregated[k] += v
+		}
+	}
+	if frames == 0 {
+		frames = 1
+		for k, v := range frameTotals {
+			aggregated[k] += v
+		}
+	}
+	mu.Unlock()
+
+	type group struct {
+		own, nested time.Duration
+		timed       bool // the group's own name was tracked
+	}
+	groups := make(map[string]*group)
+	for name, d := range aggregated {
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok || rest == "" {
+			continue
+		}
+		key, _, isNested := strings.Cut(rest, ".")
+		g := groups[key]
+		if g == nil {
+			g = &group{}
+			groups[key] = g
+		}
+		if isNested {
+			g.nested += d
+		} else {
+			g.own += d
+			g.timed = true
+		}
+	}
+
+	sections := make([]Section, 0, len(groups))
+	for name, g := range groups {
+		d := g.nested
+		if g.timed {
+			d = g.own
+		}
+		sections = append(sections, Section{Name: name, Time: d / time.Duration(frames)})
+	}
+	sort.Slice(sections, func(i, j int) bool {
+		if sections[i].Time != sections[j].Time {
+			return sections[i].Time > sections[j].Time
+		}
+		return sections[i].Name < sections[j].Name
+	})
+	return sections
+}
+
 // TopN formats top N durations from the current frame totals.
 // Example: "renderer.Render:4.2ms, meshing.BuildGreedyMeshForChunk:2.1ms"
 func TopN(n int) string {
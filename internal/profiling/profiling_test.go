@@ -0,0 +1,90 @@
+package profiling
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBreakdown(t *testing.T) {
+	rollingSamples = nil
+	ResetFrame()
+	for range 2 {
+		Add("renderer.renderBlocks", 6*time.Millisecond)
+		Add("renderer.renderBlocks.drawAtlas", 4*time.Millisecond) // inside renderBlocks
+		Add("renderer.renderSky.dome", time.Millisecond)
+		Add("renderer.renderSky.stars", time.Millisecond)
+		Add("renderer.renderHand", 2*time.Millisecond)
+		Add("player.Update", 9*time.Millisecond)
+		ResetFrame()
+	}
+
+	got := Breakdown("renderer.")
+	want := []Section{
+		{"renderBlocks", 6 * time.Millisecond},
+		{"renderHand", 2 * time.Millisecond},
+		{"renderSky", 2 * time.Millisecond},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Breakdown = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("section %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLatencies(t *testing.T) {
+	var l Latencies
+	for i := range maxLatencySamples + 2 {
+		l.Add(time.Duration(i))
+	}
+	got := l.Take(nil)
+	if len(got) != maxLatencySamples || got[0] != 2 || got[len(got)-1] != maxLatencySamples+1 {
+		t.Fatalf("took %d samples from %v to %v", len(got), got[0], got[len(got)-1])
+	}
+	l.Add(time.Second)
+	if got := l.Take(nil); len(got) != 1 || got[0] != time.Second {
+		t.Errorf("after taking, took %v", got)
+	}
+}
+
+func TestBenchmarkReport(t *testing.T) {
+	b := Benchmark{Seed: 7, RenderDistance: 8}
+	for i := range 100 {
+		b.Frames = append(b.Frames, BenchmarkFrame{
+			At:        time.Duration(i+1) * 10 * time.Millisecond,
+			Frame:     time.Duration(i+1) * time.Millisecond,
+			Render:    2 * time.Millisecond,
+			DrawCalls: i % 10,
+		})
+	}
+	b.MeshLatencies = []time.Duration{3 * time.Millisecond, time.Millisecond}
+
+	s := b.Summary()
+	if s.Frames != 100 || s.Seconds != 1 || s.AverageFPS != 100 {
+		t.Errorf("%d frames over %vs at %v fps", s.Frames, s.Seconds, s.AverageFPS)
+	}
+	if ft := s.FrameTime; ft.P50 != 50 || ft.P95 != 95 || ft.P99 != 99 || ft.Max != 100 || ft.Mean != 50.5 {
+		t.Errorf("frame times %+v", ft)
+	}
+	if s.MeshLatency != (Stats{Count: 2, Mean: 2, P50: 1, P95: 3, P99: 3, Max: 3}) || s.ChunkLatency.Count != 0 {
+		t.Errorf("latencies %+v, %+v", s.MeshLatency, s.ChunkLatency)
+	}
+	if s.DrawCallsMean != 4.5 || s.DrawCallsMax != 9 {
+		t.Errorf("draw calls %v mean, %d max", s.DrawCallsMean, s.DrawCallsMax)
+	}
+
+	var js, rows strings.Builder
+	if err := b.WriteJSON(&js); err != nil || !strings.Contains(js.String(), `"p95": 95`) {
+		t.Errorf("JSON %s, %v", js.String(), err)
+	}
+	if err := b.WriteCSV(&rows); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(rows.String()), "\n")
+	if len(lines) != 101 || lines[1] != "0.010,1.000,2.000,0,0,0.00,0.00,0.00" {
+		t.Errorf("%d CSV lines, the first frame %q", len(lines), lines[1])
+	}
+}
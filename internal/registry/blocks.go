@@ -1,7 +1,7 @@
 package registry
 
 import (
-	"fmt"
+	"log/slog"
 	"mini-mc/internal/world"
 	"mini-mc/pkg/blockmodel"
 	"os"
@@ -16,12 +16,42 @@ type BlockDefinition struct {
 	TextureTop    string
 	TextureSide   string
 	TextureBot    string
+	TextureFront  string // front face of blocks with a facing (stored in metadata)
 	IsSolid       bool
 	IsTransparent bool
-	TintColor     uint32
+	TintColor     uint32 // also the colour of a biome tinted block away from the world
 	TintFaces     map[world.BlockFace]bool
-	Hardness      float32
+	BiomeTint     BiomeTint // which biome colour replaces TintColor in the world
+	Hardness      float32   // seconds to mine by hand are 1.5x this; -1 is unbreakable
+	Sound         SoundType // dig, place and step sounds
 	Elements      []blockmodel.Element
+	IsItem        bool // held item only (tools); never placed in the world
+	// Tool is the kind of tool that mines the block faster. Blocks that
+	// NeedsTool mine slowly and drop nothing without one.
+	Tool      ToolKind
+	NeedsTool bool
+	// MiningSpeed is how many times faster a tool item mines the blocks its
+	// Tool kind is for.
+	MiningSpeed float32
+	// EntityRendered blocks are drawn by their block entity's renderer (e.g.
+	// item frames) instead of the chunk mesh.
+	EntityRendered bool
+	// Varied natural blocks have their texture rotated (top and bottom) and
+	// tinted slightly per block position, breaking up uniform tiling.
+	Varied bool
+	// Foliage blocks sway in the wind.
+	Foliage bool
+	// HidesOwnFaces transparent blocks (glass) draw no faces between two of
+	// themselves, so a wall of them shows only its outside.
+	HidesOwnFaces bool
+	// Translucent blocks have partly see-through texels, so they are drawn
+	// blended in the translucent stage instead of with the opaque blocks.
+	Translucent bool
+	// LightEmission is the block light the block gives off, 0-15.
+	LightEmission uint8
+	// LightOpacity is how much light passing through the block loses, 0-15.
+	// Opaque blocks always take all of it.
+	LightOpacity uint8
 
 	// Drop Logic
 	GetItemDropped  func() world.BlockType
@@ -48,6 +78,59 @@ var blockTexLayers [256][6]int
 // 0xFFFF means no tint (white). Face indices same as blockTexLayers.
 var blockTints [256][6]uint16
 
+// Pre-computed occlusion lookups used to skip fully enclosed sections before meshing.
+// blockFullCube: opaque single-element cube handled entirely by the greedy mesher.
+// blockOccluder: opaque solid block that hides the faces of its neighbours.
+var (
+	blockFullCube [256]bool
+	blockOccluder [256]bool
+)
+
+// Pre-computed facing lookups: blockFrontLayers[blockType] is the texture layer of
+// the front face for blocks whose metadata holds a facing (blockHasFacing).
+var (
+	blockHasFacing   [256]bool
+	blockFrontLayers [256]int
+)
+
+// blockVaried mirrors BlockDefinition.Varied for the mesher.
+var blockVaried [256]bool
+
+// ToolKind is a kind of tool blocks can be mined faster with.
+type ToolKind uint8
+
+const (
+	ToolNone ToolKind = iota
+	ToolPickaxe
+)
+
+// BiomeTint selects the biome colour a tinted block takes in the world.
+type BiomeTint uint8
+
+const (
+	BiomeTintNone BiomeTint = iota // always TintColor
+	BiomeTintGrass
+	BiomeTintFoliage
+)
+
+// blockBiomeTint mirrors BlockDefinition.BiomeTint for the mesher.
+var blockBiomeTint [256]BiomeTint
+
+// RailTextures holds the track texture for each world.RailShape. Ascending
+// rails reuse the straight textures.
+var RailTextures = [world.NumRailShapes]string{
+	world.RailNorthSouth:     "rail_straight_z.png",
+	world.RailEastWest:       "rail_straight_x.png",
+	world.RailAscendingNorth: "rail_straight_z.png",
+	world.RailAscendingSouth: "rail_straight_z.png",
+	world.RailAscendingEast:  "rail_straight_x.png",
+	world.RailAscendingWest:  "rail_straight_x.png",
+	world.RailNorthEast:      "rail_curve_ne.png",
+	world.RailNorthWest:      "rail_curve_nw.png",
+	world.RailSouthWest:      "rail_curve_sw.png",
+	world.RailSouthEast:      "rail_curve_se.png",
+}
+
 func RegisterBlock(def *BlockDefinition) {
 	if ModelLoader != nil && def.Name != "air" && def.Name != "water_still" && def.Name != "lava_still" {
 		loadTexturesFromModel(def)
@@ -71,12 +154,13 @@ func RegisterBlock(def *BlockDefinition) {
 	registerTexture(def.TextureTop)
 	registerTexture(def.TextureSide)
 	registerTexture(def.TextureBot)
+	registerTexture(def.TextureFront)
 }
 
 func loadTexturesFromModel(def *BlockDefinition) {
 	bs, err := ModelLoader.LoadBlockState(def.Name)
 	if err != nil {
-		fmt.Printf("Warning: Failed to load blockstate for %s: %v\n", def.Name, err)
+		slog.Warn("failed to load blockstate", "block", def.Name, "err", err)
 		return
 	}
 
@@ -106,7 +190,7 @@ func loadTexturesFromModel(def *BlockDefinition) {
 
 	model, err := ModelLoader.LoadModel(modelName)
 	if err != nil {
-		fmt.Printf("Warning: Failed to load model %s for block %s: %v\n", modelName, def.Name, err)
+		slog.Warn("failed to load block model", "model", modelName, "block", def.Name, "err", err)
 		return
 	}
 
@@ -205,9 +289,20 @@ func registerTexture(name string) {
 }
 
 func InitRegistry() {
+	// Start over, so blocks added by a previous world's overrides are gone
+	Blocks = make(map[world.BlockType]*BlockDefinition)
+	BlockNames = make(map[string]world.BlockType)
+	TextureNames = nil
+	TextureMap = make(map[string]int)
+	BlockDefs = [256]*BlockDefinition{}
+
 	cwd, _ := os.Getwd()
 	assetsDir := filepath.Join(cwd, "assets")
-	ModelLoader = blockmodel.NewLoader(assetsDir)
+	if overridesPath != "" {
+		ModelLoader = blockmodel.NewLoaderWithOverrides(assetsDir, overridesPath)
+	} else {
+		ModelLoader = blockmodel.NewLoader(assetsDir)
+	}
 
 	RegisterBlock(&BlockDefinition{
 		ID:            world.BlockTypeAir,
@@ -226,6 +321,7 @@ func InitRegistry() {
 		IsSolid:       false, // Players can move through water
 		IsTransparent: true,  // Transparent rendering
 		Hardness:      100.0, // Cannot be mined
+		LightOpacity:  3,
 	})
 
 	// Lava
@@ -242,14 +338,17 @@ func InitRegistry() {
 		// If we set IsTransparent=true, it might cull weirdly?
 		// BlockLiquidRenderer handles it.
 		// The key is that it uses the fluid renderer.
-		Hardness: 100.0,
+		Hardness:      100.0,
+		LightEmission: world.MaxLight,
 	})
 
 	RegisterBlock(&BlockDefinition{
-		ID:       world.BlockTypeObsidian,
-		Name:     "obsidian",
-		IsSolid:  true,
-		Hardness: 50.0,
+		ID:        world.BlockTypeObsidian,
+		Name:      "obsidian",
+		IsSolid:   true,
+		Hardness:  50.0,
+		Tool:      ToolPickaxe,
+		NeedsTool: true,
 	})
 
 	RegisterBlock(&BlockDefinition{
@@ -258,7 +357,10 @@ func InitRegistry() {
 		IsSolid:   true,
 		TintColor: 0x7DFF5C,
 		TintFaces: map[world.BlockFace]bool{world.FaceTop: true},
+		BiomeTint: BiomeTintGrass,
 		Hardness:  0.6,
+		Sound:     SoundGrass,
+		Varied:    true,
 		GetItemDropped: func() world.BlockType {
 			return world.BlockTypeDirt
 		},
@@ -269,6 +371,8 @@ func InitRegistry() {
 		Name:     "dirt",
 		IsSolid:  true,
 		Hardness: 0.5,
+		Sound:    SoundGravel,
+		Varied:   true,
 	})
 
 	// Stone
@@ -277,17 +381,22 @@ func InitRegistry() {
 		Name:     "stone",
 		IsSolid:  true,
 		Hardness: 1.5,
+		Varied:   true,
 		GetItemDropped: func() world.BlockType {
 			return world.BlockTypeCobblestone
 		},
+		Tool:      ToolPickaxe,
+		NeedsTool: true,
 	})
 
 	// Cobblestone
 	RegisterBlock(&BlockDefinition{
-		ID:       world.BlockTypeCobblestone,
-		Name:     "cobblestone",
-		IsSolid:  true,
-		Hardness: 2.0,
+		ID:        world.BlockTypeCobblestone,
+		Name:      "cobblestone",
+		IsSolid:   true,
+		Hardness:  2.0,
+		Tool:      ToolPickaxe,
+		NeedsTool: true,
 	})
 
 	// Bedrock
@@ -296,14 +405,29 @@ func InitRegistry() {
 		Name:     "bedrock",
 		IsSolid:  true,
 		Hardness: -1.0, // Unbreakable
+		Varied:   true,
 	})
 
 	// Stone Brick
 	RegisterBlock(&BlockDefinition{
-		ID:       world.BlockTypeStoneBrick,
-		Name:     "stonebrick",
-		IsSolid:  true,
-		Hardness: 1.5,
+		ID:        world.BlockTypeStoneBrick,
+		Name:      "stonebrick",
+		IsSolid:   true,
+		Hardness:  1.5,
+		Tool:      ToolPickaxe,
+		NeedsTool: true,
+	})
+
+	// Glass breaks without dropping anything
+	RegisterBlock(&BlockDefinition{
+		ID:              world.BlockTypeGlass,
+		Name:            "glass",
+		IsSolid:         true,
+		IsTransparent:   true,
+		HidesOwnFaces:   true,
+		Translucent:     true,
+		Hardness:        0.3,
+		QuantityDropped: func() int { return 0 },
 	})
 
 	// Oak Planks
@@ -312,6 +436,7 @@ func InitRegistry() {
 		Name:     "oak_planks", // Changed from "planks_oak" to match json?
 		IsSolid:  true,
 		Hardness: 2.0,
+		Sound:    SoundWood,
 	})
 	// ... need to fix naming for other planks too
 
@@ -321,6 +446,7 @@ func InitRegistry() {
 		Name:     "birch_planks",
 		IsSolid:  true,
 		Hardness: 2.0,
+		Sound:    SoundWood,
 	})
 
 	// Spruce Planks
@@ -329,6 +455,7 @@ func InitRegistry() {
 		Name:     "spruce_planks",
 		IsSolid:  true,
 		Hardness: 2.0,
+		Sound:    SoundWood,
 	})
 
 	// Jungle Planks
@@ -337,6 +464,7 @@ func InitRegistry() {
 		Name:     "jungle_planks",
 		IsSolid:  true,
 		Hardness: 2.0,
+		Sound:    SoundWood,
 	})
 
 	// Acacia Planks
@@ -345,6 +473,7 @@ func InitRegistry() {
 		Name:     "acacia_planks",
 		IsSolid:  true,
 		Hardness: 2.0,
+		Sound:    SoundWood,
 	})
 
 	// Sand — desert and ocean floor surface block.
@@ -353,6 +482,8 @@ func InitRegistry() {
 		Name:     "sand",
 		IsSolid:  true,
 		Hardness: 0.5,
+		Sound:    SoundSand,
+		Varied:   true,
 	})
 
 	// Oak Log — vertical log with bark side and ring top/bottom textures.
@@ -361,6 +492,7 @@ func InitRegistry() {
 		Name:     "oak_log",
 		IsSolid:  true,
 		Hardness: 2.0,
+		Sound:    SoundWood,
 	})
 
 	// Oak Leaves — tinted with MC foliage green (leaves_oak.png is a grayscale dot pattern).
@@ -375,7 +507,11 @@ func InitRegistry() {
 			world.FaceEast: true, world.FaceWest: true,
 			world.FaceTop: true, world.FaceBottom: true,
 		},
-		Hardness: 0.2,
+		BiomeTint:    BiomeTintFoliage,
+		Hardness:     0.2,
+		Sound:        SoundGrass,
+		Foliage:      true,
+		LightOpacity: 1,
 	})
 
 	// Spruce Log
@@ -384,6 +520,7 @@ func InitRegistry() {
 		Name:     "spruce_log",
 		IsSolid:  true,
 		Hardness: 2.0,
+		Sound:    SoundWood,
 	})
 
 	// Spruce Leaves — tinted with spruce foliage green.
@@ -398,26 +535,208 @@ func InitRegistry() {
 			world.FaceEast: true, world.FaceWest: true,
 			world.FaceTop: true, world.FaceBottom: true,
 		},
-		Hardness: 0.2,
+		Hardness:     0.2,
+		Sound:        SoundGrass,
+		Foliage:      true,
+		LightOpacity: 1,
+	})
+
+	// Snow Layer — 1 to 8 stacked layers; height is stored in block metadata.
+	RegisterBlock(&BlockDefinition{
+		ID:            world.BlockTypeSnowLayer,
+		Name:          "snow_layer",
+		IsSolid:       false,
+		IsTransparent: true,
+		Hardness:      0.1,
+		Sound:         SoundSnow,
+	})
+
+	// Furnace — the front faces the player who placed it (facing in metadata).
+	// The lit variant is swapped in while it burns fuel and drops a plain furnace.
+	for _, furnace := range []struct {
+		id    world.BlockType
+		name  string
+		front string
+		light uint8
+	}{
+		{world.BlockTypeFurnace, "furnace", "furnace_front_off.png", 0},
+		{world.BlockTypeLitFurnace, "lit_furnace", "furnace_front_on.png", 13},
+	} {
+		RegisterBlock(&BlockDefinition{
+			ID:            furnace.id,
+			Name:          furnace.name,
+			TextureTop:    "furnace_top.png",
+			TextureSide:   "furnace_side.png",
+			TextureBot:    "furnace_top.png",
+			TextureFront:  furnace.front,
+			IsSolid:       true,
+			Hardness:      3.5,
+			LightEmission: furnace.light,
+			Tool:          ToolPickaxe,
+			NeedsTool:     true,
+			GetItemDropped: func() world.BlockType {
+				return world.BlockTypeFurnace
+			},
+		})
+	}
+
+	// Crafting Table — opens a 3x3 crafting grid. Its front faces the player
+	// who placed it, like the furnace's.
+	RegisterBlock(&BlockDefinition{
+		ID:           world.BlockTypeCraftingTable,
+		Name:         "crafting_table",
+		TextureTop:   "crafting_table_top.png",
+		TextureSide:  "crafting_table_side.png",
+		TextureBot:   "planks_oak.png",
+		TextureFront: "crafting_table_front.png",
+		IsSolid:      true,
+		Hardness:     2.5,
+		Sound:        SoundWood,
+	})
+
+	// Item Frame — hangs on the side of a block (facing in metadata) and
+	// shows an item; drawn with its contents by the item renderer.
+	RegisterBlock(&BlockDefinition{
+		ID:             world.BlockTypeItemFrame,
+		Name:           "item_frame",
+		IsSolid:        false,
+		IsTransparent:  true,
+		Hardness:       0.1,
+		Sound:          SoundWood,
+		EntityRendered: true,
+	})
+
+	// Rails are meshed from their shape metadata (see RailTextures), not the
+	// flat item model.
+	RegisterBlock(&BlockDefinition{
+		ID:            world.BlockTypeRail,
+		Name:          "rail",
+		IsSolid:       false,
+		IsTransparent: true,
+		Hardness:      0.7,
+		Sound:         SoundMetal,
+		Tool:          ToolPickaxe,
+	})
+	for _, tex := range RailTextures {
+		registerTexture(tex)
+	}
+
+	// Plants are meshed as two crossed quads (see world.IsPlant). Tall grass
+	// takes the biome's grass colour and, with no seeds to give, drops nothing.
+	RegisterBlock(&BlockDefinition{
+		ID:              world.BlockTypeTallGrass,
+		Name:            "tallgrass",
+		IsSolid:         false,
+		IsTransparent:   true,
+		TintColor:       0x7DFF5C,
+		BiomeTint:       BiomeTintGrass,
+		Sound:           SoundGrass,
+		Foliage:         true,
+		QuantityDropped: func() int { return 0 },
+	})
+	for _, flower := range []struct {
+		id   world.BlockType
+		name string
+	}{
+		{world.BlockTypeDandelion, "dandelion"},
+		{world.BlockTypePoppy, "poppy"},
+	} {
+		RegisterBlock(&BlockDefinition{
+			ID:            flower.id,
+			Name:          flower.name,
+			IsSolid:       false,
+			IsTransparent: true,
+			Sound:         SoundGrass,
+			Foliage:       true,
+		})
+	}
+
+	// Tools, with MC's mining speed for each material
+	for _, tool := range []struct {
+		id    world.BlockType
+		name  string
+		speed float32
+	}{
+		{world.BlockTypeWoodenPickaxe, "wooden_pickaxe", 2},
+		{world.BlockTypeStonePickaxe, "stone_pickaxe", 4},
+		{world.BlockTypeIronPickaxe, "iron_pickaxe", 6},
+	} {
+		RegisterBlock(&BlockDefinition{
+			ID:          tool.id,
+			Name:        tool.name,
+			IsItem:      true,
+			Tool:        ToolPickaxe,
+			MiningSpeed: tool.speed,
+		})
+	}
+
+	// Crafting materials
+	RegisterBlock(&BlockDefinition{
+		ID:     world.BlockTypeStick,
+		Name:   "stick",
+		IsItem: true,
 	})
 
+	// Vehicles
+	for _, vehicle := range []struct {
+		id   world.BlockType
+		name string
+	}{
+		{world.BlockTypeBoat, "boat"},
+		{world.BlockTypeMinecart, "minecart"},
+	} {
+		RegisterBlock(&BlockDefinition{
+			ID:     vehicle.id,
+			Name:   vehicle.name,
+			IsItem: true,
+		})
+	}
+
 	// Register extra fluid textures
 	registerTexture("water_flow.png")
 	registerTexture("lava_still.png")
 	registerTexture("lava_flow.png")
 
+	applyWorldOverrides()
+
+	registerRecipes()
+
 	precomputeMeshingLookups()
 	populateWorldLookups()
 }
 
-// populateWorldLookups fills world.BlockSolidTable and world.BlockFluidTable from
-// the registered block definitions. Called after all blocks are registered so that
+// CreativeBlocks returns the block types the creative picker offers, in ID
+// order: every registered type except air and variants that drop another
+// block (the lit furnace).
+func CreativeBlocks() []world.BlockType {
+	var types []world.BlockType
+	for i, def := range BlockDefs {
+		bt := world.BlockType(i)
+		if def == nil || bt == world.BlockTypeAir || def.GetItemDropped() != bt {
+			continue
+		}
+		types = append(types, bt)
+	}
+	return types
+}
+
+// populateWorldLookups fills world.BlockSolidTable, world.BlockFluidTable and
+// the light tables from the registered block definitions. Called after all blocks are registered so that
 // the world package can use fast lookup arrays without importing registry.
 func populateWorldLookups() {
 	for i := 0; i < 256; i++ {
 		def := BlockDefs[i]
-		if def != nil {
-			world.BlockSolidTable[i] = def.IsSolid
+		if def == nil {
+			world.BlockSolidTable[i] = false
+			world.BlockLightEmission[i] = 0
+			world.BlockLightOpacity[i] = 0
+			continue
+		}
+		world.BlockSolidTable[i] = def.IsSolid
+		world.BlockLightEmission[i] = def.LightEmission
+		world.BlockLightOpacity[i] = def.LightOpacity
+		if def.IsSolid && !def.IsTransparent {
+			world.BlockLightOpacity[i] = world.MaxLight
 		}
 	}
 	world.BlockFluidTable[world.BlockTypeWater] = true
@@ -462,9 +781,22 @@ func precomputeMeshingLookups() {
 			for f := range 6 {
 				blockTints[bt][f] = 0xFFFF
 			}
+			blockFullCube[bt] = false
+			blockOccluder[bt] = false
+			blockHasFacing[bt] = false
+			blockVaried[bt] = false
+			blockBiomeTint[bt] = BiomeTintNone
 			continue
 		}
 
+		blockOccluder[bt] = def.IsSolid && !def.IsTransparent
+		blockFullCube[bt] = blockOccluder[bt] && len(def.Elements) <= 1
+
+		blockHasFacing[bt] = def.TextureFront != ""
+		blockFrontLayers[bt] = TextureMap[def.TextureFront]
+		blockVaried[bt] = def.Varied
+		blockBiomeTint[bt] = def.BiomeTint
+
 		// Texture layers per face.
 		for fi, face := range faces {
 			var texName string
@@ -484,13 +816,7 @@ func precomputeMeshingLookups() {
 		// RGB565 tints per face.
 		for fi, face := range faces {
 			if def.TintColor != 0 && def.TintFaces[face] {
-				r := (def.TintColor >> 16) & 0xFF
-				g := (def.TintColor >> 8) & 0xFF
-				b := def.TintColor & 0xFF
-				r5 := (r >> 3) & 0x1F
-				g6 := (g >> 2) & 0x3F
-				b5 := (b >> 3) & 0x1F
-				blockTints[bt][fi] = uint16((r5 << 11) | (g6 << 5) | b5)
+				blockTints[bt][fi] = PackRGB565(def.TintColor)
 			} else {
 				blockTints[bt][fi] = 0xFFFF // White = no tint
 			}
@@ -504,8 +830,69 @@ func GetTexLayerFast(bt world.BlockType, faceIdx int) int {
 	return blockTexLayers[bt][faceIdx]
 }
 
+// GetStateTexLayerFast returns the texture layer for a face of a block
+// state: the front texture on the face a facing block points to, otherwise
+// the type's texture for that face.
+func GetStateTexLayerFast(st world.BlockState, faceIdx int) int {
+	if blockHasFacing[st.Type] && int(st.Meta) == faceIdx {
+		return blockFrontLayers[st.Type]
+	}
+	return blockTexLayers[st.Type][faceIdx]
+}
+
 // GetTintFast returns the pre-computed RGB565 tint for a block type and face index.
 // 0xFFFF means no tint (white). Face indices same as GetTexLayerFast.
 func GetTintFast(bt world.BlockType, faceIdx int) uint16 {
 	return blockTints[bt][faceIdx]
 }
+
+// GetBiomeTintFast is GetTintFast for a block at local column (x, z) of a
+// chunk with biome colours bc: tinted faces of biome tinted blocks take the
+// blended biome colour instead of their fixed one.
+func GetBiomeTintFast(bt world.BlockType, faceIdx int, bc *world.BiomeColors, x, z int) uint16 {
+	tint := blockTints[bt][faceIdx]
+	if tint == 0xFFFF || blockBiomeTint[bt] == BiomeTintNone {
+		return tint
+	}
+	return PackRGB565(BiomeColor(BlockDefs[bt], bc, x, z))
+}
+
+// BiomeColor returns the 0xRRGGBB tint of def at local column (x, z) of a
+// chunk with biome colours bc, or its fixed TintColor.
+func BiomeColor(def *BlockDefinition, bc *world.BiomeColors, x, z int) uint32 {
+	switch def.BiomeTint {
+	case BiomeTintGrass:
+		return bc.Grass[x*world.ChunkSizeZ+z]
+	case BiomeTintFoliage:
+		return bc.Foliage[x*world.ChunkSizeZ+z]
+	}
+	return def.TintColor
+}
+
+// PackRGB565 packs a 0xRRGGBB colour into the mesh's 16-bit tint.
+func PackRGB565(c uint32) uint16 {
+	r5 := (c >> 19) & 0x1F
+	g6 := (c >> 10) & 0x3F
+	b5 := (c >> 3) & 0x1F
+	return uint16(r5<<11 | g6<<5 | b5)
+}
+
+// HasFacingFast reports whether the block's metadata holds the face its front points to.
+func HasFacingFast(bt world.BlockType) bool {
+	return blockHasFacing[bt]
+}
+
+// IsVariedFast reports whether the block's texture varies per position.
+func IsVariedFast(bt world.BlockType) bool {
+	return blockVaried[bt]
+}
+
+// FullCubeTable returns the lookup of block types that are opaque full cubes.
+func FullCubeTable() *[256]bool {
+	return &blockFullCube
+}
+
+// OccluderTable returns the lookup of block types that hide adjacent faces.
+func OccluderTable() *[256]bool {
+	return &blockOccluder
+}
@@ -0,0 +1,143 @@
+package registry
+
+import (
+	"fmt"
+	"slices"
+
+	"mini-mc/internal/world"
+)
+
+// Ingredient is what a crafting slot accepts: any one of the listed types.
+type Ingredient []world.BlockType
+
+// Matches reports whether t is one of the ingredient's types.
+func (in Ingredient) Matches(t world.BlockType) bool {
+	return slices.Contains(in, t)
+}
+
+// AnyPlanks accepts planks of any wood, as MC's recipes do.
+var AnyPlanks = Ingredient{
+	world.BlockTypePlanksOak, world.BlockTypePlanksBirch, world.BlockTypePlanksSpruce,
+	world.BlockTypePlanksJungle, world.BlockTypePlanksAcacia,
+}
+
+// CraftingRecipe turns the items in a crafting grid into Count of Output.
+// A shaped recipe's Inputs are its Width x Height pattern, row by row, nil
+// where the slot must be empty; it matches anywhere in the grid and
+// mirrored left to right. A shapeless recipe's Inputs may lie anywhere.
+type CraftingRecipe struct {
+	Width, Height int
+	Inputs        []Ingredient
+	Shapeless     bool
+	Output        world.BlockType
+	Count         int
+}
+
+// CraftingRecipes holds every registered crafting recipe, matched in order.
+var CraftingRecipes []CraftingRecipe
+
+// RegisterShaped adds a shaped recipe. The pattern's rows are strings of
+// equal length whose runes are looked up in key, a space being an empty
+// slot, like MC's recipe files.
+func RegisterShaped(output world.BlockType, count int, pattern []string, key map[rune]Ingredient) {
+	r := CraftingRecipe{Width: len([]rune(pattern[0])), Height: len(pattern), Output: output, Count: count}
+	for _, row := range pattern {
+		if len([]rune(row)) != r.Width {
+			panic(fmt.Sprintf("recipe for %d: ragged pattern %q", output, pattern))
+		}
+		for _, c := range row {
+			if c == ' ' {
+				r.Inputs = append(r.Inputs, nil)
+				continue
+			}
+			in, ok := key[c]
+			if !ok {
+				panic(fmt.Sprintf("recipe for %d: no key for %q", output, c))
+			}
+			r.Inputs = append(r.Inputs, in)
+		}
+	}
+	CraftingRecipes = append(CraftingRecipes, r)
+}
+
+// RegisterShapeless adds a recipe taking one of each ingredient, placed
+// anywhere in the grid.
+func RegisterShapeless(output world.BlockType, count int, inputs ...Ingredient) {
+	CraftingRecipes = append(CraftingRecipes, CraftingRecipe{Shapeless: true, Inputs: inputs, Output: output, Count: count})
+}
+
+// MatchCrafting returns the recipe the items in a crafting grid make. grid
+// holds the grid's types row by row, width to a row, with BlockTypeAir in
+// empty slots.
+func MatchCrafting(grid []world.BlockType, width int) (CraftingRecipe, bool) {
+	for _, r := range CraftingRecipes {
+		if r.matches(grid, width) {
+			return r, true
+		}
+	}
+	return CraftingRecipe{}, false
+}
+
+func (r CraftingRecipe) matches(grid []world.BlockType, width int) bool {
+	if r.Shapeless {
+		var items []world.BlockType
+		for _, t := range grid {
+			if t != world.BlockTypeAir {
+				items = append(items, t)
+			}
+		}
+		return len(items) == len(r.Inputs) && assignIngredients(items, r.Inputs, make([]bool, len(r.Inputs)))
+	}
+
+	height := len(grid) / width
+	for ox := 0; ox+r.Width <= width; ox++ {
+		for oy := 0; oy+r.Height <= height; oy++ {
+			if r.matchesAt(grid, width, ox, oy, false) || r.matchesAt(grid, width, ox, oy, true) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesAt reports whether the pattern, mirrored if asked, lies with its
+// top left at (ox, oy) with every other slot of the grid empty.
+func (r CraftingRecipe) matchesAt(grid []world.BlockType, width, ox, oy int, mirror bool) bool {
+	for i, t := range grid {
+		x, y := i%width-ox, i/width-oy
+		var in Ingredient
+		if x >= 0 && x < r.Width && y >= 0 && y < r.Height {
+			if mirror {
+				x = r.Width - 1 - x
+			}
+			in = r.Inputs[y*r.Width+x]
+		}
+		if in == nil {
+			if t != world.BlockTypeAir {
+				return false
+			}
+		} else if !in.Matches(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// assignIngredients reports whether each item can be given a different
+// ingredient it matches, trying every assignment; grids are small.
+func assignIngredients(items []world.BlockType, inputs []Ingredient, used []bool) bool {
+	if len(items) == 0 {
+		return true
+	}
+	for i, in := range inputs {
+		if used[i] || !in.Matches(items[0]) {
+			continue
+		}
+		used[i] = true
+		if assignIngredients(items[1:], inputs, used) {
+			return true
+		}
+		used[i] = false
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package registry
+
+import (
+	"testing"
+
+	"mini-mc/internal/world"
+)
+
+func TestMatchCrafting(t *testing.T) {
+	InitRegistry()
+	const (
+		air   = world.BlockTypeAir
+		oak   = world.BlockTypePlanksOak
+		birch = world.BlockTypePlanksBirch
+		cobb  = world.BlockTypeCobblestone
+		stick = world.BlockTypeStick
+		log   = world.BlockTypeOakLog
+	)
+	for _, tc := range []struct {
+		name  string
+		grid  []world.BlockType
+		width int
+		want  world.BlockType // air for no match
+		count int
+	}{
+		{"log anywhere in 2x2", []world.BlockType{air, air, air, log}, 2, oak, 4},
+		{"sticks in the right column", []world.BlockType{air, oak, air, birch}, 2, stick, 4},
+		{"sticks at the bottom of 3x3", []world.BlockType{air, air, air, air, oak, air, air, oak, air}, 3, stick, 4},
+		{"mixed planks table", []world.BlockType{oak, birch, birch, oak}, 2, world.BlockTypeCraftingTable, 1},
+		{"furnace", []world.BlockType{cobb, cobb, cobb, cobb, air, cobb, cobb, cobb, cobb}, 3, world.BlockTypeFurnace, 1},
+		{"pickaxe", []world.BlockType{cobb, cobb, cobb, air, stick, air, air, stick, air}, 3, world.BlockTypeStonePickaxe, 1},
+		{"boat upside down", []world.BlockType{oak, oak, oak, oak, air, oak, air, air, air}, 3, air, 0},
+		{"furnace needs a hole", []world.BlockType{cobb, cobb, cobb, cobb, cobb, cobb, cobb, cobb, cobb}, 3, air, 0},
+		{"stray item", []world.BlockType{log, air, air, cobb}, 2, air, 0},
+		{"pickaxe too big for 2x2", []world.BlockType{oak, oak, stick, air}, 2, air, 0},
+	} {
+		r, ok := MatchCrafting(tc.grid, tc.width)
+		if tc.want == air {
+			if ok {
+				t.Errorf("%s: matched %d", tc.name, r.Output)
+			}
+			continue
+		}
+		if !ok || r.Output != tc.want || r.Count != tc.count {
+			t.Errorf("%s: got %d x%d (%v), want %d x%d", tc.name, r.Output, r.Count, ok, tc.want, tc.count)
+		}
+	}
+}
+
+func TestShapedRecipesMirror(t *testing.T) {
+	saved := CraftingRecipes
+	t.Cleanup(func() { CraftingRecipes = saved })
+	CraftingRecipes = nil
+	RegisterShaped(world.BlockTypeBoat, 1, []string{"##", " #"}, map[rune]Ingredient{'#': {world.BlockTypeStone}})
+
+	s, a := world.BlockTypeStone, world.BlockTypeAir
+	if _, ok := MatchCrafting([]world.BlockType{s, s, s, a}, 2); !ok {
+		t.Error("mirrored shape did not match")
+	}
+	if _, ok := MatchCrafting([]world.BlockType{a, s, s, s}, 2); ok {
+		t.Error("flipped shape matched")
+	}
+}
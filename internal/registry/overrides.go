@@ -0,0 +1,194 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mini-mc/internal/world"
+)
+
+// A saved world may carry custom content in its OverridesDir, laid out like
+// the assets folder: its blockstates, models and textures/blocks take the
+// place of the global files of the same name while the world is open. Two
+// more files have no global counterpart:
+//
+//	blocks.json                    changes block properties or adds blocks
+//	loot_tables/blocks/<name>.json sets what the named block drops
+const (
+	OverridesDir      = "overrides"
+	blockOverrideFile = "blocks.json"
+	lootTablesDir     = "loot_tables/blocks"
+)
+
+// overridesPath is the overrides folder InitRegistry loads; "" for none.
+var overridesPath string
+
+// UseWorldOverrides makes InitRegistry load the overrides of the world saved
+// in worldDir, or none if worldDir is empty. Call it before the world's
+// renderer (or server) initialises the registry.
+func UseWorldOverrides(worldDir string) {
+	overridesPath = ""
+	if worldDir != "" {
+		overridesPath = filepath.Join(worldDir, OverridesDir)
+	}
+}
+
+// TexturePath returns the file of block texture name (e.g. "stone.png"),
+// from the open world's overrides when they have it.
+func TexturePath(name string) string {
+	if overridesPath != "" {
+		path := filepath.Join(overridesPath, "textures", "blocks", name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join("assets", "textures", "blocks", name)
+}
+
+// blockOverride is an entry of blocks.json. Name picks the block to change;
+// with an ID that no block uses it adds a new one, solid unless it says
+// otherwise, whose model comes from the blockstate of the same name. Fields
+// left out keep their value.
+type blockOverride struct {
+	Name          string   `json:"name"`
+	ID            *int     `json:"id,omitempty"`
+	Solid         *bool    `json:"solid,omitempty"`
+	Transparent   *bool    `json:"transparent,omitempty"`
+	Hardness      *float32 `json:"hardness,omitempty"`
+	LightEmission *uint8   `json:"lightEmission,omitempty"`
+	LightOpacity  *uint8   `json:"lightOpacity,omitempty"`
+	NeedsTool     *bool    `json:"needsTool,omitempty"`
+}
+
+// lootTable is a loot_tables/blocks file: the block dropped, by name, and
+// how many. An empty item drops nothing.
+type lootTable struct {
+	Item  string `json:"item"`
+	Count *int   `json:"count,omitempty"` // 1 if left out
+}
+
+// applyWorldOverrides applies blocks.json and the loot tables of the
+// overrides folder to the registered blocks. Mistakes in a file are logged
+// and skip only the entry they are in.
+func applyWorldOverrides() {
+	if overridesPath == "" {
+		return
+	}
+	if err := applyBlockOverrides(filepath.Join(overridesPath, blockOverrideFile)); err != nil {
+		slog.Warn("world block overrides not loaded", "err", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(overridesPath, lootTablesDir))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.Warn("world loot tables not loaded", "err", err)
+	}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if e.IsDir() || !ok {
+			continue
+		}
+		if err := applyLootTable(name, filepath.Join(overridesPath, lootTablesDir, e.Name())); err != nil {
+			slog.Warn("world loot table not loaded", "block", name, "err", err)
+		}
+	}
+	slog.Info("world overrides loaded", "dir", overridesPath)
+}
+
+func applyBlockOverrides(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var overrides []blockOverride
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, o := range overrides {
+		if err := applyBlockOverride(o); err != nil {
+			slog.Warn("block override skipped", "block", o.Name, "err", err)
+		}
+	}
+	return nil
+}
+
+func applyBlockOverride(o blockOverride) error {
+	if o.Name == "" {
+		return errors.New("no block name")
+	}
+	var def *BlockDefinition
+	if bt, ok := BlockNames[o.Name]; ok {
+		if o.ID != nil && *o.ID != int(bt) {
+			return fmt.Errorf("block already has ID %d", bt)
+		}
+		def = BlockDefs[bt]
+	} else {
+		if o.ID == nil {
+			return errors.New("no such block; new blocks need an id")
+		}
+		if *o.ID <= 0 || *o.ID >= len(BlockDefs) || BlockDefs[*o.ID] != nil {
+			return fmt.Errorf("id %d is not free", *o.ID)
+		}
+		def = &BlockDefinition{ID: world.BlockType(*o.ID), Name: o.Name, IsSolid: true}
+		RegisterBlock(def)
+	}
+
+	if o.Solid != nil {
+		def.IsSolid = *o.Solid
+	}
+	if o.Transparent != nil {
+		def.IsTransparent = *o.Transparent
+	}
+	if o.Hardness != nil {
+		def.Hardness = *o.Hardness
+	}
+	if o.LightEmission != nil {
+		def.LightEmission = min(*o.LightEmission, world.MaxLight)
+	}
+	if o.LightOpacity != nil {
+		def.LightOpacity = min(*o.LightOpacity, world.MaxLight)
+	}
+	if o.NeedsTool != nil {
+		def.NeedsTool = *o.NeedsTool
+	}
+	return nil
+}
+
+func applyLootTable(block, path string) error {
+	bt, ok := BlockNames[block]
+	if !ok {
+		return errors.New("no such block")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var table lootTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return err
+	}
+	count := 1
+	if table.Count != nil {
+		count = max(*table.Count, 0)
+	}
+	drop := world.BlockTypeAir
+	if table.Item != "" {
+		if drop, ok = BlockNames[table.Item]; !ok {
+			return fmt.Errorf("no such item %q", table.Item)
+		}
+	}
+	if drop == world.BlockTypeAir {
+		count = 0
+	}
+	def := BlockDefs[bt]
+	def.GetItemDropped = func() world.BlockType { return drop }
+	def.QuantityDropped = func() int { return count }
+	return nil
+}
@@ -0,0 +1,92 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"mini-mc/internal/world"
+)
+
+func TestMain(m *testing.M) {
+	if err := os.Chdir("../.."); err != nil {
+		panic("cannot chdir to project root: " + err.Error())
+	}
+	os.Exit(m.Run())
+}
+
+func TestWorldOverridesApplyUntilAnotherWorldOpens(t *testing.T) {
+	InitRegistry()
+	free := -1
+	for i := len(BlockDefs) - 1; i > 0; i-- {
+		if BlockDefs[i] == nil {
+			free = i
+			break
+		}
+	}
+	if free < 0 {
+		t.Fatal("no free block ID")
+	}
+	stoneHardness := BlockDefs[world.BlockTypeStone].Hardness
+
+	dir := t.TempDir()
+	write := func(rel, data string) {
+		path := filepath.Join(dir, OverridesDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(blockOverrideFile, `[
+		{"name": "stone", "hardness": 9, "lightEmission": 20},
+		{"name": "ruby_ore", "id": `+strconv.Itoa(free)+`, "hardness": 3},
+		{"name": "dirt", "id": 200}
+	]`)
+	write("loot_tables/blocks/ruby_ore.json", `{"item": "cobblestone", "count": 2}`)
+	write("loot_tables/blocks/sand.json", `{"item": ""}`)
+
+	UseWorldOverrides(dir)
+	InitRegistry()
+
+	stone := BlockDefs[world.BlockTypeStone]
+	if stone.Hardness != 9 || stone.LightEmission != world.MaxLight {
+		t.Errorf("overridden stone: hardness %v, light %d", stone.Hardness, stone.LightEmission)
+	}
+	if world.BlockLightEmission[world.BlockTypeStone] != world.MaxLight {
+		t.Error("world light table missed the override")
+	}
+	ruby := BlockDefs[free]
+	if ruby == nil || ruby.Name != "ruby_ore" || !ruby.IsSolid || ruby.Hardness != 3 {
+		t.Fatalf("new block = %+v", ruby)
+	}
+	if !world.BlockSolidTable[free] {
+		t.Error("new block not solid in the world")
+	}
+	if got, n := ruby.GetItemDropped(), ruby.QuantityDropped(); got != world.BlockTypeCobblestone || n != 2 {
+		t.Errorf("new block drops %d of %v, want 2 cobblestone", n, got)
+	}
+	if n := BlockDefs[world.BlockTypeSand].QuantityDropped(); n != 0 {
+		t.Errorf("sand with an empty loot table drops %d", n)
+	}
+	if BlockNames["dirt"] != world.BlockTypeDirt {
+		t.Error("an override moved dirt to another ID")
+	}
+
+	UseWorldOverrides("")
+	InitRegistry()
+	if BlockDefs[free] != nil || world.BlockSolidTable[free] {
+		t.Error("new block outlived its world")
+	}
+	if _, ok := BlockNames["ruby_ore"]; ok {
+		t.Error("new block name outlived its world")
+	}
+	if got := BlockDefs[world.BlockTypeStone].Hardness; got != stoneHardness {
+		t.Errorf("stone hardness after the world closed = %v, want %v", got, stoneHardness)
+	}
+	if n := BlockDefs[world.BlockTypeSand].QuantityDropped(); n != 1 {
+		t.Errorf("sand drops %d after the world closed", n)
+	}
+}
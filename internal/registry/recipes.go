@@ -0,0 +1,77 @@
+package registry
+
+import "mini-mc/internal/world"
+
+// SmeltingRecipe is what a furnace turns one input item into.
+type SmeltingRecipe struct {
+	Output world.BlockType
+	Count  int
+}
+
+var (
+	// SmeltingRecipes maps a furnace input to its result.
+	SmeltingRecipes = make(map[world.BlockType]SmeltingRecipe)
+	// FuelBurnTimes maps a fuel item to how many ticks one item burns for.
+	FuelBurnTimes = make(map[world.BlockType]int)
+)
+
+// RegisterSmelting adds a furnace recipe turning one input into count outputs.
+func RegisterSmelting(input, output world.BlockType, count int) {
+	SmeltingRecipes[input] = SmeltingRecipe{Output: output, Count: count}
+}
+
+// RegisterFuel makes t usable as furnace fuel burning for burnTicks ticks.
+func RegisterFuel(t world.BlockType, burnTicks int) {
+	FuelBurnTimes[t] = burnTicks
+}
+
+// GetSmeltingResult returns the recipe for smelting input, if any.
+func GetSmeltingResult(input world.BlockType) (SmeltingRecipe, bool) {
+	r, ok := SmeltingRecipes[input]
+	return r, ok
+}
+
+// GetBurnTime returns how many ticks one t burns in a furnace, or 0 if it is not fuel.
+func GetBurnTime(t world.BlockType) int {
+	return FuelBurnTimes[t]
+}
+
+// registerRecipes fills the recipe tables (MC 1.8 values, limited to the
+// blocks and items that exist so far).
+func registerRecipes() {
+	// The registry is rebuilt for every world
+	CraftingRecipes = nil
+
+	planks := map[rune]Ingredient{'#': AnyPlanks}
+	RegisterShapeless(world.BlockTypePlanksOak, 4, Ingredient{world.BlockTypeOakLog})
+	RegisterShapeless(world.BlockTypePlanksSpruce, 4, Ingredient{world.BlockTypeSpruceLog})
+	RegisterShaped(world.BlockTypeStick, 4, []string{"#", "#"}, planks)
+	RegisterShaped(world.BlockTypeCraftingTable, 1, []string{"##", "##"}, planks)
+	RegisterShaped(world.BlockTypeBoat, 1, []string{"# #", "###"}, planks)
+	RegisterShaped(world.BlockTypeFurnace, 1, []string{"###", "# #", "###"},
+		map[rune]Ingredient{'#': {world.BlockTypeCobblestone}})
+	RegisterShaped(world.BlockTypeStoneBrick, 4, []string{"##", "##"},
+		map[rune]Ingredient{'#': {world.BlockTypeStone}})
+	for _, pickaxe := range []struct {
+		output   world.BlockType
+		material Ingredient
+	}{
+		{world.BlockTypeWoodenPickaxe, AnyPlanks},
+		{world.BlockTypeStonePickaxe, Ingredient{world.BlockTypeCobblestone}},
+	} {
+		RegisterShaped(pickaxe.output, 1, []string{"XXX", " # ", " # "},
+			map[rune]Ingredient{'X': pickaxe.material, '#': {world.BlockTypeStick}})
+	}
+
+	RegisterSmelting(world.BlockTypeCobblestone, world.BlockTypeStone, 1)
+	RegisterSmelting(world.BlockTypeSand, world.BlockTypeGlass, 1)
+
+	for _, t := range AnyPlanks {
+		RegisterFuel(t, 300)
+	}
+	RegisterFuel(world.BlockTypeOakLog, 300)
+	RegisterFuel(world.BlockTypeSpruceLog, 300)
+	RegisterFuel(world.BlockTypeWoodenPickaxe, 200)
+	RegisterFuel(world.BlockTypeCraftingTable, 300)
+	RegisterFuel(world.BlockTypeStick, 100)
+}
@@ -0,0 +1,48 @@
+package registry
+
+import "mini-mc/internal/world"
+
+// SoundType is the material group a block's sounds come from, following
+// 1.8.9's Block.SoundType. The zero value is stone.
+type SoundType uint8
+
+const (
+	SoundStone SoundType = iota
+	SoundWood
+	SoundGravel
+	SoundGrass
+	SoundSand
+	SoundSnow
+	SoundMetal
+)
+
+// soundGroups holds each group's sound name and the pitch it is played at.
+var soundGroups = [...]struct {
+	name  string
+	pitch float32
+}{
+	SoundStone:  {"stone", 1},
+	SoundWood:   {"wood", 1},
+	SoundGravel: {"gravel", 1},
+	SoundGrass:  {"grass", 1},
+	SoundSand:   {"sand", 1},
+	SoundSnow:   {"snow", 1},
+	SoundMetal:  {"stone", 1.5},
+}
+
+// BreakSound is played when a block of the group is broken or placed.
+func (s SoundType) BreakSound() string { return "dig." + soundGroups[s].name }
+
+// StepSound is played when walking on a block of the group.
+func (s SoundType) StepSound() string { return "step." + soundGroups[s].name }
+
+// Pitch is the group's base pitch. Break and place sounds play at 0.8 of it.
+func (s SoundType) Pitch() float32 { return soundGroups[s].pitch }
+
+// GetSoundFast returns the sound group of a block type, stone if unregistered.
+func GetSoundFast(bt world.BlockType) SoundType {
+	if def := BlockDefs[bt]; def != nil {
+		return def.Sound
+	}
+	return SoundStone
+}
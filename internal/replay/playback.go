@@ -0,0 +1,65 @@
+package replay
+
+import "math"
+
+// Playback steps through a recording a frame each game tick.
+type Playback struct {
+	rec  *Recording
+	tick int
+
+	// walked is the distance across the ground covered from the first
+	// frame to each, for the stride of a body following the recording.
+	walked []float32
+}
+
+// NewPlayback returns a playback of r showing its first frame. r must hold
+// at least one frame.
+func NewPlayback(r *Recording) *Playback {
+	pb := &Playback{rec: r, walked: make([]float32, len(r.Frames))}
+	for i := 1; i < len(r.Frames); i++ {
+		d := r.Frames[i].Pos.Sub(r.Frames[i-1].Pos)
+		pb.walked[i] = pb.walked[i-1] + float32(math.Hypot(float64(d[0]), float64(d[2])))
+	}
+	return pb
+}
+
+// Recording returns the recording played.
+func (pb *Playback) Recording() *Recording {
+	return pb.rec
+}
+
+// Tick moves on to the next frame, reporting false when the recording has
+// ended.
+func (pb *Playback) Tick() bool {
+	if pb.tick+1 >= len(pb.rec.Frames) {
+		return false
+	}
+	pb.tick++
+	return true
+}
+
+// Remaining returns how many ticks are left to play.
+func (pb *Playback) Remaining() int {
+	return len(pb.rec.Frames) - 1 - pb.tick
+}
+
+// Frame returns the frame partialTick of the way from the last tick's to
+// this one's, as the player is drawn between ticks.
+func (pb *Playback) Frame(partialTick float32) Frame {
+	cur := pb.rec.Frames[pb.tick]
+	prev := pb.rec.Frames[max(pb.tick-1, 0)]
+	return Frame{
+		Pos:     prev.Pos.Add(cur.Pos.Sub(prev.Pos).Mul(partialTick)),
+		Yaw:     prev.Yaw + (cur.Yaw-prev.Yaw)*partialTick,
+		Pitch:   prev.Pitch + (cur.Pitch-prev.Pitch)*partialTick,
+		Actions: cur.Actions,
+	}
+}
+
+// Walked returns the distance across the ground covered since the first
+// frame at partialTick, and in the last tick.
+func (pb *Playback) Walked(partialTick float32) (distance, step float32) {
+	prev := pb.walked[max(pb.tick-1, 0)]
+	step = pb.walked[pb.tick] - prev
+	return prev + step*partialTick, step
+}
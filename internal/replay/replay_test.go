@@ -0,0 +1,103 @@
+package replay
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func walk() *Recording {
+	r := &Recording{Seed: -42}
+	for i := range 100 {
+		t := float32(i)
+		r.Add(Frame{
+			Pos:     mgl32.Vec3{-1000.3 + t*0.2153, 64 + float32(math.Sin(float64(t))), 30000000 - t*0.1},
+			Yaw:     -720 + t*7.31,
+			Pitch:   float32(math.Cos(float64(t))) * 89.9,
+			Actions: OnGround | Action(i%2)*Sneaking,
+		})
+	}
+	return r
+}
+
+func TestEncodeDecode(t *testing.T) {
+	r := walk()
+	data := r.Encode()
+	if len(data) > 13+len(r.Frames)*16 {
+		t.Errorf("%d frames took %d bytes", len(r.Frames), len(data))
+	}
+	got, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Seed != r.Seed || len(got.Frames) != len(r.Frames) {
+		t.Fatalf("seed %d with %d frames, want %d with %d", got.Seed, len(got.Frames), r.Seed, len(r.Frames))
+	}
+	for i, f := range got.Frames {
+		want := r.Frames[i]
+		for a := range 3 {
+			tolerance := 1e-3
+			if a == 2 {
+				tolerance = 4 // float32 steps by 2 around 30 million
+			}
+			if math.Abs(float64(f.Pos[a]-want.Pos[a])) > tolerance {
+				t.Fatalf("frame %d at %v, want %v", i, f.Pos, want.Pos)
+			}
+		}
+		if math.Abs(float64(f.Yaw-want.Yaw)) > 0.01 || math.Abs(float64(f.Pitch-want.Pitch)) > 0.01 || f.Actions != want.Actions {
+			t.Fatalf("frame %d = %+v, want %+v", i, f, want)
+		}
+	}
+
+	for _, bad := range [][]byte{nil, []byte("MCRP"), data[:len(data)-1], append([]byte("XXXX"), data[4:]...)} {
+		if _, err := Decode(bad); err == nil {
+			t.Errorf("decoded %d corrupt bytes", len(bad))
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recordings", "walk.mcr")
+	if err := walk().Save(path); err != nil {
+		t.Fatal(err)
+	}
+	r, err := Load(path)
+	if err != nil || len(r.Frames) != 100 {
+		t.Fatalf("loaded %v, %v", r, err)
+	}
+}
+
+func TestPlayback(t *testing.T) {
+	r := &Recording{}
+	r.Add(Frame{Pos: mgl32.Vec3{0, 64, 0}})
+	r.Add(Frame{Pos: mgl32.Vec3{3, 64, 4}, Yaw: 90, Actions: Sprinting})
+	r.Add(Frame{Pos: mgl32.Vec3{3, 70, 4}, Yaw: 100})
+
+	pb := NewPlayback(r)
+	if f := pb.Frame(0.5); f.Pos != r.Frames[0].Pos {
+		t.Errorf("first frame at %v", f.Pos)
+	}
+	if !pb.Tick() || pb.Remaining() != 1 {
+		t.Fatalf("%d ticks left after the first", pb.Remaining())
+	}
+	f := pb.Frame(0.5)
+	if f.Pos != (mgl32.Vec3{1.5, 64, 2}) || f.Yaw != 45 || f.Actions != Sprinting {
+		t.Errorf("halfway frame = %+v", f)
+	}
+	if d, step := pb.Walked(0.5); d != 2.5 || step != 5 {
+		t.Errorf("walked %v, %v in the last tick; want 2.5, 5", d, step)
+	}
+
+	pb.Tick()
+	if d, step := pb.Walked(1); d != 5 || step != 0 {
+		t.Errorf("climbing walked %v, %v; want 5, 0", d, step)
+	}
+	if pb.Tick() || pb.Remaining() != 0 {
+		t.Error("played past the end")
+	}
+	if f := pb.Frame(1); f.Pos != r.Frames[2].Pos {
+		t.Errorf("ended at %v", f.Pos)
+	}
+}
@@ -0,0 +1,58 @@
+// Package rng derives the game's random sources from a world seed. A seed
+// always reproduces the same world: terrain and decoration draw from
+// per-chunk sub-seeds that do not depend on generation order, and gameplay
+// randomness (item drops, entity motion) has its own stream so it can never
+// shift what the generator produces.
+package rng
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Salt separates the streams derived from one seed.
+type Salt uint64
+
+const (
+	SaltTrees Salt = iota + 1
+	SaltLakes
+	SaltEntities
+	SaltPlants
+)
+
+// mix is the splitmix64 finalizer: a cheap bijection whose output bits all
+// depend on every input bit.
+func mix(v uint64) uint64 {
+	v ^= v >> 30
+	v *= 0xBF58476D1CE4E5B9
+	v ^= v >> 27
+	v *= 0x94D049BB133111EB
+	v ^= v >> 31
+	return v
+}
+
+// Derive returns the seed of the stream salt draws from.
+func Derive(seed int64, salt Salt) int64 {
+	return int64(mix(uint64(seed) + uint64(salt)*0x9E3779B97F4A7C15))
+}
+
+// ChunkSeed returns the seed of chunk (cx, cz)'s stream for salt.
+func ChunkSeed(seed int64, salt Salt, cx, cz int) int64 {
+	h := mix(uint64(Derive(seed, salt)) ^ uint64(int64(cx))*0x9E3779B97F4A7C15)
+	return int64(mix(h ^ uint64(int64(cz))*0xC2B2AE3D27D4EB4F))
+}
+
+// New returns a generator for the stream salt draws from.
+func New(seed int64, salt Salt) *rand.Rand {
+	return rand.New(rand.NewSource(Derive(seed, salt)))
+}
+
+// ForChunk returns a generator for chunk (cx, cz)'s stream for salt.
+func ForChunk(seed int64, salt Salt, cx, cz int) *rand.Rand {
+	return rand.New(rand.NewSource(ChunkSeed(seed, salt, cx, cz)))
+}
+
+// NewSeed picks a seed for a new world.
+func NewSeed() int64 {
+	return rand.New(rand.NewSource(time.Now().UnixNano())).Int63n(10000)
+}
@@ -0,0 +1,42 @@
+package rng
+
+import "testing"
+
+func TestChunkSeedsAreStableAndDistinct(t *testing.T) {
+	const seed = 1234
+	if ChunkSeed(seed, SaltTrees, 3, -7) != ChunkSeed(seed, SaltTrees, 3, -7) {
+		t.Fatal("chunk seed is not deterministic")
+	}
+
+	seen := make(map[int64]string)
+	add := func(what string, v int64) {
+		if prev, dup := seen[v]; dup {
+			t.Errorf("%s collides with %s", what, prev)
+		}
+		seen[v] = what
+	}
+	for _, salt := range []Salt{SaltTrees, SaltLakes, SaltEntities, SaltPlants} {
+		for cx := -4; cx <= 4; cx++ {
+			for cz := -4; cz <= 4; cz++ {
+				add("chunk", ChunkSeed(seed, salt, cx, cz))
+			}
+		}
+		add("stream", Derive(seed, salt))
+	}
+	// Swapping coordinates must not give the same chunk
+	if ChunkSeed(seed, SaltLakes, 1, 2) == ChunkSeed(seed, SaltLakes, 2, 1) {
+		t.Error("chunk seed is symmetric in x and z")
+	}
+	if ChunkSeed(seed, SaltLakes, 0, 0) == ChunkSeed(seed+1, SaltLakes, 0, 0) {
+		t.Error("chunk seed ignores the world seed")
+	}
+}
+
+func TestStreamsReplay(t *testing.T) {
+	a, b := New(42, SaltEntities), New(42, SaltEntities)
+	for range 100 {
+		if a.Int63() != b.Int63() {
+			t.Fatal("same seed and salt gave different streams")
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Package sim runs the game simulation apart from rendering: the world's
+// game ticks, the time of day and the entities. It needs no window or GL
+// context, so the server and tests drive it as the game does.
+package sim
+
+import (
+	"mini-mc/internal/entity"
+	"mini-mc/internal/world"
+)
+
+// TickLength is the seconds one game tick stands for.
+const TickLength = 1.0 / world.TicksPerSecond
+
+// Catching up after a slow frame runs at most maxTicksPerUpdate ticks, and
+// time owed beyond maxBacklog is dropped rather than spiralling.
+const (
+	maxTicksPerUpdate = 10
+	maxBacklog        = 0.5 // seconds
+)
+
+// Engine simulates a world. The game calls Update once a frame with the
+// frame's time; a headless host calls Tick 20 times a second.
+type Engine struct {
+	World *world.World
+
+	// DaySpeed is how many times faster than normal the day passes.
+	DaySpeed float64
+
+	// Remote leaves block ticks to the server whose world this mirrors;
+	// time and entities still move between the server's updates.
+	Remote bool
+
+	// OnTick, if set, runs at the start of each game tick, for what the host
+	// simulates itself, such as the local player's movement.
+	OnTick func()
+
+	// Mobs, if set, spawns passive mobs around the focus and zombies around
+	// its target. Remote worlds get theirs from the server.
+	Mobs *entity.MobSpawner
+
+	// Entities within SimDistance blocks of the focus update every tick,
+	// those further out at a reduced rate or not at all (see
+	// world.EntityManager.Update).
+	SimDistance float32
+	focusX      float32
+	focusZ      float32
+	foci        [][2]float32 // used instead of the focus with useFoci
+	useFoci     bool
+
+	accumulator float64 // seconds owed toward the next game tick
+	ticks       uint64
+}
+
+// NewEngine returns an engine for w at normal day speed.
+func NewEngine(w *world.World) *Engine {
+	return &Engine{World: w, DaySpeed: 1}
+}
+
+// SetFocus centres entity updates on (x, z), usually the player.
+func (e *Engine) SetFocus(x, z float32) {
+	e.focusX, e.focusZ = x, z
+	e.useFoci = false
+}
+
+// SetFoci centres entity updates on each of foci, such as the players of a
+// server, with mobs spawning around the first. With none, entities freeze.
+func (e *Engine) SetFoci(foci [][2]float32) {
+	e.foci = append(e.foci[:0], foci...)
+	e.useFoci = true
+	if len(foci) > 0 {
+		e.focusX, e.focusZ = foci[0][0], foci[0][1]
+	}
+}
+
+// Ticks returns the game ticks run so far.
+func (e *Engine) Ticks() uint64 {
+	return e.ticks
+}
+
+// PartialTick returns how far the simulation is from the last game tick to
+// the next, 0-1, for drawing movement between ticks.
+func (e *Engine) PartialTick() float32 {
+	return float32(min(e.accumulator/TickLength, 1))
+}
+
+// EntityPartialTick is PartialTick for drawing entities. When the
+// simulation has fallen behind, so ticks are owed after an update, it runs
+// past 1 to carry entities on through the hitch (see
+// entity.RenderTransform).
+func (e *Engine) EntityPartialTick() float32 {
+	return float32(e.accumulator / TickLength)
+}
+
+// Update advances the simulation by dt seconds of play: the time of day
+// smoothly, then the game ticks that fell due. It returns how many ticks
+// ran.
+func (e *Engine) Update(dt float64) int {
+	e.World.AdvanceTime(dt, e.DaySpeed)
+	e.accumulator += dt
+	n := 0
+	for e.accumulator >= TickLength && n < maxTicksPerUpdate {
+		e.step()
+		e.accumulator -= TickLength
+		n++
+	}
+	e.accumulator = min(e.accumulator, maxBacklog)
+	return n
+}
+
+// Tick advances the simulation by exactly one game tick.
+func (e *Engine) Tick() {
+	e.World.AdvanceTime(TickLength, e.DaySpeed)
+	e.step()
+}
+
+// step runs one game tick: the host's own part, mob spawning, entities,
+// then block entities and scheduled block updates.
+func (e *Engine) step() {
+	if e.OnTick != nil {
+		e.OnTick()
+	}
+	if e.Mobs != nil && !e.Remote {
+		e.Mobs.Tick(e.World, e.focusX, e.focusZ)
+	}
+	if e.useFoci {
+		e.World.UpdateEntitiesAround(TickLength, e.foci, e.SimDistance)
+	} else {
+		e.World.UpdateEntities(TickLength, e.focusX, e.focusZ, e.SimDistance)
+	}
+	if !e.Remote {
+		e.World.Tick()
+	}
+	e.ticks++
+}
@@ -0,0 +1,62 @@
+package sim
+
+import (
+	"math"
+	"testing"
+
+	"mini-mc/internal/entity"
+	"mini-mc/internal/item"
+	"mini-mc/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestEngineRunsHeadless(t *testing.T) {
+	w := world.NewEmpty()
+	defer w.Close()
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			w.Set(x, 40, z, world.BlockTypeStone)
+		}
+	}
+	w.Set(8, 41, 8, world.BlockTypeWater)
+	w.ScheduleBlockTick(8, 41, 8, world.WaterTickRate, 0)
+	drop := entity.NewItemEntity(w, w.Rand(), mgl32.Vec3{4.5, 45, 4.5}, item.NewItemStack(world.BlockTypeDirt, 1))
+	w.AddEntity(drop)
+
+	e := NewEngine(w)
+	e.SimDistance = 64
+	e.SetFocus(8, 8)
+	for range 2 * world.TicksPerSecond {
+		e.Tick()
+	}
+
+	if e.Ticks() != 40 || w.Time() != 40 {
+		t.Errorf("after 40 ticks: ticks %d, time %v", e.Ticks(), w.Time())
+	}
+	if w.Get(9, 41, 8) != world.BlockTypeWater {
+		t.Error("water did not spread")
+	}
+	if y := drop.Position().Y(); math.Abs(float64(y)-41) > 0.2 {
+		t.Errorf("dropped item at y %v, want resting on the floor at 41", y)
+	}
+
+	// A long frame catches up a capped number of ticks
+	if n := e.Update(2); n != maxTicksPerUpdate {
+		t.Errorf("ticks run for a 2 s frame = %d, want %d", n, maxTicksPerUpdate)
+	}
+	if n := e.Update(0); n != maxTicksPerUpdate {
+		t.Errorf("ticks run catching up the backlog = %d, want %d", n, maxTicksPerUpdate)
+	}
+
+	// A remote engine leaves block updates to the server
+	e.Remote = true
+	w.Set(2, 41, 2, world.BlockTypeWater)
+	w.ScheduleBlockTick(2, 41, 2, world.WaterTickRate, 0)
+	for range 20 {
+		e.Tick()
+	}
+	if w.Get(3, 41, 2) == world.BlockTypeWater {
+		t.Error("remote engine ran block updates")
+	}
+}
@@ -0,0 +1,17 @@
+package sound
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// Listener is implemented by outputs that place world sounds around the
+// player, louder on the side they come from and fading with distance.
+type Listener interface {
+	SetListener(pos, front mgl32.Vec3)
+}
+
+// SetListener tells the output where the player hears from and which way
+// they face. Call it once a frame from the game loop.
+func SetListener(pos, front mgl32.Vec3) {
+	if l, ok := output.(Listener); ok {
+		l.SetListener(pos, front)
+	}
+}
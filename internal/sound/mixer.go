@@ -0,0 +1,75 @@
+package sound
+
+import "mini-mc/internal/config"
+
+// Ducking lowers the world buses while a menu or inventory covers the world,
+// fading over duckFadeTime so sounds under way are not cut off.
+const (
+	DuckedGain   = 0.3
+	duckFadeTime = 0.25 // seconds
+)
+
+// BusGainSetter is implemented by outputs whose sounds outlast a Play call,
+// such as music and loops, so that volume changes reach them while playing.
+type BusGainSetter interface {
+	SetBusGain(b config.AudioBus, gain float32)
+}
+
+var mixer = struct {
+	menuOpen bool
+	paused   bool
+	duck     float32 // world buses' current ducking gain, DuckedGain-1
+	gains    [config.AudioBusCount]float32
+}{duck: 1}
+
+// SetMenuOpen tells the mixer whether a menu or inventory covers the world.
+func SetMenuOpen(open bool) {
+	mixer.menuOpen = open
+}
+
+// SetPaused tells the mixer whether the game is paused.
+func SetPaused(paused bool) {
+	mixer.paused = paused
+}
+
+// Update fades ducking in or out over dt seconds and passes changed bus
+// gains to the output. Call it once a frame from the game loop.
+func Update(dt float64) {
+	target := float32(1)
+	if mixer.menuOpen && config.GetDuckInMenus() {
+		target = DuckedGain
+	}
+	step := float32(dt/duckFadeTime) * (1 - DuckedGain)
+	if mixer.duck < target {
+		mixer.duck = min(mixer.duck+step, target)
+	} else {
+		mixer.duck = max(mixer.duck-step, target)
+	}
+
+	setter, ok := output.(BusGainSetter)
+	for b := range config.AudioBusCount {
+		if g := BusGain(b); g != mixer.gains[b] {
+			mixer.gains[b] = g
+			if ok {
+				setter.SetBusGain(b, g)
+			}
+		}
+	}
+}
+
+// BusGain returns the gain sounds on bus b play at now: its volume times the
+// master volume, ducked under menus for world sounds, and 0 for world sounds
+// and music while paused if the game is set to mute them.
+func BusGain(b config.AudioBus) float32 {
+	if mixer.paused && config.GetMuteWhenPaused() && (b.IsWorld() || b == config.AudioBusMusic) {
+		return 0
+	}
+	g := config.GetBusVolume(config.AudioBusMaster)
+	if b != config.AudioBusMaster {
+		g *= config.GetBusVolume(b)
+	}
+	if b.IsWorld() {
+		g *= mixer.duck
+	}
+	return g
+}
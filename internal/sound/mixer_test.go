@@ -0,0 +1,54 @@
+package sound
+
+import (
+	"testing"
+
+	"mini-mc/internal/config"
+)
+
+type recordOutput []Event
+
+func (r *recordOutput) Play(e Event) { *r = append(*r, e) }
+
+func TestMixerDucksAndMutesWorldBuses(t *testing.T) {
+	var out recordOutput
+	SetOutput(&out)
+	t.Cleanup(func() {
+		SetOutput(nil)
+		SetMenuOpen(false)
+		SetPaused(false)
+		Update(1)
+		config.SetBusVolume(config.AudioBusMaster, 1)
+		config.SetBusVolume(config.AudioBusBlocks, 1)
+		config.SetMuteWhenPaused(false)
+	})
+	config.SetBusVolume(config.AudioBusMaster, 0.5)
+	config.SetBusVolume(config.AudioBusBlocks, 0.5)
+
+	Play(Event{Name: "dig.stone", Volume: 1, Bus: config.AudioBusBlocks})
+	if len(out) != 1 || out[0].Volume != 0.25 {
+		t.Fatalf("played %+v, want volume 0.25", out)
+	}
+
+	// Opening a menu fades the world down, not the UI
+	SetMenuOpen(true)
+	Update(duckFadeTime / 2)
+	if g := BusGain(config.AudioBusBlocks); g <= 0.25*DuckedGain || g >= 0.25 {
+		t.Errorf("half-faded blocks gain = %v", g)
+	}
+	Update(duckFadeTime)
+	if g := BusGain(config.AudioBusBlocks); g != 0.25*DuckedGain {
+		t.Errorf("ducked blocks gain = %v, want %v", g, 0.25*DuckedGain)
+	}
+	if g := BusGain(config.AudioBusUI); g != 0.5 {
+		t.Errorf("UI gain under a menu = %v, want 0.5", g)
+	}
+
+	// Muted while paused: world sounds are dropped
+	config.SetMuteWhenPaused(true)
+	SetPaused(true)
+	Play(Event{Name: "dig.stone", Volume: 1, Bus: config.AudioBusBlocks})
+	if len(out) != 1 || BusGain(config.AudioBusMusic) != 0 {
+		t.Errorf("world sound played or music left on while paused and muted")
+	}
+}
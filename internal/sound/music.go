@@ -0,0 +1,257 @@
+package sound
+
+import (
+	"io/fs"
+	"log/slog"
+	"math/rand"
+	"path/filepath"
+	"strings"
+
+	"mini-mc/internal/config"
+)
+
+// Music timing, after 1.8.9's MusicTicker: the first track starts soon
+// after joining, later ones after a random 10-20 minute gap.
+const (
+	firstTrackDelay = 5.0  // seconds
+	minTrackGap     = 600  // seconds
+	maxTrackGap     = 1200 // seconds
+	crossFadeTime   = 4.0  // seconds a mood change takes to fade one track into the next
+)
+
+// MusicStream is a music track being streamed from disk.
+type MusicStream interface {
+	// SetGain sets the track's volume, 0-1.
+	SetGain(gain float32)
+	// Done reports whether the track has played to its end.
+	Done() bool
+	// Stop ends the track and frees it.
+	Stop()
+}
+
+// MusicOutput is implemented by audio outputs that can stream music.
+type MusicOutput interface {
+	StreamMusic(path string) (MusicStream, error)
+}
+
+// MusicMood is what the player is surrounded by, which decides the tracks
+// that suit.
+type MusicMood struct {
+	Biome string // biome name, e.g. "Extreme Hills"
+	Night bool
+}
+
+// Music plays the OGG tracks in a directory one at a time with quiet gaps
+// between them, shuffled so none repeats until the rest have played.
+//
+// Tracks directly in the directory suit any mood. Those in a subdirectory
+// named "day", "night" or after a biome ("extreme_hills") are preferred in
+// that mood, and when the mood changes under a track that does not suit it
+// the track cross-fades into one that does. Volume follows the music bus.
+type Music struct {
+	// OnTrackStart is called with a track's display name as it starts, for
+	// the now-playing toast.
+	OnTrackStart func(name string)
+
+	tracks []musicTrack
+	played map[string]bool // tracks played since the shuffle last reset
+	rng    *rand.Rand
+
+	mood    MusicMood
+	current *playingTrack
+	fading  *playingTrack // the previous track, fading out under current
+	wait    float64       // seconds until the next track starts
+}
+
+type musicTrack struct {
+	path string
+	mood string // subdirectory, lowercase; "" suits any mood
+}
+
+type playingTrack struct {
+	track  musicTrack
+	stream MusicStream
+	fade   float32 // 0-1
+	fadeIn bool
+}
+
+// NewMusic finds the tracks under dir. A missing directory leaves the game
+// silent rather than failing.
+func NewMusic(dir string, seed int64) *Music {
+	m := &Music{
+		played: make(map[string]bool),
+		rng:    rand.New(rand.NewSource(seed)),
+		wait:   firstTrackDelay,
+	}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".ogg") {
+			return nil
+		}
+		mood := ""
+		if parent := filepath.Dir(path); parent != filepath.Clean(dir) {
+			mood = strings.ToLower(filepath.Base(parent))
+		}
+		m.tracks = append(m.tracks, musicTrack{path: path, mood: mood})
+		return nil
+	})
+	if err != nil {
+		slog.Debug("no music", "dir", dir, "err", err)
+	}
+	return m
+}
+
+// TrackName returns the name a track is shown under: its file name without
+// the extension, with underscores as spaces.
+func TrackName(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return strings.ReplaceAll(name, "_", " ")
+}
+
+// moodKeys returns the subdirectory names that suit mood.
+func moodKeys(mood MusicMood) [2]string {
+	daytime := "day"
+	if mood.Night {
+		daytime = "night"
+	}
+	return [2]string{strings.ToLower(strings.ReplaceAll(mood.Biome, " ", "_")), daytime}
+}
+
+// suits reports whether t is one of the tracks preferred in mood.
+func (t musicTrack) suits(mood MusicMood) bool {
+	keys := moodKeys(mood)
+	return t.mood != "" && (t.mood == keys[0] || t.mood == keys[1])
+}
+
+// candidates returns the tracks to pick from in mood: those preferred in
+// it, or the ones that suit any mood when none are.
+func (m *Music) candidates(mood MusicMood) []musicTrack {
+	var preferred, general []musicTrack
+	for _, t := range m.tracks {
+		switch {
+		case t.suits(mood):
+			preferred = append(preferred, t)
+		case t.mood == "":
+			general = append(general, t)
+		}
+	}
+	if len(preferred) > 0 {
+		return preferred
+	}
+	return general
+}
+
+// hasPreferred reports whether any track is preferred in mood.
+func (m *Music) hasPreferred(mood MusicMood) bool {
+	for _, t := range m.tracks {
+		if t.suits(mood) {
+			return true
+		}
+	}
+	return false
+}
+
+// pick shuffles through the candidates for mood, avoiding tracks already
+// played until all of them have been. ok is false when there are none.
+func (m *Music) pick(mood MusicMood) (t musicTrack, ok bool) {
+	tracks := m.candidates(mood)
+	if len(tracks) == 0 {
+		return musicTrack{}, false
+	}
+	var fresh []musicTrack
+	for _, t := range tracks {
+		if !m.played[t.path] {
+			fresh = append(fresh, t)
+		}
+	}
+	if len(fresh) == 0 {
+		for _, t := range tracks {
+			delete(m.played, t.path)
+		}
+		fresh = tracks
+	}
+	t = fresh[m.rng.Intn(len(fresh))]
+	m.played[t.path] = true
+	return t, true
+}
+
+// Update starts, fades and ends tracks over dt seconds as the player's mood
+// changes. Call it once a frame from the game loop.
+func (m *Music) Update(dt float64, mood MusicMood) {
+	if mood != m.mood {
+		m.mood = mood
+		// A track picked for the last mood gives way if others suit this one
+		if c := m.current; c != nil && m.fading == nil && !c.track.suits(mood) && m.hasPreferred(mood) {
+			c.fadeIn = false
+			m.fading, m.current = c, nil
+			m.start(0)
+		}
+	}
+
+	step := float32(dt / crossFadeTime)
+	if f := m.fading; f != nil {
+		f.fade -= step
+		if f.fade <= 0 || f.stream.Done() {
+			f.stream.Stop()
+			m.fading = nil
+		}
+	}
+	if c := m.current; c != nil {
+		if c.fadeIn {
+			c.fade = min(c.fade+step, 1)
+		}
+		if c.stream.Done() {
+			c.stream.Stop()
+			m.current = nil
+			m.wait = minTrackGap + m.rng.Float64()*(maxTrackGap-minTrackGap)
+		}
+	} else if m.fading == nil {
+		m.wait -= dt
+		if m.wait <= 0 {
+			m.start(1)
+		}
+	}
+
+	gain := BusGain(config.AudioBusMusic)
+	for _, t := range []*playingTrack{m.current, m.fading} {
+		if t != nil {
+			t.stream.SetGain(gain * t.fade)
+		}
+	}
+}
+
+// start plays a track for the current mood at fade, fading it in from
+// there. Without a music output the track is only logged and skipped.
+func (m *Music) start(fade float32) {
+	m.wait = minTrackGap + m.rng.Float64()*(maxTrackGap-minTrackGap)
+	t, ok := m.pick(m.mood)
+	if !ok {
+		return
+	}
+	out, ok := output.(MusicOutput)
+	if !ok {
+		slog.Debug("music", "track", t.path)
+		return
+	}
+	stream, err := out.StreamMusic(t.path)
+	if err != nil {
+		slog.Warn("playing music failed", "track", t.path, "err", err)
+		return
+	}
+	m.current = &playingTrack{track: t, stream: stream, fade: fade, fadeIn: true}
+	if m.OnTrackStart != nil {
+		m.OnTrackStart(TrackName(t.path))
+	}
+}
+
+// Stop ends whatever is playing.
+func (m *Music) Stop() {
+	for _, t := range []*playingTrack{m.current, m.fading} {
+		if t != nil {
+			t.stream.Stop()
+		}
+	}
+	m.current, m.fading = nil, nil
+}
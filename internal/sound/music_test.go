@@ -0,0 +1,84 @@
+package sound
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type fakeStream struct {
+	path    string
+	gain    float32
+	done    bool
+	stopped bool
+}
+
+func (s *fakeStream) SetGain(g float32) { s.gain = g }
+func (s *fakeStream) Done() bool        { return s.done }
+func (s *fakeStream) Stop()             { s.stopped = true }
+
+type musicOutput struct {
+	recordOutput
+	streams []*fakeStream
+}
+
+func (o *musicOutput) StreamMusic(path string) (MusicStream, error) {
+	s := &fakeStream{path: path}
+	o.streams = append(o.streams, s)
+	return s, nil
+}
+
+func TestMusicShufflesAndCrossFades(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"calm1.ogg", "calm2.ogg", "night/moon.ogg", "desert/dunes.ogg", "notes.txt"} {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		os.WriteFile(path, nil, 0o644)
+	}
+	out := &musicOutput{}
+	SetOutput(out)
+	t.Cleanup(func() { SetOutput(nil) })
+
+	m := NewMusic(dir, 1)
+	var toasts []string
+	m.OnTrackStart = func(name string) { toasts = append(toasts, name) }
+	plains := MusicMood{Biome: "Plains"}
+
+	// Both general tracks play before either repeats, with a gap between
+	for i := 0; i < 2; i++ {
+		m.Update(maxTrackGap, plains)
+		if len(out.streams) != i+1 {
+			t.Fatalf("%d tracks started after %d gaps", len(out.streams), i+1)
+		}
+		out.streams[i].done = true
+		m.Update(0, plains)
+	}
+	if a, b := out.streams[0].path, out.streams[1].path; a == b || filepath.Dir(a) != dir || filepath.Dir(b) != dir {
+		t.Errorf("played %s then %s, want both general tracks", a, b)
+	}
+	if len(toasts) != 2 || toasts[0] != TrackName(out.streams[0].path) {
+		t.Errorf("toasts = %v", toasts)
+	}
+
+	// Nightfall cross-fades into the night track
+	m.Update(maxTrackGap, plains)
+	day := out.streams[2]
+	m.Update(0, MusicMood{Biome: "Plains", Night: true})
+	if len(out.streams) != 4 || filepath.Base(out.streams[3].path) != "moon.ogg" {
+		t.Fatalf("no night track started at nightfall")
+	}
+	night := out.streams[3]
+	m.Update(crossFadeTime/2, MusicMood{Biome: "Plains", Night: true})
+	if day.gain <= 0 || night.gain <= 0 || day.stopped {
+		t.Errorf("mid cross-fade gains = %v, %v", day.gain, night.gain)
+	}
+	m.Update(crossFadeTime, MusicMood{Biome: "Plains", Night: true})
+	if !day.stopped || night.gain != 1 {
+		t.Errorf("after cross-fade: day stopped %v, night gain %v", day.stopped, night.gain)
+	}
+
+	m.Stop()
+	if !night.stopped {
+		t.Error("Stop left the track playing")
+	}
+}
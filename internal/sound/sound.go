@@ -0,0 +1,48 @@
+// Package sound carries sound events from gameplay to the audio output.
+// Sounds are named like 1.8.9's (e.g. "dig.stone") and mixed through the
+// bus they name (see BusGain). Until an output is installed with SetOutput,
+// events are only logged at debug level.
+package sound
+
+import (
+	"log/slog"
+
+	"mini-mc/internal/config"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Event is one sound to play at a position in the world.
+type Event struct {
+	Name   string
+	Pos    mgl32.Vec3
+	Volume float32
+	Pitch  float32
+	Bus    config.AudioBus // master when unset
+}
+
+// Output plays sound events.
+type Output interface {
+	Play(e Event)
+}
+
+var output Output
+
+// SetOutput installs the audio output; nil drops events again.
+func SetOutput(o Output) {
+	output = o
+}
+
+// Play sends e to the audio output at its bus's gain, dropping it when that
+// is silent. Call it from the game loop.
+func Play(e Event) {
+	e.Volume *= BusGain(e.Bus)
+	if e.Volume <= 0 {
+		return
+	}
+	if output == nil {
+		slog.Debug("sound", "name", e.Name, "bus", e.Bus, "pos", e.Pos, "volume", e.Volume, "pitch", e.Pitch)
+		return
+	}
+	output.Play(e)
+}
@@ -0,0 +1,140 @@
+package menu
+
+import (
+	"fmt"
+	"mini-mc/internal/config"
+	"mini-mc/internal/graphics/renderables/ui"
+	"mini-mc/internal/ui/widget"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// settingToggle pairs a toggle with its label and config getter so settings
+// pages can lay out and re-sync every row the same way.
+type settingToggle struct {
+	title  string
+	toggle *widget.Toggle
+	get    func() bool
+}
+
+// AccessibilityMenu is the accessibility settings page opened from the pause menu.
+type AccessibilityMenu struct {
+	toggles          []settingToggle
+	textScale        *widget.Slider
+	colorblindButton *widget.Button
+	doneButton       *widget.Button
+	shouldBack       bool
+}
+
+func NewAccessibilityMenu() *AccessibilityMenu {
+	am := &AccessibilityMenu{}
+
+	addToggle := func(title string, get func() bool, set func(bool)) {
+		t := widget.NewToggle(title, 0, 0, 40, 20, get(), func(isOn bool) {
+			set(isOn)
+			config.MarkOptionsDirty()
+		})
+		am.toggles = append(am.toggles, settingToggle{title: title, toggle: t, get: get})
+	}
+	addToggle("Reduced Motion", config.GetReducedMotion, config.SetReducedMotion)
+	addToggle("High Contrast Outlines", config.GetHighContrast, config.SetHighContrast)
+	addToggle("Disable Flashing Effects", config.GetDisableFlashing, config.SetDisableFlashing)
+	addToggle("Low Health Effect", config.GetLowHealthEffect, config.SetLowHealthEffect)
+	addToggle("Colorblind Foliage Tint", config.GetColorblindFoliage, config.SetColorblindFoliage)
+
+	// HUD Text Scale: Range 0.5-2.0 in 0.1 steps.
+	scaleRange := float32(config.MaxHUDTextScale - config.MinHUDTextScale)
+	scaleVal := (config.GetHUDTextScale() - config.MinHUDTextScale) / scaleRange
+	am.textScale = widget.NewSlider(0, 0, 200, 20, scaleVal, 16, "hudTextScale", func(val float32) {
+		config.SetHUDTextScale(config.MinHUDTextScale + val*scaleRange)
+	})
+	am.textScale.OnCommit = commitOption
+
+	am.colorblindButton = widget.NewButton("", 0, 0, 200, 40, func() {
+		config.CycleColorblindMode()
+		config.MarkOptionsDirty()
+	})
+	am.colorblindButton.NormalColor = mgl32.Vec3{0.2, 0.2, 0.2}
+	am.colorblindButton.HoverColor = mgl32.Vec3{0.3, 0.3, 0.3}
+
+	am.doneButton = widget.NewButton("Done", 0, 0, 200, 40, func() {
+		am.shouldBack = true
+	})
+	am.doneButton.NormalColor = mgl32.Vec3{0.2, 0.2, 0.2}
+	am.doneButton.HoverColor = mgl32.Vec3{0.3, 0.3, 0.3}
+
+	return am
+}
+
+// Update handles input and reports whether the player asked to go back.
+func (a *AccessibilityMenu) Update(window *glfw.Window, justPressedLeft bool) bool {
+	a.shouldBack = false
+
+	for _, row := range a.toggles {
+		row.toggle.IsOn = row.get()
+		row.toggle.HandleInput(window, justPressedLeft)
+	}
+	a.colorblindButton.HandleInput(window, justPressedLeft)
+	a.doneButton.HandleInput(window, justPressedLeft)
+
+	return a.shouldBack
+}
+
+func (a *AccessibilityMenu) Render(u *ui.UI, window *glfw.Window) {
+	fWinW, fWinH := ui.LayoutSize(window)
+	u.DrawFilledRect(0, 0, fWinW, fWinH, mgl32.Vec3{0, 0, 0}, 0.5)
+
+	centerX := fWinW / 2
+
+	title := "ACCESSIBILITY"
+	tw, _ := u.MeasureText(title, 1.0)
+	u.DrawText(title, centerX-tw/2, 80, 1.0, mgl32.Vec3{1, 1, 1})
+
+	startY := float32(150.0)
+	spacing := float32(50.0)
+	toggleW := float32(40.0)
+	sliderW := float32(200.0)
+
+	for _, row := range a.toggles {
+		lw, _ := u.MeasureText(row.title, 0.4)
+		u.DrawText(row.title, centerX-lw/2, startY-15, 0.4, mgl32.Vec3{1, 1, 1})
+
+		t := row.toggle
+		t.X = centerX - toggleW/2
+		t.Y = startY
+		t.W = toggleW
+		t.H = float32(20.0)
+		t.Render(u, window)
+
+		statusText := "Off"
+		if t.IsOn {
+			statusText = "On"
+		}
+		u.DrawText(statusText, t.X+toggleW+10, startY+15, 0.35, mgl32.Vec3{0.8, 0.8, 0.8})
+
+		startY += spacing
+	}
+
+	// HUD Text Scale
+	scaleTitle := "HUD Text Scale"
+	sw, _ := u.MeasureText(scaleTitle, 0.4)
+	u.DrawText(scaleTitle, centerX-sw/2, startY-15, 0.4, mgl32.Vec3{1, 1, 1})
+	a.textScale.X = centerX - sliderW/2
+	a.textScale.Y = startY
+	a.textScale.W = sliderW
+	a.textScale.H = float32(20.0)
+	a.textScale.Render(u, window)
+	u.DrawText(fmt.Sprintf("%.1fx", config.GetHUDTextScale()), a.textScale.X+sliderW+10, startY+15, 0.35, mgl32.Vec3{0.8, 0.8, 0.8})
+
+	startY += spacing - 20
+
+	a.colorblindButton.Text = "Colorblind: " + config.GetColorblindMode().String()
+	a.colorblindButton.SetPosition(centerX-100, startY)
+	a.colorblindButton.Render(u, window)
+
+	startY += 50
+
+	a.doneButton.SetPosition(centerX-100, startY)
+	a.doneButton.Render(u, window)
+}
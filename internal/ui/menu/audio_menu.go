@@ -0,0 +1,117 @@
+package menu
+
+import (
+	"fmt"
+	"mini-mc/internal/config"
+	"mini-mc/internal/graphics/renderables/ui"
+	"mini-mc/internal/ui/widget"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// AudioMenu is the audio settings page opened from the pause menu: a volume
+// slider per mixer bus and the menu ducking and pause mute switches.
+type AudioMenu struct {
+	volumes    [config.AudioBusCount]*widget.Slider
+	toggles    []settingToggle
+	doneButton *widget.Button
+	shouldBack bool
+}
+
+func NewAudioMenu() *AudioMenu {
+	am := &AudioMenu{}
+
+	// Volumes: 0-100% in 5% steps
+	for b := range config.AudioBusCount {
+		s := widget.NewSlider(0, 0, 200, 20, config.GetBusVolume(b), 21, "volume"+b.String(), func(val float32) {
+			config.SetBusVolume(b, val)
+		})
+		s.OnCommit = commitOption
+		am.volumes[b] = s
+	}
+
+	addToggle := func(title string, get func() bool, set func(bool)) {
+		t := widget.NewToggle(title, 0, 0, 40, 20, get(), func(isOn bool) {
+			set(isOn)
+			config.MarkOptionsDirty()
+		})
+		am.toggles = append(am.toggles, settingToggle{title: title, toggle: t, get: get})
+	}
+	addToggle("Lower World Sounds in Menus", config.GetDuckInMenus, config.SetDuckInMenus)
+	addToggle("Mute When Paused", config.GetMuteWhenPaused, config.SetMuteWhenPaused)
+
+	am.doneButton = widget.NewButton("Done", 0, 0, 200, 40, func() {
+		am.shouldBack = true
+	})
+	am.doneButton.NormalColor = mgl32.Vec3{0.2, 0.2, 0.2}
+	am.doneButton.HoverColor = mgl32.Vec3{0.3, 0.3, 0.3}
+
+	return am
+}
+
+// Update handles input and reports whether the player asked to go back.
+func (a *AudioMenu) Update(window *glfw.Window, justPressedLeft bool) bool {
+	a.shouldBack = false
+
+	for _, row := range a.toggles {
+		row.toggle.IsOn = row.get()
+		row.toggle.HandleInput(window, justPressedLeft)
+	}
+	a.doneButton.HandleInput(window, justPressedLeft)
+
+	return a.shouldBack
+}
+
+func (a *AudioMenu) Render(u *ui.UI, window *glfw.Window) {
+	fWinW, fWinH := ui.LayoutSize(window)
+	u.DrawFilledRect(0, 0, fWinW, fWinH, mgl32.Vec3{0, 0, 0}, 0.5)
+
+	centerX := fWinW / 2
+
+	title := "AUDIO"
+	tw, _ := u.MeasureText(title, 1.0)
+	u.DrawText(title, centerX-tw/2, 80, 1.0, mgl32.Vec3{1, 1, 1})
+
+	startY := float32(150.0)
+	spacing := float32(50.0)
+	toggleW := float32(40.0)
+	sliderW := float32(200.0)
+
+	for b, s := range a.volumes {
+		label := config.AudioBus(b).String()
+		lw, _ := u.MeasureText(label, 0.4)
+		u.DrawText(label, centerX-lw/2, startY-15, 0.4, mgl32.Vec3{1, 1, 1})
+		s.X = centerX - sliderW/2
+		s.Y = startY
+		s.W = sliderW
+		s.H = float32(20.0)
+		s.Render(u, window)
+		u.DrawText(fmt.Sprintf("%.0f%%", s.Value*100), s.X+sliderW+10, startY+15, 0.35, mgl32.Vec3{0.8, 0.8, 0.8})
+
+		startY += spacing
+	}
+
+	for _, row := range a.toggles {
+		lw, _ := u.MeasureText(row.title, 0.4)
+		u.DrawText(row.title, centerX-lw/2, startY-15, 0.4, mgl32.Vec3{1, 1, 1})
+
+		t := row.toggle
+		t.X = centerX - toggleW/2
+		t.Y = startY
+		t.W = toggleW
+		t.H = float32(20.0)
+		t.Render(u, window)
+
+		statusText := "Off"
+		if t.IsOn {
+			statusText = "On"
+		}
+		u.DrawText(statusText, t.X+toggleW+10, startY+15, 0.35, mgl32.Vec3{0.8, 0.8, 0.8})
+
+		startY += spacing
+	}
+
+	a.doneButton.SetPosition(centerX-100, startY-20)
+	a.doneButton.Render(u, window)
+}
@@ -0,0 +1,76 @@
+package menu
+
+import (
+	"time"
+
+	"mini-mc/internal/graphics/renderables/ui"
+	"mini-mc/internal/ui/widget"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// deathScreenDelay is how long the death screen ignores clicks after it
+// opens, so a click meant for the fight that ended doesn't respawn.
+const deathScreenDelay = time.Second
+
+// DeathScreen is shown over the world while the player is dead. It offers
+// to respawn or to quit to the main menu.
+type DeathScreen struct {
+	buttons []*widget.Button
+	opened  time.Time
+	action  Action
+}
+
+func NewDeathScreen() *DeathScreen {
+	d := &DeathScreen{}
+
+	respawnBtn := widget.NewButton("Respawn", 0, 0, 200, 40, func() {
+		d.action = ActionRespawn
+	})
+	respawnBtn.NormalColor = mgl32.Vec3{0.2, 0.2, 0.2}
+	respawnBtn.HoverColor = mgl32.Vec3{0.3, 0.3, 0.3}
+	d.buttons = append(d.buttons, respawnBtn)
+
+	quitBtn := widget.NewButton("Main Menu", 0, 0, 200, 40, func() {
+		d.action = ActionQuitToMenu
+	})
+	quitBtn.NormalColor = mgl32.Vec3{0.2, 0.2, 0.2}
+	quitBtn.HoverColor = mgl32.Vec3{0.3, 0.3, 0.3}
+	d.buttons = append(d.buttons, quitBtn)
+
+	return d
+}
+
+// Open restarts the delay before the buttons respond.
+func (d *DeathScreen) Open() {
+	d.opened = time.Now()
+}
+
+func (d *DeathScreen) Update(window *glfw.Window, justPressedLeft bool) Action {
+	d.action = ActionNone
+	if time.Since(d.opened) < deathScreenDelay {
+		return ActionNone
+	}
+	for _, btn := range d.buttons {
+		btn.HandleInput(window, justPressedLeft)
+	}
+	return d.action
+}
+
+func (d *DeathScreen) Render(u *ui.UI, window *glfw.Window) {
+	fWinW, fWinH := ui.LayoutSize(window)
+	u.DrawFilledRect(0, 0, fWinW, fWinH, mgl32.Vec3{0.5, 0, 0}, 0.4)
+
+	centerX := fWinW / 2
+	title := "You died!"
+	tw, _ := u.MeasureText(title, 1.5)
+	u.DrawText(title, centerX-tw/2, fWinH/4, 1.5, mgl32.Vec3{1, 1, 1})
+
+	startY := fWinH/2 - 20
+	for _, btn := range d.buttons {
+		btn.SetPosition(centerX-100, startY)
+		btn.Render(u, window)
+		startY += 50
+	}
+}
@@ -0,0 +1,106 @@
+package menu
+
+import (
+	"mini-mc/internal/config"
+	"mini-mc/internal/graphics/renderables/ui"
+	"mini-mc/internal/ui/widget"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// HUDMenu is the HUD settings page opened from the pause menu: a row per
+// info widget with a switch to show it and a button cycling its corner.
+type HUDMenu struct {
+	toggles    [config.HUDWidgetCount]settingToggle
+	corners    [config.HUDWidgetCount]*widget.Button
+	doneButton *widget.Button
+	shouldBack bool
+}
+
+func NewHUDMenu() *HUDMenu {
+	hm := &HUDMenu{}
+
+	for w := range config.HUDWidgetCount {
+		get := func() bool { return config.GetHUDWidgetShown(w) }
+		t := widget.NewToggle(w.String(), 0, 0, 40, 20, get(), func(isOn bool) {
+			config.SetHUDWidgetShown(w, isOn)
+			config.MarkOptionsDirty()
+		})
+		hm.toggles[w] = settingToggle{title: w.String(), toggle: t, get: get}
+
+		corner := widget.NewButton("", 0, 0, 140, 30, func() {
+			config.CycleHUDWidgetCorner(w)
+			config.MarkOptionsDirty()
+		})
+		corner.NormalColor = mgl32.Vec3{0.2, 0.2, 0.2}
+		corner.HoverColor = mgl32.Vec3{0.3, 0.3, 0.3}
+		hm.corners[w] = corner
+	}
+
+	hm.doneButton = widget.NewButton("Done", 0, 0, 200, 40, func() {
+		hm.shouldBack = true
+	})
+	hm.doneButton.NormalColor = mgl32.Vec3{0.2, 0.2, 0.2}
+	hm.doneButton.HoverColor = mgl32.Vec3{0.3, 0.3, 0.3}
+
+	return hm
+}
+
+// Update handles input and reports whether the player asked to go back.
+func (h *HUDMenu) Update(window *glfw.Window, justPressedLeft bool) bool {
+	h.shouldBack = false
+
+	for w, row := range h.toggles {
+		row.toggle.IsOn = row.get()
+		row.toggle.HandleInput(window, justPressedLeft)
+		h.corners[w].HandleInput(window, justPressedLeft)
+	}
+	h.doneButton.HandleInput(window, justPressedLeft)
+
+	return h.shouldBack
+}
+
+func (h *HUDMenu) Render(u *ui.UI, window *glfw.Window) {
+	fWinW, fWinH := ui.LayoutSize(window)
+	u.DrawFilledRect(0, 0, fWinW, fWinH, mgl32.Vec3{0, 0, 0}, 0.5)
+
+	centerX := fWinW / 2
+
+	title := "HUD"
+	tw, _ := u.MeasureText(title, 1.0)
+	u.DrawText(title, centerX-tw/2, 80, 1.0, mgl32.Vec3{1, 1, 1})
+
+	startY := float32(150.0)
+	spacing := float32(50.0)
+	toggleW := float32(40.0)
+
+	// Label, switch and corner side by side
+	for w, row := range h.toggles {
+		lw, _ := u.MeasureText(row.title, 0.4)
+		u.DrawText(row.title, centerX-90-lw, startY+15, 0.4, mgl32.Vec3{1, 1, 1})
+
+		t := row.toggle
+		t.X = centerX - 70
+		t.Y = startY
+		t.W = toggleW
+		t.H = float32(20.0)
+		t.Render(u, window)
+
+		statusText := "Off"
+		if t.IsOn {
+			statusText = "On"
+		}
+		u.DrawText(statusText, t.X+toggleW+10, startY+15, 0.35, mgl32.Vec3{0.8, 0.8, 0.8})
+
+		corner := h.corners[w]
+		corner.Text = config.GetHUDWidgetCorner(config.HUDWidget(w)).String()
+		corner.SetPosition(centerX+20, startY-5)
+		corner.Render(u, window)
+
+		startY += spacing
+	}
+
+	h.doneButton.SetPosition(centerX-100, startY)
+	h.doneButton.Render(u, window)
+}
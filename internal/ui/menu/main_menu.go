@@ -12,6 +12,18 @@ type MainMenu struct {
 	buttons             []*widget.Button
 	shouldStartSurvival bool
 	shouldStartCreative bool
+
+	// backdrop is true while a world panorama is drawn behind the menu
+	backdrop bool
+
+	world *WorldInfo // saved world shown under the buttons; nil if none
+}
+
+// WorldInfo describes a saved world for the world list.
+type WorldInfo struct {
+	Name    string
+	Details string // game mode, play time, last played
+	IconTex uint32 // 2D texture of the world icon, 0 if it has none
 }
 
 func NewMainMenu() *MainMenu {
@@ -36,6 +48,17 @@ func NewMainMenu() *MainMenu {
 	return mm
 }
 
+// SetBackdrop tells the menu whether a panorama is drawn behind it. With a
+// backdrop the background is a translucent shade instead of an opaque fill.
+func (m *MainMenu) SetBackdrop(enabled bool) {
+	m.backdrop = enabled
+}
+
+// SetWorld sets the saved world listed under the buttons; nil hides it.
+func (m *MainMenu) SetWorld(info *WorldInfo) {
+	m.world = info
+}
+
 func (m *MainMenu) Update(window *glfw.Window, justPressedLeft bool) Action {
 	m.shouldStartSurvival = false
 	m.shouldStartCreative = false
@@ -54,8 +77,7 @@ func (m *MainMenu) Update(window *glfw.Window, justPressedLeft bool) Action {
 }
 
 func (m *MainMenu) Render(u *ui.UI, window *glfw.Window) {
-	width, height := window.GetSize()
-	fWinW, fWinH := float32(width), float32(height)
+	fWinW, fWinH := ui.LayoutSize(window)
 
 	// Calculate scale
 	scaleX := fWinW / 900.0
@@ -85,8 +107,12 @@ func (m *MainMenu) Render(u *ui.UI, window *glfw.Window) {
 	m.buttons[1].SetPosition(btnX, cBtnY)
 	m.buttons[1].SetSize(btnW, btnH)
 
-	// Draw background
-	u.DrawFilledRect(0, 0, fWinW, fWinH, mgl32.Vec3{0.1, 0.1, 0.1}, 1.0)
+	// Draw background (shade the panorama so the text stays readable)
+	if m.backdrop {
+		u.DrawFilledRect(0, 0, fWinW, fWinH, mgl32.Vec3{0, 0, 0}, 0.35)
+	} else {
+		u.DrawFilledRect(0, 0, fWinW, fWinH, mgl32.Vec3{0.1, 0.1, 0.1}, 1.0)
+	}
 
 	// Title: MINI MC
 	title := "MINI MC"
@@ -104,4 +130,22 @@ func (m *MainMenu) Render(u *ui.UI, window *glfw.Window) {
 	for _, btn := range m.buttons {
 		btn.Render(u, window)
 	}
+
+	if m.world != nil {
+		m.renderWorld(u, btnX, cBtnY+btnH+20*scale, btnW, scale)
+	}
+}
+
+// renderWorld draws the saved world's entry: icon, name and details.
+func (m *MainMenu) renderWorld(u *ui.UI, x, y, w, scale float32) {
+	h := 56 * scale
+	u.DrawFilledRect(x, y, w, h, mgl32.Vec3{0, 0, 0}, 0.5)
+	iconSize := h - 8*scale
+	textX := x + 8*scale
+	if m.world.IconTex != 0 {
+		u.DrawTexturedRect(x+4*scale, y+4*scale, iconSize, iconSize, m.world.IconTex, 0, 0, 1, 1, mgl32.Vec3{1, 1, 1}, 1)
+		textX += iconSize + 4*scale
+	}
+	u.DrawText(m.world.Name, textX, y+22*scale, 0.4*scale, mgl32.Vec3{1, 1, 1})
+	u.DrawText(m.world.Details, textX, y+44*scale, 0.3*scale, mgl32.Vec3{0.7, 0.7, 0.7})
 }
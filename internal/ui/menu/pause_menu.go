@@ -14,22 +14,57 @@ type PauseMenu struct {
 	buttons      []*widget.Button
 	renderDist   *widget.Slider
 	fpsLimit     *widget.Slider
+	sensitivity  *widget.Slider
+	fov          *widget.Slider
+	entitySim    *widget.Slider
+	entityRender *widget.Slider
 	bobbing      *widget.Toggle
+	foliage      *widget.Toggle
+	ao           *widget.Toggle
+	fancyWater   *widget.Toggle
+	sunShafts    *widget.Toggle
+	shadows      *widget.Button
 	shouldResume bool
 	shouldQuit   bool
+	togglePregen bool
+
+	// Accessibility sub-page
+	accessibility     *AccessibilityMenu
+	showAccessibility bool
+
+	// Audio sub-page
+	audio     *AudioMenu
+	showAudio bool
+
+	// HUD sub-page
+	hud     *HUDMenu
+	showHUD bool
+}
+
+// commitOption is the OnCommit of sliders whose setting changes live while
+// dragging: letting go only marks the settings for saving.
+func commitOption(float32) {
+	config.MarkOptionsDirty()
 }
 
 func NewPauseMenu() *PauseMenu {
-	pm := &PauseMenu{}
+	pm := &PauseMenu{
+		accessibility: NewAccessibilityMenu(),
+		audio:         NewAudioMenu(),
+		hud:           NewHUDMenu(),
+	}
 
 	// Initialize Sliders & Toggles with current config
 	// Render Distance: Range 5-50. Slider 0-1 mapped to this.
 	curDist := config.GetRenderDistance()
 	distVal := float32(curDist-5) / float32(50-5)
-	pm.renderDist = widget.NewSlider(0, 0, 200, 20, distVal, 46, "renderDist", func(val float32) {
+	// Applied on release only: each change reloads chunks around the player
+	pm.renderDist = widget.NewSlider(0, 0, 200, 20, distVal, 46, "renderDist", nil)
+	pm.renderDist.OnCommit = func(val float32) {
 		chunks := int(5 + val*45 + 0.5)
 		config.SetRenderDistance(chunks)
-	})
+		config.MarkOptionsDirty()
+	}
 
 	// FPS Limit: Range 30-240 (mapped), 0 (uncapped) at max.
 	// Logic: 0.0-0.9 -> 30-240. >0.9 -> Uncapped.
@@ -51,12 +86,75 @@ func NewPauseMenu() *PauseMenu {
 			config.SetFPSLimit(limit)
 		}
 	})
+	pm.fpsLimit.OnCommit = commitOption
+
+	// Mouse Sensitivity: Range 0.02-0.4 degrees per pixel in 0.01 steps.
+	sensRange := float32(config.MaxMouseSensitivity - config.MinMouseSensitivity)
+	sensVal := (config.GetMouseSensitivity() - config.MinMouseSensitivity) / sensRange
+	pm.sensitivity = widget.NewSlider(0, 0, 200, 20, sensVal, 39, "sensitivity", func(val float32) {
+		config.SetMouseSensitivity(config.MinMouseSensitivity + val*sensRange)
+	})
+	pm.sensitivity.OnCommit = commitOption
+
+	// FOV: Range 30-110 degrees.
+	fovVal := float32(config.GetFOV()-config.MinFOV) / float32(config.MaxFOV-config.MinFOV)
+	pm.fov = widget.NewSlider(0, 0, 200, 20, fovVal, config.MaxFOV-config.MinFOV+1, "fov", func(val float32) {
+		config.SetFOV(config.MinFOV + int(val*(config.MaxFOV-config.MinFOV)+0.5))
+	})
+	pm.fov.OnCommit = commitOption
+
+	// Entity Simulation and Render Distances, in chunks
+	simRange := config.MaxEntitySimulationDistance - config.MinEntitySimulationDistance
+	simVal := float32(config.GetEntitySimulationDistance()-config.MinEntitySimulationDistance) / float32(simRange)
+	pm.entitySim = widget.NewSlider(0, 0, 200, 20, simVal, simRange+1, "entitySim", func(val float32) {
+		config.SetEntitySimulationDistance(config.MinEntitySimulationDistance + int(val*float32(simRange)+0.5))
+	})
+	pm.entitySim.OnCommit = commitOption
+	renderRange := config.MaxEntityRenderDistance - config.MinEntityRenderDistance
+	renderVal := float32(config.GetEntityRenderDistance()-config.MinEntityRenderDistance) / float32(renderRange)
+	pm.entityRender = widget.NewSlider(0, 0, 200, 20, renderVal, renderRange+1, "entityRender", func(val float32) {
+		config.SetEntityRenderDistance(config.MinEntityRenderDistance + int(val*float32(renderRange)+0.5))
+	})
+	pm.entityRender.OnCommit = commitOption
 
 	// View Bobbing
 	pm.bobbing = widget.NewToggle("View Bobbing", 0, 0, 40, 20, config.GetViewBobbing(), func(isOn bool) {
 		config.SetViewBobbing(isOn)
+		config.MarkOptionsDirty()
+	})
+
+	// Waving Foliage
+	pm.foliage = widget.NewToggle("Waving Foliage", 0, 0, 40, 20, config.GetFoliageWaving(), func(isOn bool) {
+		config.SetFoliageWaving(isOn)
+		config.MarkOptionsDirty()
+	})
+
+	// Ambient Occlusion
+	pm.ao = widget.NewToggle("Ambient Occlusion", 0, 0, 40, 20, config.GetAmbientOcclusion(), func(isOn bool) {
+		config.SetAmbientOcclusion(isOn)
+		config.MarkOptionsDirty()
+	})
+
+	// Fancy Water
+	pm.fancyWater = widget.NewToggle("Fancy Water", 0, 0, 40, 20, config.GetFancyWater(), func(isOn bool) {
+		config.SetFancyWater(isOn)
+		config.MarkOptionsDirty()
+	})
+
+	// Sun Shafts
+	pm.sunShafts = widget.NewToggle("Sun Shafts", 0, 0, 40, 20, config.GetSunShafts(), func(isOn bool) {
+		config.SetSunShafts(isOn)
+		config.MarkOptionsDirty()
 	})
 
+	// Shadows: Off, Low or High
+	pm.shadows = widget.NewButton("", 0, 0, 200, 40, func() {
+		config.CycleShadowQuality()
+		config.MarkOptionsDirty()
+	})
+	pm.shadows.NormalColor = mgl32.Vec3{0.2, 0.2, 0.2}
+	pm.shadows.HoverColor = mgl32.Vec3{0.3, 0.3, 0.3}
+
 	// Resume Button
 	resumeBtn := widget.NewButton("Continue", 0, 0, 200, 40, func() {
 		pm.shouldResume = true
@@ -65,6 +163,30 @@ func NewPauseMenu() *PauseMenu {
 	resumeBtn.HoverColor = mgl32.Vec3{0.3, 0.3, 0.3}
 	pm.buttons = append(pm.buttons, resumeBtn)
 
+	// Accessibility Button
+	accessBtn := widget.NewButton("Accessibility...", 0, 0, 145, 40, func() {
+		pm.showAccessibility = true
+	})
+	accessBtn.NormalColor = mgl32.Vec3{0.2, 0.2, 0.2}
+	accessBtn.HoverColor = mgl32.Vec3{0.3, 0.3, 0.3}
+	pm.buttons = append(pm.buttons, accessBtn)
+
+	// Audio Button
+	audioBtn := widget.NewButton("Audio...", 0, 0, 145, 40, func() {
+		pm.showAudio = true
+	})
+	audioBtn.NormalColor = mgl32.Vec3{0.2, 0.2, 0.2}
+	audioBtn.HoverColor = mgl32.Vec3{0.3, 0.3, 0.3}
+	pm.buttons = append(pm.buttons, audioBtn)
+
+	// Pregenerate Button
+	pregenBtn := widget.NewButton("Pregenerate World", 0, 0, 200, 40, func() {
+		pm.togglePregen = true
+	})
+	pregenBtn.NormalColor = mgl32.Vec3{0.2, 0.2, 0.2}
+	pregenBtn.HoverColor = mgl32.Vec3{0.3, 0.3, 0.3}
+	pm.buttons = append(pm.buttons, pregenBtn)
+
 	// Quit Button
 	quitBtn := widget.NewButton("Main Menu", 0, 0, 200, 40, func() {
 		pm.shouldQuit = true
@@ -73,21 +195,58 @@ func NewPauseMenu() *PauseMenu {
 	quitBtn.HoverColor = mgl32.Vec3{0.3, 0.3, 0.3}
 	pm.buttons = append(pm.buttons, quitBtn)
 
+	// HUD Button
+	hudBtn := widget.NewButton("HUD...", 0, 0, 145, 40, func() {
+		pm.showHUD = true
+	})
+	hudBtn.NormalColor = mgl32.Vec3{0.2, 0.2, 0.2}
+	hudBtn.HoverColor = mgl32.Vec3{0.3, 0.3, 0.3}
+	pm.buttons = append(pm.buttons, hudBtn)
+
 	return pm
 }
 
 func (p *PauseMenu) Update(window *glfw.Window, justPressedLeft bool) Action {
 	p.shouldResume = false
 	p.shouldQuit = false
+	p.togglePregen = false
+
+	if p.showAccessibility {
+		if p.accessibility.Update(window, justPressedLeft) {
+			p.showAccessibility = false
+		}
+		return ActionNone
+	}
+	if p.showAudio {
+		if p.audio.Update(window, justPressedLeft) {
+			p.showAudio = false
+		}
+		return ActionNone
+	}
+	if p.showHUD {
+		if p.hud.Update(window, justPressedLeft) {
+			p.showHUD = false
+		}
+		return ActionNone
+	}
 
 	// Update sync with config (in case changed externally)
 	// For sliders, we trust internal state unless we want full bi-directional sync every frame.
 	// For toggle, it's safer to sync to visual if changed by keybind?
 	p.bobbing.IsOn = config.GetViewBobbing()
+	p.foliage.IsOn = config.GetFoliageWaving()
+	p.ao.IsOn = config.GetAmbientOcclusion()
+	p.fancyWater.IsOn = config.GetFancyWater()
+	p.sunShafts.IsOn = config.GetSunShafts()
 
 	// Update components
 	// Render handles slider input (DrawSlider), but we need to propagate clicks for buttons/toggles
 	p.bobbing.HandleInput(window, justPressedLeft)
+	p.foliage.HandleInput(window, justPressedLeft)
+	p.ao.HandleInput(window, justPressedLeft)
+	p.fancyWater.HandleInput(window, justPressedLeft)
+	p.sunShafts.HandleInput(window, justPressedLeft)
+	p.shadows.HandleInput(window, justPressedLeft)
 	for _, btn := range p.buttons {
 		btn.HandleInput(window, justPressedLeft)
 	}
@@ -98,13 +257,38 @@ func (p *PauseMenu) Update(window *glfw.Window, justPressedLeft bool) Action {
 	if p.shouldQuit {
 		return ActionQuitToMenu
 	}
+	if p.togglePregen {
+		return ActionTogglePregen
+	}
 	return ActionNone
 }
 
+// SetPregenRunning switches the pregenerate button between starting and
+// stopping a job.
+func (p *PauseMenu) SetPregenRunning(running bool) {
+	if running {
+		p.buttons[3].Text = "Stop Pregenerating"
+	} else {
+		p.buttons[3].Text = "Pregenerate World"
+	}
+}
+
 func (p *PauseMenu) Render(u *ui.UI, window *glfw.Window) {
+	if p.showAccessibility {
+		p.accessibility.Render(u, window)
+		return
+	}
+	if p.showAudio {
+		p.audio.Render(u, window)
+		return
+	}
+	if p.showHUD {
+		p.hud.Render(u, window)
+		return
+	}
+
 	// Draw background overlay
-	winW, winH := window.GetSize()
-	fWinW, fWinH := float32(winW), float32(winH)
+	fWinW, fWinH := ui.LayoutSize(window)
 	u.DrawFilledRect(0, 0, fWinW, fWinH, mgl32.Vec3{0, 0, 0}, 0.5)
 
 	centerX := fWinW / 2
@@ -118,37 +302,10 @@ func (p *PauseMenu) Render(u *ui.UI, window *glfw.Window) {
 	startY := float32(150.0)
 	spacing := float32(70.0)
 	sliderW := float32(200.0)
-	sliderH := float32(20.0)
-
-	// 1. Render Distance
-	// Label
-	rdTitle := "Render Distance"
-	rdW, _ := u.MeasureText(rdTitle, 0.4)
-	u.DrawText(rdTitle, centerX-rdW/2, startY-15, 0.4, mgl32.Vec3{1, 1, 1})
-	// Slider
-	p.renderDist.X = centerX - sliderW/2
-	p.renderDist.Y = startY
-	p.renderDist.W = sliderW
-	p.renderDist.H = sliderH
-	p.renderDist.Render(u, window)
-	// Value Text
-	distVal := int(5 + p.renderDist.Value*45 + 0.5)
-	u.DrawText(fmt.Sprintf("%d chunks", distVal), p.renderDist.X+sliderW+10, startY+15, 0.35, mgl32.Vec3{0.8, 0.8, 0.8})
 
-	startY += spacing
-
-	// 2. FPS Limit
-	// Label
-	fpsTitle := "FPS Limit"
-	fpsW, _ := u.MeasureText(fpsTitle, 0.4)
-	u.DrawText(fpsTitle, centerX-fpsW/2, startY-15, 0.4, mgl32.Vec3{1, 1, 1})
-	// Slider
-	p.fpsLimit.X = centerX - sliderW/2
-	p.fpsLimit.Y = startY
-	p.fpsLimit.W = sliderW
-	p.fpsLimit.H = sliderH
-	p.fpsLimit.Render(u, window)
-	// Value Text
+	// 1. Render Distance and FPS Limit, side by side
+	distVal := int(5 + p.renderDist.Value*45 + 0.5)
+	p.renderSlider(u, window, p.renderDist, "Render Distance", fmt.Sprintf("%d chunks", distVal), centerX-sliderW-60, startY)
 	var fpsText string
 	if p.fpsLimit.Value > 0.99 {
 		fpsText = "Uncapped"
@@ -156,38 +313,85 @@ func (p *PauseMenu) Render(u *ui.UI, window *glfw.Window) {
 		limit := int(30 + p.fpsLimit.Value*210 + 0.5)
 		fpsText = fmt.Sprintf("%d FPS", limit)
 	}
-	u.DrawText(fpsText, p.fpsLimit.X+sliderW+10, startY+15, 0.35, mgl32.Vec3{0.8, 0.8, 0.8})
+	p.renderSlider(u, window, p.fpsLimit, "FPS Limit", fpsText, centerX+60, startY)
 
 	startY += spacing
 
-	// 3. View Bobbing
-	// Label
-	bobTitle := "View Bobbing"
-	bobW, _ := u.MeasureText(bobTitle, 0.4)
-	u.DrawText(bobTitle, centerX-bobW/2, startY-15, 0.4, mgl32.Vec3{1, 1, 1})
-	// Toggle
-	toggleW := float32(40.0)
-	p.bobbing.X = centerX - toggleW/2
-	p.bobbing.Y = startY
-	p.bobbing.W = toggleW
-	p.bobbing.H = float32(20.0)
-	p.bobbing.Render(u, window)
-	// Status Text
-	statusText := "Off"
-	if p.bobbing.IsOn {
-		statusText = "On"
-	}
-	u.DrawText(statusText, p.bobbing.X+toggleW+10, startY+15, 0.35, mgl32.Vec3{0.8, 0.8, 0.8})
+	// 2. Mouse Sensitivity and FOV, side by side
+	sensPercent := int(config.GetMouseSensitivity()/config.DefaultMouseSensitivity*100 + 0.5)
+	p.renderSlider(u, window, p.sensitivity, "Mouse Sensitivity", fmt.Sprintf("%d%%", sensPercent), centerX-sliderW-60, startY)
+	p.renderSlider(u, window, p.fov, "FOV", fmt.Sprintf("%d degrees", config.GetFOV()), centerX+60, startY)
 
 	startY += spacing
 
-	// 4. Resume Button
+	// 3. Entity Simulation and Render Distances, side by side
+	p.renderSlider(u, window, p.entitySim, "Entity Simulation", fmt.Sprintf("%d chunks", config.GetEntitySimulationDistance()), centerX-sliderW-60, startY)
+	p.renderSlider(u, window, p.entityRender, "Entity Render Distance", fmt.Sprintf("%d chunks", config.GetEntityRenderDistance()), centerX+60, startY)
+
+	startY += spacing
+
+	// 4. View Bobbing, Waving Foliage, Ambient Occlusion, Fancy Water and Sun Shafts, side by side
+	toggleW := float32(40.0)
+	toggles := []*widget.Toggle{p.bobbing, p.foliage, p.ao, p.fancyWater, p.sunShafts}
+	for i, t := range toggles {
+		colX := centerX - float32(len(toggles)-1)*80 + float32(i)*160
+		tW, _ := u.MeasureText(t.Label, 0.4)
+		u.DrawText(t.Label, colX-tW/2, startY-15, 0.4, mgl32.Vec3{1, 1, 1})
+		t.X = colX - toggleW/2
+		t.Y = startY
+		t.W = toggleW
+		t.H = float32(20.0)
+		t.Render(u, window)
+		statusText := "Off"
+		if t.IsOn {
+			statusText = "On"
+		}
+		u.DrawText(statusText, t.X+toggleW+10, startY+15, 0.35, mgl32.Vec3{0.8, 0.8, 0.8})
+	}
+
+	startY += 40
+
+	// 5. Shadow Quality Button
+	p.shadows.Text = "Shadows: " + config.GetShadowQuality().String()
+	p.shadows.SetPosition(centerX-100, startY)
+	p.shadows.Render(u, window)
+
+	startY += 60
+
+	// 6. Resume Button
 	p.buttons[0].SetPosition(centerX-100, startY)
 	p.buttons[0].Render(u, window)
 
 	startY += 50
 
-	// 5. Quit Button
-	p.buttons[1].SetPosition(centerX-100, startY)
+	// 7. Accessibility, Audio and HUD Buttons, side by side
+	p.buttons[1].SetPosition(centerX-225, startY)
 	p.buttons[1].Render(u, window)
+	p.buttons[2].SetPosition(centerX-72, startY)
+	p.buttons[2].Render(u, window)
+	p.buttons[5].SetPosition(centerX+80, startY)
+	p.buttons[5].Render(u, window)
+
+	startY += 50
+
+	// 8. Pregenerate Button
+	p.buttons[3].SetPosition(centerX-100, startY)
+	p.buttons[3].Render(u, window)
+
+	startY += 50
+
+	// 9. Quit Button
+	p.buttons[4].SetPosition(centerX-100, startY)
+	p.buttons[4].Render(u, window)
+}
+
+// renderSlider draws slider s with its title above and value text to the
+// right, its left edge at x.
+func (p *PauseMenu) renderSlider(u *ui.UI, window *glfw.Window, s *widget.Slider, title, value string, x, y float32) {
+	titleW, _ := u.MeasureText(title, 0.4)
+	u.DrawText(title, x+s.W/2-titleW/2, y-15, 0.4, mgl32.Vec3{1, 1, 1})
+	s.X = x
+	s.Y = y
+	s.Render(u, window)
+	u.DrawText(value, x+s.W+10, y+15, 0.35, mgl32.Vec3{0.8, 0.8, 0.8})
 }
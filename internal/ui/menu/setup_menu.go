@@ -0,0 +1,123 @@
+package menu
+
+import (
+	"fmt"
+	"mini-mc/internal/config"
+	"mini-mc/internal/graphics/renderables/ui"
+	"mini-mc/internal/ui/widget"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// SetupMenu is the first-run page shown before the main menu when no
+// options file exists. Each row cycles a setting and applies it at once.
+type SetupMenu struct {
+	rows        []*widget.Button
+	doneButton  *widget.Button
+	preset      int
+	recommended int
+	shouldClose bool
+}
+
+// NewSetupMenu creates the page, starting on the recommended render preset.
+func NewSetupMenu(recommendedPreset int) *SetupMenu {
+	sm := &SetupMenu{preset: recommendedPreset, recommended: recommendedPreset}
+	config.SetRenderDistance(config.RenderPresets[sm.preset].Distance)
+
+	addRow := func(onClick func()) {
+		b := widget.NewButton("", 0, 0, 300, 40, onClick)
+		b.NormalColor = mgl32.Vec3{0.2, 0.2, 0.2}
+		b.HoverColor = mgl32.Vec3{0.3, 0.3, 0.3}
+		sm.rows = append(sm.rows, b)
+	}
+	addRow(func() {
+		cur := 0
+		for i, l := range config.Languages {
+			if l.Code == config.GetLanguage() {
+				cur = i
+			}
+		}
+		config.SetLanguage(config.Languages[(cur+1)%len(config.Languages)].Code)
+	})
+	addRow(func() {
+		next := config.GetGUIScale() + 1
+		if next > config.MaxGUIScale {
+			next = config.MinGUIScale
+		}
+		config.SetGUIScale(next)
+	})
+	addRow(func() {
+		sm.preset = (sm.preset + 1) % len(config.RenderPresets)
+		config.SetRenderDistance(config.RenderPresets[sm.preset].Distance)
+	})
+	addRow(func() {
+		config.SetKeyboardLayout((config.GetKeyboardLayout() + 1) % config.KeyboardLayoutCount)
+	})
+
+	sm.doneButton = widget.NewButton("Done", 0, 0, 200, 40, func() {
+		sm.shouldClose = true
+	})
+	sm.doneButton.NormalColor = mgl32.Vec3{0.2, 0.2, 0.2}
+	sm.doneButton.HoverColor = mgl32.Vec3{0.3, 0.3, 0.3}
+	return sm
+}
+
+// Update handles input and reports whether setup is finished.
+func (s *SetupMenu) Update(window *glfw.Window, justPressedLeft bool) bool {
+	s.shouldClose = false
+	for _, b := range s.rows {
+		b.HandleInput(window, justPressedLeft)
+	}
+	s.doneButton.HandleInput(window, justPressedLeft)
+	return s.shouldClose
+}
+
+func (s *SetupMenu) Render(u *ui.UI, window *glfw.Window) {
+	fWinW, fWinH := ui.LayoutSize(window)
+	u.DrawFilledRect(0, 0, fWinW, fWinH, mgl32.Vec3{0, 0, 0}, 0.5)
+
+	centerX := fWinW / 2
+
+	title := "WELCOME"
+	tw, _ := u.MeasureText(title, 1.0)
+	u.DrawText(title, centerX-tw/2, 80, 1.0, mgl32.Vec3{1, 1, 1})
+	sub := "Pick a few settings to start with. You can change them later."
+	sw, _ := u.MeasureText(sub, 0.35)
+	u.DrawText(sub, centerX-sw/2, 115, 0.35, mgl32.Vec3{0.8, 0.8, 0.8})
+
+	language := config.GetLanguage()
+	for _, l := range config.Languages {
+		if l.Code == language {
+			language = l.Name
+		}
+	}
+	languageHint := ""
+	if len(config.Languages) == 1 {
+		languageHint = "More languages are not available yet"
+	}
+	preset := config.RenderPresets[s.preset]
+	presetText := fmt.Sprintf("Render Distance: %s (%d chunks)", preset.Name, preset.Distance)
+	presetHint := ""
+	if s.preset == s.recommended {
+		presetHint = "Recommended for this computer"
+	}
+	layout := config.GetKeyboardLayout()
+
+	s.rows[0].Text, s.rows[0].Subtitle = "Language: "+language, languageHint
+	s.rows[1].Text = fmt.Sprintf("GUI Scale: %dx", config.GetGUIScale())
+	s.rows[2].Text, s.rows[2].Subtitle = presetText, presetHint
+	s.rows[3].Text = "Keyboard: " + layout.String()
+	s.rows[3].Subtitle = "Move with " + layout.MovementKeys()
+
+	startY := float32(150.0)
+	for _, b := range s.rows {
+		b.SetPosition(centerX-b.W/2, startY)
+		b.Render(u, window)
+		startY += 55
+	}
+
+	startY += 10
+	s.doneButton.SetPosition(centerX-100, startY)
+	s.doneButton.Render(u, window)
+}
@@ -9,4 +9,6 @@ const (
 	ActionResume
 	ActionQuitToMenu
 	ActionQuitGame
+	ActionTogglePregen // start or stop pregenerating the world around the player
+	ActionRespawn      // bring the dead player back at the spawn point
 )
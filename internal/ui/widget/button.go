@@ -1,7 +1,9 @@
 package widget
 
 import (
+	"mini-mc/internal/config"
 	"mini-mc/internal/graphics/renderables/ui"
+	"mini-mc/internal/sound"
 
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
@@ -33,8 +35,7 @@ func NewButton(text string, x, y, w, h float32, onClick func()) *Button {
 }
 
 func (b *Button) Render(u *ui.UI, window *glfw.Window) {
-	mx, my := window.GetCursorPos()
-	mx32, my32 := float32(mx), float32(my)
+	mx32, my32 := ui.CursorPos(window)
 
 	b.IsHovered = mx32 >= b.X && mx32 <= b.X+b.W && my32 >= b.Y && my32 <= b.Y+b.H
 
@@ -122,6 +123,7 @@ func (b *Button) Render(u *ui.UI, window *glfw.Window) {
 
 func (b *Button) HandleInput(window *glfw.Window, justPressedLeft bool) bool {
 	if b.IsHovered && justPressedLeft {
+		playClick()
 		if b.OnClick != nil {
 			b.OnClick()
 		}
@@ -129,3 +131,8 @@ func (b *Button) HandleInput(window *glfw.Window, justPressedLeft bool) bool {
 	}
 	return false
 }
+
+// playClick plays 1.8.9's button press sound on the UI bus.
+func playClick() {
+	sound.Play(sound.Event{Name: "gui.button.press", Volume: 0.25, Pitch: 1, Bus: config.AudioBusUI})
+}
@@ -13,6 +13,11 @@ type Slider struct {
 	ID       string
 	Label    string // For internal use or debugging
 	OnChange func(val float32)
+	// OnCommit is called with the final value when the slider is let go,
+	// for changes too costly to make on every step of a drag
+	OnCommit func(val float32)
+
+	dragging bool
 }
 
 func NewSlider(x, y, w, h float32, initialVal float32, steps int, id string, onChange func(val float32)) *Slider {
@@ -35,6 +40,12 @@ func (s *Slider) Render(u *ui.UI, window *glfw.Window) {
 			s.OnChange(s.Value)
 		}
 	}
+
+	active := u.SliderActive(s.ID)
+	if s.dragging && !active && s.OnCommit != nil {
+		s.OnCommit(s.Value)
+	}
+	s.dragging = active
 }
 
 func (s *Slider) HandleInput(window *glfw.Window, justPressedLeft bool) bool {
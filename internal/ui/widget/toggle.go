@@ -25,8 +25,7 @@ func NewToggle(label string, x, y, w, h float32, initial bool, onToggle func(isO
 }
 
 func (t *Toggle) Render(u *ui.UI, window *glfw.Window) {
-	mx, my := window.GetCursorPos()
-	mx32, my32 := float32(mx), float32(my)
+	mx32, my32 := ui.CursorPos(window)
 
 	t.IsHovered = mx32 >= t.X && mx32 <= t.X+t.W && my32 >= t.Y && my32 <= t.Y+t.H
 
@@ -54,6 +53,7 @@ func (t *Toggle) Render(u *ui.UI, window *glfw.Window) {
 
 func (t *Toggle) HandleInput(window *glfw.Window, justPressedLeft bool) bool {
 	if t.IsHovered && justPressedLeft {
+		playClick()
 		t.IsOn = !t.IsOn
 		if t.OnToggle != nil {
 			t.OnToggle(t.IsOn)
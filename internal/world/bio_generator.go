@@ -2,6 +2,8 @@ package world
 
 import (
 	"math"
+
+	"mini-mc/internal/mathutil"
 )
 
 // BioGenerator implements surface generation inspired by Minecraft 1.8.9 but with corrected scales.
@@ -55,17 +57,6 @@ func NewBioGenerator(seed int64) TerrainGenerator {
 	return g
 }
 
-// clamp helper
-func clamp(v, min, max float64) float64 {
-	if v < min {
-		return min
-	}
-	if v > max {
-		return max
-	}
-	return v
-}
-
 // denormalizeClamp similar to MC's MathHelper
 func denormalizeClamp(min, max, ratio float64) float64 {
 	if ratio < 0.0 {
@@ -150,7 +141,7 @@ func (g *BioGenerator) computeDensity(worldX, worldY, worldZ int) float64 {
 
 	// Interpolate
 	vol := (mainNoise/10.0 + 1.0) / 2.0
-	vol = clamp(vol, 0.0, 1.0)
+	vol = mathutil.Clamp(vol, 0.0, 1.0)
 
 	// Combine noise with height gradient
 	// density > 0 means solid
@@ -21,6 +21,8 @@ type Biome struct {
 	Rainfall    float64   // 0.0=dry, 1.0=wet
 	Trees       TreeType  // Tree type to place during decoration
 	TreeCount   uint8     // Trees attempted per chunk
+	GrassCount  uint8     // Tall grass patches per chunk
+	FlowerCount uint8     // Flower patches per chunk
 }
 
 // All biome definitions use MC 1.8.9 authentic MinHeight/MaxHeight parameters.
@@ -36,6 +38,7 @@ var (
 		MinHeight: 0.125, MaxHeight: 0.05,
 		TopBlock: BlockTypeGrass, FillerBlock: BlockTypeDirt,
 		Temperature: 0.8, Rainfall: 0.4,
+		GrassCount: 10, FlowerCount: 4,
 	}
 	BiomeDesert = &Biome{
 		ID: 2, Name: "Desert",
@@ -49,6 +52,7 @@ var (
 		TopBlock: BlockTypeGrass, FillerBlock: BlockTypeDirt,
 		Temperature: 0.2, Rainfall: 0.3,
 		Trees: TreeOak, TreeCount: 3,
+		GrassCount: 1, FlowerCount: 2,
 	}
 	BiomeForest = &Biome{
 		ID: 4, Name: "Forest",
@@ -56,6 +60,7 @@ var (
 		TopBlock: BlockTypeGrass, FillerBlock: BlockTypeDirt,
 		Temperature: 0.7, Rainfall: 0.8,
 		Trees: TreeOak, TreeCount: 10,
+		GrassCount: 2, FlowerCount: 4,
 	}
 	BiomeTaiga = &Biome{
 		ID: 5, Name: "Taiga",
@@ -63,6 +68,7 @@ var (
 		TopBlock: BlockTypeGrass, FillerBlock: BlockTypeDirt,
 		Temperature: 0.25, Rainfall: 0.8,
 		Trees: TreeSpruce, TreeCount: 10,
+		GrassCount: 7, FlowerCount: 2,
 	}
 	BiomeSwamp = &Biome{
 		ID: 6, Name: "Swampland",
@@ -70,12 +76,14 @@ var (
 		TopBlock: BlockTypeGrass, FillerBlock: BlockTypeDirt,
 		Temperature: 0.8, Rainfall: 0.9,
 		Trees: TreeOak, TreeCount: 2,
+		GrassCount: 5,
 	}
 	BiomeSavanna = &Biome{
 		ID: 35, Name: "Savanna",
 		MinHeight: 0.125, MaxHeight: 0.05,
 		TopBlock: BlockTypeGrass, FillerBlock: BlockTypeDirt,
 		Temperature: 1.2, Rainfall: 0.0,
+		GrassCount: 20, FlowerCount: 4,
 	}
 	BiomeJungle = &Biome{
 		ID: 21, Name: "Jungle",
@@ -83,6 +91,7 @@ var (
 		TopBlock: BlockTypeGrass, FillerBlock: BlockTypeDirt,
 		Temperature: 0.95, Rainfall: 0.9,
 		Trees: TreeOak, TreeCount: 50,
+		GrassCount: 25, FlowerCount: 4,
 	}
 	BiomeBirchForest = &Biome{
 		ID: 27, Name: "Birch Forest",
@@ -90,6 +99,7 @@ var (
 		TopBlock: BlockTypeGrass, FillerBlock: BlockTypeDirt,
 		Temperature: 0.6, Rainfall: 0.6,
 		Trees: TreeOak, TreeCount: 10,
+		GrassCount: 2, FlowerCount: 4,
 	}
 	BiomeForestHills = &Biome{
 		ID: 18, Name: "Forest Hills",
@@ -97,6 +107,7 @@ var (
 		TopBlock: BlockTypeGrass, FillerBlock: BlockTypeDirt,
 		Temperature: 0.7, Rainfall: 0.8,
 		Trees: TreeOak, TreeCount: 10,
+		GrassCount: 2, FlowerCount: 4,
 	}
 	BiomeTaigaHills = &Biome{
 		ID: 19, Name: "Taiga Hills",
@@ -104,6 +115,7 @@ var (
 		TopBlock: BlockTypeGrass, FillerBlock: BlockTypeDirt,
 		Temperature: 0.25, Rainfall: 0.8,
 		Trees: TreeSpruce, TreeCount: 10,
+		GrassCount: 7, FlowerCount: 2,
 	}
 	BiomeColdTaiga = &Biome{
 		ID: 30, Name: "Cold Taiga",
@@ -111,12 +123,14 @@ var (
 		TopBlock: BlockTypeGrass, FillerBlock: BlockTypeDirt,
 		Temperature: -0.5, Rainfall: 0.4,
 		Trees: TreeSpruce, TreeCount: 10,
+		GrassCount: 7, FlowerCount: 2,
 	}
 	BiomeIcePlains = &Biome{
 		ID: 12, Name: "Ice Plains",
 		MinHeight: 0.125, MaxHeight: 0.05,
 		TopBlock: BlockTypeGrass, FillerBlock: BlockTypeDirt,
 		Temperature: 0.0, Rainfall: 0.5,
+		GrassCount: 1, FlowerCount: 2,
 	}
 	BiomeDeepOcean = &Biome{
 		ID: 24, Name: "Deep Ocean",
@@ -0,0 +1,137 @@
+package world
+
+// BiomeBlendRadius is how far, in blocks, grass, foliage and water colours
+// are averaged across, so biome borders fade over a few blocks instead of
+// showing a seam.
+const BiomeBlendRadius = 4
+
+// Corners of 1.8.9's grass and foliage colour maps, which are triangles:
+// hot and wet, hot and dry, and cold. Colours are 0xRRGGBB.
+var (
+	grassColorCorners   = [3]uint32{0x47CD33, 0xBFB755, 0x80B497}
+	foliageColorCorners = [3]uint32{0x1ABF00, 0xAEA42A, 0x60A17B}
+)
+
+// Swamps override the colour maps, as in 1.8.9.
+const (
+	swampGrassColor = 0x6A7039
+	swampWaterColor = 0xE0FFAE
+	plainWaterColor = 0xFFFFFF // water's texture is already blue
+)
+
+// colorMap looks up a biome's climate in a colour map given by its corners.
+func colorMap(corners [3]uint32, temperature, rainfall float64) uint32 {
+	t := min(max(temperature, 0), 1)
+	r := min(max(rainfall, 0), 1) * t
+	weights := [3]float64{r, t - r, 1 - t}
+	var out uint32
+	for shift := 0; shift <= 16; shift += 8 {
+		var v float64
+		for i, c := range corners {
+			v += weights[i] * float64(c>>shift&0xFF)
+		}
+		out |= uint32(v+0.5) << shift
+	}
+	return out
+}
+
+// GrassColor returns the colour grass is tinted in the biome.
+func (b *Biome) GrassColor() uint32 {
+	if b == BiomeSwamp {
+		return swampGrassColor
+	}
+	return colorMap(grassColorCorners, b.Temperature, b.Rainfall)
+}
+
+// FoliageColor returns the colour leaves are tinted in the biome.
+func (b *Biome) FoliageColor() uint32 {
+	if b == BiomeSwamp {
+		return swampGrassColor
+	}
+	return colorMap(foliageColorCorners, b.Temperature, b.Rainfall)
+}
+
+// WaterColor returns the colour water is tinted in the biome.
+func (b *Biome) WaterColor() uint32 {
+	if b == BiomeSwamp {
+		return swampWaterColor
+	}
+	return plainWaterColor
+}
+
+// BiomeColors holds a chunk's biome colours per column, blended across
+// BiomeBlendRadius, indexed x*ChunkSizeZ+z.
+type BiomeColors struct {
+	Grass   [ChunkSizeX * ChunkSizeZ]uint32
+	Foliage [ChunkSizeX * ChunkSizeZ]uint32
+	Water   [ChunkSizeX * ChunkSizeZ]uint32
+}
+
+// BiomeColors returns the blended biome colours of c, working them out the
+// first time they are asked for.
+func (w *World) BiomeColors(c *Chunk) *BiomeColors {
+	if c.origin != nil {
+		// Kept on the live chunk, so each snapshot does not work them out again
+		return w.BiomeColors(c.origin)
+	}
+	if bc := c.biomeColors.Load(); bc != nil {
+		return bc
+	}
+	bc := blendBiomeColors(w.seed, c.X, c.Z)
+	c.biomeColors.Store(bc)
+	return bc
+}
+
+// blendBiomeColors averages each colour over the square of columns within
+// BiomeBlendRadius of every column in chunk (chunkX, chunkZ).
+func blendBiomeColors(seed int64, chunkX, chunkZ int) *BiomeColors {
+	const (
+		r    = BiomeBlendRadius
+		size = ChunkSizeX + 2*r // sampled columns along each side
+		area = (2*r + 1) * (2*r + 1)
+	)
+	var grass, foliage, water [size][size]uint32
+	x0, z0 := chunkX*ChunkSizeX-r, chunkZ*ChunkSizeZ-r
+	for i := range size {
+		for j := range size {
+			b := GetBiomeForCoords(float64(x0+i), float64(z0+j), seed)
+			grass[i][j] = b.GrassColor()
+			foliage[i][j] = b.FoliageColor()
+			water[i][j] = b.WaterColor()
+		}
+	}
+
+	bc := &BiomeColors{}
+	blend := func(src *[size][size]uint32, dst *[ChunkSizeX * ChunkSizeZ]uint32) {
+		// Sum along z, then along x
+		var rows [size][ChunkSizeZ][3]uint32
+		for i := range size {
+			for z := range ChunkSizeZ {
+				for j := z; j <= z+2*r; j++ {
+					addRGB(&rows[i][z], src[i][j])
+				}
+			}
+		}
+		for x := range ChunkSizeX {
+			for z := range ChunkSizeZ {
+				var sum [3]uint32
+				for i := x; i <= x+2*r; i++ {
+					for ch := range sum {
+						sum[ch] += rows[i][z][ch]
+					}
+				}
+				dst[x*ChunkSizeZ+z] = (sum[0]+area/2)/area<<16 | (sum[1]+area/2)/area<<8 | (sum[2]+area/2)/area
+			}
+		}
+	}
+	blend(&grass, &bc.Grass)
+	blend(&foliage, &bc.Foliage)
+	blend(&water, &bc.Water)
+	return bc
+}
+
+func addRGB(sum *[3]uint32, c uint32) {
+	sum[0] += c >> 16 & 0xFF
+	sum[1] += c >> 8 & 0xFF
+	sum[2] += c & 0xFF
+}
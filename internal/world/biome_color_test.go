@@ -0,0 +1,55 @@
+package world
+
+import "testing"
+
+func TestBiomeColorMap(t *testing.T) {
+	// 1.8.9's plains grass is 0x91BD59; the corner interpolation lands within
+	// a few steps of it
+	got := BiomePlains.GrassColor()
+	want := uint32(0x91BD59)
+	for shift := 0; shift <= 16; shift += 8 {
+		d := int(got>>shift&0xFF) - int(want>>shift&0xFF)
+		if d < -6 || d > 6 {
+			t.Errorf("plains grass = %06X, want about %06X", got, want)
+			break
+		}
+	}
+	if BiomeSwamp.WaterColor() != swampWaterColor || BiomePlains.WaterColor() != plainWaterColor {
+		t.Error("only swamp water should be tinted")
+	}
+}
+
+func TestBiomeColorsBlendAcrossBorders(t *testing.T) {
+	const seed = 12345
+	// Walk along x until the grass colour changes within a chunk, which
+	// means a biome border runs through it
+	for cx := 0; cx < 400; cx++ {
+		bc := blendBiomeColors(seed, cx, 0)
+		next := blendBiomeColors(seed, cx+1, 0)
+		if bc.Grass[0] == bc.Grass[(ChunkSizeX-1)*ChunkSizeZ] {
+			continue
+		}
+
+		// One column further moves a single row in or out of the
+		// averaged square, so no channel can jump by more than a row's
+		// share of the full range
+		maxStep := 255/(2*BiomeBlendRadius+1) + 1
+		row := func(x int) uint32 {
+			if x < ChunkSizeX {
+				return bc.Grass[x*ChunkSizeZ]
+			}
+			return next.Grass[(x-ChunkSizeX)*ChunkSizeZ]
+		}
+		for x := 0; x+1 < 2*ChunkSizeX; x++ {
+			a, b := row(x), row(x+1)
+			for shift := 0; shift <= 16; shift += 8 {
+				d := int(a>>shift&0xFF) - int(b>>shift&0xFF)
+				if d < -maxStep || d > maxStep {
+					t.Fatalf("chunk %d: grass jumps from %06X to %06X at x=%d", cx, a, b, x)
+				}
+			}
+		}
+		return
+	}
+	t.Skip("no biome border found")
+}
@@ -23,6 +23,29 @@ const (
 	BlockTypeOakLeaves
 	BlockTypeSpruceLog
 	BlockTypeSpruceLeaves
+	BlockTypeSnowLayer
+	BlockTypeFurnace
+	BlockTypeLitFurnace
+	BlockTypeItemFrame
+	BlockTypeRail
+
+	// Tools share the BlockType space so they fit in item stacks; they are
+	// never placed in the world.
+	BlockTypeWoodenPickaxe
+	BlockTypeStonePickaxe
+	BlockTypeIronPickaxe
+
+	// Vehicles are placed as entities, not blocks.
+	BlockTypeBoat
+	BlockTypeMinecart
+
+	// Later blocks go at the end so saved chunks keep their block IDs.
+	BlockTypeGlass
+	BlockTypeTallGrass
+	BlockTypeDandelion
+	BlockTypePoppy
+	BlockTypeCraftingTable
+	BlockTypeStick
 )
 
 // BlockSolidTable is a flat lookup indexed by BlockType (uint8).
@@ -35,6 +58,15 @@ var BlockSolidTable [256]bool
 // true = block is a fluid (water or lava). Useful for fast checks in hot paths.
 var BlockFluidTable [256]bool
 
+// BlockLightEmission is the block light each BlockType gives off, 0-15.
+// Populated by the registry like BlockSolidTable.
+var BlockLightEmission [256]uint8
+
+// BlockLightOpacity is how much light each BlockType takes away from light
+// passing through it, 0-15; 15 blocks it completely. Populated by the
+// registry like BlockSolidTable.
+var BlockLightOpacity [256]uint8
+
 // BlockFace identifies a face of a block
 type BlockFace int
 
@@ -46,3 +78,8 @@ const (
 	FaceTop
 	FaceBottom
 )
+
+// Opposite returns the face on the other side of the block.
+func (f BlockFace) Opposite() BlockFace {
+	return f ^ 1
+}
@@ -0,0 +1,136 @@
+package world
+
+import "sync"
+
+// BlockEntity holds extra state for a single block position, such as a
+// furnace's slots and burn progress. Implementations live outside the world
+// package (items would create an import cycle) and are stored by position.
+//
+// In a world that is never saved, block entities are kept when their chunk
+// unloads and keep ticking; when the chunk is generated again their blocks
+// are put back. Saved worlds save them with the chunk instead (see
+// block_entity_save.go).
+type BlockEntity interface {
+	// Tick advances the entity by one game tick.
+	Tick()
+	// Block returns the block type and metadata the entity's position should
+	// hold, e.g. a lit furnace while it is burning fuel.
+	Block() (BlockType, uint8)
+}
+
+type blockEntityEntry struct {
+	entity BlockEntity
+	block  BlockType
+	meta   uint8
+}
+
+// blockEntityStore is guarded by a mutex because chunk generation workers
+// restore blocks from it while the main thread ticks it.
+type blockEntityStore struct {
+	mu      sync.RWMutex
+	entries map[BlockPos]*blockEntityEntry
+	changed []BlockPos // reused by tick
+}
+
+func newBlockEntityStore() *blockEntityStore {
+	return &blockEntityStore{entries: make(map[BlockPos]*blockEntityEntry)}
+}
+
+// SetBlockEntity attaches be to the block at (x, y, z), replacing any existing entity.
+func (w *World) SetBlockEntity(x, y, z int, be BlockEntity) {
+	bt, meta := be.Block()
+	w.blockEntities.mu.Lock()
+	w.blockEntities.entries[BlockPos{X: x, Y: y, Z: z}] = &blockEntityEntry{entity: be, block: bt, meta: meta}
+	w.blockEntities.mu.Unlock()
+}
+
+// BlockEntityAt returns the entity attached to the block at (x, y, z), or nil.
+func (w *World) BlockEntityAt(x, y, z int) BlockEntity {
+	w.blockEntities.mu.RLock()
+	defer w.blockEntities.mu.RUnlock()
+	if e, ok := w.blockEntities.entries[BlockPos{X: x, Y: y, Z: z}]; ok {
+		return e.entity
+	}
+	return nil
+}
+
+// RemoveBlockEntity detaches and returns the entity at (x, y, z), or nil if there was none.
+func (w *World) RemoveBlockEntity(x, y, z int) BlockEntity {
+	pos := BlockPos{X: x, Y: y, Z: z}
+	w.blockEntities.mu.Lock()
+	defer w.blockEntities.mu.Unlock()
+	e, ok := w.blockEntities.entries[pos]
+	if !ok {
+		return nil
+	}
+	delete(w.blockEntities.entries, pos)
+	return e.entity
+}
+
+// BlockEntityRef pairs a block entity with its position.
+type BlockEntityRef struct {
+	Pos    BlockPos
+	Entity BlockEntity
+}
+
+// AppendBlockEntitiesInRadius appends the block entities within radius blocks
+// of (x, y, z) to dst.
+func (w *World) AppendBlockEntitiesInRadius(x, y, z, radius float32, dst []BlockEntityRef) []BlockEntityRef {
+	r2 := radius * radius
+	w.blockEntities.mu.RLock()
+	defer w.blockEntities.mu.RUnlock()
+	for pos, e := range w.blockEntities.entries {
+		dx := float32(pos.X) + 0.5 - x
+		dy := float32(pos.Y) + 0.5 - y
+		dz := float32(pos.Z) + 0.5 - z
+		if dx*dx+dy*dy+dz*dz <= r2 {
+			dst = append(dst, BlockEntityRef{Pos: pos, Entity: e.entity})
+		}
+	}
+	return dst
+}
+
+// tickBlockEntities ticks every block entity, loaded or not, and updates the
+// blocks of loaded ones whose state changed (e.g. a furnace lighting up).
+func (w *World) tickBlockEntities() {
+	s := w.blockEntities
+	s.mu.Lock()
+	s.changed = s.changed[:0]
+	for pos, e := range s.entries {
+		e.entity.Tick()
+		bt, meta := e.entity.Block()
+		if bt != e.block || meta != e.meta {
+			e.block, e.meta = bt, meta
+			s.changed = append(s.changed, pos)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, pos := range s.changed {
+		if w.GetChunkFromBlockCoords(pos.X, pos.Y, pos.Z, false) == nil {
+			continue
+		}
+		s.mu.RLock()
+		e, ok := s.entries[pos]
+		s.mu.RUnlock()
+		if ok {
+			w.SetWithMeta(pos.X, pos.Y, pos.Z, e.block, e.meta)
+		}
+	}
+}
+
+// restoreBlockEntities puts back the blocks of entities inside a freshly
+// generated chunk. Called from generation workers before the chunk is added.
+func (s *blockEntityStore) restoreBlockEntities(c *Chunk) {
+	baseX, baseY, baseZ := c.X*ChunkSizeX, c.Y*ChunkSizeY, c.Z*ChunkSizeZ
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for pos, e := range s.entries {
+		lx, ly, lz := pos.X-baseX, pos.Y-baseY, pos.Z-baseZ
+		if lx < 0 || lx >= ChunkSizeX || ly < 0 || ly >= ChunkSizeY || lz < 0 || lz >= ChunkSizeZ {
+			continue
+		}
+		c.SetBlock(lx, ly, lz, e.block)
+		c.SetMeta(lx, ly, lz, e.meta)
+	}
+}
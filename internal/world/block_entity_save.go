@@ -0,0 +1,197 @@
+package world
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// Block entities of a saved world are saved with their chunk, like entities
+// (see entity_save.go). When the chunk unloads they leave the world and are
+// encoded into it; autosaves encode them without removing them. A saved
+// chunk's block entities are put back as it loads, before it is in the
+// store, unless the world still holds one at the position.
+
+// BlockEntityTypeID names a kind of block entity in saved chunks.
+type BlockEntityTypeID string
+
+// BlockEntityType says how to load the block entities of one kind. The
+// blockentity package registers its types with RegisterBlockEntityType.
+type BlockEntityType struct {
+	ID BlockEntityTypeID
+	// Load makes a block entity from what its SaveBlockEntity returned.
+	Load func(data []byte) (BlockEntity, error)
+}
+
+// SavedBlockEntity is a block entity saved with its chunk. Block entities
+// that do not implement it are kept in the world when their chunk unloads.
+type SavedBlockEntity interface {
+	BlockEntity
+	BlockEntityType() BlockEntityTypeID
+	// SaveBlockEntity returns the entity's state for its type's Load.
+	SaveBlockEntity() []byte
+}
+
+var blockEntityTypes = struct {
+	sync.RWMutex
+	byID map[BlockEntityTypeID]BlockEntityType
+}{byID: make(map[BlockEntityTypeID]BlockEntityType)}
+
+// RegisterBlockEntityType makes t known by its ID. Registering an ID twice
+// panics.
+func RegisterBlockEntityType(t BlockEntityType) {
+	blockEntityTypes.Lock()
+	defer blockEntityTypes.Unlock()
+	if _, ok := blockEntityTypes.byID[t.ID]; ok {
+		panic(fmt.Sprintf("block entity type %q registered twice", t.ID))
+	}
+	blockEntityTypes.byID[t.ID] = t
+}
+
+// LookupBlockEntityType returns the type registered as id.
+func LookupBlockEntityType(id BlockEntityTypeID) (BlockEntityType, bool) {
+	blockEntityTypes.RLock()
+	defer blockEntityTypes.RUnlock()
+	t, ok := blockEntityTypes.byID[id]
+	return t, ok
+}
+
+// localBlockIndex returns pos as an index into c, or -1 if c does not hold
+// it.
+func localBlockIndex(c *Chunk, pos BlockPos) int {
+	lx, ly, lz := pos.X-c.X*ChunkSizeX, pos.Y-c.Y*ChunkSizeY, pos.Z-c.Z*ChunkSizeZ
+	if lx < 0 || lx >= ChunkSizeX || ly < 0 || ly >= ChunkSizeY || lz < 0 || lz >= ChunkSizeZ {
+		return -1
+	}
+	return (ly*ChunkSizeZ+lz)*ChunkSizeX + lx
+}
+
+// stashBlockEntities encodes the saved block entities in c into it for the
+// next save, marking c modified when they changed. With take they are also
+// removed from the world, as c is unloading.
+func (w *World) stashBlockEntities(c *Chunk, take bool) {
+	type saved struct {
+		index  int
+		pos    BlockPos
+		entity SavedBlockEntity
+	}
+	var list []saved
+	s := w.blockEntities
+	s.mu.RLock()
+	for pos, e := range s.entries {
+		be, ok := e.entity.(SavedBlockEntity)
+		if !ok {
+			continue
+		}
+		if i := localBlockIndex(c, pos); i >= 0 {
+			list = append(list, saved{i, pos, be})
+		}
+	}
+	s.mu.RUnlock()
+	// Sorted, so an unchanged chunk encodes the same record
+	slices.SortFunc(list, func(a, b saved) int { return a.index - b.index })
+
+	var record []byte
+	if len(list) > 0 {
+		record = binary.AppendUvarint(nil, uint64(len(list)))
+		for _, e := range list {
+			data := e.entity.SaveBlockEntity()
+			record = binary.AppendUvarint(record, uint64(e.index))
+			record = binary.AppendUvarint(record, uint64(len(e.entity.BlockEntityType())))
+			record = append(record, e.entity.BlockEntityType()...)
+			record = binary.AppendUvarint(record, uint64(len(data)))
+			record = append(record, data...)
+		}
+	}
+	if !bytes.Equal(record, c.blockEntities) {
+		c.blockEntities = record
+		c.modified = true
+	}
+	if take {
+		s.mu.Lock()
+		for _, e := range list {
+			delete(s.entries, e.pos)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// loadBlockEntities puts the block entities saved in a loading chunk back in
+// the world. Called from generation workers.
+func (w *World) loadBlockEntities(c *Chunk) {
+	list, err := decodeBlockEntities(c)
+	if err != nil {
+		slog.Warn("loading chunk block entities failed", "chunk", ChunkCoord{X: c.X, Y: c.Y, Z: c.Z}, "err", err)
+	}
+	s := w.blockEntities
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ref := range list {
+		if _, held := s.entries[ref.Pos]; held {
+			continue
+		}
+		bt, meta := ref.Entity.Block()
+		s.entries[ref.Pos] = &blockEntityEntry{entity: ref.Entity, block: bt, meta: meta}
+	}
+}
+
+var errBlockEntityRecord = errors.New("corrupt block entity record")
+
+// decodeBlockEntities makes the block entities of c's record, written by
+// stashBlockEntities. Those of unknown types or that fail to load are
+// skipped with a warning.
+func decodeBlockEntities(c *Chunk) ([]BlockEntityRef, error) {
+	record := c.blockEntities
+	next := func() ([]byte, error) {
+		n, k := binary.Uvarint(record)
+		if k <= 0 || n > uint64(len(record)-k) {
+			return nil, errBlockEntityRecord
+		}
+		field := record[k : k+int(n)]
+		record = record[k+int(n):]
+		return field, nil
+	}
+	count, k := binary.Uvarint(record)
+	if k <= 0 {
+		return nil, errBlockEntityRecord
+	}
+	record = record[k:]
+	var list []BlockEntityRef
+	for range count {
+		index, k := binary.Uvarint(record)
+		if k <= 0 || index >= ChunkSizeX*ChunkSizeY*ChunkSizeZ {
+			return list, errBlockEntityRecord
+		}
+		record = record[k:]
+		id, err := next()
+		if err != nil {
+			return list, err
+		}
+		data, err := next()
+		if err != nil {
+			return list, err
+		}
+		t, ok := LookupBlockEntityType(BlockEntityTypeID(id))
+		if !ok || t.Load == nil {
+			slog.Warn("dropping saved block entity of unknown type", "type", string(id))
+			continue
+		}
+		be, err := t.Load(data)
+		if err != nil {
+			slog.Warn("dropping saved block entity", "type", string(id), "err", err)
+			continue
+		}
+		i := int(index)
+		pos := BlockPos{
+			X: c.X*ChunkSizeX + i%ChunkSizeX,
+			Y: c.Y*ChunkSizeY + i/(ChunkSizeX*ChunkSizeZ),
+			Z: c.Z*ChunkSizeZ + i/ChunkSizeX%ChunkSizeZ,
+		}
+		list = append(list, BlockEntityRef{Pos: pos, Entity: be})
+	}
+	return list, nil
+}
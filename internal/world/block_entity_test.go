@@ -0,0 +1,39 @@
+package world
+
+import "testing"
+
+type testBlockEntity struct{ ticks int }
+
+func (e *testBlockEntity) Tick() { e.ticks++ }
+
+func (e *testBlockEntity) Block() (BlockType, uint8) {
+	if e.ticks > 0 {
+		return BlockTypeLitFurnace, uint8(FaceEast)
+	}
+	return BlockTypeFurnace, uint8(FaceEast)
+}
+
+func TestBlockEntitySurvivesChunkUnload(t *testing.T) {
+	w := New()
+	defer w.Close()
+
+	be := &testBlockEntity{}
+	w.SetBlockEntity(5, 200, 5, be)
+
+	// Generating the chunk puts the entity's block back
+	w.StreamChunksAroundSync(0, 0, 1)
+	if got := w.Get(5, 200, 5); got != BlockTypeFurnace {
+		t.Fatalf("block after generation = %v, want furnace", got)
+	}
+
+	// Unloaded entities keep ticking, and come back in their new state
+	w.EvictFarChunks(10000, 10000, 1)
+	w.Tick()
+	w.StreamChunksAroundSync(0, 0, 1)
+	if got, meta := w.Get(5, 200, 5), w.GetMeta(5, 200, 5); got != BlockTypeLitFurnace || meta != uint8(FaceEast) {
+		t.Fatalf("block after reload = %v (meta %d), want lit furnace facing east", got, meta)
+	}
+	if w.BlockEntityAt(5, 200, 5) != be || be.ticks != 1 {
+		t.Fatal("block entity was lost or not ticked while unloaded")
+	}
+}
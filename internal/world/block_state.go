@@ -0,0 +1,62 @@
+package world
+
+import "mini-mc/internal/mathutil"
+
+// BlockState is a block type together with its metadata: the per-block bits
+// that hold a variant or orientation, such as a furnace's facing, a rail's
+// shape, a fluid's level or a snow layer's depth. What the bits mean is up
+// to each block type; blocks without states keep Meta at 0.
+type BlockState struct {
+	Type BlockType
+	Meta uint8
+}
+
+// StateAir is the state of an empty block.
+var StateAir = BlockState{}
+
+// IsAir reports whether the state is an empty block.
+func (s BlockState) IsAir() bool {
+	return s.Type == BlockTypeAir
+}
+
+// Facing returns the horizontal face the block's front points to, for
+// blocks that store a facing in their metadata.
+func (s BlockState) Facing() BlockFace {
+	return BlockFace(s.Meta)
+}
+
+// GetState returns the block state at the specified local coordinates.
+func (c *Chunk) GetState(x, y, z int) BlockState {
+	return BlockState{Type: c.GetBlock(x, y, z), Meta: c.GetMeta(x, y, z)}
+}
+
+// SetState sets the block type and metadata at the specified local
+// coordinates.
+func (c *Chunk) SetState(x, y, z int, s BlockState) {
+	c.SetBlock(x, y, z, s.Type)
+	c.SetMeta(x, y, z, s.Meta)
+}
+
+// GetState returns the block state at the specified world coordinates.
+func (cs *ChunkStore) GetState(x, y, z int) BlockState {
+	chunk := cs.GetChunkFromBlockCoords(x, y, z, false)
+	if chunk == nil {
+		return StateAir
+	}
+	return chunk.GetState(mathutil.Mod(x, ChunkSizeX), mathutil.Mod(y, ChunkSizeY), mathutil.Mod(z, ChunkSizeZ))
+}
+
+// SetState sets the block state at the specified world coordinates.
+func (cs *ChunkStore) SetState(x, y, z int, s BlockState) {
+	cs.SetWithMeta(x, y, z, s.Type, s.Meta)
+}
+
+// GetState returns the block state at the specified world coordinates
+func (w *World) GetState(x, y, z int) BlockState {
+	return w.store.GetState(x, y, z)
+}
+
+// SetState sets the block state at the specified world coordinates
+func (w *World) SetState(x, y, z int, s BlockState) {
+	w.store.SetState(x, y, z, s)
+}
@@ -0,0 +1,24 @@
+package world
+
+import "testing"
+
+func TestBlockStateRoundTrip(t *testing.T) {
+	store := NewChunkStore()
+	furnace := BlockState{Type: BlockTypeFurnace, Meta: uint8(FaceEast)}
+	store.SetState(-3, 64, 17, furnace)
+	if got := store.GetState(-3, 64, 17); got != furnace || got.Facing() != FaceEast {
+		t.Fatalf("state = %+v, want %+v", got, furnace)
+	}
+	if c := store.GetChunkFromBlockCoords(-3, 64, 17, false); !c.Modified() {
+		t.Error("setting a state did not mark the chunk modified")
+	}
+
+	// Breaking the block clears its metadata with it
+	store.Set(-3, 64, 17, BlockTypeAir)
+	if got := store.GetState(-3, 64, 17); got != StateAir || !got.IsAir() {
+		t.Errorf("state after breaking = %+v", got)
+	}
+	if got := store.GetState(1000, 64, 1000); got != StateAir {
+		t.Errorf("state in an unloaded chunk = %+v", got)
+	}
+}
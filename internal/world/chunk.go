@@ -1,6 +1,7 @@
 package world
 
 import (
+	"sync/atomic"
 	"unsafe"
 
 	"github.com/go-gl/mathgl/mgl32"
@@ -18,20 +19,35 @@ const (
 	SectionVolume = ChunkSizeX * SectionHeight * ChunkSizeZ
 )
 
-// Section represents a 16x16x16 sub-volume of a chunk
-type Section struct {
-	blocks   []BlockType
-	basePtr  unsafe.Pointer // &blocks[0] tutuluyor (nil slice durumunda nil kalır)
-	metadata []uint8
-	metaPtr  unsafe.Pointer // &metadata[0] tutuluyor; nil → tüm metadata sıfır (kaynak su gibi)
-}
-
 // Chunk represents a 16x256x16 section of the world
 type Chunk struct {
 	X, Y, Z    int
 	sections   [NumSections]*Section
 	dirty      bool
 	generation uint64 // incremented on each block change; used to detect stale mesh jobs
+
+	modified bool   // edited through the world since it was generated or loaded
+	genHash  uint64 // ContentHash of the pure generator output
+
+	biomeColors atomic.Pointer[BiomeColors] // worked out when first meshed
+
+	// Per-section light (see light.go); a nil section is lit lightFill
+	// throughout
+	light       [NumSections]atomic.Pointer[[SectionVolume]uint8]
+	lightFill   [NumSections]uint8
+	lightShared [NumSections]atomic.Bool // light array held by a snapshot too (see snapshot.go)
+
+	origin *Chunk // for snapshots, the live chunk it was taken of
+
+	// Entities saved with the chunk (see entity_save.go): the record it was
+	// loaded or last persisted with, and whether the loaded entities still
+	// wait to be spawned
+	entities        []byte
+	entitiesPending bool
+
+	// The block entities record it was loaded or last persisted with (see
+	// block_entity_save.go)
+	blockEntities []byte
 }
 
 // Generation returns the current generation counter.
@@ -39,14 +55,25 @@ func (c *Chunk) Generation() uint64 {
 	return c.generation
 }
 
+// Modified returns whether the chunk was edited through the world since it
+// was generated or loaded. Writes made by the generator do not count.
+func (c *Chunk) Modified() bool {
+	return c.modified
+}
+
 // NewChunk creates a new chunk at the specified chunk coordinates
 func NewChunk(x, y, z int) *Chunk {
-	return &Chunk{
+	c := &Chunk{
 		X:     x,
 		Y:     y,
 		Z:     z,
 		dirty: true,
 	}
+	// Unlit until initLight; open sky keeps unlit chunks looking as before
+	for i := range c.lightFill {
+		c.lightFill[i] = fullSky
+	}
+	return c
 }
 
 // indexInSection converts local section coordinates (x, localY, z) → flat index
@@ -60,17 +87,11 @@ func (c *Chunk) GetBlock(x, y, z int) BlockType {
 		return BlockTypeAir
 	}
 
-	secIdx := y / SectionHeight
-	sec := c.sections[secIdx]
-	if sec == nil || sec.basePtr == nil {
+	sec := c.sections[y/SectionHeight]
+	if sec == nil {
 		return BlockTypeAir
 	}
-
-	localY := y % SectionHeight
-	idx := indexInSection(x, localY, z)
-
-	blockPtr := (*BlockType)(unsafe.Pointer(uintptr(sec.basePtr) + uintptr(idx)*unsafe.Sizeof(BlockType(0))))
-	return *blockPtr
+	return sec.blocks.Load().get(indexInSection(x, y%SectionHeight, z))
 }
 
 // SetBlock sets the block type at the specified local coordinates
@@ -86,61 +107,47 @@ func (c *Chunk) SetBlock(x, y, z int, blockType BlockType) {
 	sec := c.sections[secIdx]
 
 	if blockType == BlockTypeAir {
-		if sec != nil && sec.basePtr != nil {
-			blockPtr := (*BlockType)(unsafe.Pointer(uintptr(sec.basePtr) + uintptr(idx)*unsafe.Sizeof(BlockType(0))))
-			old := *blockPtr
-
-			if old != BlockTypeAir {
-				*blockPtr = BlockTypeAir
-				c.dirty = true
-				c.generation++
-
-				// Blok air yapılırken o pozisyondaki metadata'yı da temizle
-				if sec.metaPtr != nil {
-					metaPtr := (*uint8)(unsafe.Pointer(uintptr(sec.metaPtr) + uintptr(idx)))
-					*metaPtr = 0
-					// Tüm metadata sıfır olduysa diziyi serbest bırak
-					allZero := true
-					for _, v := range sec.metadata {
-						if v != 0 {
-							allZero = false
-							break
-						}
-					}
-					if allZero {
-						sec.metadata = nil
-						sec.metaPtr = nil
-					}
-				}
+		if sec == nil {
+			return
+		}
+		if sec.blocks.Load().get(idx) == BlockTypeAir {
+			return
+		}
+		sec.own()
+		blocks := sec.blocks.Load()
+		sec.blocks.Store(blocks.set(idx, BlockTypeAir))
+		c.dirty = true
+		c.generation++
 
-				if len(sec.blocks) <= 0 {
-					sec.blocks = nil
-					sec.basePtr = nil
-					sec.metadata = nil
-					sec.metaPtr = nil
-					c.sections[secIdx] = nil
+		// Blok air yapılırken o pozisyondaki metadata'yı da temizle
+		if sec.metaPtr != nil {
+			metaPtr := (*uint8)(unsafe.Pointer(uintptr(sec.metaPtr) + uintptr(idx)))
+			*metaPtr = 0
+			// Tüm metadata sıfır olduysa diziyi serbest bırak
+			allZero := true
+			for _, v := range sec.metadata {
+				if v != 0 {
+					allZero = false
+					break
 				}
 			}
+			if allZero {
+				sec.metadata = nil
+				sec.metaPtr = nil
+			}
 		}
 		return
 	}
 
 	// non-air blok → section yoksa oluştur
 	if sec == nil {
-		sec = &Section{}
+		sec = newSection()
 		c.sections[secIdx] = sec
 	}
 
-	if sec.blocks == nil {
-		sec.blocks = make([]BlockType, SectionVolume)
-		sec.basePtr = unsafe.Pointer(&sec.blocks[0])
-	}
-
-	blockPtr := (*BlockType)(unsafe.Pointer(uintptr(sec.basePtr) + uintptr(idx)*unsafe.Sizeof(BlockType(0))))
-	old := *blockPtr
-
-	if old != blockType {
-		*blockPtr = blockType
+	if sec.blocks.Load().get(idx) != blockType {
+		sec.own()
+		sec.blocks.Store(sec.blocks.Load().set(idx, blockType))
 		c.dirty = true
 		c.generation++
 	}
@@ -186,6 +193,7 @@ func (c *Chunk) SetMeta(x, y, z int, meta uint8) {
 		if sec == nil || sec.metaPtr == nil {
 			return
 		}
+		sec.own()
 		metaPtr := (*uint8)(unsafe.Pointer(uintptr(sec.metaPtr) + uintptr(idx)))
 		*metaPtr = 0
 
@@ -207,9 +215,10 @@ func (c *Chunk) SetMeta(x, y, z int, meta uint8) {
 
 	// Sıfır dışı değer: gerekirse section ve metadata dizisini oluştur
 	if sec == nil {
-		sec = &Section{}
+		sec = newSection()
 		c.sections[secIdx] = sec
 	}
+	sec.own()
 	if sec.metadata == nil {
 		sec.metadata = make([]uint8, SectionVolume)
 		sec.metaPtr = unsafe.Pointer(&sec.metadata[0])
@@ -221,43 +230,86 @@ func (c *Chunk) SetMeta(x, y, z int, meta uint8) {
 }
 
 // SetBlockFast sets block without bounds checking. Caller must ensure valid coordinates.
-// For use during initial chunk generation only — skips dirty flag, bounds check, and old-value comparison.
+// For use during initial chunk generation only — skips dirty flag and generation counter.
 func (c *Chunk) SetBlockFast(x, y, z int, blockType BlockType) {
 	secIdx := y >> 4 // y / 16
 	sec := c.sections[secIdx]
-
-	if blockType == BlockTypeAir {
-		if sec != nil && sec.basePtr != nil {
-			localY := y & 0xF // y % 16
-			idx := x*SectionHeight*ChunkSizeZ + localY*ChunkSizeZ + z
-			blockPtr := (*BlockType)(unsafe.Pointer(uintptr(sec.basePtr) + uintptr(idx)))
-			*blockPtr = BlockTypeAir
-		}
-		return
-	}
-
 	if sec == nil {
-		sec = &Section{}
+		if blockType == BlockTypeAir {
+			return
+		}
+		sec = newSection()
 		c.sections[secIdx] = sec
 	}
-	if sec.blocks == nil {
-		sec.blocks = make([]BlockType, SectionVolume)
-		sec.basePtr = unsafe.Pointer(&sec.blocks[0])
-	}
 
-	localY := y & 0xF
-	idx := x*SectionHeight*ChunkSizeZ + localY*ChunkSizeZ + z
-	blockPtr := (*BlockType)(unsafe.Pointer(uintptr(sec.basePtr) + uintptr(idx)))
-	*blockPtr = blockType
+	idx := x*SectionHeight*ChunkSizeZ + (y&0xF)*ChunkSizeZ + z
+	sec.own()
+	blocks := sec.blocks.Load()
+	if next := blocks.set(idx, blockType); next != blocks {
+		sec.blocks.Store(next)
+	}
 }
 
-// IsSectionEmpty returns true if the section at the given Y index has no blocks allocated.
+// IsSectionEmpty returns true if the section at the given Y index holds only air.
 func (c *Chunk) IsSectionEmpty(sectionIdx int) bool {
 	if sectionIdx < 0 || sectionIdx >= NumSections {
 		return true
 	}
 	sec := c.sections[sectionIdx]
-	return sec == nil || sec.basePtr == nil
+	return sec == nil || sec.blocks.Load() == uniform(BlockTypeAir)
+}
+
+// OccupiedYRange returns the local Y range [minY, maxY) covered by non-empty
+// sections. ok is false when every section is empty.
+func (c *Chunk) OccupiedYRange() (minY, maxY int, ok bool) {
+	lo, hi := -1, -1
+	for secIdx := range NumSections {
+		if c.IsSectionEmpty(secIdx) {
+			continue
+		}
+		if lo < 0 {
+			lo = secIdx
+		}
+		hi = secIdx
+	}
+	if lo < 0 {
+		return 0, 0, false
+	}
+	return lo * SectionHeight, (hi + 1) * SectionHeight, true
+}
+
+// SectionAllMatch reports whether every block in the section satisfies table,
+// a lookup indexed by BlockType. Missing sections are all air.
+func (c *Chunk) SectionAllMatch(sectionIdx int, table *[256]bool) bool {
+	if sectionIdx < 0 || sectionIdx >= NumSections {
+		return false
+	}
+	sec := c.sections[sectionIdx]
+	if sec == nil {
+		return table[BlockTypeAir]
+	}
+	return sec.blocks.Load().matchesAll(table)
+}
+
+// compact repacks every section's blocks into the narrowest storage that
+// holds them and drops sections left with only air and no metadata. Call it
+// once a chunk is generated or loaded, before other goroutines can see it.
+func (c *Chunk) compact() {
+	var blocks [SectionVolume]BlockType
+	for secIdx, sec := range c.sections {
+		if sec == nil {
+			continue
+		}
+		s := sec.blocks.Load()
+		if s.bits != 0 {
+			s.expand(&blocks)
+			s = packBlocks(&blocks)
+			sec.blocks.Store(s)
+		}
+		if s == uniform(BlockTypeAir) && sec.metaPtr == nil {
+			c.sections[secIdx] = nil
+		}
+	}
 }
 
 // IsAir checks if the block at the specified local coordinates is air
@@ -285,20 +337,16 @@ func (c *Chunk) GetActiveBlocks() []mgl32.Vec3 {
 
 	for secIdx := range NumSections {
 		sec := c.sections[secIdx]
-		if sec == nil || sec.basePtr == nil {
+		if c.IsSectionEmpty(secIdx) {
 			continue
 		}
-
+		blocks := sec.blocks.Load()
 		sectionBaseY := secIdx * SectionHeight
-		sizeof := unsafe.Sizeof(BlockType(0))
 
 		for lx := range ChunkSizeX {
 			for ly := range SectionHeight {
 				for lz := range ChunkSizeZ {
-					idx := indexInSection(lx, ly, lz)
-					blockPtr := (*BlockType)(unsafe.Pointer(uintptr(sec.basePtr) + uintptr(idx)*sizeof))
-
-					if *blockPtr != BlockTypeAir {
+					if blocks.get(indexInSection(lx, ly, lz)) != BlockTypeAir {
 						wx := worldOffsetX + float32(lx)
 						wy := worldOffsetY + float32(sectionBaseY+ly)
 						wz := worldOffsetZ + float32(lz)
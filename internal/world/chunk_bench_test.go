@@ -68,8 +68,7 @@ func BenchmarkChunk(b *testing.B) {
 			c := NewChunk(0, 0, 0)
 			// Copy template sections into fresh chunk for a fair starting state.
 			for secIdx := range NumSections {
-				srcSec := template.sections[secIdx]
-				if srcSec != nil && srcSec.basePtr != nil {
+				if srcSec := template.sections[secIdx]; srcSec != nil {
 					c.sections[secIdx] = srcSec
 				}
 			}
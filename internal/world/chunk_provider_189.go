@@ -4,6 +4,9 @@ import (
 	"math"
 	"math/rand"
 	"sync"
+
+	"mini-mc/internal/mathutil"
+	"mini-mc/internal/rng"
 )
 
 // chunkGenBuffers holds pre-allocated noise and biome buffers reused across chunk generation calls.
@@ -16,6 +19,7 @@ type chunkGenBuffers struct {
 	biomeGrid     [81]*Biome  // 9×9 grid covering the density blending neighbourhood
 	surfaceBiomes [256]*Biome // 16×16 grid for replaceSurface
 	heightMap     [256]int16  // per-column max Y with non-air block, indexed [lx*16+lz]
+	sandMask      [256]bool   // columns carved by a river or on its bank, indexed [lx*16+lz]
 }
 
 var genBufferPool = sync.Pool{
@@ -62,6 +66,10 @@ type ChunkProvider189 struct {
 
 	// Precomputed parabolic blending weights (5x5 grid)
 	parabolicField [25]float64
+
+	// decorations receives the parts of trees that reach into neighbouring
+	// chunks; nil clips trees at the chunk border
+	decorations *decorationQueue
 }
 
 func NewChunkProvider189(seed int64) *ChunkProvider189 {
@@ -378,26 +386,28 @@ func (cp *ChunkProvider189) PopulateChunk(c *Chunk) {
 		}
 	}
 
-	// Phase 2: Surface replacement (grass/dirt/sand) + bedrock
-	cp.replaceSurface(c, xChunk, zChunk, &bufs.surfaceBiomes, &bufs.heightMap)
+	// Phase 2: River channels, carved before surfacing so banks get biome blocks
+	cp.carveRivers(c, xChunk, zChunk, &bufs.surfaceBiomes, &bufs.heightMap, &bufs.sandMask)
 
-	// Phase 3: Vegetation (trees)
+	// Phase 3: Surface replacement (grass/dirt/sand) + bedrock
+	cp.replaceSurface(c, xChunk, zChunk, &bufs.surfaceBiomes, &bufs.heightMap, &bufs.sandMask)
+
+	// Phase 4: Lakes, then decoration: trees, then grass and flowers
+	cp.generateLakes(c, xChunk, zChunk, &bufs.surfaceBiomes)
 	cp.generateTrees(c, xChunk, zChunk, &bufs.surfaceBiomes)
+	cp.generatePlants(c, xChunk, zChunk, &bufs.surfaceBiomes)
 
-	c.dirty = true
-}
+	// Phase 5: Snow cover in cold biomes, after trees so canopies get it too
+	cp.placeSnowCover(c, &bufs.surfaceBiomes)
 
-// absInt returns the absolute value of an integer.
-func absInt(x int) int {
-	if x < 0 {
-		return -x
-	}
-	return x
+	c.dirty = true
 }
 
 // generateTrees places trees after surface generation.
 // Uses the center biome of the chunk to pick tree type and count,
 // matching the MC 1.8.9 BiomeDecorator approach (treesPerChunk attempts).
+// Trunks stand anywhere in the chunk; leaves that reach past its border are
+// queued for the neighbouring chunk.
 func (cp *ChunkProvider189) generateTrees(c *Chunk, xChunk, zChunk int, surfaceBiomes *[256]*Biome) {
 	// Determine tree parameters from the chunk's center biome.
 	biome := surfaceBiomes[7*16+7]
@@ -405,19 +415,19 @@ func (cp *ChunkProvider189) generateTrees(c *Chunk, xChunk, zChunk int, surfaceB
 		return
 	}
 
-	// Seeded RNG for deterministic, chunk-local decoration.
-	// XOR-mix of world seed and chunk coords — matches MC's per-chunk decoration seed pattern.
-	rngSeed := cp.seed ^ (int64(xChunk) * 0x4F9939F508) ^ (int64(zChunk) * 0x1EF1565BD5)
-	rng := rand.New(rand.NewSource(rngSeed))
+	// Seeded RNG for deterministic, chunk-local decoration, like MC's
+	// per-chunk decoration seed.
+	rng := rng.ForChunk(cp.seed, rng.SaltTrees, xChunk, zChunk)
 
 	count := int(biome.TreeCount)
 	if rng.Intn(10) == 0 { // MC adds 10% chance of +1 tree
 		count++
 	}
 
+	d := &decorator{c: c, queue: cp.decorations}
 	for i := 0; i < count; i++ {
-		lx := 1 + rng.Intn(14) // stay off edges so canopy has room
-		lz := 1 + rng.Intn(14)
+		lx := rng.Intn(ChunkSizeX)
+		lz := rng.Intn(ChunkSizeZ)
 
 		// Find the surface block in this column (scan down from max expected height).
 		surfaceY := -1
@@ -438,21 +448,21 @@ func (cp *ChunkProvider189) generateTrees(c *Chunk, xChunk, zChunk int, surfaceB
 
 		switch biome.Trees {
 		case TreeOak:
-			cp.placeOakTree(c, lx, surfaceY+1, lz, rng)
+			cp.placeOakTree(d, lx, surfaceY+1, lz, rng)
 		case TreeSpruce:
-			cp.placeSpruceTree(c, lx, surfaceY+1, lz, rng)
+			cp.placeSpruceTree(d, lx, surfaceY+1, lz, rng)
 		}
 	}
 }
 
 // placeOakTree generates a standard oak tree matching WorldGenTrees exactly.
 // baseY is the Y of the first trunk block (one above ground).
-func (cp *ChunkProvider189) placeOakTree(c *Chunk, x, baseY, z int, rng *rand.Rand) {
+func (cp *ChunkProvider189) placeOakTree(d *decorator, x, baseY, z int, rng *rand.Rand) {
 	i := rng.Intn(3) + 4 // 4-6: trunk height (WorldGenTrees minTreeHeight=4)
 
 	// Abort if trunk space is obstructed.
 	for y := 0; y < i; y++ {
-		if c.GetBlock(x, baseY+y, z) != BlockTypeAir {
+		if d.get(x, baseY+y, z) != BlockTypeAir {
 			return
 		}
 	}
@@ -468,13 +478,13 @@ func (cp *ChunkProvider189) placeOakTree(c *Chunk, x, baseY, z int, rng *rand.Ra
 		j1 := 1 - i4/2         // radius: 2,2,1,1
 		for dx := -j1; dx <= j1; dx++ {
 			for dz := -j1; dz <= j1; dz++ {
-				isCorner := absInt(dx) == j1 && absInt(dz) == j1
+				isCorner := mathutil.Abs(dx) == j1 && mathutil.Abs(dz) == j1
 				// MC: drop corner if at top layer (i4==0) OR with 50% chance elsewhere.
 				if isCorner && (i4 == 0 || rng.Intn(2) == 0) {
 					continue
 				}
-				if c.GetBlock(x+dx, leafY, z+dz) == BlockTypeAir {
-					c.SetBlock(x+dx, leafY, z+dz, BlockTypeOakLeaves)
+				if d.get(x+dx, leafY, z+dz) == BlockTypeAir {
+					d.set(x+dx, leafY, z+dz, BlockTypeOakLeaves)
 				}
 			}
 		}
@@ -482,16 +492,16 @@ func (cp *ChunkProvider189) placeOakTree(c *Chunk, x, baseY, z int, rng *rand.Ra
 
 	// Trunk: placed after leaves to overwrite any leaf at trunk position.
 	for y := 0; y < i; y++ {
-		b := c.GetBlock(x, baseY+y, z)
+		b := d.get(x, baseY+y, z)
 		if b == BlockTypeAir || b == BlockTypeOakLeaves {
-			c.SetBlock(x, baseY+y, z, BlockTypeOakLog)
+			d.set(x, baseY+y, z, BlockTypeOakLog)
 		}
 	}
 }
 
 // placeSpruceTree generates a spruce tree matching WorldGenTaiga2 exactly.
 // baseY is the Y of the first trunk block (one above ground).
-func (cp *ChunkProvider189) placeSpruceTree(c *Chunk, x, baseY, z int, rng *rand.Rand) {
+func (cp *ChunkProvider189) placeSpruceTree(d *decorator, x, baseY, z int, rng *rand.Rand) {
 	i := rng.Intn(4) + 6 // 6-9: total height
 	j := 1 + rng.Intn(2) // 1-2: bare trunk blocks at bottom (no leaves below)
 	k := i - j           // number of leaf coverage layers
@@ -499,7 +509,7 @@ func (cp *ChunkProvider189) placeSpruceTree(c *Chunk, x, baseY, z int, rng *rand
 
 	// Abort if trunk space is obstructed.
 	for y := 0; y < i; y++ {
-		if c.GetBlock(x, baseY+y, z) != BlockTypeAir {
+		if d.get(x, baseY+y, z) != BlockTypeAir {
 			return
 		}
 	}
@@ -517,11 +527,11 @@ func (cp *ChunkProvider189) placeSpruceTree(c *Chunk, x, baseY, z int, rng *rand
 		for dx := -i3; dx <= i3; dx++ {
 			for dz := -i3; dz <= i3; dz++ {
 				// MC uses hollow squares: skip the 4 exact corners.
-				if absInt(dx) == i3 && absInt(dz) == i3 && i3 > 0 {
+				if mathutil.Abs(dx) == i3 && mathutil.Abs(dz) == i3 && i3 > 0 {
 					continue
 				}
-				if c.GetBlock(x+dx, leafY, z+dz) == BlockTypeAir {
-					c.SetBlock(x+dx, leafY, z+dz, BlockTypeSpruceLeaves)
+				if d.get(x+dx, leafY, z+dz) == BlockTypeAir {
+					d.set(x+dx, leafY, z+dz, BlockTypeSpruceLeaves)
 				}
 			}
 		}
@@ -542,9 +552,9 @@ func (cp *ChunkProvider189) placeSpruceTree(c *Chunk, x, baseY, z int, rng *rand
 	// Trunk placed after leaves (may be slightly shorter than i), overwriting leaves.
 	trunkH := i - rng.Intn(3)
 	for y := 0; y < trunkH; y++ {
-		b := c.GetBlock(x, baseY+y, z)
+		b := d.get(x, baseY+y, z)
 		if b == BlockTypeAir || b == BlockTypeSpruceLeaves {
-			c.SetBlock(x, baseY+y, z, BlockTypeSpruceLog)
+			d.set(x, baseY+y, z, BlockTypeSpruceLog)
 		}
 	}
 }
@@ -553,7 +563,8 @@ func (cp *ChunkProvider189) placeSpruceTree(c *Chunk, x, baseY, z int, rng *rand
 // surfaceBiomes is a pre-computed 16×16 array indexed [lx*16+lz].
 // heightMap tracks the maximum Y with a non-air block per column, allowing the Y loop
 // to start from the actual terrain surface rather than always scanning from y=255.
-func (cp *ChunkProvider189) replaceSurface(c *Chunk, xChunk, zChunk int, surfaceBiomes *[256]*Biome, heightMap *[256]int16) {
+// Columns flagged in sandMask (river beds and banks) are surfaced with sand.
+func (cp *ChunkProvider189) replaceSurface(c *Chunk, xChunk, zChunk int, surfaceBiomes *[256]*Biome, heightMap *[256]int16, sandMask *[256]bool) {
 	for lx := 0; lx < ChunkSizeX; lx++ {
 		for lz := 0; lz < ChunkSizeZ; lz++ {
 			worldX := xChunk*ChunkSizeX + lx
@@ -562,6 +573,10 @@ func (cp *ChunkProvider189) replaceSurface(c *Chunk, xChunk, zChunk int, surface
 
 			topBlock := biome.TopBlock
 			fillerBlock := biome.FillerBlock
+			if sandMask[lx*16+lz] {
+				topBlock = BlockTypeSand
+				fillerBlock = BlockTypeSand
+			}
 			fillerDepth := -1
 
 			startY := int(heightMap[lx*16+lz])
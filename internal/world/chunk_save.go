@@ -0,0 +1,472 @@
+package world
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"unsafe"
+)
+
+// Only chunks whose content differs from the generator's output are written
+// to disk. Every generated chunk remembers the hash of what the generator
+// produced; a chunk edited through the world is saved when its current hash
+// differs, and its saved copy is removed again once the edits are undone.
+// Chunks that are not saved are simply generated from the seed on load.
+// A chunk that holds entities is saved as well, with the entities after its
+// sections (see entity_save.go), and the same goes for block entities (see
+// block_entity_save.go), which follow the entities.
+
+const (
+	chunkFileMagic   = "MCCK"
+	chunkFileVersion = 3 // 1 had no entity record, 2 no block entity record
+
+	// maxEntityRecord bounds a chunk's entity and block entity records,
+	// against corrupt sizes
+	maxEntityRecord = 16 << 20
+
+	sectionHasBlocks = 1 << 0
+	sectionHasMeta   = 1 << 1
+)
+
+// ContentHash returns an FNV-1a hash of the chunk's blocks and metadata.
+// Empty sections hash the same whether or not they are allocated, so a block
+// placed and broken again leaves the hash unchanged.
+func (c *Chunk) ContentHash() uint64 {
+	h := fnv.New64a()
+	var blocks [SectionVolume]BlockType
+	for secIdx := range NumSections {
+		flags := c.sectionFlags(secIdx)
+		h.Write([]byte{flags})
+		sec := c.sections[secIdx]
+		if flags&sectionHasBlocks != 0 {
+			sec.blocks.Load().expand(&blocks)
+			h.Write(blockBytes(blocks[:]))
+		}
+		if flags&sectionHasMeta != 0 {
+			h.Write(sec.metadata)
+		}
+	}
+	return h.Sum64()
+}
+
+// sectionFlags reports which of a section's arrays hold anything but zeros.
+func (c *Chunk) sectionFlags(secIdx int) uint8 {
+	sec := c.sections[secIdx]
+	if sec == nil {
+		return 0
+	}
+	var flags uint8
+	if !c.IsSectionEmpty(secIdx) && !sec.blocks.Load().matchesAll(&airOnly) {
+		flags |= sectionHasBlocks
+	}
+	if sec.metadata != nil && !allZero(sec.metadata) {
+		flags |= sectionHasMeta
+	}
+	return flags
+}
+
+// airOnly matches only air
+var airOnly = [256]bool{BlockTypeAir: true}
+
+func blockBytes(blocks []BlockType) []byte {
+	return unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(blocks))), len(blocks))
+}
+
+func allZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// needsSave reports whether the chunk was edited and no longer matches the
+// generator output, or holds entities or block entities.
+func (c *Chunk) needsSave() bool {
+	return c.modified && (len(c.entities) > 0 || len(c.blockEntities) > 0 || c.ContentHash() != c.genHash)
+}
+
+// chunkSaveStore keeps edited and pregenerated chunks in region files under
+// dir. Chunks evicted or saved by the game loop are encoded there and then
+// queued; a background goroutine compresses and writes them. Until a queued
+// chunk is written, loads are served from the queue so a chunk streamed back
+// in straight away keeps its edits.
+type chunkSaveStore struct {
+	dir string
+
+	mu      sync.Mutex
+	regions map[ChunkCoord]*regionFile // by region coordinates
+	closed  bool                       // set by closeRegions; no region opens after it
+	pending map[ChunkCoord]*saveJob    // latest queued job per chunk
+	errs    []error                    // write errors since the last flush
+
+	jobs     chan *saveJob
+	queued   sync.WaitGroup // jobs not yet written
+	writerWG sync.WaitGroup
+}
+
+// saveJob is a chunk to write, or to remove when data is nil.
+type saveJob struct {
+	coord ChunkCoord
+	data  []byte // encoded, uncompressed
+}
+
+// errSaveStoreClosed is returned for reads and writes after the world closed.
+var errSaveStoreClosed = errors.New("world saves are closed")
+
+// saveQueueSize is how many chunks may wait to be written before the game
+// loop blocks on saving.
+const saveQueueSize = 256
+
+func newChunkSaveStore(dir string) (*chunkSaveStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	s := &chunkSaveStore{
+		dir:     dir,
+		regions: make(map[ChunkCoord]*regionFile),
+		pending: make(map[ChunkCoord]*saveJob),
+		jobs:    make(chan *saveJob, saveQueueSize),
+	}
+	if err := s.migrateChunkFiles(); err != nil {
+		s.closeRegions()
+		return nil, err
+	}
+	s.writerWG.Add(1)
+	go s.writer()
+	return s, nil
+}
+
+// region returns the open region file holding coord, opening it if needed.
+func (s *chunkSaveStore) region(coord ChunkCoord) (*regionFile, int, error) {
+	key := ChunkCoord{X: coord.X >> regionShift, Y: coord.Y, Z: coord.Z >> regionShift}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.regions[key]
+	if r == nil {
+		if s.closed {
+			return nil, 0, errSaveStoreClosed
+		}
+		var err error
+		path := filepath.Join(s.dir, fmt.Sprintf("r.%d.%d.%d.mcr", key.X, key.Y, key.Z))
+		if r, err = openRegionFile(path); err != nil {
+			return nil, 0, err
+		}
+		s.regions[key] = r
+	}
+	return r, regionIndex(coord.X, coord.Z), nil
+}
+
+// has reports whether coord is saved or queued to be.
+func (s *chunkSaveStore) has(coord ChunkCoord) bool {
+	s.mu.Lock()
+	job, queued := s.pending[coord]
+	s.mu.Unlock()
+	if queued {
+		return job.data != nil
+	}
+	r, i, err := s.region(coord)
+	return err == nil && r.has(i)
+}
+
+// persist queues the chunk for writing if it differs from the generator
+// output, or for removal if edits brought it back to generated content.
+// Unedited chunks are left alone: either they were never saved or their
+// saved copy is already current. Call it from the goroutine editing the
+// chunk.
+func (s *chunkSaveStore) persist(c *Chunk) {
+	if !c.modified {
+		return
+	}
+	job := &saveJob{coord: ChunkCoord{X: c.X, Y: c.Y, Z: c.Z}}
+	if c.needsSave() {
+		job.data = encodeChunk(c)
+	}
+	c.modified = false // the queued copy now matches
+
+	s.mu.Lock()
+	s.pending[job.coord] = job
+	s.mu.Unlock()
+	s.queued.Add(1)
+	s.jobs <- job
+}
+
+func (s *chunkSaveStore) writer() {
+	defer s.writerWG.Done()
+	for job := range s.jobs {
+		err := s.writeJob(job)
+		s.mu.Lock()
+		if s.pending[job.coord] == job {
+			delete(s.pending, job.coord)
+		}
+		if err != nil {
+			s.errs = append(s.errs, fmt.Errorf("saving chunk %v: %w", job.coord, err))
+		}
+		s.mu.Unlock()
+		if err != nil {
+			slog.Error("saving chunk failed", "chunk", job.coord, "err", err)
+		}
+		s.queued.Done()
+	}
+}
+
+func (s *chunkSaveStore) writeJob(job *saveJob) error {
+	r, i, err := s.region(job.coord)
+	if err != nil {
+		return err
+	}
+	if job.data == nil {
+		return r.remove(i)
+	}
+	data, err := compressChunk(job.data)
+	if err != nil {
+		return err
+	}
+	return r.write(i, data)
+}
+
+// flush waits until every queued chunk is written and returns the write
+// errors since the last flush.
+func (s *chunkSaveStore) flush() error {
+	s.queued.Wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := errors.Join(s.errs...)
+	s.errs = nil
+	return err
+}
+
+// close writes the queued chunks and closes the region files.
+func (s *chunkSaveStore) close() error {
+	close(s.jobs)
+	s.writerWG.Wait()
+	err := s.flush()
+	return errors.Join(err, s.closeRegions())
+}
+
+func (s *chunkSaveStore) closeRegions() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	var errs []error
+	for key, r := range s.regions {
+		errs = append(errs, r.close())
+		delete(s.regions, key)
+	}
+	return errors.Join(errs...)
+}
+
+// write saves c at coord straight away, bypassing the queue. It is used by
+// pregeneration workers, which own the chunks they write.
+func (s *chunkSaveStore) write(coord ChunkCoord, c *Chunk) error {
+	data, err := compressChunk(encodeChunk(c))
+	if err != nil {
+		return err
+	}
+	r, i, err := s.region(coord)
+	if err != nil {
+		return err
+	}
+	return r.write(i, data)
+}
+
+// load reads the saved chunk at coord. It returns nil, nil when the chunk is
+// not saved and should be generated.
+func (s *chunkSaveStore) load(coord ChunkCoord) (*Chunk, error) {
+	s.mu.Lock()
+	job, queued := s.pending[coord]
+	s.mu.Unlock()
+	if queued {
+		if job.data == nil {
+			return nil, nil
+		}
+		return decodeChunk(coord, bytes.NewReader(job.data))
+	}
+
+	r, i, err := s.region(coord)
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.read(i)
+	if data == nil || err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return decodeChunk(coord, bufio.NewReader(zr))
+}
+
+// encodeChunk returns the chunk in the save format, uncompressed.
+func encodeChunk(c *Chunk) []byte {
+	var buf bytes.Buffer
+	var header [13]byte
+	copy(header[:4], chunkFileMagic)
+	header[4] = chunkFileVersion
+	binary.LittleEndian.PutUint64(header[5:], c.genHash)
+	buf.Write(header[:])
+	var blocks [SectionVolume]BlockType
+	for secIdx := range NumSections {
+		flags := c.sectionFlags(secIdx)
+		buf.WriteByte(flags)
+		sec := c.sections[secIdx]
+		if flags&sectionHasBlocks != 0 {
+			sec.blocks.Load().expand(&blocks)
+			buf.Write(blockBytes(blocks[:]))
+		}
+		if flags&sectionHasMeta != 0 {
+			buf.Write(sec.metadata)
+		}
+	}
+	for _, record := range [][]byte{c.entities, c.blockEntities} {
+		var n [4]byte
+		binary.LittleEndian.PutUint32(n[:], uint32(len(record)))
+		buf.Write(n[:])
+		buf.Write(record)
+	}
+	return buf.Bytes()
+}
+
+func compressChunk(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeChunk reads a chunk in the save format.
+func decodeChunk(coord ChunkCoord, r io.Reader) (*Chunk, error) {
+	var header [13]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	version := header[4]
+	if string(header[:4]) != chunkFileMagic || version < 1 || version > chunkFileVersion {
+		return nil, fmt.Errorf("chunk %v: unsupported format", coord)
+	}
+
+	c := NewChunk(coord.X, coord.Y, coord.Z)
+	c.genHash = binary.LittleEndian.Uint64(header[5:])
+	var blocks [SectionVolume]BlockType
+	var flags [1]byte
+	for secIdx := range NumSections {
+		if _, err := io.ReadFull(r, flags[:]); err != nil {
+			return nil, err
+		}
+		if flags[0] == 0 {
+			continue
+		}
+		sec := newSection()
+		if flags[0]&sectionHasBlocks != 0 {
+			if _, err := io.ReadFull(r, blockBytes(blocks[:])); err != nil {
+				return nil, err
+			}
+			sec.blocks.Store(packBlocks(&blocks))
+		}
+		if flags[0]&sectionHasMeta != 0 {
+			sec.metadata = make([]uint8, SectionVolume)
+			if _, err := io.ReadFull(r, sec.metadata); err != nil {
+				return nil, err
+			}
+			sec.metaPtr = unsafe.Pointer(&sec.metadata[0])
+		}
+		c.sections[secIdx] = sec
+	}
+	var err error
+	if version >= 2 {
+		if c.entities, err = readRecord(coord, r, "entity"); err != nil {
+			return nil, err
+		}
+	}
+	if version >= 3 {
+		if c.blockEntities, err = readRecord(coord, r, "block entity"); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// readRecord reads one of the size-prefixed records after a chunk's
+// sections; it is nil when empty.
+func readRecord(coord ChunkCoord, r io.Reader, kind string) ([]byte, error) {
+	var n [4]byte
+	if _, err := io.ReadFull(r, n[:]); err != nil {
+		return nil, err
+	}
+	size := binary.LittleEndian.Uint32(n[:])
+	if size > maxEntityRecord {
+		return nil, fmt.Errorf("chunk %v: %s record of %d bytes", coord, kind, size)
+	}
+	if size == 0 {
+		return nil, nil
+	}
+	record := make([]byte, size)
+	if _, err := io.ReadFull(r, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// EncodeChunk returns the chunk compressed in the save format, as a server
+// sends it to its players.
+func EncodeChunk(c *Chunk) ([]byte, error) {
+	return compressChunk(encodeChunk(c))
+}
+
+// DecodeChunk reads a chunk written by EncodeChunk. It is unlit until added
+// to a world with InstallChunk.
+func DecodeChunk(coord ChunkCoord, data []byte) (*Chunk, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return decodeChunk(coord, bufio.NewReader(zr))
+}
+
+// migrateChunkFiles moves chunks saved one file each, before region files,
+// into regions. The files hold the same gzip-compressed format, so they are
+// copied as they are.
+func (s *chunkSaveStore) migrateChunkFiles() error {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "c.*.*.*.dat"))
+	if err != nil || len(paths) == 0 {
+		return err
+	}
+	slog.Info("moving saved chunks into region files", "chunks", len(paths))
+	for _, path := range paths {
+		var coord ChunkCoord
+		if _, err := fmt.Sscanf(filepath.Base(path), "c.%d.%d.%d.dat", &coord.X, &coord.Y, &coord.Z); err != nil {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		r, i, err := s.region(coord)
+		if err != nil {
+			return err
+		}
+		if err := r.write(i, data); err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
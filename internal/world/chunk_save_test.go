@@ -0,0 +1,164 @@
+package world
+
+import (
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChunkSaveSkipsUneditedAndRevertedChunks(t *testing.T) {
+	s, err := newChunkSaveStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.close()
+	store := NewChunkStore()
+	c := NewChunk(0, 0, 0)
+	c.SetBlock(1, 10, 1, BlockTypeStone) // generator output
+	c.genHash = c.ContentHash()
+	store.AddChunk(ChunkCoord{}, c)
+
+	s.persist(c)
+	if err := s.flush(); err != nil {
+		t.Fatal(err)
+	}
+	if s.has(ChunkCoord{}) {
+		t.Fatal("unedited chunk was saved")
+	}
+
+	store.SetWithMeta(2, 70, 2, BlockTypeWater, 3)
+	if !c.Modified() {
+		t.Fatal("edit through the store did not mark the chunk modified")
+	}
+	s.persist(c)
+	if err := s.flush(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := s.load(ChunkCoord{})
+	if err != nil || loaded == nil {
+		t.Fatalf("load = %v, %v", loaded, err)
+	}
+	if loaded.GetBlock(1, 10, 1) != BlockTypeStone || loaded.GetBlock(2, 70, 2) != BlockTypeWater || loaded.GetMeta(2, 70, 2) != 3 {
+		t.Fatal("saved chunk did not round-trip")
+	}
+	if loaded.genHash != c.genHash || loaded.Modified() {
+		t.Fatal("loaded chunk lost its generator hash or came back modified")
+	}
+
+	// Undoing the edit brings the chunk back to generator output.
+	store.SetWithMeta(2, 70, 2, BlockTypeAir, 0)
+	s.persist(c)
+	if err := s.flush(); err != nil {
+		t.Fatal(err)
+	}
+	if s.has(ChunkCoord{}) {
+		t.Fatal("reverted chunk stayed saved")
+	}
+}
+
+func TestChunkSaveQueueAndRegions(t *testing.T) {
+	dir := t.TempDir()
+
+	// A chunk saved one file each before region files existed
+	legacy := NewChunk(-1, 0, 40)
+	legacy.SetBlock(0, 0, 0, BlockTypeSand)
+	f, err := os.Create(filepath.Join(dir, "c.-1.0.40.dat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := gzip.NewWriter(f)
+	zw.Write(encodeChunk(legacy))
+	zw.Close()
+	f.Close()
+
+	s, err := newChunkSaveStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.load(ChunkCoord{X: -1, Z: 40}); err != nil || got == nil || got.GetBlock(0, 0, 0) != BlockTypeSand {
+		t.Fatalf("migrated chunk = %v, %v", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "c.-1.0.40.dat")); !os.IsNotExist(err) {
+		t.Error("chunk file left behind after migration")
+	}
+
+	// Chunks in one region, queued and loaded back before and after writing
+	for x := range 4 {
+		c := NewChunk(x, 0, 0)
+		c.SetBlock(x, 1, 0, BlockTypeStone)
+		c.modified = true
+		s.persist(c)
+		if got, err := s.load(ChunkCoord{X: x}); err != nil || got == nil || got.GetBlock(x, 1, 0) != BlockTypeStone {
+			t.Fatalf("queued chunk %d = %v, %v", x, got, err)
+		}
+	}
+	if err := s.close(); err != nil {
+		t.Fatal(err)
+	}
+	if regions, _ := filepath.Glob(filepath.Join(dir, "*.mcr")); len(regions) != 2 {
+		t.Errorf("region files %v, want one at x 0 and one at x -1", regions)
+	}
+	// A closed store opens no region files again
+	if _, err := s.load(ChunkCoord{X: 100}); !errors.Is(err, errSaveStoreClosed) {
+		t.Errorf("load after close: %v, want errSaveStoreClosed", err)
+	}
+	if regions, _ := filepath.Glob(filepath.Join(dir, "*.mcr")); len(regions) != 2 {
+		t.Errorf("region files after close %v", regions)
+	}
+
+	s, err = newChunkSaveStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.close()
+	for x := range 4 {
+		if got, err := s.load(ChunkCoord{X: x}); err != nil || got == nil || got.GetBlock(x, 1, 0) != BlockTypeStone {
+			t.Fatalf("reopened chunk %d = %v, %v", x, got, err)
+		}
+	}
+}
+
+func TestRegionFileReusesSectors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "r.0.0.0.mcr")
+	r, err := openRegionFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	big := make([]byte, 2*regionSectorSize+1)
+	for i := range big {
+		big[i] = byte(i)
+	}
+	if err := r.write(5, big); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.write(6, []byte("small")); err != nil {
+		t.Fatal(err)
+	}
+	// Rewriting goes to free sectors, then the old ones are reused
+	if err := r.write(5, []byte("shrunk")); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.write(7, []byte("reuse")); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.header[7].offset; got != regionHeaderSectors {
+		t.Errorf("chunk 7 at sector %d, want the freed sector %d", got, regionHeaderSectors)
+	}
+	r.close()
+
+	r, err = openRegionFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.close()
+	for i, want := range map[int]string{5: "shrunk", 6: "small", 7: "reuse"} {
+		if got, err := r.read(i); err != nil || string(got) != want {
+			t.Errorf("chunk %d = %q, %v; want %q", i, got, err, want)
+		}
+	}
+	if got, _ := r.read(8); got != nil {
+		t.Errorf("unsaved chunk read as %q", got)
+	}
+}
@@ -1,6 +1,7 @@
 package world
 
 import (
+	"mini-mc/internal/mathutil"
 	"mini-mc/internal/profiling"
 	"sync"
 
@@ -16,14 +17,41 @@ type ChunkStore struct {
 
 	// Per-column index for fast XZ radius queries: (chunkX,chunkZ) -> slice indexed by chunkY
 	colIndex map[[2]int][]*Chunk
+
+	// columns lists the keys of colIndex so a loaded column can be picked
+	// by number; columnPos is each key's place in it
+	columns   [][2]int
+	columnPos map[[2]int]int
+
+	// onEvict, if set, runs on each chunk removed by EvictFarChunks after the
+	// store lock is released
+	onEvict func(*Chunk)
+
+	// onChange, if set, runs after each block or metadata write, on the
+	// goroutine that made it
+	onChange func(x, y, z int)
+
+	// lightMu serializes light updates, which spread across chunks; take
+	// it before mu
+	lightMu sync.Mutex
+	lighter *lighter
+
+	// onRelight, if set, is given the chunks whose light changed, instead
+	// of them being marked dirty; it runs with lightMu held
+	onRelight func(...ChunkCoord)
 }
 
 // NewChunkStore creates a new chunk store.
 func NewChunkStore() *ChunkStore {
-	return &ChunkStore{
-		chunks:   make(map[ChunkCoord]*Chunk),
-		colIndex: make(map[[2]int][]*Chunk),
+	cs := &ChunkStore{
+		chunks:    make(map[ChunkCoord]*Chunk),
+		colIndex:  make(map[[2]int][]*Chunk),
+		columnPos: make(map[[2]int]int),
 	}
+	cs.lighter = newLighter(func(chunkX, chunkZ int) *Chunk {
+		return cs.GetChunk(chunkX, 0, chunkZ, false)
+	})
+	return cs
 }
 
 // GetChunk returns the chunk at the specified chunk coordinates.
@@ -44,18 +72,7 @@ func (cs *ChunkStore) GetChunk(chunkX, chunkY, chunkZ int, create bool) *Chunk {
 		chunk = NewChunk(chunkX, chunkY, chunkZ)
 		cs.chunks[coord] = chunk
 		cs.modCount++
-		// maintain column index
-		key := [2]int{chunkX, chunkZ}
-		col := cs.colIndex[key]
-		if chunkY >= 0 {
-			if len(col) <= chunkY {
-				n := make([]*Chunk, chunkY+1)
-				copy(n, col)
-				col = n
-			}
-			col[chunkY] = chunk
-			cs.colIndex[key] = col
-		}
+		cs.indexColumn(coord, chunk)
 		cs.mu.Unlock()
 	}
 	return chunk
@@ -64,9 +81,9 @@ func (cs *ChunkStore) GetChunk(chunkX, chunkY, chunkZ int, create bool) *Chunk {
 // GetChunkFromBlockCoords returns the chunk containing the block at the specified world coordinates.
 func (cs *ChunkStore) GetChunkFromBlockCoords(x, y, z int, create bool) *Chunk {
 	// Convert world coordinates to chunk coordinates
-	chunkX := floorDiv(x, ChunkSizeX)
-	chunkY := floorDiv(y, ChunkSizeY)
-	chunkZ := floorDiv(z, ChunkSizeZ)
+	chunkX := mathutil.FloorDiv(x, ChunkSizeX)
+	chunkY := mathutil.FloorDiv(y, ChunkSizeY)
+	chunkZ := mathutil.FloorDiv(z, ChunkSizeZ)
 
 	return cs.GetChunk(chunkX, chunkY, chunkZ, create)
 }
@@ -79,9 +96,9 @@ func (cs *ChunkStore) Get(x, y, z int) BlockType {
 	}
 
 	// Convert world coordinates to local chunk coordinates
-	localX := mod(x, ChunkSizeX)
-	localY := mod(y, ChunkSizeY)
-	localZ := mod(z, ChunkSizeZ)
+	localX := mathutil.Mod(x, ChunkSizeX)
+	localY := mathutil.Mod(y, ChunkSizeY)
+	localZ := mathutil.Mod(z, ChunkSizeZ)
 
 	return chunk.GetBlock(localX, localY, localZ)
 }
@@ -96,11 +113,14 @@ func (cs *ChunkStore) Set(x, y, z int, val BlockType) {
 	chunk := cs.GetChunkFromBlockCoords(x, y, z, true)
 
 	// Convert world coordinates to local chunk coordinates
-	localX := mod(x, ChunkSizeX)
-	localY := mod(y, ChunkSizeY)
-	localZ := mod(z, ChunkSizeZ)
+	localX := mathutil.Mod(x, ChunkSizeX)
+	localY := mathutil.Mod(y, ChunkSizeY)
+	localZ := mathutil.Mod(z, ChunkSizeZ)
 
+	old := chunk.GetBlock(localX, localY, localZ)
 	chunk.SetBlock(localX, localY, localZ, val)
+	chunk.modified = true
+	cs.relight(x, y, z, old)
 
 	// Mark neighbor chunks dirty if we touched a border block
 	if localX == 0 {
@@ -130,6 +150,9 @@ func (cs *ChunkStore) Set(x, y, z int, val BlockType) {
 			nb.dirty = true
 		}
 	}
+	if cs.onChange != nil {
+		cs.onChange(x, y, z)
+	}
 }
 
 // GetMeta returns the metadata at the specified world coordinates.
@@ -139,9 +162,9 @@ func (cs *ChunkStore) GetMeta(x, y, z int) uint8 {
 		return 0
 	}
 
-	localX := mod(x, ChunkSizeX)
-	localY := mod(y, ChunkSizeY)
-	localZ := mod(z, ChunkSizeZ)
+	localX := mathutil.Mod(x, ChunkSizeX)
+	localY := mathutil.Mod(y, ChunkSizeY)
+	localZ := mathutil.Mod(z, ChunkSizeZ)
 
 	return chunk.GetMeta(localX, localY, localZ)
 }
@@ -150,11 +173,12 @@ func (cs *ChunkStore) GetMeta(x, y, z int) uint8 {
 func (cs *ChunkStore) SetMeta(x, y, z int, meta uint8) {
 	chunk := cs.GetChunkFromBlockCoords(x, y, z, true)
 
-	localX := mod(x, ChunkSizeX)
-	localY := mod(y, ChunkSizeY)
-	localZ := mod(z, ChunkSizeZ)
+	localX := mathutil.Mod(x, ChunkSizeX)
+	localY := mathutil.Mod(y, ChunkSizeY)
+	localZ := mathutil.Mod(z, ChunkSizeZ)
 
 	chunk.SetMeta(localX, localY, localZ, meta)
+	chunk.modified = true
 
 	// Sınır bloklarında komşu chunk'ları dirty yap
 	if localX == 0 {
@@ -184,18 +208,24 @@ func (cs *ChunkStore) SetMeta(x, y, z int, meta uint8) {
 			nb.dirty = true
 		}
 	}
+	if cs.onChange != nil {
+		cs.onChange(x, y, z)
+	}
 }
 
 // SetWithMeta sets the block type and metadata at the specified world coordinates atomically.
 func (cs *ChunkStore) SetWithMeta(x, y, z int, val BlockType, meta uint8) {
 	chunk := cs.GetChunkFromBlockCoords(x, y, z, true)
 
-	localX := mod(x, ChunkSizeX)
-	localY := mod(y, ChunkSizeY)
-	localZ := mod(z, ChunkSizeZ)
+	localX := mathutil.Mod(x, ChunkSizeX)
+	localY := mathutil.Mod(y, ChunkSizeY)
+	localZ := mathutil.Mod(z, ChunkSizeZ)
 
+	old := chunk.GetBlock(localX, localY, localZ)
 	chunk.SetBlock(localX, localY, localZ, val)
 	chunk.SetMeta(localX, localY, localZ, meta)
+	chunk.modified = true
+	cs.relight(x, y, z, old)
 
 	// Sınır bloklarında komşu chunk'ları dirty yap
 	if localX == 0 {
@@ -225,6 +255,9 @@ func (cs *ChunkStore) SetWithMeta(x, y, z int, val BlockType, meta uint8) {
 			nb.dirty = true
 		}
 	}
+	if cs.onChange != nil {
+		cs.onChange(x, y, z)
+	}
 }
 
 // GetActiveBlocks returns a list of positions of all non-air blocks in the world.
@@ -249,6 +282,22 @@ func (cs *ChunkStore) GetAllChunks() []ChunkWithCoord {
 	return chunks
 }
 
+// SectionStorage returns how many sections the loaded chunks hold and the
+// heap bytes their blocks are stored in.
+func (cs *ChunkStore) SectionStorage() (sections, bytes int) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	for _, c := range cs.chunks {
+		for _, sec := range c.sections {
+			if sec != nil {
+				sections++
+				bytes += sec.blocks.Load().storedBytes()
+			}
+		}
+	}
+	return sections, bytes
+}
+
 // AppendChunksInRadiusXZ appends all loaded chunks within a radius (in chunks)
 // around a center chunk coordinate (cx, cz) into dst and returns the resulting slice.
 func (cs *ChunkStore) AppendChunksInRadiusXZ(cx, cz, radius int, dst []ChunkWithCoord) []ChunkWithCoord {
@@ -288,13 +337,40 @@ func (cs *ChunkStore) GetModCount() uint64 {
 // EvictFarChunks removes chunks outside the given radius from the store.
 // Returns number of removed chunks.
 func (cs *ChunkStore) EvictFarChunks(cx, cz, radius int) int {
+	return cs.evictWhere(func(coord ChunkCoord) bool {
+		dx := coord.X - cx
+		dz := coord.Z - cz
+		return dx*dx+dz*dz > radius*radius
+	})
+}
+
+// EvictChunksFarFromAll removes the chunks outside the given radius of
+// every centre, given as chunk coordinates, so a server keeps what any
+// player is near. With no centres every chunk is removed.
+func (cs *ChunkStore) EvictChunksFarFromAll(centers [][2]int, radius int) int {
+	return cs.evictWhere(func(coord ChunkCoord) bool {
+		for _, c := range centers {
+			dx := coord.X - c[0]
+			dz := coord.Z - c[1]
+			if dx*dx+dz*dz <= radius*radius {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// evictWhere removes the chunks far reports true for and returns how many.
+func (cs *ChunkStore) evictWhere(far func(ChunkCoord) bool) int {
 	defer profiling.Track("world.EvictFarChunks")()
 	removed := 0
+	var evicted []*Chunk
 	cs.mu.Lock()
-	for coord := range cs.chunks {
-		dx := coord.X - cx
-		dz := coord.Z - cz
-		if dx*dx+dz*dz > radius*radius {
+	for coord, chunk := range cs.chunks {
+		if far(coord) {
+			if cs.onEvict != nil {
+				evicted = append(evicted, chunk)
+			}
 			delete(cs.chunks, coord)
 			cs.modCount++
 			// maintain column index
@@ -309,6 +385,7 @@ func (cs *ChunkStore) EvictFarChunks(cx, cz, radius int) int {
 					}
 					if end == 0 {
 						delete(cs.colIndex, key)
+						cs.dropColumn(key)
 					} else {
 						cs.colIndex[key] = col[:end]
 					}
@@ -318,9 +395,62 @@ func (cs *ChunkStore) EvictFarChunks(cx, cz, radius int) int {
 		}
 	}
 	cs.mu.Unlock()
+	for _, chunk := range evicted {
+		cs.onEvict(chunk)
+	}
 	return removed
 }
 
+// indexColumn adds chunk to the column index. Call it with mu held.
+func (cs *ChunkStore) indexColumn(coord ChunkCoord, chunk *Chunk) {
+	if coord.Y < 0 {
+		return
+	}
+	key := [2]int{coord.X, coord.Z}
+	col, ok := cs.colIndex[key]
+	if !ok {
+		cs.columnPos[key] = len(cs.columns)
+		cs.columns = append(cs.columns, key)
+	}
+	if len(col) <= coord.Y {
+		n := make([]*Chunk, coord.Y+1)
+		copy(n, col)
+		col = n
+	}
+	col[coord.Y] = chunk
+	cs.colIndex[key] = col
+}
+
+// dropColumn removes key, whose last chunk went, from columns by moving the
+// last column into its place. Call it with mu held.
+func (cs *ChunkStore) dropColumn(key [2]int) {
+	i := cs.columnPos[key]
+	last := cs.columns[len(cs.columns)-1]
+	cs.columns[i] = last
+	cs.columnPos[last] = i
+	cs.columns = cs.columns[:len(cs.columns)-1]
+	delete(cs.columnPos, key)
+}
+
+// ColumnCount returns how many chunk columns are loaded.
+func (cs *ChunkStore) ColumnCount() int {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return len(cs.columns)
+}
+
+// ColumnAt returns the chunk coordinates (x, z) of loaded column i, from 0
+// to ColumnCount()-1. Columns are numbered in no particular order, which
+// changes as chunks load and unload; ok is false when i is out of range.
+func (cs *ChunkStore) ColumnAt(i int) (x, z int, ok bool) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	if i < 0 || i >= len(cs.columns) {
+		return 0, 0, false
+	}
+	return cs.columns[i][0], cs.columns[i][1], true
+}
+
 // HasChunk checks if a chunk exists without creating it (lite wrapper around RLock).
 func (cs *ChunkStore) HasChunk(coord ChunkCoord) bool {
 	cs.mu.RLock()
@@ -329,40 +459,42 @@ func (cs *ChunkStore) HasChunk(coord ChunkCoord) bool {
 	return exists
 }
 
-// AddChunk adds a pre-generated chunk to the store.
+// AddChunk adds a pre-generated chunk to the store and lets light flow
+// between it and the chunks around it.
 func (cs *ChunkStore) AddChunk(coord ChunkCoord, chunk *Chunk) {
+	cs.lightMu.Lock()
+	defer cs.lightMu.Unlock()
+	if cs.addChunk(coord, chunk) {
+		cs.stitchLight(chunk)
+	}
+}
+
+// addChunk adds chunk unless the store already has one at coord, and
+// reports whether it did.
+func (cs *ChunkStore) addChunk(coord ChunkCoord, chunk *Chunk) bool {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
 
-	if _, ok := cs.chunks[coord]; !ok {
-		cs.chunks[coord] = chunk
-		cs.modCount++
-		// maintain column index
-		key := [2]int{coord.X, coord.Z}
-		col := cs.colIndex[key]
-		if coord.Y >= 0 {
-			if len(col) <= coord.Y {
-				n := make([]*Chunk, coord.Y+1)
-				copy(n, col)
-				col = n
-			}
-			col[coord.Y] = chunk
-			cs.colIndex[key] = col
-		}
-		// Mark face-adjacent neighbors dirty so they re-mesh against the new chunk.
-		neighborDirs := [6]ChunkCoord{
-			{coord.X + 1, coord.Y, coord.Z},
-			{coord.X - 1, coord.Y, coord.Z},
-			{coord.X, coord.Y + 1, coord.Z},
-			{coord.X, coord.Y - 1, coord.Z},
-			{coord.X, coord.Y, coord.Z + 1},
-			{coord.X, coord.Y, coord.Z - 1},
-		}
-		for _, nc := range neighborDirs {
-			if nb, ok := cs.chunks[nc]; ok {
-				nb.dirty = true
-				nb.generation++
-			}
+	if _, ok := cs.chunks[coord]; ok {
+		return false
+	}
+	cs.chunks[coord] = chunk
+	cs.modCount++
+	cs.indexColumn(coord, chunk)
+	// Mark face-adjacent neighbors dirty so they re-mesh against the new chunk.
+	neighborDirs := [6]ChunkCoord{
+		{coord.X + 1, coord.Y, coord.Z},
+		{coord.X - 1, coord.Y, coord.Z},
+		{coord.X, coord.Y + 1, coord.Z},
+		{coord.X, coord.Y - 1, coord.Z},
+		{coord.X, coord.Y, coord.Z + 1},
+		{coord.X, coord.Y, coord.Z - 1},
+	}
+	for _, nc := range neighborDirs {
+		if nb, ok := cs.chunks[nc]; ok {
+			nb.dirty = true
+			nb.generation++
 		}
 	}
+	return true
 }
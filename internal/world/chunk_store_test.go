@@ -0,0 +1,31 @@
+package world
+
+import "testing"
+
+func TestChunkStoreColumns(t *testing.T) {
+	cs := NewChunkStore()
+	for _, c := range []ChunkCoord{{0, 0, 0}, {5, 0, 0}, {0, 0, 5}, {5, 0, 5}} {
+		cs.AddChunk(c, NewChunk(c.X, c.Y, c.Z))
+	}
+	cs.GetChunk(-3, 0, 0, true)
+	if n := cs.ColumnCount(); n != 5 {
+		t.Fatalf("%d columns, want 5", n)
+	}
+
+	// Evicting moves the last column into the gap
+	cs.EvictFarChunks(0, 0, 3)
+	seen := map[[2]int]bool{}
+	for i := range cs.ColumnCount() {
+		x, z, ok := cs.ColumnAt(i)
+		if !ok {
+			t.Fatalf("column %d missing", i)
+		}
+		seen[[2]int{x, z}] = true
+	}
+	if len(seen) != 2 || !seen[[2]int{0, 0}] || !seen[[2]int{-3, 0}] {
+		t.Errorf("columns after eviction %v, want (0, 0) and (-3, 0)", seen)
+	}
+	if _, _, ok := cs.ColumnAt(2); ok {
+		t.Error("column past the end reported")
+	}
+}
@@ -2,15 +2,20 @@ package world
 
 import (
 	"math"
+	"mini-mc/internal/mathutil"
 	"mini-mc/internal/profiling"
 	"runtime"
 	"sync"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
 )
 
 // ChunkStreamer manages asynchronous chunk generation and loading.
 type ChunkStreamer struct {
-	jobs       chan ChunkCoord
-	pending    map[ChunkCoord]struct{}
+	queue      *streamQueue
+	workers    sync.WaitGroup           // the generation workers, until Close
+	pending    map[ChunkCoord]time.Time // when each queued chunk was asked for
 	pendingMu  sync.Mutex
 	maxPending int
 
@@ -23,13 +28,28 @@ type ChunkStreamer struct {
 	// Dependencies
 	store *ChunkStore
 	gen   TerrainGenerator
+
+	// loadSaved, if set, returns the saved copy of a chunk, or nil to
+	// generate it from the seed
+	loadSaved func(ChunkCoord) *Chunk
+
+	// onGenerated, if set, runs on each new chunk before it is added to the store
+	onGenerated func(*Chunk)
+
+	// decorations, if set, holds blocks from neighbours' decoration waiting
+	// for chunks to be generated or loaded
+	decorations *decorationQueue
+
+	// Latencies holds how long queued chunks took from being asked for to
+	// being in the store
+	Latencies profiling.Latencies
 }
 
 // NewChunkStreamer creates a new chunk streamer.
 func NewChunkStreamer(store *ChunkStore, gen TerrainGenerator) *ChunkStreamer {
 	cs := &ChunkStreamer{
-		jobs:           make(chan ChunkCoord, 4096),
-		pending:        make(map[ChunkCoord]struct{}),
+		queue:          newStreamQueue(),
+		pending:        make(map[ChunkCoord]time.Time),
 		maxJobsPerCall: 2048,
 		maxPending:     16384,
 		heightCache:    make(map[[2]int]int),
@@ -38,6 +58,7 @@ func NewChunkStreamer(store *ChunkStore, gen TerrainGenerator) *ChunkStreamer {
 	}
 
 	workers := max(runtime.NumCPU(), 1)
+	cs.workers.Add(workers)
 	for i := 0; i < workers; i++ {
 		go cs.worker()
 	}
@@ -45,37 +66,73 @@ func NewChunkStreamer(store *ChunkStore, gen TerrainGenerator) *ChunkStreamer {
 	return cs
 }
 
-// Close stops the background generation workers.
+// Close stops the background generation workers, waiting for the chunks
+// they are building to be finished.
 func (cs *ChunkStreamer) Close() {
-	close(cs.jobs)
+	cs.queue.close()
+	cs.workers.Wait()
+}
+
+// SetView orders the queued chunks for a camera looking along forward, the
+// ones in view first (see stream_queue.go).
+func (cs *ChunkStreamer) SetView(forward mgl32.Vec3) {
+	cs.queue.setView(forward)
 }
 
 func (cs *ChunkStreamer) worker() {
-	for coord := range cs.jobs {
-		cs.generateChunkSync(coord)
+	defer cs.workers.Done()
+	for {
+		coord, ok := cs.queue.pop()
+		if !ok {
+			return
+		}
+		generated := cs.generateChunkSync(coord)
 		cs.pendingMu.Lock()
+		asked := cs.pending[coord]
 		delete(cs.pending, coord)
 		cs.pendingMu.Unlock()
+		if generated {
+			cs.Latencies.Add(time.Since(asked))
+		}
 	}
 }
 
-// generateChunkSync builds and installs a chunk if missing.
-func (cs *ChunkStreamer) generateChunkSync(coord ChunkCoord) {
+// generateChunkSync builds and installs a chunk if missing, reporting
+// whether it was.
+func (cs *ChunkStreamer) generateChunkSync(coord ChunkCoord) bool {
 	if cs.store.HasChunk(coord) {
-		return
+		return false
 	}
 
-	chunk := NewChunk(coord.X, coord.Y, coord.Z)
-	cs.gen.PopulateChunk(chunk)
+	var chunk *Chunk
+	if cs.loadSaved != nil {
+		chunk = cs.loadSaved(coord)
+	}
+	if chunk == nil {
+		chunk = NewChunk(coord.X, coord.Y, coord.Z)
+		cs.gen.PopulateChunk(chunk)
+		chunk.compact()
+		chunk.genHash = chunk.ContentHash()
+	}
+	// Neighbours' trees are not part of the generator output: a chunk they
+	// reached is saved like an edited one, since they may not come again
+	if cs.decorations != nil && applyPending(chunk, cs.decorations.take(coord)) {
+		chunk.modified = true
+	}
+	chunk.initLight()
+	if cs.onGenerated != nil {
+		cs.onGenerated(chunk)
+	}
 
 	cs.store.AddChunk(coord, chunk)
+	return true
 }
 
 // StreamChunksAroundSync leads chunks synchronously.
 func (cs *ChunkStreamer) StreamChunksAroundSync(x, z float32, radius int) {
 	defer profiling.Track("world.StreamChunksAroundSync")()
-	cx := floorDiv(int(math.Floor(float64(x))), ChunkSizeX)
-	cz := floorDiv(int(math.Floor(float64(z))), ChunkSizeZ)
+	cx := mathutil.FloorDiv(int(math.Floor(float64(x))), ChunkSizeX)
+	cz := mathutil.FloorDiv(int(math.Floor(float64(z))), ChunkSizeZ)
 	for dx := -radius; dx <= radius; dx++ {
 		for dz := -radius; dz <= radius; dz++ {
 			chunkX := cx + dx
@@ -83,7 +140,7 @@ func (cs *ChunkStreamer) StreamChunksAroundSync(x, z float32, radius int) {
 			worldX := chunkX*ChunkSizeX + ChunkSizeX/2
 			worldZ := chunkZ*ChunkSizeZ + ChunkSizeZ/2
 			h := cs.gen.HeightAt(worldX, worldZ)
-			maxChunkY := max(floorDiv(h, ChunkSizeY), 0)
+			maxChunkY := max(mathutil.FloorDiv(h, ChunkSizeY), 0)
 			for cy := 0; cy <= maxChunkY; cy++ {
 				cs.generateChunkSync(ChunkCoord{X: chunkX, Y: cy, Z: chunkZ})
 			}
@@ -94,18 +151,34 @@ func (cs *ChunkStreamer) StreamChunksAroundSync(x, z float32, radius int) {
 // StreamChunksAroundAsync queues chunks for async loading.
 func (cs *ChunkStreamer) StreamChunksAroundAsync(x, z float32, radius int) {
 	defer profiling.Track("world.StreamChunksAroundAsync")()
-	cx := floorDiv(int(math.Floor(float64(x))), ChunkSizeX)
-	cz := floorDiv(int(math.Floor(float64(z))), ChunkSizeZ)
+	cx := mathutil.FloorDiv(int(math.Floor(float64(x))), ChunkSizeX)
+	cz := mathutil.FloorDiv(int(math.Floor(float64(z))), ChunkSizeZ)
+	cs.streamRings(cx, cz, 0, radius)
+}
+
+// StreamRing queues the columns more than inner and up to outer chunks from
+// the one holding (x, z), nearest first, as when the render distance grows.
+func (cs *ChunkStreamer) StreamRing(x, z float32, inner, outer int) {
+	defer profiling.Track("world.StreamRing")()
+	cx := mathutil.FloorDiv(int(math.Floor(float64(x))), ChunkSizeX)
+	cz := mathutil.FloorDiv(int(math.Floor(float64(z))), ChunkSizeZ)
+	cs.streamRings(cx, cz, inner+1, outer)
+}
 
+// streamRings queues the columns of the square rings from r0 to r1 chunks
+// around (cx, cz), a ring at a time outwards, up to maxJobsPerCall chunks.
+// The workers take them in view order, not in the order queued.
+func (cs *ChunkStreamer) streamRings(cx, cz, r0, r1 int) {
 	jobsPushed := 0
+	center := [2]int{cx, cz}
 
-	for r := 0; r <= radius; r++ {
+	for r := max(r0, 0); r <= r1; r++ {
 		if jobsPushed >= cs.maxJobsPerCall {
 			break
 		}
 
 		if r == 0 {
-			jobsPushed += cs.enqueueColumn(cx, cz)
+			jobsPushed += cs.enqueueColumn(cx, cz, center)
 			continue
 		}
 
@@ -115,25 +188,25 @@ func (cs *ChunkStreamer) StreamChunksAroundAsync(x, z float32, radius int) {
 		z1 := cz + r
 
 		for xk := x0; xk <= x1; xk++ {
-			jobsPushed += cs.enqueueColumn(xk, z0)
+			jobsPushed += cs.enqueueColumn(xk, z0, center)
 			if jobsPushed >= cs.maxJobsPerCall {
 				return
 			}
 		}
 		for zk := z0 + 1; zk <= z1-1; zk++ {
-			jobsPushed += cs.enqueueColumn(x1, zk)
+			jobsPushed += cs.enqueueColumn(x1, zk, center)
 			if jobsPushed >= cs.maxJobsPerCall {
 				return
 			}
 		}
 		for xk := x1; xk >= x0; xk-- {
-			jobsPushed += cs.enqueueColumn(xk, z1)
+			jobsPushed += cs.enqueueColumn(xk, z1, center)
 			if jobsPushed >= cs.maxJobsPerCall {
 				return
 			}
 		}
 		for zk := z1 - 1; zk >= z0+1; zk-- {
-			jobsPushed += cs.enqueueColumn(x0, zk)
+			jobsPushed += cs.enqueueColumn(x0, zk, center)
 			if jobsPushed >= cs.maxJobsPerCall {
 				return
 			}
@@ -141,8 +214,9 @@ func (cs *ChunkStreamer) StreamChunksAroundAsync(x, z float32, radius int) {
 	}
 }
 
-// enqueueColumn enqueues all needed Y-chunks for a column.
-func (cs *ChunkStreamer) enqueueColumn(chunkX, chunkZ int) int {
+// enqueueColumn enqueues all needed Y-chunks for a column, requested around
+// column center.
+func (cs *ChunkStreamer) enqueueColumn(chunkX, chunkZ int, center [2]int) int {
 	// check pending cap
 	cs.pendingMu.Lock()
 	if cs.maxPending > 0 && len(cs.pending) >= cs.maxPending {
@@ -151,38 +225,86 @@ func (cs *ChunkStreamer) enqueueColumn(chunkX, chunkZ int) int {
 	}
 	cs.pendingMu.Unlock()
 
-	// Use cached column height to avoid repeated noise work
+	enq := 0
+	for cy := 0; cy <= cs.columnTop(chunkX, chunkZ); cy++ {
+		if cs.requestChunkLimited(ChunkCoord{X: chunkX, Y: cy, Z: chunkZ}, center) {
+			enq++
+		}
+	}
+	return enq
+}
+
+// columnTop returns the highest chunk Y a column needs, using cached column
+// heights to avoid repeated noise work.
+func (cs *ChunkStreamer) columnTop(chunkX, chunkZ int) int {
 	key := [2]int{chunkX, chunkZ}
 	cs.heightCacheMu.RLock()
-	cached, ok := cs.heightCache[key]
+	maxChunkY, ok := cs.heightCache[key]
 	cs.heightCacheMu.RUnlock()
-	maxChunkY := -1
-	if ok {
-		maxChunkY = cached
-	} else {
+	if !ok {
 		worldX := chunkX*ChunkSizeX + ChunkSizeX/2
 		worldZ := chunkZ*ChunkSizeZ + ChunkSizeZ/2
-		h := cs.gen.HeightAt(worldX, worldZ)
-		maxChunkY = floorDiv(h, ChunkSizeY)
+		maxChunkY = mathutil.FloorDiv(cs.gen.HeightAt(worldX, worldZ), ChunkSizeY)
 		cs.heightCacheMu.Lock()
 		cs.heightCache[key] = maxChunkY
 		cs.heightCacheMu.Unlock()
 	}
-	if maxChunkY < 0 {
-		maxChunkY = 0
+	return max(maxChunkY, 0)
+}
+
+// AreaLoaded reports whether every chunk of the columns within radius of
+// world position (x, z) is in the store.
+func (cs *ChunkStreamer) AreaLoaded(x, z float32, radius int) bool {
+	cx := mathutil.FloorDiv(int(math.Floor(float64(x))), ChunkSizeX)
+	cz := mathutil.FloorDiv(int(math.Floor(float64(z))), ChunkSizeZ)
+	for chunkX := cx - radius; chunkX <= cx+radius; chunkX++ {
+		for chunkZ := cz - radius; chunkZ <= cz+radius; chunkZ++ {
+			for cy := 0; cy <= cs.columnTop(chunkX, chunkZ); cy++ {
+				if !cs.store.HasChunk(ChunkCoord{X: chunkX, Y: cy, Z: chunkZ}) {
+					return false
+				}
+			}
+		}
 	}
+	return true
+}
 
-	enq := 0
-	for cy := 0; cy <= maxChunkY; cy++ {
-		if cs.requestChunkLimited(ChunkCoord{X: chunkX, Y: cy, Z: chunkZ}) {
-			enq++
+// RingProgress is how many of the chunks between two radii around a point
+// are loaded.
+type RingProgress struct {
+	Inner, Outer  int // in chunks; the ring is the columns past Inner up to Outer
+	Loaded, Total int // chunks
+}
+
+// Done reports whether every chunk of the ring is loaded.
+func (p RingProgress) Done() bool {
+	return p.Loaded >= p.Total
+}
+
+// RingProgress counts the loaded chunks of the columns more than inner and
+// up to outer chunks from the one holding (x, z).
+func (cs *ChunkStreamer) RingProgress(x, z float32, inner, outer int) RingProgress {
+	cx := mathutil.FloorDiv(int(math.Floor(float64(x))), ChunkSizeX)
+	cz := mathutil.FloorDiv(int(math.Floor(float64(z))), ChunkSizeZ)
+	p := RingProgress{Inner: inner, Outer: outer}
+	for chunkX := cx - outer; chunkX <= cx+outer; chunkX++ {
+		for chunkZ := cz - outer; chunkZ <= cz+outer; chunkZ++ {
+			if max(chunkX-cx, cx-chunkX, chunkZ-cz, cz-chunkZ) <= inner {
+				continue
+			}
+			for cy := 0; cy <= cs.columnTop(chunkX, chunkZ); cy++ {
+				p.Total++
+				if cs.store.HasChunk(ChunkCoord{X: chunkX, Y: cy, Z: chunkZ}) {
+					p.Loaded++
+				}
+			}
 		}
 	}
-	return enq
+	return p
 }
 
 // requestChunkLimited respects pending cap and returns true if enqueued.
-func (cs *ChunkStreamer) requestChunkLimited(coord ChunkCoord) bool {
+func (cs *ChunkStreamer) requestChunkLimited(coord ChunkCoord, center [2]int) bool {
 	// already present?
 	if cs.store.HasChunk(coord) {
 		return false
@@ -198,25 +320,23 @@ func (cs *ChunkStreamer) requestChunkLimited(coord ChunkCoord) bool {
 		cs.pendingMu.Unlock()
 		return false
 	}
-	cs.pending[coord] = struct{}{}
+	cs.pending[coord] = time.Now()
 	cs.pendingMu.Unlock()
 
-	select {
-	case cs.jobs <- coord:
-		return true
-	default:
+	if !cs.queue.push(coord, center) {
 		// queue full: rollback
 		cs.pendingMu.Lock()
 		delete(cs.pending, coord)
 		cs.pendingMu.Unlock()
 		return false
 	}
+	return true
 }
 
 // EvictFarChunks removes chunks outside the given radius.
 func (cs *ChunkStreamer) EvictFarChunks(x, z float32, radius int) int {
-	cx := floorDiv(int(math.Floor(float64(x))), ChunkSizeX)
-	cz := floorDiv(int(math.Floor(float64(z))), ChunkSizeZ)
+	cx := mathutil.FloorDiv(int(math.Floor(float64(x))), ChunkSizeX)
+	cz := mathutil.FloorDiv(int(math.Floor(float64(z))), ChunkSizeZ)
 
 	// Delegate physical removal to Store
 	removed := cs.store.EvictFarChunks(cx, cz, radius)
@@ -234,3 +354,32 @@ func (cs *ChunkStreamer) EvictFarChunks(x, z float32, radius int) int {
 
 	return removed
 }
+
+// EvictChunksFarFromAll removes the chunks outside the given radius of
+// every position, as EvictFarChunks does around one.
+func (cs *ChunkStreamer) EvictChunksFarFromAll(positions []mgl32.Vec3, radius int) int {
+	centers := make([][2]int, len(positions))
+	for i, p := range positions {
+		centers[i] = [2]int{
+			mathutil.FloorDiv(int(math.Floor(float64(p[0]))), ChunkSizeX),
+			mathutil.FloorDiv(int(math.Floor(float64(p[2]))), ChunkSizeZ),
+		}
+	}
+	removed := cs.store.EvictChunksFarFromAll(centers, radius)
+
+	cs.heightCacheMu.Lock()
+	for key := range cs.heightCache {
+		near := false
+		for _, c := range centers {
+			dx := key[0] - c[0]
+			dz := key[1] - c[1]
+			near = near || dx*dx+dz*dz <= radius*radius
+		}
+		if !near {
+			delete(cs.heightCache, key)
+		}
+	}
+	cs.heightCacheMu.Unlock()
+
+	return removed
+}
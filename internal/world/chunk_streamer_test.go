@@ -0,0 +1,43 @@
+package world
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAreaLoadedAfterSyncStreaming(t *testing.T) {
+	w := NewWithSeed(1)
+	defer w.Close()
+
+	if w.AreaLoaded(100, -40, 1) {
+		t.Fatal("area reported loaded before streaming")
+	}
+	w.StreamChunksAroundSync(100, -40, 1)
+	if !w.AreaLoaded(100, -40, 1) {
+		t.Fatal("area not loaded after streaming it")
+	}
+	if w.AreaLoaded(100, -40, 2) {
+		t.Fatal("larger area reported loaded")
+	}
+}
+
+func TestStreamRingLoadsOnlyTheRing(t *testing.T) {
+	w := NewWithSeed(1)
+	defer w.Close()
+
+	w.StreamChunksAroundSync(0, 0, 1)
+	if p := w.RingProgress(0, 0, 1, 2); p.Loaded != 0 || p.Done() {
+		t.Fatalf("ring progress before streaming it = %+v", p)
+	}
+	w.StreamRing(0, 0, 1, 2)
+	deadline := time.Now().Add(10 * time.Second)
+	for !w.RingProgress(0, 0, 1, 2).Done() {
+		if time.Now().After(deadline) {
+			t.Fatalf("ring not loaded: %+v", w.RingProgress(0, 0, 1, 2))
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if !w.AreaLoaded(0, 0, 2) || w.AreaLoaded(0, 0, 3) {
+		t.Error("streaming the ring loaded the wrong area")
+	}
+}
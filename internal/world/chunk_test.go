@@ -0,0 +1,17 @@
+package world
+
+import "testing"
+
+func TestChunkOccupiedYRange(t *testing.T) {
+	c := NewChunk(0, 0, 0)
+	if _, _, ok := c.OccupiedYRange(); ok {
+		t.Fatal("empty chunk reported an occupied range")
+	}
+
+	c.SetBlock(3, 20, 3, BlockTypeStone)
+	c.SetBlock(3, 100, 3, BlockTypeStone)
+	minY, maxY, ok := c.OccupiedYRange()
+	if !ok || minY != 16 || maxY != 112 {
+		t.Fatalf("OccupiedYRange = %d, %d, %v; want 16, 112, true", minY, maxY, ok)
+	}
+}
@@ -0,0 +1,200 @@
+package world
+
+import (
+	"math/rand"
+	"sync"
+
+	"mini-mc/internal/mathutil"
+	"mini-mc/internal/rng"
+)
+
+// Decoration (trees, grass, flowers) runs per chunk after its terrain, so a
+// tree near the edge reaches into chunks that may not exist yet. Blocks that
+// land outside the chunk being decorated wait in a decorationQueue under
+// the chunk they belong to. They are written into that chunk when it is
+// generated or loaded, or straight away on the game loop if it already is.
+// Queued blocks only fill air and plants. The queue is not saved: blocks
+// waiting for a chunk that was never loaded in a session are lost with it.
+
+// pendingBlock is a decoration block waiting for its chunk, in the chunk's
+// local coordinates.
+type pendingBlock struct {
+	x, y, z int
+	block   BlockType
+}
+
+// decorationQueue holds the decoration blocks waiting for each chunk.
+// Generation workers add to it concurrently.
+type decorationQueue struct {
+	mu      sync.Mutex
+	pending map[ChunkCoord][]pendingBlock
+}
+
+func newDecorationQueue() *decorationQueue {
+	return &decorationQueue{pending: make(map[ChunkCoord][]pendingBlock)}
+}
+
+// add queues b for the chunk at coord.
+func (q *decorationQueue) add(coord ChunkCoord, b pendingBlock) {
+	q.mu.Lock()
+	q.pending[coord] = append(q.pending[coord], b)
+	q.mu.Unlock()
+}
+
+// take removes and returns the blocks waiting for the chunk at coord.
+func (q *decorationQueue) take(coord ChunkCoord) []pendingBlock {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	blocks := q.pending[coord]
+	delete(q.pending, coord)
+	return blocks
+}
+
+// takeLoaded removes and returns the blocks waiting for chunks loaded
+// reports true for.
+func (q *decorationQueue) takeLoaded(loaded func(ChunkCoord) bool) map[ChunkCoord][]pendingBlock {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var ready map[ChunkCoord][]pendingBlock
+	for coord, blocks := range q.pending {
+		if !loaded(coord) {
+			continue
+		}
+		if ready == nil {
+			ready = make(map[ChunkCoord][]pendingBlock)
+		}
+		ready[coord] = blocks
+		delete(q.pending, coord)
+	}
+	return ready
+}
+
+// decorationReplaces reports whether a decoration block may be written over
+// bt: only air and plants give way, never terrain or the player's blocks.
+func decorationReplaces(bt BlockType) bool {
+	return bt == BlockTypeAir || IsPlant(bt)
+}
+
+// applyPending writes blocks waiting for c into it and reports whether any
+// were written. Generation workers call it on chunks not yet in the store.
+func applyPending(c *Chunk, blocks []pendingBlock) bool {
+	applied := false
+	for _, b := range blocks {
+		if decorationReplaces(c.GetBlock(b.x, b.y, b.z)) {
+			c.SetBlock(b.x, b.y, b.z, b.block)
+			applied = true
+		}
+	}
+	return applied
+}
+
+// applyLoadedDecorations writes the blocks waiting for chunks that are
+// already loaded through the store, relighting and remeshing them like any
+// other edit. Call it from the game loop.
+func (w *World) applyLoadedDecorations() {
+	if w.decorations == nil {
+		return
+	}
+	for coord, blocks := range w.decorations.takeLoaded(w.store.HasChunk) {
+		baseX, baseZ := coord.X*ChunkSizeX, coord.Z*ChunkSizeZ
+		for _, b := range blocks {
+			x, y, z := baseX+b.x, coord.Y*ChunkSizeY+b.y, baseZ+b.z
+			if decorationReplaces(w.store.Get(x, y, z)) {
+				w.store.Set(x, y, z, b.block)
+			}
+		}
+	}
+}
+
+// decorator writes the decoration of one chunk in its local coordinates.
+// Writes outside the chunk go to the queue; reads outside it see air.
+type decorator struct {
+	c     *Chunk
+	queue *decorationQueue // nil drops writes outside the chunk
+}
+
+func inChunk(x, y, z int) bool {
+	return x >= 0 && x < ChunkSizeX && y >= 0 && y < ChunkSizeY && z >= 0 && z < ChunkSizeZ
+}
+
+// get returns the block at local (x, y, z), or air outside the chunk.
+func (d *decorator) get(x, y, z int) BlockType {
+	return d.c.GetBlock(x, y, z)
+}
+
+// set writes bt at local (x, y, z), queueing it for the neighbouring chunk
+// it falls in when outside this one.
+func (d *decorator) set(x, y, z int, bt BlockType) {
+	if inChunk(x, y, z) {
+		d.c.SetBlock(x, y, z, bt)
+		return
+	}
+	if d.queue == nil || y < 0 || y >= ChunkSizeY {
+		return
+	}
+	cx, cz := mathutil.FloorDiv(x, ChunkSizeX), mathutil.FloorDiv(z, ChunkSizeZ)
+	coord := ChunkCoord{X: d.c.X + cx, Y: d.c.Y, Z: d.c.Z + cz}
+	d.queue.add(coord, pendingBlock{x: x - cx*ChunkSizeX, y: y, z: z - cz*ChunkSizeZ, block: bt})
+}
+
+// Attempts per patch, fewer than MC's 128 and 64 since a patch stays in its
+// chunk rather than spreading over the four it overlaps
+const (
+	grassPatchTries  = 48
+	flowerPatchTries = 24
+)
+
+// generatePlants scatters tall grass and flower patches over the grass of
+// the chunk, as many as its centre biome asks for, like MC's
+// grassPerChunk and flowersPerChunk. Plants stay inside the chunk: whether
+// they fit depends on the surface, which is not known for neighbours.
+func (cp *ChunkProvider189) generatePlants(c *Chunk, xChunk, zChunk int, surfaceBiomes *[256]*Biome) {
+	biome := surfaceBiomes[7*16+7]
+	if biome.GrassCount == 0 && biome.FlowerCount == 0 {
+		return
+	}
+	rng := rng.ForChunk(cp.seed, rng.SaltPlants, xChunk, zChunk)
+
+	for range biome.GrassCount {
+		scatterPlants(c, rng, grassPatchTries, BlockTypeTallGrass)
+	}
+	for range biome.FlowerCount {
+		// A patch is all one flower; dandelions are the more common
+		flower := BlockTypeDandelion
+		if rng.Intn(3) == 0 {
+			flower = BlockTypePoppy
+		}
+		scatterPlants(c, rng, flowerPatchTries, flower)
+	}
+}
+
+// scatterPlants makes tries attempts to place plant around a random column
+// of c, each on grass with air above and within 7 blocks of the centre.
+func scatterPlants(c *Chunk, rng *rand.Rand, tries int, plant BlockType) {
+	cx, cz := rng.Intn(ChunkSizeX), rng.Intn(ChunkSizeZ)
+	for range tries {
+		x := cx + rng.Intn(8) - rng.Intn(8)
+		z := cz + rng.Intn(8) - rng.Intn(8)
+		if x < 0 || x >= ChunkSizeX || z < 0 || z >= ChunkSizeZ {
+			continue
+		}
+		y := surfaceY(c, x, z)
+		if y < 0 || c.GetBlock(x, y, z) != BlockTypeGrass || c.GetBlock(x, y+1, z) != BlockTypeAir {
+			continue
+		}
+		c.SetBlock(x, y+1, z, plant)
+	}
+}
+
+// surfaceY returns the height of the top block of the column at local
+// (x, z) that is not air, leaves or water, or -1 below the sea level.
+func surfaceY(c *Chunk, x, z int) int {
+	for y := 120; y >= seaLevel; y-- {
+		switch c.GetBlock(x, y, z) {
+		case BlockTypeAir, BlockTypeWater, BlockTypeOakLeaves, BlockTypeSpruceLeaves:
+			continue
+		}
+		return y
+	}
+	return -1
+}
@@ -0,0 +1,101 @@
+package world
+
+import "testing"
+
+func TestDecoratorQueuesBlocksOutsideTheChunk(t *testing.T) {
+	q := newDecorationQueue()
+	c := NewChunk(2, 0, -1)
+	d := &decorator{c: c, queue: q}
+
+	d.set(5, 70, 5, BlockTypeOakLog)
+	d.set(16, 71, -1, BlockTypeOakLeaves)
+	d.set(-2, 72, 3, BlockTypeSpruceLeaves)
+
+	if got := c.GetBlock(5, 70, 5); got != BlockTypeOakLog {
+		t.Errorf("block inside the chunk = %v, want oak log", got)
+	}
+	if got := q.take(ChunkCoord{X: 3, Y: 0, Z: -2}); len(got) != 1 || got[0] != (pendingBlock{0, 71, 15, BlockTypeOakLeaves}) {
+		t.Errorf("queued for the north-east neighbour = %v", got)
+	}
+	if got := q.take(ChunkCoord{X: 1, Y: 0, Z: -1}); len(got) != 1 || got[0] != (pendingBlock{14, 72, 3, BlockTypeSpruceLeaves}) {
+		t.Errorf("queued for the west neighbour = %v", got)
+	}
+	if got := q.take(ChunkCoord{X: 3, Y: 0, Z: -2}); got != nil {
+		t.Errorf("blocks taken twice: %v", got)
+	}
+}
+
+func TestPendingDecorationsReachTheirChunk(t *testing.T) {
+	w := layerWorld(t, append(layers(64, BlockTypeDirt), BlockTypeGrass))
+
+	// Waiting for a chunk that is generated later
+	w.decorations.add(ChunkCoord{X: 4, Z: 0}, pendingBlock{3, 70, 4, BlockTypeOakLeaves})
+	w.decorations.add(ChunkCoord{X: 4, Z: 0}, pendingBlock{3, 64, 5, BlockTypeOakLeaves}) // the grass stays
+	w.StreamChunksAroundSync(4*ChunkSizeX+8, 8, 0)
+	if got := w.Get(4*ChunkSizeX+3, 70, 4); got != BlockTypeOakLeaves {
+		t.Errorf("queued leaves in the new chunk = %v", got)
+	}
+	if got := w.Get(4*ChunkSizeX+3, 64, 5); got != BlockTypeGrass {
+		t.Errorf("leaves replaced terrain: %v", got)
+	}
+	if c := w.GetChunk(4, 0, 0, false); !c.Modified() {
+		t.Error("chunk with a neighbour's leaves not marked for saving")
+	}
+
+	// Waiting for a chunk that is already loaded
+	w.decorations.add(ChunkCoord{X: 4, Z: 0}, pendingBlock{9, 75, 9, BlockTypeSpruceLeaves})
+	w.decorations.add(ChunkCoord{X: 9, Z: 9}, pendingBlock{1, 70, 1, BlockTypeSpruceLeaves})
+	w.Tick()
+	if got := w.Get(4*ChunkSizeX+9, 75, 9); got != BlockTypeSpruceLeaves {
+		t.Errorf("queued leaves in the loaded chunk = %v", got)
+	}
+	if got := w.decorations.take(ChunkCoord{X: 9, Z: 9}); len(got) != 1 {
+		t.Errorf("blocks for an unloaded chunk = %v, want them kept", got)
+	}
+}
+
+func TestPlantsGrowOnGrass(t *testing.T) {
+	cp := NewChunkProvider189(7)
+	c := NewChunk(0, 0, 0)
+	var biomes [256]*Biome
+	for i := range biomes {
+		biomes[i] = BiomePlains
+	}
+	for x := range ChunkSizeX {
+		for z := range ChunkSizeZ {
+			c.SetBlockFast(x, 64, z, BlockTypeDirt)
+			if x < 8 {
+				c.SetBlockFast(x, 64, z, BlockTypeGrass)
+			}
+		}
+	}
+
+	cp.generatePlants(c, 0, 0, &biomes)
+
+	plants := 0
+	for x := range ChunkSizeX {
+		for z := range ChunkSizeZ {
+			if bt := c.GetBlock(x, 65, z); IsPlant(bt) {
+				plants++
+				if x >= 8 {
+					t.Fatalf("%v at x=%d grows on dirt", bt, x)
+				}
+			}
+		}
+	}
+	if plants == 0 {
+		t.Error("no plants on a plains chunk covered in grass")
+	}
+}
+
+func TestPlantPopsOffWithoutSoil(t *testing.T) {
+	w := layerWorld(t, append(layers(64, BlockTypeDirt), BlockTypeGrass))
+	w.StreamChunksAroundSync(0, 0, 0)
+	w.Set(3, 65, 3, BlockTypePoppy)
+
+	w.Set(3, 64, 3, BlockTypeStone)
+	w.NotifyNeighbors(3, 64, 3)
+	if got := w.Get(3, 65, 3); got != BlockTypeAir {
+		t.Errorf("poppy on stone = %v, want it gone", got)
+	}
+}
@@ -1,5 +1,7 @@
 package world
 
+import "mini-mc/internal/mathutil"
+
 // DensityGenerator generates 3D terrain using density fields instead of heightmaps.
 // This enables overhangs, floating formations, and underground voids.
 type DensityGenerator struct {
@@ -148,22 +150,22 @@ func (g *DensityGenerator) PopulateChunk(c *Chunk) {
 				for lx := 0; lx < xScale; lx++ {
 					// Interpolate along X
 					tx := float64(lx) / float64(xScale)
-					d00 := lerp(d000, d100, tx)
-					d01 := lerp(d001, d101, tx)
-					d10 := lerp(d010, d110, tx)
-					d11 := lerp(d011, d111, tx)
+					d00 := mathutil.Lerp(d000, d100, tx)
+					d01 := mathutil.Lerp(d001, d101, tx)
+					d10 := mathutil.Lerp(d010, d110, tx)
+					d11 := mathutil.Lerp(d011, d111, tx)
 
 					for lz := 0; lz < zScale; lz++ {
 						// Interpolate along Z
 						tz := float64(lz) / float64(zScale)
-						d0 := lerp(d00, d01, tz) // Bottom face density at this x,z
-						d1 := lerp(d10, d11, tz) // Top face density at this x,z
+						d0 := mathutil.Lerp(d00, d01, tz) // Bottom face density at this x,z
+						d1 := mathutil.Lerp(d10, d11, tz) // Top face density at this x,z
 
 						for ly := 0; ly < (limitY - startY); ly++ {
 							// Interpolate along Y
 							// Note: ly here is relative to cell startY
 							ty := float64(ly) / float64(yScale)
-							density := lerp(d0, d1, ty)
+							density := mathutil.Lerp(d0, d1, ty)
 
 							if density > 0 {
 								targetY := startY + ly
@@ -187,5 +189,3 @@ func (g *DensityGenerator) PopulateChunk(c *Chunk) {
 
 	c.dirty = true
 }
-
-// lerp is defined in noise.go
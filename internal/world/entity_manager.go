@@ -1,20 +1,39 @@
 package world
 
 import (
+	"math"
 	"mini-mc/internal/profiling"
+	"slices"
 	"sync"
 )
 
+// The spatial index buckets entities by the chunk column they are in, which
+// also finds the entities saved with a chunk when it unloads.
+const entityCellSize = ChunkSizeX
+
+type entityCell struct{ x, z int }
+
 // EntityManager handles the lifecycle and updates of entities in the world.
 type EntityManager struct {
 	entities []Ticker
 	mu       sync.RWMutex
+
+	// cells buckets entities by XZ position as of the last Update (or their
+	// Add), so box queries only visit nearby entities.
+	cells map[entityCell][]Ticker
+
+	// pending holds the time far entities have skipped since their last tick
+	pending map[Ticker]float64
+	frame   uint64
+	stats   EntityUpdateStats
 }
 
 // NewEntityManager creates a new entity manager.
 func NewEntityManager() *EntityManager {
 	return &EntityManager{
 		entities: make([]Ticker, 0),
+		cells:    make(map[entityCell][]Ticker),
+		pending:  make(map[Ticker]float64),
 	}
 }
 
@@ -23,10 +42,71 @@ func (em *EntityManager) Add(e Ticker) {
 	em.mu.Lock()
 	defer em.mu.Unlock()
 	em.entities = append(em.entities, e)
+	em.index(e)
+}
+
+func entityCellAt(x, z float32) entityCell {
+	return entityCell{
+		x: int(math.Floor(float64(x) / entityCellSize)),
+		z: int(math.Floor(float64(z) / entityCellSize)),
+	}
+}
+
+func (em *EntityManager) index(e Ticker) {
+	pos := e.Position()
+	c := entityCellAt(pos.X(), pos.Z())
+	em.cells[c] = append(em.cells[c], e)
+}
+
+// reindex rebuilds the spatial index from the live entities, keeping the
+// cell slices' storage. Caller holds the write lock.
+func (em *EntityManager) reindex() {
+	for c, list := range em.cells {
+		if len(list) == 0 {
+			delete(em.cells, c)
+			continue
+		}
+		clear(list)
+		em.cells[c] = list[:0]
+	}
+	for _, e := range em.entities {
+		em.index(e)
+	}
+}
+
+// Entities between the simulation distance and farTickDistance times it tick
+// every farTickInterval updates with the accumulated time; further out they
+// freeze until the focus comes back.
+const (
+	farTickInterval = 4
+	farTickDistance = 2
+)
+
+// Idler is implemented by entities drawn between their last two updates.
+// An update that passes one by calls Idle, so it is drawn standing still
+// rather than replaying its last movement.
+type Idler interface {
+	Idle()
+}
+
+// EntityUpdateStats counts how entities were treated by the last Update.
+type EntityUpdateStats struct {
+	Full      int // ticked this update at full rate
+	Throttled int // beyond the simulation distance, ticking at a reduced rate
+	Frozen    int // too far to tick at all
 }
 
-// Update updates all entities and removes dead ones.
-func (em *EntityManager) Update(dt float64) {
+// Update advances entities around the focus (x, z) and removes dead ones.
+// Entities within simDistance blocks tick every update; see farTickInterval
+// for those further out.
+func (em *EntityManager) Update(dt float64, x, z, simDistance float32) {
+	em.UpdateAround(dt, [][2]float32{{x, z}}, simDistance)
+}
+
+// UpdateAround is Update with several foci, such as the players on a
+// server: entities are as near as the nearest of them. With none, every
+// entity freezes.
+func (em *EntityManager) UpdateAround(dt float64, foci [][2]float32, simDistance float32) {
 	defer profiling.Track("world.UpdateEntities")()
 
 	// First, get a copy of entities to update (holding lock briefly)
@@ -35,14 +115,54 @@ func (em *EntityManager) Update(dt float64) {
 	copy(entitiesToUpdate, em.entities)
 	em.mu.RUnlock()
 
+	em.frame++
+	fullSq := simDistance * simDistance
+	farSq := fullSq * farTickDistance * farTickDistance
+	var stats EntityUpdateStats
+	full := make([]Ticker, 0, len(entitiesToUpdate))
+
 	// Update all entities WITHOUT holding the lock
 	// This prevents deadlock when ItemEntity.Update() calls GetEntitiesInAABB()
-	for _, e := range entitiesToUpdate {
-		if !e.IsDead() {
-			e.Update(dt)
+	// The pending map is only touched by Update, which runs on one goroutine.
+	for i, e := range entitiesToUpdate {
+		if e.IsDead() {
+			continue
+		}
+		pos := e.Position()
+		distSq := float32(math.Inf(1))
+		for _, f := range foci {
+			dx, dz := pos.X()-f[0], pos.Z()-f[1]
+			distSq = min(distSq, dx*dx+dz*dz)
+		}
+		switch {
+		case distSq <= fullSq:
+			stats.Full++
+			full = append(full, e)
+			if pending, ok := em.pending[e]; ok {
+				delete(em.pending, e)
+				e.Update(dt + pending)
+			} else {
+				e.Update(dt)
+			}
+		case distSq <= farSq:
+			stats.Throttled++
+			// Stagger by slot so far entities don't all tick on the same frame
+			pending := em.pending[e] + dt
+			if (em.frame+uint64(i))%farTickInterval == 0 {
+				delete(em.pending, e)
+				e.Update(pending)
+			} else {
+				em.pending[e] = pending
+				idle(e)
+			}
+		default:
+			stats.Frozen++
+			idle(e)
 		}
 	}
 
+	em.separate(full, dt)
+
 	// Now compact the slice to remove dead entities (holding write lock)
 	em.mu.Lock()
 	defer em.mu.Unlock()
@@ -53,9 +173,28 @@ func (em *EntityManager) Update(dt float64) {
 		if !e.IsDead() {
 			em.entities[activeCount] = e
 			activeCount++
+		} else {
+			delete(em.pending, e)
 		}
 	}
+	clear(em.entities[activeCount:])
 	em.entities = em.entities[:activeCount]
+	em.reindex()
+	em.stats = stats
+}
+
+// idle tells e, if it cares, that this update passed it by.
+func idle(e Ticker) {
+	if i, ok := e.(Idler); ok {
+		i.Idle()
+	}
+}
+
+// Stats returns the counters from the last Update.
+func (em *EntityManager) Stats() EntityUpdateStats {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+	return em.stats
 }
 
 // GetAll returns a safe copy of the entities slice.
@@ -70,23 +209,72 @@ func (em *EntityManager) GetAll() []Ticker {
 	return result
 }
 
+// EntitiesInChunk returns the live entities standing in chunk column
+// (cx, cz).
+func (em *EntityManager) EntitiesInChunk(cx, cz int) []Ticker {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+
+	var result []Ticker
+	// Entities may have crossed into the column since the last reindex
+	for x := cx - 1; x <= cx+1; x++ {
+		for z := cz - 1; z <= cz+1; z++ {
+			for _, e := range em.cells[entityCell{x, z}] {
+				if e.IsDead() {
+					continue
+				}
+				pos := e.Position()
+				if entityCellAt(pos.X(), pos.Z()) == (entityCell{cx, cz}) {
+					result = append(result, e)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// remove takes the given entities out of the manager.
+func (em *EntityManager) remove(list []Ticker) {
+	if len(list) == 0 {
+		return
+	}
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	em.entities = slices.DeleteFunc(em.entities, func(e Ticker) bool {
+		return slices.Contains(list, e)
+	})
+	for _, e := range list {
+		delete(em.pending, e)
+	}
+	em.reindex()
+}
+
 // GetEntitiesInAABB returns all entities within the given axis-aligned bounding box.
-// Used for item stacking logic to find nearby items.
+// Used for item stacking and pickup to find nearby items. Only the index cells
+// the box touches (plus a one-cell margin for entities that moved since the
+// last reindex) are visited.
 func (em *EntityManager) GetEntitiesInAABB(minX, minY, minZ, maxX, maxY, maxZ float32) []Ticker {
 	em.mu.RLock()
 	defer em.mu.RUnlock()
 
+	lo := entityCellAt(minX, minZ)
+	hi := entityCellAt(maxX, maxZ)
+
 	var result []Ticker
-	for _, e := range em.entities {
-		if e.IsDead() {
-			continue
-		}
-		pos := e.Position()
-		// Check if entity's center is within the AABB
-		if pos.X() >= minX && pos.X() <= maxX &&
-			pos.Y() >= minY && pos.Y() <= maxY &&
-			pos.Z() >= minZ && pos.Z() <= maxZ {
-			result = append(result, e)
+	for cx := lo.x - 1; cx <= hi.x+1; cx++ {
+		for cz := lo.z - 1; cz <= hi.z+1; cz++ {
+			for _, e := range em.cells[entityCell{cx, cz}] {
+				if e.IsDead() {
+					continue
+				}
+				pos := e.Position()
+				// Check if entity's center is within the AABB
+				if pos.X() >= minX && pos.X() <= maxX &&
+					pos.Y() >= minY && pos.Y() <= maxY &&
+					pos.Z() >= minZ && pos.Z() <= maxZ {
+					result = append(result, e)
+				}
+			}
 		}
 	}
 	return result
@@ -0,0 +1,101 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type testTicker struct {
+	pos     mgl32.Vec3
+	elapsed float64
+	ticks   int
+	idles   int // updates that passed it by
+}
+
+func (e *testTicker) Update(dt float64)    { e.elapsed += dt; e.ticks++ }
+func (e *testTicker) IsDead() bool         { return false }
+func (e *testTicker) SetDead()             {}
+func (e *testTicker) Position() mgl32.Vec3 { return e.pos }
+func (e *testTicker) Idle()                { e.idles++ }
+
+func TestEntityUpdateDistances(t *testing.T) {
+	em := NewEntityManager()
+	near := &testTicker{pos: mgl32.Vec3{10, 64, 0}}
+	far := &testTicker{pos: mgl32.Vec3{150, 64, 0}}
+	frozen := &testTicker{pos: mgl32.Vec3{0, 64, 500}}
+	em.Add(near)
+	em.Add(far)
+	em.Add(frozen)
+
+	const dt, updates = 0.05, 8
+	for range updates {
+		em.Update(dt, 0, 0, 100)
+	}
+
+	if near.ticks != updates {
+		t.Errorf("near entity ticked %d times, want %d", near.ticks, updates)
+	}
+	if far.ticks != updates/farTickInterval {
+		t.Errorf("far entity ticked %d times, want %d", far.ticks, updates/farTickInterval)
+	}
+	if got, want := far.elapsed+em.pending[far], dt*updates; got < want-1e-9 || got > want+1e-9 {
+		t.Errorf("far entity advanced or owed %.3fs, want %.3fs", got, want)
+	}
+	if frozen.ticks != 0 {
+		t.Errorf("frozen entity ticked %d times", frozen.ticks)
+	}
+	if near.idles != 0 || far.ticks+far.idles != updates || frozen.idles != updates {
+		t.Errorf("idled near %d, far %d, frozen %d times; want every update not ticked", near.idles, far.idles, frozen.idles)
+	}
+	if got := em.Stats(); got != (EntityUpdateStats{Full: 1, Throttled: 1, Frozen: 1}) {
+		t.Errorf("stats = %+v", got)
+	}
+
+	// Coming back into range catches up on the skipped time
+	far.pos = mgl32.Vec3{50, 64, 0}
+	em.Update(dt, 0, 0, 100)
+	em.Update(dt, 0, 0, 100)
+	if want := dt * (updates + 2); far.elapsed < want-1e-9 || far.elapsed > want+1e-9 {
+		t.Errorf("far entity advanced %.3fs after returning, want %.3fs", far.elapsed, want)
+	}
+
+	if got := em.GetEntitiesInAABB(-20, 0, -20, 20, 100, 20); len(got) != 1 || got[0] != near {
+		t.Errorf("box query returned %v, want only the near entity", got)
+	}
+}
+
+type testBody struct {
+	testTicker
+	vx, vz float32
+}
+
+func (b *testBody) GetBounds() (width, height float32) { return 0.6, 1.8 }
+func (b *testBody) Push(dvx, dvz float32)              { b.vx += dvx; b.vz += dvz }
+
+func TestOverlappingBodiesPushApart(t *testing.T) {
+	em := NewEntityManager()
+	a := &testBody{testTicker: testTicker{pos: mgl32.Vec3{0, 64, 0}}}
+	b := &testBody{testTicker: testTicker{pos: mgl32.Vec3{0.3, 64, 0}}}
+	above := &testBody{testTicker: testTicker{pos: mgl32.Vec3{0, 66, 0.1}}}
+	frozen := &testBody{testTicker: testTicker{pos: mgl32.Vec3{500, 64, 0}}}
+	frozenNeighbour := &testBody{testTicker: testTicker{pos: mgl32.Vec3{500.2, 64, 0}}}
+	for _, e := range []Ticker{a, b, above, frozen, frozenNeighbour} {
+		em.Add(e)
+	}
+
+	em.Update(0.05, 0, 0, 100)
+
+	if a.vx >= 0 || b.vx <= 0 || a.vx != -b.vx {
+		t.Errorf("overlapping bodies pushed by %v and %v, want equal and apart", a.vx, b.vx)
+	}
+	if a.vz != 0 || b.vz != 0 {
+		t.Errorf("bodies side by side on X pushed along Z: %v, %v", a.vz, b.vz)
+	}
+	if above.vx != 0 || above.vz != 0 {
+		t.Error("body standing above the others was pushed")
+	}
+	if frozen.vx != 0 || frozenNeighbour.vx != 0 {
+		t.Error("bodies beyond the simulation distance were pushed")
+	}
+}
@@ -0,0 +1,148 @@
+package world
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// EntityTypeID names a kind of entity, e.g. in saved chunks.
+type EntityTypeID string
+
+// EntityType says how to make the entities of one kind. The entity package
+// registers its types with RegisterEntityType.
+type EntityType struct {
+	ID EntityTypeID
+	// New makes a fresh entity of the type at pos.
+	New func(w *World, pos mgl32.Vec3) Ticker
+	// Load makes an entity from what its SaveEntity returned.
+	Load func(w *World, data []byte) (Ticker, error)
+}
+
+// SavedEntity is an entity saved with the chunk it is in. Entities that do
+// not implement it are dropped when their chunk unloads from a saved world.
+type SavedEntity interface {
+	Ticker
+	EntityType() EntityTypeID
+	// SaveEntity returns the entity's state for its type's Load.
+	SaveEntity() []byte
+}
+
+var entityTypes = struct {
+	sync.RWMutex
+	byID map[EntityTypeID]EntityType
+}{byID: make(map[EntityTypeID]EntityType)}
+
+// RegisterEntityType makes t known by its ID. Registering an ID twice panics.
+func RegisterEntityType(t EntityType) {
+	entityTypes.Lock()
+	defer entityTypes.Unlock()
+	if _, ok := entityTypes.byID[t.ID]; ok {
+		panic(fmt.Sprintf("entity type %q registered twice", t.ID))
+	}
+	entityTypes.byID[t.ID] = t
+}
+
+// LookupEntityType returns the type registered as id.
+func LookupEntityType(id EntityTypeID) (EntityType, bool) {
+	entityTypes.RLock()
+	defer entityTypes.RUnlock()
+	t, ok := entityTypes.byID[id]
+	return t, ok
+}
+
+// EntityTypes returns the registered type IDs, sorted.
+func EntityTypes() []EntityTypeID {
+	entityTypes.RLock()
+	defer entityTypes.RUnlock()
+	ids := make([]EntityTypeID, 0, len(entityTypes.byID))
+	for id := range entityTypes.byID {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// SpawnEntity adds a new entity of type id at pos to the world.
+func (w *World) SpawnEntity(id EntityTypeID, pos mgl32.Vec3) (Ticker, error) {
+	t, ok := LookupEntityType(id)
+	if !ok || t.New == nil {
+		return nil, fmt.Errorf("unknown entity type %q", id)
+	}
+	e := t.New(w, pos)
+	w.AddEntity(e)
+	return e, nil
+}
+
+// encodeEntities returns the saved entities among list as a chunk's entity
+// record: a count, then each entity's type ID and state, length-prefixed.
+// It is nil when none of them are saved.
+func encodeEntities(list []Ticker) []byte {
+	var buf []byte
+	n := 0
+	for _, e := range list {
+		s, ok := e.(SavedEntity)
+		if !ok || e.IsDead() {
+			continue
+		}
+		data := s.SaveEntity()
+		buf = binary.AppendUvarint(buf, uint64(len(s.EntityType())))
+		buf = append(buf, s.EntityType()...)
+		buf = binary.AppendUvarint(buf, uint64(len(data)))
+		buf = append(buf, data...)
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	return append(binary.AppendUvarint(nil, uint64(n)), buf...)
+}
+
+var errEntityRecord = errors.New("corrupt entity record")
+
+// decodeEntities makes the entities of a record written by encodeEntities.
+// Entities of unknown types or that fail to load are skipped with a warning.
+func decodeEntities(w *World, record []byte) ([]Ticker, error) {
+	next := func() ([]byte, error) {
+		n, k := binary.Uvarint(record)
+		if k <= 0 || n > uint64(len(record)-k) {
+			return nil, errEntityRecord
+		}
+		field := record[k : k+int(n)]
+		record = record[k+int(n):]
+		return field, nil
+	}
+	count, k := binary.Uvarint(record)
+	if k <= 0 {
+		return nil, errEntityRecord
+	}
+	record = record[k:]
+	var list []Ticker
+	for range count {
+		id, err := next()
+		if err != nil {
+			return list, err
+		}
+		data, err := next()
+		if err != nil {
+			return list, err
+		}
+		t, ok := LookupEntityType(EntityTypeID(id))
+		if !ok || t.Load == nil {
+			slog.Warn("dropping saved entity of unknown type", "type", string(id))
+			continue
+		}
+		e, err := t.Load(w, data)
+		if err != nil {
+			slog.Warn("dropping saved entity", "type", string(id), "err", err)
+			continue
+		}
+		list = append(list, e)
+	}
+	return list, nil
+}
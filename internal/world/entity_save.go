@@ -0,0 +1,83 @@
+package world
+
+import (
+	"bytes"
+	"log/slog"
+	"sync"
+)
+
+// Entities are saved with the chunk column they stand in. When a chunk of a
+// saved world unloads, its saved entities (see SavedEntity) leave the world
+// and are encoded into the chunk, which is then saved like an edited one;
+// autosaves encode them without removing them. A loaded chunk holding
+// entities is queued, and the game loop spawns them at its next entity
+// update once the chunk is in the store, so they never tick over a hole.
+
+// entityLoadQueue holds loaded chunks whose entities wait to be spawned. It
+// is filled by generation workers and drained by the game loop.
+type entityLoadQueue struct {
+	mu     sync.Mutex
+	chunks []*Chunk
+}
+
+func (q *entityLoadQueue) push(c *Chunk) {
+	c.entitiesPending = true
+	q.mu.Lock()
+	q.chunks = append(q.chunks, c)
+	q.mu.Unlock()
+}
+
+// spawnLoadedEntities adds the entities of the queued chunks that reached
+// the store. Chunks unloaded before that keep their entities saved.
+func (w *World) spawnLoadedEntities() {
+	q := &w.entityLoads
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	waiting := q.chunks[:0]
+	for _, c := range q.chunks {
+		if !c.entitiesPending {
+			continue
+		}
+		if w.store.GetChunk(c.X, c.Y, c.Z, false) != c {
+			waiting = append(waiting, c)
+			continue
+		}
+		c.entitiesPending = false
+		list, err := decodeEntities(w, c.entities)
+		if err != nil {
+			slog.Warn("loading chunk entities failed", "chunk", ChunkCoord{X: c.X, Y: c.Y, Z: c.Z}, "err", err)
+		}
+		for _, e := range list {
+			w.AddEntity(e)
+		}
+	}
+	clear(q.chunks[len(waiting):])
+	q.chunks = waiting
+}
+
+// stashEntities encodes the saved entities standing in c into it for the
+// next save, marking c modified when they changed. With take they are also
+// removed from the world, as c is unloading.
+func (w *World) stashEntities(c *Chunk, take bool) {
+	if c.entitiesPending {
+		// Never spawned: c still holds them as loaded
+		if take {
+			c.entitiesPending = false
+		}
+		return
+	}
+	var saved []Ticker
+	for _, e := range w.entities.EntitiesInChunk(c.X, c.Z) {
+		if _, ok := e.(SavedEntity); ok {
+			saved = append(saved, e)
+		}
+	}
+	record := encodeEntities(saved)
+	if !bytes.Equal(record, c.entities) {
+		c.entities = record
+		c.modified = true
+	}
+	if take {
+		w.entities.remove(saved)
+	}
+}
@@ -0,0 +1,101 @@
+package world
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const crateType EntityTypeID = "test_crate"
+
+// crate is a saved entity that stands still
+type crate struct {
+	pos  mgl32.Vec3
+	dead bool
+}
+
+func (c *crate) Update(dt float64)        {}
+func (c *crate) IsDead() bool             { return c.dead }
+func (c *crate) SetDead()                 { c.dead = true }
+func (c *crate) Position() mgl32.Vec3     { return c.pos }
+func (c *crate) EntityType() EntityTypeID { return crateType }
+
+func (c *crate) SaveEntity() []byte {
+	b, _ := binary.Append(nil, binary.LittleEndian, c.pos)
+	return b
+}
+
+func init() {
+	RegisterEntityType(EntityType{
+		ID:  crateType,
+		New: func(_ *World, pos mgl32.Vec3) Ticker { return &crate{pos: pos} },
+		Load: func(w *World, data []byte) (Ticker, error) {
+			c := &crate{}
+			_, err := binary.Decode(data, binary.LittleEndian, &c.pos)
+			return c, err
+		},
+	})
+}
+
+// crates returns the crates in w after spawning loaded entities
+func crates(w *World) []*crate {
+	w.UpdateEntities(0.05, 0, 0, 64)
+	var list []*crate
+	for _, e := range w.GetEntities() {
+		if c, ok := e.(*crate); ok {
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+func TestEntitiesSavedWithTheirChunk(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.StreamChunksAroundSync(8, 8, 0)
+	pos := mgl32.Vec3{4.5, 90, 4.5}
+	if _, err := w.SpawnEntity(crateType, pos); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.SpawnEntity("no_such_type", pos); err == nil {
+		t.Fatal("spawned an unregistered type")
+	}
+	// Entities that are not saved stay in the world
+	w.AddEntity(&testTicker{pos: pos})
+
+	w.EvictFarChunks(10000, 10000, 1)
+	if got := crates(w); len(got) != 0 {
+		t.Fatalf("%d crates left after their chunk unloaded", len(got))
+	}
+	if len(w.GetEntities()) != 1 {
+		t.Fatal("unsaved entity was removed with the chunk")
+	}
+
+	// Unloaded again before the crate spawned: it must stay saved
+	w.StreamChunksAroundSync(8, 8, 0)
+	w.EvictFarChunks(10000, 10000, 1)
+	w.StreamChunksAroundSync(8, 8, 0)
+	got := crates(w)
+	if len(got) != 1 || got[0].pos != pos {
+		t.Fatalf("crates after reload = %v, want one at %v", got, pos)
+	}
+
+	if err := w.Save(); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	w, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.StreamChunksAroundSync(8, 8, 0)
+	if got := crates(w); len(got) != 1 || got[0].pos != pos {
+		t.Fatalf("crates after reopening = %v, want one at %v", got, pos)
+	}
+}
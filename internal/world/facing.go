@@ -0,0 +1,70 @@
+package world
+
+// Blocks with a front (furnaces) store the horizontal face their front points
+// to in metadata, using the BlockFace values FaceNorth..FaceWest.
+
+// FacingTowards returns the facing metadata for a block placed by a player
+// looking along (dx, dz), so that its front points back at the player.
+func FacingTowards(dx, dz float32) uint8 {
+	if abs32(dx) > abs32(dz) {
+		if dx > 0 {
+			return uint8(FaceWest) // front towards -X
+		}
+		return uint8(FaceEast)
+	}
+	if dz > 0 {
+		return uint8(FaceSouth) // front towards -Z
+	}
+	return uint8(FaceNorth)
+}
+
+// FacingFromNormal returns the facing metadata for a block attached to the
+// side of another one, pointing out along the clicked face's normal. ok is
+// false for top and bottom faces.
+func FacingFromNormal(dx, dy, dz int) (facing uint8, ok bool) {
+	switch {
+	case dy != 0:
+		return 0, false
+	case dz > 0:
+		return uint8(FaceNorth), true // +Z
+	case dz < 0:
+		return uint8(FaceSouth), true
+	case dx > 0:
+		return uint8(FaceEast), true // +X
+	case dx < 0:
+		return uint8(FaceWest), true
+	}
+	return 0, false
+}
+
+// FacingOffset returns the unit offset a facing points along.
+func FacingOffset(facing uint8) (dx, dz int) {
+	switch BlockFace(facing) {
+	case FaceNorth:
+		return 0, 1
+	case FaceSouth:
+		return 0, -1
+	case FaceEast:
+		return 1, 0
+	case FaceWest:
+		return -1, 0
+	}
+	return 0, 0
+}
+
+func abs32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// MirrorFacing returns the facing of a block mirrored across a plane
+// perpendicular to the X axis, or to the Z axis if acrossX is false.
+func MirrorFacing(facing uint8, acrossX bool) uint8 {
+	f := BlockFace(facing)
+	if (acrossX && (f == FaceEast || f == FaceWest)) || (!acrossX && (f == FaceNorth || f == FaceSouth)) {
+		return uint8(f.Opposite())
+	}
+	return facing
+}
@@ -47,7 +47,12 @@ func getFluidLevel(w *World, x, y, z int, fluidType BlockType) int {
 
 // isFluidBlocked checks if a block position blocks fluid flow.
 // Uses BlockSolidTable (populated by registry after init) to avoid import cycle.
+// Positions outside the world height or in chunks that are not loaded block
+// too, so fluids neither fall into the void nor spill into missing chunks.
 func isFluidBlocked(w *World, x, y, z int) bool {
+	if y < 0 || y >= ChunkSizeY || w.GetChunkFromBlockCoords(x, y, z, false) == nil {
+		return true
+	}
 	bt := w.Get(x, y, z)
 	if bt == BlockTypeAir {
 		return false
@@ -178,6 +183,7 @@ func tryFlowInto(w *World, x, y, z int, level int, fluidType BlockType) {
 	tickRate := WaterTickRate
 	if fluidType == BlockTypeLava {
 		tickRate = LavaTickRate
+		notifySnowNear(w, x, y, z)
 	}
 	w.ScheduleBlockTick(x, y, z, tickRate, 0)
 }
@@ -373,7 +379,12 @@ func notifyFluidNeighbors(w *World, x, y, z int) {
 }
 
 // NotifyNeighbors is called when a block is placed or broken to wake up any
-// adjacent fluid blocks so they can recalculate their flow.
+// adjacent fluid blocks so they can recalculate their flow, any nearby snow
+// so it can melt or fall off, and a rail or plant resting on top so it can
+// pop off.
 func (w *World) NotifyNeighbors(x, y, z int) {
 	notifyFluidNeighbors(w, x, y, z)
+	notifySnowNear(w, x, y, z)
+	notifyRailAbove(w, x, y, z)
+	notifyPlantAbove(w, x, y, z)
 }
@@ -0,0 +1,79 @@
+package world
+
+import "testing"
+
+// settleFluids ticks w until no scheduled fluid update is left, or gives up.
+func settleFluids(t *testing.T, w *World) {
+	t.Helper()
+	for range 2000 {
+		w.Tick()
+		if len(w.tickScheduler.pending) == 0 {
+			return
+		}
+	}
+	t.Fatal("fluids still flowing after 2000 ticks")
+}
+
+func TestWaterSpreadsInLevels(t *testing.T) {
+	w := layerWorld(t, layers(64, BlockTypeStone))
+	w.StreamChunksAroundSync(0, 0, 0)
+	w.Set(8, 64, 8, BlockTypeWater)
+	w.ScheduleBlockTick(8, 64, 8, WaterTickRate, 0)
+	settleFluids(t, w)
+
+	for d := 1; d <= 7; d++ {
+		if got := w.Get(8+d, 64, 8); got != BlockTypeWater {
+			t.Fatalf("%d blocks out = %v, want water", d, got)
+		}
+		if got := int(w.GetMeta(8+d, 64, 8)); got != d {
+			t.Errorf("%d blocks out level = %d, want %d", d, got, d)
+		}
+	}
+	if got := w.Get(16, 64, 8); got != BlockTypeAir {
+		t.Errorf("8 blocks out = %v, want the flow to end", got)
+	}
+
+	// Taking the source away dries the flow up
+	w.Set(8, 64, 8, BlockTypeAir)
+	w.NotifyNeighbors(8, 64, 8)
+	settleFluids(t, w)
+	if got := w.Get(10, 64, 8); got != BlockTypeAir {
+		t.Errorf("flow without a source = %v, want it gone", got)
+	}
+}
+
+func TestWaterFallsAndBetweenTwoSourcesRefills(t *testing.T) {
+	w := layerWorld(t, layers(64, BlockTypeStone))
+	w.StreamChunksAroundSync(8, 8, 1)
+	w.Set(4, 63, 4, BlockTypeAir)
+	w.Set(4, 66, 4, BlockTypeWater)
+	w.ScheduleBlockTick(4, 66, 4, WaterTickRate, 0)
+	settleFluids(t, w)
+	if got, meta := w.Get(4, 63, 4), w.GetMeta(4, 63, 4); got != BlockTypeWater || meta < 8 {
+		t.Errorf("hole under the source = %v meta %d, want falling water", got, meta)
+	}
+
+	w.Set(8, 64, 12, BlockTypeWater)
+	w.Set(10, 64, 12, BlockTypeWater)
+	w.ScheduleBlockTick(8, 64, 12, WaterTickRate, 0)
+	w.ScheduleBlockTick(10, 64, 12, WaterTickRate, 0)
+	settleFluids(t, w)
+	if got, meta := w.Get(9, 64, 12), w.GetMeta(9, 64, 12); got != BlockTypeWater || meta != 0 {
+		t.Errorf("between two sources = %v meta %d, want a new source", got, meta)
+	}
+}
+
+func TestWaterStaysInLoadedChunks(t *testing.T) {
+	w := layerWorld(t, layers(64, BlockTypeStone))
+	w.StreamChunksAroundSync(0, 0, 0)
+	w.Set(14, 64, 8, BlockTypeWater)
+	w.ScheduleBlockTick(14, 64, 8, WaterTickRate, 0)
+	settleFluids(t, w)
+
+	if got := w.Get(15, 64, 8); got != BlockTypeWater {
+		t.Errorf("edge of the loaded chunk = %v, want water", got)
+	}
+	if c := w.GetChunkFromBlockCoords(16, 64, 8, false); c != nil {
+		t.Error("water created the unloaded chunk next door")
+	}
+}
@@ -0,0 +1,78 @@
+package world
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// DefaultGameRules are the game rules a world starts with, as in 1.8.9.
+// Boolean rules are "true" or "false"; the others are integers.
+var DefaultGameRules = map[string]string{
+	"doDaylightCycle":     "true",
+	"doEntityDrops":       "true",
+	"doFireTick":          "true",
+	"doMobLoot":           "true",
+	"doMobSpawning":       "true",
+	"doTileDrops":         "true",
+	"keepInventory":       "false",
+	"mobGriefing":         "true",
+	"naturalRegeneration": "true",
+	"randomTickSpeed":     "3",
+}
+
+// GameRuleNames returns the names of all game rules, sorted.
+func GameRuleNames() []string {
+	names := make([]string, 0, len(DefaultGameRules))
+	for name := range DefaultGameRules {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// GameRule returns the value of a game rule, or false if there is no rule
+// by that name.
+func (l *Level) GameRule(name string) (string, bool) {
+	def, ok := DefaultGameRules[name]
+	if !ok {
+		return "", false
+	}
+	if v, set := l.GameRules[name]; set {
+		return v, true
+	}
+	return def, true
+}
+
+// SetGameRule sets a game rule. The value must have the rule's type.
+func (l *Level) SetGameRule(name, value string) error {
+	def, ok := DefaultGameRules[name]
+	if !ok {
+		return fmt.Errorf("unknown game rule %q", name)
+	}
+	if _, err := strconv.ParseBool(def); err == nil {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("game rule %s takes true or false", name)
+		}
+		value = strconv.FormatBool(b)
+	} else {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("game rule %s takes a whole number", name)
+		}
+		value = strconv.Itoa(n)
+	}
+	if l.GameRules == nil {
+		l.GameRules = make(map[string]string)
+	}
+	l.GameRules[name] = value
+	return nil
+}
+
+// GameRuleBool returns a boolean game rule, false if it is unknown.
+func (l *Level) GameRuleBool(name string) bool {
+	v, _ := l.GameRule(name)
+	b, _ := strconv.ParseBool(v)
+	return b
+}
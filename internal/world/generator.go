@@ -13,6 +13,8 @@ type TerrainGenerator interface {
 }
 
 // StandardGenerator handles terrain generation logic using Perlin noise.
+// Columns whose surface lies below the sea level are flooded up to it, and
+// shores within beachHeight of it are sand.
 type StandardGenerator struct {
 	seed        int64
 	scale       float64
@@ -21,6 +23,8 @@ type StandardGenerator struct {
 	octaves     int
 	persistence float64
 	lacunarity  float64
+	seaLevel    int
+	beachHeight int // how far above and below the sea level shores are sand
 }
 
 // NewGenerator creates a new generator with default settings.
@@ -29,15 +33,18 @@ func NewGenerator(seed int64) TerrainGenerator {
 	return &StandardGenerator{
 		seed:        seed,
 		scale:       1.0 / 64.0,
-		baseHeight:  32,
-		amp:         32,
+		baseHeight:  42, // about a third of the surface ends up under the sea
+		amp:         48,
 		octaves:     4,
 		persistence: 0.5,
 		lacunarity:  2.0,
+		seaLevel:    63,
+		beachHeight: 2,
 	}
 }
 
-// HeightAt computes world surface height (block Y) at world X,Z.
+// HeightAt computes world surface height (block Y) at world X,Z. Under the
+// sea this is the sea floor.
 func (g *StandardGenerator) HeightAt(worldX, worldZ int) int {
 	x := float64(worldX) * g.scale
 	z := float64(worldZ) * g.scale
@@ -49,6 +56,11 @@ func (g *StandardGenerator) HeightAt(worldX, worldZ int) int {
 	return int(math.Floor(height))
 }
 
+// SeaLevel returns the height of the sea surface: the top water block.
+func (g *StandardGenerator) SeaLevel() int {
+	return g.seaLevel
+}
+
 // PopulateChunk fills a chunk using noise heightmap.
 func (g *StandardGenerator) PopulateChunk(c *Chunk) {
 	chunkBaseY := c.Y * ChunkSizeY
@@ -57,24 +69,34 @@ func (g *StandardGenerator) PopulateChunk(c *Chunk) {
 			worldX := c.X*ChunkSizeX + lx
 			worldZ := c.Z*ChunkSizeZ + lz
 			height := g.HeightAt(worldX, worldZ)
-			topLocal := height - chunkBaseY
-			if topLocal < 0 {
-				continue
-			}
-			if topLocal >= ChunkSizeY {
-				topLocal = ChunkSizeY - 1
+
+			// Shores and the shallow sea floor are sand, over a few blocks
+			// of sand so the beach does not show dirt when dug
+			top, filler := BlockTypeGrass, BlockTypeDirt
+			switch {
+			case height < g.seaLevel-g.beachHeight:
+				top = BlockTypeDirt
+			case height <= g.seaLevel+g.beachHeight-1:
+				top, filler = BlockTypeSand, BlockTypeSand
 			}
-			for ly := 0; ly < topLocal; ly++ {
-				if chunkBaseY+ly == 0 {
+
+			for ly := range ChunkSizeY {
+				y := chunkBaseY + ly
+				switch {
+				case y == 0:
 					c.SetBlock(lx, ly, lz, BlockTypeBedrock)
-				} else {
+				case y < height-3:
 					c.SetBlock(lx, ly, lz, BlockTypeDirt)
+				case y < height:
+					c.SetBlock(lx, ly, lz, filler)
+				case y == height:
+					c.SetBlock(lx, ly, lz, top)
+				case y <= g.seaLevel:
+					c.SetBlock(lx, ly, lz, BlockTypeWater)
+				}
+				if y >= height && y >= g.seaLevel {
+					break
 				}
-			}
-			if chunkBaseY+topLocal == 0 {
-				c.SetBlock(lx, topLocal, lz, BlockTypeBedrock)
-			} else {
-				c.SetBlock(lx, topLocal, lz, BlockTypeGrass)
 			}
 		}
 	}
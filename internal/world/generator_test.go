@@ -9,6 +9,42 @@ func TestStandardGeneratorImplementsInterface(t *testing.T) {
 	var _ TerrainGenerator = NewGenerator(123)
 }
 
+func TestStandardGeneratorSeaLevel(t *testing.T) {
+	g := NewGenerator(123).(*StandardGenerator)
+	sea := g.SeaLevel()
+	var water, beach, land int
+	for cx := range 8 {
+		c := NewChunk(cx, 0, 0)
+		g.PopulateChunk(c)
+		for lx := range ChunkSizeX {
+			for lz := range ChunkSizeZ {
+				h := g.HeightAt(cx*ChunkSizeX+lx, lz)
+				if c.GetBlock(lx, sea+1, lz) == BlockTypeWater {
+					t.Fatalf("water above the sea level at %d,%d", cx*ChunkSizeX+lx, lz)
+				}
+				switch top := c.GetBlock(lx, h, lz); {
+				case h < sea:
+					water++
+					for y := h + 1; y <= sea; y++ {
+						if b := c.GetBlock(lx, y, lz); b != BlockTypeWater {
+							t.Fatalf("column %d,%d is %v at y=%d under the sea", cx*ChunkSizeX+lx, lz, b, y)
+						}
+					}
+				case top == BlockTypeSand:
+					beach++
+				case top == BlockTypeGrass:
+					land++
+				default:
+					t.Fatalf("surface at %d,%d is %v", cx*ChunkSizeX+lx, lz, top)
+				}
+			}
+		}
+	}
+	if water == 0 || beach == 0 || land == 0 {
+		t.Errorf("want sea, beach and land, got %d, %d and %d columns", water, beach, land)
+	}
+}
+
 func TestFlatGeneratorImplementsInterface(t *testing.T) {
 	var _ TerrainGenerator = NewFlatGenerator(10)
 }
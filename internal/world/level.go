@@ -0,0 +1,109 @@
+package world
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini-mc/internal/rng"
+)
+
+// Files in a world's save directory besides its chunks.
+const (
+	LevelFile = "level.json" // metadata, see Level
+	IconFile  = "icon.png"   // thumbnail shown in the world list
+
+	// legacySeedFile held only the seed before level files existed
+	legacySeedFile = "seed"
+)
+
+// GeneratorDefault is the 1.8.9-style terrain generator preset.
+const GeneratorDefault = "default"
+
+// DifficultyNormal is the difficulty of new worlds.
+const DifficultyNormal = "normal"
+
+// Level is a world's metadata, the counterpart of 1.8.9's level.dat.
+type Level struct {
+	Name       string    `json:"name"`
+	Seed       int64     `json:"seed"`
+	Generator  string    `json:"generator"`
+	GameMode   string    `json:"gameMode"`
+	Difficulty string    `json:"difficulty"`
+	PlayTime   float64   `json:"playTimeSeconds"` // accumulated unpaused play
+	DayTime    float64   `json:"dayTime"`         // world time in ticks, see World.Time
+	LastPlayed time.Time `json:"lastPlayed"`
+
+	// GameRules holds the rules changed from DefaultGameRules
+	GameRules map[string]string `json:"gameRules,omitempty"`
+
+	// Spawn is where players appear, chosen the first time the world is
+	// played (see World.Spawn)
+	Spawn *BlockPos `json:"spawn,omitempty"`
+}
+
+// newLevel returns the metadata of a new world named after its directory.
+func newLevel(dir string, seed int64) *Level {
+	return &Level{
+		Name:       filepath.Base(dir),
+		Seed:       seed,
+		Generator:  GeneratorDefault,
+		Difficulty: DifficultyNormal,
+	}
+}
+
+// ReadLevel reads the metadata of the world saved in dir. It returns an
+// error satisfying errors.Is(err, os.ErrNotExist) if dir holds no world.
+func ReadLevel(dir string) (*Level, error) {
+	data, err := os.ReadFile(filepath.Join(dir, LevelFile))
+	if err != nil {
+		return nil, err
+	}
+	l := &Level{}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Write saves the metadata into dir.
+func (l *Level) Write(dir string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, LevelFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readOrCreateLevel reads dir's metadata, creating it for a new world. Worlds
+// saved before level files existed keep the seed from their seed file.
+func readOrCreateLevel(dir string) (*Level, error) {
+	l, err := ReadLevel(dir)
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return l, err
+	}
+
+	seed := rng.NewSeed()
+	data, err := os.ReadFile(filepath.Join(dir, legacySeedFile))
+	if err == nil {
+		if seed, err = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	l = newLevel(dir, seed)
+	return l, l.Write(dir)
+}
@@ -0,0 +1,70 @@
+package world
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenCreatesAndKeepsLevel(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Island")
+	w, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := w.Level()
+	if l.Name != "Island" || l.Seed != w.Seed() || l.Generator != GeneratorDefault || l.Difficulty != DifficultyNormal {
+		t.Fatalf("new level = %+v", l)
+	}
+	l.GameMode = "creative"
+	l.PlayTime = 90
+	if err := w.Save(); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	got, err := ReadLevel(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GameMode != "creative" || got.PlayTime != 90 || got.LastPlayed.IsZero() || got.Seed != l.Seed {
+		t.Errorf("saved level = %+v", got)
+	}
+}
+
+func TestOpenMigratesSeedFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, legacySeedFile), []byte("1234\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if w.Seed() != 1234 || w.Level().Seed != 1234 {
+		t.Errorf("seed = %d, level seed = %d, want 1234", w.Seed(), w.Level().Seed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, LevelFile)); err != nil {
+		t.Errorf("level file not written: %v", err)
+	}
+}
+
+func TestGameRules(t *testing.T) {
+	var l Level
+	if v, ok := l.GameRule("doDaylightCycle"); !ok || v != "true" {
+		t.Errorf("default doDaylightCycle = %q, %v", v, ok)
+	}
+	if err := l.SetGameRule("doDaylightCycle", "FALSE"); err != nil || l.GameRuleBool("doDaylightCycle") {
+		t.Errorf("clearing doDaylightCycle: %v", err)
+	}
+	if err := l.SetGameRule("randomTickSpeed", "fast"); err == nil {
+		t.Error("randomTickSpeed accepted a word")
+	}
+	if err := l.SetGameRule("noSuchRule", "1"); err == nil {
+		t.Error("unknown rule accepted")
+	}
+	if _, ok := l.GameRule("noSuchRule"); ok {
+		t.Error("unknown rule found")
+	}
+}
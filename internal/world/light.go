@@ -0,0 +1,421 @@
+package world
+
+import "mini-mc/internal/mathutil"
+
+// Light comes in two channels, each 0-15: sky light, which falls straight
+// down from the top of the world without fading and spreads sideways and
+// under overhangs from there, and block light, which spreads out from
+// emitting blocks such as lava. Both lose at least one level per block they
+// spread, and more through blocks that absorb light (BlockLightOpacity);
+// fully opaque blocks hold none.
+//
+// A chunk's light is stored per section, one byte per block (sky<<4 | block).
+// Sections where every block has the same light - open sky, or solid rock -
+// keep a single fill value instead, until a block in them is lit
+// differently.
+//
+// A new chunk is lit on its own by initLight, on the generation worker, as
+// if nothing around it were loaded. ChunkStore.AddChunk then lets light flow
+// across its borders into and out of its neighbours, and ChunkStore.Set
+// relights around each changed block. Both hold the store's light lock, so
+// only one update spreads light at a time; mesh workers read light without
+// it, as they do blocks.
+
+// MaxLight is the brightest light level.
+const MaxLight = 15
+
+// fullSky is the light of blocks open to the sky with no block light.
+const fullSky = MaxLight << 4
+
+// Light returns the light at local (x, y, z) packed as sky<<4 | block.
+// Above the world is open sky; elsewhere outside the chunk is dark.
+func (c *Chunk) Light(x, y, z int) uint8 {
+	if y >= ChunkSizeY {
+		return fullSky
+	}
+	if x < 0 || x >= ChunkSizeX || y < 0 || z < 0 || z >= ChunkSizeZ {
+		return 0
+	}
+	secIdx := y / SectionHeight
+	if l := c.light[secIdx].Load(); l != nil {
+		return l[indexInSection(x, y%SectionHeight, z)]
+	}
+	return c.lightFill[secIdx]
+}
+
+// setLight stores packed light at local (x, y, z), which must be inside the
+// chunk, giving the section its own light array once it stops being uniform.
+func (c *Chunk) setLight(x, y, z int, v uint8) {
+	secIdx := y / SectionHeight
+	l := c.ownLight(secIdx)
+	if l == nil {
+		if v == c.lightFill[secIdx] {
+			return
+		}
+		l = new([SectionVolume]uint8)
+		for i := range l {
+			l[i] = c.lightFill[secIdx]
+		}
+		c.light[secIdx].Store(l)
+	}
+	l[indexInSection(x, y%SectionHeight, z)] = v
+}
+
+// SkyLight returns the sky light at the specified world coordinates.
+func (w *World) SkyLight(x, y, z int) uint8 {
+	return w.Light(x, y, z) >> 4
+}
+
+// BlockLight returns the block light at the specified world coordinates.
+func (w *World) BlockLight(x, y, z int) uint8 {
+	return w.Light(x, y, z) & MaxLight
+}
+
+// Light returns the light at the specified world coordinates packed as
+// sky<<4 | block. Chunks that are not loaded count as open sky.
+func (w *World) Light(x, y, z int) uint8 {
+	if y >= ChunkSizeY {
+		return fullSky
+	}
+	c := w.store.GetChunkFromBlockCoords(x, y, z, false)
+	if c == nil {
+		return fullSky
+	}
+	return c.Light(mathutil.Mod(x, ChunkSizeX), mathutil.Mod(y, ChunkSizeY), mathutil.Mod(z, ChunkSizeZ))
+}
+
+// lightNode is a block queued for light to spread from (or, while removing
+// light, the level it used to have).
+type lightNode struct {
+	x, y, z int
+	level   uint8
+}
+
+// lightDirs are the six neighbours light spreads to; lightDown is the index
+// of the one below.
+var lightDirs = [6][3]int{{1, 0, 0}, {-1, 0, 0}, {0, 0, 1}, {0, 0, -1}, {0, 1, 0}, {0, -1, 0}}
+
+const lightDown = 5
+
+// lighter spreads and removes light over the chunks lookup returns, by
+// world block coordinates. Its queues are kept between updates.
+type lighter struct {
+	lookup  func(chunkX, chunkZ int) *Chunk
+	last    *Chunk
+	touched map[*Chunk]struct{}
+	relit   []ChunkCoord // reused by finish
+
+	queue   []lightNode
+	removal []lightNode
+}
+
+func newLighter(lookup func(chunkX, chunkZ int) *Chunk) *lighter {
+	return &lighter{lookup: lookup, touched: make(map[*Chunk]struct{})}
+}
+
+func (l *lighter) chunkAt(x, z int) *Chunk {
+	cx, cz := mathutil.FloorDiv(x, ChunkSizeX), mathutil.FloorDiv(z, ChunkSizeZ)
+	if l.last != nil && l.last.X == cx && l.last.Z == cz {
+		return l.last
+	}
+	c := l.lookup(cx, cz)
+	if c != nil {
+		l.last = c
+	}
+	return c
+}
+
+// get returns a channel's light at (x, y, z) and the chunk holding it, nil
+// outside the loaded world.
+func (l *lighter) get(x, y, z int, sky bool) (uint8, *Chunk) {
+	if y < 0 || y >= ChunkSizeY {
+		return 0, nil
+	}
+	c := l.chunkAt(x, z)
+	if c == nil {
+		return 0, nil
+	}
+	v := c.Light(mathutil.Mod(x, ChunkSizeX), y, mathutil.Mod(z, ChunkSizeZ))
+	if sky {
+		return v >> 4, c
+	}
+	return v & MaxLight, c
+}
+
+func (l *lighter) set(c *Chunk, x, y, z int, sky bool, level uint8) {
+	lx, lz := mathutil.Mod(x, ChunkSizeX), mathutil.Mod(z, ChunkSizeZ)
+	v := c.Light(lx, y, lz)
+	if sky {
+		v = v&MaxLight | level<<4
+	} else {
+		v = v&^MaxLight | level
+	}
+	c.setLight(lx, y, lz, v)
+	l.touched[c] = struct{}{}
+}
+
+func (l *lighter) opacity(c *Chunk, x, y, z int) uint8 {
+	return BlockLightOpacity[c.GetBlock(mathutil.Mod(x, ChunkSizeX), y, mathutil.Mod(z, ChunkSizeZ))]
+}
+
+// spreadLevel is the level light at level reaches a block of opacity with,
+// going down if down is set; sky light of full strength falls through clear
+// blocks without fading.
+func spreadLevel(level, opacity uint8, sky, down bool) uint8 {
+	if opacity >= MaxLight {
+		return 0
+	}
+	if sky && down && level == MaxLight && opacity == 0 {
+		return MaxLight
+	}
+	return level - min(level, max(opacity, 1))
+}
+
+// propagate spreads light from the queued blocks until it settles.
+func (l *lighter) propagate(sky bool) {
+	for i := 0; i < len(l.queue); i++ {
+		n := l.queue[i]
+		level, _ := l.get(n.x, n.y, n.z, sky)
+		if level <= 1 {
+			continue
+		}
+		for d, dir := range lightDirs {
+			x, y, z := n.x+dir[0], n.y+dir[1], n.z+dir[2]
+			cur, c := l.get(x, y, z, sky)
+			if c == nil {
+				continue
+			}
+			if v := spreadLevel(level, l.opacity(c, x, y, z), sky, d == lightDown); v > cur {
+				l.set(c, x, y, z, sky, v)
+				l.queue = append(l.queue, lightNode{x, y, z, v})
+			}
+		}
+	}
+	l.queue = l.queue[:0]
+}
+
+// remove darkens the blocks queued for removal and everything lit only
+// through them, queueing the brighter blocks around the darkened area so
+// that a following propagate fills it back in from what remains.
+func (l *lighter) remove(sky bool) {
+	for i := 0; i < len(l.removal); i++ {
+		n := l.removal[i]
+		for d, dir := range lightDirs {
+			x, y, z := n.x+dir[0], n.y+dir[1], n.z+dir[2]
+			cur, c := l.get(x, y, z, sky)
+			if c == nil || cur == 0 {
+				continue
+			}
+			if cur < n.level || sky && d == lightDown && n.level == MaxLight && cur == MaxLight {
+				l.set(c, x, y, z, sky, 0)
+				l.removal = append(l.removal, lightNode{x, y, z, cur})
+				if e := l.emission(c, x, y, z, sky); e > 0 {
+					l.set(c, x, y, z, sky, e)
+					l.queue = append(l.queue, lightNode{x, y, z, e})
+				}
+			} else {
+				l.queue = append(l.queue, lightNode{x, y, z, cur})
+			}
+		}
+	}
+	l.removal = l.removal[:0]
+}
+
+func (l *lighter) emission(c *Chunk, x, y, z int, sky bool) uint8 {
+	if sky {
+		return 0
+	}
+	return BlockLightEmission[c.GetBlock(mathutil.Mod(x, ChunkSizeX), y, mathutil.Mod(z, ChunkSizeZ))]
+}
+
+// update relights around (x, y, z) after the block there changed.
+func (l *lighter) update(x, y, z int) {
+	for _, sky := range [2]bool{true, false} {
+		old, c := l.get(x, y, z, sky)
+		if c == nil {
+			return
+		}
+		if old > 0 {
+			l.set(c, x, y, z, sky, 0)
+			l.removal = append(l.removal, lightNode{x, y, z, old})
+			l.remove(sky)
+		}
+
+		// What the block gets from itself and its neighbours as they are now
+		level := l.emission(c, x, y, z, sky)
+		opacity := l.opacity(c, x, y, z)
+		if sky && y == ChunkSizeY-1 {
+			level = max(level, spreadLevel(MaxLight, opacity, true, true))
+		}
+		for d, dir := range lightDirs {
+			nl, nc := l.get(x-dir[0], y-dir[1], z-dir[2], sky)
+			if nc != nil {
+				level = max(level, spreadLevel(nl, opacity, sky, d == lightDown))
+			}
+		}
+		if level > 0 {
+			l.set(c, x, y, z, sky, level)
+			l.queue = append(l.queue, lightNode{x, y, z, level})
+		}
+		l.propagate(sky)
+	}
+}
+
+// finish hands the chunks whose light changed to onRelight for remeshing,
+// or marks them dirty when it is nil.
+func (l *lighter) finish(onRelight func(...ChunkCoord)) {
+	l.relit = l.relit[:0]
+	for c := range l.touched {
+		if onRelight == nil {
+			c.dirty = true
+		}
+		l.relit = append(l.relit, ChunkCoord{X: c.X, Y: c.Y, Z: c.Z})
+		delete(l.touched, c)
+	}
+	if onRelight != nil && len(l.relit) > 0 {
+		onRelight(l.relit...)
+	}
+	l.last = nil
+}
+
+// initLight lights a newly generated or loaded chunk on its own. Call it
+// before other goroutines can see the chunk.
+func (c *Chunk) initLight() {
+	var clear, dark [256]bool // lets all light through; emits none
+	for i := range clear {
+		clear[i] = BlockLightOpacity[i] == 0
+		dark[i] = BlockLightEmission[i] == 0
+	}
+
+	// Sections above the highest one that blocks light are open sky
+	top := -1
+	for secIdx := NumSections - 1; secIdx >= 0; secIdx-- {
+		if !c.SectionAllMatch(secIdx, &clear) {
+			top = secIdx
+			break
+		}
+	}
+	for secIdx := range NumSections {
+		c.light[secIdx].Store(nil)
+		c.lightFill[secIdx] = 0
+		if secIdx > top {
+			c.lightFill[secIdx] = fullSky
+		}
+	}
+
+	l := newLighter(func(chunkX, chunkZ int) *Chunk {
+		if chunkX == c.X && chunkZ == c.Z {
+			return c
+		}
+		return nil
+	})
+	x0, z0 := c.X*ChunkSizeX, c.Z*ChunkSizeZ
+
+	// Sky light falls straight down to the first block that takes any of
+	// it; open columns light their neighbours from as low as those are lit.
+	var lowest [ChunkSizeX][ChunkSizeZ]int
+	for x := range ChunkSizeX {
+		for z := range ChunkSizeZ {
+			y := (top + 1) * SectionHeight
+			for y > 0 && BlockLightOpacity[c.GetBlock(x, y-1, z)] == 0 {
+				y--
+				c.setLight(x, y, z, fullSky)
+			}
+			lowest[x][z] = y
+		}
+	}
+	for x := range ChunkSizeX {
+		for z := range ChunkSizeZ {
+			y := lowest[x][z]
+			if y >= ChunkSizeY {
+				continue
+			}
+			reach := y + 1
+			for _, d := range lightDirs[:4] {
+				nx, nz := x+d[0], z+d[2]
+				if nx >= 0 && nx < ChunkSizeX && nz >= 0 && nz < ChunkSizeZ {
+					reach = max(reach, lowest[nx][nz])
+				}
+			}
+			for ; y < reach && y < ChunkSizeY; y++ {
+				l.queue = append(l.queue, lightNode{x0 + x, y, z0 + z, MaxLight})
+			}
+		}
+	}
+	l.propagate(true)
+
+	for secIdx := range NumSections {
+		if c.SectionAllMatch(secIdx, &dark) {
+			continue
+		}
+		for x := range ChunkSizeX {
+			for z := range ChunkSizeZ {
+				for y := secIdx * SectionHeight; y < (secIdx+1)*SectionHeight; y++ {
+					if e := BlockLightEmission[c.GetBlock(x, y, z)]; e > 0 {
+						c.setLight(x, y, z, c.Light(x, y, z)&^MaxLight|e)
+						l.queue = append(l.queue, lightNode{x0 + x, y, z0 + z, e})
+					}
+				}
+			}
+		}
+	}
+	l.propagate(false)
+}
+
+// stitchLight lets light flow between c, just added to the store, and its
+// loaded neighbours. Call with lightMu held.
+func (cs *ChunkStore) stitchLight(c *Chunk) {
+	l := cs.lighter
+	for _, d := range lightDirs[:4] {
+		nb := cs.GetChunk(c.X+d[0], c.Y, c.Z+d[2], false)
+		if nb == nil {
+			continue
+		}
+		// Queue the blocks on both sides of the shared face
+		for secIdx := range NumSections {
+			if c.light[secIdx].Load() == nil && nb.light[secIdx].Load() == nil && c.lightFill[secIdx] == nb.lightFill[secIdx] {
+				continue // uniform and equal on both sides: nothing to flow
+			}
+			for _, side := range [2]*Chunk{c, nb} {
+				lx, lz := 0, 0
+				switch {
+				case side == c && d[0] > 0, side == nb && d[0] < 0:
+					lx = ChunkSizeX - 1
+				case side == c && d[2] > 0, side == nb && d[2] < 0:
+					lz = ChunkSizeZ - 1
+				}
+				for i := range ChunkSizeX {
+					x, z := lx, lz
+					if d[0] != 0 {
+						z = i
+					} else {
+						x = i
+					}
+					for y := secIdx * SectionHeight; y < (secIdx+1)*SectionHeight; y++ {
+						if side.Light(x, y, z) != 0 {
+							l.queue = append(l.queue, lightNode{side.X*ChunkSizeX + x, y, side.Z*ChunkSizeZ + z, 0})
+						}
+					}
+				}
+			}
+		}
+	}
+	queued := append([]lightNode(nil), l.queue...)
+	l.propagate(true)
+	l.queue = append(l.queue, queued...)
+	l.propagate(false)
+	l.finish(cs.onRelight)
+}
+
+// relight updates light around (x, y, z) after its block changed from old
+// to the block there now.
+func (cs *ChunkStore) relight(x, y, z int, old BlockType) {
+	now := cs.Get(x, y, z)
+	if BlockLightOpacity[old] == BlockLightOpacity[now] && BlockLightEmission[old] == BlockLightEmission[now] {
+		return
+	}
+	cs.lightMu.Lock()
+	defer cs.lightMu.Unlock()
+	cs.lighter.update(x, y, z)
+	cs.lighter.finish(cs.onRelight)
+}
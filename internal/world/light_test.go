@@ -0,0 +1,136 @@
+package world
+
+import "testing"
+
+// withLightTables gives stone and lava their registry light values for the
+// length of a test; the world package does not import the registry.
+func withLightTables(t *testing.T) {
+	emission, opacity := BlockLightEmission, BlockLightOpacity
+	t.Cleanup(func() { BlockLightEmission, BlockLightOpacity = emission, opacity })
+	BlockLightOpacity[BlockTypeStone] = MaxLight
+	BlockLightOpacity[BlockTypeWater] = 3
+	BlockLightEmission[BlockTypeLava] = MaxLight
+}
+
+// rockChunk returns a chunk of stone up to y=69 with a sealed cave at
+// x 4-10, y 55-58, z 4-10, lit on its own.
+func rockChunk(cx, cz int) *Chunk {
+	c := NewChunk(cx, 0, cz)
+	for x := range ChunkSizeX {
+		for z := range ChunkSizeZ {
+			for y := range 70 {
+				c.SetBlockFast(x, y, z, BlockTypeStone)
+			}
+		}
+	}
+	for x := 4; x <= 10; x++ {
+		for z := 4; z <= 10; z++ {
+			for y := 55; y <= 58; y++ {
+				c.SetBlockFast(x, y, z, BlockTypeAir)
+			}
+		}
+	}
+	c.compact()
+	c.initLight()
+	return c
+}
+
+func TestChunkLightSkyAndCaves(t *testing.T) {
+	withLightTables(t)
+	c := rockChunk(0, 0)
+
+	if got := c.Light(8, 70, 8) >> 4; got != MaxLight {
+		t.Errorf("sky light above ground = %d, want %d", got, MaxLight)
+	}
+	if got := c.Light(8, 56, 8); got != 0 {
+		t.Errorf("light in sealed cave = %#x, want 0", got)
+	}
+	if c.light[NumSections-1].Load() != nil {
+		t.Error("open sky section was given a light array")
+	}
+}
+
+func TestChunkLightWaterAndEmitters(t *testing.T) {
+	withLightTables(t)
+	c := NewChunk(0, 0, 0)
+	for x := range ChunkSizeX {
+		for z := range ChunkSizeZ {
+			for y := 60; y < 64; y++ {
+				c.SetBlockFast(x, y, z, BlockTypeWater)
+			}
+		}
+	}
+	c.SetBlockFast(12, 20, 12, BlockTypeLava)
+	c.initLight()
+
+	for y, want := range map[int]uint8{63: 12, 62: 9, 60: 3} {
+		if got := c.Light(3, y, 3) >> 4; got != want {
+			t.Errorf("sky light in water at y=%d = %d, want %d", y, got, want)
+		}
+	}
+	for d, want := range []uint8{15, 14, 13, 12} {
+		if got := c.Light(12-d, 20, 12) & MaxLight; got != want {
+			t.Errorf("block light %d from lava = %d, want %d", d, got, want)
+		}
+	}
+}
+
+func TestStoreLightUpdatesAndBorders(t *testing.T) {
+	withLightTables(t)
+	store := NewChunkStore()
+	store.AddChunk(ChunkCoord{}, rockChunk(0, 0))
+
+	// Opening the roof lets sky light fall straight to the cave floor
+	for y := 59; y < 70; y++ {
+		store.Set(7, y, 7, BlockTypeAir)
+	}
+	c := store.GetChunk(0, 0, 0, false)
+	if got := c.Light(7, 55, 7) >> 4; got != MaxLight {
+		t.Errorf("sky light under the opening = %d, want %d", got, MaxLight)
+	}
+	if got := c.Light(4, 55, 4) >> 4; got != MaxLight-6 {
+		t.Errorf("sky light in the cave corner = %d, want %d", got, MaxLight-6)
+	}
+
+	// Closing it again takes the light away
+	store.Set(7, 69, 7, BlockTypeStone)
+	if got := c.Light(7, 55, 7); got != 0 {
+		t.Errorf("light after closing the roof = %#x, want 0", got)
+	}
+
+	// A tunnel to the chunk border takes light from an open neighbour
+	for x := 11; x < ChunkSizeX; x++ {
+		store.Set(x, 56, 8, BlockTypeAir)
+	}
+	store.AddChunk(ChunkCoord{X: 1}, NewChunk(1, 0, 0))
+	if got := c.Light(15, 56, 8) >> 4; got != MaxLight-1 {
+		t.Errorf("sky light at the tunnel mouth = %d, want %d", got, MaxLight-1)
+	}
+	if got := c.Light(10, 56, 8) >> 4; got != MaxLight-6 {
+		t.Errorf("sky light in the cave = %d, want %d", got, MaxLight-6)
+	}
+}
+
+func TestStoreHandsRelitChunksOn(t *testing.T) {
+	withLightTables(t)
+	store := NewChunkStore()
+	store.AddChunk(ChunkCoord{}, rockChunk(0, 0))
+	store.AddChunk(ChunkCoord{X: 1}, rockChunk(1, 0))
+	relit := make(map[ChunkCoord]bool)
+	store.onRelight = func(coords ...ChunkCoord) {
+		for _, c := range coords {
+			relit[c] = true
+		}
+	}
+	neighbour := store.GetChunk(1, 0, 0, false)
+	neighbour.SetClean()
+
+	// Lava by the border lights into the next chunk, whose blocks stay the same
+	store.Set(14, 70, 8, BlockTypeLava)
+	if !relit[ChunkCoord{X: 1}] || !relit[ChunkCoord{}] {
+		t.Errorf("relit chunks = %v, want both", relit)
+	}
+	if neighbour.IsDirty() {
+		t.Error("relit neighbour was marked dirty rather than handed on")
+	}
+}
@@ -2,6 +2,8 @@ package world
 
 import (
 	"math"
+
+	"mini-mc/internal/mathutil"
 )
 
 // Simple deterministic 2D value noise with multiple octaves.
@@ -13,21 +15,6 @@ func fade(t float64) float64 {
 	return t * t * t * (t*(t*6-15) + 10)
 }
 
-func lerp(a, b, t float64) float64 {
-	return a + t*(b-a)
-}
-
-// lerp performs linear interpolation
-// Note: This is also defined in density.go, but Go treats package-level funcs as same scope.
-// We should remove one of them. For now, let's keep it here and remove from density.go if needed
-// or just rename it. If we have multiple files in same package, they share scope.
-// I will rename this one to lerpNoise just in case or assume the one in density.go will be removed.
-// Actually, I should check if I broke the build.
-// Best to keep helper functions unexported.
-// The previous error said "lerp redeclared".
-// I'll use the one defined in density.go if I keep it there, or here.
-// Let's use noise.go as the source of truth for noise functions.
-
 func hash2(x int64, z int64, seed int64) uint64 {
 	// SplitMix64 style integer hash, stable across runs for same inputs
 	v := uint64(x) + (uint64(z) << 1) + uint64(seed)*0x9E3779B97F4A7C15
@@ -60,9 +47,9 @@ func valueNoise2D(x float64, z float64, seed int64) float64 {
 	v01 := latticeValue(int64(x0), int64(z1), seed)
 	v11 := latticeValue(int64(x1), int64(z1), seed)
 
-	i0 := lerp(v00, v10, fx)
-	i1 := lerp(v01, v11, fx)
-	return lerp(i0, i1, fz) // [0,1]
+	i0 := mathutil.Lerp(v00, v10, fx)
+	i1 := mathutil.Lerp(v01, v11, fx)
+	return mathutil.Lerp(i0, i1, fz) // [0,1]
 }
 
 func octaveNoise2D(x float64, z float64, seed int64, octaves int, persistence, lacunarity float64) float64 {
@@ -128,17 +115,17 @@ func valueNoise3D(x, y, z float64, seed int64) float64 {
 
 	// Trilinear interpolation
 	// First interpolate along X (4 results)
-	i00 := lerp(v000, v100, fx)
-	i10 := lerp(v010, v110, fx)
-	i01 := lerp(v001, v101, fx)
-	i11 := lerp(v011, v111, fx)
+	i00 := mathutil.Lerp(v000, v100, fx)
+	i10 := mathutil.Lerp(v010, v110, fx)
+	i01 := mathutil.Lerp(v001, v101, fx)
+	i11 := mathutil.Lerp(v011, v111, fx)
 
 	// Then interpolate along Y (2 results)
-	i0 := lerp(i00, i10, fy)
-	i1 := lerp(i01, i11, fy)
+	i0 := mathutil.Lerp(i00, i10, fy)
+	i1 := mathutil.Lerp(i01, i11, fy)
 
 	// Finally interpolate along Z (1 result)
-	return lerp(i0, i1, fz) // [0,1]
+	return mathutil.Lerp(i0, i1, fz) // [0,1]
 }
 
 func octaveNoise3D(x, y, z float64, seed int64, octaves int, persistence, lacunarity float64) float64 {
@@ -0,0 +1,29 @@
+package world
+
+// IsPlant reports whether bt is a small plant: tall grass or a flower. Plants
+// are drawn as two crossed quads, grow on grass or dirt and pop off when the
+// block under them goes.
+func IsPlant(bt BlockType) bool {
+	switch bt {
+	case BlockTypeTallGrass, BlockTypeDandelion, BlockTypePoppy:
+		return true
+	}
+	return false
+}
+
+// plantSoil reports whether a plant can grow on bt.
+func plantSoil(bt BlockType) bool {
+	return bt == BlockTypeGrass || bt == BlockTypeDirt
+}
+
+// CanPlacePlant reports whether a plant placed at (x, y, z) would be supported.
+func (w *World) CanPlacePlant(x, y, z int) bool {
+	return plantSoil(w.Get(x, y-1, z))
+}
+
+// notifyPlantAbove removes a plant left without soil by a change at (x, y, z).
+func notifyPlantAbove(w *World, x, y, z int) {
+	if IsPlant(w.Get(x, y+1, z)) && !plantSoil(w.Get(x, y, z)) {
+		w.Set(x, y+1, z, BlockTypeAir)
+	}
+}
@@ -0,0 +1,157 @@
+package world
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"mini-mc/internal/mathutil"
+)
+
+// PregenProgress is a snapshot of a pregeneration job. Work is counted in
+// chunk columns.
+type PregenProgress struct {
+	Done, Total int
+	Elapsed     time.Duration
+	Finished    bool  // all columns done, or the job was cancelled
+	Err         error // first save error, if any
+}
+
+// Fraction returns the share of columns done, from 0 to 1.
+func (p PregenProgress) Fraction() float64 {
+	if p.Total == 0 {
+		return 1
+	}
+	return float64(p.Done) / float64(p.Total)
+}
+
+// ETA estimates the time left from the average rate so far. It is zero
+// until the first column is done.
+func (p PregenProgress) ETA() time.Duration {
+	if p.Done == 0 || p.Finished {
+		return 0
+	}
+	perColumn := p.Elapsed / time.Duration(p.Done)
+	return perColumn * time.Duration(p.Total-p.Done)
+}
+
+// PregenJob generates and saves every chunk in a square of columns in the
+// background, one worker per CPU.
+type PregenJob struct {
+	total   int
+	done    atomic.Int64
+	start   time.Time
+	cancel  chan struct{}
+	stop    sync.Once
+	errOnce sync.Once
+	err     error
+
+	finished chan struct{} // closed once every worker has stopped
+	end      time.Time     // set before finished is closed
+}
+
+// Pregenerate starts generating and saving all chunks within radius columns
+// of chunk column (cx, cz). Chunks that are loaded or already saved are
+// skipped, so a job can be run again to resume. Pregenerated chunks
+// are saved with their generator hash; they load like edited chunks but are
+// dropped again if edits ever bring them back to generator output.
+func (w *World) Pregenerate(cx, cz, radius int) (*PregenJob, error) {
+	if w.saves == nil {
+		return nil, errors.New("world is not saved")
+	}
+	if radius < 0 {
+		return nil, errors.New("pregen radius must not be negative")
+	}
+	side := 2*radius + 1
+	j := &PregenJob{
+		total:    side * side,
+		start:    time.Now(),
+		cancel:   make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+
+	columns := make(chan [2]int)
+	go func() {
+		defer close(columns)
+		for dx := -radius; dx <= radius; dx++ {
+			for dz := -radius; dz <= radius; dz++ {
+				select {
+				case columns <- [2]int{cx + dx, cz + dz}:
+				case <-j.cancel:
+					return
+				}
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	workers := max(runtime.NumCPU(), 1)
+	wg.Add(workers)
+	for range workers {
+		go func() {
+			defer wg.Done()
+			for col := range columns {
+				w.pregenColumn(j, col[0], col[1])
+				j.done.Add(1)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		j.end = time.Now()
+		close(j.finished)
+	}()
+	return j, nil
+}
+
+// pregenColumn generates and saves the chunks of one column.
+func (w *World) pregenColumn(j *PregenJob, chunkX, chunkZ int) {
+	worldX := chunkX*ChunkSizeX + ChunkSizeX/2
+	worldZ := chunkZ*ChunkSizeZ + ChunkSizeZ/2
+	maxChunkY := max(mathutil.FloorDiv(w.gen.HeightAt(worldX, worldZ), ChunkSizeY), 0)
+	for cy := 0; cy <= maxChunkY; cy++ {
+		coord := ChunkCoord{X: chunkX, Y: cy, Z: chunkZ}
+		if w.store.HasChunk(coord) {
+			continue // saved through the normal path when evicted
+		}
+		if w.saves.has(coord) {
+			continue
+		}
+		c := NewChunk(coord.X, coord.Y, coord.Z)
+		w.gen.PopulateChunk(c)
+		c.genHash = c.ContentHash()
+		if w.decorations != nil {
+			applyPending(c, w.decorations.take(coord))
+		}
+		if err := w.saves.write(coord, c); err != nil {
+			j.errOnce.Do(func() { j.err = err })
+		}
+	}
+}
+
+// Progress returns the job's current progress.
+func (j *PregenJob) Progress() PregenProgress {
+	p := PregenProgress{Done: int(j.done.Load()), Total: j.total}
+	select {
+	case <-j.finished:
+		p.Finished = true
+		p.Elapsed = j.end.Sub(j.start)
+		p.Err = j.err
+	default:
+		p.Elapsed = time.Since(j.start)
+	}
+	return p
+}
+
+// Cancel stops handing out columns. Columns already being generated finish.
+func (j *PregenJob) Cancel() {
+	j.stop.Do(func() { close(j.cancel) })
+}
+
+// Wait blocks until the job has finished or been cancelled.
+func (j *PregenJob) Wait() PregenProgress {
+	<-j.finished
+	return j.Progress()
+}
@@ -0,0 +1,59 @@
+package world
+
+import (
+	"testing"
+)
+
+func TestPregenerateSavesGeneratedChunks(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	job, err := w.Pregenerate(0, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := job.Wait()
+	if p.Err != nil || !p.Finished || p.Done != 9 || p.Total != 9 || p.Fraction() != 1 {
+		t.Fatalf("progress = %+v", p)
+	}
+	coord := ChunkCoord{X: 1, Y: 0, Z: -1}
+	if !w.saves.has(coord) {
+		t.Fatalf("chunk %v not saved", coord)
+	}
+
+	// The saved copy matches what the generator would build, apart from
+	// trees reaching in from the neighbours generated around it.
+	want := NewChunk(coord.X, coord.Y, coord.Z)
+	w.gen.PopulateChunk(want)
+	got := w.loadSavedChunk(coord)
+	if got == nil || !matchesGenerated(got, want) || got.genHash != want.ContentHash() {
+		t.Fatal("pregenerated chunk differs from generator output")
+	}
+	if got.needsSave() {
+		t.Fatal("loaded pregenerated chunk wants saving")
+	}
+
+	if _, err := NewEmpty().Pregenerate(0, 0, 1); err == nil {
+		t.Error("pregenerating an unsaved world succeeded")
+	}
+}
+
+// matchesGenerated reports whether got holds the blocks of want, except where
+// a neighbour's decoration replaced air or a plant.
+func matchesGenerated(got, want *Chunk) bool {
+	for x := range ChunkSizeX {
+		for y := range ChunkSizeY {
+			for z := range ChunkSizeZ {
+				g, w := got.GetBlock(x, y, z), want.GetBlock(x, y, z)
+				if g != w && !decorationReplaces(w) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,205 @@
+package world
+
+// Rails store their shape in metadata. Directions use the BlockFace names of
+// facing.go: north is +Z and east is +X.
+
+// RailShape is the track layout of a rail block.
+type RailShape uint8
+
+const (
+	RailNorthSouth RailShape = iota
+	RailEastWest
+	RailAscendingNorth // climbs towards +Z
+	RailAscendingSouth
+	RailAscendingEast
+	RailAscendingWest
+	RailNorthEast // curve joining the +Z and +X edges
+	RailNorthWest
+	RailSouthWest
+	RailSouthEast
+
+	NumRailShapes
+)
+
+// railExits lists the two faces each shape connects, in path order.
+var railExits = [NumRailShapes][2]BlockFace{
+	RailNorthSouth:     {FaceSouth, FaceNorth},
+	RailEastWest:       {FaceWest, FaceEast},
+	RailAscendingNorth: {FaceSouth, FaceNorth},
+	RailAscendingSouth: {FaceNorth, FaceSouth},
+	RailAscendingEast:  {FaceWest, FaceEast},
+	RailAscendingWest:  {FaceEast, FaceWest},
+	RailNorthEast:      {FaceNorth, FaceEast},
+	RailNorthWest:      {FaceNorth, FaceWest},
+	RailSouthWest:      {FaceSouth, FaceWest},
+	RailSouthEast:      {FaceSouth, FaceEast},
+}
+
+// Exits returns the two faces the rail connects. For ascending rails b is the
+// raised end.
+func (s RailShape) Exits() (a, b BlockFace) {
+	if s >= NumRailShapes {
+		s = RailNorthSouth
+	}
+	return railExits[s][0], railExits[s][1]
+}
+
+// IsAscending reports whether the rail climbs one block towards its second exit.
+func (s RailShape) IsAscending() bool {
+	return s >= RailAscendingNorth && s <= RailAscendingWest
+}
+
+// IsCurve reports whether the rail turns a corner.
+func (s RailShape) IsCurve() bool {
+	return s >= RailNorthEast && s < NumRailShapes
+}
+
+// hasExit reports whether the shape connects through face f.
+func (s RailShape) hasExit(f BlockFace) bool {
+	a, b := s.Exits()
+	return a == f || b == f
+}
+
+var railHorizontalFaces = [4]BlockFace{FaceNorth, FaceSouth, FaceEast, FaceWest}
+
+// canRailRestOn reports whether a rail can sit at (x, y, z).
+func canRailRestOn(w *World, x, y, z int) bool {
+	return BlockSolidTable[w.Get(x, y-1, z)]
+}
+
+// CanPlaceRail reports whether a rail placed at (x, y, z) would be supported.
+func (w *World) CanPlaceRail(x, y, z int) bool {
+	return canRailRestOn(w, x, y, z)
+}
+
+// railNeighbor finds the rail beyond face f of (x, y, z): level with it, one
+// block up (this rail would climb to it) or one block down (it climbs here).
+func (w *World) railNeighbor(x, y, z int, f BlockFace) (nx, ny, nz int, ok bool) {
+	dx, dz := FacingOffset(uint8(f))
+	for _, dy := range [3]int{0, 1, -1} {
+		if w.Get(x+dx, y+dy, z+dz) == BlockTypeRail {
+			return x + dx, y + dy, z + dz, true
+		}
+	}
+	return 0, 0, 0, false
+}
+
+// railLinked reports whether the rail at (x, y, z) is joined through face f to
+// a rail that connects back.
+func (w *World) railLinked(x, y, z int, f BlockFace) bool {
+	if !RailShape(w.GetMeta(x, y, z)).hasExit(f) {
+		return false
+	}
+	nx, ny, nz, ok := w.railNeighbor(x, y, z, f)
+	return ok && RailShape(w.GetMeta(nx, ny, nz)).hasExit(f.Opposite())
+}
+
+// railCanLink reports whether the rail at (x, y, z) could connect through
+// face f: it already points that way or has an end that is not linked.
+func (w *World) railCanLink(x, y, z int, f BlockFace) bool {
+	shape := RailShape(w.GetMeta(x, y, z))
+	if shape.hasExit(f) {
+		return true
+	}
+	a, b := shape.Exits()
+	return !w.railLinked(x, y, z, a) || !w.railLinked(x, y, z, b)
+}
+
+// railShapeFor returns the shape joining faces f1 and f2 at (x, y, z). Equal
+// or opposite faces give a straight rail, which climbs towards a rail one
+// block up at either end.
+func (w *World) railShapeFor(x, y, z int, f1, f2 BlockFace) RailShape {
+	if f1 == f2 || f2 == f1.Opposite() {
+		for _, f := range [2]BlockFace{f1, f1.Opposite()} {
+			dx, dz := FacingOffset(uint8(f))
+			if w.Get(x+dx, y+1, z+dz) == BlockTypeRail {
+				switch f {
+				case FaceNorth:
+					return RailAscendingNorth
+				case FaceSouth:
+					return RailAscendingSouth
+				case FaceEast:
+					return RailAscendingEast
+				default:
+					return RailAscendingWest
+				}
+			}
+		}
+		if f1 == FaceEast || f1 == FaceWest {
+			return RailEastWest
+		}
+		return RailNorthSouth
+	}
+	for s := RailNorthEast; s < NumRailShapes; s++ {
+		if s.hasExit(f1) && s.hasExit(f2) {
+			return s
+		}
+	}
+	return RailNorthSouth
+}
+
+// UpdateRailShape connects the rail at (x, y, z) to the rails around it, as
+// MC's BlockRailBase does on placement: neighbours already pointing here come
+// first, straight track is preferred over curves, and neighbours with a free
+// end are bent towards this rail. A rail with no neighbours keeps its shape.
+func (w *World) UpdateRailShape(x, y, z int) {
+	if w.Get(x, y, z) != BlockTypeRail {
+		return
+	}
+
+	var linked, free []BlockFace
+	for _, f := range railHorizontalFaces {
+		nx, ny, nz, ok := w.railNeighbor(x, y, z, f)
+		if !ok {
+			continue
+		}
+		back := f.Opposite()
+		if RailShape(w.GetMeta(nx, ny, nz)).hasExit(back) {
+			linked = append(linked, f)
+		} else if w.railCanLink(nx, ny, nz, back) {
+			free = append(free, f)
+		}
+	}
+	candidates := append(linked, free...)
+	if len(candidates) == 0 {
+		return
+	}
+
+	f1, f2 := candidates[0], candidates[0]
+	if len(candidates) > 1 {
+		f2 = candidates[1]
+		for _, f := range candidates[1:] {
+			if f == f1.Opposite() {
+				f2 = f
+				break
+			}
+		}
+	}
+	shape := w.railShapeFor(x, y, z, f1, f2)
+	w.SetWithMeta(x, y, z, BlockTypeRail, uint8(shape))
+
+	// Bend neighbours that do not point back yet
+	a, b := shape.Exits()
+	for _, f := range [2]BlockFace{a, b} {
+		nx, ny, nz, ok := w.railNeighbor(x, y, z, f)
+		back := f.Opposite()
+		if !ok || RailShape(w.GetMeta(nx, ny, nz)).hasExit(back) || !w.railCanLink(nx, ny, nz, back) {
+			continue
+		}
+		keep := back
+		na, nb := RailShape(w.GetMeta(nx, ny, nz)).Exits()
+		if w.railLinked(nx, ny, nz, na) {
+			keep = na
+		} else if w.railLinked(nx, ny, nz, nb) {
+			keep = nb
+		}
+		w.SetWithMeta(nx, ny, nz, BlockTypeRail, uint8(w.railShapeFor(nx, ny, nz, keep, back)))
+	}
+}
+
+// notifyRailAbove removes a rail left without support by a change at (x, y, z).
+func notifyRailAbove(w *World, x, y, z int) {
+	if w.Get(x, y+1, z) == BlockTypeRail && !canRailRestOn(w, x, y+1, z) {
+		w.Set(x, y+1, z, BlockTypeAir)
+	}
+}
@@ -0,0 +1,53 @@
+package world
+
+import "testing"
+
+// placeRail puts a rail on a stone floor and connects it, as the player does.
+func placeRail(w *World, x, y, z int) {
+	w.Set(x, y-1, z, BlockTypeStone)
+	w.SetWithMeta(x, y, z, BlockTypeRail, uint8(RailNorthSouth))
+	w.UpdateRailShape(x, y, z)
+}
+
+func railShapeAt(w *World, x, y, z int) RailShape {
+	return RailShape(w.GetMeta(x, y, z))
+}
+
+func TestRailConnectsIntoCurve(t *testing.T) {
+	w := NewEmpty()
+	placeRail(w, 0, 61, 0)
+	placeRail(w, 1, 61, 0) // east of the first: both become east-west
+	if got := railShapeAt(w, 0, 61, 0); got != RailEastWest {
+		t.Fatalf("first rail = %d, want east-west", got)
+	}
+
+	placeRail(w, 0, 61, 1) // north (+Z) of the first: it bends into a curve
+	if got := railShapeAt(w, 0, 61, 0); got != RailNorthEast {
+		t.Fatalf("corner rail = %d, want north-east curve", got)
+	}
+	if got := railShapeAt(w, 0, 61, 1); got != RailNorthSouth {
+		t.Fatalf("new rail = %d, want north-south", got)
+	}
+}
+
+func TestRailAscendsToHigherNeighbour(t *testing.T) {
+	w := NewEmpty()
+	placeRail(w, 0, 61, 0)
+	placeRail(w, 1, 62, 0)
+	if got := railShapeAt(w, 0, 61, 0); got != RailAscendingEast {
+		t.Fatalf("lower rail = %d, want ascending east", got)
+	}
+	if got := railShapeAt(w, 1, 62, 0); got != RailEastWest {
+		t.Fatalf("upper rail = %d, want east-west", got)
+	}
+}
+
+func TestRailPopsOffWithoutSupport(t *testing.T) {
+	w := NewEmpty()
+	placeRail(w, 0, 61, 0)
+	w.Set(0, 60, 0, BlockTypeAir)
+	w.NotifyNeighbors(0, 60, 0)
+	if got := w.Get(0, 61, 0); got != BlockTypeAir {
+		t.Fatalf("unsupported rail remained: %v", got)
+	}
+}
@@ -0,0 +1,211 @@
+package world
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// Saved chunks live in region files of regionSize x regionSize chunk
+// columns, like 1.8.9's region format. A file starts with a header of one
+// entry per chunk, the sector the chunk's data starts at and its length in
+// bytes, followed by 4 KiB sectors of gzip-compressed chunk data. A chunk is
+// rewritten into free sectors before its header entry is updated, so a
+// crash mid-write leaves the previous copy intact.
+const (
+	regionShift   = 5
+	regionSize    = 1 << regionShift // chunk columns along each side
+	regionEntries = regionSize * regionSize
+
+	regionSectorSize    = 4096
+	regionEntrySize     = 8
+	regionHeaderSectors = regionEntries * regionEntrySize / regionSectorSize
+)
+
+// regionEntry locates a chunk in its region file. A zero offset means the
+// chunk is not saved.
+type regionEntry struct {
+	offset uint32 // first sector
+	length uint32 // bytes
+}
+
+func (e regionEntry) sectors() int {
+	return (int(e.length) + regionSectorSize - 1) / regionSectorSize
+}
+
+// regionFile is one open region file. It is safe for concurrent use.
+type regionFile struct {
+	mu     sync.Mutex
+	f      *os.File
+	header [regionEntries]regionEntry
+	used   []bool // by sector, the header's included
+}
+
+// regionIndex returns the header index of a chunk column within its region.
+func regionIndex(chunkX, chunkZ int) int {
+	return (chunkZ&(regionSize-1))<<regionShift | chunkX&(regionSize-1)
+}
+
+// openRegionFile opens or creates a region file. Header entries pointing
+// outside the file or into another chunk's sectors are dropped, so those
+// chunks are generated again.
+func openRegionFile(path string) (*regionFile, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	r := &regionFile{f: f, used: make([]bool, regionHeaderSectors)}
+	for i := range r.used {
+		r.used[i] = true
+	}
+
+	var buf [regionEntries * regionEntrySize]byte
+	n, err := io.ReadFull(f, buf[:])
+	switch {
+	case n == 0 && errors.Is(err, io.EOF):
+		if _, err := f.WriteAt(buf[:], 0); err != nil {
+			f.Close()
+			return nil, err
+		}
+		return r, nil
+	case err != nil:
+		f.Close()
+		return nil, fmt.Errorf("region file %s: reading header: %w", path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	fileSectors := int((info.Size() + regionSectorSize - 1) / regionSectorSize)
+	for i := range r.header {
+		e := regionEntry{
+			offset: binary.LittleEndian.Uint32(buf[i*regionEntrySize:]),
+			length: binary.LittleEndian.Uint32(buf[i*regionEntrySize+4:]),
+		}
+		if e.offset == 0 {
+			continue
+		}
+		first, n := int(e.offset), e.sectors()
+		if first < regionHeaderSectors || n == 0 || first+n > fileSectors || r.overlaps(first, n) {
+			slog.Warn("dropping bad region entry", "file", path, "index", i)
+			continue
+		}
+		r.header[i] = e
+		r.mark(first, n, true)
+	}
+	return r, nil
+}
+
+func (r *regionFile) overlaps(first, n int) bool {
+	for s := first; s < first+n && s < len(r.used); s++ {
+		if r.used[s] {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *regionFile) mark(first, n int, used bool) {
+	for len(r.used) < first+n {
+		r.used = append(r.used, false)
+	}
+	for s := first; s < first+n; s++ {
+		r.used[s] = used
+	}
+}
+
+// allocate returns the first run of n free sectors, possibly past the end
+// of the file.
+func (r *regionFile) allocate(n int) int {
+	run := 0
+	for s := regionHeaderSectors; s < len(r.used); s++ {
+		if r.used[s] {
+			run = 0
+			continue
+		}
+		run++
+		if run == n {
+			return s - n + 1
+		}
+	}
+	return len(r.used) - run
+}
+
+// has reports whether the chunk at index i is saved.
+func (r *regionFile) has(i int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.header[i].offset != 0
+}
+
+// read returns the data of the chunk at index i, or nil if it is not saved.
+func (r *regionFile) read(i int) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := r.header[i]
+	if e.offset == 0 {
+		return nil, nil
+	}
+	data := make([]byte, e.length)
+	if _, err := r.f.ReadAt(data, int64(e.offset)*regionSectorSize); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// write stores data as the chunk at index i.
+func (r *regionFile) write(i int, data []byte) error {
+	if len(data) == 0 {
+		return errors.New("empty chunk data")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := regionEntry{length: uint32(len(data))}
+	first := r.allocate(e.sectors())
+	e.offset = uint32(first)
+
+	// Pad to whole sectors so the next chunk appended starts on a boundary
+	padded := make([]byte, e.sectors()*regionSectorSize)
+	copy(padded, data)
+	if _, err := r.f.WriteAt(padded, int64(first)*regionSectorSize); err != nil {
+		return err
+	}
+	r.mark(first, e.sectors(), true)
+	return r.setEntry(i, e)
+}
+
+// remove drops the chunk at index i.
+func (r *regionFile) remove(i int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.header[i].offset == 0 {
+		return nil
+	}
+	return r.setEntry(i, regionEntry{})
+}
+
+// setEntry writes header entry i and frees the sectors it pointed to.
+func (r *regionFile) setEntry(i int, e regionEntry) error {
+	var buf [regionEntrySize]byte
+	binary.LittleEndian.PutUint32(buf[:], e.offset)
+	binary.LittleEndian.PutUint32(buf[4:], e.length)
+	if _, err := r.f.WriteAt(buf[:], int64(i*regionEntrySize)); err != nil {
+		return err
+	}
+	if old := r.header[i]; old.offset != 0 {
+		r.mark(int(old.offset), old.sectors(), false)
+	}
+	r.header[i] = e
+	return nil
+}
+
+func (r *regionFile) close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Close()
+}
@@ -0,0 +1,208 @@
+package world
+
+import (
+	"math"
+
+	"mini-mc/internal/rng"
+)
+
+// River and lake decoration for ChunkProvider189.
+//
+// Rivers are carved from a ridged 2D noise: the zero-crossings of a smooth
+// noise field form long, winding lines, and columns close to those lines are
+// cut down to just below sea level and flooded. Carving runs before
+// replaceSurface so the freshly exposed columns still get biome surface
+// blocks; the carved channel and its banks are flagged so replaceSurface can
+// lay sand there instead.
+//
+// Lakes are a chunk-local port of MC 1.8.9 WorldGenLakes: a blob built from a
+// handful of random ellipsoids, rejected if it would leak, then filled with
+// water in its lower half.
+
+const (
+	riverNoiseScale = 384.0 // horizontal size of river meanders (blocks)
+	riverNoiseSeed  = 577   // seed offset so rivers don't follow biome noise
+	riverWidth      = 0.028 // ridge threshold: wider value = wider channel
+	riverBankWidth  = 0.018 // extra ridge band outside the channel that gets sand
+	riverBedDepth   = 3     // channel floor sits this far below sea level at its centre
+	riverMaxCut     = 12    // skip carving where terrain rises more than this above sea level
+
+	lakeChance     = 4 // one lake attempt per this many chunks (MC waterLakeChance)
+	lakeBlobHeight = 8 // vertical extent of the lake blob mask
+)
+
+// riverRidge returns the distance of (x, z) from the nearest river centre line
+// in noise space: 0 on the centre line, growing towards 1 away from it.
+func riverRidge(x, z float64, seed int64) float64 {
+	n := octaveNoise2D(x/riverNoiseScale, z/riverNoiseScale, seed+riverNoiseSeed, 3, 0.5, 2.0)
+	return math.Abs(n*2.0 - 1.0)
+}
+
+// isOceanBiome reports whether rivers and lakes should be suppressed for the biome.
+func isOceanBiome(b *Biome) bool {
+	return b == BiomeOcean || b == BiomeDeepOcean
+}
+
+// carveRivers cuts river channels into the raw stone/water terrain.
+// heightMap is updated so replaceSurface starts from the new surface, and
+// sandMask marks columns that belong to a channel or its banks.
+func (cp *ChunkProvider189) carveRivers(c *Chunk, xChunk, zChunk int, surfaceBiomes *[256]*Biome, heightMap *[256]int16, sandMask *[256]bool) {
+	for lx := 0; lx < ChunkSizeX; lx++ {
+		for lz := 0; lz < ChunkSizeZ; lz++ {
+			idx := lx*16 + lz
+			sandMask[idx] = false
+
+			if isOceanBiome(surfaceBiomes[idx]) {
+				continue
+			}
+
+			surfaceY := int(heightMap[idx])
+			if surfaceY < seaLevel-riverBedDepth || surfaceY > seaLevel+riverMaxCut {
+				continue
+			}
+
+			worldX := float64(xChunk*ChunkSizeX + lx)
+			worldZ := float64(zChunk*ChunkSizeZ + lz)
+			ridge := riverRidge(worldX, worldZ, cp.seed)
+
+			if ridge >= riverWidth+riverBankWidth {
+				continue
+			}
+			if ridge >= riverWidth {
+				// Bank: keep terrain, but only low banks turn to sand.
+				if surfaceY <= seaLevel+2 {
+					sandMask[idx] = true
+				}
+				continue
+			}
+
+			// Channel: depth eases from 1 block at the edge to riverBedDepth at the centre.
+			t := 1.0 - ridge/riverWidth
+			floorY := seaLevel - 2 - int(t*float64(riverBedDepth-1)+0.5)
+
+			// Fill up to sea level even where the terrain was already low, so the
+			// water surface stays flat along the whole channel.
+			topY := max(surfaceY, seaLevel-1)
+			for y := topY; y > floorY; y-- {
+				if y < seaLevel {
+					c.SetBlockFast(lx, y, lz, BlockTypeWater)
+				} else {
+					c.SetBlockFast(lx, y, lz, BlockTypeAir)
+				}
+			}
+			heightMap[idx] = seaLevel - 1
+			sandMask[idx] = true
+		}
+	}
+}
+
+// generateLakes attempts to place a single small surface lake in the chunk.
+// Deterministic per chunk; mirrors the decoration gating in MC's
+// ChunkProviderGenerate.populate (no lakes in deserts, 1-in-lakeChance).
+func (cp *ChunkProvider189) generateLakes(c *Chunk, xChunk, zChunk int, surfaceBiomes *[256]*Biome) {
+	biome := surfaceBiomes[7*16+7]
+	if biome == BiomeDesert || isOceanBiome(biome) {
+		return
+	}
+
+	rng := rng.ForChunk(cp.seed, rng.SaltLakes, xChunk, zChunk)
+	if rng.Intn(lakeChance) != 0 {
+		return
+	}
+
+	// Find the ground under the chunk centre, then sink the blob so its
+	// water half sits just below the surface (WorldGenLakes: position.down(4)).
+	baseY := -1
+	for y := 120; y > 5; y-- {
+		if c.GetBlock(8, y, 8) != BlockTypeAir {
+			baseY = y
+			break
+		}
+	}
+	if baseY <= seaLevel {
+		return
+	}
+	baseY -= 4
+
+	var blob [ChunkSizeX * ChunkSizeZ * lakeBlobHeight]bool
+	blobIdx := func(x, z, y int) int { return (x*16+z)*lakeBlobHeight + y }
+
+	n := rng.Intn(4) + 4
+	for i := 0; i < n; i++ {
+		sx := rng.Float64()*6.0 + 3.0
+		sy := rng.Float64()*4.0 + 2.0
+		sz := rng.Float64()*6.0 + 3.0
+		cx := rng.Float64()*(16.0-sx-2.0) + 1.0 + sx/2.0
+		cy := rng.Float64()*(8.0-sy-4.0) + 2.0 + sy/2.0
+		cz := rng.Float64()*(16.0-sz-2.0) + 1.0 + sz/2.0
+
+		for x := 1; x < 15; x++ {
+			for z := 1; z < 15; z++ {
+				for y := 1; y < 7; y++ {
+					dx := (float64(x) - cx) / (sx / 2.0)
+					dy := (float64(y) - cy) / (sy / 2.0)
+					dz := (float64(z) - cz) / (sz / 2.0)
+					if dx*dx+dy*dy+dz*dz < 1.0 {
+						blob[blobIdx(x, z, y)] = true
+					}
+				}
+			}
+		}
+	}
+
+	inBlob := func(x, z, y int) bool {
+		if x < 0 || x >= 16 || z < 0 || z >= 16 || y < 0 || y >= lakeBlobHeight {
+			return false
+		}
+		return blob[blobIdx(x, z, y)]
+	}
+
+	// Reject the lake if its shell would let water escape or flood into it.
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			for y := 0; y < lakeBlobHeight; y++ {
+				if inBlob(x, z, y) {
+					continue
+				}
+				if !inBlob(x+1, z, y) && !inBlob(x-1, z, y) &&
+					!inBlob(x, z+1, y) && !inBlob(x, z-1, y) &&
+					!inBlob(x, z, y+1) && !inBlob(x, z, y-1) {
+					continue
+				}
+				b := c.GetBlock(x, baseY+y, z)
+				if y >= 4 && (b == BlockTypeWater || b == BlockTypeLava) {
+					return
+				}
+				if y < 4 && b == BlockTypeAir {
+					return
+				}
+			}
+		}
+	}
+
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			for y := 0; y < lakeBlobHeight; y++ {
+				if !blob[blobIdx(x, z, y)] {
+					continue
+				}
+				if y >= 4 {
+					c.SetBlockFast(x, baseY+y, z, BlockTypeAir)
+				} else {
+					c.SetBlockFast(x, baseY+y, z, BlockTypeWater)
+				}
+			}
+		}
+	}
+
+	// Dirt exposed by the air half of the blob grows grass again.
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			for y := 4; y < lakeBlobHeight; y++ {
+				if blob[blobIdx(x, z, y)] && c.GetBlock(x, baseY+y-1, z) == BlockTypeDirt {
+					c.SetBlockFast(x, baseY+y-1, z, biome.TopBlock)
+				}
+			}
+		}
+	}
+}
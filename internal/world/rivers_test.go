@@ -0,0 +1,103 @@
+package world
+
+import (
+	"testing"
+)
+
+// findRiverChunk returns the first chunk X (at chunk Z=0) whose columns cross a river channel.
+func findRiverChunk(t *testing.T, seed int64) int {
+	t.Helper()
+	for cx := 0; cx < 4096; cx++ {
+		for lx := 0; lx < ChunkSizeX; lx++ {
+			if riverRidge(float64(cx*ChunkSizeX+lx), 0, seed) < riverWidth {
+				return cx
+			}
+		}
+	}
+	t.Fatalf("no river channel found for seed %d", seed)
+	return 0
+}
+
+func TestCarveRivers_ChannelIsFloodedToSeaLevel(t *testing.T) {
+	seed := int64(12345)
+	cp := NewChunkProvider189(seed)
+	cx := findRiverChunk(t, seed)
+
+	// Flat stone terrain a few blocks above sea level.
+	const groundY = seaLevel + 3
+	c := NewChunk(cx, 0, 0)
+	var biomes [256]*Biome
+	var heightMap [256]int16
+	var sandMask [256]bool
+	for lx := 0; lx < ChunkSizeX; lx++ {
+		for lz := 0; lz < ChunkSizeZ; lz++ {
+			for y := 0; y <= groundY; y++ {
+				c.SetBlockFast(lx, y, lz, BlockTypeStone)
+			}
+			biomes[lx*16+lz] = BiomePlains
+			heightMap[lx*16+lz] = groundY
+		}
+	}
+
+	cp.carveRivers(c, cx, 0, &biomes, &heightMap, &sandMask)
+
+	carved := 0
+	for lx := 0; lx < ChunkSizeX; lx++ {
+		for lz := 0; lz < ChunkSizeZ; lz++ {
+			ridge := riverRidge(float64(cx*ChunkSizeX+lx), float64(lz), seed)
+			if ridge >= riverWidth {
+				if c.GetBlock(lx, groundY, lz) != BlockTypeStone {
+					t.Fatalf("column (%d,%d) outside channel was carved", lx, lz)
+				}
+				continue
+			}
+			carved++
+			if got := c.GetBlock(lx, seaLevel-1, lz); got != BlockTypeWater {
+				t.Errorf("column (%d,%d): expected water at sea level, got %v", lx, lz, got)
+			}
+			for y := seaLevel; y <= groundY; y++ {
+				if got := c.GetBlock(lx, y, lz); got != BlockTypeAir {
+					t.Errorf("column (%d,%d): expected air at y=%d, got %v", lx, lz, y, got)
+				}
+			}
+			if !sandMask[lx*16+lz] {
+				t.Errorf("column (%d,%d): channel not flagged for sand", lx, lz)
+			}
+			if heightMap[lx*16+lz] != seaLevel-1 {
+				t.Errorf("column (%d,%d): heightMap = %d, want %d", lx, lz, heightMap[lx*16+lz], seaLevel-1)
+			}
+		}
+	}
+	if carved == 0 {
+		t.Fatal("expected at least one carved column")
+	}
+}
+
+func TestCarveRivers_SkipsOcean(t *testing.T) {
+	seed := int64(12345)
+	cp := NewChunkProvider189(seed)
+	cx := findRiverChunk(t, seed)
+
+	const groundY = seaLevel + 3
+	c := NewChunk(cx, 0, 0)
+	var biomes [256]*Biome
+	var heightMap [256]int16
+	var sandMask [256]bool
+	for lx := 0; lx < ChunkSizeX; lx++ {
+		for lz := 0; lz < ChunkSizeZ; lz++ {
+			c.SetBlockFast(lx, groundY, lz, BlockTypeStone)
+			biomes[lx*16+lz] = BiomeOcean
+			heightMap[lx*16+lz] = groundY
+		}
+	}
+
+	cp.carveRivers(c, cx, 0, &biomes, &heightMap, &sandMask)
+
+	for lx := 0; lx < ChunkSizeX; lx++ {
+		for lz := 0; lz < ChunkSizeZ; lz++ {
+			if c.GetBlock(lx, groundY, lz) != BlockTypeStone || sandMask[lx*16+lz] {
+				t.Fatalf("ocean column (%d,%d) was carved", lx, lz)
+			}
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package world
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// A section stores its blocks in the smallest form that holds them:
+//
+//   - uniform: one block type for the whole section, no per-block data
+//   - packed: 1, 2 or 4 bit indices into a palette of up to 16 types
+//   - direct: one byte per block, the block type itself
+//
+// Writes widen the storage when a new type does not fit the palette; chunks
+// are compacted back to the narrowest form after generation and on load.
+// Storage is replaced rather than resized in place, and published through
+// an atomic pointer, so mesh workers reading a section while the main thread
+// edits it always see a consistent palette and index array.
+
+// maxPaletteSize is the most types a packed section holds; more go direct.
+const maxPaletteSize = 16
+
+type blockStorage struct {
+	bits    uint8 // bits per block: 0 uniform, 1, 2 or 4 packed, 8 direct
+	n       uint8 // palette entries in use when packed
+	palette [maxPaletteSize]BlockType
+	data    []byte // packed indices, or the block types when direct
+}
+
+// uniformStorage is shared by every uniform section of each type; it is
+// never written.
+var uniformStorage = func() (s [256]blockStorage) {
+	for i := range s {
+		s[i].palette[0] = BlockType(i)
+	}
+	return s
+}()
+
+func uniform(t BlockType) *blockStorage {
+	return &uniformStorage[t]
+}
+
+func (s *blockStorage) get(idx int) BlockType {
+	switch s.bits {
+	case 0:
+		return s.palette[0]
+	case 8:
+		return BlockType(s.data[idx])
+	}
+	bit := idx * int(s.bits)
+	v := s.data[bit>>3] >> (bit & 7) & (1<<s.bits - 1)
+	return s.palette[v&(maxPaletteSize-1)]
+}
+
+// setIndex writes palette index v at idx of packed storage.
+func (s *blockStorage) setIndex(idx int, v uint8) {
+	bit := idx * int(s.bits)
+	mask := uint8(1<<s.bits-1) << (bit & 7)
+	b := &s.data[bit>>3]
+	*b = *b&^mask | v<<(bit&7)&mask
+}
+
+// set stores t at idx and returns the storage to use from now on: s itself,
+// or a wider copy when t does not fit in s.
+func (s *blockStorage) set(idx int, t BlockType) *blockStorage {
+	switch s.bits {
+	case 0:
+		if s.palette[0] == t {
+			return s
+		}
+	case 8:
+		s.data[idx] = byte(t)
+		return s
+	default:
+		for i := range s.n {
+			if s.palette[i] == t {
+				s.setIndex(idx, i)
+				return s
+			}
+		}
+		if int(s.n) < 1<<s.bits {
+			s.palette[s.n] = t
+			s.setIndex(idx, s.n)
+			s.n++
+			return s
+		}
+	}
+	w := s.widen()
+	return w.set(idx, t)
+}
+
+// widen returns a copy of s with twice the bits per block.
+func (s *blockStorage) widen() *blockStorage {
+	bits := uint8(1)
+	if s.bits > 0 {
+		bits = s.bits * 2
+	}
+	w := &blockStorage{bits: bits}
+	if bits == 8 {
+		w.data = make([]byte, SectionVolume)
+		for i := range w.data {
+			w.data[i] = byte(s.get(i))
+		}
+		return w
+	}
+	w.data = make([]byte, SectionVolume*int(bits)/8)
+	w.palette = s.palette
+	w.n = max(s.n, 1) // a uniform section's one type is index 0
+	if s.bits > 0 {
+		for i := range SectionVolume {
+			bit := i * int(s.bits)
+			w.setIndex(i, s.data[bit>>3]>>(bit&7)&(1<<s.bits-1))
+		}
+	}
+	return w
+}
+
+// expand writes every block of s into dst.
+func (s *blockStorage) expand(dst *[SectionVolume]BlockType) {
+	switch s.bits {
+	case 0:
+		for i := range dst {
+			dst[i] = s.palette[0]
+		}
+	case 8:
+		for i, b := range s.data {
+			dst[i] = BlockType(b)
+		}
+	default:
+		for i := range dst {
+			dst[i] = s.get(i)
+		}
+	}
+}
+
+// matchesAll reports whether every block of s satisfies table.
+func (s *blockStorage) matchesAll(table *[256]bool) bool {
+	switch s.bits {
+	case 0:
+		return table[s.palette[0]]
+	case 8:
+		for _, b := range s.data {
+			if !table[b] {
+				return false
+			}
+		}
+		return true
+	}
+	// The palette may still hold types no longer used; only when one of
+	// those fails is a full scan needed.
+	for _, t := range s.palette[:s.n] {
+		if !table[t] {
+			for i := range SectionVolume {
+				if !table[s.get(i)] {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return true
+}
+
+// packBlocks returns the narrowest storage for blocks.
+func packBlocks(blocks *[SectionVolume]BlockType) *blockStorage {
+	var seen [256]bool
+	var palette [maxPaletteSize]BlockType
+	var index [256]uint8
+	n := 0
+	for _, t := range blocks {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		if n < maxPaletteSize {
+			palette[n] = t
+			index[t] = uint8(n)
+		}
+		n++
+	}
+
+	var bits uint8
+	switch {
+	case n == 1:
+		return uniform(blocks[0])
+	case n <= 2:
+		bits = 1
+	case n <= 4:
+		bits = 2
+	case n <= maxPaletteSize:
+		bits = 4
+	default:
+		s := &blockStorage{bits: 8, data: make([]byte, SectionVolume)}
+		for i, t := range blocks {
+			s.data[i] = byte(t)
+		}
+		return s
+	}
+	s := &blockStorage{bits: bits, n: uint8(n), palette: palette, data: make([]byte, SectionVolume*int(bits)/8)}
+	for i, t := range blocks {
+		s.setIndex(i, index[t])
+	}
+	return s
+}
+
+// storedBytes returns the heap bytes the storage holds for its blocks.
+func (s *blockStorage) storedBytes() int {
+	return len(s.data)
+}
+
+// Section represents a 16x16x16 sub-volume of a chunk
+type Section struct {
+	blocks   atomic.Pointer[blockStorage]
+	metadata []uint8
+	metaPtr  unsafe.Pointer // &metadata[0] tutuluyor; nil → tüm metadata sıfır (kaynak su gibi)
+	shared   atomic.Bool    // blocks and metadata held by a snapshot too (see snapshot.go)
+}
+
+// newSection returns an all-air section.
+func newSection() *Section {
+	sec := &Section{}
+	sec.blocks.Store(uniform(BlockTypeAir))
+	return sec
+}
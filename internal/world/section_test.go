@@ -0,0 +1,81 @@
+package world
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBlockStorageMatchesDense(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var want [SectionVolume]BlockType
+	s := uniform(BlockTypeAir)
+	// Widen through every width: a few types, then many
+	for _, types := range []int{2, 4, 16, 40} {
+		for range 3000 {
+			idx := rnd.Intn(SectionVolume)
+			bt := BlockType(rnd.Intn(types))
+			want[idx] = bt
+			s = s.set(idx, bt)
+		}
+		for i, bt := range want {
+			if got := s.get(i); got != bt {
+				t.Fatalf("%d types, %d bits: block %d = %d, want %d", types, s.bits, i, got, bt)
+			}
+		}
+	}
+	if s.bits != 8 {
+		t.Errorf("40 types stored with %d bits, want 8", s.bits)
+	}
+	if uniform(BlockTypeAir).get(0) != BlockTypeAir || uniform(BlockTypeStone).get(17) != BlockTypeStone {
+		t.Error("shared uniform storage was written")
+	}
+}
+
+func TestCompactPacksSections(t *testing.T) {
+	c := NewChunk(0, 0, 0)
+	for x := range ChunkSizeX {
+		for z := range ChunkSizeZ {
+			for y := range 40 {
+				bt := BlockTypeStone
+				if y == 0 {
+					bt = BlockTypeBedrock
+				} else if (x+y+z)%7 == 0 {
+					bt = BlockTypeDirt
+				}
+				c.SetBlockFast(x, y, z, bt)
+			}
+		}
+	}
+	c.SetBlock(3, 100, 3, BlockTypeStone)
+	c.SetBlock(3, 100, 3, BlockTypeAir)
+	hash := c.ContentHash()
+
+	c.compact()
+	if got := c.ContentHash(); got != hash {
+		t.Errorf("compacting changed the hash: %x, want %x", got, hash)
+	}
+	if c.sections[6] != nil {
+		t.Error("section emptied by edits was kept")
+	}
+	if s := c.sections[1].blocks.Load(); s.bits != 1 {
+		t.Errorf("stone and dirt section stored with %d bits, want 1", s.bits)
+	}
+	if c.GetBlock(5, 0, 5) != BlockTypeBedrock || c.GetBlock(0, 7, 0) != BlockTypeDirt || c.GetBlock(1, 7, 0) != BlockTypeStone {
+		t.Error("blocks changed by compacting")
+	}
+	stoneOrDirt := [256]bool{BlockTypeStone: true, BlockTypeDirt: true}
+	stone := [256]bool{BlockTypeStone: true}
+	if !c.SectionAllMatch(1, &stoneOrDirt) || c.SectionAllMatch(1, &stone) {
+		t.Error("SectionAllMatch disagrees with the blocks")
+	}
+}
+
+func TestSectionStorageCountsPackedBlocks(t *testing.T) {
+	w := NewEmpty()
+	defer w.Close()
+	w.Set(1, 2, 3, BlockTypeStone)
+	sections, bytes := w.SectionStorage()
+	if sections != 1 || bytes != SectionVolume/8 {
+		t.Errorf("one stone block in air: %d sections of %d bytes, want 1 of %d", sections, bytes, SectionVolume/8)
+	}
+}
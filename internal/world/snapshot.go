@@ -0,0 +1,96 @@
+package world
+
+import (
+	"slices"
+	"unsafe"
+)
+
+// Mesh workers read chunks while the main thread edits them and the lighter
+// relights them. So that each mesh is built from one consistent state, they
+// are handed snapshots: copies of a chunk and its neighbours sharing the
+// live chunks' block, metadata and light arrays. The arrays are copied on
+// write: the first write to a live section or light array after a snapshot
+// was taken gives the live chunk its own copy and leaves the snapshot's
+// alone. Taking a snapshot costs a few pointer copies per section.
+//
+// A write racing the snapshot that shares its array may still land in it;
+// only the lighter writes off the main thread, and a relit chunk is meshed
+// again anyway (see the blocks renderer's light remeshing).
+
+// Snapshot returns a copy of c that later writes to c do not change. It is
+// for reading only: writing to it is not supported.
+func (c *Chunk) Snapshot() *Chunk {
+	s := &Chunk{
+		X: c.X, Y: c.Y, Z: c.Z,
+		generation: c.generation,
+		modified:   c.modified,
+		genHash:    c.genHash,
+		lightFill:  c.lightFill,
+		origin:     c,
+	}
+	for i, sec := range c.sections {
+		if sec == nil {
+			continue
+		}
+		sec.shared.Store(true)
+		ns := &Section{metadata: sec.metadata, metaPtr: sec.metaPtr}
+		ns.blocks.Store(sec.blocks.Load())
+		s.sections[i] = ns
+	}
+	for i := range c.light {
+		if l := c.light[i].Load(); l != nil {
+			c.lightShared[i].Store(true)
+			s.light[i].Store(l)
+		}
+	}
+	return s
+}
+
+// own gives sec its own block and metadata arrays if a snapshot shares
+// them. Call it before writing to either in place.
+func (sec *Section) own() {
+	if !sec.shared.Load() {
+		return
+	}
+	if s := sec.blocks.Load(); s.bits != 0 {
+		sec.blocks.Store(&blockStorage{bits: s.bits, n: s.n, palette: s.palette, data: slices.Clone(s.data)})
+	}
+	if sec.metadata != nil {
+		sec.metadata = slices.Clone(sec.metadata)
+		sec.metaPtr = unsafe.Pointer(&sec.metadata[0])
+	}
+	sec.shared.Store(false)
+}
+
+// ownLight gives section secIdx of c its own light array if a snapshot
+// shares it, returning the array to write to (nil when the section has none).
+func (c *Chunk) ownLight(secIdx int) *[SectionVolume]uint8 {
+	l := c.light[secIdx].Load()
+	if l != nil && c.lightShared[secIdx].Load() {
+		own := *l
+		l = &own
+		c.light[secIdx].Store(l)
+		c.lightShared[secIdx].Store(false)
+	}
+	return l
+}
+
+// SnapshotAround returns a world holding snapshots of c and the chunks
+// around it, enough to mesh c: block, metadata, light and biome colour reads
+// work as on w within one chunk of c. Nothing else of the world is there.
+func (w *World) SnapshotAround(c *Chunk) *World {
+	store := &ChunkStore{chunks: make(map[ChunkCoord]*Chunk, 9)}
+	for dx := -1; dx <= 1; dx++ {
+		for dz := -1; dz <= 1; dz++ {
+			coord := ChunkCoord{X: c.X + dx, Y: c.Y, Z: c.Z + dz}
+			nb := c
+			if dx != 0 || dz != 0 {
+				nb = w.store.GetChunk(coord.X, coord.Y, coord.Z, false)
+			}
+			if nb != nil {
+				store.chunks[coord] = nb.Snapshot()
+			}
+		}
+	}
+	return &World{store: store, seed: w.seed}
+}
@@ -0,0 +1,65 @@
+package world
+
+import "testing"
+
+func TestSnapshotKeepsStateBeforeWrites(t *testing.T) {
+	c := NewChunk(0, 0, 0)
+	for x := range ChunkSizeX {
+		c.SetBlock(x, 10, 0, BlockTypeStone)
+	}
+	c.SetBlock(1, 10, 1, BlockTypeGrass) // packed storage, written in place
+	c.SetMeta(2, 10, 2, 3)
+	c.setLight(4, 10, 4, 7)
+
+	s := c.Snapshot()
+	c.SetBlock(1, 10, 1, BlockTypeDirt)
+	c.SetBlock(0, 10, 0, BlockTypeAir)
+	c.SetMeta(2, 10, 2, 5)
+	c.setLight(4, 10, 4, 9)
+	c.SetBlock(3, 40, 3, BlockTypeStone) // new section
+
+	if got := s.GetBlock(1, 10, 1); got != BlockTypeGrass {
+		t.Errorf("snapshot block = %v, want grass", got)
+	}
+	if got := s.GetBlock(0, 10, 0); got != BlockTypeStone {
+		t.Errorf("snapshot block = %v, want stone", got)
+	}
+	if got := s.GetMeta(2, 10, 2); got != 3 {
+		t.Errorf("snapshot meta = %d, want 3", got)
+	}
+	if got := s.Light(4, 10, 4); got != 7 {
+		t.Errorf("snapshot light = %d, want 7", got)
+	}
+	if !s.IsSectionEmpty(40 / SectionHeight) {
+		t.Error("section added after the snapshot shows in it")
+	}
+
+	if c.GetBlock(1, 10, 1) != BlockTypeDirt || c.GetMeta(2, 10, 2) != 5 || c.Light(4, 10, 4) != 9 {
+		t.Error("writes after the snapshot lost on the live chunk")
+	}
+}
+
+func TestSnapshotAroundHoldsNeighbours(t *testing.T) {
+	w := New()
+	defer w.Close()
+	c := w.GetChunk(0, 0, 0, true)
+	east := w.GetChunk(1, 0, 0, true)
+	east.SetBlock(0, 5, 0, BlockTypeStone)
+	w.GetChunk(3, 0, 0, true)
+
+	s := w.SnapshotAround(c)
+	east.SetBlock(0, 5, 0, BlockTypeAir)
+
+	if s.GetChunk(0, 0, 0, false) == c {
+		t.Fatal("snapshot world returns the live chunk")
+	}
+	if got := s.Get(ChunkSizeX, 5, 0); got != BlockTypeStone {
+		t.Errorf("neighbour block = %v, want stone", got)
+	}
+	if s.GetChunk(3, 0, 0, false) != nil {
+		t.Error("chunk beyond the neighbours in the snapshot")
+	}
+	if s.BiomeColors(s.GetChunk(0, 0, 0, false)) != w.BiomeColors(c) {
+		t.Error("snapshot works biome colours out again")
+	}
+}
@@ -0,0 +1,196 @@
+package world
+
+import "mini-mc/internal/mathutil"
+
+// Snow layers (MC BlockSnow). The block's metadata holds layers-1, so a fresh
+// layer is meta 0 and a full block of snow is meta 7.
+
+const (
+	MaxSnowLayers = 8
+	SnowTickRate  = 10
+
+	// snowMeltLight is the block light above which snow melts (MC
+	// BlockSnow.updateTick).
+	snowMeltLight = 11
+
+	// snowMeltRadius is how far block light above snowMeltLight reaches: a
+	// level 15 source such as lava lights blocks within three (Manhattan)
+	// above 11, so a change further away cannot start snow melting.
+	snowMeltRadius = MaxLight - 1 - snowMeltLight
+
+	// snowfallChance is one in how many ticks each loaded chunk has snow
+	// settle on one of its columns, as MC's per-chunk weather update.
+	snowfallChance = 16
+
+	// snowfallMaxLayers is how deep falling snow builds up; only players
+	// stack it higher.
+	snowfallMaxLayers = 3
+
+	// snowBiomeTemperature is the biome temperature below which generated
+	// surfaces start out covered with a single snow layer (MC canSnowAt).
+	snowBiomeTemperature = 0.15
+)
+
+// SnowLayers returns the number of layers (1-8) encoded in a snow block's metadata.
+func SnowLayers(meta uint8) int {
+	return int(meta&7) + 1
+}
+
+// CollisionHeight returns the top of the block's collision box, measured from
+// the bottom of the block: 0 for no collision, 1 for a full cube. Snow layers
+// collide one layer lower than they render, so a single layer can be walked through.
+func CollisionHeight(bt BlockType, meta uint8) float32 {
+	if bt == BlockTypeSnowLayer {
+		return float32(SnowLayers(meta)-1) / MaxSnowLayers
+	}
+	if BlockSolidTable[bt] {
+		return 1
+	}
+	return 0
+}
+
+// canSnowRestOn reports whether a snow layer can sit on top of the given block.
+func canSnowRestOn(w *World, x, y, z int) bool {
+	below := w.Get(x, y-1, z)
+	if below == BlockTypeSnowLayer {
+		return SnowLayers(w.GetMeta(x, y-1, z)) == MaxSnowLayers
+	}
+	return BlockSolidTable[below]
+}
+
+// StackSnowLayer adds one layer to the snow block at (x, y, z).
+// Returns false if the block is not snow or is already a full block.
+func (w *World) StackSnowLayer(x, y, z int) bool {
+	if w.Get(x, y, z) != BlockTypeSnowLayer {
+		return false
+	}
+	layers := SnowLayers(w.GetMeta(x, y, z))
+	if layers >= MaxSnowLayers {
+		return false
+	}
+	w.SetWithMeta(x, y, z, BlockTypeSnowLayer, uint8(layers))
+	return true
+}
+
+// AccumulateSnow adds one snow layer to the top of the column at (x, z).
+// Snowfall (tickSnowfall) calls this for the columns it snows on; it thickens
+// an existing layer or starts a new one on the highest solid surface.
+// Returns true if the column changed.
+func (w *World) AccumulateSnow(x, z int) bool {
+	y := w.columnTop(x, z)
+	if y <= 0 {
+		return false
+	}
+	return w.accumulateSnowOn(x, y, z)
+}
+
+// accumulateSnowOn is AccumulateSnow for a column whose highest block is
+// at y.
+func (w *World) accumulateSnowOn(x, y, z int) bool {
+	bt := w.Get(x, y, z)
+	if bt == BlockTypeSnowLayer && w.StackSnowLayer(x, y, z) {
+		return true
+	}
+	// A full block of snow takes a new layer on top, like a solid block
+	if bt != BlockTypeSnowLayer && !BlockSolidTable[bt] || y+1 >= ChunkSizeY {
+		return false
+	}
+	w.SetWithMeta(x, y+1, z, BlockTypeSnowLayer, 0)
+	w.ScheduleBlockTick(x, y+1, z, SnowTickRate, 0)
+	return true
+}
+
+// snowMeltsAt reports whether the block light at (x, y, z) is bright enough
+// to melt snow there.
+func snowMeltsAt(w *World, x, y, z int) bool {
+	return w.BlockLight(x, y, z) > snowMeltLight
+}
+
+// tickSnowfall lets snow settle in cold biomes, where it is dark enough to
+// stay. Each loaded column has a one in snowfallChance chance a tick, as
+// MC's per-chunk weather update; the columns are picked at random so the
+// random draws come in the same order whatever the store's map order.
+func (w *World) tickSnowfall() {
+	n := w.store.ColumnCount()
+	if n == 0 {
+		return
+	}
+	picks := n / snowfallChance
+	if w.rand.Intn(snowfallChance) < n%snowfallChance {
+		picks++
+	}
+	for range picks {
+		i := w.rand.Intn(n)
+		lx, lz := w.rand.Intn(ChunkSizeX), w.rand.Intn(ChunkSizeZ)
+		cx, cz, ok := w.store.ColumnAt(i)
+		if !ok {
+			continue
+		}
+		x, z := cx*ChunkSizeX+lx, cz*ChunkSizeZ+lz
+		if GetBiomeForCoords(float64(x), float64(z), w.seed).Temperature >= snowBiomeTemperature {
+			continue
+		}
+		y := w.columnTop(x, z)
+		if y <= 0 {
+			continue
+		}
+		if w.Get(x, y, z) == BlockTypeSnowLayer && SnowLayers(w.GetMeta(x, y, z)) >= snowfallMaxLayers {
+			continue
+		}
+		if snowMeltsAt(w, x, y+1, z) {
+			continue
+		}
+		w.accumulateSnowOn(x, y, z)
+	}
+}
+
+// SnowTick is called when a scheduled tick fires for a snow layer.
+// Mirrors BlockSnow.updateTick: melt near light, drop off unsupported surfaces.
+func SnowTick(w *World, x, y, z int) {
+	if w.Get(x, y, z) != BlockTypeSnowLayer {
+		return
+	}
+	if snowMeltsAt(w, x, y, z) || !canSnowRestOn(w, x, y, z) {
+		w.Set(x, y, z, BlockTypeAir)
+		notifyFluidNeighbors(w, x, y, z)
+	}
+}
+
+// notifySnowNear schedules a melt check for every snow layer within melt range
+// of (x, y, z). Called when a heat source appears or a block changes nearby.
+func notifySnowNear(w *World, x, y, z int) {
+	for dx := -snowMeltRadius; dx <= snowMeltRadius; dx++ {
+		rdx := snowMeltRadius - mathutil.Abs(dx)
+		for dy := -rdx; dy <= rdx; dy++ {
+			rdy := rdx - mathutil.Abs(dy)
+			for dz := -rdy; dz <= rdy; dz++ {
+				if w.Get(x+dx, y+dy, z+dz) == BlockTypeSnowLayer {
+					w.ScheduleBlockTick(x+dx, y+dy, z+dz, SnowTickRate, 0)
+				}
+			}
+		}
+	}
+}
+
+// placeSnowCover lays a single snow layer over every exposed surface in cold
+// biomes, matching the freeze pass MC runs while decorating chunks.
+func (cp *ChunkProvider189) placeSnowCover(c *Chunk, surfaceBiomes *[256]*Biome) {
+	for lx := 0; lx < ChunkSizeX; lx++ {
+		for lz := 0; lz < ChunkSizeZ; lz++ {
+			if surfaceBiomes[lx*16+lz].Temperature >= snowBiomeTemperature {
+				continue
+			}
+			for y := ChunkSizeY - 2; y > 0; y-- {
+				bt := c.GetBlock(lx, y, lz)
+				if bt == BlockTypeAir {
+					continue
+				}
+				// Like MC, snow does not settle where a plant stands
+				if bt != BlockTypeWater && bt != BlockTypeLava && !IsPlant(bt) {
+					c.SetBlockFast(lx, y+1, lz, BlockTypeSnowLayer)
+				}
+				break
+			}
+		}
+	}
+}
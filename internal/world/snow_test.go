@@ -0,0 +1,138 @@
+package world
+
+import (
+	"testing"
+)
+
+func TestAccumulateSnow_StacksUpToFullBlock(t *testing.T) {
+	w := NewEmpty()
+	w.Set(4, 60, 4, BlockTypeStone)
+
+	for i := 1; i <= MaxSnowLayers; i++ {
+		if !w.AccumulateSnow(4, 4) {
+			t.Fatalf("accumulation %d: column did not change", i)
+		}
+		if got := w.Get(4, 61, 4); got != BlockTypeSnowLayer {
+			t.Fatalf("accumulation %d: expected snow at y=61, got %v", i, got)
+		}
+		if got := SnowLayers(w.GetMeta(4, 61, 4)); got != i {
+			t.Fatalf("accumulation %d: layers = %d, want %d", i, got, i)
+		}
+	}
+
+	// A full block of snow starts a new layer on top of itself.
+	if !w.AccumulateSnow(4, 4) {
+		t.Fatal("expected a new layer above the full snow block")
+	}
+	if got := w.Get(4, 62, 4); got != BlockTypeSnowLayer {
+		t.Fatalf("expected snow at y=62, got %v", got)
+	}
+	if got := SnowLayers(w.GetMeta(4, 62, 4)); got != 1 {
+		t.Fatalf("new layer has %d layers, want 1", got)
+	}
+}
+
+func TestAccumulateSnow_SkipsFluids(t *testing.T) {
+	w := NewEmpty()
+	w.Set(4, 60, 4, BlockTypeWater)
+
+	if w.AccumulateSnow(4, 4) {
+		t.Fatal("snow should not settle on water")
+	}
+	if got := w.Get(4, 61, 4); got != BlockTypeAir {
+		t.Fatalf("expected air above water, got %v", got)
+	}
+}
+
+func TestCollisionHeight(t *testing.T) {
+	cases := []struct {
+		bt   BlockType
+		meta uint8
+		want float32
+	}{
+		{BlockTypeAir, 0, 0},
+		{BlockTypeStone, 0, 1},
+		{BlockTypeWater, 0, 0},
+		{BlockTypeSnowLayer, 0, 0},     // 1 layer: no collision
+		{BlockTypeSnowLayer, 3, 0.375}, // 4 layers
+		{BlockTypeSnowLayer, 7, 0.875}, // full snow still collides one layer low
+	}
+	for _, tc := range cases {
+		if got := CollisionHeight(tc.bt, tc.meta); got != tc.want {
+			t.Errorf("CollisionHeight(%v, %d) = %v, want %v", tc.bt, tc.meta, got, tc.want)
+		}
+	}
+}
+
+func TestSnowTick_MeltsNearLava(t *testing.T) {
+	withLightTables(t)
+	w := NewEmpty()
+	w.Set(4, 60, 4, BlockTypeStone)
+	w.Set(10, 60, 4, BlockTypeStone)
+	w.SetWithMeta(4, 61, 4, BlockTypeSnowLayer, 2)
+	w.SetWithMeta(10, 61, 4, BlockTypeSnowLayer, 2)
+	w.Set(6, 61, 4, BlockTypeLava)
+
+	SnowTick(w, 4, 61, 4)
+	SnowTick(w, 10, 61, 4)
+
+	if got := w.Get(4, 61, 4); got != BlockTypeAir {
+		t.Errorf("snow next to lava should melt, got %v", got)
+	}
+	if got := w.Get(10, 61, 4); got != BlockTypeSnowLayer {
+		t.Errorf("snow out of lava range should remain, got %v", got)
+	}
+}
+
+func TestSnowfall_SettlesInColdBiomes(t *testing.T) {
+	withLightTables(t)
+	const seed = 1 // cold taiga around the origin
+	if GetBiomeForCoords(8, 8, seed).Temperature >= snowBiomeTemperature {
+		t.Fatal("test seed is not cold at the origin")
+	}
+	w := NewWithSeed(seed)
+	defer w.Close()
+	for x := range ChunkSizeX {
+		for z := range ChunkSizeZ {
+			w.Set(x, 60, z, BlockTypeStone)
+		}
+	}
+	w.Set(8, 61, 8, BlockTypeLava)
+
+	for range 400 * snowfallChance {
+		w.Tick()
+	}
+
+	snowy := 0
+	for x := range ChunkSizeX {
+		for z := range ChunkSizeZ {
+			if w.Get(x, 62, z) != BlockTypeAir {
+				t.Fatalf("snow at (%d, %d) grew past a block", x, z)
+			}
+			if w.Get(x, 61, z) != BlockTypeSnowLayer {
+				continue
+			}
+			snowy++
+			if layers := SnowLayers(w.GetMeta(x, 61, z)); layers > snowfallMaxLayers {
+				t.Errorf("snow at (%d, %d) fell %d layers deep, want at most %d", x, z, layers, snowfallMaxLayers)
+			}
+			if w.BlockLight(x, 61, z) > snowMeltLight {
+				t.Errorf("snow settled at (%d, %d) in light %d from the lava", x, z, w.BlockLight(x, 61, z))
+			}
+		}
+	}
+	if snowy < 100 {
+		t.Errorf("snow settled on %d of %d columns, want most", snowy, ChunkSizeX*ChunkSizeZ)
+	}
+}
+
+func TestSnowTick_DropsWithoutSupport(t *testing.T) {
+	w := NewEmpty()
+	w.SetWithMeta(4, 61, 4, BlockTypeSnowLayer, 0)
+
+	SnowTick(w, 4, 61, 4)
+
+	if got := w.Get(4, 61, 4); got != BlockTypeAir {
+		t.Fatalf("unsupported snow should be removed, got %v", got)
+	}
+}
@@ -0,0 +1,121 @@
+package world
+
+import "mini-mc/internal/mathutil"
+
+// SpawnSearchRadius is how far, in blocks, FindSpawn looks around the point
+// asked for before giving up on dry land and building a platform.
+const SpawnSearchRadius = 32
+
+// Spawn platforms are built at the surface of the water they stand in, or
+// at spawnPlatformY in a world with no blocks below.
+const (
+	spawnPlatformY      = 64
+	spawnPlatformRadius = 2 // a 5x5 platform
+	spawnPlatformBlock  = BlockTypeCobblestone
+)
+
+// Spawn returns where players appear in the world: the spawn point saved
+// with it while that is still safe, or else the nearest safe place above it,
+// and for a new world a point found by FindSpawn near the origin, which is
+// then saved.
+func (w *World) Spawn() BlockPos {
+	if w.level != nil && w.level.Spawn != nil {
+		s := *w.level.Spawn
+		w.StreamChunksAroundSync(float32(s.X), float32(s.Z), 1)
+		if y, ok := w.safeAbove(s.X, s.Y, s.Z); ok {
+			return BlockPos{X: s.X, Y: y, Z: s.Z}
+		}
+	}
+	s := w.FindSpawn(0, 0)
+	if w.level != nil {
+		w.level.Spawn = &s
+	}
+	return s
+}
+
+// FindSpawn returns the block a player's feet can safely be in near (x, z):
+// on a full, opaque block with two blocks of air or other passable blocks
+// above, and not in water. It looks at the top of each column, nearest
+// first, so never picks a cave. When there is no dry land within
+// SpawnSearchRadius, as at sea or in a void world, it builds a small
+// platform at (x, z) and returns a place on that. Chunks around are loaded
+// as needed.
+func (w *World) FindSpawn(x, z int) BlockPos {
+	w.StreamChunksAroundSync(float32(x), float32(z), SpawnSearchRadius/ChunkSizeX+1)
+	for r := 0; r <= SpawnSearchRadius; r++ {
+		// The square ring r blocks out, side by side
+		for dx := -r; dx <= r; dx++ {
+			for dz := -r; dz <= r; dz++ {
+				if max(dx, -dx, dz, -dz) != r {
+					continue
+				}
+				top := w.columnTop(x+dx, z+dz)
+				if top < 0 || BlockFluidTable[w.Get(x+dx, top, z+dz)] {
+					continue
+				}
+				if y, ok := w.safeAbove(x+dx, top+1, z+dz); ok && y == top+1 {
+					return BlockPos{X: x + dx, Y: y, Z: z + dz}
+				}
+			}
+		}
+	}
+	return w.buildSpawnPlatform(x, z)
+}
+
+// columnTop returns the y of the highest block in column (x, z), or -1 when
+// the column is empty or not loaded.
+func (w *World) columnTop(x, z int) int {
+	c := w.GetChunkFromBlockCoords(x, 0, z, false)
+	if c == nil {
+		return -1
+	}
+	lx, lz := mathutil.Mod(x, ChunkSizeX), mathutil.Mod(z, ChunkSizeZ)
+	_, maxY, ok := c.OccupiedYRange()
+	if !ok {
+		return -1
+	}
+	for y := maxY - 1; y >= 0; y-- {
+		if c.GetBlock(lx, y, lz) != BlockTypeAir {
+			return y
+		}
+	}
+	return -1
+}
+
+// safeAbove returns the lowest y from y up where a player's feet can be in
+// column (x, z): a full, opaque floor and two passable, dry blocks.
+func (w *World) safeAbove(x, y, z int) (int, bool) {
+	for ; y > 0 && y+1 < ChunkSizeY; y++ {
+		floor := w.Get(x, y-1, z)
+		if !BlockSolidTable[floor] || BlockLightOpacity[floor] < MaxLight {
+			continue // air, water, or leaves and other blocks light gets through
+		}
+		if spawnPassable(w.Get(x, y, z)) && spawnPassable(w.Get(x, y+1, z)) {
+			return y, true
+		}
+	}
+	return 0, false
+}
+
+func spawnPassable(bt BlockType) bool {
+	return !BlockSolidTable[bt] && !BlockFluidTable[bt]
+}
+
+// buildSpawnPlatform lays a platform centred on (x, z) over whatever is the
+// top of the column, clearing the space above it, and returns the middle of
+// it.
+func (w *World) buildSpawnPlatform(x, z int) BlockPos {
+	y := w.columnTop(x, z)
+	if y < 0 {
+		y = spawnPlatformY
+	}
+	y = min(y, ChunkSizeY-3)
+	for dx := -spawnPlatformRadius; dx <= spawnPlatformRadius; dx++ {
+		for dz := -spawnPlatformRadius; dz <= spawnPlatformRadius; dz++ {
+			w.Set(x+dx, y, z+dz, spawnPlatformBlock)
+			w.Set(x+dx, y+1, z+dz, BlockTypeAir)
+			w.Set(x+dx, y+2, z+dz, BlockTypeAir)
+		}
+	}
+	return BlockPos{X: x, Y: y + 1, Z: z}
+}
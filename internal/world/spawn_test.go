@@ -0,0 +1,99 @@
+package world
+
+import "testing"
+
+// layerGenerator fills every column with the same layers: blocks[y] at y.
+type layerGenerator []BlockType
+
+func (g layerGenerator) HeightAt(_, _ int) int { return len(g) - 1 }
+
+func (g layerGenerator) PopulateChunk(c *Chunk) {
+	for x := range ChunkSizeX {
+		for z := range ChunkSizeZ {
+			for y, bt := range g {
+				c.SetBlockFast(x, y, z, bt)
+			}
+		}
+	}
+}
+
+// layerWorld returns an unsaved world generated by g, with the block
+// tables the registry would fill in for the blocks used.
+func layerWorld(t *testing.T, g layerGenerator) *World {
+	withLightTables(t)
+	solid, fluid := BlockSolidTable, BlockFluidTable
+	t.Cleanup(func() { BlockSolidTable, BlockFluidTable = solid, fluid })
+	for _, bt := range []BlockType{BlockTypeStone, BlockTypeCobblestone, BlockTypeOakLeaves} {
+		BlockSolidTable[bt] = true
+	}
+	BlockLightOpacity[BlockTypeCobblestone] = MaxLight
+	BlockLightOpacity[BlockTypeOakLeaves] = 1
+	BlockFluidTable[BlockTypeWater] = true
+
+	w := NewWithSeed(1)
+	t.Cleanup(w.Close)
+	w.gen, w.streamer.gen = g, g
+	return w
+}
+
+func layers(n int, bt BlockType) layerGenerator {
+	g := make(layerGenerator, n)
+	for i := range g {
+		g[i] = bt
+	}
+	return g
+}
+
+func TestFindSpawnOnLand(t *testing.T) {
+	w := layerWorld(t, layers(70, BlockTypeStone))
+	if got, want := w.FindSpawn(5, -3), (BlockPos{X: 5, Y: 70, Z: -3}); got != want {
+		t.Errorf("spawn = %v, want %v", got, want)
+	}
+}
+
+func TestFindSpawnAvoidsWaterAndTrees(t *testing.T) {
+	w := layerWorld(t, append(layers(40, BlockTypeStone), layers(24, BlockTypeWater)...))
+	// A strip of leaves just offshore and dry land further out
+	for z := -3; z <= 3; z++ {
+		w.Set(2, 64, z, BlockTypeOakLeaves)
+	}
+	w.Set(4, 64, 0, BlockTypeStone)
+
+	if got, want := w.FindSpawn(0, 0), (BlockPos{X: 4, Y: 65, Z: 0}); got != want {
+		t.Errorf("spawn = %v, want %v", got, want)
+	}
+}
+
+func TestFindSpawnBuildsPlatform(t *testing.T) {
+	for name, g := range map[string]layerGenerator{
+		"ocean": append(layers(40, BlockTypeStone), layers(24, BlockTypeWater)...),
+		"void":  nil,
+	} {
+		w := layerWorld(t, g)
+		s := w.FindSpawn(0, 0)
+		if s != (BlockPos{Y: 64}) && s != (BlockPos{Y: spawnPlatformY + 1}) {
+			t.Errorf("%s: spawn = %v", name, s)
+		}
+		if got := w.Get(s.X+spawnPlatformRadius, s.Y-1, s.Z); got != spawnPlatformBlock {
+			t.Errorf("%s: platform edge is %v", name, got)
+		}
+		if _, ok := w.safeAbove(s.X, s.Y, s.Z); !ok {
+			t.Errorf("%s: spawn on the platform is not safe", name)
+		}
+	}
+}
+
+func TestSpawnKeepsSavedPointAboveBuilding(t *testing.T) {
+	w := layerWorld(t, layers(70, BlockTypeStone))
+	w.level = &Level{Spawn: &BlockPos{X: 3, Y: 70, Z: 3}}
+
+	if got := w.Spawn(); got != *w.level.Spawn {
+		t.Fatalf("spawn = %v, want the saved %v", got, *w.level.Spawn)
+	}
+	// Walled in: the spawn moves up onto the roof
+	w.Set(3, 70, 3, BlockTypeStone)
+	w.Set(3, 71, 3, BlockTypeStone)
+	if got, want := w.Spawn(), (BlockPos{X: 3, Y: 72, Z: 3}); got != want {
+		t.Errorf("spawn = %v, want %v", got, want)
+	}
+}
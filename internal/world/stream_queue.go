@@ -0,0 +1,138 @@
+package world
+
+import (
+	"container/heap"
+	"math"
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Chunks waiting to be generated are kept in a heap ordered by how soon the
+// player should see them: distance counts first, but a chunk behind the
+// camera waits as if it were 1+2*streamViewWeight times as far. The chunks
+// right around the player come first whatever the view, since the player
+// stands on them. When the view turns far enough the heap is reordered, so
+// the terrain being turned to overtakes what was queued before.
+
+const (
+	// streamViewWeight is how much being away from the view delays a chunk
+	streamViewWeight = 1.0
+	// streamNearChunks is how far from the centre, in chunks, the view
+	// does not count
+	streamNearChunks = 2.0
+	// streamQueueSize is the most chunks queued for generation at once
+	streamQueueSize = 4096
+)
+
+// streamRescoreCos is the cosine of how far the view turns before the
+// queue is reordered
+var streamRescoreCos = math.Cos(15 * math.Pi / 180)
+
+// streamScore returns the order of a chunk (dx, dz) chunks from the centre
+// it was requested around, lowest first, for a view looking along the unit
+// XZ vector (fx, fz), or (0, 0) for no view.
+func streamScore(dx, dz int, fx, fz float64) float64 {
+	dist := math.Hypot(float64(dx), float64(dz))
+	if dist <= streamNearChunks || (fx == 0 && fz == 0) {
+		return dist
+	}
+	cos := (float64(dx)*fx + float64(dz)*fz) / dist
+	return dist * (1 + streamViewWeight*(1-cos))
+}
+
+// streamJob is a chunk queued for generation.
+type streamJob struct {
+	coord  ChunkCoord
+	center [2]int // column it was requested around
+	score  float64
+}
+
+type streamHeap []streamJob
+
+func (h streamHeap) Len() int           { return len(h) }
+func (h streamHeap) Less(i, j int) bool { return h[i].score < h[j].score }
+func (h streamHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *streamHeap) Push(x any)        { *h = append(*h, x.(streamJob)) }
+
+func (h *streamHeap) Pop() any {
+	old := *h
+	n := len(old)
+	j := old[n-1]
+	*h = old[:n-1]
+	return j
+}
+
+// streamQueue is the generation workers' queue.
+type streamQueue struct {
+	mu     sync.Mutex
+	ready  *sync.Cond
+	jobs   streamHeap
+	fx, fz float64 // view direction the jobs are scored for
+	closed bool
+}
+
+func newStreamQueue() *streamQueue {
+	q := &streamQueue{}
+	q.ready = sync.NewCond(&q.mu)
+	return q
+}
+
+// push queues coord, requested around column center; false when the queue
+// is full or closed.
+func (q *streamQueue) push(coord ChunkCoord, center [2]int) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed || len(q.jobs) >= streamQueueSize {
+		return false
+	}
+	score := streamScore(coord.X-center[0], coord.Z-center[1], q.fx, q.fz)
+	heap.Push(&q.jobs, streamJob{coord: coord, center: center, score: score})
+	q.ready.Signal()
+	return true
+}
+
+// pop waits for the first queued chunk; ok is false once the queue closes.
+func (q *streamQueue) pop() (coord ChunkCoord, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.jobs) == 0 && !q.closed {
+		q.ready.Wait()
+	}
+	if q.closed {
+		return ChunkCoord{}, false
+	}
+	return heap.Pop(&q.jobs).(streamJob).coord, true
+}
+
+// setView scores the queue for a camera looking along forward, reordering
+// it when the horizontal view turned past streamRescoreCos. Looking
+// straight up or down keeps the last view.
+func (q *streamQueue) setView(forward mgl32.Vec3) {
+	fx, fz := float64(forward.X()), float64(forward.Z())
+	l := math.Hypot(fx, fz)
+	if l < 1e-3 {
+		return
+	}
+	fx, fz = fx/l, fz/l
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if fx*q.fx+fz*q.fz >= streamRescoreCos {
+		return
+	}
+	q.fx, q.fz = fx, fz
+	for i := range q.jobs {
+		j := &q.jobs[i]
+		j.score = streamScore(j.coord.X-j.center[0], j.coord.Z-j.center[1], fx, fz)
+	}
+	heap.Init(&q.jobs)
+}
+
+func (q *streamQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.jobs = nil
+	q.ready.Broadcast()
+	q.mu.Unlock()
+}
@@ -0,0 +1,47 @@
+package world
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestStreamQueueFollowsView(t *testing.T) {
+	q := newStreamQueue()
+	center := [2]int{0, 0}
+	for _, c := range []ChunkCoord{{X: -5}, {X: 8}, {X: 1}, {Z: 6}} {
+		if !q.push(c, center) {
+			t.Fatalf("push %v refused", c)
+		}
+	}
+	popAll := func() []int {
+		var got []int
+		for len(q.jobs) > 0 {
+			c, _ := q.pop()
+			got = append(got, c.X+100*c.Z)
+		}
+		return got
+	}
+
+	// No view yet: nearest first
+	q.setView(mgl32.Vec3{0, 1, 0}) // straight up: no horizontal view
+	if got, want := popAll(), []int{1, -5, 600, 8}; !slices.Equal(got, want) {
+		t.Fatalf("without a view popped %v, want %v", got, want)
+	}
+
+	for _, c := range []ChunkCoord{{X: -5}, {X: 8}, {X: 1}, {Z: 6}} {
+		q.push(c, center)
+	}
+	// Looking along +X: the far chunk ahead overtakes the ones aside and
+	// behind, the one at the player's feet still comes first
+	q.setView(mgl32.Vec3{1, -0.3, 0})
+	if got, want := popAll(), []int{1, 8, 600, -5}; !slices.Equal(got, want) {
+		t.Fatalf("looking east popped %v, want %v", got, want)
+	}
+
+	q.close()
+	if _, ok := q.pop(); ok {
+		t.Error("pop after close")
+	}
+}
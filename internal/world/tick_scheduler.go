@@ -1,6 +1,10 @@
 package world
 
-import "container/heap"
+import (
+	"container/heap"
+
+	"mini-mc/internal/mathutil"
+)
 
 // BlockPos represents a world-space block position.
 type BlockPos struct {
@@ -121,7 +125,7 @@ func (ts *TickScheduler) Cancel(pos BlockPos) {
 // the chunk at chunk coordinates (chunkX, chunkZ).
 func (ts *TickScheduler) CancelInRange(chunkX, chunkZ int) {
 	for pos := range ts.pending {
-		if floorDiv(pos.X, ChunkSizeX) == chunkX && floorDiv(pos.Z, ChunkSizeZ) == chunkZ {
+		if mathutil.FloorDiv(pos.X, ChunkSizeX) == chunkX && mathutil.FloorDiv(pos.Z, ChunkSizeZ) == chunkZ {
 			delete(ts.pending, pos)
 		}
 	}
@@ -130,12 +134,17 @@ func (ts *TickScheduler) CancelInRange(chunkX, chunkZ int) {
 // CancelOutsideRadius lazily cancels all pending ticks whose chunk coordinate is
 // further than radius chunks (Chebyshev square) from (cx, cz).
 func (ts *TickScheduler) CancelOutsideRadius(cx, cz, radius int) {
-	for pos := range ts.pending {
-		pcx := floorDiv(pos.X, ChunkSizeX)
-		pcz := floorDiv(pos.Z, ChunkSizeZ)
+	ts.cancelWhere(func(pcx, pcz int) bool {
 		dx := pcx - cx
 		dz := pcz - cz
-		if dx*dx+dz*dz > radius*radius {
+		return dx*dx+dz*dz > radius*radius
+	})
+}
+
+// cancelWhere drops pending ticks in the chunks far reports true for.
+func (ts *TickScheduler) cancelWhere(far func(chunkX, chunkZ int) bool) {
+	for pos := range ts.pending {
+		if far(mathutil.FloorDiv(pos.X, ChunkSizeX), mathutil.FloorDiv(pos.Z, ChunkSizeZ)) {
 			delete(ts.pending, pos)
 		}
 	}
@@ -0,0 +1,88 @@
+package world
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// World time is counted in game ticks, as in 1.8.9: a day is DayLength
+// ticks, 20 minutes at normal speed, starting at sunrise. Noon is tick 6000,
+// sunset 12000 and midnight 18000.
+const (
+	DayLength      = 24000
+	TicksPerSecond = 20
+
+	// MaxSkyDarkening is how many levels sky light loses at midnight.
+	MaxSkyDarkening = 11
+)
+
+// Time returns the ticks the world has run for, counting whole days.
+func (w *World) Time() float64 {
+	return w.dayTime
+}
+
+// SetTime sets the ticks the world has run for.
+func (w *World) SetTime(ticks float64) {
+	w.dayTime = max(ticks, 0)
+}
+
+// TimeOfDay returns the ticks into the current day, from 0 up to DayLength.
+func (w *World) TimeOfDay() float64 {
+	return math.Mod(w.dayTime, DayLength)
+}
+
+// Day returns the number of whole days the world has run for.
+func (w *World) Day() int {
+	return int(w.dayTime / DayLength)
+}
+
+// AdvanceTime moves the time of day on by dt seconds of play, speed times
+// faster than normal. Time stands still when the doDaylightCycle game rule
+// is off. Only call it from the game loop.
+func (w *World) AdvanceTime(dt, speed float64) {
+	if w.level != nil && !w.level.GameRuleBool("doDaylightCycle") {
+		return
+	}
+	w.dayTime += dt * speed * TicksPerSecond
+}
+
+// CelestialAngle returns how far round the sky the sun is, from 0 at noon
+// through 0.5 at midnight, on 1.8.9's curve, which lingers a little in the
+// day and the night and hurries through dawn and dusk.
+func (w *World) CelestialAngle() float64 {
+	f := w.TimeOfDay()/DayLength - 0.25
+	if f < 0 {
+		f++
+	}
+	eased := 1 - (math.Cos(f*math.Pi)+1)/2
+	return f + (eased-f)/3
+}
+
+// SunDirection returns the unit vector pointing at the sun. It rises in the
+// east (+X), is overhead at noon and below the ground at night.
+func (w *World) SunDirection() mgl32.Vec3 {
+	a := w.CelestialAngle() * 2 * math.Pi
+	return mgl32.Vec3{float32(-math.Sin(a)), float32(math.Cos(a)), 0}
+}
+
+// Daylight returns how light the sky is, from 0 in the dead of night to 1
+// through the day.
+func (w *World) Daylight() float32 {
+	a := w.CelestialAngle() * 2 * math.Pi
+	return float32(min(max(math.Cos(a)*2+0.5, 0), 1))
+}
+
+// SkyDarkening returns how many levels of sky light the time of day takes
+// away, from 0 by day to MaxSkyDarkening at night, unrounded so the light
+// fades smoothly.
+func (w *World) SkyDarkening() float32 {
+	return (1 - w.Daylight()) * MaxSkyDarkening
+}
+
+// Clock formats the time of day as a 24-hour clock, "06:00" at sunrise.
+func (w *World) Clock() string {
+	t := int(w.TimeOfDay())
+	return fmt.Sprintf("%02d:%02d", (t/1000+6)%24, t%1000*60/1000)
+}
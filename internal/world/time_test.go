@@ -0,0 +1,52 @@
+package world
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTimeOfDay(t *testing.T) {
+	w := &World{level: &Level{}}
+
+	w.AdvanceTime(60, 2) // a minute at double speed
+	if got := w.Time(); got != 2400 {
+		t.Fatalf("time after a minute at double speed = %v, want 2400", got)
+	}
+
+	for _, tc := range []struct {
+		ticks    float64
+		clock    string
+		sunUp    bool
+		daylight float32
+	}{
+		{0, "06:00", true, 0.93},
+		{6000, "12:00", true, 1},
+		{18000, "00:00", false, 0},
+		{DayLength + 13000, "19:00", false, 0.38},
+	} {
+		w.SetTime(tc.ticks)
+		if got := w.Clock(); got != tc.clock {
+			t.Errorf("clock at %v = %s, want %s", tc.ticks, got, tc.clock)
+		}
+		if sun := w.SunDirection(); (sun.Y() > 0) != tc.sunUp {
+			t.Errorf("sun at %v = %v, want up %v", tc.ticks, sun, tc.sunUp)
+		}
+		if got := w.Daylight(); math.Abs(float64(got-tc.daylight)) > 0.01 {
+			t.Errorf("daylight at %v = %v, want %v", tc.ticks, got, tc.daylight)
+		}
+	}
+	if w.Day() != 1 {
+		t.Errorf("day = %d, want 1", w.Day())
+	}
+	w.SetTime(6000)
+	if got := w.SunDirection(); got.Y() < 0.99 {
+		t.Errorf("sun at noon is not overhead: %v", got)
+	}
+
+	w.level.SetGameRule("doDaylightCycle", "false")
+	w.SetTime(100)
+	w.AdvanceTime(10, 1)
+	if w.Time() != 100 {
+		t.Errorf("time moved with doDaylightCycle off: %v", w.Time())
+	}
+}
@@ -1,8 +1,17 @@
 package world
 
 import (
-	"github.com/go-gl/mathgl/mgl32"
+	"errors"
+	"log/slog"
 	"math/rand"
+	"path/filepath"
+	"time"
+
+	"mini-mc/internal/mathutil"
+	"mini-mc/internal/profiling"
+	"mini-mc/internal/rng"
+
+	"github.com/go-gl/mathgl/mgl32"
 )
 
 // Ticker interface for updating entities (avoids circular dependency with entity package)
@@ -13,10 +22,6 @@ type Ticker interface {
 	Position() mgl32.Vec3
 }
 
-// ItemEntityConfigurator is called when adding ItemEntity to configure it with world services
-// This avoids import cycles by using a function reference set from the entity package
-var ItemEntityConfigurator func(item Ticker, world interface{})
-
 // World represents the game world, composed of chunks
 type World struct {
 	// Components
@@ -25,6 +30,17 @@ type World struct {
 	gen           TerrainGenerator
 	streamer      *ChunkStreamer
 	tickScheduler *TickScheduler
+	blockEntities *blockEntityStore
+	saves         *chunkSaveStore  // nil for worlds that are never saved
+	decorations   *decorationQueue // decoration blocks waiting for their chunk
+	entityLoads   entityLoadQueue  // loaded chunks whose entities wait to spawn
+
+	seed  int64
+	rand  *rand.Rand // gameplay randomness, kept apart from generation
+	dir   string     // save directory; empty for worlds that are never saved
+	level *Level     // metadata of saved worlds, nil otherwise
+
+	dayTime float64 // ticks run, see Time
 }
 
 // ChunkCoord is a unique identifier for a chunk based on its position
@@ -32,20 +48,139 @@ type ChunkCoord struct {
 	X, Y, Z int
 }
 
-// New creates a new world.
+// New creates a new world that is not saved.
 func New() *World {
+	return newWorld(rng.NewSeed(), nil)
+}
+
+// NewWithSeed creates an unsaved world generated from seed, for reproducible
+// scenes such as rendering tests.
+func NewWithSeed(seed int64) *World {
+	return newWorld(seed, nil)
+}
+
+// Open loads the world saved in dir, creating it with a random seed if dir
+// holds no world yet. Edited chunks are read back from dir; the rest are
+// generated from the seed in its level metadata.
+func Open(dir string) (*World, error) {
+	level, err := readOrCreateLevel(dir)
+	if err != nil {
+		return nil, err
+	}
+	saves, err := newChunkSaveStore(filepath.Join(dir, "chunks"))
+	if err != nil {
+		return nil, err
+	}
+	w := newWorld(level.Seed, saves)
+	w.dir, w.level = dir, level
+	w.dayTime = level.DayTime
+	return w, nil
+}
+
+func newWorld(seed int64, saves *chunkSaveStore) *World {
 	store := NewChunkStore()
 	entities := NewEntityManager()
-	gen := NewChunkProvider189(rand.Int63n(10000))
+	gen := NewChunkProvider189(seed)
+	decorations := newDecorationQueue()
+	gen.decorations = decorations
 	streamer := NewChunkStreamer(store, gen)
+	streamer.decorations = decorations
+	blockEntities := newBlockEntityStore()
+	streamer.onGenerated = blockEntities.restoreBlockEntities
 
-	return &World{
+	w := &World{
 		store:         store,
 		entities:      entities,
 		gen:           gen,
 		streamer:      streamer,
 		tickScheduler: NewTickScheduler(),
+		blockEntities: blockEntities,
+		saves:         saves,
+		decorations:   decorations,
+		seed:          seed,
+		rand:          rng.New(seed, rng.SaltEntities),
+	}
+	if saves != nil {
+		streamer.loadSaved = w.loadSavedChunk
+		store.onEvict = w.persistChunk
+	}
+	return w
+}
+
+// Seed returns the seed the world is generated from.
+func (w *World) Seed() int64 {
+	return w.seed
+}
+
+// Rand returns the world's gameplay random source, used for item drops and
+// entity motion. It is derived from the seed but separate from generation,
+// so gameplay never changes the terrain. Only use it from the game loop.
+func (w *World) Rand() *rand.Rand {
+	return w.rand
+}
+
+// Level returns the metadata of a saved world for the game to update; it is
+// written by Save. It is nil for worlds created with New.
+func (w *World) Level() *Level {
+	return w.level
+}
+
+// Dir returns the save directory, or "" for worlds created with New.
+func (w *World) Dir() string {
+	return w.dir
+}
+
+// loadSavedChunk returns the saved copy of coord, or nil to generate it.
+// Called from generation workers.
+func (w *World) loadSavedChunk(coord ChunkCoord) *Chunk {
+	c, err := w.saves.load(coord)
+	if err != nil {
+		slog.Warn("loading saved chunk failed; regenerating", "chunk", coord, "err", err)
+		return nil
+	}
+	if c != nil && len(c.entities) > 0 {
+		w.entityLoads.push(c)
+	}
+	if c != nil && len(c.blockEntities) > 0 {
+		w.loadBlockEntities(c)
+	}
+	return c
+}
+
+// persistChunk queues an evicted chunk for saving if it holds edits,
+// entities or block entities, which leave the world with it.
+func (w *World) persistChunk(c *Chunk) {
+	w.stashEntities(c, true)
+	w.stashBlockEntities(c, true)
+	w.saves.persist(c)
+}
+
+// Autosave writes the level metadata and queues every loaded chunk that
+// holds edits for the background writer, without waiting for the chunks to
+// reach the disk. It is a no-op for worlds created with New.
+func (w *World) Autosave() error {
+	if w.saves == nil {
+		return nil
+	}
+	w.level.LastPlayed = time.Now()
+	w.level.DayTime = w.dayTime
+	for _, cc := range w.store.GetAllChunks() {
+		w.stashEntities(cc.Chunk, false)
+		w.stashBlockEntities(cc.Chunk, false)
+		w.saves.persist(cc.Chunk)
 	}
+	return w.level.Write(w.dir)
+}
+
+// Save writes the level metadata and every loaded chunk that differs from
+// the generator output, and waits until they are on disk. It is a no-op for
+// worlds created with New.
+func (w *World) Save() error {
+	if w.saves == nil {
+		return nil
+	}
+	err := w.Autosave()
+	return errors.Join(err, w.saves.flush())
 }
 
 // NewEmpty creates an empty world.
@@ -53,23 +188,42 @@ func NewEmpty() *World {
 	return New()
 }
 
-// Close stops the background generation workers
+// Close stops the background generation workers, waiting for the chunks
+// they are loading, and closes the save files once any chunks still queued
+// are written.
 func (w *World) Close() {
 	w.streamer.Close()
+	if w.saves != nil {
+		if err := w.saves.close(); err != nil {
+			slog.Error("closing world saves failed", "err", err)
+		}
+	}
 }
 
 // AddEntity adds an entity to the world
 func (w *World) AddEntity(e Ticker) {
-	// If the configurator is set and this is an ItemEntity, configure it
-	if ItemEntityConfigurator != nil {
-		ItemEntityConfigurator(e, w)
-	}
 	w.entities.Add(e)
 }
 
-// UpdateEntities updates all entities and removes dead ones
-func (w *World) UpdateEntities(dt float64) {
-	w.entities.Update(dt)
+// UpdateEntities spawns the entities of chunks loaded since the last update,
+// then updates entities around the focus (x, z) and removes dead ones.
+// Entities beyond simDistance blocks tick at a reduced rate, and those much
+// further out freeze.
+func (w *World) UpdateEntities(dt float64, x, z, simDistance float32) {
+	w.spawnLoadedEntities()
+	w.entities.Update(dt, x, z, simDistance)
+}
+
+// UpdateEntitiesAround is UpdateEntities around several foci, such as the
+// players of a server; see EntityManager.UpdateAround.
+func (w *World) UpdateEntitiesAround(dt float64, foci [][2]float32, simDistance float32) {
+	w.spawnLoadedEntities()
+	w.entities.UpdateAround(dt, foci, simDistance)
+}
+
+// EntityUpdateStats returns how entities were treated by the last update.
+func (w *World) EntityUpdateStats() EntityUpdateStats {
+	return w.entities.Stats()
 }
 
 // GetEntities returns a safe copy of the current entities in the world
@@ -78,22 +232,19 @@ func (w *World) GetEntities() []Ticker {
 }
 
 // GetNearbyEntities returns entities within a box centered at (cx, cy, cz) with ranges (rx, ry, rz).
-// Returns []interface{} to avoid import cycles - callers type-assert to specific entity types.
-func (w *World) GetNearbyEntities(cx, cy, cz, rx, ry, rz float32) []interface{} {
-	minX := cx - rx
-	maxX := cx + rx
-	minY := cy - ry
-	maxY := cy + ry
-	minZ := cz - rz
-	maxZ := cz + rz
-
-	tickers := w.entities.GetEntitiesInAABB(minX, minY, minZ, maxX, maxY, maxZ)
-
-	result := make([]interface{}, len(tickers))
-	for i, t := range tickers {
-		result[i] = t
-	}
-	return result
+// Callers type-assert to specific entity types.
+func (w *World) GetNearbyEntities(cx, cy, cz, rx, ry, rz float32) []Ticker {
+	return w.GetEntitiesInAABB(cx-rx, cy-ry, cz-rz, cx+rx, cy+ry, cz+rz)
+}
+
+// GetEntitiesInAABB returns the entities whose position lies in the box.
+func (w *World) GetEntitiesInAABB(minX, minY, minZ, maxX, maxY, maxZ float32) []Ticker {
+	return w.entities.GetEntitiesInAABB(minX, minY, minZ, maxX, maxY, maxZ)
+}
+
+// EntitiesInChunk returns the entities standing in chunk column (cx, cz).
+func (w *World) EntitiesInChunk(cx, cz int) []Ticker {
+	return w.entities.EntitiesInChunk(cx, cz)
 }
 
 // GetChunk returns the chunk at the specified chunk coordinates
@@ -152,29 +303,118 @@ func (w *World) GetAllChunks() []ChunkWithCoord {
 	return w.store.GetAllChunks()
 }
 
+// SectionStorage returns how many sections the loaded chunks hold and the
+// heap bytes their blocks are stored in.
+func (w *World) SectionStorage() (sections, bytes int) {
+	return w.store.SectionStorage()
+}
+
 // StreamChunksAroundSync synchronously generates chunks around a world position (x,z) within radius
 func (w *World) StreamChunksAroundSync(x, z float32, radius int) {
 	w.streamer.StreamChunksAroundSync(x, z, radius)
 }
 
+// SetStreamView tells the chunk streamer where the camera looks, so chunks
+// in view are generated before those behind it
+func (w *World) SetStreamView(forward mgl32.Vec3) {
+	w.streamer.SetView(forward)
+}
+
 // StreamChunksAroundAsync enqueues async generation around a world position (x,z) within radius
 func (w *World) StreamChunksAroundAsync(x, z float32, radius int) {
 	w.streamer.StreamChunksAroundAsync(x, z, radius)
 }
 
+// StreamRing queues the chunks of the columns more than inner and up to
+// outer chunks around (x, z), nearest first, for when the load radius grows.
+func (w *World) StreamRing(x, z float32, inner, outer int) {
+	w.streamer.StreamRing(x, z, inner, outer)
+}
+
+// RingProgress counts the loaded chunks of the ring StreamRing queues.
+func (w *World) RingProgress(x, z float32, inner, outer int) RingProgress {
+	return w.streamer.RingProgress(x, z, inner, outer)
+}
+
+// ChunkLatencies returns how long the chunks streamed in the background
+// took from being asked for to being loaded.
+func (w *World) ChunkLatencies() *profiling.Latencies {
+	return &w.streamer.Latencies
+}
+
+// AreaLoaded reports whether all chunks of the columns within radius of
+// (x, z) are loaded.
+func (w *World) AreaLoaded(x, z float32, radius int) bool {
+	return w.streamer.AreaLoaded(x, z, radius)
+}
+
 // EvictFarChunks removes chunks outside the given radius (in chunks) from the center (world x,z).
 // Pending ticks for evicted positions are lazily cancelled to prevent stale heap growth.
 func (w *World) EvictFarChunks(x, z float32, radius int) int {
-	cx := floorDiv(int(x), ChunkSizeX)
-	cz := floorDiv(int(z), ChunkSizeZ)
+	cx := mathutil.FloorDiv(int(x), ChunkSizeX)
+	cz := mathutil.FloorDiv(int(z), ChunkSizeZ)
 	w.tickScheduler.CancelOutsideRadius(cx, cz, radius)
 	return w.streamer.EvictFarChunks(x, z, radius)
 }
 
-// Tick processes one game tick - runs scheduled block updates.
+// InstallChunk lights a chunk received from a server and adds it to the
+// world in place of any chunk already loaded there.
+func (w *World) InstallChunk(c *Chunk) {
+	coord := ChunkCoord{X: c.X, Y: c.Y, Z: c.Z}
+	if w.store.HasChunk(coord) {
+		w.store.evictWhere(func(cc ChunkCoord) bool { return cc == coord })
+	}
+	c.initLight()
+	w.store.AddChunk(coord, c)
+}
+
+// UnloadChunk removes the column of chunks at (chunkX, chunkZ), as a server
+// tells its players to once they move away from it.
+func (w *World) UnloadChunk(chunkX, chunkZ int) {
+	w.store.evictWhere(func(cc ChunkCoord) bool { return cc.X == chunkX && cc.Z == chunkZ })
+	w.tickScheduler.CancelInRange(chunkX, chunkZ)
+}
+
+// EvictChunksFarFromAll removes the chunks outside the given radius (in
+// chunks) of every position, for a server keeping what any of its players
+// is near. Pending ticks in the removed chunks are dropped.
+func (w *World) EvictChunksFarFromAll(positions []mgl32.Vec3, radius int) int {
+	removed := w.streamer.EvictChunksFarFromAll(positions, radius)
+	w.tickScheduler.cancelWhere(func(chunkX, chunkZ int) bool {
+		return !w.store.HasChunk(ChunkCoord{X: chunkX, Z: chunkZ})
+	})
+	return removed
+}
+
+// OnBlockChange sets fn to run after every block or metadata write made
+// through the world, on the goroutine making it; a server uses it to send
+// the changes to its players. Set it before the world is played in.
+func (w *World) OnBlockChange(fn func(x, y, z int)) {
+	w.store.onChange = fn
+}
+
+// OnRelight sets fn to take the chunks whose light changed after a block
+// change or a chunk loading beside them, so a renderer can remesh them by
+// urgency; without it they are marked dirty. It may run on any goroutine
+// and must not call into the world. Set it before the world is played in.
+func (w *World) OnRelight(fn func(coords ...ChunkCoord)) {
+	w.store.onRelight = fn
+}
+
+// Tick processes one game tick - runs scheduled block updates and block
+// entities, lets snow fall, and fills in decorations that reached loaded
+// chunks.
 func (w *World) Tick() {
+	w.applyLoadedDecorations()
+	w.tickBlockEntities()
+	w.tickSnowfall()
+
 	positions := w.tickScheduler.Process(1024)
 	for _, pos := range positions {
+		if w.Get(pos.X, pos.Y, pos.Z) == BlockTypeSnowLayer {
+			SnowTick(w, pos.X, pos.Y, pos.Z)
+			continue
+		}
 		FluidTick(w, pos.X, pos.Y, pos.Z)
 	}
 }
@@ -203,22 +443,3 @@ func (w *World) AppendChunksInRadiusXZ(cx, cz, radius int, dst []ChunkWithCoord)
 func (w *World) GetModCount() uint64 {
 	return w.store.GetModCount()
 }
-
-// Helper functions for coordinate conversion
-
-// floorDiv performs integer division that rounds down for negative numbers
-func floorDiv(a, b int) int {
-	if a < 0 {
-		return (a - b + 1) / b
-	}
-	return a / b
-}
-
-// mod returns the remainder of a/b, always positive
-func mod(a, b int) int {
-	result := a % b
-	if result < 0 {
-		result += b
-	}
-	return result
-}
@@ -2,16 +2,18 @@ package blockmodel
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
 type Loader struct {
-	assetsPath string
-	modelCache map[string]*Model
+	assetsPath   string
+	overridePath string // laid out like assetsPath and read first; "" for none
+	modelCache   map[string]*Model
 }
 
 func NewLoader(assetsPath string) *Loader {
@@ -21,6 +23,27 @@ func NewLoader(assetsPath string) *Loader {
 	}
 }
 
+// NewLoaderWithOverrides returns a loader that reads each model and
+// blockstate from overridePath if it has the file, and from assetsPath
+// otherwise. Models in either may inherit from models in the other.
+func NewLoaderWithOverrides(assetsPath, overridePath string) *Loader {
+	l := NewLoader(assetsPath)
+	l.overridePath = overridePath
+	return l
+}
+
+// readAsset reads the file at rel within the override path, falling back to
+// the assets path.
+func (l *Loader) readAsset(rel string) ([]byte, error) {
+	if l.overridePath != "" {
+		data, err := os.ReadFile(filepath.Join(l.overridePath, rel))
+		if err == nil || !errors.Is(err, fs.ErrNotExist) {
+			return data, err
+		}
+	}
+	return os.ReadFile(filepath.Join(l.assetsPath, rel))
+}
+
 func (l *Loader) LoadModel(name string) (*Model, error) {
 	if !strings.Contains(name, "/") {
 		name = "block/" + name
@@ -30,9 +53,7 @@ func (l *Loader) LoadModel(name string) (*Model, error) {
 		return model, nil
 	}
 
-	path := filepath.Join(l.assetsPath, "models", name+".json")
-
-	data, err := ioutil.ReadFile(path)
+	data, err := l.readAsset(filepath.Join("models", name+".json"))
 	if err != nil {
 		return nil, fmt.Errorf("could not read model file: %w", err)
 	}
@@ -124,9 +145,7 @@ func (l *Loader) ResolveTexture(textureName string, m *Model) string {
 }
 
 func (l *Loader) LoadBlockState(name string) (*BlockState, error) {
-	path := filepath.Join(l.assetsPath, "blockstates", name+".json")
-
-	data, err := os.ReadFile(path)
+	data, err := l.readAsset(filepath.Join("blockstates", name+".json"))
 	if err != nil {
 		return nil, fmt.Errorf("could not read blockstate file: %w", err)
 	}
@@ -0,0 +1,38 @@
+package blockmodel
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOverridesTakePrecedence(t *testing.T) {
+	assets, overrides := t.TempDir(), t.TempDir()
+	for _, dir := range []string{
+		filepath.Join(assets, "models", "block"), filepath.Join(assets, "blockstates"),
+		filepath.Join(overrides, "models", "block"), filepath.Join(overrides, "blockstates"),
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(filepath.Join(assets, "models", "block", "cube_all.json"), `{
+		"elements": [ { "from": [0,0,0], "to": [16,16,16], "faces": { "up": { "texture": "#all" } } } ]
+	}`)
+	writeFile(filepath.Join(assets, "models", "block", "stone.json"), `{"parent": "block/cube_all", "textures": {"all": "blocks/stone"}}`)
+	writeFile(filepath.Join(overrides, "models", "block", "stone.json"), `{"parent": "block/cube_all", "textures": {"all": "blocks/marble"}}`)
+	writeFile(filepath.Join(assets, "blockstates", "dirt.json"), `{"variants": {"normal": [{"model": "dirt"}]}}`)
+
+	loader := NewLoaderWithOverrides(assets, overrides)
+	m, err := loader.LoadModel("stone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Elements[0].Faces["up"].Texture; got != "blocks/marble" {
+		t.Errorf("overridden stone has texture %q, want the world's marble", got)
+	}
+	bs, err := loader.LoadBlockState("dirt")
+	if err != nil || bs.Variants["normal"][0].Model != "dirt" {
+		t.Errorf("blockstate missing from the overrides = %+v, %v; want the global one", bs, err)
+	}
+}